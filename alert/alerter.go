@@ -0,0 +1,322 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/core"
+	dp "github.com/thetatoken/theta/dispatcher"
+	"github.com/thetatoken/theta/netsync"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "alert"})
+
+const (
+	checkInterval    = 5 * time.Second
+	commandTimeout   = 30 * time.Second
+	webhookTimeout   = 10 * time.Second
+	maxResponseBytes = 4096
+
+	// maxEpochGap caps the number of the skipped epochs checked for the missed proposals between
+	// two finalized blocks, e.g. after the network stalled for a long time
+	maxEpochGap = 1000
+)
+
+// The types of the alerts
+const (
+	AlertMissedProposals = "missed_proposals"
+	AlertBlockLag        = "block_lag"
+	AlertNoPeers         = "no_peers"
+	AlertClockSkew       = "clock_skew"
+)
+
+// Alert is passed to the alert command in the THETA_ALERT_* environment variables, and is the JSON
+// payload POSTed to the alert webhook URLs
+type Alert struct {
+	Type      string            `json:"type"`
+	Resolved  bool              `json:"resolved"`
+	NodeID    string            `json:"node_id"`
+	Message   string            `json:"message"`
+	Timestamp common.JSONUint64 `json:"timestamp"` // unix time in seconds
+}
+
+// nodeStatus is the status of the node the alert conditions are evaluated against
+type nodeStatus struct {
+	missedProposals uint64        // number of the consecutive missed proposal slots
+	blockLag        uint64        // number of blocks behind the best peer
+	noPeersFor      time.Duration // how long the node has had no peers, zero if it has peers
+	clockSkew       time.Duration
+}
+
+//
+// Alerter runs the alert command and POSTs to the alert webhook URLs when the node misses K
+// consecutive proposal slots, falls N blocks behind its peers, loses all its peers, or detects
+// clock skew, so that the operators get paged before the validator is slashed or dropped. Each
+// alert is sent once when its condition starts, and once more, marked as resolved, when the
+// condition ends.
+//
+type Alerter struct {
+	chain      *blockchain.Chain
+	consensus  *consensus.ConsensusEngine
+	dispatcher *dp.Dispatcher
+	syncMgr    *netsync.SyncManager
+
+	command         string
+	urls            []string
+	maxMissed       uint64
+	maxBlockLag     uint64
+	maxNoPeers      time.Duration
+	maxClockSkew    time.Duration
+	client          *http.Client
+	firing          map[string]bool
+	missedProposals uint64
+	checkedHeight   uint64
+	noPeersSince    time.Time
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewAlerter creates an Alerter with the configured actions and thresholds
+func NewAlerter(chain *blockchain.Chain, consensus *consensus.ConsensusEngine, dispatcher *dp.Dispatcher, syncMgr *netsync.SyncManager) *Alerter {
+	return &Alerter{
+		chain:        chain,
+		consensus:    consensus,
+		dispatcher:   dispatcher,
+		syncMgr:      syncMgr,
+		command:      viper.GetString(common.CfgAlertCommand),
+		urls:         viper.GetStringSlice(common.CfgAlertWebhookURLs),
+		maxMissed:    uint64(viper.GetInt64(common.CfgAlertMissedProposals)),
+		maxBlockLag:  uint64(viper.GetInt64(common.CfgAlertMaxBlockLag)),
+		maxNoPeers:   time.Duration(viper.GetInt64(common.CfgAlertNoPeersSecs)) * time.Second,
+		maxClockSkew: time.Duration(viper.GetInt64(common.CfgAlertMaxClockSkewMs)) * time.Millisecond,
+		client:       &http.Client{Timeout: webhookTimeout},
+		firing:       make(map[string]bool),
+		wg:           &sync.WaitGroup{},
+	}
+}
+
+// Start starts the alerting
+func (a *Alerter) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	a.ctx = c
+	a.cancel = cancel
+
+	a.checkedHeight = a.consensus.GetLastFinalizedBlock().Height
+	a.wg.Add(1)
+	go a.mainLoop()
+}
+
+// Stop stops the alerting
+func (a *Alerter) Stop() {
+	a.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (a *Alerter) Wait() {
+	a.wg.Wait()
+}
+
+func (a *Alerter) mainLoop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, alert := range a.evaluate(a.collectStatus()) {
+				a.notify(alert)
+			}
+		}
+	}
+}
+
+func (a *Alerter) collectStatus() *nodeStatus {
+	status := &nodeStatus{}
+
+	lastFinalized := a.consensus.GetLastFinalizedBlock()
+	for height := a.checkedHeight + 1; height <= lastFinalized.Height; height++ {
+		if block := a.findFinalizedBlockByHeight(height); block != nil {
+			a.checkProposals(block)
+		}
+	}
+	a.checkedHeight = lastFinalized.Height
+	status.missedProposals = a.missedProposals
+
+	if a.syncMgr != nil {
+		status.blockLag = a.syncMgr.GetSyncProgress().BlocksRemaining
+	}
+
+	if len(a.dispatcher.Peers(false)) > 0 {
+		a.noPeersSince = time.Time{}
+	} else if a.noPeersSince.IsZero() {
+		a.noPeersSince = time.Now()
+	} else {
+		status.noPeersFor = time.Since(a.noPeersSince)
+	}
+
+	if skew := a.consensus.ClockSkew(); skew.Source != consensus.ClockSkewSourceNone {
+		status.clockSkew = skew.Skew
+		if status.clockSkew < 0 {
+			status.clockSkew = -status.clockSkew
+		}
+	}
+	return status
+}
+
+func (a *Alerter) findFinalizedBlockByHeight(height uint64) *core.ExtendedBlock {
+	for _, block := range a.chain.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return block
+		}
+	}
+	return nil
+}
+
+// checkProposals counts the slots of the node skipped between the finalized block and its parent
+// as missed, and resets the count if the node proposed the block
+func (a *Alerter) checkProposals(block *core.ExtendedBlock) {
+	parent, err := a.chain.FindBlock(block.Parent)
+	if err != nil {
+		return
+	}
+	epoch := parent.Epoch + 1
+	if block.Epoch > maxEpochGap && epoch < block.Epoch-maxEpochGap {
+		epoch = block.Epoch - maxEpochGap
+	}
+	for ; epoch < block.Epoch; epoch++ {
+		if a.isProposer(parent.Hash(), epoch) {
+			a.missedProposals++
+		}
+	}
+	if a.isProposer(parent.Hash(), block.Epoch) {
+		a.missedProposals = 0
+	}
+}
+
+// isProposer returns whether the node is the proposer of the epoch after the block
+func (a *Alerter) isProposer(previousBlock common.Hash, epoch uint64) bool {
+	vm := a.consensus.GetValidatorManager()
+	proposer := vm.GetNextProposer(previousBlock, epoch)
+	signer := vm.GetNextValidatorSet(previousBlock).SigningAddress(proposer.ID())
+	return signer.Hex() == a.consensus.ID()
+}
+
+// evaluate returns the alerts whose conditions started or ended since the last evaluation
+func (a *Alerter) evaluate(status *nodeStatus) []*Alert {
+	alerts := []*Alert{}
+	update := func(alertType string, active bool, message string) {
+		if active == a.firing[alertType] {
+			return
+		}
+		a.firing[alertType] = active
+		alerts = append(alerts, &Alert{
+			Type:      alertType,
+			Resolved:  !active,
+			NodeID:    a.nodeID(),
+			Message:   message,
+			Timestamp: common.JSONUint64(time.Now().Unix()),
+		})
+	}
+
+	update(AlertMissedProposals, a.maxMissed > 0 && status.missedProposals >= a.maxMissed,
+		fmt.Sprintf("Missed %v consecutive proposal slots", status.missedProposals))
+	update(AlertBlockLag, a.maxBlockLag > 0 && status.blockLag > a.maxBlockLag,
+		fmt.Sprintf("%v blocks behind the peers", status.blockLag))
+	update(AlertNoPeers, a.maxNoPeers > 0 && status.noPeersFor >= a.maxNoPeers,
+		fmt.Sprintf("No peers for %v", status.noPeersFor))
+	update(AlertClockSkew, a.maxClockSkew > 0 && status.clockSkew > a.maxClockSkew,
+		fmt.Sprintf("Clock skew of %v", status.clockSkew))
+	return alerts
+}
+
+func (a *Alerter) nodeID() string {
+	if a.consensus == nil {
+		return ""
+	}
+	return a.consensus.ID()
+}
+
+// notify runs the alert command and POSTs the alert to the webhook URLs. The failures are logged,
+// but not retried.
+func (a *Alerter) notify(alert *Alert) {
+	if alert.Resolved {
+		logger.Infof("Resolved alert %v: %v", alert.Type, alert.Message)
+	} else {
+		logger.Warnf("Alert %v: %v", alert.Type, alert.Message)
+	}
+
+	if a.command != "" {
+		if err := a.runCommand(alert); err != nil {
+			logger.Errorf("Failed to run the alert command: %v", err)
+		}
+	}
+	for _, url := range a.urls {
+		if err := a.post(url, alert); err != nil {
+			logger.Errorf("Failed to POST the alert to %v: %v", url, err)
+		}
+	}
+}
+
+func (a *Alerter) runCommand(alert *Alert) error {
+	ctx, cancel := context.WithTimeout(a.ctx, commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", a.command)
+	cmd.Env = append(os.Environ(),
+		"THETA_ALERT_TYPE="+alert.Type,
+		fmt.Sprintf("THETA_ALERT_RESOLVED=%v", alert.Resolved),
+		"THETA_ALERT_NODE_ID="+alert.NodeID,
+		"THETA_ALERT_MESSAGE="+alert.Message,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v, output: %s", err, output)
+	}
+	return nil
+}
+
+func (a *Alerter) post(url string, alert *Alert) error {
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(a.ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Unexpected status %v", resp.Status)
+	}
+	return nil
+}
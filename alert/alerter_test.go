@@ -0,0 +1,43 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &Alerter{
+		maxMissed:    3,
+		maxBlockLag:  20,
+		maxNoPeers:   time.Minute,
+		maxClockSkew: 2 * time.Second,
+		firing:       make(map[string]bool),
+	}
+
+	assert.Equal(0, len(a.evaluate(&nodeStatus{missedProposals: 2, blockLag: 20, noPeersFor: 30 * time.Second})))
+
+	alerts := a.evaluate(&nodeStatus{missedProposals: 3, blockLag: 21})
+	assert.Equal(2, len(alerts))
+	assert.Equal(AlertMissedProposals, alerts[0].Type)
+	assert.False(alerts[0].Resolved)
+	assert.Equal("Missed 3 consecutive proposal slots", alerts[0].Message)
+	assert.Equal(AlertBlockLag, alerts[1].Type)
+
+	// Sent once while the conditions last
+	assert.Equal(0, len(a.evaluate(&nodeStatus{missedProposals: 4, blockLag: 30})))
+
+	alerts = a.evaluate(&nodeStatus{missedProposals: 0, blockLag: 30, noPeersFor: time.Minute, clockSkew: 3 * time.Second})
+	assert.Equal(3, len(alerts))
+	assert.Equal(AlertMissedProposals, alerts[0].Type)
+	assert.True(alerts[0].Resolved)
+	assert.Equal(AlertNoPeers, alerts[1].Type)
+	assert.Equal(AlertClockSkew, alerts[2].Type)
+
+	// A zero threshold disables the alert
+	a = &Alerter{firing: make(map[string]bool)}
+	assert.Equal(0, len(a.evaluate(&nodeStatus{missedProposals: 100, blockLag: 100, noPeersFor: time.Hour, clockSkew: time.Hour})))
+}
@@ -24,7 +24,9 @@ type Chain struct {
 	ChainID string
 	root    common.Hash
 
-	mu *sync.RWMutex
+	mu     *sync.RWMutex
+	events *chainEventBus
+	stats  *ChainStats
 }
 
 // NewChain creates a new Chain instance.
@@ -33,6 +35,8 @@ func NewChain(chainID string, store store.Store, root *core.Block) *Chain {
 		ChainID: chainID,
 		store:   store,
 		mu:      &sync.RWMutex{},
+		events:  newChainEventBus(),
+		stats:   NewChainStats(),
 	}
 	rootBlock, err := chain.FindBlock(root.Hash())
 	if err != nil {
@@ -47,6 +51,20 @@ func NewChain(chainID string, store store.Store, root *core.Block) *Chain {
 	return chain
 }
 
+// SubscribeEvents subscribes to the block insertion, finalization and abandoned branch events.
+// The subscriber should drain the channel promptly, the subscription is dropped if more than
+// bufferSize events are pending.
+func (ch *Chain) SubscribeEvents(bufferSize int) *ChainEventSubscription {
+	return ch.events.subscribe(bufferSize)
+}
+
+func (ch *Chain) publishEvent(eventType ChainEventType, blocks []*core.ExtendedBlock) {
+	if ch.events == nil || len(blocks) == 0 {
+		return
+	}
+	ch.events.publish(newChainEvent(eventType, blocks))
+}
+
 // Root returns the root block
 func (ch *Chain) Root() *core.ExtendedBlock {
 	ret, _ := ch.FindBlock(ch.root)
@@ -111,6 +129,8 @@ func (ch *Chain) addBlock(block *core.Block, isSnapshotRoot bool) (*core.Extende
 	ch.AddBlockByHeightIndex(extendedBlock.Height, extendedBlock.Hash())
 	ch.AddTxsToIndex(extendedBlock, false)
 
+	ch.publishEvent(EventBlockInserted, []*core.ExtendedBlock{extendedBlock})
+
 	return extendedBlock, nil
 }
 
@@ -236,6 +256,31 @@ func (ch *Chain) MarkBlockInvalid(hash common.Hash) *core.ExtendedBlock {
 	if err != nil {
 		logger.Panic(err)
 	}
+
+	ch.RemoveTxsFromIndex(block)
+	ch.publishEvent(EventBranchAbandoned, []*core.ExtendedBlock{block})
+
+	return block
+}
+
+// DisposeBlock marks the block as disposed, e.g. when rewinding to a hardcoded checkpoint.
+func (ch *Chain) DisposeBlock(hash common.Hash) *core.ExtendedBlock {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	block, err := ch.findBlock(hash)
+	if err != nil {
+		logger.Panic(err)
+	}
+	block.Status = core.BlockStatusDisposed
+	err = ch.saveBlock(block)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	ch.RemoveTxsFromIndex(block)
+	ch.publishEvent(EventBranchAbandoned, []*core.ExtendedBlock{block})
+
 	return block
 }
 
@@ -274,6 +319,19 @@ func (ch *Chain) FinalizePreviousBlocks(hash common.Hash) error {
 	ch.mu.Lock()
 	defer ch.mu.Unlock()
 
+	finalized := []*core.ExtendedBlock{}
+	abandoned := []*core.ExtendedBlock{}
+	defer func() {
+		// Blocks are collected from the tip down, the events list them by ascending height.
+		reverseBlocks(finalized)
+		reverseBlocks(abandoned)
+		for _, block := range finalized {
+			ch.addBlockToStats(block)
+		}
+		ch.publishEvent(EventBlockFinalized, finalized)
+		ch.publishEvent(EventBranchAbandoned, abandoned)
+	}()
+
 	status := core.BlockStatusDirectlyFinalized
 	for !hash.IsEmpty() {
 		block, err := ch.findBlock(hash)
@@ -293,6 +351,18 @@ func (ch *Chain) FinalizePreviousBlocks(hash common.Hash) error {
 		// Force update TX index on block finalization so that the index doesn't point to
 		// duplicate TX in fork.
 		ch.AddTxsToIndex(block, true)
+		ch.AddBlockLogsToIndex(block)
+		finalized = append(finalized, block)
+
+		// The other blocks at the same height can no longer be finalized. The invalid ones
+		// have been reported when they were marked.
+		for _, sibling := range ch.findBlocksByHeight(block.Height) {
+			if sibling.Hash() == block.Hash() || sibling.Status.IsInvalid() {
+				continue
+			}
+			ch.RemoveTxsFromIndex(sibling)
+			abandoned = append(abandoned, sibling)
+		}
 
 		hash = block.Parent
 	}
@@ -304,6 +374,12 @@ func (ch *Chain) IsOrphan(block *core.Block) bool {
 	return err != nil
 }
 
+func reverseBlocks(blocks []*core.ExtendedBlock) {
+	for i, j := 0, len(blocks)-1; i < j; i, j = i+1, j-1 {
+		blocks[i], blocks[j] = blocks[j], blocks[i]
+	}
+}
+
 // saveBlock updates a previously stored block.
 func (ch *Chain) saveBlock(block *core.ExtendedBlock) error {
 	hash := block.Hash()
@@ -5,7 +5,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
 )
 
 func TestBlockchain(t *testing.T) {
@@ -167,3 +169,87 @@ func TestBlockIndex(t *testing.T) {
 	assert.Equal(core.GetTestBlock("a2").Hash(), blocks[0].Hash())
 	assert.Equal(core.GetTestBlock("b2").Hash(), blocks[1].Hash())
 }
+
+func TestChainEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	core.ResetTestBlocks()
+
+	ch := CreateTestChainByBlocks([]string{
+		"a1", "a0",
+		"a2", "a1",
+		"b2", "a1",
+		"b3", "b2",
+		"c1", "a0",
+	})
+	sub := ch.SubscribeEvents(10)
+	defer sub.Unsubscribe()
+
+	tx := common.Bytes("tx")
+	a3 := core.CreateTestBlock("a3", "a2")
+	b4 := core.CreateTestBlock("b4", "b3")
+	b4.Txs = []common.Bytes{tx}
+	b4.UpdateHash()
+	_, err := ch.AddBlock(a3)
+	require.Nil(err)
+	_, err = ch.AddBlock(b4)
+	require.Nil(err)
+	_, _, found := ch.FindTxByHash(crypto.Keccak256Hash(tx))
+	assert.True(found)
+
+	event := <-sub.Events()
+	assert.Equal(EventBlockInserted, event.Type)
+	assert.Equal(a3.Hash(), event.Blocks[0].Hash())
+	assert.Equal(uint64(3), event.StartHeight)
+	event = <-sub.Events()
+	assert.Equal(EventBlockInserted, event.Type)
+	assert.Equal(b4.Hash(), event.Blocks[0].Hash())
+
+	require.Nil(ch.FinalizePreviousBlocks(a3.Hash()))
+
+	event = <-sub.Events()
+	assert.Equal(EventBlockFinalized, event.Type)
+	assert.Equal(uint64(1), event.StartHeight)
+	assert.Equal(uint64(3), event.EndHeight)
+	assert.Equal(3, len(event.Blocks))
+	for i, name := range []string{"a1", "a2", "a3"} {
+		assert.Equal(core.GetTestBlock(name).Hash(), event.Blocks[i].Hash())
+	}
+
+	event = <-sub.Events()
+	assert.Equal(EventBranchAbandoned, event.Type)
+	assert.Equal(uint64(1), event.StartHeight)
+	assert.Equal(uint64(3), event.EndHeight)
+	assert.Equal(3, len(event.Blocks))
+	for i, name := range []string{"c1", "b2", "b3"} {
+		assert.Equal(core.GetTestBlock(name).Hash(), event.Blocks[i].Hash())
+	}
+
+	ch.MarkBlockInvalid(b4.Hash())
+	event = <-sub.Events()
+	assert.Equal(EventBranchAbandoned, event.Type)
+	assert.Equal(uint64(4), event.StartHeight)
+	assert.Equal(b4.Hash(), event.Blocks[0].Hash())
+	_, _, found = ch.FindTxByHash(crypto.Keccak256Hash(tx))
+	assert.False(found)
+}
+
+func TestChainEventsLaggedSubscriber(t *testing.T) {
+	assert := assert.New(t)
+	core.ResetTestBlocks()
+
+	ch := CreateTestChainByBlocks([]string{
+		"a1", "a0",
+	})
+	sub := ch.SubscribeEvents(1)
+
+	ch.AddBlock(core.CreateTestBlock("a2", "a1"))
+	ch.AddBlock(core.CreateTestBlock("a3", "a2"))
+
+	_, ok := <-sub.Events()
+	assert.True(ok)
+	_, ok = <-sub.Events()
+	assert.False(ok)
+	assert.True(sub.Lagged())
+	sub.Unsubscribe()
+}
@@ -0,0 +1,138 @@
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/thetatoken/theta/core"
+)
+
+// ChainEventType is the type of a chain event
+type ChainEventType byte
+
+const (
+	// EventBlockInserted is emitted when a block is added to the chain
+	EventBlockInserted ChainEventType = iota
+	// EventBlockFinalized is emitted when a batch of blocks gets finalized
+	EventBlockFinalized
+	// EventBranchAbandoned is emitted when blocks are known to never be finalized, i.e. they
+	// are invalid, disposed, or on a fork of a finalized block
+	EventBranchAbandoned
+)
+
+func (t ChainEventType) String() string {
+	switch t {
+	case EventBlockInserted:
+		return "BlockInserted"
+	case EventBlockFinalized:
+		return "BlockFinalized"
+	case EventBranchAbandoned:
+		return "BranchAbandoned"
+	default:
+		return "Unknown"
+	}
+}
+
+// ChainEvent describes a change of the chain. Blocks are sorted by height in ascending
+// order, and StartHeight and EndHeight are the lowest and the highest affected heights.
+type ChainEvent struct {
+	Type        ChainEventType
+	Blocks      []*core.ExtendedBlock
+	StartHeight uint64
+	EndHeight   uint64
+}
+
+func newChainEvent(eventType ChainEventType, blocks []*core.ExtendedBlock) ChainEvent {
+	event := ChainEvent{
+		Type:   eventType,
+		Blocks: blocks,
+	}
+	for i, block := range blocks {
+		if i == 0 || block.Height < event.StartHeight {
+			event.StartHeight = block.Height
+		}
+		if i == 0 || block.Height > event.EndHeight {
+			event.EndHeight = block.Height
+		}
+	}
+	return event
+}
+
+// ChainEventSubscription receives the chain events. The channel is closed if the subscriber
+// falls behind and the buffer overflows, in which case Lagged() returns true and the
+// subscriber needs to resync from the chain before subscribing again.
+type ChainEventSubscription struct {
+	bus    *chainEventBus
+	ch     chan ChainEvent
+	lagged bool
+	closed bool
+}
+
+// Events returns the channel of the chain events
+func (s *ChainEventSubscription) Events() <-chan ChainEvent {
+	return s.ch
+}
+
+// Lagged returns whether events were dropped because the subscriber fell behind
+func (s *ChainEventSubscription) Lagged() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.lagged
+}
+
+// Unsubscribe stops the delivery of events and closes the channel
+func (s *ChainEventSubscription) Unsubscribe() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s)
+}
+
+// chainEventBus fans out the chain events to the subscribers. Publishing never blocks, so
+// that a slow subscriber cannot stall the chain.
+type chainEventBus struct {
+	mu          *sync.Mutex
+	subscribers map[*ChainEventSubscription]struct{}
+}
+
+func newChainEventBus() *chainEventBus {
+	return &chainEventBus{
+		mu:          &sync.Mutex{},
+		subscribers: make(map[*ChainEventSubscription]struct{}),
+	}
+}
+
+func (bus *chainEventBus) subscribe(bufferSize int) *ChainEventSubscription {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	sub := &ChainEventSubscription{
+		bus: bus,
+		ch:  make(chan ChainEvent, bufferSize),
+	}
+	bus.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (bus *chainEventBus) publish(event ChainEvent) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	for sub := range bus.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			logger.Warnf("Chain event subscriber fell behind, dropping subscription")
+			sub.lagged = true
+			bus.remove(sub)
+		}
+	}
+}
+
+// remove should be called with the lock held
+func (bus *chainEventBus) remove(sub *ChainEventSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(bus.subscribers, sub)
+	close(sub.ch)
+}
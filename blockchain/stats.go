@@ -0,0 +1,158 @@
+package blockchain
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// MaxStatsWindow is the number of the latest finalized blocks the chain statistics are kept for.
+const MaxStatsWindow = 10000
+
+// blockSample records the running totals of the finalized blocks up to a block, so that the
+// totals of any window of blocks are the difference of two samples.
+type blockSample struct {
+	height    uint64
+	timestamp *big.Int
+	numTxs    uint64   // Running total of the transactions
+	gasUsed   uint64   // Running total of the gas used by the smart contract transactions
+	fees      *big.Int // Running total of the fees in TFuelWei
+}
+
+// WindowStats is the statistics of a window of the latest finalized blocks.
+type WindowStats struct {
+	NumBlocks     uint64
+	StartHeight   uint64
+	EndHeight     uint64
+	BlockInterval float64 // Average block interval in seconds
+	TxsPerBlock   float64
+	TPS           float64
+	GasPerBlock   float64
+	TotalFees     *big.Int // In TFuelWei
+}
+
+// ChainStats maintains the running totals of the latest finalized blocks. The statistics start
+// empty when the node starts, and fill as the blocks are finalized.
+type ChainStats struct {
+	mu      *sync.Mutex
+	samples []blockSample // Ring buffer of the samples of the latest MaxStatsWindow+1 blocks
+	next    int
+	size    int
+}
+
+// NewChainStats creates an empty ChainStats.
+func NewChainStats() *ChainStats {
+	return &ChainStats{
+		mu:      &sync.Mutex{},
+		samples: make([]blockSample, MaxStatsWindow+1),
+	}
+}
+
+// addBlock adds a finalized block to the statistics, along with its gas used and fees. The
+// statistics restart if the block does not follow the last one.
+func (cs *ChainStats) addBlock(block *core.ExtendedBlock, numTxs uint64, gasUsed uint64, fees *big.Int) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	sample := blockSample{
+		height:    block.Height,
+		timestamp: new(big.Int).Set(block.Timestamp),
+		numTxs:    numTxs,
+		gasUsed:   gasUsed,
+		fees:      new(big.Int).Set(fees),
+	}
+	if cs.size > 0 {
+		last := cs.sample(0)
+		if block.Height <= last.height {
+			return // already added
+		}
+		if block.Height == last.height+1 {
+			sample.numTxs += last.numTxs
+			sample.gasUsed += last.gasUsed
+			sample.fees.Add(sample.fees, last.fees)
+		} else {
+			cs.size = 0
+		}
+	}
+
+	cs.samples[cs.next] = sample
+	cs.next = (cs.next + 1) % len(cs.samples)
+	if cs.size < len(cs.samples) {
+		cs.size++
+	}
+}
+
+// sample returns the i-th latest sample
+func (cs *ChainStats) sample(i int) blockSample {
+	return cs.samples[(cs.next-1-i+2*len(cs.samples))%len(cs.samples)]
+}
+
+// Window returns the statistics of the latest window blocks, or fewer if the statistics have not
+// been filled yet. It returns nil if fewer than two consecutive blocks have been finalized.
+func (cs *ChainStats) Window(window uint64) *WindowStats {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.size < 2 || window == 0 {
+		return nil
+	}
+	if window > uint64(cs.size-1) {
+		window = uint64(cs.size - 1)
+	}
+	end := cs.sample(0)
+	start := cs.sample(int(window))
+	stats := &WindowStats{
+		NumBlocks:   window,
+		StartHeight: start.height + 1,
+		EndHeight:   end.height,
+		TotalFees:   new(big.Int).Sub(end.fees, start.fees),
+	}
+
+	numBlocks := float64(window)
+	duration := new(big.Int).Sub(end.timestamp, start.timestamp).Int64()
+	numTxs := end.numTxs - start.numTxs
+	stats.BlockInterval = float64(duration) / numBlocks
+	stats.TxsPerBlock = float64(numTxs) / numBlocks
+	if duration > 0 {
+		stats.TPS = float64(numTxs) / float64(duration)
+	}
+	stats.GasPerBlock = float64(end.gasUsed-start.gasUsed) / numBlocks
+	return stats
+}
+
+// addBlockToStats adds the finalized block to the chain statistics. The gas used by the smart
+// contract transactions is read from their receipts, which are saved when the block is committed.
+func (ch *Chain) addBlockToStats(block *core.ExtendedBlock) {
+	if ch.stats == nil || block.Timestamp == nil {
+		return
+	}
+	gasUsed := uint64(0)
+	fees := big.NewInt(0)
+	for _, rawTx := range block.Txs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		fees.Add(fees, types.FixedTxFee(tx).TFuelWei)
+		if sctx, ok := tx.(*types.SmartContractTx); ok {
+			receipt, found := ch.FindTxReceiptByHash(crypto.Keccak256Hash(rawTx))
+			if !found {
+				continue
+			}
+			gasUsed += receipt.GasUsed
+			if sctx.GasPrice != nil {
+				fee := new(big.Int).SetUint64(receipt.GasUsed)
+				fees.Add(fees, fee.Mul(fee, sctx.GasPrice))
+			}
+		}
+	}
+	ch.stats.addBlock(block, uint64(len(block.Txs)), gasUsed, fees)
+}
+
+// Stats returns the statistics of the latest finalized blocks.
+func (ch *Chain) Stats() *ChainStats {
+	return ch.stats
+}
@@ -0,0 +1,73 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/core"
+)
+
+func TestChainStats(t *testing.T) {
+	assert := assert.New(t)
+
+	newBlock := func(height uint64, timestamp int64) *core.ExtendedBlock {
+		block := core.NewBlock()
+		block.Height = height
+		block.Timestamp = big.NewInt(timestamp)
+		return &core.ExtendedBlock{Block: block}
+	}
+
+	stats := NewChainStats()
+	stats.addBlock(newBlock(10, 1000), 1, 0, big.NewInt(0))
+	assert.Nil(stats.Window(100))
+
+	// Block 11 to 14, 6 seconds apart, each with 3 txs, 1000 gas and 10 wei of fees
+	for height := uint64(11); height <= 14; height++ {
+		stats.addBlock(newBlock(height, 1000+6*int64(height-10)), 3, 1000, big.NewInt(10))
+	}
+	stats.addBlock(newBlock(14, 2000), 100, 100, big.NewInt(100)) // already added
+
+	window := stats.Window(100)
+	assert.Equal(uint64(4), window.NumBlocks)
+	assert.Equal(uint64(11), window.StartHeight)
+	assert.Equal(uint64(14), window.EndHeight)
+	assert.Equal(6.0, window.BlockInterval)
+	assert.Equal(3.0, window.TxsPerBlock)
+	assert.Equal(0.5, window.TPS)
+	assert.Equal(1000.0, window.GasPerBlock)
+	assert.Equal(int64(40), window.TotalFees.Int64())
+
+	window = stats.Window(2)
+	assert.Equal(uint64(2), window.NumBlocks)
+	assert.Equal(uint64(13), window.StartHeight)
+	assert.Equal(int64(20), window.TotalFees.Int64())
+
+	// The statistics restart after a gap
+	stats.addBlock(newBlock(20, 2000), 1, 0, big.NewInt(0))
+	assert.Nil(stats.Window(100))
+	stats.addBlock(newBlock(21, 2010), 5, 0, big.NewInt(0))
+	window = stats.Window(100)
+	assert.Equal(uint64(1), window.NumBlocks)
+	assert.Equal(10.0, window.BlockInterval)
+	assert.Equal(5.0, window.TxsPerBlock)
+}
+
+func TestChainStatsWrapAround(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := NewChainStats()
+	for height := uint64(1); height <= MaxStatsWindow+10; height++ {
+		block := core.NewBlock()
+		block.Height = height
+		block.Timestamp = big.NewInt(int64(height))
+		stats.addBlock(&core.ExtendedBlock{Block: block}, 2, 0, big.NewInt(0))
+	}
+
+	window := stats.Window(MaxStatsWindow + 100)
+	assert.Equal(uint64(MaxStatsWindow), window.NumBlocks)
+	assert.Equal(uint64(11), window.StartHeight)
+	assert.Equal(uint64(MaxStatsWindow+10), window.EndHeight)
+	assert.Equal(1.0, window.BlockInterval)
+	assert.Equal(2.0, window.TxsPerBlock)
+}
@@ -1,13 +1,16 @@
 package blockchain
 
 import (
+	"encoding/binary"
 	"fmt"
 	"math/big"
+	"sort"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
 	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rlp"
 	"github.com/thetatoken/theta/store"
 )
 
@@ -51,6 +54,27 @@ func (ch *Chain) AddTxsToIndex(block *core.ExtendedBlock, force bool) {
 	}
 }
 
+// RemoveTxsFromIndex removes the index entries pointing to the given block, which has been
+// abandoned. The entries pointing to other blocks containing the same transactions are kept.
+func (ch *Chain) RemoveTxsFromIndex(block *core.ExtendedBlock) {
+	blockHash := block.Hash()
+	for _, tx := range block.Txs {
+		keys := []common.Bytes{txIndexKey(crypto.Keccak256Hash(tx))}
+		if ethTxHash, err := CalcEthTxHash(block, tx); err == nil {
+			keys = append(keys, txIndexKey(ethTxHash))
+		}
+		for _, key := range keys {
+			txIndexEntry := &TxIndexEntry{}
+			if err := ch.store.Get(key, txIndexEntry); err != nil || txIndexEntry.BlockHash != blockHash {
+				continue
+			}
+			if err := ch.store.Delete(key); err != nil {
+				logger.Panic(err)
+			}
+		}
+	}
+}
+
 // Index the ETH smart contract transactions, using the ETH tx hash as the key
 func (ch *Chain) insertEthTxHash(block *core.ExtendedBlock, rawTxBytes []byte, txIndexEntry *TxIndexEntry) error {
 	ethTxHash, err := CalcEthTxHash(block, rawTxBytes)
@@ -87,6 +111,89 @@ func (ch *Chain) FindTxByHash(hash common.Hash) (tx common.Bytes, block *core.Ex
 	return block.Txs[txIndexEntry.Index], block, true
 }
 
+// TxLookup is the location and the status of a transaction found by LookupTx.
+type TxLookup struct {
+	TxIndexEntry
+	RawTx       common.Bytes
+	BlockStatus core.BlockStatus
+}
+
+// LookupTx looks up transaction by hash like FindTxByHash, but without decoding the containing
+// block: the transaction and the block status are extracted from the encoded block, so that the
+// cost does not depend on the size of the block.
+func (ch *Chain) LookupTx(hash common.Hash) (*TxLookup, bool) {
+	lookup := &TxLookup{}
+	err := ch.store.Get(txIndexKey(hash), &lookup.TxIndexEntry)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+
+	var raw rlp.RawValue
+	err = ch.store.Get(lookup.BlockHash[:], &raw)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	lookup.RawTx, lookup.BlockStatus, err = extractTx(raw, lookup.Index)
+	if err != nil {
+		logger.Errorf("Failed to extract tx %v from block %v: %v", hash.Hex(), lookup.BlockHash.Hex(), err)
+		return nil, false
+	}
+	return lookup, true
+}
+
+// extractTx returns the transaction at the index and the status of the RLP encoded extended
+// block, i.e. [[header, [txs...]], children, status, hasValidatorUpdate]
+func extractTx(raw []byte, index uint64) (common.Bytes, core.BlockStatus, error) {
+	fields, _, err := rlp.SplitList(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	block, rest, err := rlp.SplitList(fields)
+	if err != nil {
+		return nil, 0, err
+	}
+	_, _, rest, err = rlp.Split(rest) // children
+	if err != nil {
+		return nil, 0, err
+	}
+	status, _, err := rlp.SplitString(rest)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(status) > 1 {
+		return nil, 0, fmt.Errorf("invalid block status")
+	}
+	blockStatus := core.BlockStatus(0)
+	if len(status) == 1 {
+		blockStatus = core.BlockStatus(status[0])
+	}
+
+	_, _, txs, err := rlp.Split(block) // header
+	if err != nil {
+		return nil, 0, err
+	}
+	txs, _, err = rlp.SplitList(txs)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := uint64(0); ; i++ {
+		var tx []byte
+		tx, txs, err = rlp.SplitString(txs)
+		if err != nil {
+			return nil, 0, fmt.Errorf("tx index %v out of range", index)
+		}
+		if i == index {
+			return tx, blockStatus, nil
+		}
+	}
+}
+
 // ---------------- Tx Receipts ---------------
 
 // txReceiptKey constructs the DB key for the given transaction hash.
@@ -102,11 +209,12 @@ type TxReceiptEntry struct {
 	ContractAddress common.Address
 	GasUsed         uint64
 	EvmErr          string
+	Bloom           types.Bloom `rlp:"-"` // Derived from the logs, not persisted.
 }
 
-// AddTxReceipt adds transaction receipt.
-func (ch *Chain) AddTxReceipt(tx types.Tx, logs []*types.Log, evmRet common.Bytes,
-	contractAddr common.Address, gasUsed uint64, evmErr error) {
+// NewTxReceiptEntry creates the receipt of the given smart contract transaction.
+func NewTxReceiptEntry(tx types.Tx, logs []*types.Log, evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error) *TxReceiptEntry {
 	raw, err := types.TxToBytes(tx)
 	if err != nil {
 		// Should never happen
@@ -117,17 +225,33 @@ func (ch *Chain) AddTxReceipt(tx types.Tx, logs []*types.Log, evmRet common.Byte
 	if evmErr != nil {
 		errStr = evmErr.Error()
 	}
-	txReceiptEntry := TxReceiptEntry{
+	return &TxReceiptEntry{
 		TxHash:          txHash,
 		Logs:            logs,
 		EvmRet:          evmRet,
 		ContractAddress: contractAddr,
 		GasUsed:         gasUsed,
 		EvmErr:          errStr,
+		Bloom:           types.CreateBloom(logs),
 	}
-	key := txReceiptKey(txHash)
+}
 
-	err = ch.store.Put(key, txReceiptEntry)
+// Succeeded returns whether the EVM execution of the transaction succeeded.
+func (r *TxReceiptEntry) Succeeded() bool {
+	return r.EvmErr == ""
+}
+
+// AddTxReceipt adds transaction receipt.
+func (ch *Chain) AddTxReceipt(tx types.Tx, logs []*types.Log, evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error) {
+	ch.SaveTxReceipt(NewTxReceiptEntry(tx, logs, evmRet, contractAddr, gasUsed, evmErr))
+}
+
+// SaveTxReceipt persists the transaction receipt.
+func (ch *Chain) SaveTxReceipt(txReceiptEntry *TxReceiptEntry) {
+	key := txReceiptKey(txReceiptEntry.TxHash)
+
+	err := ch.store.Put(key, *txReceiptEntry)
 	if err != nil {
 		logger.Panic(err)
 	}
@@ -147,9 +271,382 @@ func (ch *Chain) FindTxReceiptByHash(hash common.Hash) (*TxReceiptEntry, bool) {
 		}
 		return nil, false
 	}
+	txReceiptEntry.Bloom = types.CreateBloom(txReceiptEntry.Logs)
 	return txReceiptEntry, true
 }
 
+// ---------------- Internal Txs ---------------
+
+// internalTxsKey constructs the DB key for the internal transactions of the given transaction hash.
+func internalTxsKey(hash common.Hash) common.Bytes {
+	return append(common.Bytes("itx/"), hash[:]...)
+}
+
+// InternalTxsEntry records the value transfers made by the contracts during the execution of a
+// smart contract Tx.
+type InternalTxsEntry struct {
+	TxHash      common.Hash
+	InternalTxs []*types.InternalTransaction
+}
+
+// SaveInternalTxs persists the internal transactions of a smart contract transaction.
+func (ch *Chain) SaveInternalTxs(internalTxsEntry *InternalTxsEntry) {
+	key := internalTxsKey(internalTxsEntry.TxHash)
+
+	err := ch.store.Put(key, *internalTxsEntry)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// FindInternalTxsByHash looks up the internal transactions of a smart contract transaction by hash.
+func (ch *Chain) FindInternalTxsByHash(hash common.Hash) (*InternalTxsEntry, bool) {
+	internalTxsEntry := &InternalTxsEntry{}
+
+	err := ch.store.Get(internalTxsKey(hash), internalTxsEntry)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return internalTxsEntry, true
+}
+
+// ---------------- Block Logs ---------------
+
+// blockLogsKey constructs the DB key for the logs of the finalized block at the given height.
+func blockLogsKey(height uint64) common.Bytes {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, height)
+	return append(common.Bytes("bl/"), buf[:n]...)
+}
+
+// LogEntry is an event log with its position in the chain.
+type LogEntry struct {
+	TxHash   common.Hash
+	TxIndex  uint64
+	LogIndex uint64 // Index of the log in the block
+	Log      *types.Log
+}
+
+// BlockLogsEntry records the event logs emitted by the transactions of a finalized block, and the
+// bloom filter of their addresses and topics.
+type BlockLogsEntry struct {
+	BlockHash   common.Hash
+	BlockHeight uint64
+	Bloom       types.Bloom
+	Logs        []LogEntry
+}
+
+// AddBlockLogsToIndex indexes the event logs of the given finalized block by height. The logs are
+// collected from the receipts of the block transactions. Blocks without logs are not indexed.
+func (ch *Chain) AddBlockLogsToIndex(block *core.ExtendedBlock) {
+	entry := BlockLogsEntry{
+		BlockHash:   block.Hash(),
+		BlockHeight: block.Height,
+		Logs:        []LogEntry{},
+	}
+	for idx, rawTx := range block.Txs {
+		txHash := crypto.Keccak256Hash(rawTx)
+		receipt, found := ch.FindTxReceiptByHash(txHash)
+		if !found {
+			continue
+		}
+		for _, log := range receipt.Logs {
+			entry.Logs = append(entry.Logs, LogEntry{
+				TxHash:   txHash,
+				TxIndex:  uint64(idx),
+				LogIndex: uint64(len(entry.Logs)),
+				Log:      log,
+			})
+		}
+		entry.Bloom.Or(receipt.Bloom)
+	}
+	if len(entry.Logs) == 0 {
+		return
+	}
+
+	err := ch.store.Put(blockLogsKey(block.Height), entry)
+	if err != nil {
+		logger.Panic(err)
+	}
+}
+
+// FindBlockLogsByHeight looks up the event logs of the finalized block at the given height.
+func (ch *Chain) FindBlockLogsByHeight(height uint64) (*BlockLogsEntry, bool) {
+	entry := &BlockLogsEntry{}
+	err := ch.store.Get(blockLogsKey(height), entry)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return nil, false
+	}
+	return entry, true
+}
+
+// LogFilter specifies the event logs to look up. A log matches if it is emitted by any of the
+// addresses, and its topic at each position is any of the topics at that position. An empty
+// list of addresses or topics matches anything.
+type LogFilter struct {
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+// MatchBloom checks whether the block with the given bloom might contain matching logs.
+func (f *LogFilter) MatchBloom(bloom types.Bloom) bool {
+	if len(f.Addresses) > 0 {
+		matched := false
+		for _, addr := range f.Addresses {
+			if bloom.Test(addr.Bytes()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		matched := false
+		for _, topic := range topics {
+			if bloom.Test(topic.Bytes()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Match checks whether the log matches the filter.
+func (f *LogFilter) Match(log *types.Log) bool {
+	if len(f.Addresses) > 0 {
+		matched := false
+		for _, addr := range f.Addresses {
+			if log.Address == addr {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		matched := false
+		for _, topic := range topics {
+			if log.Topics[i] == topic {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------------- Reward Events ---------------
+
+// The reasons of the reward events.
+const (
+	RewardReasonValidator     = "validator"       // Reward of the stakes delegated to a validator
+	RewardReasonGuardian      = "guardian"        // Reward of the stakes delegated to a guardian
+	RewardReasonStaking       = "staking"         // Reward aggregated over the validator and guardian stakes of the source
+	RewardReasonEliteEdgeNode = "elite_edge_node" // Reward of the stakes delegated to an elite edge node
+	RewardReasonSplit         = "split"           // Share of the reward split to the beneficiary of the stake holder
+)
+
+// The reward events of an address are indexed in buckets of rewardEventsBucketSize heights.
+const rewardEventsBucketSize = 10000
+
+// rewardEventsKey constructs the DB key for the reward events of the given address in the given bucket.
+func rewardEventsKey(address common.Address, bucket uint64) common.Bytes {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, bucket)
+	key := append(common.Bytes("rwd/"), address[:]...)
+	return append(key, buf[:n]...)
+}
+
+// RewardEvent records a reward paid by the coinbase transaction of a block.
+type RewardEvent struct {
+	Height      uint64
+	Beneficiary common.Address
+	Holder      common.Address // The validator, guardian or elite edge node of the rewarded stakes, empty if aggregated
+	Reason      string
+	Amount      *big.Int // In TFuelWei
+}
+
+// RewardEventsEntry records the reward events of an address in a bucket of heights.
+type RewardEventsEntry struct {
+	Events []*RewardEvent
+}
+
+// SaveRewardEvents indexes the reward events of a block by their beneficiaries. The events already
+// indexed for the block height are replaced, so that saving the events of a block again does not
+// duplicate them.
+func (ch *Chain) SaveRewardEvents(events []*RewardEvent) {
+	if len(events) == 0 {
+		return
+	}
+	height := events[0].Height
+	bucket := height / rewardEventsBucketSize
+
+	entries := make(map[common.Address]*RewardEventsEntry)
+	beneficiaries := []common.Address{}
+	for _, event := range events {
+		entry, ok := entries[event.Beneficiary]
+		if !ok {
+			entry = &RewardEventsEntry{}
+			err := ch.store.Get(rewardEventsKey(event.Beneficiary, bucket), entry)
+			if err != nil && err != store.ErrKeyNotFound {
+				logger.Panic(err)
+			}
+			kept := []*RewardEvent{}
+			for _, existing := range entry.Events {
+				if existing.Height != height {
+					kept = append(kept, existing)
+				}
+			}
+			entry.Events = kept
+			entries[event.Beneficiary] = entry
+			beneficiaries = append(beneficiaries, event.Beneficiary)
+		}
+		entry.Events = append(entry.Events, event)
+	}
+
+	for _, beneficiary := range beneficiaries {
+		entry := entries[beneficiary]
+		sort.SliceStable(entry.Events, func(i, j int) bool { return entry.Events[i].Height < entry.Events[j].Height })
+		err := ch.store.Put(rewardEventsKey(beneficiary, bucket), *entry)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+}
+
+// FindRewardEventsByAddress looks up the reward events of the address between the start and the
+// end heights (inclusive), in the order of the heights.
+func (ch *Chain) FindRewardEventsByAddress(address common.Address, startHeight, endHeight uint64) []*RewardEvent {
+	events := []*RewardEvent{}
+	if startHeight > endHeight {
+		return events
+	}
+	for bucket := startHeight / rewardEventsBucketSize; bucket <= endHeight/rewardEventsBucketSize; bucket++ {
+		entry := &RewardEventsEntry{}
+		err := ch.store.Get(rewardEventsKey(address, bucket), entry)
+		if err != nil {
+			if err != store.ErrKeyNotFound {
+				logger.Error(err)
+			}
+			continue
+		}
+		for _, event := range entry.Events {
+			if event.Height >= startHeight && event.Height <= endHeight {
+				events = append(events, event)
+			}
+		}
+	}
+	return events
+}
+
+// ---------------- Split Rule Lapse Events ---------------
+
+// The max number of split rule lapse events kept for each initiator, the older ones are dropped.
+const maxSplitRuleLapseEvents = 1000
+
+// splitRuleLapseEventsKey constructs the DB key for the split rule lapse events of the given initiator.
+func splitRuleLapseEventsKey(initiator common.Address) common.Bytes {
+	return append(common.Bytes("srl/"), initiator[:]...)
+}
+
+// SplitRuleLapseEvent records a split rule deleted after its end block height.
+type SplitRuleLapseEvent struct {
+	Height         uint64 // The height of the block which deleted the split rule
+	ResourceID     string
+	Initiator      common.Address
+	EndBlockHeight uint64
+}
+
+// SplitRuleLapseEventsEntry records the split rule lapse events of an initiator.
+type SplitRuleLapseEventsEntry struct {
+	Events []*SplitRuleLapseEvent
+}
+
+// SaveSplitRuleLapseEvents indexes the split rule lapse events of a block by their initiators. The
+// events already indexed for the block height are replaced, so that saving the events of a block
+// again does not duplicate them.
+func (ch *Chain) SaveSplitRuleLapseEvents(events []*SplitRuleLapseEvent) {
+	if len(events) == 0 {
+		return
+	}
+	height := events[0].Height
+
+	entries := make(map[common.Address]*SplitRuleLapseEventsEntry)
+	initiators := []common.Address{}
+	for _, event := range events {
+		entry, ok := entries[event.Initiator]
+		if !ok {
+			entry = &SplitRuleLapseEventsEntry{}
+			err := ch.store.Get(splitRuleLapseEventsKey(event.Initiator), entry)
+			if err != nil && err != store.ErrKeyNotFound {
+				logger.Panic(err)
+			}
+			kept := []*SplitRuleLapseEvent{}
+			for _, existing := range entry.Events {
+				if existing.Height != height {
+					kept = append(kept, existing)
+				}
+			}
+			entry.Events = kept
+			entries[event.Initiator] = entry
+			initiators = append(initiators, event.Initiator)
+		}
+		entry.Events = append(entry.Events, event)
+	}
+
+	for _, initiator := range initiators {
+		entry := entries[initiator]
+		sort.SliceStable(entry.Events, func(i, j int) bool { return entry.Events[i].Height < entry.Events[j].Height })
+		if len(entry.Events) > maxSplitRuleLapseEvents {
+			entry.Events = entry.Events[len(entry.Events)-maxSplitRuleLapseEvents:]
+		}
+		err := ch.store.Put(splitRuleLapseEventsKey(initiator), *entry)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+}
+
+// FindSplitRuleLapseEventsByInitiator looks up the split rule lapse events of the initiator, in the
+// order of the heights.
+func (ch *Chain) FindSplitRuleLapseEventsByInitiator(initiator common.Address) []*SplitRuleLapseEvent {
+	entry := &SplitRuleLapseEventsEntry{}
+	err := ch.store.Get(splitRuleLapseEventsKey(initiator), entry)
+	if err != nil {
+		if err != store.ErrKeyNotFound {
+			logger.Error(err)
+		}
+		return []*SplitRuleLapseEvent{}
+	}
+	return entry.Events
+}
+
 // ---------------- Utils ---------------
 
 func CalcEthTxHash(block *core.ExtendedBlock, rawTxBytes []byte) (common.Hash, error) {
@@ -1,6 +1,8 @@
 package blockchain
 
 import (
+	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,6 +10,7 @@ import (
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
 )
 
 func TestTxIndex(t *testing.T) {
@@ -40,6 +43,40 @@ func TestTxIndex(t *testing.T) {
 	assert.Nil(block)
 }
 
+func TestLookupTx(t *testing.T) {
+	assert := assert.New(t)
+
+	tx1 := common.Bytes("tx1")
+	tx2 := common.Bytes("tx2")
+	tx3 := common.Bytes("tx3")
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{tx1, tx2}
+	block1.UpdateHash()
+
+	chain := CreateTestChain()
+	chain.AddBlock(block1)
+
+	for idx, raw := range block1.Txs {
+		lookup, found := chain.LookupTx(crypto.Keccak256Hash(raw))
+		assert.True(found)
+		assert.Equal(raw, lookup.RawTx)
+		assert.Equal(block1.Hash(), lookup.BlockHash)
+		assert.Equal(uint64(10), lookup.BlockHeight)
+		assert.Equal(uint64(idx), lookup.Index)
+		assert.False(lookup.BlockStatus.IsFinalized())
+	}
+
+	assert.Nil(chain.FinalizePreviousBlocks(block1.Hash()))
+	lookup, found := chain.LookupTx(crypto.Keccak256Hash(tx2))
+	assert.True(found)
+	assert.Equal(tx2, lookup.RawTx)
+	assert.True(lookup.BlockStatus.IsFinalized())
+
+	_, found = chain.LookupTx(crypto.Keccak256Hash(tx3))
+	assert.False(found)
+}
+
 func TestTxIndexDuplicateTx(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -176,3 +213,178 @@ func TestTxIndexDuplicateTx2(t *testing.T) {
 	assert.NotNil(block)
 	assert.Equal(block.Hash(), block2.Hash())
 }
+
+func TestBlockLogsIndex(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	contract1 := common.HexToAddress("0x01")
+	contract2 := common.HexToAddress("0x02")
+	topic1 := common.HexToHash("0x11")
+	topic2 := common.HexToHash("0x12")
+
+	tx1 := &types.SmartContractTx{GasLimit: 100000, Data: common.Hex2Bytes("01")}
+	tx2 := &types.SmartContractTx{GasLimit: 100000, Data: common.Hex2Bytes("02")}
+	raw1, err := types.TxToBytes(tx1)
+	require.Nil(err)
+	raw2, err := types.TxToBytes(tx2)
+	require.Nil(err)
+
+	core.ResetTestBlocks()
+	chain := CreateTestChain()
+	block1 := core.CreateTestBlock("b1", "")
+	block1.Height = 10
+	block1.Txs = []common.Bytes{raw1, common.Bytes("tx"), raw2}
+	eb, err := chain.AddBlock(block1)
+	require.Nil(err)
+
+	chain.AddTxReceipt(tx1, []*types.Log{{Address: contract1, Topics: []common.Hash{topic1}}}, nil, common.Address{}, 0, nil)
+	chain.AddTxReceipt(tx2, []*types.Log{{Address: contract2, Topics: []common.Hash{topic2, topic1}}}, nil, common.Address{}, 0, nil)
+	chain.AddBlockLogsToIndex(eb)
+
+	entry, found := chain.FindBlockLogsByHeight(10)
+	require.True(found)
+	assert.Equal(block1.Hash(), entry.BlockHash)
+	assert.Equal(2, len(entry.Logs))
+	assert.Equal(uint64(0), entry.Logs[0].TxIndex)
+	assert.Equal(uint64(2), entry.Logs[1].TxIndex)
+	assert.Equal(uint64(1), entry.Logs[1].LogIndex)
+	assert.True(entry.Bloom.Test(contract1.Bytes()))
+	assert.True(entry.Bloom.Test(topic2.Bytes()))
+
+	receipt, found := chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw2))
+	require.True(found)
+	assert.True(receipt.Bloom.Test(contract2.Bytes()))
+	assert.False(receipt.Bloom.Test(contract1.Bytes()))
+
+	_, found = chain.FindBlockLogsByHeight(11)
+	assert.False(found)
+
+	filter := &LogFilter{Addresses: []common.Address{contract2}}
+	assert.True(filter.MatchBloom(entry.Bloom))
+	assert.False(filter.Match(entry.Logs[0].Log))
+	assert.True(filter.Match(entry.Logs[1].Log))
+
+	// Topics are positional, and nil matches any topic at the position
+	filter = &LogFilter{Topics: [][]common.Hash{nil, {topic1}}}
+	assert.False(filter.Match(entry.Logs[0].Log))
+	assert.True(filter.Match(entry.Logs[1].Log))
+
+	filter = &LogFilter{Addresses: []common.Address{common.HexToAddress("0x03")}}
+	assert.False(filter.Match(entry.Logs[0].Log))
+	assert.False(filter.Match(entry.Logs[1].Log))
+}
+
+func TestTxReceipt(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	tx1 := &types.SmartContractTx{GasLimit: 100000, Data: common.Hex2Bytes("01")}
+	tx2 := &types.SmartContractTx{GasLimit: 100000, Data: common.Hex2Bytes("02")}
+	raw1, err := types.TxToBytes(tx1)
+	require.Nil(err)
+	raw2, err := types.TxToBytes(tx2)
+	require.Nil(err)
+
+	contractAddr := common.HexToAddress("0x01")
+	receipt1 := NewTxReceiptEntry(tx1, nil, common.Bytes("ret"), contractAddr, 21000, nil)
+	receipt2 := NewTxReceiptEntry(tx2, nil, nil, common.Address{}, 100000, errors.New("out of gas"))
+	assert.True(receipt1.Succeeded())
+	assert.False(receipt2.Succeeded())
+
+	chain := CreateTestChain()
+	_, found := chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw1))
+	assert.False(found)
+
+	chain.SaveTxReceipt(receipt1)
+	chain.SaveTxReceipt(receipt2)
+
+	receipt, found := chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw1))
+	require.True(found)
+	assert.True(receipt.Succeeded())
+	assert.Equal(contractAddr, receipt.ContractAddress)
+	assert.Equal(uint64(21000), receipt.GasUsed)
+
+	receipt, found = chain.FindTxReceiptByHash(crypto.Keccak256Hash(raw2))
+	require.True(found)
+	assert.False(receipt.Succeeded())
+	assert.Equal("out of gas", receipt.EvmErr)
+}
+
+func TestRewardEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	addr1 := common.HexToAddress("0x01")
+	addr2 := common.HexToAddress("0x02")
+	holder := common.HexToAddress("0x0a")
+
+	chain := CreateTestChain()
+	assert.Equal(0, len(chain.FindRewardEventsByAddress(addr1, 0, 100000)))
+
+	chain.SaveRewardEvents([]*RewardEvent{
+		{Height: 9900, Beneficiary: addr1, Holder: holder, Reason: RewardReasonGuardian, Amount: big.NewInt(100)},
+		{Height: 9900, Beneficiary: addr2, Holder: holder, Reason: RewardReasonSplit, Amount: big.NewInt(10)},
+	})
+	chain.SaveRewardEvents([]*RewardEvent{
+		{Height: 10100, Beneficiary: addr1, Holder: holder, Reason: RewardReasonGuardian, Amount: big.NewInt(200)},
+	})
+	chain.SaveRewardEvents([]*RewardEvent{
+		{Height: 10000, Beneficiary: addr1, Holder: holder, Reason: RewardReasonGuardian, Amount: big.NewInt(300)},
+	})
+
+	events := chain.FindRewardEventsByAddress(addr1, 0, 100000)
+	assert.Equal(3, len(events))
+	assert.Equal(uint64(9900), events[0].Height)
+	assert.Equal(uint64(10000), events[1].Height)
+	assert.Equal(uint64(10100), events[2].Height)
+	assert.Equal(holder, events[0].Holder)
+	assert.Equal(RewardReasonGuardian, events[0].Reason)
+	assert.Equal(int64(100), events[0].Amount.Int64())
+
+	events = chain.FindRewardEventsByAddress(addr1, 9901, 10100)
+	assert.Equal(2, len(events))
+	assert.Equal(uint64(10000), events[0].Height)
+
+	events = chain.FindRewardEventsByAddress(addr2, 0, 100000)
+	assert.Equal(1, len(events))
+	assert.Equal(RewardReasonSplit, events[0].Reason)
+
+	// Saving the events of a height again replaces them
+	chain.SaveRewardEvents([]*RewardEvent{
+		{Height: 10100, Beneficiary: addr1, Holder: holder, Reason: RewardReasonGuardian, Amount: big.NewInt(250)},
+	})
+	events = chain.FindRewardEventsByAddress(addr1, 10100, 10100)
+	assert.Equal(1, len(events))
+	assert.Equal(int64(250), events[0].Amount.Int64())
+}
+
+func TestSplitRuleLapseEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	initiator1 := common.HexToAddress("0x01")
+	initiator2 := common.HexToAddress("0x02")
+
+	chain := CreateTestChain()
+	assert.Equal(0, len(chain.FindSplitRuleLapseEventsByInitiator(initiator1)))
+
+	chain.SaveSplitRuleLapseEvents([]*SplitRuleLapseEvent{
+		{Height: 200, ResourceID: "rid2", Initiator: initiator1, EndBlockHeight: 150},
+		{Height: 200, ResourceID: "rid3", Initiator: initiator2, EndBlockHeight: 180},
+	})
+	chain.SaveSplitRuleLapseEvents([]*SplitRuleLapseEvent{
+		{Height: 100, ResourceID: "rid1", Initiator: initiator1, EndBlockHeight: 90},
+	})
+
+	events := chain.FindSplitRuleLapseEventsByInitiator(initiator1)
+	assert.Equal(2, len(events))
+	assert.Equal("rid1", events[0].ResourceID)
+	assert.Equal("rid2", events[1].ResourceID)
+	assert.Equal(uint64(150), events[1].EndBlockHeight)
+	assert.Equal(1, len(chain.FindSplitRuleLapseEventsByInitiator(initiator2)))
+
+	// Saving the events of a height again replaces them
+	chain.SaveSplitRuleLapseEvents([]*SplitRuleLapseEvent{
+		{Height: 200, ResourceID: "rid2", Initiator: initiator1, EndBlockHeight: 150},
+	})
+	assert.Equal(2, len(chain.FindSplitRuleLapseEventsByInitiator(initiator1)))
+}
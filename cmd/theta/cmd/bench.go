@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/ledger/bench"
+)
+
+var benchBackend string
+var benchWorkload string
+var benchNumBlocks int
+var benchDataDir string
+var benchCPUProfile string
+var benchMemProfile string
+
+// benchCmd runs the ledger benchmarks
+var benchCmd = &cobra.Command{
+	Use:     "bench",
+	Short:   "Benchmark the block application of the ledger.",
+	Long:    "Generate and apply the blocks of a workload (send, contract or stake) on a fresh ledger state, and report the time spent applying them. The CPU and memory profiles can be written for pprof.",
+	Example: `theta bench --workload=send --backend=leveldb --blocks=100 --cpuprofile=cpu.prof`,
+	Run:     runBench,
+}
+
+func init() {
+	benchCmd.Flags().StringVar(&benchBackend, "backend", bench.BackendMemory, "Database backend, memory or leveldb")
+	benchCmd.Flags().StringVar(&benchWorkload, "workload", bench.WorkloadSend, "Workload, send, contract or stake")
+	benchCmd.Flags().IntVar(&benchNumBlocks, "blocks", 20, "Number of blocks to apply")
+	benchCmd.Flags().StringVar(&benchDataDir, "data_dir", "", "Directory of the LevelDB database (default is a temporary directory)")
+	benchCmd.Flags().StringVar(&benchCPUProfile, "cpuprofile", "", "Write the CPU profile to the file")
+	benchCmd.Flags().StringVar(&benchMemProfile, "memprofile", "", "Write the memory profile to the file")
+	RootCmd.AddCommand(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) {
+	dir := benchDataDir
+	if dir == "" && benchBackend == bench.BackendLevelDB {
+		tmpDir, err := ioutil.TempDir("", "theta_bench")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create the data directory: %v\n", err)
+			os.Exit(1)
+		}
+		defer os.RemoveAll(tmpDir)
+		dir = tmpDir
+	}
+
+	if benchCPUProfile != "" {
+		file, err := os.Create(benchCPUProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %v: %v\n", benchCPUProfile, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		if err := pprof.StartCPUProfile(file); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start the CPU profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	res, err := bench.Run(benchBackend, benchWorkload, dir, benchNumBlocks)
+	if benchCPUProfile != "" {
+		pprof.StopCPUProfile()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(res)
+
+	if benchMemProfile != "" {
+		file, err := os.Create(benchMemProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %v: %v\n", benchMemProfile, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to write the memory profile: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the config",
+}
+
+// configCheckCmd represents the config check command, which validates the config the same way as
+// the node does on start, without starting the node.
+// Example:
+//		theta config check --config=../privatenet/node
+var configCheckCmd = &cobra.Command{
+	Use:     "check",
+	Short:   "Check the config for unknown keys, invalid values and conflicting options",
+	Example: `theta config check --config=../privatenet/node`,
+	Run:     runConfigCheck,
+}
+
+func init() {
+	configCmd.AddCommand(configCheckCmd)
+	RootCmd.AddCommand(configCmd)
+}
+
+func runConfigCheck(cmd *cobra.Command, args []string) {
+	if viper.ConfigFileUsed() == "" {
+		fmt.Printf("No config file found in %v, checking the defaults\n", cfgPath)
+	}
+	errs := common.CheckConfig()
+	if len(errs) == 0 {
+		fmt.Println("Config OK")
+		return
+	}
+	for _, err := range errs {
+		fmt.Println(err)
+	}
+	fmt.Printf("Found %v problem(s)\n", len(errs))
+	os.Exit(1)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/consensus"
+)
+
+var replayVerbose bool
+
+// consensusCmd represents the consensus command
+var consensusCmd = &cobra.Command{
+	Use:   "consensus",
+	Short: "Consensus debugging tools.",
+}
+
+// replayCmd reconstructs the decision timeline from consensus journals
+var replayCmd = &cobra.Command{
+	Use:     "replay [journal files...]",
+	Short:   "Reconstruct the consensus decision timeline from the journal files.",
+	Example: `theta consensus replay consensus_journal.1 consensus_journal`,
+	Args:    cobra.MinimumNArgs(1),
+	Run:     runReplay,
+}
+
+func init() {
+	replayCmd.Flags().BoolVar(&replayVerbose, "verbose", false, "Print every event in addition to the epoch summaries")
+	consensusCmd.AddCommand(replayCmd)
+	RootCmd.AddCommand(consensusCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) {
+	events := []consensus.JournalEvent{}
+	for _, journalPath := range args {
+		fileEvents, err := consensus.ReadJournal(journalPath)
+		if err != nil {
+			if len(fileEvents) == 0 {
+				fmt.Printf("Failed to read journal %v: %v\n", journalPath, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Warning: %v, %v events read from %v\n", err, len(fileEvents), journalPath)
+		}
+		events = append(events, fileEvents...)
+	}
+	if len(events) == 0 {
+		fmt.Println("No consensus event recorded")
+		return
+	}
+
+	start := events[0].Time()
+	if replayVerbose {
+		for _, event := range events {
+			fmt.Printf("%12v  %v\n", event.Time().Sub(start).Round(time.Millisecond), event)
+		}
+		fmt.Println("")
+	}
+
+	numTimeouts := 0
+	for _, epoch := range consensus.BuildTimeline(events) {
+		status := "ok"
+		if epoch.TimedOut {
+			status = "TIMEOUT"
+			numTimeouts++
+		}
+		firstBlock := "-"
+		if len(epoch.Blocks) > 0 {
+			firstBlock = epoch.FirstBlock.Round(time.Millisecond).String()
+		}
+		fmt.Printf("%v  epoch %-8v %-7v duration: %-10v proposed: %-5v blocks: %-2v first block: %-10v votes: %-4v voters: %-3v finalized: %v\n",
+			epoch.Start.Format("2006-01-02 15:04:05.000"), epoch.Epoch, status,
+			epoch.End.Sub(epoch.Start).Round(time.Millisecond), epoch.Proposed, len(epoch.Blocks), firstBlock,
+			epoch.NumVotes, len(epoch.Voters), epoch.Finalized)
+	}
+
+	fmt.Printf("\n%v events from %v to %v, %v epoch timeouts\n", len(events),
+		start.Format(time.RFC3339), events[len(events)-1].Time().Format(time.RFC3339), numTimeouts)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/rlp"
+	"github.com/thetatoken/theta/snapshot"
+	"github.com/thetatoken/theta/store/database"
+	"github.com/thetatoken/theta/store/database/backend"
+	"github.com/thetatoken/theta/store/kvstore"
+)
+
+var dumpFromHeight uint64
+var dumpToHeight uint64
+var dumpFormat string
+var dumpOutput string
+var dumpStateHeight uint64
+var dumpStateChainID string
+var dumpStateOutput string
+
+// dumpCmd represents the dump command
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Dump chain data of a stopped node.",
+}
+
+// dumpBlocksCmd writes the finalized blocks in a height range to a file
+var dumpBlocksCmd = &cobra.Command{
+	Use:     "blocks",
+	Short:   "Dump the finalized blocks in a height range.",
+	Example: `theta dump blocks --config=../privatenet/node --from=1 --to=1000 --format=json --output=blocks.json`,
+	Run:     runDumpBlocks,
+}
+
+// dumpStateCmd writes the state at a finalized height as a genesis snapshot
+var dumpStateCmd = &cobra.Command{
+	Use:     "state",
+	Short:   "Dump the state at a finalized height as a genesis snapshot.",
+	Long:    "Dump the state at a finalized height in the genesis snapshot format of generate_genesis, to start a new chain from the state. The state at the height must not have been pruned.",
+	Example: `theta dump state --config=../privatenet/node --height=1000 --chain_id=forknet --output=genesis`,
+	Run:     runDumpState,
+}
+
+func init() {
+	dumpStateCmd.Flags().Uint64Var(&dumpStateHeight, "height", 0, "Height of the finalized block to dump the state of")
+	dumpStateCmd.Flags().StringVar(&dumpStateChainID, "chain_id", "", "Chain ID of the new genesis block (default is the chain ID of the node)")
+	dumpStateCmd.Flags().StringVar(&dumpStateOutput, "output", "genesis", "Output file")
+	dumpStateCmd.MarkFlagRequired("height")
+	dumpCmd.AddCommand(dumpStateCmd)
+
+	dumpBlocksCmd.Flags().Uint64Var(&dumpFromHeight, "from", 0, "Height of the first block to dump")
+	dumpBlocksCmd.Flags().Uint64Var(&dumpToHeight, "to", 0, "Height of the last block to dump")
+	dumpBlocksCmd.Flags().StringVar(&dumpFormat, "format", snapshot.BlockDumpFormatRLP, "Dump format, rlp or json")
+	dumpBlocksCmd.Flags().StringVar(&dumpOutput, "output", "", "Output file (default is stdout)")
+	dumpBlocksCmd.MarkFlagRequired("to")
+	dumpCmd.AddCommand(dumpBlocksCmd)
+	RootCmd.AddCommand(dumpCmd)
+}
+
+func runDumpBlocks(cmd *cobra.Command, args []string) {
+	chain, db, err := openChain()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	var output io.Writer = os.Stdout
+	if dumpOutput != "" {
+		file, err := os.Create(dumpOutput)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create %v: %v\n", dumpOutput, err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	count, err := snapshot.DumpBlocks(chain, dumpFromHeight, dumpToHeight, dumpFormat, output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Dumped %v blocks between height %v and %v\n", count, dumpFromHeight, dumpToHeight)
+}
+
+func runDumpState(cmd *cobra.Command, args []string) {
+	chain, db, err := openChain()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	file, err := os.Create(dumpStateOutput)
+	if err != nil {
+		fmt.Printf("Failed to create %v: %v\n", dumpStateOutput, err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	genesisBlockHeader, err := snapshot.ExportGenesisState(db, chain, dumpStateHeight, dumpStateChainID, file)
+	if err != nil {
+		fmt.Println(err)
+		os.Remove(dumpStateOutput)
+		os.Exit(1)
+	}
+
+	fmt.Println("")
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Printf("Genesis block hash: %v\n", genesisBlockHeader.Hash().Hex())
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Println("")
+}
+
+// openChain opens the chain in the data directory of a node that has been started at least
+// once. The node must be stopped, since the database can only be opened by one process.
+func openChain() (*blockchain.Chain, database.Database, error) {
+	dbPath := viper.GetString(common.CfgDataPath)
+	if dbPath == "" {
+		dbPath = cfgPath
+	}
+
+	mainDBPath := path.Join(dbPath, "db", "main")
+	refDBPath := path.Join(dbPath, "db", "ref")
+	db, err := backend.NewLDBDatabase(mainDBPath, refDBPath,
+		viper.GetInt(common.CfgStorageLevelDBCacheSize),
+		viper.GetInt(common.CfgStorageLevelDBHandles))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to open the db. main: %v, ref: %v, err: %v", mainDBPath, refDBPath, err)
+	}
+
+	raw, err := db.Get([]byte("/snapshot_blockheader"))
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("No snapshot has been loaded into the db at %v, start the node once first", dbPath)
+	}
+	snapshotBlockHeader := &core.BlockHeader{}
+	if err := rlp.DecodeBytes(raw, snapshotBlockHeader); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("Failed to decode the snapshot block header: %v", err)
+	}
+
+	root := &core.Block{BlockHeader: snapshotBlockHeader}
+	chain := blockchain.NewChain(root.ChainID, kvstore.NewKVStore(db), root)
+	return chain, db, nil
+}
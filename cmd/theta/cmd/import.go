@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/snapshot"
+)
+
+var importFormat string
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import chain data into a stopped node.",
+}
+
+// importBlocksCmd adds the blocks written by "theta dump blocks" to the chain
+var importBlocksCmd = &cobra.Command{
+	Use:     "blocks [dump file]",
+	Short:   "Import the blocks dumped from another node.",
+	Long:    "Import the blocks dumped from another node. The votes are not validated against the validator sets, so only import dumps from trusted sources.",
+	Example: `theta import blocks --config=../privatenet/node --format=json blocks.json`,
+	Args:    cobra.ExactArgs(1),
+	Run:     runImportBlocks,
+}
+
+func init() {
+	importBlocksCmd.Flags().StringVar(&importFormat, "format", snapshot.BlockDumpFormatRLP, "Dump format, rlp or json")
+	importCmd.AddCommand(importBlocksCmd)
+	RootCmd.AddCommand(importCmd)
+}
+
+func runImportBlocks(cmd *cobra.Command, args []string) {
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Printf("Failed to open %v: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	chain, db, err := openChain()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	imported, skipped, err := snapshot.ImportBlocks(chain, file, importFormat)
+	fmt.Printf("Imported %v blocks, skipped %v blocks already in the chain\n", imported, skipped)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/lightclient"
+)
+
+// lightCmd represents the light command
+var lightCmd = &cobra.Command{
+	Use:   "light",
+	Short: "Start Theta light client, which syncs and verifies block headers only.",
+	Run:   runLight,
+}
+
+func init() {
+	RootCmd.AddCommand(lightCmd)
+}
+
+func runLight(cmd *cobra.Command, args []string) {
+	dataPath := viper.GetString(common.CfgDataPath)
+	if dataPath == "" {
+		dataPath = cfgPath
+	}
+	err := os.MkdirAll(path.Join(dataPath, "db"), 0700)
+	if err != nil {
+		log.Fatalf("Failed to create data directory: %v", err)
+	}
+	checkpointPath := path.Join(dataPath, "db", "light_checkpoint")
+
+	remote := viper.GetString(common.CfgLightRemoteRPCEndpoint)
+	client, err := lightclient.NewLightClient(remote, checkpointPath)
+	if err != nil {
+		log.Fatalf("Failed to start light client: %v", err)
+	}
+	server := lightclient.NewLightRPCServer(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal)
+	signal.Notify(c, os.Interrupt)
+	go func() {
+		<-c
+		signal.Stop(c)
+		cancel()
+	}()
+
+	client.Start(ctx)
+	server.Start(ctx)
+
+	client.Wait()
+	server.Wait()
+
+	log.Infof("Graceful exit.")
+}
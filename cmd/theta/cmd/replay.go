@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/ledger"
+	"github.com/thetatoken/theta/snapshot"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+var replayFromHeight uint64
+var replayToHeight uint64
+var replayStatePath string
+
+// replayBlocksCmd re-applies a range of finalized blocks against an imported state
+var replayBlocksCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Re-apply the finalized blocks of a stopped node against a state snapshot.",
+	Long: `Re-apply the finalized blocks in a height range against the state imported from a snapshot,
+verifying the state root after each block, and report the first diverging block. The snapshot
+must be taken at the parent of the first block. The node database is not modified.`,
+	Example: `theta replay --config=../privatenet/node --state=theta_snapshot-1000 --from=1001 --to=2000`,
+	Run:     runReplayBlocks,
+}
+
+func init() {
+	replayBlocksCmd.Flags().Uint64Var(&replayFromHeight, "from", 0, "Height of the first block to replay (default is the height of the snapshot plus one)")
+	replayBlocksCmd.Flags().Uint64Var(&replayToHeight, "to", 0, "Height of the last block to replay")
+	replayBlocksCmd.Flags().StringVar(&replayStatePath, "state", "", "Path of the state snapshot to replay the blocks against")
+	replayBlocksCmd.MarkFlagRequired("to")
+	replayBlocksCmd.MarkFlagRequired("state")
+	RootCmd.AddCommand(replayBlocksCmd)
+}
+
+func runReplayBlocks(cmd *cobra.Command, args []string) {
+	chain, db, err := openChain()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// The snapshot is imported into a temporary db, which also receives the replayed states
+	tmpdbRoot, err := ioutil.TempDir("", "replaydb")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create the temporary db: %v\n", err)
+		os.Exit(1)
+	}
+	defer os.RemoveAll(tmpdbRoot)
+	tmpdb, err := backend.NewLDBDatabase(path.Join(tmpdbRoot, "main"), path.Join(tmpdbRoot, "ref"), 256, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create the temporary db: %v\n", err)
+		os.Exit(1)
+	}
+	replaydb := backend.NewOverlayDatabase(tmpdb, db)
+	defer replaydb.Close()
+
+	snapshotBlockHeader, _, err := snapshot.ImportSnapshot(replayStatePath, "", "", nil, tmpdb, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to import the snapshot %v: %v\n", replayStatePath, err)
+		os.Exit(1)
+	}
+	if replayFromHeight == 0 {
+		replayFromHeight = snapshotBlockHeader.Height + 1
+	}
+	if replayFromHeight != snapshotBlockHeader.Height+1 {
+		fmt.Fprintf(os.Stderr, "The snapshot is taken at height %v, the replay must start from height %v\n",
+			snapshotBlockHeader.Height, snapshotBlockHeader.Height+1)
+		os.Exit(1)
+	}
+
+	replayer := ledger.NewReplayer(chain.ChainID, replaydb, chain, consensus.NewRotatingValidatorManager())
+	divergence, err := replayer.Replay(replayFromHeight, replayToHeight, func(replay *ledger.BlockReplay) {
+		if replay.Height%1000 == 0 {
+			fmt.Printf("Replayed block %v at height %v\n", replay.BlockHash.Hex(), replay.Height)
+		}
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if divergence != nil {
+		fmt.Println("")
+		fmt.Printf("--------------------------------------------------------------------------\n")
+		fmt.Printf("State diverged at height %v, block %v\n", divergence.Height, divergence.BlockHash.Hex())
+		fmt.Printf("Expected state root: %v\n", divergence.ExpectedStateRoot.Hex())
+		fmt.Printf("Computed state root: %v\n", divergence.StateRoot.Hex())
+		fmt.Printf("--------------------------------------------------------------------------\n")
+		fmt.Println("")
+		os.Exit(1)
+	}
+	fmt.Printf("Verified the state roots of the blocks between height %v and %v\n", replayFromHeight, replayToHeight)
+}
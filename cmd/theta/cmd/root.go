@@ -21,6 +21,8 @@ var chainCorrectionPath string
 
 var nodePassword string
 
+var configOverrides []string
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:   "theta",
@@ -56,6 +58,9 @@ func init() {
 	RootCmd.PersistentFlags().String("key", "", "key path (default to config path)")
 	viper.BindPFlag(common.CfgKeyPath, RootCmd.PersistentFlags().Lookup("key"))
 
+	// Support for overriding the config keys, which takes precedence over the config file and the environment
+	// variables, e.g. --config-override=p2p.port=12000 --config-override=rpc.enabled=true
+	RootCmd.PersistentFlags().StringArrayVar(&configOverrides, "config-override", nil, "override a config key in the form of key=value, can be repeated")
 }
 
 // initConfig is called when cmd.Execute() is called. reads in config file and ENV variables if set.
@@ -79,6 +84,14 @@ func initConfig() {
 		fmt.Println("Using config file:", viper.ConfigFileUsed())
 	}
 
+	// Override the config keys with the environment variables, e.g. THETA_P2P_PORT for p2p.port,
+	// and then with the --config-override flags
+	common.ApplyEnvConfigOverrides(os.Environ())
+	if err := common.ApplyConfigOverrides(configOverrides); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
 	util.InitLog()
 }
 
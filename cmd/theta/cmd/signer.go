@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/signer"
+)
+
+// signerCmd represents the signer command
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Start the remote signer, which signs the votes and proposals of a validator node",
+	Long: `Start the remote signer, which holds the validator key and signs the votes and proposals
+of a validator node configured with signer.remoteAddress. The signer and the node authenticate
+each other with TLS certificates signed by the same CA. The signer keeps the last signed vote
+and proposal, and refuses to sign conflicting ones.`,
+	Run: runSigner,
+}
+
+func init() {
+	RootCmd.AddCommand(signerCmd)
+}
+
+func runSigner(cmd *cobra.Command, args []string) {
+	chainID := viper.GetString(common.CfgGenesisChainID)
+	if chainID == "" {
+		log.Fatalf("Chain ID is required, please set %v", common.CfgGenesisChainID)
+	}
+
+	privKey, err := loadOrCreateKey()
+	if err != nil {
+		log.Fatalf("Failed to load or create key: %v", err)
+	}
+
+	statePath := viper.GetString(common.CfgSignerStatePath)
+	if statePath == "" {
+		dataPath := viper.GetString(common.CfgDataPath)
+		if dataPath == "" {
+			dataPath = cfgPath
+		}
+		statePath = path.Join(dataPath, "signer_state.json")
+	}
+	state, err := signer.LoadSignState(statePath)
+	if err != nil {
+		log.Fatalf("Failed to load signer state: %v", err)
+	}
+
+	tlsConfig, err := signer.NewTLSConfig(viper.GetString(common.CfgSignerTLSCertFile),
+		viper.GetString(common.CfgSignerTLSKeyFile), viper.GetString(common.CfgSignerTLSCAFile), true)
+	if err != nil {
+		log.Fatalf("Failed to load TLS config: %v", err)
+	}
+
+	server := signer.NewServer(signer.NewService(chainID, privKey, state), tlsConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		signal.Stop(c)
+		cancel()
+	}()
+
+	if err := server.Start(ctx, viper.GetString(common.CfgSignerListenAddress)); err != nil {
+		log.Fatalf("Failed to start signer: %v", err)
+	}
+	log.Infof("Signing for validator %v", privKey.PublicKey().Address().Hex())
+
+	server.Wait()
+
+	log.Infof("Graceful exit.")
+}
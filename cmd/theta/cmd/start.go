@@ -10,6 +10,8 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -17,6 +19,8 @@ import (
 	"github.com/spf13/viper"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/metrics"
+	"github.com/thetatoken/theta/common/metrics/prometheus"
 	"github.com/thetatoken/theta/common/util"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
@@ -24,10 +28,12 @@ import (
 	msg "github.com/thetatoken/theta/p2p/messenger"
 	msgl "github.com/thetatoken/theta/p2pl/messenger"
 	"github.com/thetatoken/theta/rlp"
+	"github.com/thetatoken/theta/signer"
 	"github.com/thetatoken/theta/snapshot"
 	"github.com/thetatoken/theta/store/database/backend"
 	"github.com/thetatoken/theta/store/rollingdb"
 	"github.com/thetatoken/theta/version"
+	"github.com/thetatoken/theta/wallet/kms"
 	ks "github.com/thetatoken/theta/wallet/softwallet/keystore"
 )
 
@@ -47,6 +53,19 @@ func runStart(cmd *cobra.Command, args []string) {
 	var network *msgl.Messenger
 	var err error
 
+	if errs := common.CheckConfig(); len(errs) > 0 {
+		for _, err := range errs {
+			log.Error(err)
+		}
+		log.Fatalf("Invalid config, found %v problem(s)", len(errs))
+	}
+
+	// The metrics need to be enabled before the components register them
+	prometheusAddress := viper.GetString(common.CfgMetricsPrometheusAddress)
+	if prometheusAddress != "" {
+		metrics.Enabled = true
+	}
+
 	privKey, err := loadOrCreateKey()
 	if err != nil {
 		log.Fatalf("Failed to load or create key: %v", err)
@@ -135,6 +154,11 @@ func runStart(cmd *cobra.Command, args []string) {
 		networkOld = newMessengerOld(privKey, peerSeedsOld, portOld, ctx)
 	}
 
+	mempoolJournalPath := ""
+	if viper.GetBool(common.CfgMempoolJournalEnabled) {
+		mempoolJournalPath = path.Join(dbPath, "mempool_journal")
+	}
+
 	params := &node.Params{
 		ChainID:             root.ChainID,
 		PrivateKey:          privKey,
@@ -146,46 +170,157 @@ func runStart(cmd *cobra.Command, args []string) {
 		SnapshotPath:        snapshotPath,
 		ChainImportDirPath:  chainImportDirPath,
 		ChainCorrectionPath: chainCorrectionPath,
+		MempoolJournalPath:  mempoolJournalPath,
+	}
+
+	if remoteSignerAddress := viper.GetString(common.CfgSignerRemoteAddress); remoteSignerAddress != "" {
+		params.Signer = newRemoteSigner(remoteSignerAddress)
+	} else {
+		var validatorSigner core.Signer = core.NewLocalSigner(privKey)
+		if keyProviderSigner := newKeyProviderSigner(); keyProviderSigner != nil {
+			validatorSigner = keyProviderSigner
+		}
+		if viper.GetBool(common.CfgConsensusSignStateEnabled) {
+			validatorSigner = newProtectedSigner(validatorSigner, dbPath)
+		}
+		params.Signer = validatorSigner
 	}
 
 	n := node.NewNode(params)
 
-	c := make(chan os.Signal)
-	signal.Notify(c, os.Interrupt)
+	// On SIGINT/SIGTERM, stop the proposer and the other sub components, and wait for the in-flight
+	// block commit to complete before closing the databases, so that they are not left corrupted.
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	var doneOnce sync.Once
 	done := make(chan struct{})
+	closeDone := func() {
+		doneOnce.Do(func() { close(done) })
+	}
 	go func() {
-		<-c
+		sig := <-c
 		signal.Stop(c)
+		log.Infof("Received %v, shutting down...", sig)
 		cancel()
-		network.Stop()
-		// Wait at most 5 seconds before forcefully shutting down.
-		<-time.After(time.Duration(5) * time.Second)
-		close(done)
+		if network != nil {
+			network.Stop()
+		}
+		closeDone()
 	}()
 
 	n.Start(ctx)
 
+	// On SIGHUP, reload the non-critical parameters (e.g. the log levels and the peer limits) from
+	// the config file, since restarting a validator risks missing votes
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reloaded, err := common.ReloadConfig()
+			if err != nil {
+				log.Errorf("Failed to reload config: %v", err)
+			}
+			if reloaded != nil {
+				log.Infof("Reloaded config, applied: %v", reloaded.Applied)
+				if len(reloaded.RestartRequired) > 0 {
+					log.Warnf("Config keys changed but only applied on restart: %v", reloaded.RestartRequired)
+				}
+			}
+		}
+	}()
+
 	if viper.GetBool(common.CfgProfEnabled) {
 		go func() {
 			log.Println(http.ListenAndServe("localhost:6060", nil))
 		}()
 	}
 
+	if prometheusAddress != "" {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", prometheus.Handler(metrics.DefaultRegistry))
+			log.Infof("Serving the Prometheus metrics at %v/metrics", prometheusAddress)
+			log.Println(http.ListenAndServe(prometheusAddress, mux))
+		}()
+	}
+
 	if viper.GetBool(common.CfgForceGCEnabled) {
 		go memoryCleanupRoutine()
 	}
 
 	go func() {
 		n.Wait()
-		close(done)
+		closeDone()
 	}()
 
 	<-done
-	log.Infof("")
-	log.Infof("Graceful exit.")
+
+	// Wait for the sub components to stop, at most for the shutdown timeout
+	stopped := make(chan struct{})
+	go func() {
+		n.Wait()
+		close(stopped)
+	}()
+	timeout := time.Duration(viper.GetInt(common.CfgNodeShutdownTimeout)) * time.Second
+	select {
+	case <-stopped:
+		n.Close()
+		log.Infof("")
+		log.Infof("Graceful exit.")
+	case <-time.After(timeout):
+		log.Warnf("Sub components did not stop in %v, exiting without closing the databases", timeout)
+	}
 	printExitBanner()
 }
 
+func newRemoteSigner(address string) *signer.RemoteSigner {
+	tlsConfig, err := signer.NewTLSConfig(viper.GetString(common.CfgSignerTLSCertFile),
+		viper.GetString(common.CfgSignerTLSKeyFile), viper.GetString(common.CfgSignerTLSCAFile), false)
+	if err != nil {
+		log.Fatalf("Failed to load remote signer TLS config: %v", err)
+	}
+	timeout := time.Duration(viper.GetInt(common.CfgSignerTimeoutSecs)) * time.Second
+	remoteSigner, err := signer.NewRemoteSigner(address, tlsConfig, timeout)
+	if err != nil {
+		log.Fatalf("Failed to connect to remote signer %v: %v", address, err)
+	}
+	log.Infof("Using remote signer %v, validator address: %v", address, remoteSigner.PublicKey().Address().Hex())
+	return remoteSigner
+}
+
+// newKeyProviderSigner returns the signer backed by the key provider in the config file, or nil
+// if no key provider is configured.
+func newKeyProviderSigner() *signer.KeyProviderSigner {
+	provider, err := kms.NewKeyProviderFromConfig()
+	if err != nil {
+		log.Fatalf("Failed to create key provider: %v", err)
+	}
+	if provider == nil {
+		return nil
+	}
+	keyProviderSigner, err := signer.NewKeyProviderSigner(provider)
+	if err != nil {
+		log.Fatalf("Failed to get the public key from the key provider: %v", err)
+	}
+	log.Infof("Using key provider %v, validator address: %v", viper.GetString(common.CfgKeyProvider),
+		keyProviderSigner.PublicKey().Address().Hex())
+	return keyProviderSigner
+}
+
+func newProtectedSigner(validatorSigner core.Signer, dataPath string) *signer.ProtectedSigner {
+	statePath := viper.GetString(common.CfgConsensusSignStatePath)
+	if statePath == "" {
+		statePath = path.Join(dataPath, "sign_state.json")
+	}
+	state, err := signer.LoadSignState(statePath)
+	if err != nil {
+		log.Fatalf("Failed to load sign state %v: %v", statePath, err)
+	}
+	log.Infof("Double-sign protection enabled, last signed vote height: %v, proposal epoch: %v",
+		state.VoteHeight, state.ProposalEpoch)
+	return signer.NewProtectedSigner(validatorSigner, state)
+}
+
 func loadOrCreateKey() (*crypto.PrivateKey, error) {
 	keyPath := viper.GetString(common.CfgKeyPath)
 	if keyPath == "" {
@@ -193,7 +328,7 @@ func loadOrCreateKey() (*crypto.PrivateKey, error) {
 	}
 
 	keysDir := path.Join(keyPath, "key")
-	keystore, err := ks.NewKeystoreEncrypted(keysDir, ks.StandardScryptN, ks.StandardScryptP)
+	keystore, err := ks.NewKeystoreEncryptedWithKDF(keysDir, ks.KDFParamsFromConfig())
 	if err != nil {
 		log.Fatalf("Failed to create key store: %v", err)
 	}
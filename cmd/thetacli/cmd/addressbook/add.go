@@ -0,0 +1,51 @@
+package addressbook
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// addCmd adds an alias to the address book
+// Example:
+//		thetacli addressbook add --name=alice --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var addCmd = &cobra.Command{
+	Use:     "add",
+	Short:   "Add an alias",
+	Long:    `Add an alias for an address to the address book.`,
+	Example: `thetacli addressbook add --name=alice --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doAddCmd,
+}
+
+func doAddCmd(cmd *cobra.Command, args []string) {
+	cfgPath := cmd.Flag("config").Value.String()
+	if err := utils.ValidateAlias(nameFlag); err != nil {
+		utils.Error("%v\n", err)
+	}
+	address, err := utils.ParseAddress(addressFlag)
+	if err != nil {
+		utils.Error("%v: %v\n", err, addressFlag)
+	}
+
+	book, err := utils.LoadAddressBook(cfgPath)
+	if err != nil {
+		utils.Error("Failed to load the address book: %v\n", err)
+	}
+	if existing, ok := book[nameFlag]; ok {
+		utils.Error("Alias %v already exists for address %v, please remove it first\n", nameFlag, existing.Hex())
+	}
+
+	book[nameFlag] = address
+	if err := book.Save(cfgPath); err != nil {
+		utils.Error("Failed to save the address book: %v\n", err)
+	}
+	fmt.Printf("Added alias %v for address %v\n", nameFlag, address.Hex())
+}
+
+func init() {
+	addCmd.Flags().StringVar(&nameFlag, "name", "", "Alias of the address")
+	addCmd.Flags().StringVar(&addressFlag, "address", "", "The address")
+	addCmd.MarkFlagRequired("name")
+	addCmd.MarkFlagRequired("address")
+}
@@ -0,0 +1,32 @@
+package addressbook
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// listCmd lists the aliases in the address book
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List all aliases",
+	Long:    `List all aliases in the address book.`,
+	Example: "thetacli addressbook list",
+	Run: func(cmd *cobra.Command, args []string) {
+		book, err := utils.LoadAddressBook(cmd.Flag("config").Value.String())
+		if err != nil {
+			utils.Error("Failed to load the address book: %v\n", err)
+		}
+
+		names := []string{}
+		for name := range book {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s\t%s\n", name, book[name].Hex())
+		}
+	},
+}
@@ -0,0 +1,25 @@
+package addressbook
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Common flags used in addressbook sub commands.
+var (
+	nameFlag    string
+	addressFlag string
+)
+
+// AddressBookCmd represents the addressbook command
+var AddressBookCmd = &cobra.Command{
+	Use:   "addressbook",
+	Short: "Manage address aliases",
+	Long: `Manage address aliases. An alias stored in the address book can be used
+anywhere an address flag is expected, e.g. thetacli tx send --to=alice.`,
+}
+
+func init() {
+	AddressBookCmd.AddCommand(addCmd)
+	AddressBookCmd.AddCommand(listCmd)
+	AddressBookCmd.AddCommand(removeCmd)
+}
@@ -0,0 +1,42 @@
+package addressbook
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// removeCmd removes an alias from the address book
+// Example:
+//		thetacli addressbook remove --name=alice
+var removeCmd = &cobra.Command{
+	Use:     "remove",
+	Short:   "Remove an alias",
+	Long:    `Remove an alias from the address book.`,
+	Example: `thetacli addressbook remove --name=alice`,
+	Run:     doRemoveCmd,
+}
+
+func doRemoveCmd(cmd *cobra.Command, args []string) {
+	cfgPath := cmd.Flag("config").Value.String()
+	book, err := utils.LoadAddressBook(cfgPath)
+	if err != nil {
+		utils.Error("Failed to load the address book: %v\n", err)
+	}
+	address, ok := book[nameFlag]
+	if !ok {
+		utils.Error("Alias %v not found\n", nameFlag)
+	}
+
+	delete(book, nameFlag)
+	if err := book.Save(cfgPath); err != nil {
+		utils.Error("Failed to save the address book: %v\n", err)
+	}
+	fmt.Printf("Removed alias %v for address %v\n", nameFlag, address.Hex())
+}
+
+func init() {
+	removeCmd.Flags().StringVar(&nameFlag, "name", "", "Alias to remove")
+	removeCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,20 @@
+package admin
+
+import "github.com/spf13/cobra"
+
+var (
+	addressFlag  string
+	peerIDFlag   string
+	durationFlag uint64
+)
+
+// AdminCmd represents the admin command
+var AdminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Manage the node (requires rpc.adminEnabled on the node)",
+	Long:  `Manage the node (requires rpc.adminEnabled on the node).`,
+}
+
+func init() {
+	AdminCmd.AddCommand(peerCmd)
+}
@@ -0,0 +1,100 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// peerCmd represents the peer management command
+var peerCmd = &cobra.Command{
+	Use:   "peer",
+	Short: "Manage peers",
+	Long:  `Manage peers.`,
+}
+
+// peerAddCmd represents the peer add command.
+// Example:
+//		thetacli admin peer add --address=127.0.0.1:12000
+var peerAddCmd = &cobra.Command{
+	Use:     "add",
+	Short:   "Connect to a peer",
+	Long:    `Connect to a peer. The address is host:port for the legacy p2p network, or a multiaddress for libp2p.`,
+	Example: `thetacli admin peer add --address=127.0.0.1:12000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		callAdminRPC("theta.AddPeer", rpc.AddPeerArgs{
+			Address: addressFlag,
+		})
+	},
+}
+
+// peerRemoveCmd represents the peer remove command.
+// Example:
+//		thetacli admin peer remove --peer_id=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var peerRemoveCmd = &cobra.Command{
+	Use:     "remove",
+	Short:   "Disconnect from a peer",
+	Long:    `Disconnect from a peer.`,
+	Example: `thetacli admin peer remove --peer_id=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run: func(cmd *cobra.Command, args []string) {
+		callAdminRPC("theta.RemovePeer", rpc.RemovePeerArgs{
+			PeerID: peerIDFlag,
+		})
+	},
+}
+
+// peerBanCmd represents the peer ban command.
+// Example:
+//		thetacli admin peer ban --peer_id=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --duration=3600
+var peerBanCmd = &cobra.Command{
+	Use:     "ban",
+	Short:   "Disconnect from a peer and reject its connections for a while",
+	Long:    `Disconnect from a peer and reject its connections for a while.`,
+	Example: `thetacli admin peer ban --peer_id=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --duration=3600`,
+	Run: func(cmd *cobra.Command, args []string) {
+		callAdminRPC("theta.BanPeer", rpc.BanPeerArgs{
+			PeerID:          peerIDFlag,
+			DurationSeconds: common.JSONUint64(durationFlag),
+		})
+	},
+}
+
+func callAdminRPC(method string, args interface{}) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call(method, args)
+	if err != nil {
+		utils.Error("Failed to call %v: %v\n", method, err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to execute %v: %v\n", method, res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	peerAddCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the peer")
+	peerAddCmd.MarkFlagRequired("address")
+
+	peerRemoveCmd.Flags().StringVar(&peerIDFlag, "peer_id", "", "ID of the peer")
+	peerRemoveCmd.MarkFlagRequired("peer_id")
+
+	peerBanCmd.Flags().StringVar(&peerIDFlag, "peer_id", "", "ID of the peer")
+	peerBanCmd.Flags().Uint64Var(&durationFlag, "duration", 3600, "Ban duration in seconds")
+	peerBanCmd.MarkFlagRequired("peer_id")
+
+	peerCmd.AddCommand(peerAddCmd)
+	peerCmd.AddCommand(peerRemoveCmd)
+	peerCmd.AddCommand(peerBanCmd)
+}
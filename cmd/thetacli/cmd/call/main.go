@@ -15,14 +15,22 @@ var (
 	gasLimitFlag uint64
 	dataFlag     string
 	verboseFlag  bool
+	paramsFlag   string
 )
 
-// CallCmd represents the call command
+// CallCmd represents the call command. With a method name instead of a sub command, it sends the
+// raw JSON-RPC request of the method to the node.
 var CallCmd = &cobra.Command{
-	Use:   "call",
-	Short: "Call smart contract APIs",
+	Use:   "call [method]",
+	Short: "Call smart contract APIs, or any RPC method",
+	Example: `thetacli call smart_contract --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=0x7ad6cea2bc3162e30a3c98d84f821b3233c22647 --gas_price=3 --gas_limit=50000
+thetacli call theta.GetAccount --params='{"address":"0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}'`,
+	Args: cobra.ExactArgs(1),
+	Run:  doRawCallCmd,
 }
 
 func init() {
+	CallCmd.Flags().StringVar(&paramsFlag, "params", "{}", "the JSON params of the RPC method, an object or an array of positional params")
+
 	CallCmd.AddCommand(smartContractCmd)
 }
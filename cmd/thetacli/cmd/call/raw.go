@@ -0,0 +1,51 @@
+package call
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// doRawCallCmd sends the JSON-RPC request of the method to the node as-is, so that the RPC methods
+// without a dedicated sub command can be called. The method is in the theta namespace unless
+// specified otherwise.
+// Examples:
+//		thetacli call GetStatus
+//		thetacli call theta.GetAccount --params='{"address":"0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}'
+func doRawCallCmd(cmd *cobra.Command, args []string) {
+	method := args[0]
+	if !strings.Contains(method, ".") {
+		method = "theta." + method
+	}
+
+	var params interface{}
+	if err := json.Unmarshal([]byte(paramsFlag), &params); err != nil {
+		utils.Error("Failed to parse the params: %v\n", err)
+	}
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	var res *rpcc.RPCResponse
+	var err error
+	if positional, ok := params.([]interface{}); ok {
+		res, err = client.Call(method, positional...)
+	} else {
+		res, err = client.Call(method, params)
+	}
+	if err != nil {
+		utils.Error("Failed to call %v: %v\n", method, err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to call %v: %v\n", method, res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
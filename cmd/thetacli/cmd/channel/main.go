@@ -0,0 +1,34 @@
+package channel
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Common flags used in Channel sub commands.
+var (
+	chainIDFlag    string
+	fromFlag       string
+	toFlag         string
+	seqFlag        uint64
+	resourceIDFlag string
+	reserveSeqFlag uint64
+	paymentSeqFlag uint64
+	amountFlag     string
+	paymentFlag    string
+	feeFlag        string
+	passwordFlag   string
+	asyncFlag      bool
+)
+
+// ChannelCmd represents the channel command
+var ChannelCmd = &cobra.Command{
+	Use:   "channel",
+	Short: "Off-chain micropayments",
+	Long: `Off-chain micropayments. The payer first reserves a fund with "thetacli tx reserve", and then pays the payee
+off-chain with "thetacli channel pay". The payee settles the latest payment on-chain with "thetacli channel settle".`,
+}
+
+func init() {
+	ChannelCmd.AddCommand(payCmd)
+	ChannelCmd.AddCommand(settleCmd)
+}
@@ -0,0 +1,79 @@
+package channel
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/tx"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/payment"
+)
+
+// payCmd represents the pay command, which signs an off-chain payment by the payer
+// Example:
+//		thetacli channel pay --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --resource_id=die_another_day --reserve_seq=6 --payment_seq=1 --amount=20
+var payCmd = &cobra.Command{
+	Use:   "pay",
+	Short: "Sign an off-chain payment",
+	Long: `Sign an off-chain payment by the payer. The amount is accumulated within a payment sequence, i.e. it should
+include the amount of the previous payments with the same payment sequence. The signed payment is printed in hex,
+and should be sent to the payee.`,
+	Example: `thetacli channel pay --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --resource_id=die_another_day --reserve_seq=6 --payment_seq=1 --amount=20`,
+	Run:     doPayCmd,
+}
+
+func doPayCmd(cmd *cobra.Command, args []string) {
+	if len(toFlag) == 0 {
+		utils.Error("The payee address cannot be empty")
+	}
+
+	wallet, fromAddress, err := tx.SoftWalletUnlock(cmd.Flag("config").Value.String(), fromFlag, passwordFlag)
+	if err != nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	amount, ok := types.ParseCoinAmount(amountFlag)
+	if !ok || amount.Sign() <= 0 {
+		utils.Error("Failed to parse amount")
+	}
+
+	id := payment.ChannelID{
+		ChainID:         chainIDFlag,
+		Source:          fromAddress,
+		Target:          common.HexToAddress(toFlag),
+		ResourceID:      resourceIDFlag,
+		ReserveSequence: reserveSeqFlag,
+	}
+	paymentTx := payment.NewServicePaymentTx(id, paymentSeqFlag, amount)
+	if err := payment.SignAsSource(chainIDFlag, paymentTx, wallet); err != nil {
+		utils.Error("Failed to sign payment: %v\n", err)
+	}
+
+	raw, err := types.TxToBytes(paymentTx)
+	if err != nil {
+		utils.Error("Failed to encode payment: %v\n", err)
+	}
+	fmt.Printf("Signed payment:\n%s\n", hex.EncodeToString(raw))
+}
+
+func init() {
+	payCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	payCmd.Flags().StringVar(&fromFlag, "from", "", "Payer's address, i.e. the owner of the reserved fund")
+	payCmd.Flags().StringVar(&toFlag, "to", "", "Payee's address")
+	payCmd.Flags().StringVar(&resourceIDFlag, "resource_id", "", "Resource ID of the payment")
+	payCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 0, "Reserve sequence of the reserved fund")
+	payCmd.Flags().Uint64Var(&paymentSeqFlag, "payment_seq", 1, "Payment sequence, increased by 1 after each settlement")
+	payCmd.Flags().StringVar(&amountFlag, "amount", "0", "Accumulated TFuel amount of the payment sequence")
+	payCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
+
+	payCmd.MarkFlagRequired("chain")
+	payCmd.MarkFlagRequired("from")
+	payCmd.MarkFlagRequired("to")
+	payCmd.MarkFlagRequired("resource_id")
+	payCmd.MarkFlagRequired("reserve_seq")
+	payCmd.MarkFlagRequired("amount")
+}
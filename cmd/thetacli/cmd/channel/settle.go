@@ -0,0 +1,116 @@
+package channel
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/tx"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/payment"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// settleCmd represents the settle command, which signs the latest off-chain payment by the payee and broadcasts it
+// Example:
+//		thetacli channel settle --chain="privatenet" --from=9F1233798E905E173560071255140b4A8aBd3Ec6 --seq=3 --payment=<signed payment>
+var settleCmd = &cobra.Command{
+	Use:     "settle",
+	Short:   "Settle an off-chain payment",
+	Long:    `Settle the latest off-chain payment signed by the payer. The fee is paid by the payee.`,
+	Example: `thetacli channel settle --chain="privatenet" --from=9F1233798E905E173560071255140b4A8aBd3Ec6 --seq=3 --payment=<signed payment>`,
+	Run:     doSettleCmd,
+}
+
+func doSettleCmd(cmd *cobra.Command, args []string) {
+	raw, err := hex.DecodeString(strings.TrimPrefix(paymentFlag, "0x"))
+	if err != nil {
+		utils.Error("Failed to decode payment: %v\n", err)
+	}
+	decoded, err := types.TxFromBytes(raw)
+	if err != nil {
+		utils.Error("Failed to decode payment: %v\n", err)
+	}
+	paymentTx, ok := decoded.(*types.ServicePaymentTx)
+	if !ok {
+		utils.Error("Not a service payment\n")
+	}
+	if !payment.VerifySourceSignature(chainIDFlag, paymentTx) {
+		utils.Error("%v\n", payment.ErrInvalidSignature)
+	}
+
+	wallet, fromAddress, err := tx.SoftWalletUnlock(cmd.Flag("config").Value.String(), fromFlag, passwordFlag)
+	if err != nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	if paymentTx.Target.Address != fromAddress {
+		utils.Error("The payment is made to %v\n", paymentTx.Target.Address.Hex())
+	}
+
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+	txFee := types.Coins{
+		ThetaWei: new(big.Int).SetUint64(0),
+		TFuelWei: fee,
+	}
+	if err := payment.SignAsTarget(chainIDFlag, paymentTx, txFee, seqFlag, wallet); err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+
+	raw, err = types.TxToBytes(paymentTx)
+	if err != nil {
+		utils.Error("Failed to encode transaction: %v\n", err)
+	}
+	signedTx := hex.EncodeToString(raw)
+
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	var res *rpcc.RPCResponse
+	if asyncFlag {
+		res, err = client.Call("theta.BroadcastRawTransactionAsync", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	} else {
+		res, err = client.Call("theta.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	}
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Server returned error: %v\n", res.Error)
+	}
+	result := &rpc.BroadcastRawTransactionResult{}
+	err = res.GetObject(result)
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	settleCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	settleCmd.Flags().StringVar(&fromFlag, "from", "", "Payee's address")
+	settleCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	settleCmd.Flags().StringVar(&paymentFlag, "payment", "", "Payment signed by the payer, in hex")
+	settleCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
+	settleCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	settleCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
+
+	settleCmd.MarkFlagRequired("chain")
+	settleCmd.MarkFlagRequired("from")
+	settleCmd.MarkFlagRequired("seq")
+	settleCmd.MarkFlagRequired("payment")
+}
@@ -0,0 +1,64 @@
+package key
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	ks "github.com/thetatoken/theta/wallet/softwallet/keystore"
+)
+
+// exportCmd exports a key as a key file in the Ethereum keystore format
+var exportCmd = &cobra.Command{
+	Use:     "export",
+	Short:   "Export a key as a key file in the Ethereum keystore (V3) format",
+	Long:    `Export a key as a key file in the Ethereum keystore (V3) format, which can be imported by the Ethereum tools, e.g. geth. The key file is written to the given directory, or the current directory by default.`,
+	Example: "thetacli key export 2E833968E5bB786Ae419c4d13189fB081Cc43bab /tmp/keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			utils.Error("Usage: thetacli key export <address> [output dir]\n")
+		}
+		address, err := utils.ParseAddress(args[0])
+		if err != nil {
+			utils.Error("Invalid address: %v\n", err)
+		}
+		outputDir := "."
+		if len(args) > 1 {
+			outputDir = args[1]
+		}
+
+		cfgPath := cmd.Flag("config").Value.String()
+		keystore, err := ks.NewKeystoreEncryptedWithKDF(path.Join(cfgPath, "keys"), ks.KDFParamsFromConfig())
+		if err != nil {
+			utils.Error("Failed to open keystore: %v\n", err)
+		}
+
+		prompt := fmt.Sprintf("Please enter password: ")
+		password, err := utils.GetPassword(prompt)
+		if err != nil {
+			utils.Error("Failed to get password: %v\n", err)
+		}
+		key, err := keystore.GetKey(address, password)
+		if err != nil {
+			utils.Error("Failed to unlock key: %v\n", err)
+		}
+
+		keyjson, err := ks.ExportKey(key, password)
+		if err != nil {
+			utils.Error("Failed to encrypt key: %v\n", err)
+		}
+		filePath := path.Join(outputDir, ks.ExportKeyFileName(key.Address))
+		f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			utils.Error("Failed to create key file: %v\n", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(keyjson); err != nil {
+			utils.Error("Failed to write key file: %v\n", err)
+		}
+
+		fmt.Printf("Successfully exported key to %v\n", filePath)
+	},
+}
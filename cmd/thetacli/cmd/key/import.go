@@ -0,0 +1,59 @@
+package key
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	ks "github.com/thetatoken/theta/wallet/softwallet/keystore"
+)
+
+// importCmd imports a key file in the Ethereum keystore format
+var importCmd = &cobra.Command{
+	Use:     "import",
+	Short:   "Import a key file in the Ethereum keystore (V3) format",
+	Long:    `Import a key file in the Ethereum keystore (V3) format, e.g. one created by geth. The key is re-encrypted with the KDF set in the config.`,
+	Example: "thetacli key import UTC--2019-10-01T12-34-56.789000000Z--2e833968e5bb786ae419c4d13189fb081cc43bab",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			utils.Error("Usage: thetacli key import <key file>\n")
+		}
+		keyjson, err := ioutil.ReadFile(args[0])
+		if err != nil {
+			utils.Error("Failed to read key file: %v\n", err)
+		}
+
+		prompt := fmt.Sprintf("Please enter the password of the key file: ")
+		password, err := utils.GetPassword(prompt)
+		if err != nil {
+			utils.Error("Failed to get password: %v\n", err)
+		}
+
+		key, err := ks.ImportKey(keyjson, password)
+		if err != nil {
+			utils.Error("Failed to decrypt key file: %v\n", err)
+		}
+
+		cfgPath := cmd.Flag("config").Value.String()
+		keystore, err := ks.NewKeystoreEncryptedWithKDF(path.Join(cfgPath, "keys"), ks.KDFParamsFromConfig())
+		if err != nil {
+			utils.Error("Failed to open keystore: %v\n", err)
+		}
+		addresses, err := keystore.ListKeyAddresses()
+		if err != nil {
+			utils.Error("Failed to list keys: %v\n", err)
+		}
+		for _, address := range addresses {
+			if address == key.Address {
+				utils.Error("Key %v already exists\n", key.Address.Hex())
+			}
+		}
+		if err := keystore.StoreKey(key, password); err != nil {
+			utils.Error("Failed to store key: %v\n", err)
+		}
+
+		fmt.Printf("Successfully imported key: %v\n", key.Address.Hex())
+	},
+}
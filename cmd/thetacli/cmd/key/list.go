@@ -28,7 +28,17 @@ var listCmd = &cobra.Command{
 		}
 
 		for _, keyAddress := range keyAddresses {
-			fmt.Printf("%s\n", keyAddress.Hex())
+			if bech32Flag {
+				fmt.Printf("%s\t%s\n", keyAddress.Hex(), keyAddress.Bech32())
+			} else {
+				fmt.Printf("%s\n", keyAddress.Hex())
+			}
 		}
 	},
 }
+
+var bech32Flag bool
+
+func init() {
+	listCmd.Flags().BoolVar(&bech32Flag, "bech32", false, "Also display the addresses in the checksummed bech32 encoding")
+}
@@ -0,0 +1,62 @@
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// assembleCmd represents the assemble command, which merges the copies of a multisig transaction signed in parallel
+// Example:
+//		thetacli multisig assemble --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0 --txs=<transaction1>,<transaction2> --broadcast
+var assembleCmd = &cobra.Command{
+	Use:     "assemble",
+	Short:   "Assemble the partial signatures of a multisig transaction",
+	Long:    `Assemble the partial signatures of the copies of a multisig transaction, which are signed by different signers.`,
+	Example: `thetacli multisig assemble --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0 --txs=<transaction1>,<transaction2> --broadcast`,
+	Run:     doAssembleCmd,
+}
+
+func doAssembleCmd(cmd *cobra.Command, args []string) {
+	if len(txsFlag) == 0 {
+		utils.Error("No transaction to assemble")
+	}
+	keySet := parseKeySet(cmd.Flag("config").Value.String())
+
+	multisigTx := decodeTx(txsFlag[0])
+	ms := multisigSignature(multisigTx, keySet)
+	signBytes := multisigTx.SignBytes("")
+	for _, txHex := range txsFlag[1:] {
+		other := decodeTx(txHex)
+		if string(other.SignBytes("")) != string(signBytes) {
+			utils.Error("The transactions to assemble are different\n")
+		}
+		if err := ms.Merge(multisigSignature(other, keySet)); err != nil {
+			utils.Error("%v\n", err)
+		}
+	}
+	setMultisigSignature(multisigTx, ms)
+
+	signedTx := encodeTx(multisigTx)
+	fmt.Printf("Signed by %v of %v signers (%v required):\n%s\n", ms.NumSigned(), len(keySet.Signers), keySet.Threshold, signedTx)
+
+	if broadcastFlag {
+		if uint64(ms.NumSigned()) < keySet.Threshold {
+			utils.Error("Not enough signatures to broadcast\n")
+		}
+		broadcast(signedTx)
+	}
+}
+
+func init() {
+	assembleCmd.Flags().Uint64Var(&thresholdFlag, "threshold", 0, "Number of signatures required")
+	assembleCmd.Flags().StringSliceVar(&signersFlag, "signers", []string{}, "Addresses of the signers")
+	assembleCmd.Flags().StringSliceVar(&txsFlag, "txs", []string{}, "Copies of the transaction signed by different signers")
+	assembleCmd.Flags().BoolVar(&broadcastFlag, "broadcast", false, "Broadcast the assembled transaction")
+	assembleCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+
+	assembleCmd.MarkFlagRequired("threshold")
+	assembleCmd.MarkFlagRequired("signers")
+	assembleCmd.MarkFlagRequired("txs")
+}
@@ -0,0 +1,38 @@
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// createCmd represents the create command, which derives the address of a multisig account from its key set
+// Example:
+//		thetacli multisig create --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a multisig account",
+	Long: `Create a M-of-N multisig account. The address is derived from the threshold and the signers, so
+no transaction is needed, and the same threshold and signers should be provided to sign the transactions.`,
+	Example: `thetacli multisig create --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0`,
+	Run:     doCreateCmd,
+}
+
+func doCreateCmd(cmd *cobra.Command, args []string) {
+	keySet := parseKeySet(cmd.Flag("config").Value.String())
+
+	fmt.Printf("Multisig address: %v\n", keySet.Address().Hex())
+	fmt.Printf("Threshold: %v\n", keySet.Threshold)
+	fmt.Printf("Signers:\n")
+	for _, signer := range keySet.Signers {
+		fmt.Printf("    %v\n", signer.Hex())
+	}
+}
+
+func init() {
+	createCmd.Flags().Uint64Var(&thresholdFlag, "threshold", 0, "Number of signatures required")
+	createCmd.Flags().StringSliceVar(&signersFlag, "signers", []string{}, "Addresses of the signers")
+
+	createCmd.MarkFlagRequired("threshold")
+	createCmd.MarkFlagRequired("signers")
+}
@@ -0,0 +1,42 @@
+package multisig
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Common flags used in Multisig sub commands.
+var (
+	chainIDFlag     string
+	fromFlag        string
+	toFlag          string
+	holderFlag      string
+	seqFlag         uint64
+	thetaAmountFlag string
+	tfuelAmountFlag string
+	feeFlag         string
+	purposeFlag     uint8
+	thresholdFlag   uint64
+	signersFlag     []string
+	txFlag          string
+	txsFlag         []string
+	passwordFlag    string
+	broadcastFlag   bool
+	asyncFlag       bool
+)
+
+// MultisigCmd represents the multisig command
+var MultisigCmd = &cobra.Command{
+	Use:   "multisig",
+	Short: "Manage M-of-N multisig accounts",
+	Long: `Manage M-of-N multisig accounts. A multisig transaction is first built unsigned, e.g. with "thetacli multisig send".
+The signers then sign it one after another with "thetacli multisig sign", or sign copies of it in parallel and merge the
+copies with "thetacli multisig assemble". Once signed by enough signers, the transaction can be broadcasted.`,
+}
+
+func init() {
+	MultisigCmd.AddCommand(createCmd)
+	MultisigCmd.AddCommand(sendCmd)
+	MultisigCmd.AddCommand(withdrawStakeCmd)
+	MultisigCmd.AddCommand(signCmd)
+	MultisigCmd.AddCommand(assembleCmd)
+}
@@ -0,0 +1,76 @@
+package multisig
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// sendCmd represents the send command, which builds an unsigned SendTx from a multisig account
+// Example:
+//		thetacli multisig send --from=0x6c5F7B4f5b3B26e2e0D4C4B5e1B8Ee1dA3f1bd20 --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --theta=10 --tfuel=9 --seq=1
+var sendCmd = &cobra.Command{
+	Use:     "send",
+	Short:   "Build an unsigned transaction to send tokens from a multisig account",
+	Example: `thetacli multisig send --from=0x6c5F7B4f5b3B26e2e0D4C4B5e1B8Ee1dA3f1bd20 --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --theta=10 --tfuel=9 --seq=1`,
+	Run:     doSendCmd,
+}
+
+func doSendCmd(cmd *cobra.Command, args []string) {
+	if fromFlag == toFlag {
+		utils.Error("The from and to address cannot be identical")
+	}
+
+	theta, ok := types.ParseCoinAmount(thetaAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse theta amount")
+	}
+	tfuel, ok := types.ParseCoinAmount(tfuelAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse tfuel amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+	sendTx := &types.SendTx{
+		Fee: types.Coins{
+			ThetaWei: new(big.Int).SetUint64(0),
+			TFuelWei: fee,
+		},
+		Inputs: []types.TxInput{{
+			Address: common.HexToAddress(fromFlag),
+			Coins: types.Coins{
+				TFuelWei: new(big.Int).Add(tfuel, fee),
+				ThetaWei: theta,
+			},
+			Sequence: uint64(seqFlag),
+		}},
+		Outputs: []types.TxOutput{{
+			Address: common.HexToAddress(toFlag),
+			Coins: types.Coins{
+				TFuelWei: tfuel,
+				ThetaWei: theta,
+			},
+		}},
+	}
+
+	fmt.Printf("Unsigned transaction:\n%s\n", encodeTx(sendTx))
+}
+
+func init() {
+	sendCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the multisig account")
+	sendCmd.Flags().StringVar(&toFlag, "to", "", "Address to send to")
+	sendCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	sendCmd.Flags().StringVar(&thetaAmountFlag, "theta", "0", "Theta amount")
+	sendCmd.Flags().StringVar(&tfuelAmountFlag, "tfuel", "0", "TFuel amount")
+	sendCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
+
+	sendCmd.MarkFlagRequired("from")
+	sendCmd.MarkFlagRequired("to")
+	sendCmd.MarkFlagRequired("seq")
+}
@@ -0,0 +1,66 @@
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/tx"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+)
+
+// signCmd represents the sign command, which adds the partial signature of a signer to a multisig transaction
+// Example:
+//		thetacli multisig sign --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0 --tx=<transaction>
+var signCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a multisig transaction",
+	Long: `Sign a multisig transaction as one of the signers. The transaction can be unsigned, or partially signed
+by the other signers. The transaction is broadcasted with --broadcast, once it is signed by enough signers.`,
+	Example: `thetacli multisig sign --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --threshold=2 --signers=2E833968E5bB786Ae419c4d13189fB081Cc43bab,9F1233798E905E173560071255140b4A8aBd3Ec6,70f587259738cB626A1720Af7038B8DcDb6a42a0 --tx=<transaction>`,
+	Run:     doSignCmd,
+}
+
+func doSignCmd(cmd *cobra.Command, args []string) {
+	keySet := parseKeySet(cmd.Flag("config").Value.String())
+	multisigTx := decodeTx(txFlag)
+	ms := multisigSignature(multisigTx, keySet)
+
+	wallet, signerAddress, err := tx.SoftWalletUnlock(cmd.Flag("config").Value.String(), fromFlag, passwordFlag)
+	if err != nil {
+		return
+	}
+	defer wallet.Lock(signerAddress)
+
+	sig, err := wallet.Sign(signerAddress, multisigTx.SignBytes(chainIDFlag))
+	if err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+	if err := ms.AddSignature(signerAddress, sig); err != nil {
+		utils.Error("%v\n", err)
+	}
+	setMultisigSignature(multisigTx, ms)
+
+	signedTx := encodeTx(multisigTx)
+	fmt.Printf("Signed by %v of %v signers (%v required):\n%s\n", ms.NumSigned(), len(keySet.Signers), keySet.Threshold, signedTx)
+
+	if broadcastFlag {
+		broadcast(signedTx)
+	}
+}
+
+func init() {
+	signCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	signCmd.Flags().StringVar(&fromFlag, "from", "", "Address of the signer")
+	signCmd.Flags().Uint64Var(&thresholdFlag, "threshold", 0, "Number of signatures required")
+	signCmd.Flags().StringSliceVar(&signersFlag, "signers", []string{}, "Addresses of the signers")
+	signCmd.Flags().StringVar(&txFlag, "tx", "", "Unsigned or partially signed transaction")
+	signCmd.Flags().BoolVar(&broadcastFlag, "broadcast", false, "Broadcast the transaction after signing")
+	signCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	signCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
+
+	signCmd.MarkFlagRequired("chain")
+	signCmd.MarkFlagRequired("from")
+	signCmd.MarkFlagRequired("threshold")
+	signCmd.MarkFlagRequired("signers")
+	signCmd.MarkFlagRequired("tx")
+}
@@ -0,0 +1,137 @@
+package multisig
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+func parseKeySet(cfgPath string) *types.MultisigKeySet {
+	signers := []common.Address{}
+	for _, signer := range utils.ResolveAddresses(cfgPath, signersFlag) {
+		signers = append(signers, common.HexToAddress(signer))
+	}
+	keySet, err := types.NewMultisigKeySet(thresholdFlag, signers)
+	if err != nil {
+		utils.Error("Invalid key set: %v\n", err)
+	}
+	return keySet
+}
+
+func decodeTx(txHex string) types.Tx {
+	raw, err := hex.DecodeString(strings.TrimPrefix(txHex, "0x"))
+	if err != nil {
+		utils.Error("Failed to decode transaction: %v\n", err)
+	}
+	tx, err := types.TxFromBytes(raw)
+	if err != nil {
+		utils.Error("Failed to decode transaction: %v\n", err)
+	}
+	return tx
+}
+
+func encodeTx(tx types.Tx) string {
+	raw, err := types.TxToBytes(tx)
+	if err != nil {
+		utils.Error("Failed to encode transaction: %v\n", err)
+	}
+	return hex.EncodeToString(raw)
+}
+
+// inputSignature returns the signature of the tx input from the given address
+func inputSignature(tx types.Tx, addr common.Address) (*crypto.Signature, bool) {
+	var inputs []types.TxInput
+	switch tx := tx.(type) {
+	case *types.SendTx:
+		inputs = tx.Inputs
+	case *types.ReserveFundTx:
+		inputs = []types.TxInput{tx.Source}
+	case *types.ReleaseFundTx:
+		inputs = []types.TxInput{tx.Source}
+	case *types.SplitRuleTx:
+		inputs = []types.TxInput{tx.Initiator}
+	case *types.DepositStakeTxV2:
+		inputs = []types.TxInput{tx.Source}
+	case *types.WithdrawStakeTx:
+		inputs = []types.TxInput{tx.Source}
+	case *types.StakeRewardDistributionTx:
+		inputs = []types.TxInput{tx.Holder}
+	case *types.VestingTx:
+		inputs = []types.TxInput{tx.Source}
+	case *types.RotateValidatorKeyTx:
+		inputs = []types.TxInput{tx.Holder}
+	}
+	for _, input := range inputs {
+		if input.Address == addr {
+			return input.Signature, true
+		}
+	}
+	return nil, false
+}
+
+// multisigSignature returns the multisig signature collected so far by the tx
+func multisigSignature(tx types.Tx, keySet *types.MultisigKeySet) *types.MultisigSignature {
+	sig, ok := inputSignature(tx, keySet.Address())
+	if !ok {
+		utils.Error("The transaction is not sent from the multisig account %v\n", keySet.Address().Hex())
+	}
+	if sig == nil || sig.IsEmpty() {
+		return types.NewMultisigSignature(keySet)
+	}
+	ms, err := types.MultisigSignatureFromSignature(sig)
+	if err != nil {
+		utils.Error("Failed to decode the multisig signature: %v\n", err)
+	}
+	return ms
+}
+
+func setMultisigSignature(tx types.Tx, ms *types.MultisigSignature) {
+	sig, err := ms.ToSignature()
+	if err != nil {
+		utils.Error("Failed to encode the multisig signature: %v\n", err)
+	}
+	signable, ok := tx.(interface {
+		SetSignature(addr common.Address, sig *crypto.Signature) bool
+	})
+	if !ok || !signable.SetSignature(ms.KeySet.Address(), sig) {
+		utils.Error("Failed to set the multisig signature\n")
+	}
+}
+
+func broadcast(signedTx string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	var res *rpcc.RPCResponse
+	var err error
+	if asyncFlag {
+		res, err = client.Call("theta.BroadcastRawTransactionAsync", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	} else {
+		res, err = client.Call("theta.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	}
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Server returned error: %v\n", res.Error)
+	}
+	result := &rpc.BroadcastRawTransactionResult{}
+	err = res.GetObject(result)
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
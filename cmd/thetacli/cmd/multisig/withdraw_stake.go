@@ -0,0 +1,58 @@
+package multisig
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// withdrawStakeCmd represents the withdraw stake command, which builds an unsigned WithdrawStakeTx for
+// the stake deposited by a multisig account
+// Example:
+//		thetacli multisig withdraw_stake --source=0x6c5F7B4f5b3B26e2e0D4C4B5e1B8Ee1dA3f1bd20 --holder=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --purpose=0 --seq=2
+var withdrawStakeCmd = &cobra.Command{
+	Use:     "withdraw_stake",
+	Short:   "Build an unsigned transaction to withdraw the stake of a multisig account",
+	Example: `thetacli multisig withdraw_stake --source=0x6c5F7B4f5b3B26e2e0D4C4B5e1B8Ee1dA3f1bd20 --holder=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --purpose=0 --seq=2`,
+	Run:     doWithdrawStakeCmd,
+}
+
+func doWithdrawStakeCmd(cmd *cobra.Command, args []string) {
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+
+	withdrawStakeTx := &types.WithdrawStakeTx{
+		Fee: types.Coins{
+			ThetaWei: new(big.Int).SetUint64(0),
+			TFuelWei: fee,
+		},
+		Source: types.TxInput{
+			Address:  common.HexToAddress(fromFlag),
+			Sequence: uint64(seqFlag),
+		},
+		Holder: types.TxOutput{
+			Address: common.HexToAddress(holderFlag),
+		},
+		Purpose: purposeFlag,
+	}
+
+	fmt.Printf("Unsigned transaction:\n%s\n", encodeTx(withdrawStakeTx))
+}
+
+func init() {
+	withdrawStakeCmd.Flags().StringVar(&fromFlag, "source", "", "Address of the multisig account, i.e. the source of the stake")
+	withdrawStakeCmd.Flags().StringVar(&holderFlag, "holder", "", "Holder of the stake")
+	withdrawStakeCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
+	withdrawStakeCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	withdrawStakeCmd.Flags().Uint8Var(&purposeFlag, "purpose", 0, "Purpose of staking")
+
+	withdrawStakeCmd.MarkFlagRequired("source")
+	withdrawStakeCmd.MarkFlagRequired("holder")
+	withdrawStakeCmd.MarkFlagRequired("seq")
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// chainStatsCmd represents the chain_stats command.
+// Example:
+//		thetacli query chain_stats --windows=100,1000
+var chainStatsCmd = &cobra.Command{
+	Use:     "chain_stats",
+	Short:   "Get the rolling averages of the block interval, transactions, gas and fees",
+	Example: `thetacli query chain_stats --windows=100,1000`,
+	Run:     doChainStatsCmd,
+}
+
+func doChainStatsCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	windows := []common.JSONUint64{}
+	for _, window := range windowsFlag {
+		windows = append(windows, common.JSONUint64(window))
+	}
+	res, err := client.Call("theta.GetChainStats", rpc.GetChainStatsArgs{Windows: windows})
+	if err != nil {
+		utils.Error("Failed to get chain stats: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get chain stats: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	chainStatsCmd.Flags().UintSliceVar(&windowsFlag, "windows", []uint{}, "Windows in blocks, the server defaults if empty")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/rpc"
 
 	rpcc "github.com/ybbus/jsonrpc"
@@ -15,7 +16,7 @@ import (
 
 // eenpCmd represents the eenp command.
 // Example:
-//		thetacli query eenp --height=10
+//		thetacli query eenp --height=10 --offset=100 --limit=50
 var eenpCmd = &cobra.Command{
 	Use:     "eenp",
 	Short:   "Get elite edge node pool",
@@ -27,13 +28,37 @@ func doEenpCmd(cmd *cobra.Command, args []string) {
 	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
 
 	height := heightFlag
-	res, err := client.Call("theta.GetEenpByHeight", rpc.GetEenpByHeightArgs{Height: common.JSONUint64(height)})
+	res, err := client.Call("theta.GetEenpByHeight", rpc.GetEenpByHeightArgs{
+		Height:    common.JSONUint64(height),
+		Holder:    addressFlag,
+		Source:    sourceFlag,
+		WithStake: withStakeFlag,
+		Offset:    common.JSONUint64(offsetFlag),
+		Limit:     common.JSONUint64(limitFlag),
+	})
 	if err != nil {
 		utils.Error("Failed to get elite edge node pool: %v\n", err)
 	}
 	if res.Error != nil {
 		utils.Error("Failed to get elite edge node pool: %v\n", res.Error)
 	}
+	if summaryFlag || csvFlag != "" {
+		result := &rpc.GetEenpResult{}
+		if err := res.GetObject(result); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+		pools := []stakePool{}
+		for _, pair := range result.BlockHashEenpPairs {
+			pool := stakePool{BlockHash: pair.BlockHash, Holders: []*core.StakeHolder{}}
+			for _, een := range pair.EENs {
+				pool.Holders = append(pool.Holders, een.StakeHolder)
+			}
+			pools = append(pools, pool)
+		}
+		printPools(pools)
+		return
+	}
+
 	json, err := json.MarshalIndent(res.Result, "", "    ")
 	if err != nil {
 		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
@@ -43,5 +68,12 @@ func doEenpCmd(cmd *cobra.Command, args []string) {
 
 func init() {
 	eenpCmd.Flags().Uint64Var(&heightFlag, "height", uint64(0), "height of the block")
+	eenpCmd.Flags().StringVar(&addressFlag, "holder", "", "only get the elite edge node of the holder")
+	eenpCmd.Flags().StringVar(&sourceFlag, "source", "", "only get the elite edge nodes with stakes from the source")
+	eenpCmd.Flags().BoolVar(&withStakeFlag, "with_stake", false, "only get the elite edge nodes with non-withdrawn stakes")
+	eenpCmd.Flags().Uint64Var(&offsetFlag, "offset", uint64(0), "number of the elite edge nodes to skip")
+	eenpCmd.Flags().Uint64Var(&limitFlag, "limit", uint64(0), "max number of the elite edge nodes to get, 0 for all")
+	eenpCmd.Flags().BoolVar(&summaryFlag, "summary", false, "print the counts and the total, min, max and median stakes instead of the pool")
+	eenpCmd.Flags().StringVar(&csvFlag, "csv", "", "write the stakes to the CSV file")
 	eenpCmd.MarkFlagRequired("height")
 }
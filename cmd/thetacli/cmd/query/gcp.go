@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/rpc"
 
 	rpcc "github.com/ybbus/jsonrpc"
@@ -34,6 +35,25 @@ func doGcpCmd(cmd *cobra.Command, args []string) {
 	if res.Error != nil {
 		utils.Error("Failed to get guardian candidate pool: %v\n", res.Error)
 	}
+	if summaryFlag || csvFlag != "" {
+		result := &rpc.GetGcpResult{}
+		if err := res.GetObject(result); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+		pools := []stakePool{}
+		for _, pair := range result.BlockHashGcpPairs {
+			pool := stakePool{BlockHash: pair.BlockHash, Holders: []*core.StakeHolder{}}
+			if pair.Gcp != nil {
+				for _, g := range pair.Gcp.SortedGuardians {
+					pool.Holders = append(pool.Holders, g.StakeHolder)
+				}
+			}
+			pools = append(pools, pool)
+		}
+		printPools(pools)
+		return
+	}
+
 	json, err := json.MarshalIndent(res.Result, "", "    ")
 	if err != nil {
 		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
@@ -43,5 +63,7 @@ func doGcpCmd(cmd *cobra.Command, args []string) {
 
 func init() {
 	gcpCmd.Flags().Uint64Var(&heightFlag, "height", uint64(0), "height of the block")
+	gcpCmd.Flags().BoolVar(&summaryFlag, "summary", false, "print the counts and the total, min, max and median stakes instead of the pool")
+	gcpCmd.Flags().StringVar(&csvFlag, "csv", "", "write the stakes to the CSV file")
 	gcpCmd.MarkFlagRequired("height")
 }
@@ -15,6 +15,16 @@ var (
 	endFlag              uint64
 	skipEdgeNodeFlag     bool
 	includeEthTxHashFlag bool
+	epochFlag            uint64
+	countFlag            uint64
+	circulatingFlag      bool
+	sourceFlag           string
+	withStakeFlag        bool
+	offsetFlag           uint64
+	limitFlag            uint64
+	summaryFlag          bool
+	csvFlag              string
+	windowsFlag          []uint
 )
 
 // QueryCmd represents the query command
@@ -30,11 +40,18 @@ func init() {
 	QueryCmd.AddCommand(blockCmd)
 	QueryCmd.AddCommand(txCmd)
 	QueryCmd.AddCommand(splitRuleCmd)
+	QueryCmd.AddCommand(reserveFundsCmd)
+	QueryCmd.AddCommand(vestingCmd)
 	QueryCmd.AddCommand(vcpCmd)
+	QueryCmd.AddCommand(proposerScheduleCmd)
 	QueryCmd.AddCommand(gcpCmd)
 	QueryCmd.AddCommand(eenpCmd)
 	QueryCmd.AddCommand(srdrsCmd)
 	QueryCmd.AddCommand(stakeReturnsCmd)
+	QueryCmd.AddCommand(stakeWithdrawalsCmd)
 	QueryCmd.AddCommand(peersCmd)
 	QueryCmd.AddCommand(versionCmd)
+	QueryCmd.AddCommand(supplyCmd)
+	QueryCmd.AddCommand(rewardsCmd)
+	QueryCmd.AddCommand(chainStatsCmd)
 }
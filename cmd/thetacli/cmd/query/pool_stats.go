@@ -0,0 +1,125 @@
+package query
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+// stakePool is the stake holders of a validator, guardian or elite edge node pool at a block
+type stakePool struct {
+	BlockHash common.Hash
+	Holders   []*core.StakeHolder
+}
+
+// poolSummary is the statistics of the non-withdrawn stakes of a pool
+type poolSummary struct {
+	BlockHash   common.Hash     `json:"block_hash"`
+	NumHolders  int             `json:"num_holders"`
+	NumStakes   int             `json:"num_stakes"`
+	TotalStake  *common.JSONBig `json:"total_stake"`
+	MinStake    *common.JSONBig `json:"min_stake"`
+	MaxStake    *common.JSONBig `json:"max_stake"`
+	MedianStake *common.JSONBig `json:"median_stake"`
+}
+
+// summarizePool computes the statistics of the holders' total non-withdrawn stakes. The holders
+// without any non-withdrawn stake are not counted.
+func summarizePool(pool stakePool) poolSummary {
+	totals := []*big.Int{}
+	totalStake := new(big.Int)
+	numStakes := 0
+	for _, holder := range pool.Holders {
+		total := holder.TotalStake()
+		if total.Sign() == 0 {
+			continue
+		}
+		for _, stake := range holder.Stakes {
+			if !stake.Withdrawn {
+				numStakes++
+			}
+		}
+		totals = append(totals, total)
+		totalStake.Add(totalStake, total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Cmp(totals[j]) < 0 })
+
+	summary := poolSummary{
+		BlockHash:   pool.BlockHash,
+		NumHolders:  len(totals),
+		NumStakes:   numStakes,
+		TotalStake:  (*common.JSONBig)(totalStake),
+		MinStake:    (*common.JSONBig)(big.NewInt(0)),
+		MaxStake:    (*common.JSONBig)(big.NewInt(0)),
+		MedianStake: (*common.JSONBig)(big.NewInt(0)),
+	}
+	if n := len(totals); n > 0 {
+		summary.MinStake = (*common.JSONBig)(totals[0])
+		summary.MaxStake = (*common.JSONBig)(totals[n-1])
+		median := new(big.Int).Set(totals[n/2])
+		if n%2 == 0 {
+			median.Add(median, totals[n/2-1])
+			median.Div(median, big.NewInt(2))
+		}
+		summary.MedianStake = (*common.JSONBig)(median)
+	}
+	return summary
+}
+
+// writePoolsCSV writes one row per stake of the pools to the file
+func writePoolsCSV(filePath string, pools []stakePool) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		utils.Error("Failed to create %v: %v\n", filePath, err)
+	}
+	defer file.Close()
+
+	w := csv.NewWriter(file)
+	w.Write([]string{"block_hash", "holder", "source", "amount", "withdrawn", "return_height"})
+	for _, pool := range pools {
+		for _, holder := range pool.Holders {
+			for _, stake := range holder.Stakes {
+				w.Write([]string{
+					pool.BlockHash.Hex(),
+					holder.Holder.Hex(),
+					stake.Source.Hex(),
+					stake.Amount.String(),
+					strconv.FormatBool(stake.Withdrawn),
+					strconv.FormatUint(stake.ReturnHeight, 10),
+				})
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		utils.Error("Failed to write %v: %v\n", filePath, err)
+	}
+	fmt.Printf("Stakes written to %v\n", filePath)
+}
+
+// printPools writes the pools to the CSV file if --csv is set, and prints their summaries if
+// --summary is set
+func printPools(pools []stakePool) {
+	if csvFlag != "" {
+		writePoolsCSV(csvFlag, pools)
+	}
+	if summaryFlag {
+		summaries := []poolSummary{}
+		for _, pool := range pools {
+			summaries = append(summaries, summarizePool(pool))
+		}
+		json, err := json.MarshalIndent(summaries, "", "    ")
+		if err != nil {
+			utils.Error("Failed to format the summaries: %v\n", err)
+		}
+		fmt.Println(string(json))
+	}
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// proposerScheduleCmd represents the proposer schedule command.
+// Example:
+//		thetacli query proposer_schedule --epoch=1000 --count=20
+var proposerScheduleCmd = &cobra.Command{
+	Use:     "proposer_schedule",
+	Short:   "Get the expected proposers of the upcoming epochs",
+	Example: `thetacli query proposer_schedule --epoch=1000 --count=20`,
+	Run:     doProposerScheduleCmd,
+}
+
+func doProposerScheduleCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("theta.GetProposerSchedule", rpc.GetProposerScheduleArgs{
+		Epoch: common.JSONUint64(epochFlag),
+		Count: common.JSONUint64(countFlag),
+	})
+	if err != nil {
+		utils.Error("Failed to get proposer schedule: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get proposer schedule: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	proposerScheduleCmd.Flags().Uint64Var(&epochFlag, "epoch", uint64(0), "first epoch of the schedule, defaults to the current epoch")
+	proposerScheduleCmd.Flags().Uint64Var(&countFlag, "count", uint64(0), "number of epochs, defaults to 100")
+}
@@ -0,0 +1,49 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// reserveFundsCmd represents the reserve_funds command.
+// Example:
+//		thetacli query reserve_funds --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var reserveFundsCmd = &cobra.Command{
+	Use:     "reserve_funds",
+	Short:   "Get the reserved funds of an account",
+	Long:    `Get the funds reserved by an account for the off-chain micropayments.`,
+	Example: `thetacli query reserve_funds --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doReserveFundsCmd,
+}
+
+func doReserveFundsCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("theta.GetReserveFunds", rpc.GetReserveFundsArgs{
+		Address: addressFlag,
+		Preview: previewFlag})
+	if err != nil {
+		utils.Error("Failed to get reserved funds: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get reserved funds: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	reserveFundsCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the account")
+	reserveFundsCmd.Flags().BoolVar(&previewFlag, "preview", false, "Preview the reserved funds from the screened view")
+	reserveFundsCmd.MarkFlagRequired("address")
+}
@@ -0,0 +1,52 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// rewardsCmd represents the rewards command.
+// Example:
+//		thetacli query rewards --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --start=1000000 --end=2000000
+var rewardsCmd = &cobra.Command{
+	Use:     "rewards",
+	Short:   "Get the rewards paid to an address in a height range",
+	Example: `thetacli query rewards --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab --start=1000000 --end=2000000`,
+	Run:     doRewardsCmd,
+}
+
+func doRewardsCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("theta.GetRewardsByAddress", rpc.GetRewardsByAddressArgs{
+		Address:     addressFlag,
+		StartHeight: common.JSONUint64(startFlag),
+		EndHeight:   common.JSONUint64(endFlag),
+	})
+	if err != nil {
+		utils.Error("Failed to get rewards: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get rewards: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	rewardsCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the rewarded account")
+	rewardsCmd.Flags().Uint64Var(&startFlag, "start", 0, "Start height")
+	rewardsCmd.Flags().Uint64Var(&endFlag, "end", 0, "End height, the last finalized block if zero")
+	rewardsCmd.MarkFlagRequired("address")
+}
@@ -0,0 +1,45 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// stakeWithdrawalsCmd represents the stake_withdrawals command.
+// Example:
+//		thetacli query stake_withdrawals --source=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var stakeWithdrawalsCmd = &cobra.Command{
+	Use:     "stake_withdrawals",
+	Short:   "Get the pending stake withdrawals of a source address and when they return",
+	Example: `thetacli query stake_withdrawals --source=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doStakeWithdrawalsCmd,
+}
+
+func doStakeWithdrawalsCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("theta.GetPendingStakeWithdrawals", rpc.GetPendingStakeWithdrawalsArgs{Source: sourceFlag})
+	if err != nil {
+		utils.Error("Failed to get pending stake withdrawals: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get pending stake withdrawals: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	stakeWithdrawalsCmd.Flags().StringVar(&sourceFlag, "source", "", "address of the stake source")
+	stakeWithdrawalsCmd.MarkFlagRequired("source")
+}
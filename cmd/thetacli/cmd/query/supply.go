@@ -0,0 +1,50 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// supplyCmd represents the supply command.
+// Example:
+//		thetacli query supply --circulating
+var supplyCmd = &cobra.Command{
+	Use:     "supply",
+	Short:   "Get the total or circulating supply of Theta and TFuel",
+	Example: `thetacli query supply --circulating`,
+	Run:     doSupplyCmd,
+}
+
+func doSupplyCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	var res *rpcc.RPCResponse
+	var err error
+	if circulatingFlag {
+		res, err = client.Call("theta.GetCirculatingSupply", rpc.GetCirculatingSupplyArgs{})
+	} else {
+		res, err = client.Call("theta.GetTotalSupply", rpc.GetTotalSupplyArgs{})
+	}
+	if err != nil {
+		utils.Error("Failed to get supply: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get supply: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	supplyCmd.Flags().BoolVar(&circulatingFlag, "circulating", false, "Get the circulating supply instead of the total supply")
+}
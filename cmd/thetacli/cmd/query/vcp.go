@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/rpc"
 
 	rpcc "github.com/ybbus/jsonrpc"
@@ -34,6 +35,23 @@ func doVcpCmd(cmd *cobra.Command, args []string) {
 	if res.Error != nil {
 		utils.Error("Failed to get validator candidate pool: %v\n", res.Error)
 	}
+	if summaryFlag || csvFlag != "" {
+		result := &rpc.GetVcpResult{}
+		if err := res.GetObject(result); err != nil {
+			utils.Error("Failed to parse server response: %v\n", err)
+		}
+		pools := []stakePool{}
+		for _, pair := range result.BlockHashVcpPairs {
+			pool := stakePool{BlockHash: pair.BlockHash, Holders: []*core.StakeHolder{}}
+			if pair.Vcp != nil {
+				pool.Holders = pair.Vcp.SortedCandidates
+			}
+			pools = append(pools, pool)
+		}
+		printPools(pools)
+		return
+	}
+
 	json, err := json.MarshalIndent(res.Result, "", "    ")
 	if err != nil {
 		utils.Error("Failed to parse server response: %v\n%s\n", err, string(json))
@@ -43,5 +61,7 @@ func doVcpCmd(cmd *cobra.Command, args []string) {
 
 func init() {
 	vcpCmd.Flags().Uint64Var(&heightFlag, "height", uint64(0), "height of the block")
+	vcpCmd.Flags().BoolVar(&summaryFlag, "summary", false, "print the counts and the total, min, max and median stakes instead of the pool")
+	vcpCmd.Flags().StringVar(&csvFlag, "csv", "", "write the stakes to the CSV file")
 	vcpCmd.MarkFlagRequired("height")
 }
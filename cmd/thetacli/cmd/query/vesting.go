@@ -0,0 +1,47 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/rpc"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// vestingCmd represents the vesting command.
+// Example:
+//		thetacli query vesting --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab
+var vestingCmd = &cobra.Command{
+	Use:     "vesting",
+	Short:   "Get the vesting status of an account",
+	Long:    `Get the vesting schedules of an account, and the coins still locked by them.`,
+	Example: `thetacli query vesting --address=0x2E833968E5bB786Ae419c4d13189fB081Cc43bab`,
+	Run:     doVestingCmd,
+}
+
+func doVestingCmd(cmd *cobra.Command, args []string) {
+	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+
+	res, err := client.Call("theta.GetVestingStatus", rpc.GetVestingStatusArgs{
+		Address: addressFlag})
+	if err != nil {
+		utils.Error("Failed to get vesting status: %v\n", err)
+	}
+	if res.Error != nil {
+		utils.Error("Failed to get vesting status: %v\n", res.Error)
+	}
+	json, err := json.MarshalIndent(res.Result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n%v\n", err, string(json))
+	}
+	fmt.Println(string(json))
+}
+
+func init() {
+	vestingCmd.Flags().StringVar(&addressFlag, "address", "", "Address of the account")
+	vestingCmd.MarkFlagRequired("address")
+}
@@ -11,20 +11,29 @@ import (
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/addressbook"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/admin"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/call"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/channel"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/daemon"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/key"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/multisig"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/query"
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/tx"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 )
 
 var cfgPath string
 
+// addressFlagNames are the flags that accept an alias from the address book in place of an address
+var addressFlagNames = []string{"from", "to", "address", "source", "holder", "beneficiary"}
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
-	Use:   "thetacli",
-	Short: "Theta wallet",
-	Long:  `Theta wallet.`,
+	Use:              "thetacli",
+	Short:            "Theta wallet",
+	Long:             `Theta wallet.`,
+	PersistentPreRun: resolveAddressFlags,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -40,13 +49,19 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	RootCmd.PersistentFlags().StringVar(&cfgPath, "config", getDefaultConfigPath(), fmt.Sprintf("config path (default is %s)", getDefaultConfigPath()))
+	RootCmd.PersistentFlags().Bool("strict_checksum", false, "reject the hex addresses without the EIP-55 checksum")
+	viper.BindPFlag(utils.CfgStrictAddressChecksum, RootCmd.PersistentFlags().Lookup("strict_checksum"))
 
 	RootCmd.AddCommand(daemon.DaemonCmd)
 	RootCmd.AddCommand(key.KeyCmd)
 	RootCmd.AddCommand(tx.TxCmd)
 	RootCmd.AddCommand(query.QueryCmd)
 	RootCmd.AddCommand(call.CallCmd)
+	RootCmd.AddCommand(channel.ChannelCmd)
+	RootCmd.AddCommand(multisig.MultisigCmd)
 	RootCmd.AddCommand(backup.BackupCmd)
+	RootCmd.AddCommand(addressbook.AddressBookCmd)
+	RootCmd.AddCommand(admin.AdminCmd)
 	RootCmd.AddCommand(versionCmd)
 }
 
@@ -66,6 +81,23 @@ func initConfig() {
 	}
 }
 
+// resolveAddressFlags replaces the aliases passed to the address flags with the addresses in the address book
+func resolveAddressFlags(cmd *cobra.Command, args []string) {
+	if cmd.Parent() == addressbook.AddressBookCmd {
+		return
+	}
+	for _, name := range addressFlagNames {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || !f.Changed || f.Value.Type() != "string" {
+			continue
+		}
+		resolved := utils.ResolveAddress(cfgPath, f.Value.String())
+		if resolved != f.Value.String() {
+			f.Value.Set(resolved)
+		}
+	}
+}
+
 func getDefaultConfigPath() string {
 	home, err := homedir.Dir()
 	if err != nil {
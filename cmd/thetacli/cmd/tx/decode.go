@@ -0,0 +1,40 @@
+package tx
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/rpc"
+)
+
+// decodeCmd represents the decode command, which decodes the transaction locally without the node
+// Example:
+//		thetacli tx decode 0x02f8...
+var decodeCmd = &cobra.Command{
+	Use:     "decode <hex>",
+	Short:   "Decode a raw transaction",
+	Long:    `Decode a raw transaction, signed or not, into JSON with its type, signers and fee.`,
+	Example: `thetacli tx decode 0x02f8a4c78085e8d4a51000f86ff86d942e833968e5bb786ae419c4d13189fb081cc43babd3888ac7230489e800008901158e46f1e875100015b841...`,
+	Args:    cobra.ExactArgs(1),
+	Run:     doDecodeCmd,
+}
+
+func doDecodeCmd(cmd *cobra.Command, args []string) {
+	txBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(args[0]), "0x"))
+	if err != nil {
+		utils.Error("Failed to decode the hex: %v\n", err)
+	}
+	decoded, err := rpc.DecodeTx(txBytes)
+	if err != nil {
+		utils.Error("Failed to decode the transaction: %v\n", err)
+	}
+	json, err := json.MarshalIndent(decoded, "", "    ")
+	if err != nil {
+		utils.Error("Failed to encode the decoded transaction: %v\n", err)
+	}
+	fmt.Println(string(json))
+}
@@ -4,11 +4,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
-	"strings"
-
-	"github.com/thetatoken/theta/crypto"
-
-	"github.com/thetatoken/theta/crypto/bls"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -81,74 +76,30 @@ func doDepositStakeCmd(cmd *cobra.Command, args []string) {
 
 	// Parse holder flag.
 	var holderAddress common.Address
-	if purposeFlag == core.StakeForValidator {
+	if purposeFlag == core.StakeForValidator && !isGuardianSummary(holderFlag) {
 		if len(holderFlag) != 40 && len(holderFlag) != 42 {
 			utils.Error("holder must be a valid address")
 		}
 		holderAddress = common.HexToAddress(holderFlag)
-	} else if purposeFlag == core.StakeForGuardian {
-		if strings.HasPrefix(holderFlag, "0x") {
-			holderFlag = holderFlag[2:]
-		}
-		if len(holderFlag) != 458 {
-			utils.Error("Holder must be a valid guardian summary")
-		}
-		guardianKeyBytes, err := hex.DecodeString(holderFlag)
-		if err != nil {
-			utils.Error("Failed to decode guardian address: %v\n", err)
-		}
-		holderAddress = common.BytesToAddress(guardianKeyBytes[:20])
-		blsPubkey, err := bls.PublicKeyFromBytes(guardianKeyBytes[20:68])
+	} else if purposeFlag == core.StakeForValidator || purposeFlag == core.StakeForGuardian {
+		// A validator can also pass its guardian summary to register its BLS key for vote aggregation.
+		summary, err := parseNodeSummary(holderFlag, guardianSummaryLen)
 		if err != nil {
-			utils.Error("Failed to decode bls Pubkey: %v\n", err)
+			utils.Error("Holder must be a valid guardian summary: %v\n", err)
 		}
-		blsPop, err := bls.SignatureFromBytes(guardianKeyBytes[68:164])
-		if err != nil {
-			utils.Error("Failed to decode bls POP: %v\n", err)
-		}
-		holderSig, err := crypto.SignatureFromBytes(guardianKeyBytes[164:])
-		if err != nil {
-			utils.Error("Failed to decode signature: %v\n", err)
-		}
-
-		depositStakeTx.BlsPubkey = blsPubkey
-		depositStakeTx.BlsPop = blsPop
-		depositStakeTx.HolderSig = holderSig
+		holderAddress = summary.Address
+		depositStakeTx.BlsPubkey = summary.BlsPubkey
+		depositStakeTx.BlsPop = summary.BlsPop
+		depositStakeTx.HolderSig = summary.HolderSig
 	} else { // purposeFlag == core.StakeForEliteEdgeNode
-		if strings.HasPrefix(holderFlag, "0x") {
-			holderFlag = holderFlag[2:]
-		}
-		if len(holderFlag) != 522 {
-			utils.Error("Holder must be a valid elite edge node summary")
-		}
-		eenSummaryBytes, err := hex.DecodeString(holderFlag)
-		if err != nil {
-			utils.Error("Failed to decode elite edge node summary: %v\n", err)
-		}
-		holderAddress = common.BytesToAddress(eenSummaryBytes[:20])
-		blsPubkey, err := bls.PublicKeyFromBytes(eenSummaryBytes[20:68])
-		if err != nil {
-			utils.Error("Failed to decode bls Pubkey: %v\n", err)
-		}
-		blsPop, err := bls.SignatureFromBytes(eenSummaryBytes[68:164])
+		summary, err := parseNodeSummary(holderFlag, eliteEdgeNodeSummaryLen)
 		if err != nil {
-			utils.Error("Failed to decode bls POP: %v\n", err)
+			utils.Error("Holder must be a valid elite edge node summary: %v\n", err)
 		}
-		holderSig, err := crypto.SignatureFromBytes(eenSummaryBytes[164:229])
-		if err != nil {
-			utils.Error("Failed to decode signature: %v\n", err)
-		}
-
-		expectedSummaryHash := crypto.Keccak256Hash([]byte("0x" + holderFlag[:458])).Hex()
-		summaryHash := hex.EncodeToString(eenSummaryBytes[229:])
-		if expectedSummaryHash[2:] != summaryHash {
-			utils.Error("Failed to verify elite edge node summary: unmatched summary hash - %v vs %v\n",
-				expectedSummaryHash, summaryHash)
-		}
-
-		depositStakeTx.BlsPubkey = blsPubkey
-		depositStakeTx.BlsPop = blsPop
-		depositStakeTx.HolderSig = holderSig
+		holderAddress = summary.Address
+		depositStakeTx.BlsPubkey = summary.BlsPubkey
+		depositStakeTx.BlsPop = summary.BlsPop
+		depositStakeTx.HolderSig = summary.HolderSig
 	}
 
 	depositStakeTx.Holder = types.TxOutput{
@@ -193,7 +144,7 @@ func init() {
 	depositStakeCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	depositStakeCmd.Flags().StringVar(&stakeInThetaFlag, "stake", "5000000", "Theta amount to stake")
 	depositStakeCmd.Flags().Uint8Var(&purposeFlag, "purpose", 0, "Purpose of staking")
-	depositStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
+	depositStakeCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
 	depositStakeCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
 	depositStakeCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
 
@@ -36,6 +36,12 @@ var (
 	beneficiaryFlag              string
 	splitBasisPointFlag          uint64
 	passwordFlag                 string
+	startHeightFlag              uint64
+	cliffHeightFlag              uint64
+	endHeightFlag                uint64
+	signerFlag                   string
+	signerPasswordFlag           string
+	activationHeightFlag         uint64
 )
 
 // TxCmd represents the Tx command
@@ -48,10 +54,13 @@ var TxCmd = &cobra.Command{
 func init() {
 	TxCmd.AddCommand(sendCmd)
 	TxCmd.AddCommand(reserveFundCmd)
-	//TxCmd.AddCommand(releaseFundCmd) // No need for releaseFundCmd since auto-release is already implemented
+	TxCmd.AddCommand(releaseFundCmd) // expired funds are auto-released, this releases a fund as soon as the freeze period ends
 	TxCmd.AddCommand(splitRuleCmd)
 	TxCmd.AddCommand(smartContractCmd)
 	TxCmd.AddCommand(depositStakeCmd)
 	TxCmd.AddCommand(withdrawStakeCmd)
 	TxCmd.AddCommand(stakeRewardDistributionCmd)
+	TxCmd.AddCommand(vestCmd)
+	TxCmd.AddCommand(rotateKeyCmd)
+	TxCmd.AddCommand(decodeCmd)
 }
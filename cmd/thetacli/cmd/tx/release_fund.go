@@ -2,6 +2,7 @@ package tx
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -36,14 +37,14 @@ func doReleaseFundCmd(cmd *cobra.Command, args []string) {
 		Sequence: uint64(seqFlag),
 	}
 
-	tfuel, ok := types.ParseCoinAmount(feeFlag)
+	fee, ok := types.ParseCoinAmount(feeFlag)
 	if !ok {
-		utils.Error("Failed to parse tfuel amount")
+		utils.Error("Failed to parse fee")
 	}
 	releaseFundTx := &types.ReleaseFundTx{
 		Fee: types.Coins{
 			ThetaWei: new(big.Int).SetUint64(0),
-			TFuelWei: tfuel,
+			TFuelWei: fee,
 		},
 		Source:          input,
 		ReserveSequence: reserveSeqFlag,
@@ -75,7 +76,16 @@ func doReleaseFundCmd(cmd *cobra.Command, args []string) {
 	if res.Error != nil {
 		utils.Error("Server returned error: %v\n", res.Error)
 	}
-	fmt.Printf("Successfully broadcasted transaction.\n")
+	result := &rpc.BroadcastRawTransactionResult{}
+	err = res.GetObject(result)
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
 }
 
 func init() {
@@ -84,7 +94,7 @@ func init() {
 	releaseFundCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
 	releaseFundCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
 	releaseFundCmd.Flags().Uint64Var(&reserveSeqFlag, "reserve_seq", 1000, "Reserve sequence")
-	releaseFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
+	releaseFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
 	releaseFundCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
 	releaseFundCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
 
@@ -92,6 +102,4 @@ func init() {
 	releaseFundCmd.MarkFlagRequired("from")
 	releaseFundCmd.MarkFlagRequired("seq")
 	releaseFundCmd.MarkFlagRequired("reserve_seq")
-	releaseFundCmd.MarkFlagRequired("resource_id")
-
 }
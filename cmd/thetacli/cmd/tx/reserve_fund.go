@@ -2,6 +2,7 @@ package tx
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -43,6 +44,9 @@ func doReserveFundCmd(cmd *cobra.Command, args []string) {
 	if !ok {
 		utils.Error("Failed to parse collateral")
 	}
+	if fund.Sign() <= 0 {
+		utils.Error("Invalid input: fund must be positive\n")
+	}
 	input := types.TxInput{
 		Address: fromAddress,
 		Coins: types.Coins{
@@ -100,7 +104,16 @@ func doReserveFundCmd(cmd *cobra.Command, args []string) {
 	if res.Error != nil {
 		utils.Error("Server returned error: %v\n", res.Error)
 	}
-	fmt.Printf("Successfully broadcasted transaction.\n")
+	result := &rpc.BroadcastRawTransactionResult{}
+	err = res.GetObject(result)
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
 }
 
 func init() {
@@ -112,7 +125,7 @@ func init() {
 	reserveFundCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
 	reserveFundCmd.Flags().Uint64Var(&durationFlag, "duration", 1000, "Reserve duration")
 	reserveFundCmd.Flags().StringSliceVar(&resourceIDsFlag, "resource_ids", []string{}, "Reserouce IDs")
-	reserveFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
+	reserveFundCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
 	reserveFundCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
 	reserveFundCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
 
@@ -120,5 +133,5 @@ func init() {
 	reserveFundCmd.MarkFlagRequired("from")
 	reserveFundCmd.MarkFlagRequired("seq")
 	reserveFundCmd.MarkFlagRequired("duration")
-	reserveFundCmd.MarkFlagRequired("resource_id")
+	reserveFundCmd.MarkFlagRequired("resource_ids")
 }
@@ -0,0 +1,113 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto/bls"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/sdk"
+)
+
+// rotateKeyCmd represents the rotate key command
+// Example:
+//		thetacli tx rotate_key --chain="privatenet" --holder=2E833968E5bB786Ae419c4d13189fB081Cc43bab --signer=9F1233798E905E173560071255140b4A8aBd3Ec6 --activation=20000 --seq=8
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate_key",
+	Short: "Rotate the key a validator signs the votes and proposals with",
+	Long: `Rotate the key a validator signs the votes and proposals with, starting from the activation height. The stake stays with the holder.
+Both the holder key and the new signing key need to be in the wallet. The signer can also be the guardian summary of the node running the new key, which registers its BLS key for vote aggregation.`,
+	Example: `thetacli tx rotate_key --chain="privatenet" --holder=2E833968E5bB786Ae419c4d13189fB081Cc43bab --signer=9F1233798E905E173560071255140b4A8aBd3Ec6 --activation=20000 --seq=8`,
+	Run:     doRotateKeyCmd,
+}
+
+func doRotateKeyCmd(cmd *cobra.Command, args []string) {
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+	fees := types.Coins{
+		ThetaWei: new(big.Int).SetUint64(0),
+		TFuelWei: fee,
+	}
+
+	// Parse signer flag, which is either an address or a guardian summary.
+	signerStr := strings.TrimPrefix(signerFlag, "0x")
+	var signingAddress common.Address
+	var blsPubkey *bls.PublicKey
+	var blsPop *bls.Signature
+	if len(signerStr) == 40 {
+		signingAddress = common.HexToAddress(signerStr)
+	} else if isGuardianSummary(signerStr) {
+		summary, err := parseNodeSummary(signerStr, guardianSummaryLen)
+		if err != nil {
+			utils.Error("Failed to parse guardian summary: %v\n", err)
+		}
+		signingAddress = summary.Address
+		blsPubkey = summary.BlsPubkey
+		blsPop = summary.BlsPop
+	} else {
+		utils.Error("Signer must be a valid address or guardian summary")
+	}
+
+	rotateKeyTx := sdk.NewRotateValidatorKeyTx(common.HexToAddress(holderFlag), signingAddress, activationHeightFlag, fees, uint64(seqFlag))
+	rotateKeyTx.BlsPubkey = blsPubkey
+	rotateKeyTx.BlsPop = blsPop
+
+	signerWallet, signerAddress, err := walletUnlock(cmd, signingAddress.Hex(), signerPasswordFlag)
+	if err != nil {
+		return
+	}
+	err = sdk.SignSigningKey(chainIDFlag, rotateKeyTx, signerWallet)
+	signerWallet.Lock(signerAddress)
+	if err != nil {
+		utils.Error("Failed to sign with the new signing key: %v\n", err)
+	}
+
+	wallet, holderAddress, err := walletUnlock(cmd, holderFlag, passwordFlag)
+	if err != nil {
+		return
+	}
+	defer wallet.Lock(holderAddress)
+
+	err = sdk.SignTx(chainIDFlag, rotateKeyTx, wallet)
+	if err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+
+	client := sdk.NewClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	result, err := client.BroadcastTx(rotateKeyTx, asyncFlag)
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	rotateKeyCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	rotateKeyCmd.Flags().StringVar(&holderFlag, "holder", "", "Address of the validator stake holder")
+	rotateKeyCmd.Flags().StringVar(&signerFlag, "signer", "", "Address or guardian summary of the new signing key")
+	rotateKeyCmd.Flags().Uint64Var(&activationHeightFlag, "activation", 0, fmt.Sprintf("Block height when the new key takes effect, at least %v blocks ahead", types.ValidatorKeyRotationMinDelay))
+	rotateKeyCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	rotateKeyCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
+	rotateKeyCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
+	rotateKeyCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	rotateKeyCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the holder key in the wallet")
+	rotateKeyCmd.Flags().StringVar(&signerPasswordFlag, "signer_password", "", "password to unlock the new signing key in the wallet")
+
+	rotateKeyCmd.MarkFlagRequired("chain")
+	rotateKeyCmd.MarkFlagRequired("holder")
+	rotateKeyCmd.MarkFlagRequired("signer")
+	rotateKeyCmd.MarkFlagRequired("activation")
+	rotateKeyCmd.MarkFlagRequired("seq")
+}
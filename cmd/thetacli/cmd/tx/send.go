@@ -1,7 +1,6 @@
 package tx
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -11,11 +10,8 @@ import (
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/ledger/types"
-	"github.com/thetatoken/theta/rpc"
+	"github.com/thetatoken/theta/sdk"
 	wtypes "github.com/thetatoken/theta/wallet/types"
-
-	"github.com/ybbus/jsonrpc"
-	rpcc "github.com/ybbus/jsonrpc"
 )
 
 // sendCmd represents the send command
@@ -64,62 +60,26 @@ func doSendCmd(cmd *cobra.Command, args []string) {
 	if !ok {
 		utils.Error("Failed to parse fee")
 	}
-	inputs := []types.TxInput{{
-		Address: fromAddress,
-		Coins: types.Coins{
-			TFuelWei: new(big.Int).Add(tfuel, fee),
-			ThetaWei: theta,
-		},
-		Sequence: uint64(seqFlag),
-	}}
-	outputs := []types.TxOutput{{
-		Address: common.HexToAddress(toFlag),
-		Coins: types.Coins{
-			TFuelWei: tfuel,
-			ThetaWei: theta,
-		},
-	}}
-	sendTx := &types.SendTx{
-		Fee: types.Coins{
-			ThetaWei: new(big.Int).SetUint64(0),
-			TFuelWei: fee,
-		},
-		Inputs:  inputs,
-		Outputs: outputs,
+	coins := types.Coins{
+		ThetaWei: theta,
+		TFuelWei: tfuel,
 	}
-
-	sig, err := wallet.Sign(fromAddress, sendTx.SignBytes(chainIDFlag))
-	if err != nil {
-		utils.Error("Failed to sign transaction: %v\n", err)
+	fees := types.Coins{
+		ThetaWei: new(big.Int).SetUint64(0),
+		TFuelWei: fee,
 	}
-	sendTx.SetSignature(fromAddress, sig)
+	sendTx := sdk.NewSendTx(fromAddress, common.HexToAddress(toFlag), coins, fees, uint64(seqFlag))
 
-	raw, err := types.TxToBytes(sendTx)
+	err = sdk.SignTx(chainIDFlag, sendTx, wallet)
 	if err != nil {
-		utils.Error("Failed to encode transaction: %v\n", err)
-	}
-	signedTx := hex.EncodeToString(raw)
-
-	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
-
-	var res *jsonrpc.RPCResponse
-	if asyncFlag {
-		res, err = client.Call("theta.BroadcastRawTransactionAsync", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
-	} else {
-		res, err = client.Call("theta.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+		utils.Error("Failed to sign transaction: %v\n", err)
 	}
 
+	client := sdk.NewClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	result, err := client.BroadcastTx(sendTx, asyncFlag)
 	if err != nil {
 		utils.Error("Failed to broadcast transaction: %v\n", err)
 	}
-	if res.Error != nil {
-		utils.Error("Server returned error: %v\n", res.Error)
-	}
-	result := &rpc.BroadcastRawTransactionResult{}
-	err = res.GetObject(result)
-	if err != nil {
-		utils.Error("Failed to parse server response: %v\n", err)
-	}
 	formatted, err := json.MarshalIndent(result, "", "    ")
 	if err != nil {
 		utils.Error("Failed to parse server response: %v\n", err)
@@ -136,7 +96,7 @@ func init() {
 	sendCmd.Flags().StringVar(&thetaAmountFlag, "theta", "0", "Theta amount")
 	sendCmd.Flags().StringVar(&tfuelAmountFlag, "tfuel", "0", "TFuel amount")
 	sendCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
-	sendCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor)")
+	sendCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|trezor|kms)")
 	sendCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
 	sendCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -43,10 +44,11 @@ func doSplitRuleCmd(cmd *cobra.Command, args []string) {
 		return
 	}
 	var splits []types.Split
-	for idx, addressStr := range addressesFlag {
+	addresses := utils.ResolveAddresses(cmd.Flag("config").Value.String(), addressesFlag)
+	for idx, addressStr := range addresses {
 		percentageStr := percentagesFlag[idx]
 
-		address, err := hex.DecodeString(addressStr)
+		address, err := hex.DecodeString(strings.TrimPrefix(addressStr, "0x"))
 		if err != nil {
 			fmt.Println("The address must be a hex string")
 			return
@@ -119,7 +121,7 @@ func init() {
 	splitRuleCmd.Flags().StringSliceVar(&addressesFlag, "addresses", []string{}, "List of addresses participating in the split")
 	splitRuleCmd.Flags().StringSliceVar(&percentagesFlag, "percentages", []string{}, "List of integers (between 0 and 100) representing of percentage of split")
 	splitRuleCmd.Flags().Uint64Var(&durationFlag, "duration", 1000, "Reserve duration")
-	splitRuleCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano)")
+	splitRuleCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
 	splitRuleCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
 	splitRuleCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
 
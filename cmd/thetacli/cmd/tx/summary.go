@@ -0,0 +1,71 @@
+package tx
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
+)
+
+// The guardian summary is the hex encoded address (20 bytes), BLS pubkey (48 bytes), BLS proof of
+// possession (96 bytes) and holder signature (65 bytes) of a guardian node. The elite edge node
+// summary appends the Keccak256 hash (32 bytes) of the hex encoded guardian summary part.
+const (
+	guardianSummaryLen      = 229
+	eliteEdgeNodeSummaryLen = guardianSummaryLen + 32
+)
+
+type nodeSummary struct {
+	Address   common.Address
+	BlsPubkey *bls.PublicKey
+	BlsPop    *bls.Signature
+	HolderSig *crypto.Signature
+	Raw       string // hex encoded summary without the 0x prefix
+}
+
+// isGuardianSummary returns whether the string has the length of a hex encoded guardian summary
+func isGuardianSummary(str string) bool {
+	return len(strings.TrimPrefix(str, "0x")) == 2*guardianSummaryLen
+}
+
+// parseNodeSummary decodes the hex encoded guardian or elite edge node summary of the given length
+// in bytes. The hash of an elite edge node summary is verified.
+func parseNodeSummary(str string, length int) (*nodeSummary, error) {
+	str = strings.TrimPrefix(str, "0x")
+	if len(str) != 2*length {
+		return nil, fmt.Errorf("summary must be %v bytes long", length)
+	}
+	summaryBytes, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode summary: %v", err)
+	}
+	blsPubkey, err := bls.PublicKeyFromBytes(summaryBytes[20:68])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bls Pubkey: %v", err)
+	}
+	blsPop, err := bls.SignatureFromBytes(summaryBytes[68:164])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bls POP: %v", err)
+	}
+	holderSig, err := crypto.SignatureFromBytes(summaryBytes[164:guardianSummaryLen])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %v", err)
+	}
+	if length == eliteEdgeNodeSummaryLen {
+		expectedSummaryHash := crypto.Keccak256Hash([]byte("0x" + str[:2*guardianSummaryLen])).Hex()
+		summaryHash := hex.EncodeToString(summaryBytes[guardianSummaryLen:])
+		if expectedSummaryHash[2:] != summaryHash {
+			return nil, fmt.Errorf("unmatched summary hash - %v vs %v", expectedSummaryHash, summaryHash)
+		}
+	}
+	return &nodeSummary{
+		Address:   common.BytesToAddress(summaryBytes[:20]),
+		BlsPubkey: blsPubkey,
+		BlsPop:    blsPop,
+		HolderSig: holderSig,
+		Raw:       str,
+	}, nil
+}
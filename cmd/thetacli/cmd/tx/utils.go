@@ -30,6 +30,8 @@ func walletUnlockWithPath(cmd *cobra.Command, addressStr string, path string, pa
 	if walletType == wtypes.WalletTypeSoft {
 		cfgPath := cmd.Flag("config").Value.String()
 		wallet, address, err = SoftWalletUnlock(cfgPath, addressStr, password)
+	} else if walletType == wtypes.WalletTypeKMS {
+		wallet, address, err = KMSWalletUnlock(addressStr)
 	} else {
 		derivationPath, err := parseDerivationPath(path, walletType)
 		if err != nil {
@@ -71,6 +73,33 @@ func ColdWalletUnlock(walletType wtypes.WalletType, derivationPath types.Derivat
 	return wallet, address, nil
 }
 
+func KMSWalletUnlock(addressStr string) (wtypes.Wallet, common.Address, error) {
+	wallet, err := wallet.OpenWallet("", wtypes.WalletTypeKMS, true)
+	if err != nil {
+		fmt.Printf("Failed to open wallet: %v\n", err)
+		return nil, common.Address{}, err
+	}
+
+	addresses, err := wallet.List()
+	if err != nil {
+		fmt.Printf("Failed to list wallet addresses: %v\n", err)
+		return nil, common.Address{}, err
+	}
+	address := addresses[0]
+	if addressStr != "" {
+		address = common.HexToAddress(addressStr)
+	}
+	err = wallet.Unlock(address, "", nil)
+	if err != nil {
+		fmt.Printf("Failed to unlock address %v: %v\n", address.Hex(), err)
+		return nil, common.Address{}, err
+	}
+
+	log.Infof("Wallet address: %v", address)
+
+	return wallet, address, nil
+}
+
 func SoftWalletUnlock(cfgPath, addressStr string, password string) (wtypes.Wallet, common.Address, error) {
 	wallet, err := wallet.OpenWallet(cfgPath, wtypes.WalletTypeSoft, true)
 	if err != nil {
@@ -103,6 +132,8 @@ func getWalletType(cmd *cobra.Command) (walletType wtypes.WalletType) {
 		walletType = wtypes.WalletTypeColdNano
 	} else if walletTypeStr == "trezor" {
 		walletType = wtypes.WalletTypeColdTrezor
+	} else if walletTypeStr == "kms" {
+		walletType = wtypes.WalletTypeKMS
 	} else {
 		walletType = wtypes.WalletTypeSoft
 	}
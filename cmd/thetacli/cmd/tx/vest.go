@@ -0,0 +1,111 @@
+package tx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/sdk"
+)
+
+// vestCmd represents the vest command
+// Example:
+//		thetacli tx vest --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --theta=1000 --start=100 --cliff=1000 --end=10000 --seq=1
+var vestCmd = &cobra.Command{
+	Use:     "vest",
+	Short:   "Send tokens locked by a vesting schedule",
+	Long:    `Send tokens locked by a vesting schedule. None of the tokens unlocks before the cliff height, and afterwards they unlock linearly from the start height to the end height.`,
+	Example: `thetacli tx vest --chain="privatenet" --from=2E833968E5bB786Ae419c4d13189fB081Cc43bab --to=9F1233798E905E173560071255140b4A8aBd3Ec6 --theta=1000 --start=100 --cliff=1000 --end=10000 --seq=1`,
+	Run:     doVestCmd,
+}
+
+func doVestCmd(cmd *cobra.Command, args []string) {
+	if fromFlag == toFlag {
+		utils.Error("The from and to address cannot be identical")
+		return
+	}
+
+	wallet, fromAddress, err := walletUnlock(cmd, fromFlag, passwordFlag)
+	if err != nil {
+		return
+	}
+	defer wallet.Lock(fromAddress)
+
+	theta, ok := types.ParseCoinAmount(thetaAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse theta amount")
+	}
+	tfuel, ok := types.ParseCoinAmount(tfuelAmountFlag)
+	if !ok {
+		utils.Error("Failed to parse tfuel amount")
+	}
+	fee, ok := types.ParseCoinAmount(feeFlag)
+	if !ok {
+		utils.Error("Failed to parse fee")
+	}
+	coins := types.Coins{
+		ThetaWei: theta,
+		TFuelWei: tfuel,
+	}
+	if !coins.IsPositive() {
+		utils.Error("Invalid input: vesting amount must be positive\n")
+	}
+
+	fees := types.Coins{
+		ThetaWei: new(big.Int).SetUint64(0),
+		TFuelWei: fee,
+	}
+	schedule := types.VestingSchedule{
+		Coins:       coins,
+		StartHeight: startHeightFlag,
+		CliffHeight: cliffHeightFlag,
+		EndHeight:   endHeightFlag,
+	}
+	if err := schedule.Validate(); err != nil {
+		utils.Error("Invalid vesting schedule: %v\n", err)
+	}
+	vestingTx := sdk.NewVestingTx(fromAddress, common.HexToAddress(toFlag), schedule, fees, uint64(seqFlag))
+
+	err = sdk.SignTx(chainIDFlag, vestingTx, wallet)
+	if err != nil {
+		utils.Error("Failed to sign transaction: %v\n", err)
+	}
+
+	client := sdk.NewClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	result, err := client.BroadcastTx(vestingTx, asyncFlag)
+	if err != nil {
+		utils.Error("Failed to broadcast transaction: %v\n", err)
+	}
+	formatted, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		utils.Error("Failed to parse server response: %v\n", err)
+	}
+	fmt.Printf("Successfully broadcasted transaction:\n%s\n", formatted)
+}
+
+func init() {
+	vestCmd.Flags().StringVar(&chainIDFlag, "chain", "", "Chain ID")
+	vestCmd.Flags().StringVar(&fromFlag, "from", "", "Address to send from")
+	vestCmd.Flags().StringVar(&toFlag, "to", "", "Address of the beneficiary")
+	vestCmd.Flags().Uint64Var(&seqFlag, "seq", 0, "Sequence number of the transaction")
+	vestCmd.Flags().StringVar(&thetaAmountFlag, "theta", "0", "Theta amount to lock")
+	vestCmd.Flags().StringVar(&tfuelAmountFlag, "tfuel", "0", "TFuel amount to lock")
+	vestCmd.Flags().Uint64Var(&startHeightFlag, "start", 0, "Block height when the tokens start to unlock")
+	vestCmd.Flags().Uint64Var(&cliffHeightFlag, "cliff", 0, "Block height before which none of the tokens unlocks")
+	vestCmd.Flags().Uint64Var(&endHeightFlag, "end", 0, "Block height when all the tokens are unlocked")
+	vestCmd.Flags().StringVar(&feeFlag, "fee", fmt.Sprintf("%dwei", types.MinimumTransactionFeeTFuelWeiJune2021), "Fee")
+	vestCmd.Flags().StringVar(&walletFlag, "wallet", "soft", "Wallet type (soft|nano|kms)")
+	vestCmd.Flags().BoolVar(&asyncFlag, "async", false, "block until tx has been included in the blockchain")
+	vestCmd.Flags().StringVar(&passwordFlag, "password", "", "password to unlock the wallet")
+
+	vestCmd.MarkFlagRequired("chain")
+	vestCmd.MarkFlagRequired("from")
+	vestCmd.MarkFlagRequired("to")
+	vestCmd.MarkFlagRequired("seq")
+	vestCmd.MarkFlagRequired("end")
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+)
+
+// AddressBookFileName is the name of the file storing the address book under the config path
+const AddressBookFileName = "addressbook.json"
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_.\-]{0,63}$`)
+
+// AddressBook maps the aliases to the addresses
+type AddressBook map[string]common.Address
+
+// LoadAddressBook loads the address book under the config path. An empty address
+// book is returned if the file does not exist yet.
+func LoadAddressBook(cfgPath string) (AddressBook, error) {
+	book := AddressBook{}
+	raw, err := ioutil.ReadFile(path.Join(cfgPath, AddressBookFileName))
+	if os.IsNotExist(err) {
+		return book, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &book); err != nil {
+		return nil, fmt.Errorf("Failed to parse the address book: %v", err)
+	}
+	return book, nil
+}
+
+// Save writes the address book under the config path
+func (book AddressBook) Save(cfgPath string) error {
+	raw, err := json.MarshalIndent(book, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cfgPath, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(cfgPath, AddressBookFileName), raw, 0600)
+}
+
+// ValidateAlias checks whether the alias can be added to the address book. Aliases
+// must not look like addresses, so an address flag is never ambiguous.
+func ValidateAlias(alias string) error {
+	if common.IsHexAddress(alias) || isBech32Address(alias) || !aliasPattern.MatchString(alias) {
+		return fmt.Errorf("Invalid alias %v: an alias starts with a letter, followed by up to 63 letters, digits, '_', '-' or '.'", alias)
+	}
+	return nil
+}
+
+// ParseAddress parses an address in hex or in the bech32 encoding. Mixed-case hex addresses must
+// carry a valid EIP-55 checksum, and the hex addresses without checksum are rejected in the strict mode.
+func ParseAddress(value string) (common.Address, error) {
+	return common.ParseAddress(value, viper.GetBool(CfgStrictAddressChecksum))
+}
+
+// ResolveAddress returns the address of the alias if the value is an alias in the address
+// book. The resolved address is printed, so the user can confirm the address before the
+// transaction is signed. A hex address is returned as is after its checksum is verified, and
+// a bech32 address is converted to hex. Other values are returned as is.
+func ResolveAddress(cfgPath string, value string) string {
+	if value == "" {
+		return value
+	}
+	if common.IsHexAddress(value) || isBech32Address(value) {
+		address, err := ParseAddress(value)
+		if err != nil {
+			Error("%v: %v\n", err, value)
+		}
+		if isBech32Address(value) {
+			fmt.Printf("Decoded address %v to %v\n", value, address.Hex())
+			return address.Hex()
+		}
+		return value
+	}
+
+	book, err := LoadAddressBook(cfgPath)
+	if err != nil {
+		Error("Failed to load the address book: %v\n", err)
+	}
+	address, ok := book[value]
+	if !ok {
+		return value
+	}
+	fmt.Printf("Resolved alias %v to address %v\n", value, address.Hex())
+	return address.Hex()
+}
+
+// ResolveAddresses resolves the aliases in the given values
+func ResolveAddresses(cfgPath string, values []string) []string {
+	resolved := make([]string, len(values))
+	for i, value := range values {
+		resolved[i] = ResolveAddress(cfgPath, value)
+	}
+	return resolved
+}
+
+func isBech32Address(value string) bool {
+	return strings.HasPrefix(strings.ToLower(value), common.AddressBech32HRP+"1")
+}
@@ -5,9 +5,13 @@ import "github.com/spf13/viper"
 const (
 	CfgRemoteRPCEndpoint = "remoteRPCEndpoint"
 	CfgDebug             = "debug"
+
+	// CfgStrictAddressChecksum sets whether the hex addresses without the EIP-55 checksum are rejected
+	CfgStrictAddressChecksum = "strictAddressChecksum"
 )
 
 func init() {
 	viper.SetDefault(CfgRemoteRPCEndpoint, "http://localhost:16888/rpc")
 	viper.SetDefault(CfgDebug, false)
+	viper.SetDefault(CfgStrictAddressChecksum, false)
 }
@@ -1,19 +1,17 @@
 package rpc
 
 import (
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strconv"
 
 	"github.com/spf13/viper"
-	rpcc "github.com/ybbus/jsonrpc"
 
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/ledger/types"
-	trpc "github.com/thetatoken/theta/rpc"
+	"github.com/thetatoken/theta/sdk"
 )
 
 // ------------------------------- SendTx -----------------------------------
@@ -65,61 +63,26 @@ func (t *ThetaCliRPCService) Send(args *SendArgs, result *SendResult) (err error
 		return fmt.Errorf("The from address %v has not been unlocked yet", from.Hex())
 	}
 
-	inputs := []types.TxInput{{
-		Address: from,
-		Coins: types.Coins{
-			TFuelWei: new(big.Int).Add(tfuelwei, fee),
-			ThetaWei: thetawei,
-		},
-		Sequence: sequence,
-	}}
-	outputs := []types.TxOutput{{
-		Address: to,
-		Coins: types.Coins{
-			TFuelWei: tfuelwei,
-			ThetaWei: thetawei,
-		},
-	}}
-	sendTx := &types.SendTx{
-		Fee: types.Coins{
-			ThetaWei: new(big.Int).SetUint64(0),
-			TFuelWei: fee,
-		},
-		Inputs:  inputs,
-		Outputs: outputs,
+	coins := types.Coins{
+		TFuelWei: tfuelwei,
+		ThetaWei: thetawei,
 	}
+	fees := types.Coins{
+		ThetaWei: new(big.Int).SetUint64(0),
+		TFuelWei: fee,
+	}
+	sendTx := sdk.NewSendTx(from, to, coins, fees, sequence)
 
-	signBytes := sendTx.SignBytes(args.ChainID)
-	sig, err := t.wallet.Sign(from, signBytes)
+	err = sdk.SignTx(args.ChainID, sendTx, t.wallet)
 	if err != nil {
 		utils.Error("Failed to sign transaction: %v\n", err)
 	}
-	sendTx.SetSignature(from, sig)
 
-	raw, err := types.TxToBytes(sendTx)
-	if err != nil {
-		utils.Error("Failed to encode transaction: %v\n", err)
-	}
-	signedTx := hex.EncodeToString(raw)
-
-	client := rpcc.NewRPCClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
-
-	rpcMethod := "theta.BroadcastRawTransaction"
-	if args.Async {
-		rpcMethod = "theta.BroadcastRawTransactionAsync"
-	}
-	res, err := client.Call(rpcMethod, trpc.BroadcastRawTransactionArgs{TxBytes: signedTx})
+	client := sdk.NewClient(viper.GetString(utils.CfgRemoteRPCEndpoint))
+	trpcResult, err := client.BroadcastTx(sendTx, args.Async)
 	if err != nil {
 		return err
 	}
-	if res.Error != nil {
-		return fmt.Errorf("Server returned error: %v", res.Error)
-	}
-	trpcResult := &trpc.BroadcastRawTransactionResult{}
-	err = res.GetObject(trpcResult)
-	if err != nil {
-		return fmt.Errorf("Failed to parse Theta node response: %v", err)
-	}
 
 	result.TxHash = trpcResult.TxHash
 	result.Block = trpcResult.Block
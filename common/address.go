@@ -0,0 +1,163 @@
+package common
+
+import (
+	"errors"
+	"strings"
+)
+
+// AddressBech32HRP is the human-readable part of the bech32 encoded addresses
+const AddressBech32HRP = "theta"
+
+var (
+	ErrInvalidAddress          = errors.New("Invalid address")
+	ErrInvalidAddressChecksum  = errors.New("Invalid address checksum")
+	ErrAddressChecksumRequired = errors.New("Address checksum required, please use the mixed-case EIP-55 form")
+)
+
+// ParseAddress parses an address either in hex, or in the bech32 encoding with the "theta" prefix.
+// A mixed-case hex address must carry a valid EIP-55 checksum. An all-lowercase or all-uppercase hex
+// address carries no checksum, and is rejected only if strict is true.
+func ParseAddress(s string, strict bool) (Address, error) {
+	if strings.HasPrefix(strings.ToLower(s), AddressBech32HRP+"1") {
+		return Bech32ToAddress(s)
+	}
+	if !IsHexAddress(s) {
+		return Address{}, ErrInvalidAddress
+	}
+	addr := HexToAddress(s)
+	if hasHexPrefix(s) {
+		s = s[2:]
+	}
+	if s == strings.ToLower(s) || s == strings.ToUpper(s) {
+		if strict {
+			return Address{}, ErrAddressChecksumRequired
+		}
+		return addr, nil
+	}
+	if s != addr.Hex()[2:] {
+		return Address{}, ErrInvalidAddressChecksum
+	}
+	return addr, nil
+}
+
+// Bech32 returns the bech32 encoding of the address, e.g. for display. Unlike the EIP-55
+// checksum, the bech32 checksum also detects the transcription errors of the digits.
+func (a Address) Bech32() string {
+	data, _ := convertBits(a[:], 8, 5, true)
+	return bech32Encode(AddressBech32HRP, data)
+}
+
+// Bech32ToAddress decodes a bech32 encoded address
+func Bech32ToAddress(s string) (Address, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil {
+		return Address{}, err
+	}
+	if hrp != AddressBech32HRP {
+		return Address{}, ErrInvalidAddress
+	}
+	raw, err := convertBits(data, 5, 8, false)
+	if err != nil || len(raw) != AddressLength {
+		return Address{}, ErrInvalidAddress
+	}
+	return BytesToAddress(raw), nil
+}
+
+// The bech32 encoding, as specified by BIP-173
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32Encode(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+func bech32Decode(s string) (string, []byte, error) {
+	if len(s) > 90 || (s != strings.ToLower(s) && s != strings.ToUpper(s)) {
+		return "", nil, ErrInvalidAddress
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, ErrInvalidAddress
+	}
+	hrp := s[:pos]
+	data := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32Charset, s[i])
+		if d < 0 {
+			return "", nil, ErrInvalidAddress
+		}
+		data = append(data, byte(d))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != 1 {
+		return "", nil, ErrInvalidAddressChecksum
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups the bits of the data, e.g. from 8-bit bytes to 5-bit groups
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<toBits - 1
+	converted := []byte{}
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, ErrInvalidAddress
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			converted = append(converted, byte((acc>>bits)&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			converted = append(converted, byte((acc<<(toBits-bits))&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, ErrInvalidAddress
+	}
+	return converted, nil
+}
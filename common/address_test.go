@@ -0,0 +1,68 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	expected := HexToAddress(checksummed)
+
+	addr, err := ParseAddress(checksummed, true)
+	assert.Nil(err)
+	assert.Equal(expected, addr)
+
+	// Addresses without checksum are only accepted in the non-strict mode
+	addr, err = ParseAddress(strings.ToLower(checksummed), false)
+	assert.Nil(err)
+	assert.Equal(expected, addr)
+	_, err = ParseAddress(strings.ToLower(checksummed), true)
+	assert.Equal(ErrAddressChecksumRequired, err)
+	_, err = ParseAddress("0x"+strings.ToUpper(checksummed[2:]), true)
+	assert.Equal(ErrAddressChecksumRequired, err)
+
+	// Mixed-case addresses with a wrong checksum are always rejected
+	_, err = ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false)
+	assert.Equal(ErrInvalidAddressChecksum, err)
+
+	_, err = ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false)
+	assert.Equal(ErrInvalidAddress, err)
+}
+
+func TestAddressBech32(t *testing.T) {
+	assert := assert.New(t)
+
+	addr := HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	encoded := addr.Bech32()
+	assert.True(strings.HasPrefix(encoded, "theta1"))
+
+	decoded, err := Bech32ToAddress(encoded)
+	assert.Nil(err)
+	assert.Equal(addr, decoded)
+
+	decoded, err = ParseAddress(strings.ToUpper(encoded), true)
+	assert.Nil(err)
+	assert.Equal(addr, decoded)
+
+	// A single transcription error is detected by the checksum
+	corrupted := []byte(encoded)
+	if corrupted[10] == 'q' {
+		corrupted[10] = 'p'
+	} else {
+		corrupted[10] = 'q'
+	}
+	_, err = Bech32ToAddress(string(corrupted))
+	assert.Equal(ErrInvalidAddressChecksum, err)
+
+	// Test vector of BIP-173
+	hrp, data, err := bech32Decode("A12UEL5L")
+	assert.Nil(err)
+	assert.Equal("a", hrp)
+	assert.Equal(0, len(data))
+	assert.Equal("a12uel5l", bech32Encode("a", []byte{}))
+}
@@ -13,9 +13,49 @@ const (
 
 	// CfgKeyPath defines custom key path
 	CfgKeyPath = "key.path"
+	// CfgKeyKDF defines the key derivation function the new keys are encrypted with, "scrypt" or "argon2id".
+	// Only the scrypt encrypted keys can be imported by the Ethereum tools.
+	CfgKeyKDF = "key.kdf"
+	// CfgKeyScryptN defines the CPU/memory cost parameter of scrypt.
+	CfgKeyScryptN = "key.scryptN"
+	// CfgKeyScryptP defines the parallelization parameter of scrypt.
+	CfgKeyScryptP = "key.scryptP"
+	// CfgKeyArgon2Time defines the number of passes of Argon2id.
+	CfgKeyArgon2Time = "key.argon2Time"
+	// CfgKeyArgon2MemoryKB defines the memory (in KB) used by Argon2id.
+	CfgKeyArgon2MemoryKB = "key.argon2MemoryKB"
+	// CfgKeyArgon2Threads defines the degree of parallelism of Argon2id.
+	CfgKeyArgon2Threads = "key.argon2Threads"
+	// CfgKeyProvider defines the external key management service the validator key is held by, "vault" or "awskms".
+	// Leave it empty to use the local keystore.
+	CfgKeyProvider = "key.provider"
+	// CfgKeyVaultAddress defines the address of the Vault server, e.g. https://127.0.0.1:8200.
+	CfgKeyVaultAddress = "key.vaultAddress"
+	// CfgKeyVaultToken defines the Vault token, default to the VAULT_TOKEN environment variable.
+	CfgKeyVaultToken = "key.vaultToken"
+	// CfgKeyVaultTransitPath defines the mount path of the Vault transit secrets engine.
+	CfgKeyVaultTransitPath = "key.vaultTransitPath"
+	// CfgKeyVaultKeyName defines the name of the secp256k1 key in the Vault transit secrets engine.
+	CfgKeyVaultKeyName = "key.vaultKeyName"
+	// CfgKeyAWSKMSRegion defines the AWS region of the KMS key. The credentials are read from the AWS environment variables.
+	CfgKeyAWSKMSRegion = "key.awsKMSRegion"
+	// CfgKeyAWSKMSKeyID defines the ID, ARN or alias of the ECC_SECG_P256K1 KMS key.
+	CfgKeyAWSKMSKeyID = "key.awsKMSKeyID"
+	// CfgKeyAWSKMSEndpoint overrides the KMS endpoint, e.g. for VPC endpoints.
+	CfgKeyAWSKMSEndpoint = "key.awsKMSEndpoint"
 
 	// CfgNodeType indicates the type of the node, e.g. blockchain node/edge node
 	CfgNodeType = "node.type"
+	// CfgNodeShutdownTimeout sets the max number of seconds to wait for the sub components to stop on shutdown.
+	CfgNodeShutdownTimeout = "node.shutdownTimeout"
+	// CfgMempoolJournalEnabled decides whether to save the pending transactions on shutdown and restore them on startup.
+	CfgMempoolJournalEnabled = "mempool.journalEnabled"
+	// CfgMempoolMinTxFees maps the tx type names (e.g. send, smart_contract) to the minimum fees in TFuelWei the
+	// node accepts into its mempool, on top of the protocol minimums. For smart contract txs it is the gas price.
+	CfgMempoolMinTxFees = "mempool.minTxFees"
+	// CfgMempoolTxStatusRetentionSecs sets how many seconds the mempool keeps the status of a transaction, e.g.
+	// included, evicted, replaced or expired, after the transaction is first seen.
+	CfgMempoolTxStatusRetentionSecs = "mempool.txStatusRetentionSecs"
 	// CfgForceValidateSnapshot defines wether validation of snapshot can be skipped
 	CfgForceValidateSnapshot = "snapshot.force_validate"
 
@@ -28,6 +68,22 @@ const (
 	CfgConsensusMaxEpochLength = "consensus.maxEpochLength"
 	// CfgConsensusMinBlockTime defines the minimal block interval (in seconds)
 	CfgConsensusMinBlockInterval = "consensus.minBlockInterval"
+	// CfgConsensusMaxEpochTimeout defines the upper bound (in seconds) of the epoch timeout after backoff.
+	CfgConsensusMaxEpochTimeout = "consensus.maxEpochTimeout"
+	// CfgConsensusEpochTimeoutBackoffFactor defines the factor by which the epoch timeout grows after each timeout, 1.0 disables the backoff.
+	CfgConsensusEpochTimeoutBackoffFactor = "consensus.epochTimeoutBackoffFactor"
+	// CfgConsensusEpochTimeoutBackoffThreshold defines the number of consecutive epoch timeouts before the backoff starts.
+	CfgConsensusEpochTimeoutBackoffThreshold = "consensus.epochTimeoutBackoffThreshold"
+	// CfgConsensusJournalEnabled decides whether to record the consensus events to a local journal for post-mortem analysis.
+	CfgConsensusJournalEnabled = "consensus.journalEnabled"
+	// CfgConsensusJournalPath defines the path of the consensus journal, default to <config>/consensus_journal.
+	CfgConsensusJournalPath = "consensus.journalPath"
+	// CfgConsensusJournalMaxSizeMB defines the size (in MB) at which the consensus journal is rotated.
+	CfgConsensusJournalMaxSizeMB = "consensus.journalMaxSizeMB"
+	// CfgConsensusSignStateEnabled decides whether to check every vote and proposal against the last signed ones before signing.
+	CfgConsensusSignStateEnabled = "consensus.signStateEnabled"
+	// CfgConsensusSignStatePath defines the path of the file the last signed vote and proposal are kept in, default to <data>/sign_state.json.
+	CfgConsensusSignStatePath = "consensus.signStatePath"
 	// CfgConsensusMessageQueueSize defines the capacity of consensus message queue.
 	CfgConsensusMessageQueueSize = "consensus.messageQueueSize"
 	// CfgConsensusEdgeNodeVoteQueueSize defines the capacity of edge node vote message queue.
@@ -35,6 +91,13 @@ const (
 	// CfgConsensusPassThroughGuardianVote defines the how guardian vote is handled.
 	CfgConsensusPassThroughGuardianVote = "consensus.passThroughGuardianVote"
 
+	// CfgClockMaxSkewMs sets the skew (in milliseconds) of the local clock above which a warning is logged, zero disables the check.
+	CfgClockMaxSkewMs = "clock.maxSkewMs"
+	// CfgClockRefuseToPropose sets whether the node refuses to propose blocks while its clock skew is above clock.maxSkewMs.
+	CfgClockRefuseToPropose = "clock.refuseToPropose"
+	// CfgClockNTPServer sets the NTP server (host or host:port) the clock skew is measured against, in addition to the peers.
+	CfgClockNTPServer = "clock.ntpServer"
+
 	// CfgStorageRollingEnabled indicates whether rolling is enabled
 	CfgStorageRollingEnabled = "storage.stateRollingEnabled"
 	// CfgStorageStatePruningEnabled indicates whether state pruning is enabled
@@ -51,6 +114,29 @@ const (
 	CfgStorageLevelDBHandles = "storage.levelDBHandles"
 	// CfgStorageRollingInterval is the block interval that we start new db layer
 	CfgStorageRollingInterval = "storage.rollingInterval"
+	// CfgStoragePrefetchEnabled indicates whether to warm the trie nodes the block transactions touch ahead of executing them
+	CfgStoragePrefetchEnabled = "storage.prefetchEnabled"
+	// CfgStorageFlatStateSize is the number of entries of the flattened table of the latest state that serves the hot reads, 0 to disable
+	CfgStorageFlatStateSize = "storage.flatStateSize"
+
+	// CfgLedgerParallelExecEnabled indicates whether to execute the independent send transactions of a block in parallel (experimental)
+	CfgLedgerParallelExecEnabled = "ledger.parallelExecEnabled"
+	// CfgLedgerIntegrityCheckEnabled indicates whether to periodically verify the finalized state against silent database corruption
+	CfgLedgerIntegrityCheckEnabled = "ledger.integrityCheckEnabled"
+	// CfgLedgerIntegrityCheckInterval defines the interval (in seconds) of the state integrity checks
+	CfgLedgerIntegrityCheckInterval = "ledger.integrityCheckInterval"
+	// CfgLedgerIntegrityCheckSampleSize defines the number of the state trie nodes verified by each integrity check
+	CfgLedgerIntegrityCheckSampleSize = "ledger.integrityCheckSampleSize"
+	// CfgLedgerInvariantCheckEnabled indicates whether to validate the state transition of each applied block, e.g. the balances
+	// stay non-negative and the stakes are conserved, and halt the node on a violation. Meant for debugging as it slows down the node.
+	CfgLedgerInvariantCheckEnabled = "ledger.invariantCheckEnabled"
+	// CfgLedgerStateDiffEnabled indicates whether to compute the sorted key/value diff of the state for each applied block, for
+	// the differential testing against other implementations. The recent diffs can be queried through the RPC.
+	CfgLedgerStateDiffEnabled = "ledger.stateDiffEnabled"
+	// CfgLedgerStateDiffDir defines the directory to save the state diffs to, one file per block, none is saved if empty
+	CfgLedgerStateDiffDir = "ledger.stateDiffDir"
+	// CfgLedgerRewardIndexEnabled indicates whether to index the reward events of the committed blocks by the rewarded addresses
+	CfgLedgerRewardIndexEnabled = "ledger.rewardIndexEnabled"
 
 	// CfgSyncMessageQueueSize defines the capacity of Sync Manager message queue.
 	CfgSyncMessageQueueSize = "sync.messageQueueSize"
@@ -58,6 +144,26 @@ const (
 	CfgSyncDownloadByHash = "sync.downloadByHash"
 	// CfgSyncDownloadByHeader indicates whether should download blocks using header.
 	CfgSyncDownloadByHeader = "sync.downloadByHeader"
+	// CfgSyncMaxPendingBlocks defines the max number of outstanding block requests during catch-up sync.
+	CfgSyncMaxPendingBlocks = "sync.maxPendingBlocks"
+	// CfgSyncMaxPendingBlocksPerPeer defines the max number of outstanding block requests sent to a single peer.
+	CfgSyncMaxPendingBlocksPerPeer = "sync.maxPendingBlocksPerPeer"
+	// CfgSyncCheckpointHeight defines the height of the trusted checkpoint, 0 means no trusted checkpoint.
+	CfgSyncCheckpointHeight = "sync.checkpointHeight"
+	// CfgSyncCheckpointBlockHash defines the hash of the trusted checkpoint block.
+	CfgSyncCheckpointBlockHash = "sync.checkpointBlockHash"
+	// CfgSyncCheckpointValidatorSetHash defines the hash of the validator set of the trusted checkpoint block (optional).
+	CfgSyncCheckpointValidatorSetHash = "sync.checkpointValidatorSetHash"
+	// CfgSyncStateSyncEnabled indicates whether a new node downloads the latest snapshot from peers instead of syncing from its snapshot.
+	CfgSyncStateSyncEnabled = "sync.stateSyncEnabled"
+	// CfgSyncStateSyncTrustedBlockHash the hash of the block the snapshot is taken at, required for state sync.
+	CfgSyncStateSyncTrustedBlockHash = "sync.stateSyncTrustedBlockHash"
+	// CfgSyncStateSyncTimeoutSecs defines the max time (in seconds) spent on state sync before falling back to block sync.
+	CfgSyncStateSyncTimeoutSecs = "sync.stateSyncTimeoutSecs"
+	// CfgSyncSnapshotServeEnabled indicates whether to serve the local snapshots to peers in state sync.
+	CfgSyncSnapshotServeEnabled = "sync.snapshotServeEnabled"
+	// CfgSyncSnapshotServeDir defines the directory of the snapshots served to peers, default to <config>/backup/snapshot.
+	CfgSyncSnapshotServeDir = "sync.snapshotServeDir"
 
 	// CfgP2POpt sets which P2P network to use: p2p, libp2p, or both.
 	CfgP2POpt = "p2p.opt"
@@ -79,8 +185,11 @@ const (
 	//CfgP2PBootstrapNodePurgePeerInterval = "p2p.bootstrapNodePurgePeerInterval"
 	// CfgP2PBootstrapSeeds sets the boostrap peers.
 	CfgP2PBootstrapSeeds = "p2p.bootstrapSeeds"
-	// CfgP2PSeeds sets the seed peers.
+	// CfgP2PSeeds sets the seed peers. An entry without a port, e.g. seed.thetatoken.org, is a DNS seed whose
+	// A/AAAA and TXT records list the seed peers.
 	CfgP2PSeeds = "p2p.seeds"
+	// CfgP2PDNSSeedRefreshInterval specifies the interval (in seconds) to resolve the DNS seeds again, 0 to disable
+	CfgP2PDNSSeedRefreshInterval = "p2p.dnsSeedRefreshInterval"
 	// CfgLibP2PSeeds sets the boostrap peers in libp2p format.
 	CfgLibP2PSeeds = "p2p.libp2pSeeds"
 	// CfgLibP2PRendezvous is the libp2p rendezvous string
@@ -91,6 +200,11 @@ const (
 	CfgP2PSeedPeerOnlyOutbound = "p2p.seedPeerOnlyOutbound"
 	// CfgP2PSeedPeerOnly decides whether the node will connect to peers other than the seeds.
 	CfgP2PSeedPeerOnly = "p2p.seedPeerOnly"
+	// CfgP2PPrivatePeerIDs sets the comma separated IDs of the private peers, e.g. the validators behind a sentry node.
+	// The addresses of the private peers are never shared with other peers. With libp2p, the private peers are also
+	// accepted beyond the max number of peers, and their connections are never trimmed. The validator itself should
+	// set the sentry nodes as its seeds, and enable seedPeerOnly.
+	CfgP2PPrivatePeerIDs = "p2p.privatePeerIDs"
 	// CfgP2PMinNumPeers specifies the minimal number of peers a node tries to maintain
 	CfgP2PMinNumPeers = "p2p.minNumPeers"
 	// CfgP2PMaxNumPeers specifies the maximal number of peers a node can simultaneously connected to
@@ -107,6 +221,36 @@ const (
 	CfgP2PNatMapping = "p2p.natMapping"
 	// CfgP2PMaxConnections specifies the number of max connections a node can accept
 	CfgP2PMaxConnections = "p2p.maxConnections"
+	// CfgP2PPeerScoringEnabled sets whether to score the peers and automatically ban the misbehaving ones
+	CfgP2PPeerScoringEnabled = "p2p.peerScoringEnabled"
+	// CfgP2PPeerBanThreshold specifies the score at or below which a peer gets banned
+	CfgP2PPeerBanThreshold = "p2p.peerBanThreshold"
+	// CfgP2PPeerBanDurationSecs specifies how long (in seconds) a misbehaving peer is banned
+	CfgP2PPeerBanDurationSecs = "p2p.peerBanDurationSecs"
+	// CfgP2PPeerMaxRecvRate specifies the receive rate (in bytes/second) above which a peer is penalized, 0 means no limit.
+	// Disabled by default, since the peers legitimately send at high rates while serving the blocks to a syncing node.
+	CfgP2PPeerMaxRecvRate = "p2p.peerMaxRecvRate"
+	// CfgP2PSendRate specifies the upload rate limit (in bytes/second) for each peer
+	CfgP2PSendRate = "p2p.sendRate"
+	// CfgP2PRecvRate specifies the download rate limit (in bytes/second) for each peer
+	CfgP2PRecvRate = "p2p.recvRate"
+	// CfgP2PGlobalSendRate specifies the upload rate limit (in bytes/second) across all peers, 0 means no limit
+	CfgP2PGlobalSendRate = "p2p.globalSendRate"
+	// CfgP2PGlobalRecvRate specifies the download rate limit (in bytes/second) across all peers, 0 means no limit
+	CfgP2PGlobalRecvRate = "p2p.globalRecvRate"
+	// CfgP2PMaxMsgRatePerChannel specifies the max number of messages per second a peer can send on each channel, 0 means no limit
+	CfgP2PMaxMsgRatePerChannel = "p2p.maxMsgRatePerChannel"
+	// CfgP2PRequireEncryption decides whether to require an encrypted and authenticated transport. If disabled,
+	// the transport is left unencrypted with the peers that do not require it either.
+	CfgP2PRequireEncryption = "p2p.requireEncryption"
+	// CfgP2PCompression decides whether to compress the p2p payloads with peers that also support it
+	CfgP2PCompression = "p2p.compression"
+	// CfgP2PSendQueueCapacity specifies the max number of messages queued for each channel of a peer connection
+	CfgP2PSendQueueCapacity = "p2p.sendQueueCapacity"
+	// CfgP2PSendQueuePolicy specifies what happens when a message is sent to a full channel queue: "block" waits
+	// for the queue until a timeout, "dropOldest" evicts the oldest queued message on the block, header and tx channels,
+	// while the other channels still wait for the queue
+	CfgP2PSendQueuePolicy = "p2p.sendQueuePolicy"
 
 	// CfgSyncInboundResponseWhitelist filters inbound messages based on peer ID.
 	CfgSyncInboundResponseWhitelist = "sync.inboundResponseWhitelist"
@@ -121,6 +265,109 @@ const (
 	CfgRPCMaxConnections = "rpc.maxConnections"
 	// CfgRPCTimeoutSecs set a timeout for RPC.
 	CfgRPCTimeoutSecs = "rpc.timeoutSecs"
+	// CfgRPCAdminEnabled sets whether the admin RPC methods (e.g. peer management) are enabled.
+	CfgRPCAdminEnabled = "rpc.adminEnabled"
+	// CfgRPCStrictAddressChecksum sets whether the RPC methods reject the hex addresses without the EIP-55 checksum.
+	CfgRPCStrictAddressChecksum = "rpc.strictAddressChecksum"
+	// CfgRPCRESTEnabled sets whether the REST API and its OpenAPI document are served by the RPC server.
+	CfgRPCRESTEnabled = "rpc.restEnabled"
+	// CfgRPCCORSAllowedOrigins sets the origins allowed to call the RPC service from the browsers, "*" allows all origins.
+	CfgRPCCORSAllowedOrigins = "rpc.corsAllowedOrigins"
+	// CfgRPCCORSAllowedHeaders sets the request headers allowed in the cross-origin RPC calls.
+	CfgRPCCORSAllowedHeaders = "rpc.corsAllowedHeaders"
+	// CfgRPCTLSCertFile sets the path of the TLS certificate of RPC service. TLS is enabled if both the certificate and the key are set.
+	CfgRPCTLSCertFile = "rpc.tlsCertFile"
+	// CfgRPCTLSKeyFile sets the path of the TLS private key of RPC service.
+	CfgRPCTLSKeyFile = "rpc.tlsKeyFile"
+	// CfgRPCRequestIDEnabled sets whether to assign an ID to each RPC request, returned in the X-Request-ID header.
+	CfgRPCRequestIDEnabled = "rpc.requestIDEnabled"
+	// CfgRPCSlowCallThresholdMs sets the latency above which the RPC calls are logged, zero disables the logging.
+	CfgRPCSlowCallThresholdMs = "rpc.slowCallThresholdMs"
+	// CfgRPCReadyMaxBlockLag sets the max number of blocks the node can lag behind its peers to be reported ready by /readyz.
+	CfgRPCReadyMaxBlockLag = "rpc.readyMaxBlockLag"
+	// CfgRPCReadyMinPeers sets the min number of peers the node needs to be reported ready by /readyz.
+	CfgRPCReadyMinPeers = "rpc.readyMinPeers"
+	// CfgRPCSupplyLockedAddresses sets the addresses whose balances are excluded from the circulating supply.
+	CfgRPCSupplyLockedAddresses = "rpc.supplyLockedAddresses"
+
+	// CfgGRPCEnabled sets whether to run the gRPC service alongside the RPC service.
+	CfgGRPCEnabled = "grpc.enabled"
+	// CfgGRPCAddress sets the binding address of gRPC service.
+	CfgGRPCAddress = "grpc.address"
+	// CfgGRPCPort sets the port of gRPC service.
+	CfgGRPCPort = "grpc.port"
+
+	// CfgWebhookEnabled sets whether to POST the events of the finalized blocks to the webhook URLs.
+	CfgWebhookEnabled = "webhook.enabled"
+	// CfgWebhookURLs sets the URLs the webhook events are POSTed to.
+	CfgWebhookURLs = "webhook.urls"
+	// CfgWebhookSecret sets the key the webhook payloads are signed with (HMAC-SHA256), no signature if empty.
+	CfgWebhookSecret = "webhook.secret"
+	// CfgWebhookMaxRetries sets the max number of retries of a failed webhook delivery.
+	CfgWebhookMaxRetries = "webhook.maxRetries"
+	// CfgWebhookRetryBackoffMs sets the delay (in milliseconds) before the first retry, doubled for each retry.
+	CfgWebhookRetryBackoffMs = "webhook.retryBackoffMs"
+	// CfgWebhookTimeoutSecs sets the timeout (in seconds) of a webhook delivery.
+	CfgWebhookTimeoutSecs = "webhook.timeoutSecs"
+
+	// CfgTelemetryEnabled sets whether to periodically report the node version, height, peer count and region to the telemetry URL. Opt-in.
+	CfgTelemetryEnabled = "telemetry.enabled"
+	// CfgTelemetryURL sets the URL of the telemetry collector the reports are POSTed to.
+	CfgTelemetryURL = "telemetry.url"
+	// CfgTelemetryName sets the name the node is shown with on the telemetry dashboard.
+	CfgTelemetryName = "telemetry.name"
+	// CfgTelemetryRegion sets the region reported by the node, e.g. us-east.
+	CfgTelemetryRegion = "telemetry.region"
+	// CfgTelemetryIntervalSecs sets the interval (in seconds) between two telemetry reports.
+	CfgTelemetryIntervalSecs = "telemetry.intervalSecs"
+
+	// CfgAlertEnabled sets whether to run the alert command and POST to the alert webhook URLs when the node is unhealthy.
+	CfgAlertEnabled = "alert.enabled"
+	// CfgAlertCommand sets the shell command run on each alert, with the alert in the THETA_ALERT_* environment variables.
+	CfgAlertCommand = "alert.command"
+	// CfgAlertWebhookURLs sets the URLs the alerts are POSTed to.
+	CfgAlertWebhookURLs = "alert.webhookURLs"
+	// CfgAlertMissedProposals sets the number of consecutive missed proposal slots that triggers an alert, zero disables the alert.
+	CfgAlertMissedProposals = "alert.missedProposals"
+	// CfgAlertMaxBlockLag sets the number of blocks the node can fall behind its peers before an alert, zero disables the alert.
+	CfgAlertMaxBlockLag = "alert.maxBlockLag"
+	// CfgAlertNoPeersSecs sets how long (in seconds) the node can have no peers before an alert, zero disables the alert.
+	CfgAlertNoPeersSecs = "alert.noPeersSecs"
+	// CfgAlertMaxClockSkewMs sets the clock skew (in milliseconds) that triggers an alert, zero disables the alert.
+	CfgAlertMaxClockSkewMs = "alert.maxClockSkewMs"
+
+	// CfgWatchAddresses sets the watched addresses, in addition to the ones registered by the admin RPC.
+	CfgWatchAddresses = "watch.addresses"
+	// CfgWatchSubscriptionBuffer sets the number of the pending matched txs a WebSocket subscriber may have before being dropped.
+	CfgWatchSubscriptionBuffer = "watch.subscriptionBuffer"
+
+	// CfgLightRemoteRPCEndpoint defines the RPC endpoint of the full node the light client syncs headers from.
+	CfgLightRemoteRPCEndpoint = "light.remoteRPCEndpoint"
+	// CfgLightTrustedBlockHash defines the block the light client starts verifying from, default to the genesis block.
+	CfgLightTrustedBlockHash = "light.trustedBlockHash"
+	// CfgLightTrustedHeight defines the height of the trusted block.
+	CfgLightTrustedHeight = "light.trustedHeight"
+	// CfgLightSyncIntervalSecs defines the interval (in seconds) between two header syncs.
+	CfgLightSyncIntervalSecs = "light.syncIntervalSecs"
+	// CfgLightRPCPort sets the port of the light client RPC service, which differs from the full node RPC port
+	// so that both can run on the same host.
+	CfgLightRPCPort = "light.rpcPort"
+
+	// CfgSignerRemoteAddress sets the address of the remote signer the votes and proposals are signed by. The node
+	// signs with its own key if empty.
+	CfgSignerRemoteAddress = "signer.remoteAddress"
+	// CfgSignerListenAddress sets the binding address of the remote signer process.
+	CfgSignerListenAddress = "signer.listenAddress"
+	// CfgSignerTLSCertFile sets the certificate file the node and the remote signer authenticate themselves with.
+	CfgSignerTLSCertFile = "signer.tlsCertFile"
+	// CfgSignerTLSKeyFile sets the private key file of the certificate.
+	CfgSignerTLSKeyFile = "signer.tlsKeyFile"
+	// CfgSignerTLSCAFile sets the CA certificate file the certificate of the other side has to be signed by.
+	CfgSignerTLSCAFile = "signer.tlsCAFile"
+	// CfgSignerStatePath sets the path of the file the remote signer keeps the last signed vote and proposal in.
+	CfgSignerStatePath = "signer.statePath"
+	// CfgSignerTimeoutSecs sets the max number of seconds to wait for the remote signer to sign.
+	CfgSignerTimeoutSecs = "signer.timeoutSecs"
 
 	// CfgLogLevels sets the log level.
 	CfgLogLevels = "log.levels"
@@ -133,6 +380,8 @@ const (
 
 	// Graphite Server to collet metrics
 	CfgMetricsServer = "metrics.server"
+	// CfgMetricsPrometheusAddress specifies the address serving the metrics to the Prometheus scrapers, empty to disable
+	CfgMetricsPrometheusAddress = "metrics.prometheusAddress"
 
 	// CfgProfEnabled to enable profiling
 	CfgProfEnabled = "prof.enabled"
@@ -158,17 +407,57 @@ p2p:
 
 func init() {
 	viper.SetDefault(CfgNodeType, 1) // 1: blockchain node, 2: edge node
+	viper.SetDefault(CfgNodeShutdownTimeout, 30)
+	viper.SetDefault(CfgKeyKDF, "scrypt")
+	viper.SetDefault(CfgKeyScryptN, 1<<18)
+	viper.SetDefault(CfgKeyScryptP, 1)
+	viper.SetDefault(CfgKeyArgon2Time, 3)
+	viper.SetDefault(CfgKeyArgon2MemoryKB, 64*1024)
+	viper.SetDefault(CfgKeyArgon2Threads, 4)
+	viper.SetDefault(CfgKeyProvider, "")
+	viper.SetDefault(CfgKeyVaultAddress, "")
+	viper.SetDefault(CfgKeyVaultToken, "")
+	viper.SetDefault(CfgKeyVaultTransitPath, "transit")
+	viper.SetDefault(CfgKeyVaultKeyName, "")
+	viper.SetDefault(CfgKeyAWSKMSRegion, "")
+	viper.SetDefault(CfgKeyAWSKMSKeyID, "")
+	viper.SetDefault(CfgKeyAWSKMSEndpoint, "")
+	viper.SetDefault(CfgMempoolJournalEnabled, true)
+	viper.SetDefault(CfgMempoolMinTxFees, map[string]string{})
+	viper.SetDefault(CfgMempoolTxStatusRetentionSecs, 3600)
 	viper.SetDefault(CfgForceValidateSnapshot, false)
 
 	viper.SetDefault(CfgConsensusMaxEpochLength, 20)
 	viper.SetDefault(CfgConsensusMinBlockInterval, 6)
+	viper.SetDefault(CfgConsensusMaxEpochTimeout, 120)
+	viper.SetDefault(CfgConsensusEpochTimeoutBackoffFactor, 1.5)
+	viper.SetDefault(CfgConsensusEpochTimeoutBackoffThreshold, 3)
+	viper.SetDefault(CfgConsensusJournalEnabled, false)
+	viper.SetDefault(CfgConsensusJournalPath, "")
+	viper.SetDefault(CfgConsensusJournalMaxSizeMB, 256)
+	viper.SetDefault(CfgConsensusSignStateEnabled, true)
+	viper.SetDefault(CfgConsensusSignStatePath, "")
 	viper.SetDefault(CfgConsensusMessageQueueSize, 512)
 	viper.SetDefault(CfgConsensusEdgeNodeVoteQueueSize, 100000)
 	viper.SetDefault(CfgConsensusPassThroughGuardianVote, false)
 
+	viper.SetDefault(CfgClockMaxSkewMs, 1000)
+	viper.SetDefault(CfgClockRefuseToPropose, false)
+	viper.SetDefault(CfgClockNTPServer, "")
+
 	viper.SetDefault(CfgSyncMessageQueueSize, 512)
 	viper.SetDefault(CfgSyncDownloadByHash, false)
 	viper.SetDefault(CfgSyncDownloadByHeader, true)
+	viper.SetDefault(CfgSyncMaxPendingBlocks, 64)
+	viper.SetDefault(CfgSyncMaxPendingBlocksPerPeer, 16)
+	viper.SetDefault(CfgSyncCheckpointHeight, 0)
+	viper.SetDefault(CfgSyncCheckpointBlockHash, "")
+	viper.SetDefault(CfgSyncCheckpointValidatorSetHash, "")
+	viper.SetDefault(CfgSyncStateSyncEnabled, false)
+	viper.SetDefault(CfgSyncStateSyncTrustedBlockHash, "")
+	viper.SetDefault(CfgSyncStateSyncTimeoutSecs, 7200)
+	viper.SetDefault(CfgSyncSnapshotServeEnabled, false)
+	viper.SetDefault(CfgSyncSnapshotServeDir, "")
 
 	viper.SetDefault(CfgStorageRollingEnabled, true)
 	viper.SetDefault(CfgStorageStatePruningEnabled, true)
@@ -178,18 +467,45 @@ func init() {
 	viper.SetDefault(CfgStorageLevelDBCacheSize, 256)
 	viper.SetDefault(CfgStorageLevelDBHandles, 16)
 	viper.SetDefault(CfgStorageRollingInterval, 14400) // approximately 1 days by default
+	viper.SetDefault(CfgStoragePrefetchEnabled, false) // mostly helps the HDD-backed nodes
+	viper.SetDefault(CfgStorageFlatStateSize, 0)
+
+	viper.SetDefault(CfgLedgerParallelExecEnabled, false)
+	viper.SetDefault(CfgLedgerIntegrityCheckEnabled, false)
+	viper.SetDefault(CfgLedgerIntegrityCheckInterval, 3600)
+	viper.SetDefault(CfgLedgerIntegrityCheckSampleSize, 10000)
+	viper.SetDefault(CfgLedgerInvariantCheckEnabled, false)
+	viper.SetDefault(CfgLedgerStateDiffEnabled, false)
+	viper.SetDefault(CfgLedgerStateDiffDir, "")
+	viper.SetDefault(CfgLedgerRewardIndexEnabled, false)
+
+	viper.SetDefault(CfgLightRemoteRPCEndpoint, "http://localhost:16888/rpc")
+	viper.SetDefault(CfgLightTrustedBlockHash, "")
+	viper.SetDefault(CfgLightTrustedHeight, 0)
+	viper.SetDefault(CfgLightSyncIntervalSecs, 2)
+	viper.SetDefault(CfgLightRPCPort, "16898")
+
+	viper.SetDefault(CfgSignerRemoteAddress, "")
+	viper.SetDefault(CfgSignerListenAddress, "127.0.0.1:16890")
+	viper.SetDefault(CfgSignerTLSCertFile, "")
+	viper.SetDefault(CfgSignerTLSKeyFile, "")
+	viper.SetDefault(CfgSignerTLSCAFile, "")
+	viper.SetDefault(CfgSignerStatePath, "")
+	viper.SetDefault(CfgSignerTimeoutSecs, 3)
 
 	viper.SetDefault(CfgRPCEnabled, false)
 	viper.SetDefault(CfgP2PMessageQueueSize, 512)
 	viper.SetDefault(CfgP2PName, "Anonymous")
 	viper.SetDefault(CfgP2PPort, 50001)
 	viper.SetDefault(CfgP2PSeeds, "")
+	viper.SetDefault(CfgP2PDNSSeedRefreshInterval, 1800)
 	viper.SetDefault(CfgP2PSeedPeerOnlyOutbound, false)
 	//viper.SetDefault(CfgP2POpt, P2POptLibp2p) // FIXME: this for some reason doesn't work
 	viper.SetDefault(CfgP2POpt, 0)
 	viper.SetDefault(CfgP2PReuseStream, true)
 	viper.SetDefault(CfgP2PSeedPeerOnly, false)
 	viper.SetDefault(CfgP2PIsBootstrapNode, false)
+	viper.SetDefault(CfgP2PPrivatePeerIDs, "")
 	//viper.SetDefault(CfgP2PBootstrapNodePurgePeerInterval, 1800) // 30 minutes
 	viper.SetDefault(CfgP2PMinNumPeers, 32)
 	//viper.SetDefault(CfgP2PMaxNumPeers, 256)
@@ -200,11 +516,63 @@ func init() {
 	viper.SetDefault(CfgP2PConnectionFIFO, false)
 	viper.SetDefault(CfgP2PNatMapping, false)
 	viper.SetDefault(CfgP2PMaxConnections, 2048)
+	viper.SetDefault(CfgP2PPeerScoringEnabled, true)
+	viper.SetDefault(CfgP2PPeerBanThreshold, 0)
+	viper.SetDefault(CfgP2PPeerBanDurationSecs, 3600)
+	viper.SetDefault(CfgP2PPeerMaxRecvRate, 0)
+	viper.SetDefault(CfgP2PSendRate, 512000)   // 500KB/s
+	viper.SetDefault(CfgP2PRecvRate, 10240000) // 10MB/s
+	viper.SetDefault(CfgP2PGlobalSendRate, 0)
+	viper.SetDefault(CfgP2PGlobalRecvRate, 0)
+	viper.SetDefault(CfgP2PMaxMsgRatePerChannel, 0)
+	viper.SetDefault(CfgP2PRequireEncryption, true)
+	viper.SetDefault(CfgP2PCompression, false)
+	viper.SetDefault(CfgP2PSendQueueCapacity, 1)
+	viper.SetDefault(CfgP2PSendQueuePolicy, "block")
 
 	viper.SetDefault(CfgRPCAddress, "0.0.0.0")
 	viper.SetDefault(CfgRPCPort, "16888")
 	viper.SetDefault(CfgRPCMaxConnections, 200)
 	viper.SetDefault(CfgRPCTimeoutSecs, 60)
+	viper.SetDefault(CfgRPCAdminEnabled, false)
+	viper.SetDefault(CfgRPCStrictAddressChecksum, false)
+	viper.SetDefault(CfgRPCRESTEnabled, false)
+	viper.SetDefault(CfgRPCCORSAllowedOrigins, []string{"*"})
+	viper.SetDefault(CfgRPCCORSAllowedHeaders, []string{"*"})
+	viper.SetDefault(CfgRPCTLSCertFile, "")
+	viper.SetDefault(CfgRPCTLSKeyFile, "")
+	viper.SetDefault(CfgRPCRequestIDEnabled, false)
+	viper.SetDefault(CfgRPCSlowCallThresholdMs, 0)
+	viper.SetDefault(CfgRPCReadyMaxBlockLag, 10)
+	viper.SetDefault(CfgRPCReadyMinPeers, 1)
+	viper.SetDefault(CfgRPCSupplyLockedAddresses, []string{})
+	viper.SetDefault(CfgGRPCEnabled, false)
+	viper.SetDefault(CfgGRPCAddress, "0.0.0.0")
+	viper.SetDefault(CfgGRPCPort, "16889")
+
+	viper.SetDefault(CfgWebhookEnabled, false)
+	viper.SetDefault(CfgWebhookURLs, []string{})
+	viper.SetDefault(CfgWebhookSecret, "")
+	viper.SetDefault(CfgWebhookMaxRetries, 5)
+	viper.SetDefault(CfgWebhookRetryBackoffMs, 1000)
+	viper.SetDefault(CfgWebhookTimeoutSecs, 10)
+
+	viper.SetDefault(CfgTelemetryEnabled, false)
+	viper.SetDefault(CfgTelemetryURL, "")
+	viper.SetDefault(CfgTelemetryName, "")
+	viper.SetDefault(CfgTelemetryRegion, "")
+	viper.SetDefault(CfgTelemetryIntervalSecs, 60)
+
+	viper.SetDefault(CfgAlertEnabled, false)
+	viper.SetDefault(CfgAlertCommand, "")
+	viper.SetDefault(CfgAlertWebhookURLs, []string{})
+	viper.SetDefault(CfgAlertMissedProposals, 3)
+	viper.SetDefault(CfgAlertMaxBlockLag, 20)
+	viper.SetDefault(CfgAlertNoPeersSecs, 60)
+	viper.SetDefault(CfgAlertMaxClockSkewMs, 2000)
+
+	viper.SetDefault(CfgWatchAddresses, []string{})
+	viper.SetDefault(CfgWatchSubscriptionBuffer, 256)
 
 	viper.SetDefault(CfgLogLevels, "*:debug")
 	viper.SetDefault(CfgLogPrintSelfID, false)
@@ -212,9 +580,12 @@ func init() {
 	viper.SetDefault(CfgGuardianRoundLength, 30)
 
 	viper.SetDefault(CfgMetricsServer, "guardian-metrics.thetatoken.org")
+	viper.SetDefault(CfgMetricsPrometheusAddress, "")
 
 	viper.SetDefault(CfgProfEnabled, false)
 	viper.SetDefault(CfgForceGCEnabled, true)
+
+	recordConfigSchema()
 }
 
 // WriteInitialConfig writes initial config file to file system.
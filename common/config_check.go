@@ -0,0 +1,291 @@
+package common
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// configSchema maps the known config keys, in lower case as viper stores them, to their default
+// values, whose types are the expected types of the keys
+var configSchema map[string]interface{}
+
+// configSchemaExtras are the known config keys viper does not list among the defaults, i.e. the
+// keys without a default value, and the map keys whose default is empty
+var configSchemaExtras = map[string]interface{}{
+	CfgMempoolMinTxFees:             map[string]string{},
+	CfgConfigPath:                   "",
+	CfgDataPath:                     "",
+	CfgKeyPath:                      "",
+	CfgGenesisHash:                  "",
+	CfgGenesisChainID:               "",
+	CfgP2PVersion:                   "",
+	CfgP2PProtocolPrefix:            "",
+	CfgP2PLPort:                     0,
+	CfgP2PBootstrapSeeds:            "",
+	CfgLibP2PSeeds:                  "",
+	CfgLibP2PRendezvous:             "",
+	CfgSyncInboundResponseWhitelist: "",
+	CfgDebugLogSelectedEENPs:        false,
+}
+
+// recordConfigSchema records the keys with a default value as the known config keys. It is called
+// after the defaults are set, and before the config file is read.
+func recordConfigSchema() {
+	configSchema = make(map[string]interface{})
+	for _, key := range viper.AllKeys() {
+		configSchema[key] = viper.Get(key)
+	}
+	for key, def := range configSchemaExtras {
+		configSchema[strings.ToLower(key)] = def
+	}
+}
+
+// CheckConfig validates the config file and the effective config values. It reports the unknown keys
+// of the config file, the values that cannot be converted to the type of the key, and the options
+// that conflict with each other. An empty result means the config is valid.
+func CheckConfig() []error {
+	errs := []error{}
+
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		fileConfig := viper.New()
+		fileConfig.SetConfigFile(configFile)
+		if err := fileConfig.ReadInConfig(); err != nil {
+			return append(errs, fmt.Errorf("Failed to read config file %v: %v", configFile, err))
+		}
+		errs = append(errs, checkConfigKeys(configFile, fileConfig.AllKeys())...)
+	}
+
+	keys := []string{}
+	for key := range configSchema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !viper.IsSet(key) {
+			continue
+		}
+		if err := checkConfigValue(key, configSchema[key], viper.Get(key)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return append(errs, checkConfigConflicts()...)
+}
+
+// checkConfigKeys reports the keys unknown to the schema, along with the closest known key
+func checkConfigKeys(configFile string, keys []string) []error {
+	errs := []error{}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if isKnownConfigKey(key) {
+			continue
+		}
+		if suggestion := closestConfigKey(key); suggestion != "" {
+			errs = append(errs, fmt.Errorf("%v: unknown config key %v, did you mean %v?", configFile, key, suggestion))
+		} else {
+			errs = append(errs, fmt.Errorf("%v: unknown config key %v", configFile, key))
+		}
+	}
+	return errs
+}
+
+// isKnownConfigKey returns whether the key is in the schema, or is an entry of a map key, e.g.
+// mempool.minTxFees.send
+func isKnownConfigKey(key string) bool {
+	if _, ok := configSchema[key]; ok {
+		return true
+	}
+	for idx := strings.LastIndex(key, "."); idx > 0; idx = strings.LastIndex(key[:idx], ".") {
+		if def, ok := configSchema[key[:idx]]; ok {
+			return reflect.ValueOf(def).Kind() == reflect.Map
+		}
+	}
+	return false
+}
+
+// closestConfigKey returns the known key closest to the unknown key, or an empty string if none is
+// close enough to be a typo
+func closestConfigKey(key string) string {
+	closest := ""
+	minDistance := 3
+	for known := range configSchema {
+		distance := editDistance(key, known)
+		if distance < minDistance || (distance == minDistance && closest != "" && known < closest) {
+			closest = known
+			minDistance = distance
+		}
+	}
+	if minDistance >= 3 {
+		return ""
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between the strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// checkConfigValue checks the value can be read as the type of the default value. The values set
+// by the environment variables and the --config-override flags are strings.
+func checkConfigValue(key string, def interface{}, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	ok := true
+	expected := ""
+	switch def.(type) {
+	case bool:
+		expected = "a boolean"
+		switch v := value.(type) {
+		case bool:
+		case string:
+			_, err := strconv.ParseBool(v)
+			ok = err == nil
+		default:
+			ok = false
+		}
+	case int, int64, uint64:
+		expected = "an integer"
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		case float64:
+			ok = v == float64(int64(v))
+		case string:
+			_, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			ok = err == nil
+		default:
+			ok = false
+		}
+	case float64:
+		expected = "a number"
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		case string:
+			_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			ok = err == nil
+		default:
+			ok = false
+		}
+	case string:
+		expected = "a string"
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Map, reflect.Slice:
+			ok = false
+		}
+	case []string:
+		expected = "a list of strings"
+		switch v := value.(type) {
+		case string, []string:
+		case []interface{}:
+			for _, item := range v {
+				switch reflect.ValueOf(item).Kind() {
+				case reflect.Map, reflect.Slice:
+					ok = false
+				}
+			}
+		default:
+			ok = false
+		}
+	default:
+		if reflect.ValueOf(def).Kind() == reflect.Map {
+			expected = "a map"
+			ok = reflect.ValueOf(value).Kind() == reflect.Map
+		}
+	}
+	if !ok {
+		return fmt.Errorf("Invalid value of config key %v: %v, expected %v", key, value, expected)
+	}
+	return nil
+}
+
+// checkConfigConflicts reports the options that conflict with each other, or miss the options
+// they depend on
+func checkConfigConflicts() []error {
+	errs := []error{}
+
+	if (viper.GetString(CfgRPCTLSCertFile) == "") != (viper.GetString(CfgRPCTLSKeyFile) == "") {
+		errs = append(errs, fmt.Errorf("Both %v and %v need to be set to enable TLS", CfgRPCTLSCertFile, CfgRPCTLSKeyFile))
+	}
+	if viper.GetBool(CfgGRPCEnabled) && !viper.GetBool(CfgRPCEnabled) {
+		errs = append(errs, fmt.Errorf("%v requires %v", CfgGRPCEnabled, CfgRPCEnabled))
+	}
+	if viper.GetBool(CfgWebhookEnabled) && len(viper.GetStringSlice(CfgWebhookURLs)) == 0 {
+		errs = append(errs, fmt.Errorf("%v requires %v", CfgWebhookEnabled, CfgWebhookURLs))
+	}
+	if viper.GetBool(CfgTelemetryEnabled) && viper.GetString(CfgTelemetryURL) == "" {
+		errs = append(errs, fmt.Errorf("%v requires %v", CfgTelemetryEnabled, CfgTelemetryURL))
+	}
+	if viper.GetBool(CfgAlertEnabled) && viper.GetString(CfgAlertCommand) == "" && len(viper.GetStringSlice(CfgAlertWebhookURLs)) == 0 {
+		errs = append(errs, fmt.Errorf("%v requires %v or %v", CfgAlertEnabled, CfgAlertCommand, CfgAlertWebhookURLs))
+	}
+	if viper.GetBool(CfgSyncStateSyncEnabled) && viper.GetString(CfgSyncStateSyncTrustedBlockHash) == "" {
+		errs = append(errs, fmt.Errorf("%v requires %v", CfgSyncStateSyncEnabled, CfgSyncStateSyncTrustedBlockHash))
+	}
+	if viper.GetInt(CfgP2PMinNumPeers) > viper.GetInt(CfgP2PMaxNumPeers) {
+		errs = append(errs, fmt.Errorf("%v (%v) is greater than %v (%v)", CfgP2PMinNumPeers, viper.GetInt(CfgP2PMinNumPeers),
+			CfgP2PMaxNumPeers, viper.GetInt(CfgP2PMaxNumPeers)))
+	}
+
+	if viper.GetInt(CfgP2PSendQueueCapacity) <= 0 {
+		errs = append(errs, fmt.Errorf("%v must be positive", CfgP2PSendQueueCapacity))
+	}
+	switch policy := viper.GetString(CfgP2PSendQueuePolicy); policy {
+	case "block", "dropOldest":
+	default:
+		errs = append(errs, fmt.Errorf("Invalid %v: %v, expected block or dropOldest", CfgP2PSendQueuePolicy, policy))
+	}
+
+	switch kdf := viper.GetString(CfgKeyKDF); kdf {
+	case "scrypt", "argon2id":
+	default:
+		errs = append(errs, fmt.Errorf("Invalid %v: %v, expected scrypt or argon2id", CfgKeyKDF, kdf))
+	}
+
+	switch provider := viper.GetString(CfgKeyProvider); provider {
+	case "":
+	case "vault":
+		if viper.GetString(CfgKeyVaultAddress) == "" || viper.GetString(CfgKeyVaultKeyName) == "" {
+			errs = append(errs, fmt.Errorf("The vault key provider requires %v and %v", CfgKeyVaultAddress, CfgKeyVaultKeyName))
+		}
+	case "awskms":
+		if viper.GetString(CfgKeyAWSKMSRegion) == "" || viper.GetString(CfgKeyAWSKMSKeyID) == "" {
+			errs = append(errs, fmt.Errorf("The awskms key provider requires %v and %v", CfgKeyAWSKMSRegion, CfgKeyAWSKMSKeyID))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("Invalid %v: %v, expected vault or awskms", CfgKeyProvider, provider))
+	}
+	if viper.GetString(CfgKeyProvider) != "" && viper.GetString(CfgSignerRemoteAddress) != "" {
+		errs = append(errs, fmt.Errorf("%v and %v cannot both be set", CfgKeyProvider, CfgSignerRemoteAddress))
+	}
+
+	return errs
+}
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckConfigKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	errs := checkConfigKeys("config.yaml", []string{"p2p.port", "mempool.mintxfees.send", "p2p.seedz", "foo.bar"})
+	assert.Equal(2, len(errs))
+	assert.Equal("config.yaml: unknown config key foo.bar", errs[0].Error())
+	assert.Equal("config.yaml: unknown config key p2p.seedz, did you mean p2p.seeds?", errs[1].Error())
+}
+
+func TestCheckConfigValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(checkConfigValue("rpc.enabled", false, true))
+	assert.Nil(checkConfigValue("rpc.enabled", false, "true"))
+	assert.NotNil(checkConfigValue("rpc.enabled", false, "yes please"))
+	assert.Nil(checkConfigValue("p2p.port", 50001, 12000))
+	assert.Nil(checkConfigValue("p2p.port", 50001, "12000"))
+	assert.NotNil(checkConfigValue("p2p.port", 50001, "12000a"))
+	assert.NotNil(checkConfigValue("p2p.port", 50001, 1.5))
+	assert.Nil(checkConfigValue("consensus.epochTimeoutBackoffFactor", 1.5, 2))
+	assert.Nil(checkConfigValue("rpc.port", "16888", 16888))
+	assert.NotNil(checkConfigValue("rpc.port", "16888", []interface{}{"a"}))
+	assert.Nil(checkConfigValue("rpc.corsAllowedOrigins", []string{"*"}, []interface{}{"https://a.com"}))
+	assert.NotNil(checkConfigValue("rpc.corsAllowedOrigins", []string{"*"}, 3))
+	assert.Nil(checkConfigValue("mempool.minTxFees", map[string]string{}, map[string]interface{}{"send": "100"}))
+	assert.NotNil(checkConfigValue("mempool.minTxFees", map[string]string{}, "send"))
+}
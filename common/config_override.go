@@ -0,0 +1,79 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ConfigEnvPrefix is the prefix of the environment variables overriding the config keys, e.g.
+// THETA_P2P_PORT overrides p2p.port, and THETA_CONSENSUS_MINBLOCKINTERVAL overrides consensus.minBlockInterval
+const ConfigEnvPrefix = "THETA"
+
+// ConfigEnvName returns the name of the environment variable overriding the config key
+func ConfigEnvName(key string) string {
+	return ConfigEnvPrefix + "_" + strings.ToUpper(strings.Replace(key, ".", "_", -1))
+}
+
+// ParseConfigOverride parses a config override in the form of key=value
+func ParseConfigOverride(override string) (key string, value string, err error) {
+	idx := strings.Index(override, "=")
+	if idx <= 0 {
+		return "", "", fmt.Errorf("Invalid config override %v, expected key=value", override)
+	}
+	return strings.TrimSpace(override[:idx]), strings.TrimSpace(override[idx+1:]), nil
+}
+
+// SetConfigOverride overrides the config key with the value, which takes precedence over the config
+// file. The value of a list key, e.g. rpc.corsAllowedOrigins, is a comma separated list.
+func SetConfigOverride(key string, value string) {
+	reloadMu.Lock()
+	overriddenConfigKeys[strings.ToLower(key)] = true
+	reloadMu.Unlock()
+	reloadedMu.Lock()
+	delete(reloadedValues, strings.ToLower(key))
+	reloadedMu.Unlock()
+
+	switch viper.Get(key).(type) {
+	case []string, []interface{}:
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		viper.Set(key, list)
+	default:
+		viper.Set(key, value)
+	}
+}
+
+// ApplyEnvConfigOverrides overrides the known config keys, i.e. the keys with a default value or
+// present in the config file, with the environment variables named after them. The environ is in
+// the form returned by os.Environ().
+func ApplyEnvConfigOverrides(environ []string) {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if idx := strings.Index(kv, "="); idx > 0 {
+			env[kv[:idx]] = kv[idx+1:]
+		}
+	}
+	for _, key := range viper.AllKeys() {
+		if value, ok := env[ConfigEnvName(key)]; ok {
+			SetConfigOverride(key, value)
+		}
+	}
+}
+
+// ApplyConfigOverrides applies the config overrides in the form of key=value
+func ApplyConfigOverrides(overrides []string) error {
+	for _, override := range overrides {
+		key, value, err := ParseConfigOverride(override)
+		if err != nil {
+			return err
+		}
+		SetConfigOverride(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigOverride(t *testing.T) {
+	assert := assert.New(t)
+
+	key, value, err := ParseConfigOverride("p2p.port=12000")
+	assert.Nil(err)
+	assert.Equal("p2p.port", key)
+	assert.Equal("12000", value)
+
+	key, value, err = ParseConfigOverride("p2p.seeds=127.0.0.1:6000,127.0.0.1:7000")
+	assert.Nil(err)
+	assert.Equal("p2p.seeds", key)
+	assert.Equal("127.0.0.1:6000,127.0.0.1:7000", value)
+
+	_, _, err = ParseConfigOverride("p2p.port")
+	assert.NotNil(err)
+	_, _, err = ParseConfigOverride("=12000")
+	assert.NotNil(err)
+}
+
+func TestConfigOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	viper.SetDefault("test.port", 50001)
+	viper.SetDefault("test.enabled", false)
+	viper.SetDefault("test.minBlockInterval", 6)
+	viper.SetDefault("test.origins", []string{"*"})
+
+	assert.Equal("THETA_TEST_PORT", ConfigEnvName("test.port"))
+	assert.Equal("THETA_TEST_MINBLOCKINTERVAL", ConfigEnvName("test.minblockinterval"))
+
+	ApplyEnvConfigOverrides([]string{
+		"THETA_TEST_PORT=12000",
+		"THETA_TEST_MINBLOCKINTERVAL=3",
+		"THETA_TEST_ORIGINS=https://a.com, https://b.com",
+		"HOME=/root",
+	})
+	assert.Equal(12000, viper.GetInt("test.port"))
+	assert.Equal(3, viper.GetInt("test.minBlockInterval"))
+	assert.Equal([]string{"https://a.com", "https://b.com"}, viper.GetStringSlice("test.origins"))
+	assert.False(viper.GetBool("test.enabled"))
+
+	// The overrides passed in the command line take precedence over the environment variables
+	assert.Nil(ApplyConfigOverrides([]string{"test.port=13000", "test.enabled=true"}))
+	assert.Equal(13000, viper.GetInt("test.port"))
+	assert.True(viper.GetBool("test.enabled"))
+
+	assert.NotNil(ApplyConfigOverrides([]string{"test.port"}))
+}
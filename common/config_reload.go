@@ -0,0 +1,175 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// ReloadableConfigKeys are the config keys that can be reloaded from the config file without
+// restarting the node. The other keys are read once on startup. The reloadable values must be
+// read with the GetReloadableXxx accessors, since viper is not safe for concurrent writes.
+var ReloadableConfigKeys = []string{
+	CfgLogLevels,
+	CfgP2PMaxNumPeersToBroadcast,
+	CfgRPCSlowCallThresholdMs,
+	CfgRPCCORSAllowedOrigins,
+	CfgRPCCORSAllowedHeaders,
+	CfgRPCReadyMaxBlockLag,
+	CfgRPCReadyMinPeers,
+	CfgRPCSupplyLockedAddresses,
+	CfgMempoolMinTxFees,
+}
+
+var (
+	reloadMu        = &sync.Mutex{}
+	reloadListeners = make(map[string][]func() error)
+
+	// overriddenConfigKeys are the keys set by the environment variables and the --config-override
+	// flags, which take precedence over the config file on reload as well
+	overriddenConfigKeys = make(map[string]bool)
+
+	// reloadedValues are the values of the reloadable keys changed by a reload, keyed by the lower
+	// case keys, which take precedence over the values in viper
+	reloadedMu     = &sync.RWMutex{}
+	reloadedValues = make(map[string]interface{})
+)
+
+// ConfigReloadResult lists the keys changed in the config file since it was last read
+type ConfigReloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// OnConfigReload registers a function called after the reloadable key is changed by a reload, for
+// the components that keep a copy of the config value
+func OnConfigReload(key string, listener func() error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadListeners[key] = append(reloadListeners[key], listener)
+}
+
+// ReloadConfig reads the config file again, and applies the changes of the reloadable keys. The
+// changes of the other keys are reported but not applied, they take effect on the next restart.
+// Nothing is applied if any of the reloadable values is invalid.
+func ReloadConfig() (*ConfigReloadResult, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		return nil, errors.New("No config file to reload")
+	}
+	fileConfig := viper.New()
+	fileConfig.SetConfigFile(configFile)
+	if err := fileConfig.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("Failed to read config file %v: %v", configFile, err)
+	}
+
+	result := &ConfigReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+
+	changes := make(map[string]interface{})
+	for _, key := range ReloadableConfigKeys {
+		lkey := strings.ToLower(key)
+		if overriddenConfigKeys[lkey] {
+			continue
+		}
+		value := configSchema[lkey] // removed from the file, back to the default
+		if fileConfig.IsSet(key) {
+			value = fileConfig.Get(key)
+		}
+		if err := checkConfigValue(key, configSchema[lkey], value); err != nil {
+			return nil, err
+		}
+		if fmt.Sprint(value) != fmt.Sprint(getReloadable(key)) {
+			changes[key] = value
+		}
+	}
+
+	for _, key := range fileConfig.AllKeys() {
+		if overriddenConfigKeys[key] || isReloadableConfigKey(key) || !isKnownConfigKey(key) {
+			continue
+		}
+		if fmt.Sprint(fileConfig.Get(key)) != fmt.Sprint(viper.Get(key)) {
+			result.RestartRequired = append(result.RestartRequired, key)
+		}
+	}
+	sort.Strings(result.RestartRequired)
+
+	reloadedMu.Lock()
+	for _, key := range ReloadableConfigKeys {
+		if value, ok := changes[key]; ok {
+			reloadedValues[strings.ToLower(key)] = value
+			result.Applied = append(result.Applied, key)
+		}
+	}
+	reloadedMu.Unlock()
+
+	errs := []string{}
+	for _, key := range result.Applied {
+		for _, listener := range reloadListeners[key] {
+			if err := listener(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("Failed to apply the reloaded config: %v", strings.Join(errs, "; "))
+	}
+	return result, nil
+}
+
+// isReloadableConfigKey returns whether the key is reloadable, or is an entry of a reloadable map
+// key, e.g. mempool.minTxFees.send
+func isReloadableConfigKey(key string) bool {
+	for _, reloadable := range ReloadableConfigKeys {
+		reloadable = strings.ToLower(reloadable)
+		if key == reloadable || strings.HasPrefix(key, reloadable+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// getReloadable returns the current value of the reloadable key
+func getReloadable(key string) interface{} {
+	reloadedMu.RLock()
+	defer reloadedMu.RUnlock()
+	if value, ok := reloadedValues[strings.ToLower(key)]; ok {
+		return value
+	}
+	return viper.Get(key)
+}
+
+// GetReloadableString returns the current value of the reloadable key as a string
+func GetReloadableString(key string) string {
+	return cast.ToString(getReloadable(key))
+}
+
+// GetReloadableInt returns the current value of the reloadable key as an int
+func GetReloadableInt(key string) int {
+	return cast.ToInt(getReloadable(key))
+}
+
+// GetReloadableInt64 returns the current value of the reloadable key as an int64
+func GetReloadableInt64(key string) int64 {
+	return cast.ToInt64(getReloadable(key))
+}
+
+// GetReloadableStringSlice returns the current value of the reloadable key as a string slice
+func GetReloadableStringSlice(key string) []string {
+	return cast.ToStringSlice(getReloadable(key))
+}
+
+// GetReloadableStringMapString returns the current value of the reloadable key as a string map
+func GetReloadableStringMapString(key string) map[string]string {
+	return cast.ToStringMapString(getReloadable(key))
+}
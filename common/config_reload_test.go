@@ -0,0 +1,58 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "config_reload_test")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "config.yaml")
+
+	assert.Nil(ioutil.WriteFile(configFile, []byte("p2p:\n  port: 12000\n  maxNumPeersToBroadcast: 20\n"), 0600))
+	viper.SetConfigFile(configFile)
+	assert.Nil(viper.ReadInConfig())
+	assert.Equal(20, GetReloadableInt(CfgP2PMaxNumPeersToBroadcast))
+
+	maxNumPeersToBroadcast := 0
+	OnConfigReload(CfgP2PMaxNumPeersToBroadcast, func() error {
+		maxNumPeersToBroadcast = GetReloadableInt(CfgP2PMaxNumPeersToBroadcast)
+		return nil
+	})
+
+	// The peer limits are read once by the p2p layer, so they require a restart
+	assert.Nil(ioutil.WriteFile(configFile, []byte("p2p:\n  port: 13000\n  maxNumPeers: 40\n  maxNumPeersToBroadcast: 40\nrpc:\n  readyMinPeers: 10\n"), 0600))
+	reloaded, err := ReloadConfig()
+	assert.Nil(err)
+	assert.Equal([]string{CfgP2PMaxNumPeersToBroadcast, CfgRPCReadyMinPeers}, reloaded.Applied)
+	assert.Equal([]string{"p2p.maxnumpeers", "p2p.port"}, reloaded.RestartRequired)
+	assert.Equal(40, GetReloadableInt(CfgP2PMaxNumPeersToBroadcast))
+	assert.Equal(10, GetReloadableInt(CfgRPCReadyMinPeers))
+	assert.Equal(12000, viper.GetInt(CfgP2PPort))
+	assert.Equal(40, maxNumPeersToBroadcast)
+
+	// The invalid values are not applied
+	assert.Nil(ioutil.WriteFile(configFile, []byte("p2p:\n  maxNumPeersToBroadcast: many\nrpc:\n  readyMinPeers: 5\n"), 0600))
+	_, err = ReloadConfig()
+	assert.NotNil(err)
+	assert.Equal(40, GetReloadableInt(CfgP2PMaxNumPeersToBroadcast))
+	assert.Equal(10, GetReloadableInt(CfgRPCReadyMinPeers))
+
+	// The overrides take precedence
+	SetConfigOverride(CfgP2PMaxNumPeersToBroadcast, "50")
+	assert.Equal(50, GetReloadableInt(CfgP2PMaxNumPeersToBroadcast))
+	assert.Nil(ioutil.WriteFile(configFile, []byte("p2p:\n  maxNumPeersToBroadcast: 60\nrpc:\n  readyMinPeers: 10\n"), 0600))
+	reloaded, err = ReloadConfig()
+	assert.Nil(err)
+	assert.Equal(0, len(reloaded.Applied))
+	assert.Equal(50, GetReloadableInt(CfgP2PMaxNumPeersToBroadcast))
+}
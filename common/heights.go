@@ -30,6 +30,80 @@ const HeightTxWrapperExtension uint64 = 12749952
 // HeightSupportThetaTokenInSmartContract specifies the block height to support Theta in smart contracts
 const HeightSupportThetaTokenInSmartContract uint64 = 13123789 // approximate time: 5pm Dec 4, 2021 PT
 
+// HeightEnableValidatorVoteAggregation specifies the minimal block height to aggregate the validator votes with BLS signatures
+const HeightEnableValidatorVoteAggregation uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableProposerSchedule specifies the minimal block height to select the proposers with the stake-weighted round robin schedule
+const HeightEnableProposerSchedule uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableBlockLimits specifies the minimal block height to enforce the limits on the total gas and size of the block transactions
+const HeightEnableBlockLimits uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableEVMForkLevel specifies the minimal block height to follow the EVM semantics (opcodes, gas costs, and gas refunds)
+// of the Istanbul hard fork, on the chains without their own EVM fork schedule
+const HeightEnableEVMForkLevel uint64 = 1<<64 - 1 // not scheduled yet
+
+// EVMForkHeights specifies the minimal block heights to follow the EVM semantics of the Byzantium, Constantinople, and
+// Istanbul hard forks. A fork is skipped if its height is not lower than the height of the later forks.
+type EVMForkHeights struct {
+	Byzantium      uint64
+	Constantinople uint64
+	Istanbul       uint64
+}
+
+// evmForkSchedules specifies the EVM fork heights of the chains which do not switch to Istanbul at HeightEnableEVMForkLevel
+var evmForkSchedules = map[string]EVMForkHeights{}
+
+// GetEVMForkHeights returns the EVM fork heights of the given chain
+func GetEVMForkHeights(chainID string) EVMForkHeights {
+	if heights, ok := evmForkSchedules[chainID]; ok {
+		return heights
+	}
+	return EVMForkHeights{
+		Byzantium:      HeightEnableEVMForkLevel,
+		Constantinople: HeightEnableEVMForkLevel,
+		Istanbul:       HeightEnableEVMForkLevel,
+	}
+}
+
+// EarliestHeight returns the height of the earliest EVM fork
+func (heights EVMForkHeights) EarliestHeight() uint64 {
+	earliest := heights.Byzantium
+	if heights.Constantinople < earliest {
+		earliest = heights.Constantinople
+	}
+	if heights.Istanbul < earliest {
+		earliest = heights.Istanbul
+	}
+	return earliest
+}
+
+// HeightEnableStakingQueryPrecompile specifies the minimal block height to enable the precompiled contract for smart contracts
+// to query the validator/guardian stakes and the stake reward distribution
+const HeightEnableStakingQueryPrecompile uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableThetaTransferV2 specifies the minimal block height to enable the precompiled contract for smart contracts to
+// transfer both Theta and TFuel, and to forbid the token transfers in static calls and through delegatecall/callcode
+const HeightEnableThetaTransferV2 uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableMultisig specifies the minimal block height to accept the M-of-N multisig signatures of the tx inputs
+const HeightEnableMultisig uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableVesting specifies the minimal block height to accept the VestingTx, which creates time-locked account balances
+const HeightEnableVesting uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableValidatorKeyRotation specifies the minimal block height to accept the RotateValidatorKeyTx, which switches
+// the key a validator signs the votes and proposals with
+const HeightEnableValidatorKeyRotation uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableBaseFee specifies the minimal block height to enforce the base fee on the smart contract transactions, which
+// adjusts with the gas usage of the recent blocks
+const HeightEnableBaseFee uint64 = 1<<64 - 1 // not scheduled yet
+
+// HeightEnableFeeBurnTracking specifies the minimal block height to track the cumulative TFuel burned by the transaction
+// fees in the ledger state
+const HeightEnableFeeBurnTracking uint64 = 1<<64 - 1 // not scheduled yet
+
 // CheckpointInterval defines the interval between checkpoints.
 const CheckpointInterval = int64(100)
 
@@ -0,0 +1,73 @@
+// Package prometheus exports the metrics of a registry in the Prometheus text exposition format
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/thetatoken/theta/common/metrics"
+)
+
+// quantiles reported for the histograms and the timers
+var quantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+
+// Handler returns an HTTP handler serving the metrics of the registry to the Prometheus scrapers
+func Handler(reg metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write(Export(reg))
+	})
+}
+
+// Export returns the metrics of the registry in the Prometheus text format, sorted by the names
+func Export(reg metrics.Registry) []byte {
+	names := []string{}
+	reg.Each(func(name string, i interface{}) {
+		names = append(names, name)
+	})
+	sort.Strings(names)
+
+	buff := &bytes.Buffer{}
+	for _, name := range names {
+		key := mutateKey(name)
+		switch m := reg.Get(name).(type) {
+		case metrics.Counter:
+			writeMetric(buff, key, "counter", float64(m.Snapshot().Count()))
+		case metrics.Gauge:
+			writeMetric(buff, key, "gauge", float64(m.Snapshot().Value()))
+		case metrics.GaugeFloat64:
+			writeMetric(buff, key, "gauge", m.Snapshot().Value())
+		case metrics.Meter:
+			writeMetric(buff, key, "counter", float64(m.Snapshot().Count()))
+		case metrics.Histogram:
+			s := m.Snapshot()
+			writeSummary(buff, key, s.Count(), float64(s.Sum()), s.Percentiles(quantiles))
+		case metrics.Timer:
+			s := m.Snapshot()
+			writeSummary(buff, key, s.Count(), float64(s.Sum()), s.Percentiles(quantiles))
+		}
+	}
+	return buff.Bytes()
+}
+
+func writeMetric(buff *bytes.Buffer, key, typ string, value float64) {
+	fmt.Fprintf(buff, "# TYPE %s %s\n", key, typ)
+	fmt.Fprintf(buff, "%s %v\n", key, value)
+}
+
+func writeSummary(buff *bytes.Buffer, key string, count int64, sum float64, values []float64) {
+	fmt.Fprintf(buff, "# TYPE %s summary\n", key)
+	for i, quantile := range quantiles {
+		fmt.Fprintf(buff, "%s{quantile=\"%v\"} %v\n", key, quantile, values[i])
+	}
+	fmt.Fprintf(buff, "%s_sum %v\n", key, sum)
+	fmt.Fprintf(buff, "%s_count %v\n", key, count)
+}
+
+// mutateKey converts a metric name, e.g. p2p/channel/3/send/queued, into a valid Prometheus name
+func mutateKey(key string) string {
+	return strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(key)
+}
@@ -0,0 +1,32 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common/metrics"
+)
+
+func TestExport(t *testing.T) {
+	assert := assert.New(t)
+
+	metrics.Enabled = true
+	defer func() { metrics.Enabled = false }()
+
+	reg := metrics.NewRegistry()
+	metrics.NewRegisteredCounter("p2p/channel/3/send/dropped", reg).Inc(2)
+	metrics.NewRegisteredGauge("p2p/channel/3/send/queued", reg).Update(5)
+	histogram := metrics.NewRegisteredHistogram("ledger/apply-time", reg, metrics.NewUniformSample(100))
+	histogram.Update(10)
+	histogram.Update(20)
+
+	lines := strings.Split(strings.TrimSpace(string(Export(reg))), "\n")
+	assert.Contains(lines, "# TYPE p2p_channel_3_send_dropped counter")
+	assert.Contains(lines, "p2p_channel_3_send_dropped 2")
+	assert.Contains(lines, "# TYPE p2p_channel_3_send_queued gauge")
+	assert.Contains(lines, "p2p_channel_3_send_queued 5")
+	assert.Contains(lines, "# TYPE ledger_apply_time summary")
+	assert.Contains(lines, "ledger_apply_time_sum 30")
+	assert.Contains(lines, "ledger_apply_time_count 2")
+}
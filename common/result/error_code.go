@@ -1,5 +1,7 @@
 package result
 
+import "fmt"
+
 type ErrorCode int
 
 const (
@@ -13,6 +15,14 @@ const (
 	CodeEmptyPubKeyWithSequence1 ErrorCode = 100004
 	CodeUnauthorizedTx           ErrorCode = 100005
 	CodeInvalidFee               ErrorCode = 100006
+	CodeInvalidTxFormat          ErrorCode = 100007
+	CodeUnknownTxType            ErrorCode = 100008
+	CodeTxTypeNotSupported       ErrorCode = 100009
+	CodeUnknownAccount           ErrorCode = 100010
+	CodeDuplicatedAddress        ErrorCode = 100011
+	CodeTooManyAccounts          ErrorCode = 100012
+	CodeInvalidTxAmount          ErrorCode = 100013
+	CodeDuplicateTx              ErrorCode = 100014
 
 	// ReserveFund Errors
 	CodeReserveFundCheckFailed   ErrorCode = 101001
@@ -41,4 +51,78 @@ const (
 	CodeInsufficientStake       ErrorCode = 106003
 	CodeNotEnoughBalanceToStake ErrorCode = 106004
 	CodeStakeExceedsCap         ErrorCode = 106005
+
+	// Block Errors
+	CodeBlockLimitsExceeded ErrorCode = 107001
+
+	// Vesting Errors
+	CodeInvalidVestingSchedule ErrorCode = 108001
+	CodeLockedFund             ErrorCode = 108002
+
+	// Validator Key Rotation Errors
+	CodeNotValidatorStakeHolder   ErrorCode = 109001
+	CodeInvalidSigningKey         ErrorCode = 109002
+	CodeInvalidActivationHeight   ErrorCode = 109003
+	CodeSigningKeyRotationPending ErrorCode = 109004
 )
+
+var errorCodeNames = map[ErrorCode]string{
+	CodeOK: "OK",
+
+	CodeGenericError:             "GenericError",
+	CodeInvalidSignature:         "InvalidSignature",
+	CodeInvalidSequence:          "InvalidSequence",
+	CodeInsufficientFund:         "InsufficientFund",
+	CodeEmptyPubKeyWithSequence1: "EmptyPubKeyWithSequence1",
+	CodeUnauthorizedTx:           "UnauthorizedTx",
+	CodeInvalidFee:               "InvalidFee",
+	CodeInvalidTxFormat:          "InvalidTxFormat",
+	CodeUnknownTxType:            "UnknownTxType",
+	CodeTxTypeNotSupported:       "TxTypeNotSupported",
+	CodeUnknownAccount:           "UnknownAccount",
+	CodeDuplicatedAddress:        "DuplicatedAddress",
+	CodeTooManyAccounts:          "TooManyAccounts",
+	CodeInvalidTxAmount:          "InvalidTxAmount",
+	CodeDuplicateTx:              "DuplicateTx",
+
+	CodeReserveFundCheckFailed:   "ReserveFundCheckFailed",
+	CodeReservedFundNotSpecified: "ReservedFundNotSpecified",
+	CodeInvalidFundToReserve:     "InvalidFundToReserve",
+
+	CodeReleaseFundCheckFailed: "ReleaseFundCheckFailed",
+
+	CodeCheckTransferReservedFundFailed: "CheckTransferReservedFundFailed",
+
+	CodeUnauthorizedToUpdateSplitRule: "UnauthorizedToUpdateSplitRule",
+
+	CodeEVMError:               "EVMError",
+	CodeInvalidValueToTransfer: "InvalidValueToTransfer",
+	CodeInvalidGasPrice:        "InvalidGasPrice",
+	CodeFeeLimitTooHigh:        "FeeLimitTooHigh",
+	CodeInvalidGasLimit:        "InvalidGasLimit",
+
+	CodeInvalidStakePurpose:     "InvalidStakePurpose",
+	CodeInvalidStake:            "InvalidStake",
+	CodeInsufficientStake:       "InsufficientStake",
+	CodeNotEnoughBalanceToStake: "NotEnoughBalanceToStake",
+	CodeStakeExceedsCap:         "StakeExceedsCap",
+
+	CodeBlockLimitsExceeded: "BlockLimitsExceeded",
+
+	CodeInvalidVestingSchedule: "InvalidVestingSchedule",
+	CodeLockedFund:             "LockedFund",
+
+	CodeNotValidatorStakeHolder:   "NotValidatorStakeHolder",
+	CodeInvalidSigningKey:         "InvalidSigningKey",
+	CodeInvalidActivationHeight:   "InvalidActivationHeight",
+	CodeSigningKeyRotationPending: "SigningKeyRotationPending",
+}
+
+// String returns the name of the error code. The names are stable, and can be used by the
+// clients in place of the numeric codes.
+func (code ErrorCode) String() string {
+	if name, ok := errorCodeNames[code]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(code))
+}
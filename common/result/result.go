@@ -38,6 +38,14 @@ func (res Result) WithMessage(message string) Result {
 	return res
 }
 
+// Err converts the result to an error carrying the error code, or returns nil if the result is OK
+func (res Result) Err() error {
+	if res.IsOK() {
+		return nil
+	}
+	return &CodedError{Code: res.Code, Message: res.Message}
+}
+
 // -------------- Constructors -------------- //
 
 // OK represents the success result
@@ -67,3 +75,29 @@ func Error(msgFormat string, a ...interface{}) Result {
 		Info:    make(Info),
 	}
 }
+
+// -------------- Errors -------------- //
+
+// CodedError is an error with an error code, so that the callers can tell the errors
+// apart without parsing the messages
+type CodedError struct {
+	Code    ErrorCode
+	Message string
+}
+
+// Error implements the error interface
+func (err *CodedError) Error() string {
+	return err.Message
+}
+
+// ErrorCodeOf returns the error code of the error, or CodeGenericError if the error
+// does not carry an error code
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return CodeOK
+	}
+	if codedErr, ok := err.(*CodedError); ok {
+		return codedErr.Code
+	}
+	return CodeGenericError
+}
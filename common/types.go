@@ -409,6 +409,9 @@ const (
 
 	// ChannelIDAggregatedEliteEdgeNodeVotes indicates the channel for Elite Edge Node aggregated vote messages
 	ChannelIDAggregatedEliteEdgeNodeVotes
+
+	// ChannelIDSnapshot indicates the channel for state sync snapshot offers and chunks
+	ChannelIDSnapshot
 )
 
 // P2POptEnum defines the p2p network
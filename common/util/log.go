@@ -3,14 +3,25 @@ package util
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/spf13/viper"
 	"github.com/thetatoken/theta/common"
 )
 
 var logLevels map[string]string
 
+// moduleLoggers are the loggers returned by GetLoggerForModule, whose levels are updated when the
+// log levels are reloaded
+var (
+	moduleLoggersMu = &sync.Mutex{}
+	moduleLoggers   = make(map[string][]*log.Logger)
+)
+
+func init() {
+	common.OnConfigReload(common.CfgLogLevels, ReloadLogLevels)
+}
+
 const (
 	panicLevel = "panic"
 	fatalLevel = "fatal"
@@ -22,8 +33,8 @@ const (
 const defaultLevel = warnLevel
 
 func InitLog() {
-	logLevels = parseLogLevelConfig(viper.GetString(common.CfgLogLevels))
-	log.Infof("Log settings: %v, %v", logLevels, viper.GetString(common.CfgLogLevels))
+	logLevels = parseLogLevelConfig(common.GetReloadableString(common.CfgLogLevels))
+	log.Infof("Log settings: %v, %v", logLevels, common.GetReloadableString(common.CfgLogLevels))
 	if logLevels["*"] == panicLevel {
 		log.SetLevel(log.PanicLevel)
 	} else if logLevels["*"] == fatalLevel {
@@ -39,14 +50,49 @@ func InitLog() {
 	}
 }
 
+// ReloadLogLevels applies the log levels of the config to the global logger and the module
+// loggers already created
+func ReloadLogLevels() error {
+	levels, err := parseLogLevels(common.GetReloadableString(common.CfgLogLevels))
+	if err != nil {
+		return err
+	}
+
+	moduleLoggersMu.Lock()
+	defer moduleLoggersMu.Unlock()
+
+	logLevels = levels
+	log.SetLevel(log.DebugLevel)
+	setLoggerLevel(log.StandardLogger(), logLevels["*"])
+	for module, loggers := range moduleLoggers {
+		level, ok := logLevels[module]
+		if !ok {
+			level = logLevels["*"]
+		}
+		for _, logger := range loggers {
+			setLoggerLevel(logger, level)
+		}
+	}
+	log.Infof("Reloaded log settings: %v", logLevels)
+	return nil
+}
+
 func parseLogLevelConfig(config string) map[string]string {
+	levels, err := parseLogLevels(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return levels
+}
+
+func parseLogLevels(config string) (map[string]string, error) {
 	levels := make(map[string]string)
 
 	moduleAndLevels := strings.Split(config, ",")
 	for _, moduleAndLevel := range moduleAndLevels {
 		tokens := strings.Split(moduleAndLevel, ":")
 		if len(tokens) != 2 {
-			panic(fmt.Sprintf("Failed to parse module log level: \"%v\"", moduleAndLevel))
+			return nil, fmt.Errorf("Failed to parse module log level: \"%v\"", moduleAndLevel)
 		}
 		levels[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
 	}
@@ -54,7 +100,7 @@ func parseLogLevelConfig(config string) map[string]string {
 	if _, ok := levels["*"]; !ok {
 		levels["*"] = defaultLevel
 	}
-	return levels
+	return levels, nil
 }
 
 // GetLoggerForModule returns the logger for given module.
@@ -68,11 +114,21 @@ func GetLoggerForModule(module string) *log.Entry {
 	logger := log.New()
 	logger.Formatter = customFormatter
 
+	moduleLoggersMu.Lock()
+	defer moduleLoggersMu.Unlock()
+
 	level, ok := logLevels[module]
 	if !ok {
 		level = logLevels["*"]
 	}
+	setLoggerLevel(logger, level)
+	moduleLoggers[module] = append(moduleLoggers[module], logger)
 
+	return logger.WithFields(log.Fields{"prefix": module})
+}
+
+// setLoggerLevel sets the level of the logger, an unknown level leaves the logger unchanged
+func setLoggerLevel(logger *log.Logger, level string) {
 	if level == panicLevel {
 		logger.SetLevel(log.PanicLevel)
 	} else if level == fatalLevel {
@@ -86,6 +142,4 @@ func GetLoggerForModule(module string) *log.Entry {
 	} else if level == debugLevel {
 		logger.SetLevel(log.DebugLevel)
 	}
-
-	return logger.WithFields(log.Fields{"prefix": module})
 }
@@ -0,0 +1,215 @@
+package consensus
+
+import (
+	"context"
+	"encoding/binary"
+	"math/big"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/metrics"
+)
+
+var clockSkewGauge = metrics.NewRegisteredGauge("consensus/clock/skew", nil) // in milliseconds
+
+const (
+	numClockSkewSamples    = 64
+	minClockSkewSamples    = 5
+	ntpQueryInterval       = 10 * time.Minute
+	ntpQueryTimeout        = 5 * time.Second
+	ntpResultMaxAge        = 3 * ntpQueryInterval
+	ntpEpochOffset         = 2208988800 // seconds between 1900, the NTP epoch, and 1970
+	clockSkewWarnInterval  = time.Minute
+	blockTimestampRounding = 500 * time.Millisecond // the block timestamps are truncated to seconds
+)
+
+// The sources of the clock skew estimate
+const (
+	ClockSkewSourceNone  = ""
+	ClockSkewSourcePeers = "peers"
+	ClockSkewSourceNTP   = "ntp"
+)
+
+// ClockSkew is the estimated skew of the local clock, positive if the local clock is ahead
+type ClockSkew struct {
+	Skew       time.Duration
+	Source     string
+	NumSamples int // number of the block samples
+}
+
+//
+// ClockSkewMonitor estimates the skew of the local clock, since the votes and the block timestamps
+// depend on the wall clock. The peers are sampled through the timestamps of the blocks proposed in
+// the current epoch, compared to the time they are received, and the median of the recent samples
+// is taken. The estimate includes the propagation delay of the blocks, i.e. it leans towards the
+// local clock being ahead. If an NTP server is configured, it is queried periodically and preferred
+// over the peers.
+//
+type ClockSkewMonitor struct {
+	mu      *sync.Mutex
+	samples []time.Duration // the clock of the proposers minus the local clock
+	next    int
+
+	ntpServer    string
+	ntpSkew      time.Duration
+	ntpUpdatedAt time.Time
+
+	maxSkew    time.Duration
+	lastWarned time.Time
+}
+
+// NewClockSkewMonitor creates a ClockSkewMonitor with the configured NTP server and threshold
+func NewClockSkewMonitor() *ClockSkewMonitor {
+	return &ClockSkewMonitor{
+		mu:        &sync.Mutex{},
+		samples:   []time.Duration{},
+		ntpServer: viper.GetString(common.CfgClockNTPServer),
+		maxSkew:   time.Duration(viper.GetInt64(common.CfgClockMaxSkewMs)) * time.Millisecond,
+	}
+}
+
+// Start starts querying the NTP server, if any
+func (m *ClockSkewMonitor) Start(ctx context.Context, wg *sync.WaitGroup) {
+	if m.ntpServer == "" {
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			m.queryNTP()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ntpQueryInterval):
+			}
+		}
+	}()
+}
+
+func (m *ClockSkewMonitor) queryNTP() {
+	offset, err := queryNTPOffset(m.ntpServer)
+	if err != nil {
+		logger.Warnf("Failed to query the NTP server %v: %v", m.ntpServer, err)
+		return
+	}
+
+	m.mu.Lock()
+	m.ntpSkew = -offset
+	m.ntpUpdatedAt = time.Now()
+	m.mu.Unlock()
+
+	m.checkSkew()
+}
+
+// AddBlockSample records the timestamp of a block proposed by a peer in the current epoch, and
+// the local time the block is received at
+func (m *ClockSkewMonitor) AddBlockSample(timestamp *big.Int, receivedAt time.Time) {
+	if timestamp == nil {
+		return
+	}
+	proposedAt := time.Unix(timestamp.Int64(), 0).Add(blockTimestampRounding)
+
+	m.mu.Lock()
+	if len(m.samples) < numClockSkewSamples {
+		m.samples = append(m.samples, proposedAt.Sub(receivedAt))
+	} else {
+		m.samples[m.next] = proposedAt.Sub(receivedAt)
+	}
+	m.next = (m.next + 1) % numClockSkewSamples
+	m.mu.Unlock()
+
+	m.checkSkew()
+}
+
+// Skew returns the current estimate of the skew of the local clock
+func (m *ClockSkewMonitor) Skew() ClockSkew {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := ClockSkew{NumSamples: len(m.samples)}
+	if !m.ntpUpdatedAt.IsZero() && time.Since(m.ntpUpdatedAt) < ntpResultMaxAge {
+		result.Skew = m.ntpSkew
+		result.Source = ClockSkewSourceNTP
+	} else if len(m.samples) >= minClockSkewSamples {
+		sorted := make([]time.Duration, len(m.samples))
+		copy(sorted, m.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		result.Skew = -sorted[len(sorted)/2]
+		result.Source = ClockSkewSourcePeers
+	}
+	return result
+}
+
+// Exceeded returns whether the estimated skew exceeds the threshold
+func (m *ClockSkewMonitor) Exceeded() bool {
+	skew := m.Skew()
+	return m.maxSkew > 0 && skew.Source != ClockSkewSourceNone && absDuration(skew.Skew) > m.maxSkew
+}
+
+// checkSkew updates the metrics, and logs a warning if the skew exceeds the threshold
+func (m *ClockSkewMonitor) checkSkew() {
+	skew := m.Skew()
+	clockSkewGauge.Update(int64(skew.Skew / time.Millisecond))
+	if !m.Exceeded() {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Since(m.lastWarned) < clockSkewWarnInterval {
+		return
+	}
+	m.lastWarned = time.Now()
+	logger.Warnf("The local clock is off by %v according to the %v, above the max skew of %v, please check the time synchronization of the host",
+		skew.Skew, skew.Source, m.maxSkew)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// queryNTPOffset returns the offset of the NTP server clock to the local clock, with the SNTP
+// protocol (RFC 4330)
+func queryNTPOffset(server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+	conn, err := net.DialTimeout("udp", server, ntpQueryTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(ntpQueryTimeout))
+
+	request := make([]byte, 48)
+	request[0] = 0x23 // LI = 0, VN = 4, Mode = 3 (client)
+	sentAt := time.Now()
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+	response := make([]byte, 48)
+	if _, err := conn.Read(response); err != nil {
+		return 0, err
+	}
+	receivedAt := time.Now()
+
+	serverReceivedAt := ntpTime(response[32:40])
+	serverSentAt := ntpTime(response[40:48])
+	return (serverReceivedAt.Sub(sentAt) + serverSentAt.Sub(receivedAt)) / 2, nil
+}
+
+// ntpTime converts the 64-bit NTP timestamp into time
+func ntpTime(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b[0:4])) - ntpEpochOffset
+	fraction := int64(binary.BigEndian.Uint32(b[4:8]))
+	return time.Unix(seconds, (fraction*1e9)>>32)
+}
@@ -0,0 +1,59 @@
+package consensus
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClockSkewMonitor(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &ClockSkewMonitor{
+		mu:      &sync.Mutex{},
+		samples: []time.Duration{},
+		maxSkew: time.Second,
+	}
+	assert.Equal(ClockSkewSourceNone, m.Skew().Source)
+	assert.False(m.Exceeded())
+
+	// The local clock is 3 seconds ahead of the proposers, the blocks take 0.5 second to propagate
+	now := time.Unix(100000, 0)
+	for i := 0; i < minClockSkewSamples; i++ {
+		receivedAt := now.Add(time.Duration(i) * 6 * time.Second)
+		proposedAt := receivedAt.Add(-3 * time.Second).Add(-500 * time.Millisecond)
+		m.AddBlockSample(big.NewInt(proposedAt.Unix()), receivedAt)
+	}
+	skew := m.Skew()
+	assert.Equal(ClockSkewSourcePeers, skew.Source)
+	assert.Equal(minClockSkewSamples, skew.NumSamples)
+	assert.Equal(3500*time.Millisecond, skew.Skew)
+	assert.True(m.Exceeded())
+
+	// A recent NTP measurement is preferred
+	m.ntpSkew = -200 * time.Millisecond
+	m.ntpUpdatedAt = time.Now()
+	skew = m.Skew()
+	assert.Equal(ClockSkewSourceNTP, skew.Source)
+	assert.Equal(-200*time.Millisecond, skew.Skew)
+	assert.False(m.Exceeded())
+
+	// Only the recent samples are kept
+	for i := 0; i < 2*numClockSkewSamples; i++ {
+		m.AddBlockSample(big.NewInt(now.Unix()), now)
+	}
+	assert.Equal(numClockSkewSamples, len(m.samples))
+}
+
+func TestNTPTime(t *testing.T) {
+	assert := assert.New(t)
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[0:4], uint32(ntpEpochOffset+1500000000))
+	binary.BigEndian.PutUint32(b[4:8], 1<<31) // half a second
+	assert.Equal(time.Unix(1500000000, 500000000), ntpTime(b))
+}
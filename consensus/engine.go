@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"path"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -28,11 +30,16 @@ var logger = log.WithFields(log.Fields{"prefix": "consensus"})
 
 var _ core.ConsensusEngine = (*ConsensusEngine)(nil)
 
+// maxStateRecoveryDepth is the max number of blocks to rewind when recovering from a partially committed block
+const maxStateRecoveryDepth = 256
+
 // ConsensusEngine is the default implementation of the Engine interface.
 type ConsensusEngine struct {
 	logger *log.Entry
 
 	privateKey *crypto.PrivateKey
+	signer     core.Signer
+	blsKey     *bls.SecretKey
 
 	chain            *blockchain.Chain
 	dispatcher       *dispatcher.Dispatcher
@@ -59,6 +66,17 @@ type ConsensusEngine struct {
 	voteTimerReady bool
 	blockProcessed bool
 
+	consecutiveEpochTimeouts int // number of consecutive epoch timeouts without finalizing any block
+
+	journal *Journal // optional recorder of the consensus events
+
+	clockSkew *ClockSkewMonitor
+
+	// Manual driving, see manual.go
+	manual       bool
+	clock        func() time.Time
+	selfMessages []interface{}
+
 	state *State
 }
 
@@ -69,6 +87,7 @@ func NewConsensusEngine(privateKey *crypto.PrivateKey, db store.Store, chain *bl
 		dispatcher: dispatcher,
 
 		privateKey: privateKey,
+		signer:     core.NewLocalSigner(privateKey),
 
 		incoming:        make(chan interface{}, viper.GetInt(common.CfgConsensusMessageQueueSize)),
 		finalizedBlocks: make(chan *core.Block, viper.GetInt(common.CfgConsensusMessageQueueSize)),
@@ -82,6 +101,8 @@ func NewConsensusEngine(privateKey *crypto.PrivateKey, db store.Store, chain *bl
 
 		voteTimerReady: false,
 		blockProcessed: false,
+
+		clockSkew: NewClockSkewMonitor(),
 	}
 
 	logger = util.GetLoggerForModule("consensus")
@@ -91,9 +112,23 @@ func NewConsensusEngine(privateKey *crypto.PrivateKey, db store.Store, chain *bl
 	if err != nil {
 		e.logger.Panic(err)
 	}
+	e.blsKey = blsKey
 	e.guardian = NewGuardianEngine(e, blsKey)
 	e.eliteEdgeNode = NewEliteEdgeNodeEngine(e, blsKey)
 
+	if viper.GetBool(common.CfgConsensusJournalEnabled) {
+		journalPath := viper.GetString(common.CfgConsensusJournalPath)
+		if journalPath == "" {
+			journalPath = path.Join(filepath.Dir(viper.ConfigFileUsed()), "consensus_journal")
+		}
+		maxSize := int64(viper.GetInt(common.CfgConsensusJournalMaxSizeMB)) * 1024 * 1024
+		e.journal, err = OpenJournal(journalPath, maxSize)
+		if err != nil {
+			e.logger.Fatalf("Failed to open consensus journal %v: %v", journalPath, err)
+		}
+		e.logger.Infof("Recording consensus events to %v", journalPath)
+	}
+
 	e.logger.WithFields(log.Fields{"state": e.state}).Info("Starting state")
 
 	return e
@@ -108,9 +143,10 @@ func (e *ConsensusEngine) GetLedger() core.Ledger {
 	return e.ledger
 }
 
-// ID returns the identifier of current node.
+// ID returns the identifier of current node, which is the address of the key the votes and the
+// proposals are signed with.
 func (e *ConsensusEngine) ID() string {
-	return e.privateKey.PublicKey().Address().Hex()
+	return e.signer.PublicKey().Address().Hex()
 }
 
 // PrivateKey returns the private key
@@ -118,11 +154,27 @@ func (e *ConsensusEngine) PrivateKey() *crypto.PrivateKey {
 	return e.privateKey
 }
 
+// SetSigner replaces the signer of the votes and the proposals, e.g. with a remote signer. It needs to
+// be called before the engine starts.
+func (e *ConsensusEngine) SetSigner(signer core.Signer) {
+	e.signer = signer
+}
+
+// Signer returns the signer of the votes and the proposals
+func (e *ConsensusEngine) Signer() core.Signer {
+	return e.signer
+}
+
 // Chain return a pointer to the underlying chain store.
 func (e *ConsensusEngine) Chain() *blockchain.Chain {
 	return e.chain
 }
 
+// FindBlock returns the block with the given hash.
+func (e *ConsensusEngine) FindBlock(blockHash common.Hash) (*core.ExtendedBlock, error) {
+	return e.chain.FindBlock(blockHash)
+}
+
 // GetEpoch returns the current epoch
 func (e *ConsensusEngine) GetEpoch() uint64 {
 	return e.state.GetEpoch()
@@ -148,13 +200,14 @@ func (e *ConsensusEngine) Start(ctx context.Context) {
 	}
 
 	// Set ledger state pointer to initial state.
-	lastCC := e.autoRewind(e.state.GetHighestCCBlock())
+	lastCC := e.autoRewind(e.recoverState(e.state.GetHighestCCBlock()))
 	//e.ledger.ResetState(lastCC.Height, lastCC.StateHash)
 	e.ledger.ResetState(lastCC.Block)
 
 	e.resetGuardianTimer()
 	e.guardian.Start(e.ctx)
 	e.eliteEdgeNode.Start(e.ctx)
+	e.clockSkew.Start(e.ctx, e.wg)
 
 	e.checkSyncStatus()
 
@@ -215,8 +268,7 @@ func (e *ConsensusEngine) autoRewind(lastCC *core.ExtendedBlock) *core.ExtendedB
 					break
 				}
 
-				lastCC.Status = core.BlockStatusDisposed
-				e.chain.SaveBlock(lastCC)
+				e.chain.DisposeBlock(lastCC.Hash())
 				e.chain.RemoveVotesByHash(lastCC.Hash())
 
 				parent, err := e.chain.FindBlock(lastCC.Parent)
@@ -242,6 +294,122 @@ func (e *ConsensusEngine) autoRewind(lastCC *core.ExtendedBlock) *core.ExtendedB
 	return lastCC
 }
 
+// recoverState detects a block whose state was partially committed, e.g. when the node was killed during
+// a block commit, and rewinds to its most recent ancestor with a complete state. The blocks above are then
+// re-executed in height order, and the ones that cannot be re-executed are reset to pending.
+func (e *ConsensusEngine) recoverState(lastCC *core.ExtendedBlock) *core.ExtendedBlock {
+	block := lastCC
+	for e.ledger.ResetState(block.Block).IsError() {
+		e.logger.WithFields(log.Fields{
+			"block":           block.Hash().Hex(),
+			"block.Height":    block.Height,
+			"block.StateHash": block.StateHash.Hex(),
+		}).Warn("State of the block is incomplete, rewinding to the parent")
+
+		if block.Parent.IsEmpty() || lastCC.Height-block.Height >= maxStateRecoveryDepth {
+			e.logger.WithFields(log.Fields{
+				"block.Height": lastCC.Height,
+			}).Fatal("Failed to recover the state, please restore the node from a snapshot")
+		}
+		parent, err := e.chain.FindBlock(block.Parent)
+		if err != nil {
+			e.logger.WithFields(log.Fields{
+				"error":  err,
+				"parent": block.Parent.Hex(),
+				"block":  block.Hash().Hex(),
+			}).Fatal("Failed to find parent block")
+		}
+		block = parent
+	}
+	if block == lastCC {
+		return lastCC
+	}
+
+	lastFinalized := block
+	executed := map[common.Hash]bool{block.Hash(): true}
+	for height := block.Height + 1; ; height++ {
+		blocks := e.chain.FindBlocksByHeight(height)
+		if len(blocks) == 0 {
+			break
+		}
+		for _, b := range blocks {
+			if b.Status.IsInvalid() {
+				continue
+			}
+			if b.Status.IsValid() && executed[b.Parent] && e.reexecuteBlock(b) {
+				executed[b.Hash()] = true
+				if b.Status.IsFinalized() {
+					lastFinalized = b
+				}
+				continue
+			}
+			if b.Status.IsTrusted() {
+				continue
+			}
+			b.Status = core.BlockStatusPending
+			e.chain.SaveBlock(b)
+		}
+	}
+
+	// The highest CC block falls back to its most recent ancestor that was re-executed
+	hcc := lastCC
+	for !executed[hcc.Hash()] {
+		parent, err := e.chain.FindBlock(hcc.Parent)
+		if err != nil {
+			e.logger.WithFields(log.Fields{
+				"error":  err,
+				"parent": hcc.Parent.Hex(),
+				"block":  hcc.Hash().Hex(),
+			}).Fatal("Failed to find parent block")
+		}
+		hcc = parent
+	}
+
+	e.state.SetLastFinalizedBlock(lastFinalized)
+	e.state.SetHighestCCBlock(hcc)
+
+	e.logger.WithFields(log.Fields{
+		"block":         block.Hash().Hex(),
+		"block.Height":  block.Height,
+		"hcc.Height":    hcc.Height,
+		"lastCC.Height": lastCC.Height,
+	}).Warn("Recovered the state from a partially committed block")
+
+	return hcc
+}
+
+// reexecuteBlock applies the transactions of a block on top of the state of its parent, which
+// needs to be complete.
+func (e *ConsensusEngine) reexecuteBlock(eb *core.ExtendedBlock) bool {
+	parent, err := e.chain.FindBlock(eb.Parent)
+	if err != nil {
+		e.logger.WithFields(log.Fields{
+			"error":  err,
+			"parent": eb.Parent.Hex(),
+			"block":  eb.Hash().Hex(),
+		}).Fatal("Failed to find parent block")
+	}
+	if res := e.ledger.ResetState(parent.Block); res.IsError() {
+		e.logger.WithFields(log.Fields{
+			"error":            res.Message,
+			"parent.StateHash": parent.StateHash.Hex(),
+		}).Warn("Failed to reset state to parent.StateHash")
+		return false
+	}
+	if res := e.ledger.ApplyBlockTxs(eb.Block); res.IsError() {
+		e.logger.WithFields(log.Fields{
+			"error":        res.String(),
+			"block":        eb.Hash().Hex(),
+			"block.Height": eb.Height,
+		}).Warn("Failed to re-execute block")
+		return false
+	}
+	if eb.Status.IsFinalized() {
+		e.ledger.FinalizeState(eb.Height, eb.StateHash)
+	}
+	return true
+}
+
 // Stop notifies all goroutines to stop without blocking.
 func (e *ConsensusEngine) Stop() {
 	e.cancel()
@@ -267,6 +435,9 @@ func (e *ConsensusEngine) mainLoop() {
 			select {
 			case <-e.ctx.Done():
 				e.stopped = true
+				if e.journal != nil {
+					e.journal.Close()
+				}
 				return
 			case msg := <-e.incoming:
 				endEpoch := e.processMessage(msg)
@@ -274,13 +445,9 @@ func (e *ConsensusEngine) mainLoop() {
 					break Epoch
 				}
 			case <-e.voteTimer.C:
-				e.voteTimerReady = true
-				if e.blockProcessed {
-					e.vote()
-				}
+				e.handleVoteTimer()
 			case <-e.epochTimer.C:
-				e.logger.WithFields(log.Fields{"e.epoch": e.GetEpoch()}).Debug("Epoch timeout. Repeating epoch")
-				e.vote()
+				e.handleEpochTimeout()
 				break Epoch
 			case <-e.guardianTimer.C:
 				v := e.guardian.GetVoteToBroadcast()
@@ -303,23 +470,58 @@ func (e *ConsensusEngine) mainLoop() {
 	}
 }
 
-// enterEpoch is called when engine enters a new epoch.
-func (e *ConsensusEngine) enterEpoch() {
+func (e *ConsensusEngine) handleVoteTimer() {
+	e.recordEvent(JournalEventVoteTimer, 0, common.Hash{}, common.Address{}, 0)
+	e.voteTimerReady = true
+	if e.blockProcessed {
+		e.vote()
+	}
+}
+
+func (e *ConsensusEngine) handleEpochTimeout() {
+	e.recordEvent(JournalEventEpochTimeout, 0, common.Hash{}, common.Address{}, 0)
+	e.consecutiveEpochTimeouts++
+	e.logger.WithFields(log.Fields{
+		"e.epoch":             e.GetEpoch(),
+		"consecutiveTimeouts": e.consecutiveEpochTimeouts,
+	}).Debug("Epoch timeout. Repeating epoch")
+	e.vote()
+}
+
+// enterEpoch is called when engine enters a new epoch. It returns the vote interval and the
+// epoch timeout of the new epoch.
+func (e *ConsensusEngine) enterEpoch() (voteInterval time.Duration, epochTimeout time.Duration) {
 	logger.Debugf("Enter epoch %v", e.GetEpoch())
+	e.recordEvent(JournalEventEnterEpoch, 0, common.Hash{}, common.Address{}, 0)
+
+	epochTimeout = e.getEpochTimeout()
+	if e.consecutiveEpochTimeouts >= viper.GetInt(common.CfgConsensusEpochTimeoutBackoffThreshold) {
+		logger.WithFields(log.Fields{
+			"consecutiveTimeouts": e.consecutiveEpochTimeouts,
+			"epochTimeout":        epochTimeout,
+		}).Info("Backing off epoch timeout")
+	}
+	voteInterval = time.Duration(viper.GetInt(common.CfgConsensusMinBlockInterval)) * time.Second
+
+	e.voteTimerReady = false
+	e.blockProcessed = false
+
+	// The timers are scheduled by the driver when the engine is driven manually.
+	if e.manual {
+		return
+	}
 
 	// Reset timers.
 	if e.epochTimer != nil {
 		e.epochTimer.Stop()
 	}
-	e.epochTimer = time.NewTimer(time.Duration(viper.GetInt(common.CfgConsensusMaxEpochLength)) * time.Second)
+	e.epochTimer = time.NewTimer(epochTimeout)
 
 	if e.voteTimer != nil {
 		e.voteTimer.Stop()
 	}
-	e.voteTimer = time.NewTimer(time.Duration(viper.GetInt(common.CfgConsensusMinBlockInterval)) * time.Second)
-
-	e.voteTimerReady = false
-	e.blockProcessed = false
+	e.voteTimer = time.NewTimer(voteInterval)
+	return
 }
 
 // GetChannelIDs implements the p2p.MessageHandler interface.
@@ -340,6 +542,7 @@ func (e *ConsensusEngine) processMessage(msg interface{}) (endEpoch bool) {
 	switch m := msg.(type) {
 	case core.Vote:
 		e.logger.WithFields(log.Fields{"vote": m}).Debug("Received vote")
+		e.recordEvent(JournalEventReceivedVote, m.Height, m.Block, m.ID, m.Epoch)
 		endEpoch = e.handleVote(m)
 		e.checkCC(m.Block)
 		return endEpoch
@@ -347,6 +550,7 @@ func (e *ConsensusEngine) processMessage(msg interface{}) (endEpoch bool) {
 		e.logger.WithFields(log.Fields{
 			"block": m.BlockHeader,
 		}).Debug("Received block")
+		e.recordEvent(JournalEventReceivedBlock, m.Height, m.Hash(), m.Proposer, m.Epoch)
 		e.handleBlock(m)
 	case *core.AggregatedVotes:
 		// e.logger.WithFields(log.Fields{"guardian vote": m}).Debug("Received guardian vote")
@@ -437,6 +641,13 @@ func (e *ConsensusEngine) validateBlock(block *core.Block, parent *core.Extended
 		return result.Error("Parent block is invalid")
 	}
 
+	// Skip the full verification of the blocks before the trusted checkpoint. A fork of
+	// these blocks can never be extended beyond the checkpoint, since the sync layer only
+	// accepts the trusted block at the checkpoint height.
+	if core.IsBeforeTrustedCheckpoint(block.Height) {
+		return result.OK
+	}
+
 	// Validate HCC.
 	if !e.chain.IsDescendant(block.HCC.BlockHash, block.Hash()) {
 		e.logger.WithFields(log.Fields{
@@ -449,7 +660,30 @@ func (e *ConsensusEngine) validateBlock(block *core.Block, parent *core.Extended
 	if err != nil {
 		return result.Error("HCC block not found")
 	}
-	if !hccBlock.Status.IsFinalized() {
+	if block.ValidatorVotes != nil {
+		if block.Height < common.HeightEnableValidatorVoteAggregation {
+			return result.Error("Validator votes are not enabled yet")
+		}
+		hccValidators := e.validatorManager.GetValidatorSet(block.HCC.BlockHash)
+		pubkeys, err := e.ledger.GetFinalizedValidatorBLSPubkeys(block.HCC.BlockHash, false, hccValidators)
+		if err != nil {
+			e.logger.WithFields(log.Fields{
+				"block":     block.Hash().Hex(),
+				"block.HCC": block.HCC.BlockHash.Hex(),
+				"error":     err.Error(),
+			}).Warn("Failed to load validator BLS pubkeys")
+			return result.Error("Failed to load validator BLS pubkeys")
+		}
+		if res := block.ValidatorVotes.Validate(block.HCC.BlockHash, hccValidators, pubkeys); res.IsError() {
+			e.logger.WithFields(log.Fields{
+				"block":                block.Hash().Hex(),
+				"block.HCC":            block.HCC.BlockHash.Hex(),
+				"block.ValidatorVotes": block.ValidatorVotes.String(),
+				"error":                res.String(),
+			}).Warn("Invalid validator votes")
+			return result.Error("Validator votes are not valid")
+		}
+	} else if !hccBlock.Status.IsFinalized() {
 		hccValidators := e.validatorManager.GetValidatorSet(block.HCC.BlockHash)
 		if !block.HCC.IsValid(hccValidators) {
 			e.logger.WithFields(log.Fields{
@@ -703,6 +937,18 @@ func (e *ConsensusEngine) handleHardcodeBlock(hash common.Hash) {
 		return
 	}
 
+	if cp := core.GetTrustedCheckpoint(); cp != nil && cp.Height == block.Height && !cp.ValidatorSetHash.IsEmpty() {
+		valSet := e.validatorManager.GetValidatorSet(hash)
+		if valSet.Hash() != cp.ValidatorSetHash {
+			e.logger.WithFields(log.Fields{
+				"block":                       hash.Hex(),
+				"validatorSet":                valSet.String(),
+				"validatorSetHash":            valSet.Hash().Hex(),
+				"checkpoint.ValidatorSetHash": cp.ValidatorSetHash.Hex(),
+			}).Fatal("Validator set of the trusted checkpoint mismatch")
+		}
+	}
+
 	e.pruneState(block.Height)
 
 	e.state.SetHighestCCBlock(eb)
@@ -741,15 +987,26 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 	}
 	validateBlockTime := time.Since(start1)
 
-	for _, vote := range block.HCC.Votes.Votes() {
-		e.handleVote(vote)
+	// The blocks proposed by the peers in the current epoch sample their clocks
+	if e.hasSynced && block.Epoch == e.GetEpoch() && block.Proposer.Hex() != e.ID() {
+		e.clockSkew.AddBlockSample(block.Timestamp, start)
+	}
+
+	if block.HCC.Votes != nil {
+		for _, vote := range block.HCC.Votes.Votes() {
+			e.handleVote(vote)
+		}
 	}
 	if localHCC := e.state.GetHighestCCBlock().Hash(); localHCC != block.HCC.BlockHash {
 		e.logger.WithFields(log.Fields{
 			"localHCC":            localHCC.Hex(),
 			"block.HCC.BlockHash": block.HCC.BlockHash.Hex(),
 		}).Debug("Updating HCC before process block")
-		e.checkCC(block.HCC.BlockHash)
+		if block.ValidatorVotes != nil {
+			e.checkAggregatedCC(block.HCC.BlockHash)
+		} else {
+			e.checkCC(block.HCC.BlockHash)
+		}
 	}
 
 	//result := e.ledger.ResetState(parent.Height, parent.StateHash)
@@ -820,7 +1077,7 @@ func (e *ConsensusEngine) handleNormalBlock(eb *core.ExtendedBlock) {
 }
 
 func (e *ConsensusEngine) shouldVote(block common.Hash) bool {
-	return e.shouldVoteByID(e.privateKey.PublicKey().Address(), block)
+	return e.shouldVoteByID(e.signer.PublicKey().Address(), block)
 }
 
 func (e *ConsensusEngine) shouldVoteByID(id common.Address, block common.Hash) bool {
@@ -837,6 +1094,7 @@ func (e *ConsensusEngine) vote() {
 	}
 
 	var vote core.Vote
+	var err error
 	lastVote := e.state.GetLastVote()
 	shouldRepeatVote := false
 	if lastVote.Height != 0 && lastVote.Height >= tip.Height {
@@ -859,25 +1117,30 @@ func (e *ConsensusEngine) vote() {
 	}
 
 	if shouldRepeatVote {
-		block, err := e.chain.FindBlock(lastVote.Block)
+		var block *core.ExtendedBlock
+		block, err = e.chain.FindBlock(lastVote.Block)
 		if err != nil {
 			// Should not happen
 			log.Panic(err)
 		}
 		// Recreating vote so that it has updated epoch and signature.
-		vote = e.createVote(block.Block)
+		vote, err = e.createVote(block.Block)
 	} else {
-		vote = e.createVote(tip.Block)
-		e.state.SetLastVote(vote)
+		vote, err = e.createVote(tip.Block)
+		if err == nil {
+			e.state.SetLastVote(vote)
+		}
+	}
+	if err != nil {
+		e.logger.WithFields(log.Fields{"error": err}).Error("Failed to sign vote")
+		return
 	}
 	e.logger.WithFields(log.Fields{
 		"vote": vote,
 	}).Debug("Sending vote")
 	e.broadcastVote(vote)
 
-	go func() {
-		e.AddMessage(vote)
-	}()
+	e.addMessageToSelf(vote)
 }
 
 func (e *ConsensusEngine) broadcastVote(vote core.Vote) {
@@ -893,15 +1156,22 @@ func (e *ConsensusEngine) broadcastVote(vote core.Vote) {
 	e.dispatcher.SendData([]string{}, voteMsg)
 }
 
-func (e *ConsensusEngine) createVote(block *core.Block) core.Vote {
+func (e *ConsensusEngine) createVote(block *core.Block) (core.Vote, error) {
 	vote := core.Vote{
 		Block:  block.Hash(),
 		Height: block.Height,
-		ID:     e.privateKey.PublicKey().Address(),
+		ID:     e.signer.PublicKey().Address(),
 		Epoch:  e.GetEpoch(),
 	}
-	vote.Sign(e.privateKey)
-	return vote
+	sig, err := e.signer.SignVote(vote)
+	if err != nil {
+		return core.Vote{}, err
+	}
+	vote.SetSignature(sig)
+	if block.Height >= common.HeightEnableValidatorVoteAggregation {
+		vote.SignBls(e.blsKey)
+	}
+	return vote, nil
 }
 
 func (e *ConsensusEngine) validateVote(vote core.Vote) bool {
@@ -969,6 +1239,16 @@ func (e *ConsensusEngine) handleVote(vote core.Vote) (endEpoch bool) {
 }
 
 func (e *ConsensusEngine) checkCC(hash common.Hash) {
+	e.doCheckCC(hash, false)
+}
+
+// checkAggregatedCC processes the block as a CC block, whose aggregated validator votes have
+// been validated.
+func (e *ConsensusEngine) checkAggregatedCC(hash common.Hash) {
+	e.doCheckCC(hash, true)
+}
+
+func (e *ConsensusEngine) doCheckCC(hash common.Hash, hasAggregatedVotes bool) {
 	if hash.IsEmpty() {
 		return
 	}
@@ -1000,6 +1280,11 @@ func (e *ConsensusEngine) checkCC(hash common.Hash) {
 		return
 	}
 
+	if hasAggregatedVotes {
+		e.processCCBlock(block)
+		return
+	}
+
 	votes := e.chain.FindVotesByHash(hash).UniqueVoter()
 	validators := e.validatorManager.GetValidatorSet(hash)
 	if validators.HasMajority(votes) {
@@ -1097,6 +1382,11 @@ func (e *ConsensusEngine) GetSummary() *StateStub {
 	return e.state.GetSummary()
 }
 
+// ClockSkew returns the estimated skew of the local clock
+func (e *ConsensusEngine) ClockSkew() ClockSkew {
+	return e.clockSkew.Skew()
+}
+
 // FinalizedBlocks returns a channel that will be published with finalized blocks by the engine.
 func (e *ConsensusEngine) FinalizedBlocks() chan *core.Block {
 	return e.finalizedBlocks
@@ -1145,6 +1435,8 @@ func (e *ConsensusEngine) finalizeBlock(block *core.ExtendedBlock) error {
 
 	e.state.SetLastFinalizedBlock(block)
 	e.ledger.FinalizeState(block.Height, block.StateHash)
+	e.consecutiveEpochTimeouts = 0
+	e.recordEvent(JournalEventFinalized, block.Height, block.Hash(), block.Proposer, block.Epoch)
 
 	e.checkSyncStatus()
 
@@ -1224,9 +1516,11 @@ func (e *ConsensusEngine) shouldProposeByID(previousBlock common.Hash, epoch uin
 		return false
 	}
 	proposer := e.validatorManager.GetNextProposer(previousBlock, epoch)
-	if proposer.ID().Hex() != id {
+	signer := e.validatorManager.GetNextValidatorSet(previousBlock).SigningAddress(proposer.ID())
+	if signer.Hex() != id {
 		e.logger.WithFields(log.Fields{
 			"expectedProposer": proposer.ID().Hex(),
+			"expectedSigner":   signer.Hex(),
 			"tip":              previousBlock.Hex(),
 			"epoch":            epoch,
 		}).Debug("shouldProposeByID=false")
@@ -1254,12 +1548,20 @@ func (e *ConsensusEngine) createProposal(shouldIncludeValidatorUpdateTxs bool) (
 	block.Epoch = e.GetEpoch()
 	block.Parent = tip.Hash()
 	block.Height = tip.Height + 1
-	block.Proposer = e.privateKey.PublicKey().Address()
-	block.Timestamp = big.NewInt(time.Now().Unix())
+	block.Proposer = e.signer.PublicKey().Address()
+	block.Timestamp = big.NewInt(e.now().Unix())
 	block.HCC.BlockHash = e.state.GetHighestCCBlock().Hash()
 	hccValidators := e.validatorManager.GetValidatorSet(block.HCC.BlockHash)
 	block.HCC.Votes = e.chain.FindVotesByHash(block.HCC.BlockHash).UniqueVoter().FilterByValidators(hccValidators)
 
+	// Replace the HCC votes with the aggregated validator votes.
+	if block.Height >= common.HeightEnableValidatorVoteAggregation {
+		if vvotes := e.aggregateValidatorVotes(block.HCC.BlockHash, hccValidators, block.HCC.Votes); vvotes != nil {
+			block.ValidatorVotes = vvotes
+			block.HCC.Votes = core.NewVoteSet()
+		}
+	}
+
 	// Add guardian votes.
 	if block.Height >= common.HeightEnableTheta2 && common.IsCheckPointHeight(block.Height) {
 		block.GuardianVotes = e.guardian.GetBestVote()
@@ -1280,9 +1582,9 @@ func (e *ConsensusEngine) createProposal(shouldIncludeValidatorUpdateTxs bool) (
 	block.StateHash = newRoot
 
 	// Sign block.
-	sig, err := e.privateKey.Sign(block.SignBytes())
+	sig, err := e.signer.SignBlock(block.BlockHeader)
 	if err != nil {
-		e.logger.WithFields(log.Fields{"error": err}).Panic("Failed to sign vote")
+		return core.Proposal{}, fmt.Errorf("Failed to sign block proposal: %v", err)
 	}
 	block.SetSignature(sig)
 
@@ -1307,11 +1609,43 @@ func (e *ConsensusEngine) createProposal(shouldIncludeValidatorUpdateTxs bool) (
 	return proposal, nil
 }
 
+// aggregateValidatorVotes aggregates the BLS signatures of the votes on the HCC block. Returns nil
+// if the aggregated votes do not reach majority, in which case the individual votes are used. The
+// individual votes are also kept for the two blocks following a validator set change, which
+// snapshots and light clients rely on to prove the new validator set.
+func (e *ConsensusEngine) aggregateValidatorVotes(hcc common.Hash, validators *core.ValidatorSet, votes *core.VoteSet) *core.AggregatedValidatorVotes {
+	hccBlock, err := e.chain.FindBlock(hcc)
+	if err != nil || hccBlock.HasValidatorUpdate {
+		return nil
+	}
+	if hccParent, err := e.chain.FindBlock(hccBlock.Parent); err != nil || hccParent.HasValidatorUpdate {
+		return nil
+	}
+
+	pubkeys, err := e.ledger.GetFinalizedValidatorBLSPubkeys(hcc, false, validators)
+	if err != nil {
+		e.logger.WithFields(log.Fields{"hcc": hcc.Hex(), "error": err}).Warn("Failed to load validator BLS pubkeys")
+		return nil
+	}
+	vvotes := core.NewAggregatedValidatorVotes(hcc, validators, pubkeys, votes)
+	if !vvotes.HasMajority(validators) {
+		return nil
+	}
+	return vvotes
+}
+
 func (e *ConsensusEngine) propose() {
 	tip := e.GetTipToExtend()
 	if !e.shouldPropose(tip, e.GetEpoch()) {
 		return
 	}
+	if viper.GetBool(common.CfgClockRefuseToPropose) && e.clockSkew.Exceeded() {
+		e.logger.WithFields(log.Fields{
+			"epoch":     e.GetEpoch(),
+			"clockSkew": e.clockSkew.Skew().Skew,
+		}).Warn("Refuse to propose, the clock skew is above the max skew")
+		return
+	}
 
 	shouldIncludeValidatorUpdateTxs := e.shouldIncludeValidatorUpdateTxs(tip)
 
@@ -1347,10 +1681,9 @@ func (e *ConsensusEngine) propose() {
 		Payload:   payload,
 	}
 	e.dispatcher.SendData([]string{}, proposalMsg)
+	e.recordEvent(JournalEventProposed, proposal.Block.Height, proposal.Block.Hash(), proposal.Block.Proposer, proposal.Block.Epoch)
 
-	go func() {
-		e.AddMessage(proposal.Block)
-	}()
+	e.addMessageToSelf(proposal.Block)
 }
 
 func (e *ConsensusEngine) pruneState(currentBlockHeight uint64) {
@@ -1375,6 +1708,25 @@ func (e *ConsensusEngine) pruneState(currentBlockHeight uint64) {
 	// e.ledger.PruneState(endHeight)
 }
 
+// recordEvent records the event to the consensus journal if enabled
+func (e *ConsensusEngine) recordEvent(eventType JournalEventType, height uint64, block common.Hash, peer common.Address, msgEpoch uint64) {
+	if e.journal == nil {
+		return
+	}
+	err := e.journal.Record(JournalEvent{
+		Timestamp: uint64(time.Now().UnixNano()),
+		Type:      eventType,
+		Epoch:     e.GetEpoch(),
+		Height:    height,
+		Block:     block,
+		Peer:      peer,
+		Msg:       msgEpoch,
+	})
+	if err != nil {
+		e.logger.WithFields(log.Fields{"error": err}).Warn("Failed to record consensus event")
+	}
+}
+
 func (e *ConsensusEngine) State() *State {
 	return e.state
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/thetatoken/theta/blockchain"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
 	"github.com/thetatoken/theta/store/database/backend"
@@ -742,3 +743,92 @@ func TestTipSelection(t *testing.T) {
 	tip = ce.GetTipToExtend()
 	assert.Equal(a2.Hash(), tip.Hash(), "should not select blocks with validator update that are higher than local HCC")
 }
+
+// recoveryTestLedger tracks the blocks whose states are complete
+type recoveryTestLedger struct {
+	core.Ledger
+
+	complete map[common.Hash]bool
+	failing  map[common.Hash]bool
+	applied  []common.Hash
+}
+
+func (l *recoveryTestLedger) ResetState(block *core.Block) result.Result {
+	if !l.complete[block.Hash()] {
+		return result.Error("State of block %v is incomplete", block.Hash().Hex())
+	}
+	return result.OK
+}
+
+func (l *recoveryTestLedger) ApplyBlockTxs(block *core.Block) result.Result {
+	if l.failing[block.Hash()] {
+		return result.Error("Failed to apply block %v", block.Hash().Hex())
+	}
+	l.complete[block.Hash()] = true
+	l.applied = append(l.applied, block.Hash())
+	return result.OK
+}
+
+func (l *recoveryTestLedger) FinalizeState(height uint64, rootHash common.Hash) result.Result {
+	return result.OK
+}
+
+func TestRecoverState(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	validatorManager := MockValidatorManager{PrivKey: privKey}
+
+	core.ResetTestBlocks()
+
+	store := kvstore.NewKVStore(backend.NewMemDatabase())
+	root := core.CreateTestBlock("root", "")
+	chain := blockchain.NewChain("testchain", store, root)
+
+	ce := NewConsensusEngine(privKey, store, chain, nil, validatorManager)
+
+	addBlock := func(name string, parent string, status core.BlockStatus) *core.ExtendedBlock {
+		eb, _ := chain.AddBlock(core.CreateTestBlock(name, parent))
+		eb.Status = status
+		chain.SaveBlock(eb)
+		return eb
+	}
+
+	// a1 is the most recent block with a complete state, the commit of a3 was interrupted
+	a1 := addBlock("a1", "root", core.BlockStatusDirectlyFinalized)
+	a2 := addBlock("a2", "a1", core.BlockStatusDirectlyFinalized)
+	a3 := addBlock("a3", "a2", core.BlockStatusCommitted)
+	a4 := addBlock("a4", "a3", core.BlockStatusValid)
+	b2 := addBlock("b2", "a1", core.BlockStatusValid)
+	b3 := addBlock("b3", "b2", core.BlockStatusValid)
+	c4 := addBlock("c4", "a3", core.BlockStatusPending)
+
+	ledger := &recoveryTestLedger{
+		complete: map[common.Hash]bool{root.Hash(): true, a1.Hash(): true},
+		failing:  map[common.Hash]bool{b3.Hash(): true},
+	}
+	ce.SetLedger(ledger)
+
+	hcc := ce.recoverState(a3)
+	assert.Equal(a3.Hash(), hcc.Hash())
+	assert.Equal(a3.Hash(), ce.state.GetHighestCCBlock().Hash())
+	assert.Equal(a2.Hash(), ce.state.GetLastFinalizedBlock().Hash())
+
+	// The blocks above a1 are re-executed in height order
+	assert.Equal(4, len(ledger.applied))
+	assert.ElementsMatch([]common.Hash{a2.Hash(), b2.Hash()}, ledger.applied[:2])
+	assert.Equal(a3.Hash(), ledger.applied[2])
+	assert.Equal(a4.Hash(), ledger.applied[3])
+
+	// The statuses of the re-executed blocks are kept, the others are reset to pending
+	for _, expected := range []*core.ExtendedBlock{a2, a3, a4, b2} {
+		eb, err := chain.FindBlock(expected.Hash())
+		assert.Nil(err)
+		assert.Equal(expected.Status, eb.Status)
+	}
+	for _, pending := range []*core.ExtendedBlock{b3, c4} {
+		eb, err := chain.FindBlock(pending.Hash())
+		assert.Nil(err)
+		assert.True(eb.Status.IsPending())
+	}
+}
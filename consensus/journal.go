@@ -0,0 +1,243 @@
+package consensus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// JournalEventType defines the type of the events recorded in the consensus journal
+type JournalEventType uint8
+
+const (
+	JournalEventEnterEpoch JournalEventType = iota
+	JournalEventReceivedBlock
+	JournalEventReceivedVote
+	JournalEventVoteTimer
+	JournalEventEpochTimeout
+	JournalEventProposed
+	JournalEventFinalized
+)
+
+func (t JournalEventType) String() string {
+	switch t {
+	case JournalEventEnterEpoch:
+		return "EnterEpoch"
+	case JournalEventReceivedBlock:
+		return "ReceivedBlock"
+	case JournalEventReceivedVote:
+		return "ReceivedVote"
+	case JournalEventVoteTimer:
+		return "VoteTimer"
+	case JournalEventEpochTimeout:
+		return "EpochTimeout"
+	case JournalEventProposed:
+		return "Proposed"
+	case JournalEventFinalized:
+		return "Finalized"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(t))
+	}
+}
+
+// JournalEvent is a consensus event along with the local epoch and the time it happened
+type JournalEvent struct {
+	Timestamp uint64 // unix time in nanoseconds
+	Type      JournalEventType
+	Epoch     uint64         // local epoch of the node
+	Height    uint64         // height of the block involved, if any
+	Block     common.Hash    // hash of the block involved, if any
+	Peer      common.Address // voter or proposer, if any
+	Msg       uint64         // epoch of the vote or the block, if any
+}
+
+// Time returns the time of the event
+func (je JournalEvent) Time() time.Time {
+	return time.Unix(0, int64(je.Timestamp))
+}
+
+func (je JournalEvent) String() string {
+	return fmt.Sprintf("%v epoch: %v, height: %v, block: %v, peer: %v, msg epoch: %v",
+		je.Type, je.Epoch, je.Height, je.Block.Hex(), je.Peer.Hex(), je.Msg)
+}
+
+//
+// Journal appends the consensus events to a local file as RLP encoded records. When the file
+// exceeds the max size, it is rotated to <path>.1 so that the journal keeps the recent history.
+//
+type Journal struct {
+	mu *sync.Mutex
+
+	path    string
+	maxSize int64
+	file    *os.File
+	writer  *bufio.Writer
+	size    int64
+}
+
+// OpenJournal opens the journal at the given path for appending
+func OpenJournal(path string, maxSize int64) (*Journal, error) {
+	j := &Journal{
+		mu:      &sync.Mutex{},
+		path:    path,
+		maxSize: maxSize,
+	}
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *Journal) open() error {
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	j.file = file
+	j.writer = bufio.NewWriter(file)
+	j.size = info.Size()
+	return nil
+}
+
+// Record appends the event to the journal
+func (j *Journal) Record(event JournalEvent) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return fmt.Errorf("journal %v is closed", j.path)
+	}
+
+	raw, err := rlp.EncodeToBytes(event)
+	if err != nil {
+		return err
+	}
+	if j.maxSize > 0 && j.size > 0 && j.size+int64(len(raw)) > j.maxSize {
+		if err := j.rotate(); err != nil {
+			return err
+		}
+	}
+	if _, err := j.writer.Write(raw); err != nil {
+		return err
+	}
+	j.size += int64(len(raw))
+
+	// Flush each record so that the events right before a crash are not lost
+	return j.writer.Flush()
+}
+
+// rotate moves the journal file to <path>.1 and reopens the journal. If the file cannot be moved,
+// the journal keeps appending to it and the rotation is retried by the next record. The journal is
+// closed if the file cannot be reopened.
+func (j *Journal) rotate() error {
+	j.writer.Flush()
+	j.file.Close()
+	j.file = nil
+	renameErr := os.Rename(j.path, j.path+".1")
+	if err := j.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		logger.Warnf("Failed to rotate journal %v: %v", j.path, renameErr)
+	}
+	return nil
+}
+
+// Close flushes and closes the journal
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.file == nil {
+		return nil
+	}
+	j.writer.Flush()
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// ReadJournal reads all the events from the journal file
+func ReadJournal(path string) ([]JournalEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := []JournalEvent{}
+	stream := rlp.NewStream(bufio.NewReader(file), 0)
+	for {
+		event := JournalEvent{}
+		err := stream.Decode(&event)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// The last record might be truncated if the node crashed while writing
+			return events, fmt.Errorf("failed to decode event #%v: %v", len(events), err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// EpochSummary summarizes the events of an epoch in the journal
+type EpochSummary struct {
+	Epoch      uint64
+	Start      time.Time
+	End        time.Time
+	Blocks     []common.Hash
+	Proposed   bool
+	NumVotes   int
+	Voters     map[common.Address]bool
+	Finalized  []uint64 // heights finalized during the epoch
+	TimedOut   bool
+	FirstBlock time.Duration // time since the epoch start when the first block was received
+}
+
+// BuildTimeline groups the events by the local epoch, in the order they happened
+func BuildTimeline(events []JournalEvent) []*EpochSummary {
+	timeline := []*EpochSummary{}
+	var current *EpochSummary
+	for _, event := range events {
+		if current == nil || event.Type == JournalEventEnterEpoch || event.Epoch != current.Epoch {
+			current = &EpochSummary{
+				Epoch:  event.Epoch,
+				Start:  event.Time(),
+				Voters: make(map[common.Address]bool),
+			}
+			timeline = append(timeline, current)
+		}
+		current.End = event.Time()
+
+		switch event.Type {
+		case JournalEventReceivedBlock:
+			if len(current.Blocks) == 0 {
+				current.FirstBlock = event.Time().Sub(current.Start)
+			}
+			current.Blocks = append(current.Blocks, event.Block)
+		case JournalEventReceivedVote:
+			current.NumVotes++
+			current.Voters[event.Peer] = true
+		case JournalEventProposed:
+			current.Proposed = true
+		case JournalEventFinalized:
+			current.Finalized = append(current.Finalized, event.Height)
+		case JournalEventEpochTimeout:
+			current.TimedOut = true
+		}
+	}
+	return timeline
+}
@@ -0,0 +1,101 @@
+package consensus
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestJournal(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	journalPath := path.Join(dir, "consensus_journal")
+
+	j, err := OpenJournal(journalPath, 0)
+	assert.Nil(err)
+
+	block := common.HexToHash("0x01")
+	voter := common.HexToAddress("0x02")
+	events := []JournalEvent{
+		{Timestamp: 1000, Type: JournalEventEnterEpoch, Epoch: 5},
+		{Timestamp: 2000, Type: JournalEventReceivedBlock, Epoch: 5, Height: 10, Block: block, Peer: voter, Msg: 5},
+		{Timestamp: 3000, Type: JournalEventReceivedVote, Epoch: 5, Height: 10, Block: block, Peer: voter, Msg: 5},
+		{Timestamp: 4000, Type: JournalEventEpochTimeout, Epoch: 5},
+		{Timestamp: 5000, Type: JournalEventEnterEpoch, Epoch: 6},
+		{Timestamp: 6000, Type: JournalEventFinalized, Epoch: 6, Height: 10, Block: block},
+	}
+	for _, event := range events {
+		assert.Nil(j.Record(event))
+	}
+	assert.Nil(j.Close())
+	assert.NotNil(j.Record(events[0]))
+
+	read, err := ReadJournal(journalPath)
+	assert.Nil(err)
+	assert.Equal(events, read)
+
+	timeline := BuildTimeline(read)
+	assert.Equal(2, len(timeline))
+	assert.Equal(uint64(5), timeline[0].Epoch)
+	assert.True(timeline[0].TimedOut)
+	assert.Equal([]common.Hash{block}, timeline[0].Blocks)
+	assert.Equal(1, timeline[0].NumVotes)
+	assert.Equal(uint64(6), timeline[1].Epoch)
+	assert.False(timeline[1].TimedOut)
+	assert.Equal([]uint64{10}, timeline[1].Finalized)
+}
+
+func TestJournalRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	journalPath := path.Join(dir, "consensus_journal")
+
+	j, err := OpenJournal(journalPath, 64)
+	assert.Nil(err)
+	for i := 0; i < 10; i++ {
+		assert.Nil(j.Record(JournalEvent{Timestamp: uint64(i), Type: JournalEventVoteTimer, Epoch: uint64(i)}))
+	}
+	assert.Nil(j.Close())
+
+	rotated, err := ReadJournal(journalPath + ".1")
+	assert.Nil(err)
+	current, err := ReadJournal(journalPath)
+	assert.Nil(err)
+	assert.True(len(rotated) > 0)
+	assert.True(len(current) > 0)
+	assert.Equal(uint64(9), current[len(current)-1].Epoch)
+}
+
+func TestJournalRotationFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	journalPath := path.Join(dir, "consensus_journal")
+
+	// The journal file cannot be renamed to a non-empty directory
+	assert.Nil(os.MkdirAll(path.Join(journalPath+".1", "occupied"), 0700))
+
+	j, err := OpenJournal(journalPath, 64)
+	assert.Nil(err)
+	for i := 0; i < 10; i++ {
+		assert.Nil(j.Record(JournalEvent{Timestamp: uint64(i), Type: JournalEventVoteTimer, Epoch: uint64(i)}))
+	}
+	assert.Nil(j.Close())
+
+	current, err := ReadJournal(journalPath)
+	assert.Nil(err)
+	assert.Equal(10, len(current))
+	assert.Equal(uint64(9), current[9].Epoch)
+}
@@ -0,0 +1,77 @@
+package consensus
+
+import (
+	"context"
+	"time"
+)
+
+//
+// The methods below allow an external driver, e.g. the consensus simulation, to drive the
+// engine step by step with a virtual clock instead of running the main loop with wall clock
+// timers. The driver is responsible for mirroring the main loop: it enters an epoch, delivers
+// messages and fires the vote and epoch timers it scheduled, and enters the next epoch when a
+// step reports the end of the current one.
+//
+
+// StartManual prepares the engine to be driven manually. The clock provides the time used
+// for the block proposals.
+func (e *ConsensusEngine) StartManual(ctx context.Context, clock func() time.Time) {
+	c, cancel := context.WithCancel(ctx)
+	e.ctx = c
+	e.cancel = cancel
+	e.manual = true
+	e.clock = clock
+
+	lastCC := e.autoRewind(e.state.GetHighestCCBlock())
+	e.ledger.ResetState(lastCC.Block)
+
+	e.checkSyncStatus()
+}
+
+// StepEnterEpoch enters the current epoch and proposes a block if the engine is the proposer.
+// It returns the delays after which the driver should fire the vote timer and the epoch timer.
+func (e *ConsensusEngine) StepEnterEpoch() (voteInterval time.Duration, epochTimeout time.Duration) {
+	voteInterval, epochTimeout = e.enterEpoch()
+	e.propose()
+	return
+}
+
+// StepMessage processes a vote or a block. It returns true if the current epoch has ended.
+func (e *ConsensusEngine) StepMessage(msg interface{}) (endEpoch bool) {
+	return e.processMessage(msg)
+}
+
+// StepVoteTimer fires the vote timer of the current epoch.
+func (e *ConsensusEngine) StepVoteTimer() {
+	e.handleVoteTimer()
+}
+
+// StepEpochTimeout fires the epoch timer. The current epoch always ends on timeout.
+func (e *ConsensusEngine) StepEpochTimeout() {
+	e.handleEpochTimeout()
+}
+
+// TakeSelfMessages returns and clears the messages the engine sent to itself during the
+// previous steps. The driver should deliver them back with StepMessage.
+func (e *ConsensusEngine) TakeSelfMessages() []interface{} {
+	msgs := e.selfMessages
+	e.selfMessages = nil
+	return msgs
+}
+
+func (e *ConsensusEngine) addMessageToSelf(msg interface{}) {
+	if e.manual {
+		e.selfMessages = append(e.selfMessages, msg)
+		return
+	}
+	go func() {
+		e.AddMessage(msg)
+	}()
+}
+
+func (e *ConsensusEngine) now() time.Time {
+	if e.clock != nil {
+		return e.clock()
+	}
+	return time.Now()
+}
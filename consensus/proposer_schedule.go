@@ -0,0 +1,115 @@
+package consensus
+
+import (
+	"math/big"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+// ProposerScheduleRoundLength is the number of epochs in a round of the proposer schedule.
+const ProposerScheduleRoundLength = 1000
+
+//
+// ProposerSchedule is a deterministic stake-weighted round robin schedule of the proposers. Each
+// round has ProposerScheduleRoundLength slots, which are apportioned to the validators in
+// proportion to their stakes with the largest remainder method. The slots of the validators are
+// then interleaved with the smooth weighted round robin algorithm, so that a validator proposes
+// at regular intervals instead of consecutively.
+//
+type ProposerSchedule struct {
+	validators []core.Validator
+	shares     []uint64 // number of slots of each validator in a round
+	slots      []int    // index of the validator of each slot
+}
+
+// NewProposerSchedule creates the proposer schedule of the given validator set.
+func NewProposerSchedule(valSet *core.ValidatorSet) *ProposerSchedule {
+	if valSet.Size() == 0 {
+		log.Panic("No validators have been added")
+	}
+
+	s := &ProposerSchedule{
+		validators: valSet.Validators(),
+		shares:     apportionSlots(valSet, ProposerScheduleRoundLength),
+		slots:      make([]int, ProposerScheduleRoundLength),
+	}
+
+	current := make([]int64, len(s.validators))
+	for slot := 0; slot < ProposerScheduleRoundLength; slot++ {
+		selected := 0
+		for idx := range s.validators {
+			current[idx] += int64(s.shares[idx])
+			if current[idx] > current[selected] {
+				selected = idx
+			}
+		}
+		current[selected] -= ProposerScheduleRoundLength
+		s.slots[slot] = selected
+	}
+	return s
+}
+
+// apportionSlots distributes the slots to the validators in proportion to their stakes. The
+// slots left after rounding down go to the validators with the largest remainders.
+func apportionSlots(valSet *core.ValidatorSet, numSlots uint64) []uint64 {
+	validators := valSet.Validators()
+	totalStake := valSet.TotalStake()
+	shares := make([]uint64, len(validators))
+	if totalStake.Cmp(common.Big0) == 0 {
+		// Should not happen, validators are selected by stake.
+		for idx := uint64(0); idx < numSlots; idx++ {
+			shares[idx%uint64(len(validators))]++
+		}
+		return shares
+	}
+
+	remainders := make([]*big.Int, len(validators))
+	assigned := uint64(0)
+	for idx, v := range validators {
+		share, remainder := new(big.Int).QuoRem(new(big.Int).Mul(v.Stake, new(big.Int).SetUint64(numSlots)), totalStake, new(big.Int))
+		shares[idx] = share.Uint64()
+		remainders[idx] = remainder
+		assigned += shares[idx]
+	}
+
+	order := make([]int, len(validators))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]].Cmp(remainders[order[j]]) > 0
+	})
+	for idx := 0; assigned < numSlots; idx++ {
+		shares[order[idx%len(order)]]++
+		assigned++
+	}
+	return shares
+}
+
+// Proposer returns the proposer of the given epoch.
+func (s *ProposerSchedule) Proposer(epoch uint64) core.Validator {
+	return s.validators[s.slots[epoch%ProposerScheduleRoundLength]]
+}
+
+// NumSlots returns the number of epochs the given validator proposes in a round.
+func (s *ProposerSchedule) NumSlots(id common.Address) uint64 {
+	for idx, v := range s.validators {
+		if v.ID() == id {
+			return s.shares[idx]
+		}
+	}
+	return 0
+}
+
+// SelectProposer returns the proposer of the block at the given height and epoch. The proposer
+// is selected with the proposer schedule after the HeightEnableProposerSchedule fork, and randomly
+// weighted by stake before that.
+func SelectProposer(valSet *core.ValidatorSet, height uint64, epoch uint64) core.Validator {
+	if height >= common.HeightEnableProposerSchedule {
+		return NewProposerSchedule(valSet).Proposer(epoch)
+	}
+	return selectRandomProposer(valSet, epoch)
+}
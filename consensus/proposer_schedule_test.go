@@ -0,0 +1,83 @@
+package consensus
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+func TestProposerSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(core.NewValidator("A1", big.NewInt(500)))
+	valSet.AddValidator(core.NewValidator("A2", big.NewInt(300)))
+	valSet.AddValidator(core.NewValidator("A3", big.NewInt(199)))
+	valSet.AddValidator(core.NewValidator("A4", big.NewInt(1)))
+
+	schedule := NewProposerSchedule(valSet)
+
+	// Slots are apportioned by stake and add up to the round length.
+	assert.Equal(uint64(500), schedule.NumSlots(common.HexToAddress("A1")))
+	assert.Equal(uint64(300), schedule.NumSlots(common.HexToAddress("A2")))
+	assert.Equal(uint64(199), schedule.NumSlots(common.HexToAddress("A3")))
+	assert.Equal(uint64(1), schedule.NumSlots(common.HexToAddress("A4")))
+	assert.Equal(uint64(0), schedule.NumSlots(common.HexToAddress("A5")))
+
+	counts := make(map[common.Address]uint64)
+	for epoch := uint64(0); epoch < ProposerScheduleRoundLength; epoch++ {
+		counts[schedule.Proposer(epoch).ID()]++
+	}
+	for _, v := range valSet.Validators() {
+		assert.Equal(schedule.NumSlots(v.ID()), counts[v.ID()])
+	}
+
+	// Validators with less than half of the stake never propose twice in a row.
+	for epoch := uint64(0); epoch < 2*ProposerScheduleRoundLength; epoch++ {
+		proposer := schedule.Proposer(epoch)
+		if proposer.ID() == common.HexToAddress("A1") {
+			continue
+		}
+		assert.NotEqual(proposer.ID(), schedule.Proposer(epoch+1).ID())
+	}
+
+	// The schedule repeats every round and is deterministic.
+	another := NewProposerSchedule(valSet.Copy())
+	for epoch := uint64(0); epoch < ProposerScheduleRoundLength; epoch++ {
+		assert.Equal(schedule.Proposer(epoch), schedule.Proposer(epoch+ProposerScheduleRoundLength))
+		assert.Equal(schedule.Proposer(epoch), another.Proposer(epoch))
+	}
+}
+
+func TestProposerScheduleLargestRemainder(t *testing.T) {
+	assert := assert.New(t)
+
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(core.NewValidator("A1", big.NewInt(1)))
+	valSet.AddValidator(core.NewValidator("A2", big.NewInt(1)))
+	valSet.AddValidator(core.NewValidator("A3", big.NewInt(1)))
+
+	schedule := NewProposerSchedule(valSet)
+	total := uint64(0)
+	for _, v := range valSet.Validators() {
+		slots := schedule.NumSlots(v.ID())
+		assert.True(slots == 333 || slots == 334)
+		total += slots
+	}
+	assert.Equal(uint64(ProposerScheduleRoundLength), total)
+}
+
+func TestSelectProposerBeforeFork(t *testing.T) {
+	assert := assert.New(t)
+
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(core.NewValidator("A1", big.NewInt(100)))
+	valSet.AddValidator(core.NewValidator("A2", big.NewInt(200)))
+
+	for epoch := uint64(0); epoch < 100; epoch++ {
+		assert.Equal(selectRandomProposer(valSet, epoch), SelectProposer(valSet, 1, epoch))
+	}
+}
@@ -0,0 +1,80 @@
+package simulation
+
+import (
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto/bls"
+)
+
+var _ core.Ledger = (*ledger)(nil)
+
+// ledger is a stateless ledger that accepts empty blocks and returns a fixed validator
+// candidate pool, so that the simulation exercises the consensus logic only.
+type ledger struct {
+	vcp *core.ValidatorCandidatePool
+}
+
+func newLedger(vcp *core.ValidatorCandidatePool) *ledger {
+	return &ledger{vcp: vcp}
+}
+
+func (l *ledger) GetCurrentBlock() *core.Block {
+	return nil
+}
+
+func (l *ledger) ScreenTxUnsafe(rawTx common.Bytes) result.Result {
+	return result.Error("Transactions are not supported by the simulation")
+}
+
+func (l *ledger) ScreenTx(rawTx common.Bytes) (*core.TxInfo, result.Result) {
+	return nil, result.Error("Transactions are not supported by the simulation")
+}
+
+func (l *ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorUpdateTxs bool) (common.Hash, []common.Bytes, result.Result) {
+	return common.Hash{}, []common.Bytes{}, result.OK
+}
+
+func (l *ledger) ApplyBlockTxs(block *core.Block) result.Result {
+	return result.OK
+}
+
+func (l *ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common.Hash, result.Result) {
+	return common.Hash{}, result.OK
+}
+
+func (l *ledger) ResetState(block *core.Block) result.Result {
+	return result.OK
+}
+
+func (l *ledger) FinalizeState(height uint64, rootHash common.Hash) result.Result {
+	return result.OK
+}
+
+func (l *ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash, isNext bool) (*core.ValidatorCandidatePool, error) {
+	return l.vcp, nil
+}
+
+func (l *ledger) GetFinalizedValidatorBLSPubkeys(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]*bls.PublicKey, error) {
+	return make([]*bls.PublicKey, validators.Size()), nil
+}
+
+func (l *ledger) GetFinalizedValidatorSigningAddresses(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]common.Address, error) {
+	signers := []common.Address{}
+	for _, v := range validators.Validators() {
+		signers = append(signers, v.ID())
+	}
+	return signers, nil
+}
+
+func (l *ledger) GetGuardianCandidatePool(blockHash common.Hash) (*core.GuardianCandidatePool, error) {
+	return core.NewGuardianCandidatePool(), nil
+}
+
+func (l *ledger) GetEliteEdgeNodePoolOfLastCheckpoint(blockHash common.Hash) (core.EliteEdgeNodePool, error) {
+	return nil, nil
+}
+
+func (l *ledger) PruneState(endHeight uint64) error {
+	return nil
+}
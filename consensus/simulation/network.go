@@ -0,0 +1,227 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/thetatoken/theta/p2p"
+	p2ptypes "github.com/thetatoken/theta/p2p/types"
+)
+
+// Delivery records the fate of a message sent through the simulated network.
+type Delivery struct {
+	SentAt      time.Duration
+	DeliveredAt time.Duration // zero if dropped
+	From        string
+	To          string
+	Content     interface{}
+	Dropped     bool
+}
+
+//
+// Network is an in-memory network whose message delivery is driven by the scheduler. The latency
+// of each message is drawn uniformly from the latency range, and a message is dropped with the
+// drop rate or when the sender and the receiver are in different partitions. Messages from a
+// node to itself are always delivered without delay.
+//
+type Network struct {
+	scheduler *Scheduler
+	nodes     []*Node
+
+	minLatency time.Duration
+	maxLatency time.Duration
+	dropRate   float64
+	partitions map[string]int // node ID -> partition index, empty when healed
+
+	Log []Delivery
+}
+
+// NewNetwork creates a new instance of Network.
+func NewNetwork(scheduler *Scheduler, minLatency, maxLatency time.Duration, dropRate float64) *Network {
+	return &Network{
+		scheduler:  scheduler,
+		minLatency: minLatency,
+		maxLatency: maxLatency,
+		dropRate:   dropRate,
+		partitions: make(map[string]int),
+		Log:        []Delivery{},
+	}
+}
+
+// SetLatency sets the range of the message latency.
+func (n *Network) SetLatency(minLatency, maxLatency time.Duration) {
+	n.minLatency = minLatency
+	n.maxLatency = maxLatency
+}
+
+// SetDropRate sets the probability a message is dropped.
+func (n *Network) SetDropRate(dropRate float64) {
+	n.dropRate = dropRate
+}
+
+// Partition splits the network into the given groups of node IDs. Nodes not listed in any
+// group are isolated from all the other nodes.
+func (n *Network) Partition(groups ...[]string) {
+	n.partitions = make(map[string]int)
+	for idx, group := range groups {
+		for _, id := range group {
+			n.partitions[id] = idx + 1
+		}
+	}
+}
+
+// Heal removes all the partitions.
+func (n *Network) Heal() {
+	n.partitions = make(map[string]int)
+}
+
+// Connected returns true if the two nodes can reach each other.
+func (n *Network) Connected(from, to string) bool {
+	if from == to || len(n.partitions) == 0 {
+		return true
+	}
+	p, ok := n.partitions[from]
+	return ok && p == n.partitions[to]
+}
+
+func (n *Network) addNode(node *Node) {
+	n.nodes = append(n.nodes, node)
+}
+
+// send delivers the content to the given node, or to all the other nodes if to is empty.
+func (n *Network) send(from, to string, content interface{}) {
+	for _, node := range n.nodes {
+		if (to == "" && node.ID == from) || (to != "" && node.ID != to) {
+			continue
+		}
+		n.deliver(from, node, content)
+	}
+}
+
+func (n *Network) deliver(from string, node *Node, content interface{}) {
+	delivery := Delivery{
+		SentAt:  n.scheduler.Now(),
+		From:    from,
+		To:      node.ID,
+		Content: content,
+	}
+	if !n.Connected(from, node.ID) || (from != node.ID && n.scheduler.Rand().Float64() < n.dropRate) {
+		delivery.Dropped = true
+		n.Log = append(n.Log, delivery)
+		return
+	}
+
+	latency := time.Duration(0)
+	if from != node.ID {
+		latency = n.minLatency
+		if n.maxLatency > n.minLatency {
+			latency += time.Duration(n.scheduler.Rand().Int63n(int64(n.maxLatency - n.minLatency)))
+		}
+	}
+	delivery.DeliveredAt = n.scheduler.Now() + latency
+	n.Log = append(n.Log, delivery)
+
+	n.scheduler.Schedule(latency, func() {
+		node.handleMessage(from, content)
+	})
+}
+
+// endpoint is the implementation of the p2p Network interface for a node of the simulated network.
+type endpoint struct {
+	id      string
+	network *Network
+}
+
+var _ p2p.Network = &endpoint{}
+
+// Start implements the Network interface.
+func (ep *endpoint) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop implements the Network interface.
+func (ep *endpoint) Stop() {
+}
+
+// Wait implements the Network interface.
+func (ep *endpoint) Wait() {
+}
+
+// Broadcast implements the Network interface.
+func (ep *endpoint) Broadcast(message p2ptypes.Message, skipEdgeNode bool) (successes chan bool) {
+	ep.network.send(ep.id, "", message.Content)
+	successes = make(chan bool, 1)
+	successes <- true
+	return successes
+}
+
+// BroadcastToNeighbors implements the Network interface. The simulated network is fully connected.
+func (ep *endpoint) BroadcastToNeighbors(message p2ptypes.Message, maxNumPeersToBroadcast int, skipEdgeNode bool) (successes chan bool) {
+	return ep.Broadcast(message, skipEdgeNode)
+}
+
+// Send implements the Network interface.
+func (ep *endpoint) Send(id string, message p2ptypes.Message) bool {
+	ep.network.send(ep.id, id, message.Content)
+	return true
+}
+
+// Peers implements the Network interface.
+func (ep *endpoint) Peers(skipEdgeNode bool) []string {
+	peers := []string{}
+	for _, node := range ep.network.nodes {
+		if node.ID != ep.id {
+			peers = append(peers, node.ID)
+		}
+	}
+	return peers
+}
+
+// PeerURLs implements the Network interface.
+func (ep *endpoint) PeerURLs(skipEdgeNode bool) []string {
+	return []string{}
+}
+
+// PeerExists implements the Network interface.
+func (ep *endpoint) PeerExists(peerID string) bool {
+	for _, node := range ep.network.nodes {
+		if node.ID == peerID && node.ID != ep.id {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerInfos implements the Network interface.
+func (ep *endpoint) PeerInfos(skipEdgeNode bool) []p2ptypes.PeerInfo {
+	return []p2ptypes.PeerInfo{}
+}
+
+// ConnectPeer implements the Network interface.
+func (ep *endpoint) ConnectPeer(peerAddr string) error {
+	return errors.New("ConnectPeer is not supported by the simulated network")
+}
+
+// DisconnectPeer implements the Network interface.
+func (ep *endpoint) DisconnectPeer(peerID string) bool {
+	return false
+}
+
+// BanPeer implements the Network interface.
+func (ep *endpoint) BanPeer(peerID string, duration time.Duration) bool {
+	return false
+}
+
+// ReportMisbehavior implements the Network interface.
+func (ep *endpoint) ReportMisbehavior(peerID string, misbehavior p2ptypes.Misbehavior) {
+}
+
+// RegisterMessageHandler implements the Network interface. Messages are delivered to the node directly.
+func (ep *endpoint) RegisterMessageHandler(handler p2p.MessageHandler) {
+}
+
+// ID implements the Network interface.
+func (ep *endpoint) ID() string {
+	return ep.id
+}
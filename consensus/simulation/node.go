@@ -0,0 +1,185 @@
+package simulation
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/dispatcher"
+	"github.com/thetatoken/theta/rlp"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "simulation"})
+
+//
+// Node wraps a consensus engine driven by the scheduler. It mirrors the main loop of the
+// engine with timers of the virtual time, and plays the role of the sync layer: it decodes
+// the votes and proposals from the network, adds the blocks to the chain in order, and
+// requests the missing ancestors from the sender.
+//
+type Node struct {
+	ID      string
+	PrivKey *crypto.PrivateKey
+	Engine  *consensus.ConsensusEngine
+	Chain   *blockchain.Chain
+
+	sim        *Simulation
+	voteTimer  *Event
+	epochTimer *Event
+	orphans    map[common.Hash][]*core.Block // parent hash -> blocks waiting for the parent
+
+	// Finalized contains the blocks finalized by the node, in order.
+	Finalized []*core.Block
+}
+
+func (n *Node) start() {
+	n.Engine.StartManual(n.sim.ctx, n.sim.Now)
+	n.enterEpoch()
+}
+
+func (n *Node) enterEpoch() {
+	if n.voteTimer != nil {
+		n.voteTimer.Cancel()
+	}
+	if n.epochTimer != nil {
+		n.epochTimer.Cancel()
+	}
+
+	voteInterval, epochTimeout := n.Engine.StepEnterEpoch()
+	n.voteTimer = n.sim.scheduler.Schedule(voteInterval, func() {
+		n.Engine.StepVoteTimer()
+		n.afterStep()
+	})
+	n.epochTimer = n.sim.scheduler.Schedule(epochTimeout, func() {
+		n.Engine.StepEpochTimeout()
+		n.afterStep()
+		n.enterEpoch()
+	})
+	n.afterStep()
+}
+
+func (n *Node) process(msg interface{}) {
+	endEpoch := n.Engine.StepMessage(msg)
+	n.afterStep()
+	if endEpoch {
+		n.enterEpoch()
+	}
+}
+
+// afterStep delivers the messages the engine sent to itself and collects the finalized blocks.
+func (n *Node) afterStep() {
+	for _, msg := range n.Engine.TakeSelfMessages() {
+		msg := msg
+		n.sim.scheduler.Schedule(0, func() {
+			n.process(msg)
+		})
+	}
+
+	for {
+		select {
+		case block := <-n.Engine.FinalizedBlocks():
+			n.Finalized = append(n.Finalized, block)
+		default:
+			return
+		}
+	}
+}
+
+func (n *Node) handleMessage(from string, content interface{}) {
+	switch m := content.(type) {
+	case dispatcher.DataResponse:
+		n.handleDataResponse(from, m)
+	case dispatcher.DataRequest:
+		n.handleDataRequest(from, m)
+	default:
+		// Guardian and elite edge node messages are not simulated.
+	}
+}
+
+func (n *Node) handleDataResponse(from string, data dispatcher.DataResponse) {
+	switch data.ChannelID {
+	case common.ChannelIDVote:
+		vote := core.Vote{}
+		if err := rlp.DecodeBytes(data.Payload, &vote); err != nil {
+			logger.WithFields(log.Fields{"node": n.ID, "error": err}).Panic("Failed to decode vote")
+		}
+		n.handleVote(vote)
+	case common.ChannelIDProposal:
+		proposal := &core.Proposal{}
+		if err := rlp.DecodeBytes(data.Payload, proposal); err != nil {
+			logger.WithFields(log.Fields{"node": n.ID, "error": err}).Panic("Failed to decode proposal")
+		}
+		if proposal.Votes != nil {
+			for _, vote := range proposal.Votes.Votes() {
+				n.handleVote(vote)
+			}
+		}
+		n.handleBlock(from, proposal.Block)
+	case common.ChannelIDBlock:
+		block := core.NewBlock()
+		if err := rlp.DecodeBytes(data.Payload, block); err != nil {
+			logger.WithFields(log.Fields{"node": n.ID, "error": err}).Panic("Failed to decode block")
+		}
+		n.handleBlock(from, block)
+	}
+}
+
+func (n *Node) handleDataRequest(from string, data dispatcher.DataRequest) {
+	if data.ChannelID != common.ChannelIDBlock {
+		return
+	}
+	for _, entry := range data.Entries {
+		eb, err := n.Chain.FindBlock(common.HexToHash(entry))
+		if err != nil {
+			continue
+		}
+		payload, err := rlp.EncodeToBytes(eb.Block)
+		if err != nil {
+			logger.WithFields(log.Fields{"node": n.ID, "error": err}).Panic("Failed to encode block")
+		}
+		n.sim.network.send(n.ID, from, dispatcher.DataResponse{
+			ChannelID: common.ChannelIDBlock,
+			Payload:   payload,
+		})
+	}
+}
+
+func (n *Node) handleVote(vote core.Vote) {
+	for _, v := range n.Chain.FindVotesByHash(vote.Block).Votes() {
+		if v.Epoch == vote.Epoch && v.Height == vote.Height && v.ID == vote.ID {
+			return
+		}
+	}
+	n.process(vote)
+}
+
+func (n *Node) handleBlock(from string, block *core.Block) {
+	if eb, err := n.Chain.FindBlock(block.Hash()); err == nil && !eb.Status.IsPending() {
+		return
+	}
+	if res := block.Validate(n.Chain.ChainID); res.IsError() {
+		logger.WithFields(log.Fields{"node": n.ID, "block": block.Hash().Hex(), "error": res.Message}).Warn("Ignoring invalid block")
+		return
+	}
+
+	if _, err := n.Chain.FindBlock(block.Parent); err != nil {
+		n.orphans[block.Parent] = append(n.orphans[block.Parent], block)
+		n.sim.network.send(n.ID, from, dispatcher.DataRequest{
+			ChannelID: common.ChannelIDBlock,
+			Entries:   []string{block.Parent.Hex()},
+		})
+		return
+	}
+
+	n.Chain.AddBlock(block)
+	n.process(block)
+
+	hash := block.Hash()
+	children := n.orphans[hash]
+	delete(n.orphans, hash)
+	for _, child := range children {
+		n.handleBlock(from, child)
+	}
+}
@@ -0,0 +1,129 @@
+package simulation
+
+import (
+	"container/heap"
+	"math/rand"
+	"time"
+)
+
+// Event is an action scheduled at a point of the virtual time.
+type Event struct {
+	time      time.Duration
+	seq       uint64
+	action    func()
+	cancelled bool
+}
+
+// Cancel prevents the event from being executed.
+func (ev *Event) Cancel() {
+	ev.cancelled = true
+}
+
+type eventQueue []*Event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].time != q[j].time {
+		return q[i].time < q[j].time
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) { *q = append(*q, x.(*Event)) }
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	ev := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return ev
+}
+
+//
+// Scheduler executes the scheduled events one at a time in the order of their virtual time.
+// Events scheduled at the same time are executed in the order they were scheduled. Together
+// with the seeded random source used for all the random decisions of the simulation, this
+// makes every run with the same seed identical.
+//
+type Scheduler struct {
+	now    time.Duration
+	seq    uint64
+	queue  eventQueue
+	rand   *rand.Rand
+	nSteps uint64
+}
+
+// NewScheduler creates a new instance of Scheduler.
+func NewScheduler(seed int64) *Scheduler {
+	return &Scheduler{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Now returns the virtual time elapsed since the start of the simulation.
+func (s *Scheduler) Now() time.Duration {
+	return s.now
+}
+
+// Rand returns the random source of the simulation.
+func (s *Scheduler) Rand() *rand.Rand {
+	return s.rand
+}
+
+// Steps returns the number of events executed so far.
+func (s *Scheduler) Steps() uint64 {
+	return s.nSteps
+}
+
+// Schedule executes the action after the given delay of virtual time.
+func (s *Scheduler) Schedule(delay time.Duration, action func()) *Event {
+	if delay < 0 {
+		delay = 0
+	}
+	ev := &Event{
+		time:   s.now + delay,
+		seq:    s.seq,
+		action: action,
+	}
+	s.seq++
+	heap.Push(&s.queue, ev)
+	return ev
+}
+
+// Step executes the next event. It returns false if there is no event left.
+func (s *Scheduler) Step() bool {
+	ev := s.next()
+	if ev == nil {
+		return false
+	}
+	heap.Pop(&s.queue)
+	s.now = ev.time
+	s.nSteps++
+	ev.action()
+	return true
+}
+
+// RunUntil executes the events up to the given virtual time.
+func (s *Scheduler) RunUntil(end time.Duration) {
+	for ev := s.next(); ev != nil && ev.time <= end; ev = s.next() {
+		s.Step()
+	}
+	if s.now < end {
+		s.now = end
+	}
+}
+
+// next returns the next event to execute without removing it from the queue.
+func (s *Scheduler) next() *Event {
+	for s.queue.Len() > 0 {
+		if ev := s.queue[0]; !ev.cancelled {
+			return ev
+		}
+		heap.Pop(&s.queue)
+	}
+	return nil
+}
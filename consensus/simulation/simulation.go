@@ -0,0 +1,204 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/dispatcher"
+	"github.com/thetatoken/theta/store/database/backend"
+	"github.com/thetatoken/theta/store/kvstore"
+)
+
+// ChainID is the chain ID of the simulated chain.
+const ChainID = "simchain"
+
+// GenesisTime is the virtual time at the start of the simulation.
+var GenesisTime = time.Unix(1600000000, 0)
+
+// Config specifies the initial setup of the simulation.
+type Config struct {
+	NumValidators int
+	Seed          int64
+	MinLatency    time.Duration
+	MaxLatency    time.Duration
+	DropRate      float64
+}
+
+//
+// Simulation runs multiple consensus engines over the simulated network. All the engines are
+// driven by a single scheduler with a virtual clock, so a run is fully determined by its
+// configuration and the actions applied to the network, which makes safety and liveness
+// regressions reproducible by the seed.
+//
+type Simulation struct {
+	scheduler *Scheduler
+	network   *Network
+	nodes     []*Node
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewSimulation creates a new instance of Simulation.
+func NewSimulation(config Config) (*Simulation, error) {
+	if config.NumValidators <= 0 {
+		return nil, fmt.Errorf("Invalid number of validators: %v", config.NumValidators)
+	}
+
+	scheduler := NewScheduler(config.Seed)
+	sim := &Simulation{
+		scheduler: scheduler,
+		network:   NewNetwork(scheduler, config.MinLatency, config.MaxLatency, config.DropRate),
+	}
+	sim.ctx, sim.cancel = context.WithCancel(context.Background())
+
+	privKeys := []*crypto.PrivateKey{}
+	vcp := &core.ValidatorCandidatePool{}
+	for len(privKeys) < config.NumValidators {
+		skBytes := make([]byte, 32)
+		scheduler.Rand().Read(skBytes)
+		privKey, err := crypto.PrivateKeyFromBytes(skBytes)
+		if err != nil {
+			continue
+		}
+		privKeys = append(privKeys, privKey)
+
+		address := privKey.PublicKey().Address()
+		stake := core.NewStake(address, new(big.Int).Set(core.MinValidatorStakeDeposit))
+		vcp.SortedCandidates = append(vcp.SortedCandidates, core.NewStakeHolder(address, []*core.Stake{stake}))
+	}
+
+	for _, privKey := range privKeys {
+		sim.addNode(privKey, vcp)
+	}
+	return sim, nil
+}
+
+func (sim *Simulation) addNode(privKey *crypto.PrivateKey, vcp *core.ValidatorCandidatePool) {
+	id := privKey.PublicKey().Address().Hex()
+	db := kvstore.NewKVStore(backend.NewMemDatabase())
+	chain := blockchain.NewChain(ChainID, db, newGenesisBlock())
+	ep := &endpoint{id: id, network: sim.network}
+
+	validatorManager := consensus.NewRotatingValidatorManager()
+	engine := consensus.NewConsensusEngine(privKey, db, chain, dispatcher.NewDispatcher(ep, nil), validatorManager)
+	validatorManager.SetConsensusEngine(engine)
+	engine.SetLedger(newLedger(vcp))
+
+	node := &Node{
+		ID:      id,
+		PrivKey: privKey,
+		Engine:  engine,
+		Chain:   chain,
+		sim:     sim,
+		orphans: make(map[common.Hash][]*core.Block),
+	}
+	sim.nodes = append(sim.nodes, node)
+	sim.network.addNode(node)
+}
+
+func newGenesisBlock() *core.Block {
+	block := core.NewBlock()
+	block.ChainID = ChainID
+	block.Timestamp = big.NewInt(GenesisTime.Unix())
+	return block
+}
+
+// Start starts all the consensus engines.
+func (sim *Simulation) Start() {
+	for _, node := range sim.nodes {
+		node.start()
+	}
+}
+
+// Stop stops all the consensus engines.
+func (sim *Simulation) Stop() {
+	sim.cancel()
+}
+
+// Run advances the simulation by the given duration of virtual time.
+func (sim *Simulation) Run(duration time.Duration) {
+	sim.scheduler.RunUntil(sim.scheduler.Now() + duration)
+}
+
+// Now returns the current virtual time.
+func (sim *Simulation) Now() time.Time {
+	return GenesisTime.Add(sim.scheduler.Now())
+}
+
+// Scheduler returns the scheduler of the simulation.
+func (sim *Simulation) Scheduler() *Scheduler {
+	return sim.scheduler
+}
+
+// Network returns the simulated network.
+func (sim *Simulation) Network() *Network {
+	return sim.network
+}
+
+// Nodes returns all the nodes of the simulation.
+func (sim *Simulation) Nodes() []*Node {
+	return sim.nodes
+}
+
+// NodeIDs returns the IDs of all the nodes.
+func (sim *Simulation) NodeIDs() []string {
+	ids := []string{}
+	for _, node := range sim.nodes {
+		ids = append(ids, node.ID)
+	}
+	return ids
+}
+
+// CheckSafety returns an error if two nodes finalized different blocks at the same height.
+func (sim *Simulation) CheckSafety() error {
+	finalized := make(map[uint64]*core.Block)
+	owners := make(map[uint64]string)
+	for _, node := range sim.nodes {
+		for _, block := range node.Finalized {
+			existing, ok := finalized[block.Height]
+			if !ok {
+				finalized[block.Height] = block
+				owners[block.Height] = node.ID
+				continue
+			}
+			if existing.Hash() != block.Hash() {
+				return fmt.Errorf("Conflicting blocks finalized at height %v: %v by %v, %v by %v",
+					block.Height, existing.Hash().Hex(), owners[block.Height], block.Hash().Hex(), node.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// MinFinalizedHeight returns the lowest last finalized height among the given nodes, or all
+// the nodes if none is given.
+func (sim *Simulation) MinFinalizedHeight(ids ...string) uint64 {
+	nodes := sim.nodes
+	if len(ids) > 0 {
+		nodes = []*Node{}
+		for _, node := range sim.nodes {
+			for _, id := range ids {
+				if node.ID == id {
+					nodes = append(nodes, node)
+				}
+			}
+		}
+	}
+
+	minHeight := uint64(0)
+	for idx, node := range nodes {
+		height := node.Engine.GetLastFinalizedBlock().Height
+		if idx == 0 || height < minHeight {
+			minHeight = height
+		}
+	}
+	return minHeight
+}
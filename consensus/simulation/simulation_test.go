@@ -0,0 +1,119 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestSchedulerOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewScheduler(1)
+	executed := []string{}
+	s.Schedule(2*time.Second, func() { executed = append(executed, "b") })
+	s.Schedule(time.Second, func() { executed = append(executed, "a") })
+	s.Schedule(2*time.Second, func() { executed = append(executed, "c") })
+	cancelled := s.Schedule(time.Second, func() { executed = append(executed, "x") })
+	s.Schedule(3*time.Second, func() { executed = append(executed, "d") })
+	cancelled.Cancel()
+
+	s.RunUntil(2 * time.Second)
+	assert.Equal([]string{"a", "b", "c"}, executed)
+	assert.Equal(2*time.Second, s.Now())
+
+	assert.True(s.Step())
+	assert.Equal(3*time.Second, s.Now())
+	assert.False(s.Step())
+	assert.Equal([]string{"a", "b", "c", "d"}, executed)
+	assert.Equal(uint64(4), s.Steps())
+}
+
+func TestSimulationLiveness(t *testing.T) {
+	assert := assert.New(t)
+
+	sim := newTestSimulation(t, 4, 42, 0)
+	sim.Start()
+	sim.Run(3 * time.Minute)
+	sim.Stop()
+
+	assert.Nil(sim.CheckSafety())
+	assert.True(sim.MinFinalizedHeight() >= 5, "height: %v", sim.MinFinalizedHeight())
+}
+
+func TestSimulationDeterministic(t *testing.T) {
+	assert := assert.New(t)
+
+	run := func() *Simulation {
+		sim := newTestSimulation(t, 4, 7, 0.1)
+		sim.Start()
+		sim.Run(2 * time.Minute)
+		sim.Stop()
+		return sim
+	}
+	sim1 := run()
+	sim2 := run()
+
+	assert.Equal(sim1.Scheduler().Steps(), sim2.Scheduler().Steps())
+	assert.Equal(len(sim1.Network().Log), len(sim2.Network().Log))
+	for idx, node := range sim1.Nodes() {
+		assert.Equal(finalizedHashes(node), finalizedHashes(sim2.Nodes()[idx]))
+	}
+}
+
+func TestSimulationPartition(t *testing.T) {
+	assert := assert.New(t)
+
+	sim := newTestSimulation(t, 4, 3, 0)
+	ids := sim.NodeIDs()
+	sim.Start()
+	sim.Run(time.Minute)
+	assert.True(sim.MinFinalizedHeight() > 0)
+
+	// Neither half has more than 2/3 of the stake.
+	sim.Network().Partition(ids[:2], ids[2:])
+	sim.Run(10 * time.Second)
+	height := sim.MinFinalizedHeight()
+	sim.Run(2 * time.Minute)
+	assert.Nil(sim.CheckSafety())
+	for _, node := range sim.Nodes() {
+		assert.True(node.Engine.GetLastFinalizedBlock().Height <= height+1)
+	}
+
+	// The majority keeps finalizing blocks without the isolated node.
+	sim.Network().Partition(ids[:3])
+	sim.Run(5 * time.Minute)
+	assert.Nil(sim.CheckSafety())
+	assert.True(sim.MinFinalizedHeight(ids[:3]...) > height+1)
+
+	// The isolated node catches up after the network heals.
+	sim.Network().Heal()
+	sim.Run(5 * time.Minute)
+	sim.Stop()
+	assert.Nil(sim.CheckSafety())
+	assert.True(sim.MinFinalizedHeight(ids[3]) > height+1)
+}
+
+func newTestSimulation(t *testing.T, numValidators int, seed int64, dropRate float64) *Simulation {
+	sim, err := NewSimulation(Config{
+		NumValidators: numValidators,
+		Seed:          seed,
+		MinLatency:    50 * time.Millisecond,
+		MaxLatency:    500 * time.Millisecond,
+		DropRate:      dropRate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim
+}
+
+func finalizedHashes(node *Node) []common.Hash {
+	hashes := []common.Hash{}
+	for _, block := range node.Finalized {
+		hashes = append(hashes, block.Hash())
+	}
+	return hashes
+}
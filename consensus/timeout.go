@@ -0,0 +1,40 @@
+package consensus
+
+import (
+	"math"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+)
+
+// epochTimeout calculates the epoch timeout given the number of consecutive epochs that
+// timed out without finalizing any block. Once the number exceeds the threshold, the timeout
+// grows exponentially by the backoff factor, so that the validators have enough time to
+// collect the votes and converge instead of thrashing through the epochs.
+func epochTimeout(base, max time.Duration, backoffFactor float64, backoffThreshold, consecutiveTimeouts int) time.Duration {
+	if backoffFactor <= 1.0 || consecutiveTimeouts < backoffThreshold {
+		return base
+	}
+	if max < base {
+		max = base
+	}
+
+	exponent := float64(consecutiveTimeouts - backoffThreshold + 1)
+	timeout := float64(base) * math.Pow(backoffFactor, exponent)
+	if timeout > float64(max) {
+		return max
+	}
+	return time.Duration(timeout)
+}
+
+// getEpochTimeout returns the timeout of the current epoch according to the config
+func (e *ConsensusEngine) getEpochTimeout() time.Duration {
+	return epochTimeout(
+		time.Duration(viper.GetInt(common.CfgConsensusMaxEpochLength))*time.Second,
+		time.Duration(viper.GetInt(common.CfgConsensusMaxEpochTimeout))*time.Second,
+		viper.GetFloat64(common.CfgConsensusEpochTimeoutBackoffFactor),
+		viper.GetInt(common.CfgConsensusEpochTimeoutBackoffThreshold),
+		e.consecutiveEpochTimeouts,
+	)
+}
@@ -0,0 +1,32 @@
+package consensus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEpochTimeoutBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	base := 20 * time.Second
+	max := 60 * time.Second
+
+	// No backoff below the threshold
+	assert.Equal(base, epochTimeout(base, max, 1.5, 3, 0))
+	assert.Equal(base, epochTimeout(base, max, 1.5, 3, 2))
+
+	// Exponential backoff after the threshold
+	assert.Equal(30*time.Second, epochTimeout(base, max, 1.5, 3, 3))
+	assert.Equal(45*time.Second, epochTimeout(base, max, 1.5, 3, 4))
+
+	// Capped by the max timeout
+	assert.Equal(max, epochTimeout(base, max, 1.5, 3, 10))
+
+	// Backoff disabled
+	assert.Equal(base, epochTimeout(base, max, 1.0, 3, 10))
+
+	// Max timeout smaller than the base timeout
+	assert.Equal(base, epochTimeout(base, 10*time.Second, 1.5, 3, 10))
+}
@@ -70,7 +70,8 @@ func (m *FixedValidatorManager) GetNextValidatorSet(blockHash common.Hash) *core
 var _ core.ValidatorManager = &RotatingValidatorManager{}
 
 // RotatingValidatorManager is an implementation of ValidatorManager interface that selects a random validator as
-// the proposer using validator's stake as weight.
+// the proposer using validator's stake as weight, or follows the stake-weighted round robin proposer schedule after
+// the HeightEnableProposerSchedule fork.
 type RotatingValidatorManager struct {
 	consensus core.ConsensusEngine
 }
@@ -88,15 +89,24 @@ func (m *RotatingValidatorManager) SetConsensusEngine(consensus core.ConsensusEn
 
 // GetProposer implements ValidatorManager interface.
 func (m *RotatingValidatorManager) GetProposer(blockHash common.Hash, epoch uint64) core.Validator {
-	return m.getProposerFromValidators(m.GetValidatorSet(blockHash), epoch)
+	return SelectProposer(m.GetValidatorSet(blockHash), m.getBlockHeight(blockHash), epoch)
 }
 
 // GetNextProposer implements ValidatorManager interface.
 func (m *RotatingValidatorManager) GetNextProposer(blockHash common.Hash, epoch uint64) core.Validator {
-	return m.getProposerFromValidators(m.GetNextValidatorSet(blockHash), epoch)
+	return SelectProposer(m.GetNextValidatorSet(blockHash), m.getBlockHeight(blockHash)+1, epoch)
 }
 
-func (m *RotatingValidatorManager) getProposerFromValidators(valSet *core.ValidatorSet, epoch uint64) core.Validator {
+func (m *RotatingValidatorManager) getBlockHeight(blockHash common.Hash) uint64 {
+	block, err := m.consensus.FindBlock(blockHash)
+	if err != nil {
+		log.Panicf("Failed to find block for proposer selection, blockHash: %v, err: %v", blockHash.Hex(), err)
+	}
+	return block.Height
+}
+
+// selectRandomProposer randomly selects a validator as the proposer of the epoch, using validator's stake as weight.
+func selectRandomProposer(valSet *core.ValidatorSet, epoch uint64) core.Validator {
 	if valSet.Size() == 0 {
 		log.Panic("No validators have been added")
 	}
@@ -157,6 +167,25 @@ func SelectTopStakeHoldersAsValidators(vcp *core.ValidatorCandidatePool) *core.V
 	return valSet
 }
 
+// SelectTopStakeHoldersAsValidatorsWithSigners selects the validators from the candidate pool, and sets the
+// signing addresses returned by getSigningAddress, which resolves the key rotations of the validators as of
+// the block height the validator set applies to
+func SelectTopStakeHoldersAsValidatorsWithSigners(vcp *core.ValidatorCandidatePool, height uint64,
+	getSigningAddress func(id common.Address) (common.Address, error)) (*core.ValidatorSet, error) {
+	valSet := SelectTopStakeHoldersAsValidators(vcp)
+	if height < common.HeightEnableValidatorKeyRotation {
+		return valSet, nil
+	}
+	for _, v := range valSet.Validators() {
+		signer, err := getSigningAddress(v.ID())
+		if err != nil {
+			return nil, err
+		}
+		valSet.SetSigningAddress(v.ID(), signer)
+	}
+	return valSet, nil
+}
+
 func selectTopStakeHoldersAsValidatorsForBlock(consensus core.ConsensusEngine, blockHash common.Hash, isNext bool) *core.ValidatorSet {
 	vcp, err := consensus.GetLedger().GetFinalizedValidatorCandidatePool(blockHash, isNext)
 	if err != nil {
@@ -166,7 +195,15 @@ func selectTopStakeHoldersAsValidatorsForBlock(consensus core.ConsensusEngine, b
 		log.Panic("Failed to retrieve the validator candidate pool")
 	}
 
-	return SelectTopStakeHoldersAsValidators(vcp)
+	valSet := SelectTopStakeHoldersAsValidators(vcp)
+	signers, err := consensus.GetLedger().GetFinalizedValidatorSigningAddresses(blockHash, isNext, valSet)
+	if err != nil {
+		log.Panicf("Failed to get the validator signing addresses, blockHash: %v, isNext: %v, err: %v", blockHash.Hex(), isNext, err)
+	}
+	for idx, v := range valSet.Validators() {
+		valSet.SetSigningAddress(v.ID(), signers[idx])
+	}
+	return valSet
 }
 
 // Generate a random uint64 in [0, max)
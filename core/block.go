@@ -126,8 +126,9 @@ type BlockHeader struct {
 	Height             uint64
 	Parent             common.Hash
 	HCC                CommitCertificate
-	GuardianVotes      *AggregatedVotes    `rlp:"nil"` // Added in Theta2.0 fork.
-	EliteEdgeNodeVotes *AggregatedEENVotes `rlp:"nil"` // Added in Theta3.0 fork.
+	GuardianVotes      *AggregatedVotes          `rlp:"nil"` // Added in Theta2.0 fork.
+	EliteEdgeNodeVotes *AggregatedEENVotes       `rlp:"nil"` // Added in Theta3.0 fork.
+	ValidatorVotes     *AggregatedValidatorVotes `rlp:"nil"` // Added in validator vote aggregation fork.
 	TxHash             common.Hash
 	ReceiptHash        common.Hash `json:"-"`
 	Bloom              Bloom       `json:"-"`
@@ -141,70 +142,68 @@ type BlockHeader struct {
 
 var _ rlp.Encoder = (*BlockHeader)(nil)
 
-// EncodeRLP implements RLP Encoder interface.
+// EncodeRLP implements RLP Encoder interface. The fields are appended directly rather than
+// through reflection since headers are encoded for every hash, and the later forks append
+// their fields to the end.
 func (h *BlockHeader) EncodeRLP(w io.Writer) error {
 	if h == nil {
 		return rlp.Encode(w, &BlockHeader{})
 	}
-	if h.Height < common.HeightEnableTheta2 {
-		return rlp.Encode(w, []interface{}{
-			h.ChainID,
-			h.Epoch,
-			h.Height,
-			h.Parent,
-			h.HCC,
-			h.TxHash,
-			h.ReceiptHash,
-			h.Bloom,
-			h.StateHash,
-			h.Timestamp,
-			h.Proposer,
-			h.Signature,
-		})
+
+	var err error
+	b := make([]byte, 0, 512)
+	b = rlp.AppendString(b, []byte(h.ChainID))
+	b = rlp.AppendUint64(b, h.Epoch)
+	b = rlp.AppendUint64(b, h.Height)
+	b = rlp.AppendString(b, h.Parent[:])
+	if b, err = rlp.AppendValue(b, h.HCC); err != nil {
+		return err
+	}
+	b = rlp.AppendString(b, h.TxHash[:])
+	b = rlp.AppendString(b, h.ReceiptHash[:])
+	b = rlp.AppendString(b, h.Bloom[:])
+	b = rlp.AppendString(b, h.StateHash[:])
+	if b, err = rlp.AppendBigInt(b, h.Timestamp); err != nil {
+		return err
+	}
+	b = rlp.AppendString(b, h.Proposer[:])
+	if h.Signature == nil {
+		b = append(b, rlp.EmptyString...)
+	} else {
+		b = rlp.AppendString(b, h.Signature.ToBytes())
 	}
 
 	// Theta2.0 fork
-	if h.Height >= common.HeightEnableTheta2 && h.Height < common.HeightEnableTheta3 {
-		return rlp.Encode(w, []interface{}{
-			h.ChainID,
-			h.Epoch,
-			h.Height,
-			h.Parent,
-			h.HCC,
-			h.TxHash,
-			h.ReceiptHash,
-			h.Bloom,
-			h.StateHash,
-			h.Timestamp,
-			h.Proposer,
-			h.Signature,
-			h.GuardianVotes,
-		})
+	if h.Height >= common.HeightEnableTheta2 {
+		if b, err = rlp.AppendValue(b, h.GuardianVotes); err != nil {
+			return err
+		}
 	}
 
 	// Theta3.0 fork
-	return rlp.Encode(w, []interface{}{
-		h.ChainID,
-		h.Epoch,
-		h.Height,
-		h.Parent,
-		h.HCC,
-		h.TxHash,
-		h.ReceiptHash,
-		h.Bloom,
-		h.StateHash,
-		h.Timestamp,
-		h.Proposer,
-		h.Signature,
-		h.GuardianVotes,
-		h.EliteEdgeNodeVotes,
-	})
+	if h.Height >= common.HeightEnableTheta3 {
+		if b, err = rlp.AppendValue(b, h.EliteEdgeNodeVotes); err != nil {
+			return err
+		}
+	}
+
+	// Validator vote aggregation fork
+	if h.Height >= common.HeightEnableValidatorVoteAggregation {
+		if b, err = rlp.AppendValue(b, h.ValidatorVotes); err != nil {
+			return err
+		}
+	}
+
+	_, err = w.Write(rlp.AppendList(nil, b))
+	return err
 }
 
 var _ rlp.Decoder = (*BlockHeader)(nil)
 
 // DecodeRLP implements RLP Decoder interface.
 func (h *BlockHeader) DecodeRLP(stream *rlp.Stream) error {
+	h.hash = common.Hash{}
+
 	_, err := stream.List()
 	if err != nil {
 		return err
@@ -307,6 +306,24 @@ func (h *BlockHeader) DecodeRLP(stream *rlp.Stream) error {
 		}
 	}
 
+	// Validator vote aggregation fork
+	if h.Height >= common.HeightEnableValidatorVoteAggregation {
+		raw, err := stream.Raw()
+		if err != nil {
+			return err
+		}
+		if common.Bytes2Hex(raw) == "c0" {
+			h.ValidatorVotes = nil
+		} else {
+			vvotes := &AggregatedValidatorVotes{}
+			err = rlp.DecodeBytes(raw, vvotes)
+			if err != nil {
+				return err
+			}
+			h.ValidatorVotes = vvotes
+		}
+	}
+
 	return stream.ListEnd()
 }
 
@@ -353,9 +370,10 @@ func (h *BlockHeader) SignBytes() common.Bytes {
 	return raw
 }
 
-// SetSignature sets given signature in header.
+// SetSignature sets given signature in header, and clears the cached hash.
 func (h *BlockHeader) SetSignature(sig *crypto.Signature) {
 	h.Signature = sig
+	h.hash = common.Hash{}
 }
 
 // Validate checks the header is legitimate.
@@ -369,6 +387,9 @@ func (h *BlockHeader) Validate(chainID string) result.Result {
 	if h.HCC.BlockHash.IsEmpty() {
 		return result.Error("HCC is empty")
 	}
+	if h.ValidatorVotes != nil && h.Height < common.HeightEnableValidatorVoteAggregation {
+		return result.Error("Validator votes are not enabled yet")
+	}
 	if h.Timestamp == nil {
 		return result.Error("Timestamp is missing")
 	}
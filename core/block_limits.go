@@ -0,0 +1,75 @@
+package core
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultMaxBlockGas is the default max total gas of the transactions in a block
+	DefaultMaxBlockGas uint64 = 100e6
+
+	// DefaultMaxBlockSize is the default max total serialized size (in bytes) of the transactions in a block
+	DefaultMaxBlockSize uint64 = 8 * 1024 * 1024
+)
+
+//
+// BlockLimits specifies the max total gas and serialized size of the transactions in a block.
+// The limits are enforced after the HeightEnableBlockLimits fork, both when the proposer
+// assembles a block and when the other nodes validate it.
+//
+type BlockLimits struct {
+	MaxGas  uint64 // Max total gas of the transactions
+	MaxSize uint64 // Max total serialized size of the transactions, in bytes
+}
+
+// chainBlockLimits specifies the initial block limits of each chain. The limits can be adjusted
+// later by governance through BlockLimitsUpdates, which stores the new limits in the ledger state.
+var chainBlockLimits = map[string]BlockLimits{
+	MainnetChainID: {MaxGas: DefaultMaxBlockGas, MaxSize: DefaultMaxBlockSize},
+}
+
+//
+// BlockLimitsUpdate is a block limits adjustment approved by governance. The new limits are written
+// to the ledger state when the block at the given height is applied, and are enforced from the
+// next block on.
+//
+type BlockLimitsUpdate struct {
+	Height uint64
+	Limits BlockLimits
+}
+
+// BlockLimitsUpdates lists the block limits adjustments of each chain
+var BlockLimitsUpdates = map[string][]BlockLimitsUpdate{}
+
+// GetBlockLimitsUpdate returns the block limits adjustment of the given chain scheduled at the given height, if any.
+func GetBlockLimitsUpdate(chainID string, height uint64) (BlockLimits, bool) {
+	for _, update := range BlockLimitsUpdates[chainID] {
+		if update.Height == height {
+			return update.Limits, true
+		}
+	}
+	return BlockLimits{}, false
+}
+
+// DefaultBlockLimits returns the initial block limits of the given chain.
+func DefaultBlockLimits(chainID string) BlockLimits {
+	if limits, ok := chainBlockLimits[chainID]; ok {
+		return limits
+	}
+	return BlockLimits{MaxGas: DefaultMaxBlockGas, MaxSize: DefaultMaxBlockSize}
+}
+
+func (bl BlockLimits) String() string {
+	return fmt.Sprintf("BlockLimits{MaxGas: %v, MaxSize: %v}", bl.MaxGas, bl.MaxSize)
+}
+
+// Validate checks the limits are usable.
+func (bl BlockLimits) Validate() error {
+	if bl.MaxGas == 0 {
+		return fmt.Errorf("max block gas cannot be zero")
+	}
+	if bl.MaxSize == 0 {
+		return fmt.Errorf("max block size cannot be zero")
+	}
+	return nil
+}
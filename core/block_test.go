@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,6 +98,37 @@ func TestBlockHash(t *testing.T) {
 	assert.Equal("0x87a331c1e807476de260f2dc2e4d531dc42500764587605c7574179bc4cbd5bc", eb.Hash().Hex())
 }
 
+func TestBlockHashCache(t *testing.T) {
+	assert := assert.New(t)
+
+	newHeader := func(epoch uint64) *BlockHeader {
+		return &BlockHeader{
+			ChainID:   "testchain",
+			Epoch:     epoch,
+			Height:    1,
+			Timestamp: big.NewInt(1),
+		}
+	}
+
+	header := newHeader(1)
+	hash := header.Hash()
+	assert.Equal(hash, header.CalculateHash())
+
+	// Setting the signature clears the cached hash
+	privKey, _, _ := crypto.GenerateKeyPair()
+	sig, err := privKey.Sign(header.SignBytes())
+	assert.Nil(err)
+	header.SetSignature(sig)
+	assert.NotEqual(hash, header.Hash())
+	assert.Equal(header.CalculateHash(), header.Hash())
+
+	// Decoding into a header with a cached hash clears it
+	raw, err := rlp.EncodeToBytes(newHeader(2))
+	assert.Nil(err)
+	assert.Nil(rlp.DecodeBytes(raw, header))
+	assert.Equal(newHeader(2).Hash(), header.Hash())
+}
+
 func TestCreateTestBlock(t *testing.T) {
 	assert := assert.New(t)
 
@@ -168,3 +200,73 @@ func TestBlockBasicValidation(t *testing.T) {
 	require.True(res.IsError())
 	require.Equal("Signature verification failed", res.Message)
 }
+
+// legacyHeaderFields returns the fields of the header as they were encoded through reflection
+func legacyHeaderFields(h *BlockHeader) []interface{} {
+	fields := []interface{}{
+		h.ChainID,
+		h.Epoch,
+		h.Height,
+		h.Parent,
+		h.HCC,
+		h.TxHash,
+		h.ReceiptHash,
+		h.Bloom,
+		h.StateHash,
+		h.Timestamp,
+		h.Proposer,
+		h.Signature,
+	}
+	if h.Height >= common.HeightEnableTheta2 {
+		fields = append(fields, h.GuardianVotes)
+	}
+	if h.Height >= common.HeightEnableTheta3 {
+		fields = append(fields, h.EliteEdgeNodeVotes)
+	}
+	if h.Height >= common.HeightEnableValidatorVoteAggregation {
+		fields = append(fields, h.ValidatorVotes)
+	}
+	return fields
+}
+
+func TestBlockHeaderEncodingLegacy(t *testing.T) {
+	require := require.New(t)
+
+	CreateTestBlock("root", "")
+	block := CreateTestBlock("b1", "root")
+	for _, height := range []uint64{1, common.HeightEnableTheta2, common.HeightEnableTheta3} {
+		header := *block.BlockHeader
+		header.Height = height
+		header.GuardianVotes = NewAggregateVotes(header.Parent, NewGuardianCandidatePool())
+
+		encoded, err := rlp.EncodeToBytes(&header)
+		require.Nil(err)
+		expected, err := rlp.EncodeToBytes(legacyHeaderFields(&header))
+		require.Nil(err)
+		require.Equal(expected, encoded)
+
+		header.Signature = nil
+		header.Timestamp = nil
+		encoded, err = rlp.EncodeToBytes(&header)
+		require.Nil(err)
+		expected, err = rlp.EncodeToBytes(legacyHeaderFields(&header))
+		require.Nil(err)
+		require.Equal(expected, encoded)
+	}
+}
+
+func BenchmarkBlockHeaderEncodeRLP(b *testing.B) {
+	header := CreateTestBlock("b1", "").BlockHeader
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(header)
+	}
+}
+
+func BenchmarkBlockHeaderEncodeRLPReflect(b *testing.B) {
+	fields := legacyHeaderFields(CreateTestBlock("b1", "").BlockHeader)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(fields)
+	}
+}
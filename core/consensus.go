@@ -9,7 +9,9 @@ import (
 type ConsensusEngine interface {
 	ID() string
 	PrivateKey() *crypto.PrivateKey
+	Signer() Signer
 	GetTip(includePendingBlockingLeaf bool) *ExtendedBlock
+	FindBlock(blockHash common.Hash) (*ExtendedBlock, error)
 	GetEpoch() uint64
 	GetLedger() Ledger
 	AddMessage(msg interface{})
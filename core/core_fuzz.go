@@ -0,0 +1,84 @@
+// +build gofuzz
+
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/thetatoken/theta/rlp"
+)
+
+// The harnesses below are built with go-fuzz-build, e.g.
+//   go-fuzz-build -func FuzzBlock -o block-fuzz.zip ./core
+// and return 1 for the inputs that decode, so that the fuzzer favors them.
+
+// FuzzBlock fuzzes the decoding of the blocks received from the peers
+func FuzzBlock(data []byte) int {
+	block := NewBlock()
+	if err := rlp.DecodeBytes(data, block); err != nil {
+		return 0
+	}
+	block.Hash()
+	block.Validate("testchain")
+	if _, err := rlp.EncodeToBytes(block); err != nil {
+		panic(fmt.Sprintf("Failed to re-encode a decoded block: %v", err))
+	}
+	return 1
+}
+
+// FuzzVote fuzzes the decoding of the votes and the vote sets received from the peers
+func FuzzVote(data []byte) int {
+	if len(data) == 0 {
+		return -1
+	}
+	if data[0]%2 == 0 {
+		vote := &Vote{}
+		if err := rlp.DecodeBytes(data[1:], vote); err != nil {
+			return 0
+		}
+		vote.Hash()
+		vote.Validate()
+		return 1
+	}
+	voteSet := NewVoteSet()
+	if err := rlp.DecodeBytes(data[1:], voteSet); err != nil {
+		return 0
+	}
+	voteSet.Validate()
+	return 1
+}
+
+// FuzzSnapshotRecord fuzzes the reading of the records of a snapshot file. The first byte selects
+// the type of the records.
+func FuzzSnapshotRecord(data []byte) int {
+	if len(data) == 0 {
+		return -1
+	}
+	newRecord := func() interface{} {
+		switch data[0] % 5 {
+		case 0:
+			return &SnapshotTrieRecord{}
+		case 1:
+			return &SnapshotMetadata{}
+		case 2:
+			return &LastCheckpoint{}
+		case 3:
+			return &SnapshotHeader{}
+		default:
+			return &SnapshotBlockTrio{}
+		}
+	}
+	file := bytes.NewReader(data[1:])
+	decoded := 0
+	for {
+		if _, err := ReadRecord(file, newRecord()); err != nil {
+			break
+		}
+		decoded++
+	}
+	if decoded == 0 {
+		return 0
+	}
+	return 1
+}
@@ -103,6 +103,24 @@ func (a *AggregatedVotes) Abs() int {
 	return ret
 }
 
+// Bitmap returns the participation bitmap of the vote. Bit i, counted from the least significant bit
+// of the first byte, is set if the i-th guardian with stake in the pool has signed.
+func (a *AggregatedVotes) Bitmap() common.Bytes {
+	bitmap := make(common.Bytes, (len(a.Multiplies)+7)/8)
+	for i := 0; i < len(a.Multiplies); i++ {
+		if a.Multiplies[i] != 0 {
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return bitmap
+}
+
+// SignBytes returns the bytes signed by the guardians, for the external verifiers to check the
+// aggregated signature.
+func (a *AggregatedVotes) SignBytes() common.Bytes {
+	return a.signBytes()
+}
+
 // Pick selects better vote from two votes.
 func (a *AggregatedVotes) Pick(b *AggregatedVotes) (*AggregatedVotes, error) {
 	if a.Block != b.Block || a.Gcp != b.Gcp {
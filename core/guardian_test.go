@@ -190,3 +190,23 @@ func TestAggregateVoteEncoding(t *testing.T) {
 	err = rlp.DecodeBytes(raw, vote2)
 	require.Nil(err)
 }
+
+func TestAggregateVoteBitmap(t *testing.T) {
+	require := require.New(t)
+
+	pool, sks := createTestGuardianPool(10)
+
+	bh := common.BytesToHash([]byte{12})
+	vote := NewAggregateVotes(bh, pool)
+	require.Equal(common.Bytes{0, 0}, vote.Bitmap())
+
+	for _, idx := range []int{0, 3, 9} {
+		require.True(vote.Sign(sks[pool.SortedGuardians[idx].Holder], idx))
+	}
+	require.Equal(common.Bytes{0x09, 0x02}, vote.Bitmap())
+
+	// The aggregated signature verifies against the sign bytes and the signers' keys
+	pubKeys := pool.WithStake().PubKeys()
+	aggPubkey := bls.AggregatePublicKeysVec(pubKeys, vote.Multiplies)
+	require.True(vote.Signature.Verify(vote.SignBytes(), aggPubkey))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/crypto/bls"
 )
 
 type ViewSelector int
@@ -38,6 +39,8 @@ type Ledger interface {
 	ResetState(block *Block) result.Result
 	FinalizeState(height uint64, rootHash common.Hash) result.Result
 	GetFinalizedValidatorCandidatePool(blockHash common.Hash, isNext bool) (*ValidatorCandidatePool, error)
+	GetFinalizedValidatorBLSPubkeys(blockHash common.Hash, isNext bool, validators *ValidatorSet) ([]*bls.PublicKey, error)
+	GetFinalizedValidatorSigningAddresses(blockHash common.Hash, isNext bool, validators *ValidatorSet) ([]common.Address, error)
 	GetGuardianCandidatePool(blockHash common.Hash) (*GuardianCandidatePool, error)
 	GetEliteEdgeNodePoolOfLastCheckpoint(blockHash common.Hash) (EliteEdgeNodePool, error)
 	PruneState(endHeight uint64) error
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+// Signer signs the votes, the block proposals and the special transactions (e.g. the coinbase
+// transaction) on behalf of the validator.
+type Signer interface {
+	PublicKey() *crypto.PublicKey
+	SignVote(vote Vote) (*crypto.Signature, error)
+	SignBlock(header *BlockHeader) (*crypto.Signature, error)
+	SignTx(signBytes common.Bytes) (*crypto.Signature, error)
+}
+
+var _ Signer = (*LocalSigner)(nil)
+
+// LocalSigner signs with the private key of the node.
+type LocalSigner struct {
+	privKey *crypto.PrivateKey
+}
+
+// NewLocalSigner creates an instance of LocalSigner.
+func NewLocalSigner(privKey *crypto.PrivateKey) *LocalSigner {
+	return &LocalSigner{
+		privKey: privKey,
+	}
+}
+
+// PublicKey implements the Signer interface.
+func (s *LocalSigner) PublicKey() *crypto.PublicKey {
+	return s.privKey.PublicKey()
+}
+
+// SignVote implements the Signer interface.
+func (s *LocalSigner) SignVote(vote Vote) (*crypto.Signature, error) {
+	return s.privKey.Sign(vote.SignBytes())
+}
+
+// SignBlock implements the Signer interface.
+func (s *LocalSigner) SignBlock(header *BlockHeader) (*crypto.Signature, error) {
+	return s.privKey.Sign(header.SignBytes())
+}
+
+// SignTx implements the Signer interface.
+func (s *LocalSigner) SignTx(signBytes common.Bytes) (*crypto.Signature, error) {
+	return s.privKey.Sign(signBytes)
+}
@@ -7,13 +7,19 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"os"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/rlp"
 )
 
 const SnapshotHeaderMagic = "ThetaToDaMoon"
+
+// The range of the snapshot format versions supported. Version 1 snapshots have no header, and
+// the version of the others is recorded in the header.
+const (
+	SnapshotVersionMin    uint = 1
+	SnapshotVersionLatest uint = 4
+)
 const BlockTrioStoreKeyPrefix = "prooftrio_"
 const (
 	SVStart = iota
@@ -53,6 +59,7 @@ type SnapshotHeader struct {
 type SnapshotMetadata struct {
 	ProofTrios []SnapshotBlockTrio
 	TailTrio   SnapshotBlockTrio
+	Version    uint // The snapshot format version, 0 if not recorded in the metadata
 }
 
 type LastCheckpoint struct {
@@ -60,6 +67,45 @@ type LastCheckpoint struct {
 	IntermediateHeaders []*BlockHeader
 }
 
+// CheckSnapshotVersion returns an error if the snapshot format version is not supported
+func CheckSnapshotVersion(version uint) error {
+	if version < SnapshotVersionMin || version > SnapshotVersionLatest {
+		return fmt.Errorf("Unsupported snapshot version %v, the supported versions are %v to %v. The snapshot might have been exported by a newer version of the software",
+			version, SnapshotVersionMin, SnapshotVersionLatest)
+	}
+	return nil
+}
+
+// CheckVersion checks the metadata version matches the version in the snapshot header. The metadata
+// written by the older software has no version, which is then taken from the header.
+func (m *SnapshotMetadata) CheckVersion(headerVersion uint) error {
+	if m.Version == 0 {
+		m.Version = headerVersion
+		return nil
+	}
+	if m.Version != headerVersion {
+		return fmt.Errorf("Snapshot metadata version %v does not match the header version %v", m.Version, headerVersion)
+	}
+	return nil
+}
+
+// ReadSnapshotHeader reads the header at the beginning of the snapshot file, and checks its
+// version. The file is rewound for the version 1 snapshots, which have no header.
+func ReadSnapshotHeader(file io.ReadSeeker) (*SnapshotHeader, error) {
+	snapshotHeader := &SnapshotHeader{}
+	_, err := ReadRecord(file, snapshotHeader)
+	if err != nil || snapshotHeader.Magic != SnapshotHeaderMagic { // older version, reset the file
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return &SnapshotHeader{Version: 1}, nil
+	}
+	if err := CheckSnapshotVersion(snapshotHeader.Version); err != nil {
+		return nil, err
+	}
+	return snapshotHeader, nil
+}
+
 func WriteSnapshotHeader(writer *bufio.Writer, snapshotHeader *SnapshotHeader) error {
 	raw, err := rlp.EncodeToBytes(*snapshotHeader)
 	if err != nil {
@@ -90,6 +136,45 @@ func WriteMetadata(writer *bufio.Writer, metadata *SnapshotMetadata) error {
 	return err
 }
 
+var _ rlp.Encoder = (*SnapshotMetadata)(nil)
+
+// EncodeRLP implements RLP Encoder interface. The version is omitted if not set, the same as in
+// the metadata written by the older software.
+func (m SnapshotMetadata) EncodeRLP(w io.Writer) error {
+	if m.Version == 0 {
+		return rlp.Encode(w, []interface{}{m.ProofTrios, m.TailTrio})
+	}
+	return rlp.Encode(w, []interface{}{m.ProofTrios, m.TailTrio, m.Version})
+}
+
+var _ rlp.Decoder = (*SnapshotMetadata)(nil)
+
+// DecodeRLP implements RLP Decoder interface. It accepts the metadata with or without the
+// version, and rejects the unsupported versions, whose fields might differ.
+func (m *SnapshotMetadata) DecodeRLP(stream *rlp.Stream) error {
+	if _, err := stream.List(); err != nil {
+		return err
+	}
+	if err := stream.Decode(&m.ProofTrios); err != nil {
+		return err
+	}
+	if err := stream.Decode(&m.TailTrio); err != nil {
+		return err
+	}
+	m.Version = 0
+	err := stream.Decode(&m.Version)
+	if err == rlp.EOL {
+		return stream.ListEnd()
+	}
+	if err != nil {
+		return err
+	}
+	if err := CheckSnapshotVersion(m.Version); err != nil {
+		return err
+	}
+	return stream.ListEnd()
+}
+
 func WriteRecord(writer *bufio.Writer, k, v common.Bytes) error {
 	record := SnapshotTrieRecord{K: k, V: v}
 	raw, err := rlp.EncodeToBytes(record)
@@ -121,7 +206,7 @@ func writeBytes(writer *bufio.Writer, raw []byte) error {
 	return nil
 }
 
-func ReadRecord(file *os.File, obj interface{}) (uint64, error) {
+func ReadRecord(file io.Reader, obj interface{}) (uint64, error) {
 	sizeBytes := make([]byte, 8)
 	n, err := io.ReadAtLeast(file, sizeBytes, 8)
 	if err != nil {
@@ -131,15 +216,17 @@ func ReadRecord(file *os.File, obj interface{}) (uint64, error) {
 		return 0, fmt.Errorf("Failed to read record length")
 	}
 	size := Bytestoi(sizeBytes)
-	bytes := make([]byte, size)
-	n, err = io.ReadAtLeast(file, bytes, int(size))
+	// The record is read incrementally instead of allocating the size upfront, since the size
+	// comes from the input and a corrupted one could exhaust the memory
+	buff := new(bytes.Buffer)
+	read, err := io.CopyN(buff, file, int64(size))
+	if uint64(read) < size {
+		return 0, fmt.Errorf("Failed to read record, %v < %v", read, size)
+	}
 	if err != nil {
 		return 0, err
 	}
-	if uint64(n) < size {
-		return 0, fmt.Errorf("Failed to read record, %v < %v", n, size)
-	}
-	err = rlp.DecodeBytes(bytes, obj)
+	err = rlp.DecodeBytes(buff.Bytes(), obj)
 	return size, err
 }
 
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rlp"
+)
+
+func newTestSnapshotMetadata(version uint) SnapshotMetadata {
+	newHeader := func(height uint64) *BlockHeader {
+		return &BlockHeader{
+			ChainID:   "testchain",
+			Height:    height,
+			Timestamp: big.NewInt(1),
+		}
+	}
+	return SnapshotMetadata{
+		ProofTrios: []SnapshotBlockTrio{},
+		TailTrio: SnapshotBlockTrio{
+			First:  SnapshotFirstBlock{Header: newHeader(1)},
+			Second: SnapshotSecondBlock{Header: newHeader(2)},
+			Third:  SnapshotThirdBlock{Header: newHeader(3), VoteSet: NewVoteSet()},
+		},
+		Version: version,
+	}
+}
+
+func TestSnapshotMetadataVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	// The metadata without the version is encoded as before
+	type legacyMetadata struct {
+		ProofTrios []SnapshotBlockTrio
+		TailTrio   SnapshotBlockTrio
+	}
+	metadata := newTestSnapshotMetadata(0)
+	raw, err := rlp.EncodeToBytes(metadata)
+	assert.Nil(err)
+	legacyRaw, err := rlp.EncodeToBytes(legacyMetadata{metadata.ProofTrios, metadata.TailTrio})
+	assert.Nil(err)
+	assert.Equal(legacyRaw, raw)
+
+	decoded := SnapshotMetadata{Version: 3}
+	assert.Nil(rlp.DecodeBytes(raw, &decoded))
+	assert.Equal(uint(0), decoded.Version)
+	assert.Equal(uint64(2), decoded.TailTrio.Second.Header.Height)
+
+	raw, err = rlp.EncodeToBytes(newTestSnapshotMetadata(SnapshotVersionLatest))
+	assert.Nil(err)
+	assert.Nil(rlp.DecodeBytes(raw, &decoded))
+	assert.Equal(SnapshotVersionLatest, decoded.Version)
+
+	raw, err = rlp.EncodeToBytes(newTestSnapshotMetadata(SnapshotVersionLatest + 1))
+	assert.Nil(err)
+	err = rlp.DecodeBytes(raw, &decoded)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "Unsupported snapshot version")
+}
+
+func TestSnapshotMetadataCheckVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	metadata := newTestSnapshotMetadata(0)
+	assert.Nil(metadata.CheckVersion(3))
+	assert.Equal(uint(3), metadata.Version)
+
+	metadata = newTestSnapshotMetadata(SnapshotVersionLatest)
+	assert.Nil(metadata.CheckVersion(SnapshotVersionLatest))
+	assert.NotNil(metadata.CheckVersion(SnapshotVersionLatest - 1))
+}
+
+func TestReadSnapshotHeader(t *testing.T) {
+	assert := assert.New(t)
+
+	writeHeader := func(version uint) *bytes.Reader {
+		buf := &bytes.Buffer{}
+		writer := bufio.NewWriter(buf)
+		assert.Nil(WriteSnapshotHeader(writer, &SnapshotHeader{Magic: SnapshotHeaderMagic, Version: version}))
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	header, err := ReadSnapshotHeader(writeHeader(SnapshotVersionLatest))
+	assert.Nil(err)
+	assert.Equal(SnapshotVersionLatest, header.Version)
+
+	_, err = ReadSnapshotHeader(writeHeader(SnapshotVersionLatest + 1))
+	assert.NotNil(err)
+
+	// Version 1 snapshots start with the metadata
+	buf := &bytes.Buffer{}
+	writer := bufio.NewWriter(buf)
+	metadata := newTestSnapshotMetadata(0)
+	assert.Nil(WriteMetadata(writer, &metadata))
+	file := bytes.NewReader(buf.Bytes())
+	header, err = ReadSnapshotHeader(file)
+	assert.Nil(err)
+	assert.Equal(uint(1), header.Version)
+	decoded := SnapshotMetadata{}
+	_, err = ReadRecord(file, &decoded)
+	assert.Nil(err)
+	assert.Equal(uint64(3), decoded.TailTrio.Third.Header.Height)
+}
+
+func TestReadRecordTruncated(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	writer := bufio.NewWriter(buf)
+	assert.Nil(WriteRecord(writer, common.Bytes("key"), common.Bytes("value")))
+	raw := buf.Bytes()
+
+	record := SnapshotTrieRecord{}
+	size, err := ReadRecord(bytes.NewReader(raw), &record)
+	assert.Nil(err)
+	assert.Equal(uint64(len(raw)-8), size)
+	assert.Equal(common.Bytes("value"), record.V)
+
+	_, err = ReadRecord(bytes.NewReader(raw[:len(raw)-1]), &record)
+	assert.NotNil(err)
+
+	// A corrupted size is rejected without allocating it
+	corrupted := append(Itobytes(1<<62), raw[8:]...)
+	_, err = ReadRecord(bytes.NewReader(corrupted), &record)
+	assert.NotNil(err)
+}
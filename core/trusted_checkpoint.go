@@ -0,0 +1,35 @@
+package core
+
+import (
+	"github.com/thetatoken/theta/common"
+)
+
+// TrustedCheckpoint is a block trusted by the node operator. The node refuses to follow
+// any chain that does not pass through it, and skips the full verification of the blocks
+// before it.
+type TrustedCheckpoint struct {
+	Height           uint64
+	BlockHash        common.Hash
+	ValidatorSetHash common.Hash // optional, the hash of the validator set of the checkpoint block
+}
+
+var trustedCheckpoint *TrustedCheckpoint
+
+// SetTrustedCheckpoint sets the trusted checkpoint. The checkpoint block is also added to
+// the hardcoded block hashes so that no other block is accepted at its height.
+func SetTrustedCheckpoint(cp *TrustedCheckpoint) {
+	trustedCheckpoint = cp
+	if cp != nil {
+		HardcodeBlockHashes[cp.Height] = cp.BlockHash.Hex()
+	}
+}
+
+// GetTrustedCheckpoint returns the trusted checkpoint, or nil if not configured.
+func GetTrustedCheckpoint() *TrustedCheckpoint {
+	return trustedCheckpoint
+}
+
+// IsBeforeTrustedCheckpoint checks whether a block at the given height precedes the trusted checkpoint.
+func IsBeforeTrustedCheckpoint(height uint64) bool {
+	return trustedCheckpoint != nil && height < trustedCheckpoint.Height
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestTrustedCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(GetTrustedCheckpoint())
+	assert.False(IsBeforeTrustedCheckpoint(10))
+
+	cp := &TrustedCheckpoint{
+		Height:    100,
+		BlockHash: common.HexToHash("0x1234"),
+	}
+	SetTrustedCheckpoint(cp)
+	defer func() {
+		SetTrustedCheckpoint(nil)
+		delete(HardcodeBlockHashes, cp.Height)
+	}()
+
+	assert.Equal(cp, GetTrustedCheckpoint())
+	assert.Equal(cp.BlockHash.Hex(), HardcodeBlockHashes[cp.Height])
+	assert.True(IsBeforeTrustedCheckpoint(99))
+	assert.False(IsBeforeTrustedCheckpoint(100))
+	assert.False(IsBeforeTrustedCheckpoint(101))
+}
+
+func TestValidatorSetHash(t *testing.T) {
+	assert := assert.New(t)
+
+	vs1 := NewValidatorSet()
+	vs1.AddValidator(NewValidator("0x111", big.NewInt(100)))
+	vs1.AddValidator(NewValidator("0x222", big.NewInt(200)))
+
+	vs2 := NewValidatorSet()
+	vs2.AddValidator(NewValidator("0x222", big.NewInt(200)))
+	vs2.AddValidator(NewValidator("0x111", big.NewInt(100)))
+	assert.Equal(vs1.Hash(), vs2.Hash())
+
+	vs3 := NewValidatorSet()
+	vs3.AddValidator(NewValidator("0x111", big.NewInt(100)))
+	vs3.AddValidator(NewValidator("0x222", big.NewInt(201)))
+	assert.NotEqual(vs1.Hash(), vs3.Hash())
+}
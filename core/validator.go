@@ -11,6 +11,8 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/rlp"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "core"})
@@ -56,6 +58,7 @@ func (v Validator) String() string {
 // ValidatorSet represents a set of validators.
 type ValidatorSet struct {
 	validators []Validator
+	signers    map[common.Address]common.Address // the signing addresses of the validators that rotated their keys
 }
 
 // NewValidatorSet returns a new instance of ValidatorSet.
@@ -76,6 +79,9 @@ func (s *ValidatorSet) Copy() *ValidatorSet {
 	for _, v := range s.Validators() {
 		ret.AddValidator(v)
 	}
+	for id, signer := range s.signers {
+		ret.SetSigningAddress(id, signer)
+	}
 	return ret
 }
 
@@ -84,7 +90,8 @@ func (s *ValidatorSet) Size() int {
 	return len(s.validators)
 }
 
-// Equals checks whether the validator set is the same as another validator set
+// Equals checks whether the validator set is the same as another validator set. The signing
+// addresses are not compared, since a key rotation does not change the validators.
 func (s *ValidatorSet) Equals(t *ValidatorSet) bool {
 	numVals := len(s.validators)
 	if numVals != len(t.validators) {
@@ -103,6 +110,30 @@ func (s *ValidatorSet) String() string {
 	return fmt.Sprintf("{Validators: %v}", s.validators)
 }
 
+// validatorSigner is the signing address of a validator that rotated its key
+type validatorSigner struct {
+	ID     common.Address
+	Signer common.Address
+}
+
+// Hash returns the hash of the validator set. The signing addresses of the validators that rotated
+// their keys are part of the hash, while the hash of a set without any rotation stays the same as
+// before the key rotation is enabled.
+func (s *ValidatorSet) Hash() common.Hash {
+	signers := []validatorSigner{}
+	for _, v := range s.validators {
+		if signer := s.SigningAddress(v.ID()); signer != v.ID() {
+			signers = append(signers, validatorSigner{ID: v.ID(), Signer: signer})
+		}
+	}
+	if len(signers) == 0 {
+		raw, _ := rlp.EncodeToBytes(s.validators)
+		return crypto.Keccak256Hash(raw)
+	}
+	raw, _ := rlp.EncodeToBytes([]interface{}{s.validators, signers})
+	return crypto.Keccak256Hash(raw)
+}
+
 // ByID implements sort.Interface for ValidatorSet based on ID.
 type ByID []Validator
 
@@ -110,16 +141,40 @@ func (b ByID) Len() int           { return len(b) }
 func (b ByID) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
 func (b ByID) Less(i, j int) bool { return bytes.Compare(b[i].ID().Bytes(), b[j].ID().Bytes()) < 0 }
 
-// GetValidator returns a validator if a matching ID is found.
+// GetValidator returns a validator whose signing address matches the given ID. The ID of the votes
+// and the proposals is the signing address, which differs from the validator ID once the validator
+// rotated its key.
 func (s *ValidatorSet) GetValidator(id common.Address) (Validator, error) {
 	for _, v := range s.validators {
-		if v.ID() == id {
+		if s.SigningAddress(v.ID()) == id {
 			return v, nil
 		}
 	}
 	return Validator{}, ErrValidatorNotFound
 }
 
+// SetSigningAddress sets the address of the key the given validator signs the votes and the
+// proposals with.
+func (s *ValidatorSet) SetSigningAddress(id common.Address, signer common.Address) {
+	if id == signer {
+		delete(s.signers, id)
+		return
+	}
+	if s.signers == nil {
+		s.signers = make(map[common.Address]common.Address)
+	}
+	s.signers[id] = signer
+}
+
+// SigningAddress returns the signing address of the given validator, which is the validator ID
+// unless the validator rotated its key.
+func (s *ValidatorSet) SigningAddress(id common.Address) common.Address {
+	if signer, ok := s.signers[id]; ok {
+		return signer
+	}
+	return id
+}
+
 // AddValidator adds a validator to the validator set.
 func (s *ValidatorSet) AddValidator(validator Validator) {
 	s.validators = append(s.validators, validator)
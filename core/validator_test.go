@@ -86,6 +86,48 @@ func TestValidatorSet(t *testing.T) {
 	assert.True(vsc.HasMajority(voteSet4)) // full set
 }
 
+func TestValidatorSetSigningAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	va1Addr := common.HexToAddress("0x111")
+	va2Addr := common.HexToAddress("0x222")
+	signer := common.HexToAddress("0x999")
+
+	vs := NewValidatorSet()
+	vs.AddValidator(NewValidator("0x111", big.NewInt(100)))
+	vs.AddValidator(NewValidator("0x222", big.NewInt(100)))
+	hash := vs.Hash()
+
+	assert.Equal(va1Addr, vs.SigningAddress(va1Addr))
+	vs.SetSigningAddress(va1Addr, signer)
+	assert.Equal(signer, vs.SigningAddress(va1Addr))
+	assert.Equal(va2Addr, vs.SigningAddress(va2Addr))
+	assert.NotEqual(hash, vs.Hash())
+
+	// Votes signed by the rotated key are counted for the validator, while the old key is rejected
+	va, err := vs.GetValidator(signer)
+	assert.Nil(err)
+	assert.Equal(va1Addr, va.ID())
+	_, err = vs.GetValidator(va1Addr)
+	assert.Equal(ErrValidatorNotFound, err)
+
+	vsc := vs.Copy()
+	assert.Equal(signer, vsc.SigningAddress(va1Addr))
+	assert.Equal(vs.Hash(), vsc.Hash())
+	voteSet := NewVoteSet()
+	voteSet.AddVote(Vote{ID: signer})
+	voteSet.AddVote(Vote{ID: va2Addr})
+	assert.True(vsc.HasMajority(voteSet))
+
+	// Rotating back to the holder key
+	vs.SetSigningAddress(va1Addr, va1Addr)
+	_, err = vs.GetValidator(va1Addr)
+	assert.Nil(err)
+	assert.Equal(hash, vs.Hash())
+	assert.NotEqual(vs.Hash(), vsc.Hash())
+	assert.True(vs.Equals(vsc))
+}
+
 func TestValidatorCandidatePool(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/crypto/bls"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// validatorVotesSignBytes returns the bytes signed by the BLS keys of the validators voting
+// on the given block. The prefix separates them from the guardian and elite edge node votes,
+// which might be signed by the same BLS key.
+func validatorVotesSignBytes(block common.Hash) common.Bytes {
+	b, _ := rlp.EncodeToBytes([]interface{}{"ValidatorVote", block})
+	return b
+}
+
+//
+// AggregatedValidatorVotes represents the votes of the validators on a block, with the BLS
+// signatures of the voters aggregated into one. It replaces the individual votes of the commit
+// certificate after the validator vote aggregation fork.
+//
+type AggregatedValidatorVotes struct {
+	Block      common.Hash    // Hash of the block.
+	Multiplies []uint32       // Multiplies of each validator, in the order of the validator set.
+	Signature  *bls.Signature // Aggregated signiature.
+}
+
+// NewAggregatedValidatorVotes aggregates the BLS signatures of the votes on the given block.
+// Votes from non-validators, votes without a valid BLS signature, and votes from validators
+// without a registered BLS key are skipped. The pubkeys are in the order of the validator set.
+func NewAggregatedValidatorVotes(block common.Hash, validators *ValidatorSet, pubkeys []*bls.PublicKey, votes *VoteSet) *AggregatedValidatorVotes {
+	a := &AggregatedValidatorVotes{
+		Block:      block,
+		Multiplies: make([]uint32, validators.Size()),
+		Signature:  bls.NewAggregateSignature(),
+	}
+	if len(pubkeys) != validators.Size() || votes == nil {
+		return a
+	}
+
+	signBytes := validatorVotesSignBytes(block)
+	for _, vote := range votes.Votes() {
+		if vote.Block != block || vote.BlsSignature.IsEmpty() {
+			continue
+		}
+		for idx, validator := range validators.Validators() {
+			if validators.SigningAddress(validator.ID()) != vote.ID {
+				continue
+			}
+			if a.Multiplies[idx] > 0 || pubkeys[idx].IsEmpty() || !vote.BlsSignature.Verify(signBytes, pubkeys[idx]) {
+				break
+			}
+			a.Multiplies[idx] = 1
+			a.Signature.Aggregate(vote.BlsSignature)
+			break
+		}
+	}
+	return a
+}
+
+func (a *AggregatedValidatorVotes) String() string {
+	if a == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("AggregatedValidatorVotes{Block: %s, Multiplies: %v}", a.Block.Hex(), a.Multiplies)
+}
+
+// Abs returns the number of validators in the vote.
+func (a *AggregatedValidatorVotes) Abs() int {
+	ret := 0
+	for i := 0; i < len(a.Multiplies); i++ {
+		if a.Multiplies[i] != 0 {
+			ret += 1
+		}
+	}
+	return ret
+}
+
+// HasMajority checks whether the voted validators have more than 2/3 of the total stake.
+func (a *AggregatedValidatorVotes) HasMajority(validators *ValidatorSet) bool {
+	if len(a.Multiplies) != validators.Size() {
+		return false
+	}
+	votedStake := new(big.Int).SetUint64(0)
+	for idx, validator := range validators.Validators() {
+		if a.Multiplies[idx] != 0 {
+			votedStake = new(big.Int).Add(votedStake, validator.Stake)
+		}
+	}
+
+	three := new(big.Int).SetUint64(3)
+	two := new(big.Int).SetUint64(2)
+	lhs := new(big.Int)
+	rhs := new(big.Int)
+
+	//return votedStake*3 > validators.TotalStake()*2
+	return lhs.Mul(votedStake, three).Cmp(rhs.Mul(validators.TotalStake(), two)) > 0
+}
+
+// Validate checks the aggregated votes form a commit certificate of the block. The pubkeys are
+// the registered BLS keys of the validators, in the order of the validator set.
+func (a *AggregatedValidatorVotes) Validate(block common.Hash, validators *ValidatorSet, pubkeys []*bls.PublicKey) result.Result {
+	if a.Block != block {
+		return result.Error("block mismatch: expected: %s, vote.Block: %s", block.Hex(), a.Block.Hex())
+	}
+	if len(a.Multiplies) != validators.Size() {
+		return result.Error("multiplies size %d is not equal to validator set size %d", len(a.Multiplies), validators.Size())
+	}
+	if len(pubkeys) != validators.Size() {
+		return result.Error("pubkeys size %d is not equal to validator set size %d", len(pubkeys), validators.Size())
+	}
+	for idx, multiply := range a.Multiplies {
+		if multiply > 1 {
+			return result.Error("validator %v voted more than once", validators.Validators()[idx].ID().Hex())
+		}
+		if multiply == 1 && pubkeys[idx].IsEmpty() {
+			return result.Error("validator %v has no BLS pubkey registered", validators.Validators()[idx].ID().Hex())
+		}
+	}
+	if a.Signature.IsEmpty() {
+		return result.Error("signature cannot be empty")
+	}
+	if !a.HasMajority(validators) {
+		return result.Error("votes do not reach majority")
+	}
+	aggPubkey := bls.AggregatePublicKeysVec(pubkeys, a.Multiplies)
+	if !a.Signature.Verify(validatorVotesSignBytes(a.Block), aggPubkey) {
+		return result.Error("signature verification failed")
+	}
+	return result.OK
+}
+
+// Copy clones the aggregated votes.
+func (a *AggregatedValidatorVotes) Copy() *AggregatedValidatorVotes {
+	clone := &AggregatedValidatorVotes{
+		Block: a.Block,
+	}
+	if a.Multiplies != nil {
+		clone.Multiplies = make([]uint32, len(a.Multiplies))
+		copy(clone.Multiplies, a.Multiplies)
+	}
+	if a.Signature != nil {
+		clone.Signature = a.Signature.Copy()
+	}
+	return clone
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
+	"github.com/thetatoken/theta/rlp"
+)
+
+func createTestValidatorVotes(block common.Hash, size int) (*ValidatorSet, []*bls.PublicKey, []Vote) {
+	validators := NewValidatorSet()
+	privKeys := make(map[common.Address]*crypto.PrivateKey)
+	blsKeys := make(map[common.Address]*bls.SecretKey)
+	for i := 0; i < size; i++ {
+		privKey, pub, _ := crypto.GenerateKeyPair()
+		blsKey, _ := bls.RandKey()
+		validators.AddValidator(Validator{Address: pub.Address(), Stake: big.NewInt(100)})
+		privKeys[pub.Address()] = privKey
+		blsKeys[pub.Address()] = blsKey
+	}
+
+	// Pubkeys and votes are in the order of the validator set.
+	pubkeys := []*bls.PublicKey{}
+	votes := []Vote{}
+	for _, validator := range validators.Validators() {
+		pubkeys = append(pubkeys, blsKeys[validator.ID()].PublicKey())
+		vote := Vote{Block: block, Height: 1, Epoch: 1, ID: validator.ID()}
+		vote.Sign(privKeys[validator.ID()])
+		vote.SignBls(blsKeys[validator.ID()])
+		votes = append(votes, vote)
+	}
+	return validators, pubkeys, votes
+}
+
+func TestAggregatedValidatorVotes(t *testing.T) {
+	assert := assert.New(t)
+
+	block := CreateTestBlock("", "").Hash()
+	validators, pubkeys, votes := createTestValidatorVotes(block, 4)
+
+	// 3 out of 4 validators reach majority.
+	voteSet := NewVoteSet()
+	for _, vote := range votes[:3] {
+		voteSet.AddVote(vote)
+	}
+	vvotes := NewAggregatedValidatorVotes(block, validators, pubkeys, voteSet)
+	assert.Equal(3, vvotes.Abs())
+	assert.True(vvotes.HasMajority(validators))
+	assert.True(vvotes.Validate(block, validators, pubkeys).IsOK())
+
+	// Survives RLP round trip.
+	raw, err := rlp.EncodeToBytes(vvotes)
+	assert.Nil(err)
+	decoded := &AggregatedValidatorVotes{}
+	assert.Nil(rlp.DecodeBytes(raw, decoded))
+	assert.True(decoded.Validate(block, validators, pubkeys).IsOK())
+
+	// Wrong block.
+	assert.True(vvotes.Validate(common.HexToHash("0x11"), validators, pubkeys).IsError())
+
+	// Claiming a validator that did not vote.
+	forged := vvotes.Copy()
+	forged.Multiplies[3] = 1
+	assert.True(forged.Validate(block, validators, pubkeys).IsError())
+
+	// 2 out of 4 validators do not reach majority.
+	voteSet = NewVoteSet()
+	for _, vote := range votes[:2] {
+		voteSet.AddVote(vote)
+	}
+	vvotes = NewAggregatedValidatorVotes(block, validators, pubkeys, voteSet)
+	assert.False(vvotes.HasMajority(validators))
+	assert.True(vvotes.Validate(block, validators, pubkeys).IsError())
+}
+
+func TestAggregatedValidatorVotesSkipInvalidSignatures(t *testing.T) {
+	assert := assert.New(t)
+
+	block := CreateTestBlock("", "").Hash()
+	validators, pubkeys, votes := createTestValidatorVotes(block, 4)
+
+	voteSet := NewVoteSet()
+	for _, vote := range votes {
+		voteSet.AddVote(vote)
+	}
+
+	// Validator 0 has not registered a BLS key, and validator 1 signed with a different key.
+	pubkeys[0] = nil
+	otherKey, _ := bls.RandKey()
+	pubkeys[1] = otherKey.PublicKey()
+
+	vvotes := NewAggregatedValidatorVotes(block, validators, pubkeys, voteSet)
+	assert.Equal([]uint32{0, 0, 1, 1}, vvotes.Multiplies)
+	assert.False(vvotes.HasMajority(validators))
+
+	forged := vvotes.Copy()
+	forged.Multiplies[0] = 1
+	assert.True(forged.Validate(block, validators, pubkeys).IsError())
+}
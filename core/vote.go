@@ -11,6 +11,7 @@ import (
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
 	"github.com/thetatoken/theta/rlp"
 )
 
@@ -71,11 +72,91 @@ func (cc CommitCertificate) IsValid(validators *ValidatorSet) bool {
 
 // Vote represents a vote on a block by a validaor.
 type Vote struct {
-	Block     common.Hash    // Hash of the tip as seen by the voter.
-	Height    uint64         // Height of the tip
-	Epoch     uint64         // Voter's current epoch. It doesn't need to equal the epoch in the block above.
-	ID        common.Address // Voter's address.
-	Signature *crypto.Signature
+	Block        common.Hash    // Hash of the tip as seen by the voter.
+	Height       uint64         // Height of the tip
+	Epoch        uint64         // Voter's current epoch. It doesn't need to equal the epoch in the block above.
+	ID           common.Address // Voter's address.
+	Signature    *crypto.Signature
+	BlsSignature *bls.Signature // Optional, added in the validator vote aggregation fork.
+
+	hash common.Hash // Cache of calculated hash.
+}
+
+var _ rlp.Encoder = Vote{}
+
+// EncodeRLP implements RLP Encoder interface. The BLS signature is only encoded when present
+// so that votes without it keep their original encoding. The fields are appended directly
+// rather than through reflection since votes are encoded for every hash and signature check.
+func (v Vote) EncodeRLP(w io.Writer) error {
+	var err error
+	b := make([]byte, 0, 192)
+	b = rlp.AppendString(b, v.Block[:])
+	b = rlp.AppendUint64(b, v.Height)
+	b = rlp.AppendUint64(b, v.Epoch)
+	b = rlp.AppendString(b, v.ID[:])
+	if v.Signature == nil {
+		b = append(b, rlp.EmptyString...)
+	} else {
+		b = rlp.AppendString(b, v.Signature.ToBytes())
+	}
+	if v.BlsSignature != nil {
+		b, err = rlp.AppendValue(b, v.BlsSignature)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(rlp.AppendList(nil, b))
+	return err
+}
+
+var _ rlp.Decoder = (*Vote)(nil)
+
+// DecodeRLP implements RLP Decoder interface.
+func (v *Vote) DecodeRLP(stream *rlp.Stream) error {
+	v.hash = common.Hash{}
+
+	_, err := stream.List()
+	if err != nil {
+		return err
+	}
+
+	err = stream.ReadBytes(v.Block[:])
+	if err != nil {
+		return err
+	}
+
+	v.Height, err = stream.Uint()
+	if err != nil {
+		return err
+	}
+
+	v.Epoch, err = stream.Uint()
+	if err != nil {
+		return err
+	}
+
+	err = stream.ReadBytes(v.ID[:])
+	if err != nil {
+		return err
+	}
+
+	err = stream.Decode(&v.Signature)
+	if err != nil {
+		return err
+	}
+
+	v.BlsSignature = nil
+	_, _, err = stream.Kind()
+	if err == nil {
+		err = stream.Decode(&v.BlsSignature)
+		if err != nil {
+			return err
+		}
+	} else if err != rlp.EOL {
+		return err
+	}
+
+	return stream.ListEnd()
 }
 
 func (v Vote) String() string {
@@ -103,9 +184,22 @@ func (v *Vote) Sign(priv *crypto.PrivateKey) {
 	v.SetSignature(sig)
 }
 
+// BlsSignBytes returns raw bytes to be signed by the BLS key of the voter. Unlike SignBytes, it
+// only covers the block hash so that the BLS signatures of all the votes on a block can be aggregated.
+func (v Vote) BlsSignBytes() common.Bytes {
+	return validatorVotesSignBytes(v.Block)
+}
+
+// SignBls adds the BLS signature of the vote using given BLS key.
+func (v *Vote) SignBls(key *bls.SecretKey) {
+	v.BlsSignature = key.Sign(v.BlsSignBytes())
+	v.hash = common.Hash{}
+}
+
 // SetSignature sets given signature in vote.
 func (v *Vote) SetSignature(sig *crypto.Signature) {
 	v.Signature = sig
+	v.hash = common.Hash{}
 }
 
 // Validate checks the vote is legitimate.
@@ -125,8 +219,16 @@ func (v Vote) Validate() result.Result {
 	return result.OK
 }
 
-// Hash calculates vote's hash.
-func (v Vote) Hash() common.Hash {
+// Hash returns vote's hash. The hash is cached, so the fields should not be modified after the
+// first call other than through Sign, SignBls and SetSignature, which clear the cache.
+func (v *Vote) Hash() common.Hash {
+	if v.hash.IsEmpty() {
+		v.hash = v.calculateHash()
+	}
+	return v.hash
+}
+
+func (v Vote) calculateHash() common.Hash {
 	raw, _ := rlp.EncodeToBytes(v)
 	return crypto.Keccak256Hash(raw)
 }
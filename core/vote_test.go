@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
 	"github.com/thetatoken/theta/rlp"
 )
 
@@ -259,3 +260,79 @@ func TestCommitCertificate(t *testing.T) {
 	cc = CommitCertificate{Votes: invalidVoteSet, BlockHash: blockHash}
 	assert.False(cc.IsValid(vs))
 }
+
+func TestVoteEncodingWithBlsSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	blsKey, _ := bls.RandKey()
+
+	v1 := Vote{
+		Block:  CreateTestBlock("", "").Hash(),
+		Height: 10,
+		ID:     privKey.PublicKey().Address(),
+		Epoch:  1,
+	}
+	v1.Sign(privKey)
+
+	// Votes without BLS signature keep the original encoding.
+	legacy := struct {
+		Block     common.Hash
+		Height    uint64
+		Epoch     uint64
+		ID        common.Address
+		Signature *crypto.Signature
+	}{v1.Block, v1.Height, v1.Epoch, v1.ID, v1.Signature}
+	b1, err := rlp.EncodeToBytes(v1)
+	assert.Nil(err)
+	b2, err := rlp.EncodeToBytes(legacy)
+	assert.Nil(err)
+	assert.Equal(b2, b1)
+
+	v2 := Vote{}
+	assert.Nil(rlp.DecodeBytes(b1, &v2))
+	assert.Nil(v2.BlsSignature)
+	assert.Equal(v1.Hash(), v2.Hash())
+
+	v1.SignBls(blsKey)
+	b3, err := rlp.EncodeToBytes(v1)
+	assert.Nil(err)
+	v3 := Vote{}
+	assert.Nil(rlp.DecodeBytes(b3, &v3))
+	assert.Equal(v1.Height, v3.Height)
+	assert.NotNil(v3.BlsSignature)
+	assert.True(v3.BlsSignature.Verify(v3.BlsSignBytes(), blsKey.PublicKey()))
+	assert.True(v3.Validate().IsOK())
+	assert.Equal(v1.Hash(), v3.Hash())
+}
+
+func BenchmarkVoteEncodeRLP(b *testing.B) {
+	privKey, _, _ := crypto.GenerateKeyPair()
+	vote := Vote{
+		Block:  CreateTestBlock("", "").Hash(),
+		Height: 10,
+		ID:     privKey.PublicKey().Address(),
+		Epoch:  1,
+	}
+	vote.Sign(privKey)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(vote)
+	}
+}
+
+func BenchmarkVoteEncodeRLPReflect(b *testing.B) {
+	privKey, _, _ := crypto.GenerateKeyPair()
+	vote := Vote{
+		Block:  CreateTestBlock("", "").Hash(),
+		Height: 10,
+		ID:     privKey.PublicKey().Address(),
+		Epoch:  1,
+	}
+	vote.Sign(privKey)
+	fields := []interface{}{vote.Block, vote.Height, vote.Epoch, vote.ID, vote.Signature}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(fields)
+	}
+}
@@ -2,10 +2,11 @@ package dispatcher
 
 import (
 	"context"
+	"errors"
 	"reflect"
+	"strings"
 	"sync"
-
-	"github.com/spf13/viper"
+	"time"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/p2p"
@@ -48,13 +49,13 @@ func (dp *Dispatcher) Start(ctx context.Context) error {
 	dp.cancel = cancel
 	var err error
 
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		err = dp.p2pnet.Start(c)
 		if err != nil {
 			return err
 		}
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		err = dp.p2plnet.Start(c)
 	}
 	return err
@@ -67,10 +68,10 @@ func (dp *Dispatcher) Stop() {
 
 // Wait suspends the caller goroutine
 func (dp *Dispatcher) Wait() {
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		dp.p2pnet.Wait()
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		dp.p2plnet.Wait()
 	}
 	dp.wg.Wait()
@@ -124,12 +125,22 @@ func (dp *Dispatcher) SendData(peerIDs []string, datarsp DataResponse) {
 	}
 }
 
+// Send delivers the given content to the specified peers through the channel. The content
+// is encoded by the message handler registered for the channel
+func (dp *Dispatcher) Send(peerIDs []string, channelID common.ChannelIDEnum, content interface{}) {
+	if len(peerIDs) == 0 {
+		dp.broadcastToNeighbors(channelID, content, true /* only blockchain nodes */)
+	} else {
+		dp.send(peerIDs, channelID, content)
+	}
+}
+
 // ID returns the ID of the node
 func (dp Dispatcher) ID() string {
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		return dp.p2pnet.ID()
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		return dp.p2plnet.ID()
 	}
 	return ""
@@ -138,10 +149,10 @@ func (dp Dispatcher) ID() string {
 // TODO: for 1.3.0 upgrade only, delete it after the upgrade completed
 // ID returns the ID of the node
 func (dp Dispatcher) LibP2PID() string {
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		return dp.p2plnet.ID()
 	}
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		return dp.p2pnet.ID()
 	}
 	return ""
@@ -149,10 +160,10 @@ func (dp Dispatcher) LibP2PID() string {
 
 // Peers returns the IDs of all peers
 func (dp *Dispatcher) Peers(skipEdgeNode bool) []string {
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		return dp.p2pnet.Peers(skipEdgeNode)
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		return dp.p2plnet.Peers(skipEdgeNode)
 	}
 	return []string{}
@@ -160,10 +171,10 @@ func (dp *Dispatcher) Peers(skipEdgeNode bool) []string {
 
 // Peers returns the IDs of all peers
 func (dp *Dispatcher) PeerURLs(skipEdgeNode bool) []string {
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		return dp.p2pnet.PeerURLs(skipEdgeNode)
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		return dp.p2plnet.PeerURLs(skipEdgeNode)
 	}
 	return []string{}
@@ -171,15 +182,76 @@ func (dp *Dispatcher) PeerURLs(skipEdgeNode bool) []string {
 
 // PeerExists indicates if the given peerID is a neighboring peer
 func (dp *Dispatcher) PeerExists(peerID string) bool {
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		return dp.p2pnet.PeerExists(peerID)
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		return dp.p2plnet.PeerExists(peerID)
 	}
 	return false
 }
 
+// PeerInfos returns the runtime information of all peers
+func (dp *Dispatcher) PeerInfos(skipEdgeNode bool) []p2ptypes.PeerInfo {
+	peerInfos := []p2ptypes.PeerInfo{}
+	if !isNilNetwork(dp.p2pnet) {
+		peerInfos = append(peerInfos, dp.p2pnet.PeerInfos(skipEdgeNode)...)
+	}
+	if !isNilNetwork(dp.p2plnet) {
+		peerInfos = append(peerInfos, dp.p2plnet.PeerInfos(skipEdgeNode)...)
+	}
+	return peerInfos
+}
+
+// ConnectPeer connects to the given peer. Multiaddresses (e.g. /ip4/1.2.3.4/tcp/12000/ipfs/<id>)
+// are handled by the libp2p network, and host:port addresses by the legacy network
+func (dp *Dispatcher) ConnectPeer(peerAddr string) error {
+	if strings.HasPrefix(peerAddr, "/") {
+		if isNilNetwork(dp.p2plnet) {
+			return errors.New("libp2p network is not enabled")
+		}
+		return dp.p2plnet.ConnectPeer(peerAddr)
+	}
+	if isNilNetwork(dp.p2pnet) {
+		return errors.New("p2p network is not enabled")
+	}
+	return dp.p2pnet.ConnectPeer(peerAddr)
+}
+
+// DisconnectPeer disconnects from the given peer
+func (dp *Dispatcher) DisconnectPeer(peerID string) bool {
+	disconnected := false
+	if !isNilNetwork(dp.p2pnet) {
+		disconnected = dp.p2pnet.DisconnectPeer(peerID) || disconnected
+	}
+	if !isNilNetwork(dp.p2plnet) {
+		disconnected = dp.p2plnet.DisconnectPeer(peerID) || disconnected
+	}
+	return disconnected
+}
+
+// BanPeer disconnects from the given peer and rejects its connections for the specified duration
+func (dp *Dispatcher) BanPeer(peerID string, duration time.Duration) bool {
+	banned := false
+	if !isNilNetwork(dp.p2pnet) {
+		banned = dp.p2pnet.BanPeer(peerID, duration) || banned
+	}
+	if !isNilNetwork(dp.p2plnet) {
+		banned = dp.p2plnet.BanPeer(peerID, duration) || banned
+	}
+	return banned
+}
+
+// ReportMisbehavior penalizes the given peer for the misbehavior
+func (dp *Dispatcher) ReportMisbehavior(peerID string, misbehavior p2ptypes.Misbehavior) {
+	if !isNilNetwork(dp.p2pnet) {
+		dp.p2pnet.ReportMisbehavior(peerID, misbehavior)
+	}
+	if !isNilNetwork(dp.p2plnet) {
+		dp.p2plnet.ReportMisbehavior(peerID, misbehavior)
+	}
+}
+
 // send delivers message directly to a list of peers.
 func (dp *Dispatcher) send(peerIDs []string, channelID common.ChannelIDEnum, content interface{}) {
 	messageOld := p2ptypes.Message{
@@ -193,13 +265,13 @@ func (dp *Dispatcher) send(peerIDs []string, channelID common.ChannelIDEnum, con
 
 	for _, peerID := range peerIDs {
 		go func(peerID string) {
-			if !reflect.ValueOf(dp.p2pnet).IsNil() {
+			if !isNilNetwork(dp.p2pnet) {
 				ok := dp.p2pnet.Send(peerID, messageOld)
 				if !ok {
 					logger.Debugf("Failed to send message to [%v]: %v, %v", peerID, channelID, content)
 				}
 			}
-			if !reflect.ValueOf(dp.p2plnet).IsNil() {
+			if !isNilNetwork(dp.p2plnet) {
 				dp.p2plnet.Send(peerID, message)
 			}
 		}(peerID)
@@ -217,10 +289,10 @@ func (dp *Dispatcher) broadcastToAll(channelID common.ChannelIDEnum, content int
 		ChannelID: channelID,
 		Content:   content,
 	}
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	if !isNilNetwork(dp.p2pnet) {
 		dp.p2pnet.Broadcast(messageOld, skipEdgeNode)
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		dp.p2plnet.Broadcast(message, skipEdgeNode)
 	}
 }
@@ -235,12 +307,18 @@ func (dp *Dispatcher) broadcastToNeighbors(channelID common.ChannelIDEnum, conte
 		ChannelID: channelID,
 		Content:   content,
 	}
-	maxNumPeersToBroadcast := viper.GetInt(common.CfgP2PMaxNumPeersToBroadcast)
-	if !reflect.ValueOf(dp.p2pnet).IsNil() {
+	maxNumPeersToBroadcast := common.GetReloadableInt(common.CfgP2PMaxNumPeersToBroadcast)
+	if !isNilNetwork(dp.p2pnet) {
 		//dp.p2pnet.Broadcast(messageOld)
 		dp.p2pnet.BroadcastToNeighbors(messageOld, maxNumPeersToBroadcast, skipEdgeNode)
 	}
-	if !reflect.ValueOf(dp.p2plnet).IsNil() {
+	if !isNilNetwork(dp.p2plnet) {
 		dp.p2plnet.BroadcastToNeighbors(message, maxNumPeersToBroadcast, skipEdgeNode)
 	}
 }
+
+// isNilNetwork returns true if the network is not set, either as a nil interface value
+// or as a nil pointer wrapped in the interface.
+func isNilNetwork(network interface{}) bool {
+	return network == nil || reflect.ValueOf(network).IsNil()
+}
@@ -20,6 +20,7 @@ require (
 	github.com/jackpal/go-nat-pmp v1.0.1
 	github.com/karalabe/hid v0.0.0-20180420081245-2b4488a37358
 	github.com/koron/go-ssdp v0.0.0-20180514024734-4a0ed625a78b
+	github.com/lib/pq v1.2.0
 	github.com/libp2p/go-libp2p v0.3.0
 	github.com/libp2p/go-libp2p-connmgr v0.1.1
 	github.com/libp2p/go-libp2p-core v0.2.0
@@ -46,6 +47,7 @@ require (
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
 	github.com/sirupsen/logrus v1.4.2
 	github.com/smira/go-statsd v1.3.1
+	github.com/spf13/cast v1.3.0
 	github.com/spf13/cobra v0.0.5
 	github.com/spf13/viper v1.5.0
 	github.com/stretchr/testify v1.4.0
@@ -63,6 +65,7 @@ require (
 	golang.org/x/net v0.0.0-20191021144547-ec77196f6094
 	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae
 	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898 // indirect
+	google.golang.org/grpc v1.21.0
 	gopkg.in/karalabe/cookiejar.v2 v2.0.0-20150724131613-8dcd6a7f4951
 	gopkg.in/mgo.v2 v2.0.0-20180705113604-9856a29383ce
 )
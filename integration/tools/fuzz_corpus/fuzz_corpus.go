@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// fuzz_corpus writes the seed corpora of the fuzzing harnesses, one directory per harness in the
+// go-fuzz workdir layout, i.e. <workdir>/<harness>/corpus/<seed>
+
+const chainID = "testchain"
+
+func main() {
+	workdir := flag.String("workdir", "fuzz", "Directory of the go-fuzz workdirs")
+	flag.Parse()
+
+	corpora := map[string]map[string][]byte{
+		"tx":              txSeeds(),
+		"block":           blockSeeds(),
+		"vote":            voteSeeds(),
+		"snapshot_record": snapshotRecordSeeds(),
+	}
+	for harness, seeds := range corpora {
+		dir := path.Join(*workdir, harness, "corpus")
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			handleError(err)
+		}
+		for name, seed := range seeds {
+			if err := ioutil.WriteFile(path.Join(dir, name), seed, 0600); err != nil {
+				handleError(err)
+			}
+		}
+		fmt.Printf("Wrote %v seeds to %v\n", len(seeds), dir)
+	}
+}
+
+func handleError(err error) {
+	fmt.Printf("Error: %v\n", err)
+	os.Exit(1)
+}
+
+func mustEncode(val interface{}) []byte {
+	raw, err := rlp.EncodeToBytes(val)
+	if err != nil {
+		handleError(err)
+	}
+	return raw
+}
+
+func txSeeds() map[string][]byte {
+	alice := types.MakeAcc("alice")
+	bob := types.MakeAcc("bob")
+
+	sendTx := types.MakeSendTx(1, bob, alice)
+	types.SignSendTx(chainID, sendTx, alice)
+
+	coinbaseTx := &types.CoinbaseTx{
+		Proposer:    types.NewTxInput(alice.Account.Address, types.NewCoins(0, 0), 1),
+		Outputs:     types.Accs2TxOutputs(bob),
+		BlockHeight: 100,
+	}
+	coinbaseTx.Proposer.Signature = alice.Sign(coinbaseTx.SignBytes(chainID))
+
+	smartContractTx := &types.SmartContractTx{
+		From:     types.NewTxInput(alice.Account.Address, types.NewCoins(0, 0), 1),
+		To:       types.TxOutput{Address: bob.Account.Address},
+		GasLimit: 100000,
+		GasPrice: big.NewInt(4000000000000),
+		Data:     common.Hex2Bytes("a9059cbb"),
+	}
+	smartContractTx.From.Signature = alice.Sign(smartContractTx.SignBytes(chainID))
+
+	depositStakeTx := &types.DepositStakeTxV2{
+		Fee:     types.NewCoins(0, 1000000000000),
+		Source:  types.NewTxInput(alice.Account.Address, types.NewCoins(1000, 0), 1),
+		Holder:  types.TxOutput{Address: bob.Account.Address},
+		Purpose: core.StakeForValidator,
+	}
+	depositStakeTx.Source.Signature = alice.Sign(depositStakeTx.SignBytes(chainID))
+
+	seeds := make(map[string][]byte)
+	for name, tx := range map[string]types.Tx{
+		"send":           sendTx,
+		"coinbase":       coinbaseTx,
+		"smart_contract": smartContractTx,
+		"deposit_stake":  depositStakeTx,
+	} {
+		raw, err := types.TxToBytes(tx)
+		if err != nil {
+			handleError(err)
+		}
+		seeds[name] = raw
+	}
+	return seeds
+}
+
+func blockSeeds() map[string][]byte {
+	parent := core.CreateTestBlock("a0", "")
+	block := core.CreateTestBlock("a1", "a0")
+	txs := []common.Bytes{}
+	for _, raw := range txSeeds() {
+		txs = append(txs, raw)
+	}
+	withTxs := core.CreateTestBlock("a2", "a1")
+	withTxs.AddTxs(txs)
+
+	return map[string][]byte{
+		"genesis":  mustEncode(parent),
+		"block":    mustEncode(block),
+		"with_txs": mustEncode(withTxs),
+	}
+}
+
+func voteSeeds() map[string][]byte {
+	privKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		handleError(err)
+	}
+	vote := core.Vote{
+		Block:  common.HexToHash("a1"),
+		Height: 1,
+		Epoch:  2,
+		ID:     privKey.PublicKey().Address(),
+	}
+	vote.Sign(privKey)
+	voteSet := core.NewVoteSet()
+	voteSet.AddVote(vote)
+
+	// The first byte selects the vote or the vote set, see core.FuzzVote
+	return map[string][]byte{
+		"vote":     append([]byte{0}, mustEncode(vote)...),
+		"vote_set": append([]byte{1}, mustEncode(voteSet)...),
+	}
+}
+
+func snapshotRecordSeeds() map[string][]byte {
+	header := &core.BlockHeader{
+		ChainID:   chainID,
+		Height:    1,
+		Timestamp: big.NewInt(1),
+	}
+	trio := core.SnapshotBlockTrio{
+		First:  core.SnapshotFirstBlock{Header: header},
+		Second: core.SnapshotSecondBlock{Header: header},
+		Third:  core.SnapshotThirdBlock{Header: header, VoteSet: core.NewVoteSet()},
+	}
+	metadata := &core.SnapshotMetadata{
+		ProofTrios: []core.SnapshotBlockTrio{trio},
+		TailTrio:   trio,
+		Version:    core.SnapshotVersionLatest,
+	}
+
+	// The first byte selects the type of the records, see core.FuzzSnapshotRecord
+	write := func(selector byte, writeRecords func(writer *bufio.Writer) error) []byte {
+		buf := &bytes.Buffer{}
+		buf.WriteByte(selector)
+		if err := writeRecords(bufio.NewWriter(buf)); err != nil {
+			handleError(err)
+		}
+		return buf.Bytes()
+	}
+	return map[string][]byte{
+		"trie_records": write(0, func(writer *bufio.Writer) error {
+			if err := core.WriteRecord(writer, common.Bytes("key"), common.Bytes("value")); err != nil {
+				return err
+			}
+			return core.WriteRecord(writer, common.Bytes{core.SVEnd}, nil)
+		}),
+		"metadata": write(1, func(writer *bufio.Writer) error {
+			return core.WriteMetadata(writer, metadata)
+		}),
+		"last_checkpoint": write(2, func(writer *bufio.Writer) error {
+			return core.WriteLastCheckpoint(writer, &core.LastCheckpoint{
+				CheckpointHeader:    header,
+				IntermediateHeaders: []*core.BlockHeader{header},
+			})
+		}),
+		"header": write(3, func(writer *bufio.Writer) error {
+			return core.WriteSnapshotHeader(writer, &core.SnapshotHeader{
+				Magic:   core.SnapshotHeaderMagic,
+				Version: core.SnapshotVersionLatest,
+			})
+		}),
+	}
+}
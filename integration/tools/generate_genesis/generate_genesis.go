@@ -3,22 +3,27 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
 	"github.com/thetatoken/theta/ledger/state"
 	"github.com/thetatoken/theta/ledger/types"
 	"github.com/thetatoken/theta/rlp"
 	"github.com/thetatoken/theta/store/database/backend"
+	"github.com/thetatoken/theta/store/treestore"
 	"github.com/thetatoken/theta/store/trie"
 )
 
@@ -35,29 +40,83 @@ type StakeDeposit struct {
 	Amount string `json:"amount"`
 }
 
-//
+// BLSStakeDeposit deposits the stake of a guardian or an elite edge node. The holder address and
+// the BLS key are taken from the summary of the node, the same as the holder of thetacli tx deposit.
+type BLSStakeDeposit struct {
+	Source  string `json:"source"`
+	Summary string `json:"summary"`
+	Amount  string `json:"amount"` // ThetaWei for a guardian, TFuelWei for an elite edge node
+}
+
+// VestingAllocation locks part of the initial balance of an address by a vesting schedule
+type VestingAllocation struct {
+	Address     string `json:"address"`
+	ThetaWei    string `json:"theta_wei"`
+	TFuelWei    string `json:"tfuel_wei"`
+	StartHeight uint64 `json:"start_height"`
+	CliffHeight uint64 `json:"cliff_height"`
+	EndHeight   uint64 `json:"end_height"`
+}
+
+// MultisigOwnerSet declares the signers of a multisig account. The address is derived from the
+// threshold and the signers, and is checked against the declared address if specified.
+type MultisigOwnerSet struct {
+	Address   string   `json:"address"`
+	Threshold uint64   `json:"threshold"`
+	Signers   []string `json:"signers"`
+}
+
+// ContractAllocation pre-deploys a contract at the address. The code is the runtime bytecode, and
+// the storage maps the slots to their initial values, both as hex. The balance of the contract, if
+// any, is allocated by the ERC20 balance snapshot like any other address.
+type ContractAllocation struct {
+	Address string            `json:"address"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage"`
+}
+
+// The rules to merge the balances of an address found in more than one balance snapshot
+const (
+	mergeRuleSum      = "sum"      // The balances are added up
+	mergeRuleOverride = "override" // The balance of the later snapshot replaces the earlier ones
+	mergeRuleError    = "error"    // The address is reported as a conflict
+)
+
+// The exit codes
+const (
+	exitInvalidInput = 1 // The input files have problems, which are all reported
+	exitFailed       = 2 // Failed to generate or write the genesis snapshot
+)
+
 // Example:
 // pushd $THETA_HOME/integration/privatenet/node
 // generate_genesis -chainID=privatenet -erc20snapshot=./data/genesis_theta_erc20_snapshot.json -stake_deposit=./data/genesis_stake_deposit.json -genesis=./genesis
+// generate_genesis -chainID=privatenet -erc20snapshot=./data/genesis_theta_erc20_snapshot.json -stake_deposit=./data/genesis_stake_deposit.json -vesting=./data/genesis_vesting.json -multisig=./data/genesis_multisig.json -genesis=./genesis
+// generate_genesis -chainID=privatenet -erc20snapshot=./data/genesis_theta_erc20_snapshot.json -stake_deposit=./data/genesis_stake_deposit.json -guardian_stake_deposit=./data/genesis_guardian_stake_deposit.json -een_stake_deposit=./data/genesis_een_stake_deposit.json -genesis=./genesis
+// generate_genesis -chainID=privatenet -erc20snapshot=./data/genesis_theta_erc20_snapshot.json -stake_deposit=./data/genesis_stake_deposit.json -contracts=./data/genesis_contracts.json -genesis=./genesis
+// generate_genesis -chainID=privatenet -erc20snapshot=./data/genesis_theta_erc20_snapshot.json,./data/genesis_foundation_allocation.json -merge=sum -stake_deposit=./data/genesis_stake_deposit.json -genesis=./genesis
 //
+// All the problems of the input files are reported at once, with the file and line of each, and
+// the generator exits with exitInvalidInput without writing the genesis snapshot.
 func main() {
-	chainID, erc20SnapshotJSONFilePath, stakeDepositFilePath, genesisSnapshotFilePath := parseArguments()
+	args := parseArguments()
 
-	sv, metadata, err := generateGenesisSnapshot(chainID, erc20SnapshotJSONFilePath, stakeDepositFilePath)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to generate genesis snapshot: %v", err))
+	sv, metadata, report := generateGenesisSnapshot(args)
+	if report.hasIssues() {
+		report.print(os.Stderr)
+		os.Exit(exitInvalidInput)
 	}
 
-	err = sanityChecks(sv)
+	err := sanityChecks(sv)
 	if err != nil {
-		panic(fmt.Sprintf("Sanity checks failed: %v", err))
+		exit(exitFailed, "Sanity checks failed: %v", err)
 	} else {
 		logger.Infof("Sanity checks all passed.")
 	}
 
-	err = writeGenesisSnapshot(sv, metadata, genesisSnapshotFilePath)
+	err = writeGenesisSnapshot(sv, metadata, args.genesisSnapshotFilePath)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to write genesis snapshot: %v", err))
+		exit(exitFailed, "Failed to write genesis snapshot: %v", err)
 	}
 
 	genesisBlockHeader := metadata.TailTrio.Second.Header
@@ -70,33 +129,95 @@ func main() {
 	fmt.Println("")
 }
 
-func parseArguments() (chainID, erc20SnapshotJSONFilePath, stakeDepositFilePath, genesisSnapshotFilePath string) {
+func exit(code int, format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(code)
+}
+
+// arguments are the command line arguments, the paths of the optional input files are empty if
+// not specified
+type arguments struct {
+	chainID                           string
+	erc20SnapshotJSONFilePaths        []string
+	mergeRule                         string
+	stakeDepositFilePath              string
+	guardianStakeDepositFilePath      string
+	eliteEdgeNodeStakeDepositFilePath string
+	vestingFilePath                   string
+	multisigFilePath                  string
+	contractsFilePath                 string
+	genesisSnapshotFilePath           string
+}
+
+func parseArguments() *arguments {
 	chainIDPtr := flag.String("chainID", "local_chain", "the ID of the chain")
-	erc20SnapshotJSONFilePathPtr := flag.String("erc20snapshot", "./theta_erc20_snapshot.json", "the json file contain the ERC20 balance snapshot")
+	erc20SnapshotJSONFilePathPtr := flag.String("erc20snapshot", "./theta_erc20_snapshot.json", "the json files contain the balance snapshots, comma separated, merged in the order given")
+	mergeRulePtr := flag.String("merge", mergeRuleError, "how to merge the balances of an address in more than one snapshot: sum, override or error")
 	stakeDepositFilePathPtr := flag.String("stake_deposit", "./stake_deposit.json", "the initial stake deposits")
+	guardianStakeDepositFilePathPtr := flag.String("guardian_stake_deposit", "", "the initial guardian stake deposits (optional)")
+	eliteEdgeNodeStakeDepositFilePathPtr := flag.String("een_stake_deposit", "", "the initial elite edge node stake deposits (optional)")
+	vestingFilePathPtr := flag.String("vesting", "", "the vesting schedules of the initial balances (optional)")
+	multisigFilePathPtr := flag.String("multisig", "", "the owner sets of the multisig accounts (optional)")
+	contractsFilePathPtr := flag.String("contracts", "", "the contracts to pre-deploy (optional)")
 	genesisSnapshotFilePathPtr := flag.String("genesis", "./genesis", "the genesis snapshot")
 	flag.Parse()
 
-	chainID = *chainIDPtr
-	erc20SnapshotJSONFilePath = *erc20SnapshotJSONFilePathPtr
-	stakeDepositFilePath = *stakeDepositFilePathPtr
-	genesisSnapshotFilePath = *genesisSnapshotFilePathPtr
+	switch *mergeRulePtr {
+	case mergeRuleSum, mergeRuleOverride, mergeRuleError:
+	default:
+		exit(exitInvalidInput, "Invalid merge rule: %v, must be one of %v, %v and %v", *mergeRulePtr, mergeRuleSum, mergeRuleOverride, mergeRuleError)
+	}
+
+	erc20SnapshotJSONFilePaths := []string{}
+	for _, path := range strings.Split(*erc20SnapshotJSONFilePathPtr, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			erc20SnapshotJSONFilePaths = append(erc20SnapshotJSONFilePaths, path)
+		}
+	}
 
-	return
+	return &arguments{
+		chainID:                           *chainIDPtr,
+		erc20SnapshotJSONFilePaths:        erc20SnapshotJSONFilePaths,
+		mergeRule:                         *mergeRulePtr,
+		stakeDepositFilePath:              *stakeDepositFilePathPtr,
+		guardianStakeDepositFilePath:      *guardianStakeDepositFilePathPtr,
+		eliteEdgeNodeStakeDepositFilePath: *eliteEdgeNodeStakeDepositFilePathPtr,
+		vestingFilePath:                   *vestingFilePathPtr,
+		multisigFilePath:                  *multisigFilePathPtr,
+		contractsFilePath:                 *contractsFilePathPtr,
+		genesisSnapshotFilePath:           *genesisSnapshotFilePathPtr,
+	}
 }
 
-// generateGenesisSnapshot generates the genesis snapshot.
-func generateGenesisSnapshot(chainID, erc20SnapshotJSONFilePath, stakeDepositFilePath string) (*state.StoreView, *core.SnapshotMetadata, error) {
+// generateGenesisSnapshot generates the genesis snapshot. The problems of the input files are
+// collected in the returned report, in which case the snapshot is incomplete.
+func generateGenesisSnapshot(args *arguments) (*state.StoreView, *core.SnapshotMetadata, *validationReport) {
 	metadata := &core.SnapshotMetadata{}
 	genesisHeight := core.GenesisBlockHeight
+	report := &validationReport{}
 
-	sv := loadInitialBalances(erc20SnapshotJSONFilePath)
-	performInitialStakeDeposit(stakeDepositFilePath, genesisHeight, sv)
+	sv := loadInitialBalances(args.erc20SnapshotJSONFilePaths, args.mergeRule, report)
+	if args.multisigFilePath != "" {
+		setupMultisigAccounts(args.multisigFilePath, sv, report)
+	}
+	if args.contractsFilePath != "" {
+		setupContracts(args.contractsFilePath, sv, report)
+	}
+	if args.vestingFilePath != "" {
+		setupVestingSchedules(args.vestingFilePath, genesisHeight, sv, report)
+	}
+	performInitialStakeDeposit(args.stakeDepositFilePath, genesisHeight, sv, report)
+	if args.guardianStakeDepositFilePath != "" {
+		performInitialGuardianStakeDeposit(args.guardianStakeDepositFilePath, genesisHeight, sv, report)
+	}
+	if args.eliteEdgeNodeStakeDepositFilePath != "" {
+		performInitialEliteEdgeNodeStakeDeposit(args.eliteEdgeNodeStakeDepositFilePath, genesisHeight, sv, report)
+	}
 
 	stateHash := sv.Hash()
 
 	genesisBlock := core.NewBlock()
-	genesisBlock.ChainID = chainID
+	genesisBlock.ChainID = args.chainID
 	genesisBlock.Height = genesisHeight
 	genesisBlock.Epoch = genesisBlock.Height
 	genesisBlock.Parent = common.Hash{}
@@ -109,36 +230,74 @@ func generateGenesisSnapshot(chainID, erc20SnapshotJSONFilePath, stakeDepositFil
 		Third:  core.SnapshotThirdBlock{},
 	}
 
-	return sv, metadata, nil
+	return sv, metadata, report
 }
 
-func loadInitialBalances(erc20SnapshotJSONFilePath string) *state.StoreView {
+// balanceSource is where the balance of an address is found
+type balanceSource struct {
+	file string
+	line int
+}
+
+// loadInitialBalances merges the balance snapshots in the order given. The balances of an address
+// found in more than one snapshot are merged by the merge rule, while an address repeated in the
+// same snapshot is always reported.
+func loadInitialBalances(erc20SnapshotJSONFilePaths []string, mergeRule string, report *validationReport) *state.StoreView {
 	initTFuelToThetaRatio := new(big.Int).SetUint64(5)
 	sv := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
 
-	erc20SnapshotJSONFile, err := os.Open(erc20SnapshotJSONFilePath)
-	if err != nil {
-		panic(fmt.Sprintf("failed to open the ERC20 balance snapshot: %v", err))
-	}
-	defer erc20SnapshotJSONFile.Close()
+	balances := make(map[common.Address]*big.Int)
+	sources := make(map[common.Address]balanceSource)
+	addresses := []common.Address{}
+	for _, file := range erc20SnapshotJSONFilePaths {
+		entries, err := readJSONEntries(file)
+		if err != nil {
+			report.addf(file, 0, "failed to read the ERC20 balance snapshot: %v", err)
+			continue
+		}
 
-	var erc20BalanceMap map[string]string
-	erc20BalanceMapByteValue, err := ioutil.ReadAll(erc20SnapshotJSONFile)
-	if err != nil {
-		panic(fmt.Sprintf("failed to read the ERC20 balance snapshot: %v", err))
-	}
+		for _, entry := range entries {
+			address, ok := parseAddress(entry.key, "balance", file, entry.line, report)
+			if !ok {
+				continue
+			}
+			source, exists := sources[address]
+			if exists && source.file == file {
+				report.addf(file, entry.line, "duplicate balance of %v, first at line %v", address.Hex(), source.line)
+				continue
+			}
 
-	json.Unmarshal(erc20BalanceMapByteValue, &erc20BalanceMap)
-	for key, val := range erc20BalanceMap {
-		if !common.IsHexAddress(key) {
-			panic(fmt.Sprintf("Invalid address: %v", key))
-		}
-		address := common.HexToAddress(key)
+			var val string
+			if err := json.Unmarshal(entry.value, &val); err != nil {
+				report.addf(file, entry.line, "the ThetaWei amount of %v must be a string: %v", address.Hex(), err)
+				continue
+			}
+			theta, ok := parseAmount(val, "ThetaWei", file, entry.line, report)
+			if !ok {
+				continue
+			}
 
-		theta, success := new(big.Int).SetString(val, 10)
-		if !success {
-			panic(fmt.Sprintf("Failed to parse ThetaWei amount: %v", val))
+			if !exists {
+				addresses = append(addresses, address)
+				balances[address] = theta
+			} else {
+				switch mergeRule {
+				case mergeRuleSum:
+					balances[address] = new(big.Int).Add(balances[address], theta)
+				case mergeRuleOverride:
+					logger.Infof("The balance of %v in %v:%v is overridden by %v:%v", address.Hex(), source.file, source.line, file, entry.line)
+					balances[address] = theta
+				default:
+					report.addf(file, entry.line, "conflicting balance of %v, also at %v:%v", address.Hex(), source.file, source.line)
+					continue
+				}
+			}
+			sources[address] = balanceSource{file: file, line: entry.line}
 		}
+	}
+
+	for _, address := range addresses {
+		theta := balances[address]
 		tfuel := new(big.Int).Mul(initTFuelToThetaRatio, theta)
 		acc := &types.Account{
 			Address:  address,
@@ -156,41 +315,219 @@ func loadInitialBalances(erc20SnapshotJSONFilePath string) *state.StoreView {
 	return sv
 }
 
-func performInitialStakeDeposit(stakeDepositFilePath string, genesisHeight uint64, sv *state.StoreView) *core.ValidatorCandidatePool {
-	var stakeDeposits []StakeDeposit
-	stakeDepositFile, err := os.Open(stakeDepositFilePath)
-	stakeDepositByteValue, err := ioutil.ReadAll(stakeDepositFile)
+func setupMultisigAccounts(multisigFilePath string, sv *state.StoreView, report *validationReport) {
+	file := multisigFilePath
+	entries, err := readJSONEntries(file)
 	if err != nil {
-		panic(fmt.Sprintf("failed to read initial stake deposit file: %v", err))
+		report.addf(file, 0, "failed to read multisig file: %v", err)
+		return
 	}
 
-	json.Unmarshal(stakeDepositByteValue, &stakeDeposits)
-	vcp := &core.ValidatorCandidatePool{}
-	for _, stakeDeposit := range stakeDeposits {
-		if !common.IsHexAddress(stakeDeposit.Source) {
-			panic(fmt.Sprintf("Invalid source address: %v", stakeDeposit.Source))
+	for _, entry := range entries {
+		var ownerSet MultisigOwnerSet
+		if err := json.Unmarshal(entry.value, &ownerSet); err != nil {
+			report.addf(file, entry.line, "invalid multisig owner set: %v", err)
+			continue
+		}
+		signers := []common.Address{}
+		for _, signer := range ownerSet.Signers {
+			if address, ok := parseAddress(signer, "multisig signer", file, entry.line, report); ok {
+				signers = append(signers, address)
+			}
+		}
+		if len(signers) != len(ownerSet.Signers) {
+			continue
+		}
+		keySet, err := types.NewMultisigKeySet(ownerSet.Threshold, signers)
+		if err != nil {
+			report.addf(file, entry.line, "invalid multisig owner set %v: %v", ownerSet.Signers, err)
+			continue
+		}
+		address := keySet.Address()
+		if ownerSet.Address != "" {
+			if !common.IsHexAddress(ownerSet.Address) || common.HexToAddress(ownerSet.Address) != address {
+				report.addf(file, entry.line, "the multisig address %v does not match the owner set, expected: %v", ownerSet.Address, address.Hex())
+				continue
+			}
+		}
+
+		// The multisig account does not need to be registered to receive coins, but writing it into
+		// the genesis snapshot makes the account queryable from the start.
+		if sv.GetAccount(address) == nil {
+			acc := &types.Account{
+				Address:  address,
+				Root:     common.Hash{},
+				CodeHash: types.EmptyCodeHash,
+				Balance:  types.NewCoins(0, 0),
+			}
+			sv.SetAccount(address, acc)
+		}
+		logger.Infof("Multisig account: %v, threshold = %v, signers = %v", address.Hex(), keySet.Threshold, keySet.Signers)
+	}
+}
+
+func setupContracts(contractsFilePath string, sv *state.StoreView, report *validationReport) {
+	file := contractsFilePath
+	entries, err := readJSONEntries(file)
+	if err != nil {
+		report.addf(file, 0, "failed to read contracts file: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		var contract ContractAllocation
+		if err := json.Unmarshal(entry.value, &contract); err != nil {
+			report.addf(file, entry.line, "invalid contract: %v", err)
+			continue
+		}
+		address, ok := parseAddress(contract.Address, "contract", file, entry.line, report)
+		code, parsed := parseHex(contract.Code, "contract code", file, entry.line, report)
+		if parsed && len(code) == 0 {
+			report.addf(file, entry.line, "the contract code must not be empty")
+			parsed = false
+		}
+		ok = ok && parsed
+		slots := make(map[common.Hash]common.Hash)
+		slotStrs := []string{}
+		for slotStr := range contract.Storage {
+			slotStrs = append(slotStrs, slotStr)
+		}
+		sort.Strings(slotStrs) // report the problems in a stable order
+		for _, slotStr := range slotStrs {
+			valueStr := contract.Storage[slotStr]
+			slot, parsed := parseHex(slotStr, "storage slot", file, entry.line, report)
+			if parsed && len(slot) > common.HashLength {
+				report.addf(file, entry.line, "the storage slot %v is longer than %v bytes", slotStr, common.HashLength)
+				parsed = false
+			}
+			value, parsedValue := parseHex(valueStr, "storage value", file, entry.line, report)
+			if parsedValue && len(value) > common.HashLength {
+				report.addf(file, entry.line, "the storage value of slot %v is longer than %v bytes", slotStr, common.HashLength)
+				parsedValue = false
+			}
+			if !parsed || !parsedValue {
+				ok = false
+				continue
+			}
+			slots[common.BytesToHash(slot)] = common.BytesToHash(value)
 		}
-		if !common.IsHexAddress(stakeDeposit.Holder) {
-			panic(fmt.Sprintf("Invalid holder address: %v", stakeDeposit.Holder))
+		if !ok {
+			continue
 		}
-		sourceAddress := common.HexToAddress(stakeDeposit.Source)
-		holderAddress := common.HexToAddress(stakeDeposit.Holder)
-		stakeAmount, success := new(big.Int).SetString(stakeDeposit.Amount, 10)
-		if !success {
-			panic(fmt.Sprintf("Failed to parse Stake amount: %v", stakeDeposit.Amount))
+		if len(sv.GetCode(address)) > 0 {
+			report.addf(file, entry.line, "duplicate contract at %v", address.Hex())
+			continue
+		}
+
+		sv.SetCode(address, code)
+		for slot, value := range slots {
+			sv.SetState(address, slot, value)
+		}
+		logger.Infof("Contract: %v, code size = %v, storage slots = %v", address.Hex(), len(code), len(slots))
+	}
+}
+
+func setupVestingSchedules(vestingFilePath string, genesisHeight uint64, sv *state.StoreView, report *validationReport) {
+	file := vestingFilePath
+	entries, err := readJSONEntries(file)
+	if err != nil {
+		report.addf(file, 0, "failed to read vesting file: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		var allocation VestingAllocation
+		if err := json.Unmarshal(entry.value, &allocation); err != nil {
+			report.addf(file, entry.line, "invalid vesting allocation: %v", err)
+			continue
+		}
+		address, ok := parseAddress(allocation.Address, "vesting", file, entry.line, report)
+		coins := types.NewCoins(0, 0)
+		if allocation.ThetaWei != "" {
+			if theta, parsed := parseAmount(allocation.ThetaWei, "vesting ThetaWei", file, entry.line, report); parsed {
+				coins.ThetaWei = theta
+			} else {
+				ok = false
+			}
+		}
+		if allocation.TFuelWei != "" {
+			if tfuel, parsed := parseAmount(allocation.TFuelWei, "vesting TFuelWei", file, entry.line, report); parsed {
+				coins.TFuelWei = tfuel
+			} else {
+				ok = false
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		schedule := types.VestingSchedule{
+			Coins:       coins,
+			StartHeight: allocation.StartHeight,
+			CliffHeight: allocation.CliffHeight,
+			EndHeight:   allocation.EndHeight,
+		}
+		if err := schedule.Validate(); err != nil {
+			report.addf(file, entry.line, "invalid vesting schedule for %v: %v", address.Hex(), err)
+			continue
+		}
+		if schedule.EndHeight <= genesisHeight {
+			report.addf(file, entry.line, "the vesting schedule for %v ends before the genesis", address.Hex())
+			continue
+		}
+
+		account := sv.GetAccount(address)
+		if account == nil {
+			report.addf(file, entry.line, "the vesting address %v has no initial balance", address.Hex())
+			continue
+		}
+		locked := sv.GetLockedCoins(address, genesisHeight).Plus(coins)
+		if !account.Balance.IsGTE(locked) {
+			report.addf(file, entry.line, "the account %v does NOT have sufficient balance for the vesting schedules. Balance = %v, Locked = %v",
+				address.Hex(), account.Balance, locked)
+			continue
+		}
+		sv.AddVestingSchedule(address, schedule, genesisHeight)
+		logger.Infof("Vesting: %v, %v", address.Hex(), schedule)
+	}
+}
+
+func performInitialStakeDeposit(stakeDepositFilePath string, genesisHeight uint64, sv *state.StoreView, report *validationReport) *core.ValidatorCandidatePool {
+	vcp := &core.ValidatorCandidatePool{}
+	file := stakeDepositFilePath
+	entries, err := readJSONEntries(file)
+	if err != nil {
+		report.addf(file, 0, "failed to read initial stake deposit file: %v", err)
+		entries = nil
+	}
+
+	for _, entry := range entries {
+		var stakeDeposit StakeDeposit
+		if err := json.Unmarshal(entry.value, &stakeDeposit); err != nil {
+			report.addf(file, entry.line, "invalid stake deposit: %v", err)
+			continue
+		}
+		sourceAddress, sourceOK := parseAddress(stakeDeposit.Source, "source", file, entry.line, report)
+		holderAddress, holderOK := parseAddress(stakeDeposit.Holder, "holder", file, entry.line, report)
+		stakeAmount, amountOK := parseAmount(stakeDeposit.Amount, "stake", file, entry.line, report)
+		if !sourceOK || !holderOK || !amountOK {
+			continue
 		}
 
 		sourceAccount := sv.GetAccount(sourceAddress)
 		if sourceAccount == nil {
-			panic(fmt.Sprintf("Failed to retrieve account for source address: %v", sourceAddress))
+			report.addf(file, entry.line, "the source address %v has no initial balance", sourceAddress.Hex())
+			continue
 		}
 		if sourceAccount.Balance.ThetaWei.Cmp(stakeAmount) < 0 {
-			panic(fmt.Sprintf("The source account %v does NOT have sufficient balance for stake deposit. ThetaWeiBalance = %v, StakeAmount = %v",
-				sourceAddress, sourceAccount.Balance.ThetaWei, stakeDeposit.Amount))
+			report.addf(file, entry.line, "the source account %v does NOT have sufficient balance for stake deposit. ThetaWeiBalance = %v, StakeAmount = %v",
+				sourceAddress.Hex(), sourceAccount.Balance.ThetaWei, stakeDeposit.Amount)
+			continue
 		}
 		err := vcp.DepositStake(sourceAddress, holderAddress, stakeAmount)
 		if err != nil {
-			panic(fmt.Sprintf("Failed to deposit stake, err: %v", err))
+			report.addf(file, entry.line, "failed to deposit stake: %v", err)
+			continue
 		}
 
 		stake := types.Coins{
@@ -210,6 +547,150 @@ func performInitialStakeDeposit(stakeDepositFilePath string, genesisHeight uint6
 	return vcp
 }
 
+func performInitialGuardianStakeDeposit(stakeDepositFilePath string, genesisHeight uint64, sv *state.StoreView, report *validationReport) {
+	gcp := core.NewGuardianCandidatePool()
+	file := stakeDepositFilePath
+	entries, err := readJSONEntries(file)
+	if err != nil {
+		report.addf(file, 0, "failed to read initial guardian stake deposit file: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		var stakeDeposit BLSStakeDeposit
+		if err := json.Unmarshal(entry.value, &stakeDeposit); err != nil {
+			report.addf(file, entry.line, "invalid guardian stake deposit: %v", err)
+			continue
+		}
+		sourceAddress, sourceOK := parseAddress(stakeDeposit.Source, "source", file, entry.line, report)
+		holderAddress, blsPubkey, summaryOK := parseNodeSummary(stakeDeposit.Summary, false, file, entry.line, report)
+		stakeAmount, amountOK := parseAmount(stakeDeposit.Amount, "guardian stake", file, entry.line, report)
+		if !sourceOK || !summaryOK || !amountOK {
+			continue
+		}
+
+		stake := types.Coins{
+			ThetaWei: stakeAmount,
+			TFuelWei: new(big.Int).SetUint64(0),
+		}
+		sourceAccount, ok := checkStakeSource(sourceAddress, stake, file, entry.line, sv, report)
+		if !ok {
+			continue
+		}
+		if err := gcp.DepositStake(sourceAddress, holderAddress, stakeAmount, blsPubkey, genesisHeight); err != nil {
+			report.addf(file, entry.line, "failed to deposit guardian stake: %v", err)
+			continue
+		}
+		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
+		sv.SetAccount(sourceAddress, sourceAccount)
+	}
+
+	sv.UpdateGuardianCandidatePool(gcp)
+}
+
+func performInitialEliteEdgeNodeStakeDeposit(stakeDepositFilePath string, genesisHeight uint64, sv *state.StoreView, report *validationReport) {
+	eenp := state.NewEliteEdgeNodePool(sv, false)
+	file := stakeDepositFilePath
+	entries, err := readJSONEntries(file)
+	if err != nil {
+		report.addf(file, 0, "failed to read initial elite edge node stake deposit file: %v", err)
+		return
+	}
+
+	for _, entry := range entries {
+		var stakeDeposit BLSStakeDeposit
+		if err := json.Unmarshal(entry.value, &stakeDeposit); err != nil {
+			report.addf(file, entry.line, "invalid elite edge node stake deposit: %v", err)
+			continue
+		}
+		sourceAddress, sourceOK := parseAddress(stakeDeposit.Source, "source", file, entry.line, report)
+		holderAddress, blsPubkey, summaryOK := parseNodeSummary(stakeDeposit.Summary, true, file, entry.line, report)
+		stakeAmount, amountOK := parseAmount(stakeDeposit.Amount, "elite edge node stake", file, entry.line, report)
+		if !sourceOK || !summaryOK || !amountOK {
+			continue
+		}
+
+		stake := types.Coins{
+			ThetaWei: new(big.Int).SetUint64(0),
+			TFuelWei: stakeAmount,
+		}
+		sourceAccount, ok := checkStakeSource(sourceAddress, stake, file, entry.line, sv, report)
+		if !ok {
+			continue
+		}
+		if err := eenp.DepositStake(sourceAddress, holderAddress, stakeAmount, blsPubkey, genesisHeight); err != nil {
+			report.addf(file, entry.line, "failed to deposit elite edge node stake: %v", err)
+			continue
+		}
+		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
+		sv.SetAccount(sourceAddress, sourceAccount)
+	}
+}
+
+// checkStakeSource returns the source account if it has sufficient balance for the stake
+func checkStakeSource(sourceAddress common.Address, stake types.Coins, file string, line int, sv *state.StoreView, report *validationReport) (*types.Account, bool) {
+	sourceAccount := sv.GetAccount(sourceAddress)
+	if sourceAccount == nil {
+		report.addf(file, line, "the source address %v has no initial balance", sourceAddress.Hex())
+		return nil, false
+	}
+	if !sourceAccount.Balance.IsGTE(stake) {
+		report.addf(file, line, "the source account %v does NOT have sufficient balance for stake deposit. Balance = %v, Stake = %v",
+			sourceAddress.Hex(), sourceAccount.Balance, stake)
+		return nil, false
+	}
+	return sourceAccount, true
+}
+
+// parseNodeSummary parses the summary of a guardian, or of an elite edge node if withHash is true,
+// and verifies the BLS proof of possession and the holder's signature of it. It returns the
+// holder address and the BLS public key.
+func parseNodeSummary(summary string, withHash bool, file string, line int, report *validationReport) (common.Address, *bls.PublicKey, bool) {
+	summary = strings.TrimPrefix(summary, "0x")
+	expectedLen := 458
+	if withHash {
+		expectedLen = 522
+	}
+	summaryBytes, err := hex.DecodeString(summary)
+	if err != nil || len(summary) != expectedLen {
+		report.addf(file, line, "invalid node summary, expected %v hex characters", expectedLen)
+		return common.Address{}, nil, false
+	}
+
+	holderAddress := common.BytesToAddress(summaryBytes[:20])
+	blsPubkey, err := bls.PublicKeyFromBytes(summaryBytes[20:68])
+	if err != nil {
+		report.addf(file, line, "invalid BLS public key in the node summary: %v", err)
+		return common.Address{}, nil, false
+	}
+	blsPop, err := bls.SignatureFromBytes(summaryBytes[68:164])
+	if err != nil {
+		report.addf(file, line, "invalid BLS pop in the node summary: %v", err)
+		return common.Address{}, nil, false
+	}
+	holderSig, err := crypto.SignatureFromBytes(summaryBytes[164:229])
+	if err != nil {
+		report.addf(file, line, "invalid holder signature in the node summary: %v", err)
+		return common.Address{}, nil, false
+	}
+	if withHash {
+		expectedSummaryHash := crypto.Keccak256Hash([]byte("0x" + summary[:458]))
+		if !bytes.Equal(expectedSummaryHash.Bytes(), summaryBytes[229:]) {
+			report.addf(file, line, "unmatched node summary hash, expected: %v", expectedSummaryHash.Hex())
+			return common.Address{}, nil, false
+		}
+	}
+	if !holderSig.Verify(blsPop.ToBytes(), holderAddress) {
+		report.addf(file, line, "the BLS key in the node summary is not signed by the holder %v", holderAddress.Hex())
+		return common.Address{}, nil, false
+	}
+	if !blsPop.PopVerify(blsPubkey) {
+		report.addf(file, line, "invalid BLS pop in the node summary of %v", holderAddress.Hex())
+		return common.Address{}, nil, false
+	}
+	return holderAddress, blsPubkey, true
+}
+
 func proveVCP(sv *state.StoreView) (*core.VCPProof, error) {
 	vp := &core.VCPProof{}
 	vcpKey := state.ValidatorCandidatePoolKey()
@@ -229,41 +710,75 @@ func writeGenesisSnapshot(sv *state.StoreView, metadata *core.SnapshotMetadata,
 	if err != nil {
 		return err
 	}
-	writeStoreView(sv, true, writer)
-	return err
+	return writeStoreView(sv, true, writer)
 }
 
-func writeStoreView(sv *state.StoreView, needAccountStorage bool, writer *bufio.Writer) {
+func writeStoreView(sv *state.StoreView, needAccountStorage bool, writer *bufio.Writer) error {
 	height := core.Itobytes(sv.Height())
 	err := core.WriteRecord(writer, []byte{core.SVStart}, height)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	sv.GetStore().Traverse(nil, func(k, v common.Bytes) bool {
 		err = core.WriteRecord(writer, k, v)
 		if err != nil {
-			panic(err)
+			return false
 		}
-		return true
+		if needAccountStorage && bytes.HasPrefix(k, state.AccountKeyPrefix()) {
+			err = writeAccountStorage(sv, v, height, writer)
+		}
+		return err == nil
 	})
+	if err != nil {
+		return err
+	}
 	err = core.WriteRecord(writer, []byte{core.SVEnd}, height)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	return writer.Flush()
+}
+
+// writeAccountStorage writes the storage of the account right after the account, nested the same
+// way as the snapshots exported by the nodes
+func writeAccountStorage(sv *state.StoreView, accountBytes common.Bytes, height common.Bytes, writer *bufio.Writer) error {
+	account := &types.Account{}
+	err := types.FromBytes(accountBytes, account)
+	if err != nil {
+		return fmt.Errorf("Failed to decode Account: %v", err)
 	}
-	writer.Flush()
+	if account.Root == (common.Hash{}) {
+		return nil
+	}
+	err = core.WriteRecord(writer, []byte{core.SVStart}, height)
+	if err != nil {
+		return err
+	}
+	storage := treestore.NewTreeStore(account.Root, sv.GetDB())
+	storage.Traverse(nil, func(k, v common.Bytes) bool {
+		err = core.WriteRecord(writer, k, v)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return core.WriteRecord(writer, []byte{core.SVEnd}, height)
 }
 
 func sanityChecks(sv *state.StoreView) error {
 	thetaWeiTotal := new(big.Int).SetUint64(0)
 	tfuelWeiTotal := new(big.Int).SetUint64(0)
+	eenStakeTotal := new(big.Int).SetUint64(0)
 
 	vcpAnalyzed := false
+	var traverseErr error
 	sv.GetStore().Traverse(nil, func(key, val common.Bytes) bool {
 		if bytes.Compare(key, state.ValidatorCandidatePoolKey()) == 0 {
 			var vcp core.ValidatorCandidatePool
 			err := rlp.DecodeBytes(val, &vcp)
 			if err != nil {
-				panic(fmt.Sprintf("Failed to decode VCP: %v", err))
+				traverseErr = fmt.Errorf("Failed to decode VCP: %v", err)
+				return false
 			}
 			for _, sc := range vcp.SortedCandidates {
 				logger.Infof("--------------------------------------------------------")
@@ -275,23 +790,68 @@ func sanityChecks(sv *state.StoreView) error {
 				logger.Infof("--------------------------------------------------------")
 			}
 			vcpAnalyzed = true
+		} else if bytes.Compare(key, state.GuardianCandidatePoolKey()) == 0 {
+			var gcp core.GuardianCandidatePool
+			err := rlp.DecodeBytes(val, &gcp)
+			if err != nil {
+				traverseErr = fmt.Errorf("Failed to decode GCP: %v", err)
+				return false
+			}
+			for _, g := range gcp.SortedGuardians {
+				logger.Infof("Guardian Candidate: %v, totalStake  = %v", g.Holder, g.TotalStake())
+				for _, stake := range g.Stakes {
+					thetaWeiTotal = new(big.Int).Add(thetaWeiTotal, stake.Amount)
+					logger.Infof("     Stake: source = %v, stakeAmount = %v", stake.Source, stake.Amount)
+				}
+			}
+		} else if bytes.HasPrefix(key, state.EliteEdgeNodeKeyPrefix()) {
+			var een core.EliteEdgeNode
+			err := rlp.DecodeBytes(val, &een)
+			if err != nil {
+				traverseErr = fmt.Errorf("Failed to decode elite edge node: %v", err)
+				return false
+			}
+			logger.Infof("Elite Edge Node: %v, totalStake  = %v", een.Holder, een.TotalStake())
+			for _, stake := range een.Stakes {
+				tfuelWeiTotal = new(big.Int).Add(tfuelWeiTotal, stake.Amount)
+				eenStakeTotal = new(big.Int).Add(eenStakeTotal, stake.Amount)
+				logger.Infof("     Stake: source = %v, stakeAmount = %v", stake.Source, stake.Amount)
+			}
+		} else if bytes.Compare(key, state.EliteEdgeNodesTotalActiveStakeKey()) == 0 {
+			// checked against the stakes of the elite edge nodes below
 		} else if bytes.Compare(key, state.StakeTransactionHeightListKey()) == 0 {
 			var hl types.HeightList
 			err := rlp.DecodeBytes(val, &hl)
 			if err != nil {
-				panic(fmt.Sprintf("Failed to decode Height List: %v", err))
+				traverseErr = fmt.Errorf("Failed to decode Height List: %v", err)
+				return false
 			}
 			if len(hl.Heights) != 1 {
-				panic(fmt.Sprintf("The genesis height list should contain only one height: %v", hl.Heights))
+				traverseErr = fmt.Errorf("The genesis height list should contain only one height: %v", hl.Heights)
+				return false
 			}
 			if hl.Heights[0] != uint64(0) {
-				panic(fmt.Sprintf("Only height 0 should be in the genesis height list"))
+				traverseErr = fmt.Errorf("Only height 0 should be in the genesis height list")
+				return false
 			}
+		} else if bytes.HasPrefix(key, state.VestingSchedulesKeyPrefix()) {
+			var schedules []types.VestingSchedule
+			err := rlp.DecodeBytes(val, &schedules)
+			if err != nil {
+				traverseErr = fmt.Errorf("Failed to decode vesting schedules: %v", err)
+				return false
+			}
+			for _, vs := range schedules {
+				logger.Infof("Vesting: %v, %v", common.BytesToAddress(key[len(state.VestingSchedulesKeyPrefix()):]).Hex(), vs)
+			}
+		} else if bytes.HasPrefix(key, state.CodeKeyPrefix()) {
+			logger.Infof("Contract code: %v, size = %v", common.BytesToHash(key[len(state.CodeKeyPrefix()):]).Hex(), len(val))
 		} else { // regular account
 			var account types.Account
 			err := rlp.DecodeBytes(val, &account)
 			if err != nil {
-				panic(fmt.Sprintf("Failed to decode Account: %v", err))
+				traverseErr = fmt.Errorf("Failed to decode Account: %v", err)
+				return false
 			}
 
 			thetaWei := account.Balance.ThetaWei
@@ -303,19 +863,25 @@ func sanityChecks(sv *state.StoreView) error {
 		}
 		return true
 	})
+	if traverseErr != nil {
+		return traverseErr
+	}
 
 	// Check #1: VCP analyzed
 	vcpProof, err := proveVCP(sv)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to get VCP proof from storeview"))
+		return fmt.Errorf("Failed to get VCP proof from storeview: %v", err)
 	}
 	_, _, err = trie.VerifyProof(sv.Hash(), state.ValidatorCandidatePoolKey(), vcpProof)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to verify VCP proof in storeview"))
+		return fmt.Errorf("Failed to verify VCP proof in storeview: %v", err)
 	}
 	if !vcpAnalyzed {
 		return fmt.Errorf("VCP not detected in the genesis file")
 	}
+	if sv.GetTotalEENStake().Cmp(eenStakeTotal) != 0 {
+		return fmt.Errorf("Unmatched elite edge node stake total: recorded = %v, calculated = %v", sv.GetTotalEENStake(), eenStakeTotal)
+	}
 
 	// Check #2: Sum(ThetaWei) + Sum(Stake) == 1 * 10^9 * 10^18
 	oneBillion := new(big.Int).SetUint64(1000000000)
@@ -329,7 +895,7 @@ func sanityChecks(sv *state.StoreView) error {
 	logger.Infof("Expected   ThetaWei total = %v", expectedThetaWeiTotal)
 	logger.Infof("Calculated ThetaWei total = %v", thetaWeiTotal)
 
-	// Check #3: Sum(TFuelWei) == 5 * 10^9 * 10^18
+	// Check #3: Sum(TFuelWei) + Sum(EEN Stake) == 5 * 10^9 * 10^18
 	expectedTFuelWeiTotal := new(big.Int).Mul(fiveBillion, ten18)
 	if expectedTFuelWeiTotal.Cmp(tfuelWeiTotal) != 0 {
 		return fmt.Errorf("Unmatched TFuelWei total: expected = %v, calculated = %v", expectedTFuelWeiTotal, tfuelWeiTotal)
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/thetatoken/theta/common"
+)
+
+// validationIssue is a problem found in an input file. The line is 0 if the problem is not
+// specific to an entry of the file.
+type validationIssue struct {
+	file    string
+	line    int
+	message string
+}
+
+func (vi validationIssue) String() string {
+	if vi.line == 0 {
+		return fmt.Sprintf("%v: %v", vi.file, vi.message)
+	}
+	return fmt.Sprintf("%v:%v: %v", vi.file, vi.line, vi.message)
+}
+
+// validationReport collects the problems of all the input files, so that they can be fixed
+// in one go instead of one run at a time
+type validationReport struct {
+	issues []validationIssue
+}
+
+func (r *validationReport) addf(file string, line int, format string, args ...interface{}) {
+	r.issues = append(r.issues, validationIssue{
+		file:    file,
+		line:    line,
+		message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *validationReport) hasIssues() bool {
+	return len(r.issues) > 0
+}
+
+func (r *validationReport) print(w io.Writer) {
+	fmt.Fprintf(w, "Found %v problem(s) in the input files:\n", len(r.issues))
+	for _, issue := range r.issues {
+		fmt.Fprintf(w, "  %v\n", issue)
+	}
+}
+
+// jsonEntry is an element of a JSON array, or a key-value of a JSON object, along with the line
+// it starts at
+type jsonEntry struct {
+	key   string
+	value json.RawMessage
+	line  int
+}
+
+// readJSONEntries reads the elements of the JSON array, or the key-values of the JSON object in
+// the file, in the order of the file
+func readJSONEntries(filePath string) ([]jsonEntry, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '[' && delim != '{') {
+		return nil, fmt.Errorf("expected a JSON array or object")
+	}
+
+	entries := []jsonEntry{}
+	for dec.More() {
+		entry := jsonEntry{line: lineAt(data, dec.InputOffset())}
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON at line %v: %v", entry.line, err)
+			}
+			entry.key, _ = tok.(string)
+		}
+		if err := dec.Decode(&entry.value); err != nil {
+			return nil, fmt.Errorf("invalid JSON at line %v: %v", entry.line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// lineAt returns the line of the next token after the offset
+func lineAt(data []byte, offset int64) int {
+	pos := int(offset)
+	for pos < len(data) && bytes.IndexByte([]byte(" \t\r\n,:"), data[pos]) >= 0 {
+		pos++
+	}
+	return bytes.Count(data[:pos], []byte("\n")) + 1
+}
+
+// parseAddress reports an issue and returns false if the address is invalid
+func parseAddress(address string, what string, file string, line int, report *validationReport) (common.Address, bool) {
+	if !common.IsHexAddress(address) {
+		report.addf(file, line, "invalid %v address: %q", what, address)
+		return common.Address{}, false
+	}
+	return common.HexToAddress(address), true
+}
+
+// parseAmount reports an issue and returns false if the amount is not a non-negative integer
+func parseAmount(amount string, what string, file string, line int, report *validationReport) (*big.Int, bool) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() < 0 {
+		report.addf(file, line, "invalid %v amount: %q", what, amount)
+		return nil, false
+	}
+	return value, true
+}
+
+// parseHex reports an issue and returns false if the value is not hex encoded, with or without
+// the 0x prefix
+func parseHex(value string, what string, file string, line int, report *validationReport) ([]byte, bool) {
+	str := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	if len(str)%2 == 1 {
+		str = "0" + str
+	}
+	data, err := hex.DecodeString(str)
+	if err != nil {
+		report.addf(file, line, "invalid %v: %q", what, value)
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc"
+)
+
+// accountDelta is the balance change of an account implied by a transaction
+type accountDelta struct {
+	Address  common.Address
+	ThetaWei *big.Int
+	TFuelWei *big.Int
+}
+
+type stakeEvent struct {
+	Event    string
+	Source   common.Address
+	Holder   common.Address
+	Purpose  uint8
+	ThetaWei *big.Int
+	TFuelWei *big.Int
+}
+
+type txReceipt struct {
+	GasUsed uint64
+}
+
+// decodeTx returns the balance changes and the stake events implied by the transaction. For the
+// inputs, the coins already include the fee.
+func decodeTx(txType byte, raw json.RawMessage, receipt json.RawMessage) ([]accountDelta, []stakeEvent, error) {
+	var deltas []accountDelta
+	var events []stakeEvent
+	credit := func(addr common.Address, coins types.Coins) {
+		coins = coins.NoNil()
+		deltas = append(deltas, accountDelta{addr, coins.ThetaWei, coins.TFuelWei})
+	}
+	debit := func(addr common.Address, coins types.Coins) {
+		credit(addr, coins.Negative())
+	}
+
+	switch txType {
+	case rpc.TxTypeCoinbase:
+		tx := &types.CoinbaseTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		for _, output := range tx.Outputs {
+			credit(output.Address, output.Coins)
+		}
+	case rpc.TxTypeSend:
+		tx := &types.SendTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		for _, input := range tx.Inputs {
+			debit(input.Address, input.Coins)
+		}
+		for _, output := range tx.Outputs {
+			credit(output.Address, output.Coins)
+		}
+	case rpc.TxTypeReserveFund:
+		tx := &types.ReserveFundTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Fee)
+	case rpc.TxTypeReleaseFund:
+		tx := &types.ReleaseFundTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Fee)
+	case rpc.TxTypeServicePayment:
+		tx := &types.ServicePaymentTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Source.Coins)
+		credit(tx.Target.Address, tx.Source.Coins.Minus(tx.Fee))
+	case rpc.TxTypeSplitRule:
+		tx := &types.SplitRuleTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Initiator.Address, tx.Fee)
+	case rpc.TxTypeSmartContract:
+		tx := &types.SmartContractTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		fee := big.NewInt(0)
+		if len(receipt) > 0 && string(receipt) != "null" && tx.GasPrice != nil {
+			r := &txReceipt{}
+			if err := json.Unmarshal(receipt, r); err != nil {
+				return nil, nil, err
+			}
+			fee.Mul(new(big.Int).SetUint64(r.GasUsed), tx.GasPrice)
+		}
+		value := tx.From.Coins.NoNil()
+		debit(tx.From.Address, types.Coins{ThetaWei: value.ThetaWei, TFuelWei: new(big.Int).Add(value.TFuelWei, fee)})
+		if !value.IsZero() {
+			credit(tx.To.Address, value)
+		}
+	case rpc.TxTypeDepositStake, rpc.TxTypeDepositStakeTxV2:
+		tx := &types.DepositStakeTxV2{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Source.Coins)
+		stake := tx.Source.Coins.Minus(tx.Fee)
+		events = append(events, stakeEvent{"deposit", tx.Source.Address, tx.Holder.Address, tx.Purpose, stake.ThetaWei, stake.TFuelWei})
+	case rpc.TxTypeWithdrawStake:
+		tx := &types.WithdrawStakeTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Fee)
+		events = append(events, stakeEvent{"withdraw", tx.Source.Address, tx.Holder.Address, tx.Purpose, big.NewInt(0), big.NewInt(0)})
+	case rpc.TxTypeStakeRewardDistributionTx:
+		tx := &types.StakeRewardDistributionTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Holder.Address, tx.Fee)
+	case rpc.TxTypeVesting:
+		tx := &types.VestingTx{}
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return nil, nil, err
+		}
+		debit(tx.Source.Address, tx.Source.Coins)
+		credit(tx.Beneficiary.Address, tx.Beneficiary.Coins)
+	case rpc.TxTypeSlash:
+		// no balance change until the slashed stake is returned
+	default:
+		return nil, nil, fmt.Errorf("Unknown transaction type: %v", txType)
+	}
+
+	return deltas, events, nil
+}
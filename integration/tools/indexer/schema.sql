@@ -0,0 +1,61 @@
+-- Schema of the indexer database. Apply it before starting the indexer, e.g. with
+-- psql -f schema.sql, or pass its path to the -schema flag of the indexer.
+--
+-- The addresses and the hashes are stored as lower case 0x-prefixed hex strings, and the coin
+-- amounts in wei. All the rows of a block are deleted with the block when the indexer rolls back.
+
+CREATE TABLE IF NOT EXISTS blocks (
+    height      BIGINT PRIMARY KEY,
+    hash        CHAR(66) NOT NULL UNIQUE,
+    parent_hash CHAR(66) NOT NULL,
+    epoch       BIGINT NOT NULL,
+    timestamp   BIGINT NOT NULL,
+    proposer    CHAR(42) NOT NULL,
+    status      SMALLINT NOT NULL,
+    num_txs     INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    block_height BIGINT NOT NULL REFERENCES blocks (height) ON DELETE CASCADE,
+    tx_index     INTEGER NOT NULL,
+    hash         CHAR(66) NOT NULL,
+    type         SMALLINT NOT NULL,
+    data         JSONB NOT NULL,
+    receipt      JSONB,
+    PRIMARY KEY (block_height, tx_index)
+);
+
+CREATE INDEX IF NOT EXISTS transactions_hash_idx ON transactions (hash);
+
+-- The balance changes implied by the transactions, i.e. the transferred coins and the fees. The
+-- balance changes made by the smart contracts internally are not included.
+CREATE TABLE IF NOT EXISTS account_deltas (
+    block_height BIGINT NOT NULL REFERENCES blocks (height) ON DELETE CASCADE,
+    tx_index     INTEGER NOT NULL,
+    address      CHAR(42) NOT NULL,
+    theta_wei    NUMERIC(78, 0) NOT NULL,
+    tfuel_wei    NUMERIC(78, 0) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS account_deltas_address_idx ON account_deltas (address, block_height);
+
+CREATE TABLE IF NOT EXISTS stake_events (
+    block_height BIGINT NOT NULL REFERENCES blocks (height) ON DELETE CASCADE,
+    tx_index     INTEGER NOT NULL,
+    event        VARCHAR(16) NOT NULL, -- deposit or withdraw
+    source       CHAR(42) NOT NULL,
+    holder       CHAR(42) NOT NULL,
+    purpose      SMALLINT NOT NULL,
+    theta_wei    NUMERIC(78, 0) NOT NULL,
+    tfuel_wei    NUMERIC(78, 0) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stake_events_holder_idx ON stake_events (holder, block_height);
+CREATE INDEX IF NOT EXISTS stake_events_source_idx ON stake_events (source, block_height);
+
+-- The last indexed block, from which the indexer resumes
+CREATE TABLE IF NOT EXISTS indexer_cursor (
+    id         SMALLINT PRIMARY KEY CHECK (id = 1),
+    height     BIGINT NOT NULL,
+    block_hash CHAR(66) NOT NULL
+);
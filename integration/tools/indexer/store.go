@@ -0,0 +1,165 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	_ "github.com/lib/pq"
+
+	"github.com/thetatoken/theta/common"
+)
+
+// store writes the indexed data into PostgreSQL
+type store struct {
+	db *sql.DB
+}
+
+func newStore(dsn string) (*store, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+	return &store{db: db}, nil
+}
+
+func (s *store) close() {
+	s.db.Close()
+}
+
+// applySchema executes the statements of the schema file
+func (s *store) applySchema(path string) error {
+	schema, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(string(schema))
+	return err
+}
+
+// getCursor returns the last indexed block, found is false if nothing has been indexed yet
+func (s *store) getCursor() (height uint64, hash common.Hash, found bool, err error) {
+	var hashStr string
+	err = s.db.QueryRow("SELECT height, block_hash FROM indexer_cursor WHERE id = 1").Scan(&height, &hashStr)
+	if err == sql.ErrNoRows {
+		return 0, common.Hash{}, false, nil
+	}
+	if err != nil {
+		return 0, common.Hash{}, false, err
+	}
+	return height, common.HexToHash(hashStr), true, nil
+}
+
+// indexBlock writes the block, its transactions, the account deltas and the stake events, and
+// moves the cursor to the block, all in one database transaction
+func (s *store) indexBlock(block *rpcBlock) (err error) {
+	dbTx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback()
+		}
+	}()
+
+	height := uint64(block.Height)
+	_, err = dbTx.Exec(`INSERT INTO blocks (height, hash, parent_hash, epoch, timestamp, proposer, status, num_txs)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		height, hexString(block.Hash.Hex()), hexString(block.Parent.Hex()), uint64(block.Epoch),
+		block.timestamp(), hexString(block.Proposer.Hex()), int(block.Status), len(block.Txs))
+	if err != nil {
+		return err
+	}
+
+	for idx, tx := range block.Txs {
+		var receipt interface{}
+		if len(tx.Receipt) > 0 && string(tx.Receipt) != "null" {
+			receipt = string(tx.Receipt)
+		}
+		_, err = dbTx.Exec(`INSERT INTO transactions (block_height, tx_index, hash, type, data, receipt)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			height, idx, hexString(tx.Hash.Hex()), int(tx.Type), rawJSON(tx.Raw), receipt)
+		if err != nil {
+			return err
+		}
+
+		deltas, events, decodeErr := decodeTx(tx.Type, tx.Raw, tx.Receipt)
+		if decodeErr != nil {
+			logger.Warnf("Failed to decode transaction %v: %v", tx.Hash.Hex(), decodeErr)
+			continue
+		}
+		for _, delta := range deltas {
+			_, err = dbTx.Exec(`INSERT INTO account_deltas (block_height, tx_index, address, theta_wei, tfuel_wei)
+				VALUES ($1, $2, $3, $4, $5)`,
+				height, idx, hexString(delta.Address.Hex()), delta.ThetaWei.String(), delta.TFuelWei.String())
+			if err != nil {
+				return err
+			}
+		}
+		for _, event := range events {
+			_, err = dbTx.Exec(`INSERT INTO stake_events (block_height, tx_index, event, source, holder, purpose, theta_wei, tfuel_wei)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				height, idx, event.Event, hexString(event.Source.Hex()), hexString(event.Holder.Hex()),
+				int(event.Purpose), event.ThetaWei.String(), event.TFuelWei.String())
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = dbTx.Exec(`INSERT INTO indexer_cursor (id, height, block_hash) VALUES (1, $1, $2)
+		ON CONFLICT (id) DO UPDATE SET height = EXCLUDED.height, block_hash = EXCLUDED.block_hash`,
+		height, hexString(block.Hash.Hex()))
+	if err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+// rollback deletes the blocks at and above the height, and moves the cursor to the block below
+func (s *store) rollback(height uint64) (err error) {
+	dbTx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			dbTx.Rollback()
+		}
+	}()
+
+	if _, err = dbTx.Exec("DELETE FROM blocks WHERE height >= $1", height); err != nil {
+		return err
+	}
+
+	var prevHash string
+	err = dbTx.QueryRow("SELECT hash FROM blocks WHERE height = $1", height-1).Scan(&prevHash)
+	if err == sql.ErrNoRows {
+		_, err = dbTx.Exec("DELETE FROM indexer_cursor WHERE id = 1")
+	} else if err == nil {
+		_, err = dbTx.Exec("UPDATE indexer_cursor SET height = $1, block_hash = $2 WHERE id = 1", height-1, prevHash)
+	}
+	if err != nil {
+		return err
+	}
+
+	return dbTx.Commit()
+}
+
+func hexString(s string) string {
+	return strings.ToLower(s)
+}
+
+// rawJSON makes sure the value is stored as a valid JSON document
+func rawJSON(data json.RawMessage) string {
+	if len(data) == 0 {
+		return "null"
+	}
+	return string(data)
+}
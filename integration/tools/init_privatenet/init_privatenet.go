@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	ks "github.com/thetatoken/theta/wallet/softwallet/keystore"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "privatenet"})
+
+var genesisHashRegexp = regexp.MustCompile(`Genesis block hash: (0x[0-9a-fA-F]{64})`)
+
+// node is a node of the generated cluster
+type node struct {
+	Name     string
+	Address  common.Address
+	Guardian bool
+	P2PPort  int
+	RPCPort  int
+}
+
+type stakeDeposit struct {
+	Source string `json:"source"`
+	Holder string `json:"holder"`
+	Amount string `json:"amount"`
+}
+
+type params struct {
+	chainID        string
+	numValidators  int
+	numGuardians   int
+	outputDir      string
+	password       string
+	p2pPort        int
+	rpcPort        int
+	balance        string
+	validatorStake string
+	genesisTool    string
+	image          string
+}
+
+// Example:
+// init_privatenet -validators=4 -guardians=2 -output=./cluster
+// cd ./cluster && ./start.sh
+//
+// The tool generates the keys of the nodes, the genesis data files, the genesis snapshot via the
+// generate_genesis tool, the config of each node, a docker-compose.yml and a start.sh to run the
+// cluster locally. Each node account receives the initial balance, and the validators deposit their
+// initial stake in the genesis. The guardians need to deposit their stake after the cluster starts,
+// as described in the generated README.md.
+func main() {
+	p := parseArguments()
+
+	if p.numValidators <= 0 || p.numGuardians < 0 {
+		handleError(fmt.Errorf("At least one validator is required"))
+	}
+	if _, err := os.Stat(p.outputDir); err == nil {
+		handleError(fmt.Errorf("Output directory %v already exists", p.outputDir))
+	}
+
+	nodes := make([]*node, 0, p.numValidators+p.numGuardians)
+	for i := 0; i < p.numValidators+p.numGuardians; i++ {
+		guardian := i >= p.numValidators
+		name := fmt.Sprintf("validator%d", i+1)
+		if guardian {
+			name = fmt.Sprintf("guardian%d", i-p.numValidators+1)
+		}
+		n := &node{
+			Name:     name,
+			Guardian: guardian,
+			P2PPort:  p.p2pPort + i,
+			RPCPort:  p.rpcPort + i,
+		}
+		addr, err := generateKey(p, n)
+		handleError(err)
+		n.Address = addr
+		nodes = append(nodes, n)
+		logger.Infof("Generated key of %v: %v", n.Name, n.Address.Hex())
+	}
+
+	handleError(writeGenesisData(p, nodes))
+	genesisHash, err := generateGenesis(p)
+	handleError(err)
+
+	localHost := func(*node) string { return "127.0.0.1" }
+	dockerHost := func(n *node) string { return n.Name }
+	for _, n := range nodes {
+		handleError(writeNodeConfig(path.Join(p.outputDir, n.Name, "config.yaml"), nodes, n, genesisHash, localHost))
+		handleError(writeNodeConfig(path.Join(p.outputDir, "docker", n.Name+".yaml"), nodes, n, genesisHash, dockerHost))
+		handleError(copyFile(path.Join(p.outputDir, "genesis"), path.Join(p.outputDir, n.Name, "snapshot")))
+	}
+	handleError(writeDockerCompose(p, nodes))
+	handleError(writeStartScript(p, nodes))
+	handleError(writeCLIConfig(p, nodes))
+	handleError(writeReadme(p, nodes, genesisHash))
+
+	fmt.Println("")
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Printf("Privatenet with %v validators and %v guardians generated under %v\n", p.numValidators, p.numGuardians, p.outputDir)
+	fmt.Printf("Genesis block hash: %v\n", genesisHash)
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Println("")
+}
+
+func parseArguments() *params {
+	chainIDPtr := flag.String("chainID", "privatenet", "the ID of the chain")
+	validatorsPtr := flag.Int("validators", 4, "the number of the validator nodes")
+	guardiansPtr := flag.Int("guardians", 0, "the number of the guardian nodes")
+	outputPtr := flag.String("output", "./privatenet_cluster", "the directory to write the cluster to, must not exist")
+	passwordPtr := flag.String("password", "qwertyuiop", "the password of the node keys")
+	p2pPortPtr := flag.Int("p2p_port", 12000, "the P2P port of the first node, incremented for each node")
+	rpcPortPtr := flag.Int("rpc_port", 16888, "the RPC port of the first node, incremented for each node")
+	balancePtr := flag.String("balance", "1000000000000000000000000000", "the initial ThetaWei balance of each node account")
+	stakePtr := flag.String("validator_stake", "50000000000000000000000000", "the initial ThetaWei stake of each validator")
+	genesisToolPtr := flag.String("genesis_tool", "generate_genesis", "the path of the generate_genesis tool")
+	imagePtr := flag.String("image", "theta:latest", "the docker image with the theta binary, used by docker-compose.yml")
+	flag.Parse()
+
+	return &params{
+		chainID:        *chainIDPtr,
+		numValidators:  *validatorsPtr,
+		numGuardians:   *guardiansPtr,
+		outputDir:      *outputPtr,
+		password:       *passwordPtr,
+		p2pPort:        *p2pPortPtr,
+		rpcPort:        *rpcPortPtr,
+		balance:        *balancePtr,
+		validatorStake: *stakePtr,
+		genesisTool:    *genesisToolPtr,
+		image:          *imagePtr,
+	}
+}
+
+// generateKey generates the key of the node, and stores it for both the node and thetacli
+func generateKey(p *params, n *node) (common.Address, error) {
+	privKey, _, err := crypto.GenerateKeyPair()
+	if err != nil {
+		return common.Address{}, err
+	}
+	key := ks.NewKey(privKey)
+
+	keysDirs := []string{
+		path.Join(p.outputDir, n.Name, "key"),
+		path.Join(p.outputDir, "thetacli", "keys"),
+	}
+	for _, keysDir := range keysDirs {
+		keystore, err := ks.NewKeystoreEncrypted(keysDir, ks.LightScryptN, ks.LightScryptP)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if err := keystore.StoreKey(key, p.password); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return key.Address, nil
+}
+
+// writeGenesisData writes the initial balances and the stake deposits for the generate_genesis tool
+func writeGenesisData(p *params, nodes []*node) error {
+	balance, ok := new(big.Int).SetString(p.balance, 10)
+	if !ok {
+		return fmt.Errorf("Invalid balance: %v", p.balance)
+	}
+	stake, ok := new(big.Int).SetString(p.validatorStake, 10)
+	if !ok {
+		return fmt.Errorf("Invalid validator stake: %v", p.validatorStake)
+	}
+	if stake.Cmp(core.MinValidatorStakeDeposit) < 0 || stake.Cmp(balance) > 0 {
+		return fmt.Errorf("Validator stake must be between %v and the balance", core.MinValidatorStakeDeposit)
+	}
+
+	balances := make(map[string]string)
+	deposits := []stakeDeposit{}
+	for _, n := range nodes {
+		addr := strings.TrimPrefix(n.Address.Hex(), "0x")
+		balances[addr] = balance.String()
+		if !n.Guardian {
+			deposits = append(deposits, stakeDeposit{Source: addr, Holder: addr, Amount: stake.String()})
+		}
+	}
+
+	dataDir := path.Join(p.outputDir, "data")
+	if err := os.MkdirAll(dataDir, 0700); err != nil {
+		return err
+	}
+	if err := writeJSON(path.Join(dataDir, "genesis_theta_erc20_snapshot.json"), balances); err != nil {
+		return err
+	}
+	return writeJSON(path.Join(dataDir, "genesis_stake_deposit.json"), deposits)
+}
+
+// generateGenesis invokes the generate_genesis tool, and returns the genesis block hash
+func generateGenesis(p *params) (string, error) {
+	cmd := exec.Command(p.genesisTool,
+		"-chainID="+p.chainID,
+		"-erc20snapshot="+path.Join(p.outputDir, "data", "genesis_theta_erc20_snapshot.json"),
+		"-stake_deposit="+path.Join(p.outputDir, "data", "genesis_stake_deposit.json"),
+		"-genesis="+path.Join(p.outputDir, "genesis"))
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("Failed to run %v: %v", p.genesisTool, err)
+	}
+
+	match := genesisHashRegexp.FindStringSubmatch(out.String())
+	if match == nil {
+		return "", fmt.Errorf("Genesis block hash not found in the output of %v", p.genesisTool)
+	}
+	return match[1], nil
+}
+
+// writeNodeConfig writes the config of the node, with all the other nodes as the seeds. The host function
+// returns the host name under which a node is reachable.
+func writeNodeConfig(filePath string, nodes []*node, n *node, genesisHash string, host func(*node) string) error {
+	seeds := []string{}
+	for _, peer := range nodes {
+		if peer != n {
+			seeds = append(seeds, fmt.Sprintf("%v:%v", host(peer), peer.P2PPort))
+		}
+	}
+
+	config := fmt.Sprintf(`# Theta configuration of %v, generated by init_privatenet
+genesis:
+  hash: "%v"
+p2p:
+  port: %v
+  seeds: %v
+rpc:
+  enabled: true
+  port: %v
+consensus:
+  minBlockInterval: 6
+  maxEpochLength: 20
+  maxEpochTimeout: 120
+  epochTimeoutBackoffFactor: 1.5
+  epochTimeoutBackoffThreshold: 3
+`, n.Name, genesisHash, n.P2PPort, strings.Join(seeds, ","), n.RPCPort)
+
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, []byte(config), 0600)
+}
+
+// writeDockerCompose writes the docker-compose.yml running each node in its own container. The key and
+// the snapshot of the node are mounted together with the config using the service names as the seeds.
+func writeDockerCompose(p *params, nodes []*node) error {
+	var sb strings.Builder
+	sb.WriteString("# Theta privatenet, generated by init_privatenet\n")
+	sb.WriteString("version: \"3\"\n")
+	sb.WriteString("services:\n")
+	for _, n := range nodes {
+		cfgDir := "/theta/" + n.Name
+		fmt.Fprintf(&sb, "  %v:\n", n.Name)
+		fmt.Fprintf(&sb, "    image: %v\n", p.image)
+		fmt.Fprintf(&sb, "    container_name: %v\n", n.Name)
+		fmt.Fprintf(&sb, "    command: theta start --config=%v --password=%v\n", cfgDir, p.password)
+		sb.WriteString("    volumes:\n")
+		fmt.Fprintf(&sb, "      - ./docker/%v.yaml:%v/config.yaml\n", n.Name, cfgDir)
+		fmt.Fprintf(&sb, "      - ./%v/key:%v/key\n", n.Name, cfgDir)
+		fmt.Fprintf(&sb, "      - ./%v/snapshot:%v/snapshot\n", n.Name, cfgDir)
+		sb.WriteString("    ports:\n")
+		fmt.Fprintf(&sb, "      - \"%v:%v\"\n", n.RPCPort, n.RPCPort)
+	}
+	return ioutil.WriteFile(path.Join(p.outputDir, "docker-compose.yml"), []byte(sb.String()), 0600)
+}
+
+// writeStartScript writes the script starting all the nodes on the local machine
+func writeStartScript(p *params, nodes []*node) error {
+	var sb strings.Builder
+	sb.WriteString("#!/bin/bash\n")
+	sb.WriteString("# Starts the Theta privatenet on the local machine, generated by init_privatenet\n")
+	sb.WriteString("cd \"$(dirname \"$0\")\"\n")
+	sb.WriteString("mkdir -p logs\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&sb, "theta start --config=%v --password=%v > logs/%v.log 2>&1 &\n", n.Name, p.password, n.Name)
+	}
+	sb.WriteString("wait\n")
+	return ioutil.WriteFile(path.Join(p.outputDir, "start.sh"), []byte(sb.String()), 0700)
+}
+
+// writeReadme writes the instructions to run the cluster and to stake for the guardians
+func writeReadme(p *params, nodes []*node, genesisHash string) error {
+	var sb strings.Builder
+	sb.WriteString("# Theta Privatenet\n\n")
+	fmt.Fprintf(&sb, "Chain ID: `%v`, genesis block hash: `%v`, key password: `%v`\n\n", p.chainID, genesisHash, p.password)
+	sb.WriteString("| Node | Address | P2P Port | RPC Port |\n")
+	sb.WriteString("|------|---------|----------|----------|\n")
+	for _, n := range nodes {
+		fmt.Fprintf(&sb, "| %v | %v | %v | %v |\n", n.Name, n.Address.Hex(), n.P2PPort, n.RPCPort)
+	}
+
+	sb.WriteString("\n## Run\n\n")
+	sb.WriteString("Locally, with the `theta` binary in the `PATH`:\n\n")
+	sb.WriteString("```\n./start.sh\n```\n\n")
+	fmt.Fprintf(&sb, "With docker, using the `%v` image:\n\n", p.image)
+	sb.WriteString("```\ndocker-compose up\n```\n\n")
+	sb.WriteString("The keys of all the nodes are also stored for `thetacli` under `./thetacli`, e.g.\n\n")
+	sb.WriteString("```\nthetacli query status --config=./thetacli\n```\n")
+
+	guardians := []*node{}
+	for _, n := range nodes {
+		if n.Guardian {
+			guardians = append(guardians, n)
+		}
+	}
+	if len(guardians) > 0 {
+		sb.WriteString("\n## Guardian Staking\n\n")
+		sb.WriteString("The guardian stakes cannot be deposited in the genesis. Once the cluster is running, get the summary ")
+		sb.WriteString("of each guardian from its RPC, and deposit the stake for it:\n\n")
+		for _, n := range guardians {
+			sb.WriteString("```\n")
+			fmt.Fprintf(&sb, "curl -X POST -H 'Content-Type: application/json' --data '{\"jsonrpc\":\"2.0\",\"method\":\"theta.GetGuardianInfo\",\"params\":[{}],\"id\":1}' http://localhost:%v/rpc\n", n.RPCPort)
+			fmt.Fprintf(&sb, "thetacli tx deposit --config=./thetacli --chain=%v --source=%v --holder=<guardian summary> --stake=%v --purpose=%v --seq=1 --password=%v\n",
+				p.chainID, n.Address.Hex(), new(big.Int).Div(core.MinGuardianStakeDeposit, big.NewInt(1e18)), core.StakeForGuardian, p.password)
+			sb.WriteString("```\n\n")
+		}
+	}
+	return ioutil.WriteFile(path.Join(p.outputDir, "README.md"), []byte(sb.String()), 0600)
+}
+
+// writeCLIConfig writes the thetacli config pointing to the RPC of the first node
+func writeCLIConfig(p *params, nodes []*node) error {
+	config := fmt.Sprintf("remoteRPCEndpoint: http://localhost:%v/rpc\n", nodes[0].RPCPort)
+	return ioutil.WriteFile(path.Join(p.outputDir, "thetacli", "config.yaml"), []byte(config), 0600)
+}
+
+func writeJSON(filePath string, obj interface{}) error {
+	data, err := json.MarshalIndent(obj, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filePath, data, 0600)
+}
+
+func copyFile(src, dst string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, data, 0600)
+}
+
+func handleError(err error) {
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: init_privatenet -validators=<num_validators> -guardians=<num_guardians> -output=<output_dir> [-chainID=<chain_id>] [-password=<password>] [-genesis_tool=<path/to/generate_genesis>]")
+}
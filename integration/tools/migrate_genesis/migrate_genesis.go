@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/snapshot"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "migrate_genesis"})
+
+// Migration declares the transformations applied to the genesis state, in the order of the fields
+type Migration struct {
+	ChainID            string              `json:"chain_id"`
+	BalanceAdjustments []BalanceAdjustment `json:"balance_adjustments"`
+	Validators         []StakeDeposit      `json:"validators"`
+	ResetSequences     bool                `json:"reset_sequences"`
+}
+
+// BalanceAdjustment sets the balance of an account if Set is true, otherwise adds the signed
+// amounts to the balance. The account is created if it does not exist.
+type BalanceAdjustment struct {
+	Address  string `json:"address"`
+	ThetaWei string `json:"theta_wei"`
+	TFuelWei string `json:"tfuel_wei"`
+	Set      bool   `json:"set"`
+}
+
+// StakeDeposit deposits the stake from the source account to the validator candidate pool
+type StakeDeposit struct {
+	Source string `json:"source"`
+	Holder string `json:"holder"`
+	Amount string `json:"amount"`
+}
+
+//
+// Example:
+// theta dump state --config=./mainnet/node --height=1000 --chain_id=forknet --output=./mainnet_state
+// migrate_genesis -genesis=./mainnet_state -migration=./migration.json -output=./genesis
+//
+// migration.json:
+// {
+//   "chain_id": "forknet",
+//   "balance_adjustments": [{"address": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "tfuel_wei": "1000000000000000000000"}],
+//   "validators": [{"source": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "holder": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", "amount": "10000000000000000000000000"}],
+//   "reset_sequences": true
+// }
+//
+func main() {
+	genesisFilePathPtr := flag.String("genesis", "./genesis", "the genesis snapshot to migrate, e.g. exported by theta dump state")
+	migrationFilePathPtr := flag.String("migration", "./migration.json", "the transformations to apply")
+	outputFilePathPtr := flag.String("output", "./genesis_migrated", "the migrated genesis snapshot")
+	flag.Parse()
+
+	migration := &Migration{}
+	migrationByteValue, err := ioutil.ReadFile(*migrationFilePathPtr)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to read migration file: %v", err))
+	}
+	if err := json.Unmarshal(migrationByteValue, migration); err != nil {
+		panic(fmt.Sprintf("Failed to parse migration file: %v", err))
+	}
+
+	db := backend.NewMemDatabase()
+	metadata, sv, err := snapshot.LoadGenesisSnapshot(*genesisFilePathPtr, db)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load genesis snapshot: %v", err))
+	}
+
+	genesisBlockHeader := *metadata.TailTrio.Second.Header
+	if migration.ChainID != "" {
+		logger.Infof("Chain ID: %v -> %v", genesisBlockHeader.ChainID, migration.ChainID)
+		genesisBlockHeader.ChainID = migration.ChainID
+	}
+	adjustBalances(migration.BalanceAdjustments, sv)
+	depositStakes(migration.Validators, genesisBlockHeader.Height, sv)
+	if migration.ResetSequences {
+		resetSequences(sv)
+	}
+
+	genesisBlockHeader.StateHash = sv.Save()
+	metadata.TailTrio.Second.Header = &genesisBlockHeader
+
+	file, err := os.Create(*outputFilePathPtr)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create migrated genesis snapshot: %v", err))
+	}
+	defer file.Close()
+	if err := snapshot.WriteGenesisSnapshot(bufio.NewWriter(file), metadata, sv, db); err != nil {
+		panic(fmt.Sprintf("Failed to write migrated genesis snapshot: %v", err))
+	}
+
+	fmt.Println("")
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Printf("Genesis block hash: %v\n", genesisBlockHeader.Hash().Hex())
+	fmt.Printf("--------------------------------------------------------------------------\n")
+	fmt.Println("")
+}
+
+func parseAddress(address string) common.Address {
+	if !common.IsHexAddress(address) {
+		panic(fmt.Sprintf("Invalid address: %v", address))
+	}
+	return common.HexToAddress(address)
+}
+
+// parseAmount parses a signed decimal amount, an empty amount is zero
+func parseAmount(amount string) *big.Int {
+	if amount == "" {
+		return big.NewInt(0)
+	}
+	value, success := new(big.Int).SetString(amount, 10)
+	if !success {
+		panic(fmt.Sprintf("Failed to parse amount: %v", amount))
+	}
+	return value
+}
+
+func adjustBalances(adjustments []BalanceAdjustment, sv *state.StoreView) {
+	for _, adjustment := range adjustments {
+		address := parseAddress(adjustment.Address)
+		account := sv.GetAccount(address)
+		if account == nil {
+			account = &types.Account{
+				Address:  address,
+				Root:     common.Hash{},
+				CodeHash: types.EmptyCodeHash,
+				Balance:  types.NewCoins(0, 0),
+			}
+		}
+
+		coins := types.Coins{
+			ThetaWei: parseAmount(adjustment.ThetaWei),
+			TFuelWei: parseAmount(adjustment.TFuelWei),
+		}
+		before := account.Balance
+		if adjustment.Set {
+			account.Balance = coins
+		} else {
+			account.Balance = account.Balance.Plus(coins)
+		}
+		if !account.Balance.IsNonnegative() {
+			panic(fmt.Sprintf("The balance of %v would become negative: %v", address.Hex(), account.Balance))
+		}
+		sv.SetAccount(address, account)
+		logger.Infof("Balance: %v, %v -> %v", address.Hex(), before, account.Balance)
+	}
+}
+
+func depositStakes(stakeDeposits []StakeDeposit, genesisHeight uint64, sv *state.StoreView) {
+	if len(stakeDeposits) == 0 {
+		return
+	}
+
+	vcp := sv.GetValidatorCandidatePool()
+	if vcp == nil {
+		vcp = &core.ValidatorCandidatePool{}
+	}
+	for _, stakeDeposit := range stakeDeposits {
+		sourceAddress := parseAddress(stakeDeposit.Source)
+		holderAddress := parseAddress(stakeDeposit.Holder)
+		stakeAmount := parseAmount(stakeDeposit.Amount)
+		if stakeAmount.Sign() <= 0 {
+			panic(fmt.Sprintf("Invalid stake amount: %v", stakeDeposit.Amount))
+		}
+
+		sourceAccount := sv.GetAccount(sourceAddress)
+		if sourceAccount == nil {
+			panic(fmt.Sprintf("Failed to retrieve account for source address: %v", sourceAddress.Hex()))
+		}
+		if sourceAccount.Balance.ThetaWei.Cmp(stakeAmount) < 0 {
+			panic(fmt.Sprintf("The source account %v does NOT have sufficient balance for stake deposit. ThetaWeiBalance = %v, StakeAmount = %v",
+				sourceAddress.Hex(), sourceAccount.Balance.ThetaWei, stakeDeposit.Amount))
+		}
+		if err := vcp.DepositStake(sourceAddress, holderAddress, stakeAmount); err != nil {
+			panic(fmt.Sprintf("Failed to deposit stake, err: %v", err))
+		}
+
+		stake := types.Coins{
+			ThetaWei: stakeAmount,
+			TFuelWei: new(big.Int).SetUint64(0),
+		}
+		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
+		sv.SetAccount(sourceAddress, sourceAccount)
+		logger.Infof("Validator: %v, source = %v, stake = %v", holderAddress.Hex(), sourceAddress.Hex(), stakeAmount)
+	}
+	sv.UpdateValidatorCandidatePool(vcp)
+
+	hl := sv.GetStakeTransactionHeightList()
+	if hl == nil {
+		hl = &types.HeightList{}
+	}
+	if !hl.Contains(genesisHeight) {
+		hl.Append(genesisHeight)
+		sv.UpdateStakeTransactionHeightList(hl)
+	}
+}
+
+func resetSequences(sv *state.StoreView) {
+	accounts := []*types.Account{}
+	sv.GetStore().Traverse(common.Bytes("ls/a/"), func(key, val common.Bytes) bool {
+		account := &types.Account{}
+		if err := types.FromBytes(val, account); err != nil {
+			panic(fmt.Sprintf("Failed to parse account for key %v: %v", key, err))
+		}
+		if account.Sequence != 0 {
+			accounts = append(accounts, account)
+		}
+		return true
+	})
+	for _, account := range accounts {
+		account.Sequence = 0
+		sv.SetAccount(account.Address, account)
+	}
+	logger.Infof("Reset the sequences of %v accounts", len(accounts))
+}
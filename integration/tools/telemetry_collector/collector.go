@@ -0,0 +1,141 @@
+// The telemetry collector is a reference implementation of the endpoint the nodes report their
+// telemetry to, when telemetry.enabled is set. It keeps the latest report of each node in memory,
+// and forgets the nodes that have not reported for the expiry duration.
+//
+// It serves:
+//
+//	POST /report   the node reports, see telemetry.Report
+//	GET  /nodes    the latest reports of the live nodes, sorted by node ID
+//	GET  /summary  the number of nodes, the max height, and the node counts by version and region
+//
+// Usage: telemetry_collector -address=0.0.0.0:8899 -expiry=5m
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/thetatoken/theta/common/util"
+	"github.com/thetatoken/theta/telemetry"
+)
+
+var logger *log.Entry = util.GetLoggerForModule("telemetry_collector")
+
+const maxReportBytes = 16 * 1024
+
+type nodeReport struct {
+	*telemetry.Report
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+type summary struct {
+	NumNodes  int            `json:"num_nodes"`
+	MaxHeight uint64         `json:"max_height"`
+	NumPeers  int            `json:"num_peers"` // the sum of the peer counts
+	Versions  map[string]int `json:"versions"`
+	Regions   map[string]int `json:"regions"`
+}
+
+type collector struct {
+	mu     *sync.Mutex
+	nodes  map[string]*nodeReport
+	expiry time.Duration
+}
+
+func (c *collector) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	report := &telemetry.Report{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxReportBytes)).Decode(report); err != nil {
+		http.Error(w, "Invalid report", http.StatusBadRequest)
+		return
+	}
+	if report.NodeID == "" {
+		http.Error(w, "Missing node ID", http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodes[report.NodeID] = &nodeReport{Report: report, ReceivedAt: time.Now()}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// liveNodes returns the latest reports of the nodes that have reported within the expiry
+// duration, and forgets the others
+func (c *collector) liveNodes() []*nodeReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nodes := []*nodeReport{}
+	for id, node := range c.nodes {
+		if time.Since(node.ReceivedAt) > c.expiry {
+			delete(c.nodes, id)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].NodeID < nodes[j].NodeID })
+	return nodes
+}
+
+func (c *collector) handleNodes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, c.liveNodes())
+}
+
+func (c *collector) handleSummary(w http.ResponseWriter, r *http.Request) {
+	s := &summary{
+		Versions: make(map[string]int),
+		Regions:  make(map[string]int),
+	}
+	for _, node := range c.liveNodes() {
+		s.NumNodes++
+		s.NumPeers += node.NumPeers
+		if uint64(node.Height) > s.MaxHeight {
+			s.MaxHeight = uint64(node.Height)
+		}
+		s.Versions[node.Version]++
+		region := node.Region
+		if region == "" {
+			region = "unknown"
+		}
+		s.Regions[region]++
+	}
+	writeJSON(w, s)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warnf("Failed to write the response: %v", err)
+	}
+}
+
+func main() {
+	addressPtr := flag.String("address", "0.0.0.0:8899", "address to listen on")
+	expiryPtr := flag.Duration("expiry", 5*time.Minute, "duration after which a node that stopped reporting is forgotten")
+
+	flag.Parse()
+
+	c := &collector{
+		mu:     &sync.Mutex{},
+		nodes:  make(map[string]*nodeReport),
+		expiry: *expiryPtr,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/report", c.handleReport)
+	mux.HandleFunc("/nodes", c.handleNodes)
+	mux.HandleFunc("/summary", c.handleSummary)
+
+	logger.Infof("Telemetry collector listening on %v", *addressPtr)
+	logger.Fatal(http.ListenAndServe(*addressPtr, mux))
+}
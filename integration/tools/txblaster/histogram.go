@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The upper bounds of the histogram buckets, the last bucket is unbounded
+var latencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	60 * time.Second,
+}
+
+// histogram records the latencies, and reports them in buckets along with the percentiles
+type histogram struct {
+	mu        sync.Mutex
+	name      string
+	latencies []time.Duration
+}
+
+func newHistogram(name string) *histogram {
+	return &histogram{name: name}
+}
+
+func (h *histogram) record(latency time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latencies = append(h.latencies, latency)
+}
+
+func (h *histogram) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.latencies)
+}
+
+func (h *histogram) print(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "%v latency (%v samples)\n", h.name, len(h.latencies))
+	if len(h.latencies) == 0 {
+		return
+	}
+
+	sorted := make([]time.Duration, len(h.latencies))
+	copy(sorted, h.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	counts := make([]int, len(latencyBuckets)+1)
+	for _, latency := range sorted {
+		idx := sort.Search(len(latencyBuckets), func(i int) bool { return latency <= latencyBuckets[i] })
+		counts[idx]++
+	}
+	for idx, count := range counts {
+		label := fmt.Sprintf("> %v", latencyBuckets[len(latencyBuckets)-1])
+		if idx < len(latencyBuckets) {
+			label = fmt.Sprintf("<= %v", latencyBuckets[idx])
+		}
+		fmt.Fprintf(w, "  %-10v %8d  %5.1f%%\n", label, count, 100*float64(count)/float64(len(sorted)))
+	}
+
+	percentile := func(p int) time.Duration {
+		idx := (len(sorted)*p+99)/100 - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx]
+	}
+	fmt.Fprintf(w, "  p50: %v, p90: %v, p99: %v, max: %v\n", percentile(50), percentile(90), percentile(99), sorted[len(sorted)-1])
+}
@@ -0,0 +1,405 @@
+// The txblaster generates and broadcasts a configurable mix of transactions at a target TPS, e.g.
+// against a privatenet, and reports the acceptance latency, i.e. the time for the mempool of the node
+// to accept a transaction, and the finalization latency of the transactions.
+//
+// Usage: txblaster -chain=privatenet -keys=<path_to_key_file> -tps=100 -duration=1m -mix=send=80,contract=15,stake=5
+//
+// The key file contains a hex encoded private key per line. Each account sends its transactions in
+// sequence, so the target TPS may require multiple accounts.
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc"
+	"github.com/thetatoken/theta/sdk"
+)
+
+const (
+	txKindSend     = "send"
+	txKindContract = "contract"
+	txKindStake    = "stake"
+)
+
+type txMix struct {
+	kinds   []string
+	weights []int
+	total   int
+}
+
+// parseMix parses the weights of the transaction kinds, e.g. "send=80,contract=15,stake=5"
+func parseMix(s string) (*txMix, error) {
+	mix := &txMix{}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid mix entry: %v", entry)
+		}
+		kind := parts[0]
+		if kind != txKindSend && kind != txKindContract && kind != txKindStake {
+			return nil, fmt.Errorf("Unknown transaction kind: %v", kind)
+		}
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("Invalid weight of %v: %v", kind, parts[1])
+		}
+		mix.kinds = append(mix.kinds, kind)
+		mix.weights = append(mix.weights, weight)
+		mix.total += weight
+	}
+	if mix.total == 0 {
+		return nil, fmt.Errorf("Total weight of the mix must be positive")
+	}
+	return mix, nil
+}
+
+func (mix *txMix) pick(r *rand.Rand) string {
+	n := r.Intn(mix.total)
+	for idx, weight := range mix.weights {
+		if n < weight {
+			return mix.kinds[idx]
+		}
+		n -= weight
+	}
+	return mix.kinds[len(mix.kinds)-1]
+}
+
+type config struct {
+	chainID      string
+	mix          *txMix
+	sendAmount   *big.Int
+	contract     common.Address
+	contractData []byte
+	gasLimit     uint64
+	stakeHolder  common.Address
+	stakeAmount  *big.Int
+	stakePurpose uint8
+}
+
+type sender struct {
+	signer    *sdk.PrivateKeySigner
+	address   common.Address
+	recipient common.Address
+	sequence  uint64
+	staked    bool
+	ticks     chan struct{}
+	rand      *rand.Rand
+}
+
+type blaster struct {
+	client      *sdk.Client
+	cfg         *config
+	blockHeight uint64
+
+	sent       uint64
+	rejected   uint64
+	skipped    uint64
+	abandoned  uint64
+	acceptance *histogram
+	finality   *histogram
+
+	mu      sync.Mutex
+	pending map[string]time.Time // tx hash -> time sent
+}
+
+func (b *blaster) buildTx(s *sender, kind string) types.Tx {
+	switch kind {
+	case txKindContract:
+		gasPrice := sdk.MinimumGasPrice(b.blockHeight)
+		return sdk.NewSmartContractTx(s.address, b.cfg.contract, types.NewCoins(0, 0), b.cfg.gasLimit, gasPrice, b.cfg.contractData, s.sequence)
+	case txKindStake:
+		fee := sdk.MinimumFee(b.blockHeight)
+		if s.staked {
+			return sdk.NewWithdrawStakeTx(s.address, b.cfg.stakeHolder, b.cfg.stakePurpose, fee, s.sequence)
+		}
+		return sdk.NewDepositStakeTx(s.address, b.cfg.stakeHolder, b.cfg.stakeAmount, b.cfg.stakePurpose, fee, s.sequence)
+	default:
+		fee := sdk.MinimumSendTxFee(1, 1, b.blockHeight)
+		coins := types.Coins{ThetaWei: big.NewInt(0), TFuelWei: b.cfg.sendAmount}
+		return sdk.NewSendTx(s.address, s.recipient, coins, fee, s.sequence)
+	}
+}
+
+// run sends a transaction of the sender for each tick
+func (b *blaster) run(s *sender, wg *sync.WaitGroup) {
+	defer wg.Done()
+	for range s.ticks {
+		kind := b.cfg.mix.pick(s.rand)
+		tx := b.buildTx(s, kind)
+		if err := sdk.SignTx(b.cfg.chainID, tx, s.signer); err != nil {
+			fmt.Printf("Failed to sign %v transaction: %v\n", kind, err)
+			continue
+		}
+
+		start := time.Now()
+		result, err := b.client.BroadcastTx(tx, true)
+		atomic.AddUint64(&b.sent, 1)
+		if err != nil {
+			atomic.AddUint64(&b.rejected, 1)
+			fmt.Printf("Failed to broadcast %v transaction from %v: %v\n", kind, s.address.Hex(), err)
+			if seq, err := b.client.GetNextSequence(s.address); err == nil {
+				s.sequence = seq
+			}
+			continue
+		}
+		b.acceptance.record(time.Since(start))
+
+		b.mu.Lock()
+		b.pending[result.TxHash] = start
+		b.mu.Unlock()
+
+		s.sequence++
+		if kind == txKindStake {
+			s.staked = !s.staked
+		}
+	}
+}
+
+// trackFinality polls the status of the pending transactions until done is closed
+func (b *blaster) trackFinality(interval time.Duration, done chan struct{}, stopped chan struct{}) {
+	defer close(stopped)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		b.mu.Lock()
+		hashes := make([]string, 0, len(b.pending))
+		for hash := range b.pending {
+			hashes = append(hashes, hash)
+		}
+		b.mu.Unlock()
+
+		for _, hash := range hashes {
+			status, err := b.client.GetTxStatus(hash)
+			if err != nil {
+				continue
+			}
+			if status.Status != rpc.TxStatusFinalized && status.Status != rpc.TxStatusAbandoned {
+				continue
+			}
+
+			b.mu.Lock()
+			start := b.pending[hash]
+			delete(b.pending, hash)
+			b.mu.Unlock()
+
+			if status.Status == rpc.TxStatusFinalized {
+				b.finality.record(time.Since(start))
+			} else {
+				atomic.AddUint64(&b.abandoned, 1)
+			}
+		}
+	}
+}
+
+func (b *blaster) numPending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending)
+}
+
+func loadSigners(path string) ([]*sdk.PrivateKeySigner, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var signers []*sdk.PrivateKeySigner
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "0x")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		skBytes, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid private key: %v", err)
+		}
+		privKey, err := crypto.PrivateKeyFromBytes(skBytes)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, sdk.NewPrivateKeySigner(privKey))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("No private key found in %v", path)
+	}
+	return signers, nil
+}
+
+func parseBig(s string) *big.Int {
+	value, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		fmt.Printf("Invalid amount: %v\n", s)
+		os.Exit(1)
+	}
+	return value
+}
+
+func main() {
+	rpcPtr := flag.String("rpc", "http://localhost:16888/rpc", "RPC endpoint of the Theta node")
+	chainPtr := flag.String("chain", "privatenet", "chain ID")
+	keysPtr := flag.String("keys", "", "path to the file with the hex encoded private keys of the sending accounts, one per line")
+	tpsPtr := flag.Float64("tps", 10, "target transactions per second")
+	durationPtr := flag.Duration("duration", time.Minute, "duration of the test")
+	mixPtr := flag.String("mix", "send=100", "weights of the transaction kinds, e.g. send=80,contract=15,stake=5")
+	sendAmountPtr := flag.String("send_amount", "1", "TFuelWei sent by each SendTx")
+	contractPtr := flag.String("contract", "", "address of the contract called by the smart contract transactions")
+	dataPtr := flag.String("data", "", "hex encoded call data of the smart contract transactions")
+	gasLimitPtr := flag.Uint64("gas_limit", 100000, "gas limit of the smart contract transactions")
+	holderPtr := flag.String("stake_holder", "", "address of the stake holder, e.g. a guardian node, for the stake transactions")
+	stakeAmountPtr := flag.String("stake_amount", core.MinGuardianStakeDeposit.String(), "ThetaWei deposited by each deposit stake transaction")
+	purposePtr := flag.Uint("stake_purpose", uint(core.StakeForGuardian), "purpose of the stake transactions")
+	pollPtr := flag.Duration("poll_interval", 500*time.Millisecond, "interval to poll the status of the pending transactions")
+	finalizeTimeoutPtr := flag.Duration("finalize_timeout", time.Minute, "time to wait for the pending transactions after the test")
+
+	flag.Parse()
+
+	if *keysPtr == "" || *tpsPtr <= 0 {
+		fmt.Println("Usage: txblaster -chain=<chain_id> -keys=<path_to_key_file> -tps=<target_tps> -duration=<duration> -mix=<mix>")
+		os.Exit(1)
+	}
+
+	mix, err := parseMix(*mixPtr)
+	handleError(err)
+	cfg := &config{
+		chainID:      *chainPtr,
+		mix:          mix,
+		sendAmount:   parseBig(*sendAmountPtr),
+		gasLimit:     *gasLimitPtr,
+		stakeAmount:  parseBig(*stakeAmountPtr),
+		stakePurpose: uint8(*purposePtr),
+	}
+	for idx, kind := range mix.kinds {
+		if mix.weights[idx] == 0 {
+			continue
+		}
+		if kind == txKindContract {
+			if *contractPtr == "" {
+				handleError(fmt.Errorf("The contract address is required for the smart contract transactions"))
+			}
+			cfg.contract = common.HexToAddress(*contractPtr)
+			cfg.contractData, err = hex.DecodeString(strings.TrimPrefix(*dataPtr, "0x"))
+			handleError(err)
+		}
+		if kind == txKindStake {
+			if *holderPtr == "" {
+				handleError(fmt.Errorf("The stake holder is required for the stake transactions"))
+			}
+			cfg.stakeHolder = common.HexToAddress(*holderPtr)
+		}
+	}
+
+	signers, err := loadSigners(*keysPtr)
+	handleError(err)
+
+	client := sdk.NewClient(*rpcPtr)
+	status, err := client.GetStatus()
+	handleError(err)
+
+	b := &blaster{
+		client:      client,
+		cfg:         cfg,
+		blockHeight: uint64(status.LatestFinalizedBlockHeight),
+		acceptance:  newHistogram("Acceptance"),
+		finality:    newHistogram("Finalization"),
+		pending:     make(map[string]time.Time),
+	}
+
+	senders := make([]*sender, len(signers))
+	for idx, signer := range signers {
+		seq, err := client.GetNextSequence(signer.Address())
+		handleError(err)
+		senders[idx] = &sender{
+			signer:    signer,
+			address:   signer.Address(),
+			recipient: signers[(idx+1)%len(signers)].Address(),
+			sequence:  seq,
+			ticks:     make(chan struct{}, 1),
+			rand:      rand.New(rand.NewSource(time.Now().UnixNano() + int64(idx))),
+		}
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, s := range senders {
+		wg.Add(1)
+		go b.run(s, wg)
+	}
+	trackerDone := make(chan struct{})
+	trackerStopped := make(chan struct{})
+	go b.trackFinality(*pollPtr, trackerDone, trackerStopped)
+
+	fmt.Printf("Sending transactions at %v TPS for %v from %v accounts\n", *tpsPtr, *durationPtr, len(senders))
+	start := time.Now()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *tpsPtr))
+	deadline := time.After(*durationPtr)
+	next := 0
+loop:
+	for {
+		select {
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			select {
+			case senders[next].ticks <- struct{}{}:
+			default:
+				atomic.AddUint64(&b.skipped, 1) // the sender has not finished the previous transaction
+			}
+			next = (next + 1) % len(senders)
+		}
+	}
+	ticker.Stop()
+	for _, s := range senders {
+		close(s.ticks)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	fmt.Printf("Waiting for %v pending transactions to be finalized\n", b.numPending())
+	waitDeadline := time.Now().Add(*finalizeTimeoutPtr)
+	for b.numPending() > 0 && time.Now().Before(waitDeadline) {
+		time.Sleep(*pollPtr)
+	}
+	close(trackerDone)
+	<-trackerStopped
+
+	fmt.Println()
+	fmt.Printf("Sent: %v, rejected: %v, skipped ticks: %v, finalized: %v, abandoned: %v, still pending: %v\n",
+		b.sent, b.rejected, b.skipped, b.finality.count(), b.abandoned, b.numPending())
+	fmt.Printf("Achieved TPS: %.2f (target %v)\n", float64(b.sent-b.rejected)/elapsed.Seconds(), *tpsPtr)
+	fmt.Println()
+	b.acceptance.print(os.Stdout)
+	fmt.Println()
+	b.finality.print(os.Stdout)
+}
+
+func handleError(err error) {
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
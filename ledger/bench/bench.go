@@ -0,0 +1,365 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"path"
+	"time"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	exec "github.com/thetatoken/theta/ledger/execution"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store/database"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+// Database backends of the benchmarks
+const (
+	BackendMemory  = "memory"
+	BackendLevelDB = "leveldb"
+)
+
+// Workloads of the benchmarks
+const (
+	WorkloadSend     = "send"     // blocks of NumSendTxsPerBlock send txs between distinct accounts
+	WorkloadContract = "contract" // blocks of NumContractCallsPerBlock calls of a contract incrementing a counter
+	WorkloadStake    = "stake"    // blocks of NumStakeTxsPerBlock validator stake deposits and withdrawals
+)
+
+const (
+	NumSendTxsPerBlock       = 1000
+	NumContractCallsPerBlock = 100
+	NumStakeTxsPerBlock      = 100
+)
+
+// The benchmarks run at the latest scheduled fork so all the tx types are enabled
+const benchStartHeight = common.HeightSupportThetaTokenInSmartContract
+
+const benchChainID = "bench_chain"
+
+// ASM:
+// push 0xa
+// push 0xc
+// push 0x0
+// codecopy
+// push 0xa
+// push 0x0
+// return
+var counterDeploymentCode, _ = hex.DecodeString("600a600c600039600a6000f3" + "60005460010160005500")
+
+// ASM:
+// push 0x0
+// sload
+// push 0x1
+// add
+// push 0x0
+// sstore
+// stop
+var counterCode, _ = hex.DecodeString("60005460010160005500")
+
+type benchAccount struct {
+	types.PrivAccount
+	sequence uint64
+}
+
+//
+// Harness generates the blocks of a workload with deterministic accounts and applies them to the
+// ledger state, the same way the ledger applies the transactions of a block: the signatures are
+// verified upfront, and then the transactions are executed one by one on the delivered view
+// before the state is committed.
+//
+type Harness struct {
+	db       database.Database
+	state    *st.LedgerState
+	executor *exec.Executor
+
+	accounts    []*benchAccount
+	numAccounts int // number of the accounts created so far, also used as the seed of the next one
+	contract    common.Address
+	validators  []common.Address
+	stakers     []*benchAccount // the stakers of the last stake block, to withdraw in the next one
+}
+
+// NewHarness creates a Harness on the given database backend. The LevelDB database is created in
+// the given directory.
+func NewHarness(backendName string, dir string) (*Harness, error) {
+	var db database.Database
+	switch backendName {
+	case BackendMemory:
+		db = backend.NewMemDatabase()
+	case BackendLevelDB:
+		ldb, err := backend.NewLDBDatabase(path.Join(dir, "main"), path.Join(dir, "ref"), 256, 16)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create the LevelDB database in %v: %v", dir, err)
+		}
+		db = ldb
+	default:
+		return nil, fmt.Errorf("Unknown database backend: %v", backendName)
+	}
+
+	state := st.NewLedgerState(benchChainID, db, nil)
+	state.ResetState(&core.Block{
+		BlockHeader: &core.BlockHeader{
+			ChainID:   benchChainID,
+			Height:    benchStartHeight,
+			Timestamp: big.NewInt(1601599331),
+		},
+	})
+
+	consensus := exec.NewTestConsensusEngine("bench_proposer")
+	proposer := core.NewValidator(consensus.PrivateKey().PublicKey().Address().String(), new(big.Int).SetUint64(999))
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(proposer)
+	valMgr := exec.NewTestValidatorManager(proposer, valSet)
+
+	h := &Harness{
+		db:       db,
+		state:    state,
+		executor: exec.NewExecutor(db, blockchain.CreateTestChain(), state, consensus, valMgr),
+	}
+	for i := 0; i < 4; i++ {
+		h.validators = append(h.validators, types.PrivAccountFromSecret(fmt.Sprintf("bench_validator_%v", i)).Address)
+	}
+	h.accounts = h.newAccounts(2 * NumSendTxsPerBlock)
+	return h, nil
+}
+
+// Close closes the database of the harness
+func (h *Harness) Close() {
+	h.db.Close()
+}
+
+// newAccounts creates and funds the given number of accounts
+func (h *Harness) newAccounts(num int) []*benchAccount {
+	balance := types.Coins{
+		ThetaWei: new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18)),
+		TFuelWei: new(big.Int).Mul(big.NewInt(1e9), big.NewInt(1e18)),
+	}
+	view := h.state.Delivered()
+	accounts := make([]*benchAccount, num)
+	for i := range accounts {
+		acc := types.MakeAccWithInitBalance(fmt.Sprintf("bench_account_%v", h.numAccounts), balance)
+		h.numAccounts++
+		view.SetAccount(acc.Address, &acc.Account)
+		accounts[i] = &benchAccount{PrivAccount: acc}
+	}
+	h.state.Commit()
+	return accounts
+}
+
+func (h *Harness) blockHeight() uint64 {
+	return h.state.Height() + 1
+}
+
+// GenerateBlock generates the transactions of a block of the given workload. The block must be
+// applied before generating the next one.
+func (h *Harness) GenerateBlock(workload string) ([]common.Bytes, error) {
+	var txs []types.Tx
+	switch workload {
+	case WorkloadSend:
+		txs = h.generateSendTxs()
+	case WorkloadContract:
+		if h.contract.IsEmpty() {
+			err := h.deployContract()
+			if err != nil {
+				return nil, err
+			}
+		}
+		txs = h.generateContractCalls()
+	case WorkloadStake:
+		txs = h.generateStakeTxs()
+	default:
+		return nil, fmt.Errorf("Unknown workload: %v", workload)
+	}
+
+	rawTxs := make([]common.Bytes, len(txs))
+	for i, tx := range txs {
+		rawTx, err := types.TxToBytes(tx)
+		if err != nil {
+			return nil, err
+		}
+		rawTxs[i] = rawTx
+	}
+	return rawTxs, nil
+}
+
+func (h *Harness) generateSendTxs() []types.Tx {
+	fee := types.GetSendTxMinimumTransactionFeeTFuelWei(2, h.blockHeight())
+	txs := []types.Tx{}
+	for i := 0; i < NumSendTxsPerBlock; i++ {
+		from := h.accounts[i]
+		to := h.accounts[NumSendTxsPerBlock+i]
+		from.sequence++
+		tx := &types.SendTx{
+			Fee: types.Coins{ThetaWei: big.NewInt(0), TFuelWei: fee},
+			Inputs: []types.TxInput{{
+				Address:  from.Address,
+				Coins:    types.Coins{ThetaWei: big.NewInt(1), TFuelWei: new(big.Int).Add(fee, big.NewInt(1))},
+				Sequence: from.sequence,
+			}},
+			Outputs: []types.TxOutput{{
+				Address: to.Address,
+				Coins:   types.NewCoins(1, 1),
+			}},
+		}
+		types.SignSendTx(benchChainID, tx, from.PrivAccount)
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+func (h *Harness) newContractTx(from *benchAccount, to common.Address, gasLimit uint64, data common.Bytes) *types.SmartContractTx {
+	from.sequence++
+	tx := &types.SmartContractTx{
+		From: types.TxInput{
+			Address:  from.Address,
+			Coins:    types.NewCoins(0, 0),
+			Sequence: from.sequence,
+		},
+		To:       types.TxOutput{Address: to},
+		GasLimit: gasLimit,
+		GasPrice: types.GetMinimumGasPrice(h.blockHeight()),
+		Data:     data,
+	}
+	tx.From.Signature = from.Sign(tx.SignBytes(benchChainID))
+	return tx
+}
+
+// deployContract deploys the counter contract the contract workload calls
+func (h *Harness) deployContract() error {
+	tx := h.newContractTx(h.accounts[0], common.Address{}, 1000000, counterDeploymentCode)
+	_, res := h.executor.ExecuteTx(tx)
+	if res.IsError() {
+		return fmt.Errorf("Failed to deploy the contract: %v", res.Message)
+	}
+	receipts := h.executor.PopTxReceipts()
+	h.executor.PopInternalTxs()
+	if len(receipts) != 1 || receipts[0].EvmErr != "" {
+		return fmt.Errorf("Failed to deploy the contract: %v", receipts)
+	}
+	h.contract = receipts[0].ContractAddress
+	h.state.Commit()
+	if !bytes.Equal(counterCode, h.state.Delivered().GetCode(h.contract)) {
+		return fmt.Errorf("Unexpected code of the deployed contract %v", h.contract.Hex())
+	}
+	return nil
+}
+
+func (h *Harness) generateContractCalls() []types.Tx {
+	txs := []types.Tx{}
+	for i := 0; i < NumContractCallsPerBlock; i++ {
+		txs = append(txs, h.newContractTx(h.accounts[i], h.contract, 100000, nil))
+	}
+	return txs
+}
+
+// generateStakeTxs generates the stake deposits of new stakers, and the withdrawals of the stakes
+// deposited in the previous block
+func (h *Harness) generateStakeTxs() []types.Tx {
+	fee := types.Coins{ThetaWei: big.NewInt(0), TFuelWei: types.GetMinimumTransactionFeeTFuelWei(h.blockHeight())}
+	txs := []types.Tx{}
+	for i, staker := range h.stakers {
+		staker.sequence++
+		tx := &types.WithdrawStakeTx{
+			Fee:     fee,
+			Source:  types.TxInput{Address: staker.Address, Coins: types.NewCoins(0, 0), Sequence: staker.sequence},
+			Holder:  types.TxOutput{Address: h.validators[i%len(h.validators)]},
+			Purpose: core.StakeForValidator,
+		}
+		tx.Source.Signature = staker.Sign(tx.SignBytes(benchChainID))
+		txs = append(txs, tx)
+	}
+
+	h.stakers = h.newAccounts(NumStakeTxsPerBlock - len(txs))
+	for i, staker := range h.stakers {
+		staker.sequence++
+		tx := &types.DepositStakeTx{
+			Fee: fee,
+			Source: types.TxInput{
+				Address:  staker.Address,
+				Coins:    types.Coins{ThetaWei: core.MinValidatorStakeDeposit, TFuelWei: big.NewInt(0)},
+				Sequence: staker.sequence,
+			},
+			Holder:  types.TxOutput{Address: h.validators[i%len(h.validators)]},
+			Purpose: core.StakeForValidator,
+		}
+		tx.Source.Signature = staker.Sign(tx.SignBytes(benchChainID))
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// ApplyBlock applies the transactions of a block, and commits the state
+func (h *Harness) ApplyBlock(rawTxs []common.Bytes) error {
+	h.executor.PreverifySignatures(rawTxs)
+	for _, rawTx := range rawTxs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			return err
+		}
+		_, res := h.executor.ExecuteTx(tx)
+		if res.IsError() {
+			return fmt.Errorf("Failed to execute tx %v: %v", tx, res.Message)
+		}
+	}
+	h.executor.PopTxReceipts()
+	h.executor.PopInternalTxs()
+	h.state.Commit()
+	return nil
+}
+
+// Result summarizes a benchmark run
+type Result struct {
+	Backend   string
+	Workload  string
+	NumBlocks int
+	NumTxs    int
+	Duration  time.Duration // Time spent applying the blocks
+}
+
+func (r *Result) String() string {
+	txsPerSec := float64(0)
+	if r.Duration > 0 {
+		txsPerSec = float64(r.NumTxs) / r.Duration.Seconds()
+	}
+	return fmt.Sprintf("backend: %v, workload: %v, blocks: %v, txs: %v, duration: %v, per block: %v, txs/s: %.1f",
+		r.Backend, r.Workload, r.NumBlocks, r.NumTxs, r.Duration, r.Duration/time.Duration(r.NumBlocks), txsPerSec)
+}
+
+// Run generates and applies the given number of blocks of the workload, and measures the time
+// spent applying them
+func Run(backendName string, workload string, dir string, numBlocks int) (*Result, error) {
+	if numBlocks <= 0 {
+		return nil, fmt.Errorf("Invalid number of blocks: %v", numBlocks)
+	}
+	h, err := NewHarness(backendName, dir)
+	if err != nil {
+		return nil, err
+	}
+	defer h.Close()
+
+	res := &Result{
+		Backend:   backendName,
+		Workload:  workload,
+		NumBlocks: numBlocks,
+	}
+	for i := 0; i < numBlocks; i++ {
+		rawTxs, err := h.GenerateBlock(workload)
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		err = h.ApplyBlock(rawTxs)
+		if err != nil {
+			return nil, err
+		}
+		res.Duration += time.Since(start)
+		res.NumTxs += len(rawTxs)
+	}
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package bench
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloads(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, workload := range []string{WorkloadSend, WorkloadContract, WorkloadStake} {
+		res, err := Run(BackendMemory, workload, "", 2)
+		assert.Nil(err, workload)
+		if assert.NotNil(res, workload) {
+			assert.Equal(2, res.NumBlocks)
+			assert.True(res.NumTxs > 0)
+		}
+	}
+
+	_, err := Run(BackendMemory, "unknown", "", 1)
+	assert.NotNil(err)
+}
+
+func benchmarkApplyBlock(b *testing.B, backendName string, workload string) {
+	dir, err := ioutil.TempDir("", "theta_bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	h, err := NewHarness(backendName, dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer h.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		rawTxs, err := h.GenerateBlock(workload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+		if err := h.ApplyBlock(rawTxs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApplySendBlockMemory(b *testing.B) {
+	benchmarkApplyBlock(b, BackendMemory, WorkloadSend)
+}
+
+func BenchmarkApplySendBlockLevelDB(b *testing.B) {
+	benchmarkApplyBlock(b, BackendLevelDB, WorkloadSend)
+}
+
+func BenchmarkApplyContractBlockMemory(b *testing.B) {
+	benchmarkApplyBlock(b, BackendMemory, WorkloadContract)
+}
+
+func BenchmarkApplyContractBlockLevelDB(b *testing.B) {
+	benchmarkApplyBlock(b, BackendLevelDB, WorkloadContract)
+}
+
+func BenchmarkApplyStakeBlockMemory(b *testing.B) {
+	benchmarkApplyBlock(b, BackendMemory, WorkloadStake)
+}
+
+func BenchmarkApplyStakeBlockLevelDB(b *testing.B) {
+	benchmarkApplyBlock(b, BackendLevelDB, WorkloadStake)
+}
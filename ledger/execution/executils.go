@@ -45,12 +45,12 @@ func getValidatorSet(ledger core.Ledger, valMgr core.ValidatorManager) *core.Val
 	return validatorSet
 }
 
-// getValidatorAddresses returns validators' addresses
+// getValidatorAddresses returns validators' signing addresses, which the proposals are signed with
 func getValidatorAddresses(validatorSet *core.ValidatorSet) []common.Address {
 	validators := validatorSet.Validators()
 	validatorAddresses := make([]common.Address, len(validators))
 	for i, v := range validators {
-		validatorAddresses[i] = v.Address
+		validatorAddresses[i] = validatorSet.SigningAddress(v.Address)
 	}
 	return validatorAddresses
 }
@@ -78,12 +78,14 @@ func getInputs(view *state.StoreView, ins []types.TxInput) (map[string]*types.Ac
 	for _, in := range ins {
 		// Account shouldn't be duplicated
 		if _, ok := accounts[string(in.Address[:])]; ok {
-			return nil, result.Error("getInputs - Duplicated address: %v", in.Address)
+			return nil, result.Error("getInputs - Duplicated address: %v", in.Address).
+				WithErrorCode(result.CodeDuplicatedAddress)
 		}
 
 		acc, success := getAccount(view, in.Address)
 		if success.IsError() {
-			return nil, result.Error("getInputs - Unknown address: %v", in.Address)
+			return nil, result.Error("getInputs - Unknown address: %v", in.Address).
+				WithErrorCode(result.CodeUnknownAccount)
 		}
 
 		accounts[string(in.Address[:])] = acc
@@ -103,7 +105,8 @@ func getOrMakeInput(view *state.StoreView, in types.TxInput) (*types.Account, re
 func getOrMakeInputImpl(view *state.StoreView, in types.TxInput, makeNewAccount bool) (*types.Account, result.Result) {
 	acc, success := getOrMakeAccountImpl(view, in.Address, makeNewAccount)
 	if success.IsError() {
-		return nil, result.Error("getOrMakeInputImpl - Unknown address: %v", in.Address)
+		return nil, result.Error("getOrMakeInputImpl - Unknown address: %v", in.Address).
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	return acc, result.OK
@@ -123,7 +126,8 @@ func getOrMakeAccountImpl(view *state.StoreView, address common.Address, makeNew
 	acc := view.GetAccount(address)
 	if acc == nil {
 		if !makeNewAccount {
-			return nil, result.Error("getOrMakeAccountImpl - Unknown address: %v", address)
+			return nil, result.Error("getOrMakeAccountImpl - Unknown address: %v", address).
+				WithErrorCode(result.CodeUnknownAccount)
 		}
 		acc = types.NewAccount(address)
 		acc.LastUpdatedBlockHeight = view.Height()
@@ -141,7 +145,8 @@ func getOrMakeOutputs(view *state.StoreView, accounts map[string]*types.Account,
 	for _, out := range outs {
 		// Account shouldn't be duplicated
 		if _, ok := accounts[string(out.Address[:])]; ok {
-			return nil, result.Error("getOrMakeOutputs - Duplicated address: %v", out.Address)
+			return nil, result.Error("getOrMakeOutputs - Duplicated address: %v", out.Address).
+				WithErrorCode(result.CodeDuplicatedAddress)
 		}
 
 		acc := getOrMakeAccount(view, out.Address)
@@ -194,10 +199,13 @@ func validateInputAdvanced(acc *types.Account, signBytes []byte, in types.TxInpu
 	}
 
 	// Check signatures
-	signatureValid := in.Signature.Verify(signBytes, acc.Address)
+	signatureValid := verifySignature(in.Signature, signBytes, acc.Address)
 	if blockHeight >= common.HeightTxWrapperExtension {
 		signBytesV2 := types.ChangeEthereumTxWrapper(signBytes, 2)
-		signatureValid = signatureValid || in.Signature.Verify(signBytesV2, acc.Address)
+		signatureValid = signatureValid || verifySignature(in.Signature, signBytesV2, acc.Address)
+	}
+	if !signatureValid && blockHeight >= common.HeightEnableMultisig {
+		signatureValid = verifyMultisigSignature(in.Signature, signBytes, acc.Address)
 	}
 
 	if !signatureValid {
@@ -208,6 +216,20 @@ func validateInputAdvanced(acc *types.Account, signBytes []byte, in types.TxInpu
 	return result.OK
 }
 
+// validateUnlockedCoins checks the coins spent by the account are not locked by its vesting schedules
+func validateUnlockedCoins(view *state.StoreView, acc *types.Account, coins types.Coins, blockHeight uint64) result.Result {
+	locked := view.GetLockedCoins(acc.Address, blockHeight)
+	if locked.IsZero() {
+		return result.OK
+	}
+	spendable := types.SpendableCoins(acc.Balance, locked)
+	if !spendable.IsGTE(coins) {
+		return result.Error("Insufficient unlocked fund: unlocked balance is %v, tried to spend %v",
+			spendable, coins).WithErrorCode(result.CodeLockedFund)
+	}
+	return result.OK
+}
+
 func validateOutputsBasic(outs []types.TxOutput) result.Result {
 	for _, out := range outs {
 		// Check TxOutput basic
@@ -283,12 +305,14 @@ func sanityCheckForSendTxFee(fee types.Coins, numAccountsAffected uint64, blockH
 	return minimumFee, success
 }
 
-func chargeFee(account *types.Account, fee types.Coins) bool {
+// chargeFee deducts the transaction fee from the account. The fee is burned, and recorded by the view.
+func chargeFee(view *state.StoreView, account *types.Account, fee types.Coins) bool {
 	if !account.Balance.IsGTE(fee) {
 		return false
 	}
 
 	account.Balance = account.Balance.Minus(fee)
+	view.RecordBurnedFees(fee.NoNil().TFuelWei)
 	return true
 }
 
@@ -44,6 +44,8 @@ type Executor struct {
 	depositStakeTxExec            *DepositStakeExecutor
 	withdrawStakeTxExec           *WithdrawStakeExecutor
 	stakeRewardDistributionTxExec *StakeRewardDistributionTxExecutor
+	vestingTxExec                 *VestingTxExecutor
+	rotateValidatorKeyTxExec      *RotateValidatorKeyTxExecutor
 
 	skipSanityCheck bool
 }
@@ -67,6 +69,8 @@ func NewExecutor(db database.Database, chain *blockchain.Chain, state *st.Ledger
 		depositStakeTxExec:            NewDepositStakeExecutor(state),
 		withdrawStakeTxExec:           NewWithdrawStakeExecutor(state),
 		stakeRewardDistributionTxExec: NewStakeRewardDistributionTxExecutor(state),
+		vestingTxExec:                 NewVestingTxExecutor(state),
+		rotateValidatorKeyTxExec:      NewRotateValidatorKeyTxExecutor(state),
 		skipSanityCheck:               false,
 	}
 
@@ -79,11 +83,27 @@ func (exec *Executor) SetSkipSanityCheck(skip bool) {
 	exec.skipSanityCheck = skip
 }
 
+// SetRecordRewardEvents sets whether to record the reward events of the delivered coinbase transactions.
+func (exec *Executor) SetRecordRewardEvents(record bool) {
+	exec.coinbaseTxExec.recordRewardEvents = record
+}
+
 // ExecuteTx executes the given transaction
 func (exec *Executor) ExecuteTx(tx types.Tx) (common.Hash, result.Result) {
 	return exec.processTx(tx, core.DeliveredView)
 }
 
+// ExecuteTxOnView executes the given transaction on the given view instead of the delivered view,
+// e.g. on a copy of the delivered view to execute the transaction in parallel with the others
+func (exec *Executor) ExecuteTxOnView(view *st.StoreView, tx types.Tx) (common.Hash, result.Result) {
+	chainID := exec.state.GetChainID()
+	sanityCheckResult := exec.sanityCheck(chainID, view, tx)
+	if sanityCheckResult.IsError() {
+		return common.Hash{}, sanityCheckResult
+	}
+	return exec.process(chainID, view, tx)
+}
+
 // CheckTx checks the validity of the given transaction
 func (exec *Executor) CheckTx(tx types.Tx) (common.Hash, result.Result) {
 	return exec.processTx(tx, core.CheckedView)
@@ -94,11 +114,29 @@ func (exec *Executor) ScreenTx(tx types.Tx) (common.Hash, result.Result) {
 	return exec.processTx(tx, core.ScreenedView)
 }
 
+// PopTxReceipts returns the receipts of the smart contract transactions delivered since the last
+// call. The receipts are saved when the block is committed, and discarded if the block is rejected.
+func (exec *Executor) PopTxReceipts() []*blockchain.TxReceiptEntry {
+	return exec.smartContractTxExec.popPendingReceipts()
+}
+
+// PopInternalTxs returns the internal transactions of the smart contract transactions delivered since
+// the last call. Similar to the receipts, they are saved when the block is committed.
+func (exec *Executor) PopInternalTxs() []*blockchain.InternalTxsEntry {
+	return exec.smartContractTxExec.popPendingInternalTxs()
+}
+
+// PopRewardEvents returns the reward events of the coinbase transactions delivered since the last
+// call. Similar to the receipts, they are saved when the block is committed.
+func (exec *Executor) PopRewardEvents() []*blockchain.RewardEvent {
+	return exec.coinbaseTxExec.popPendingRewardEvents()
+}
+
 // GetTxInfo extracts tx information used by mempool to sort Txs.
 func (exec *Executor) GetTxInfo(tx types.Tx) (*core.TxInfo, result.Result) {
 	txExecutor := exec.getTxExecutor(tx)
 	if txExecutor == nil {
-		return nil, result.Error("Unknown tx type")
+		return nil, result.Error("Unknown tx type").WithErrorCode(result.CodeUnknownTxType)
 	}
 
 	txInfo := txExecutor.getTxInfo(tx)
@@ -133,7 +171,7 @@ func (exec *Executor) sanityCheck(chainID string, view *st.StoreView, tx types.T
 	}
 
 	if !exec.isTxTypeSupported(view, tx) {
-		return result.Error("tx type not supported yet")
+		return result.Error("tx type not supported yet").WithErrorCode(result.CodeTxTypeNotSupported)
 	}
 
 	var sanityCheckResult result.Result
@@ -141,7 +179,7 @@ func (exec *Executor) sanityCheck(chainID string, view *st.StoreView, tx types.T
 	if txExecutor != nil {
 		sanityCheckResult = txExecutor.sanityCheck(chainID, view, tx)
 	} else {
-		sanityCheckResult = result.Error("Unknown tx type")
+		sanityCheckResult = result.Error("Unknown tx type").WithErrorCode(result.CodeUnknownTxType)
 	}
 
 	return sanityCheckResult
@@ -152,7 +190,7 @@ func (exec *Executor) process(chainID string, view *st.StoreView, tx types.Tx) (
 	var txHash common.Hash
 
 	if !exec.isTxTypeSupported(view, tx) {
-		return txHash, result.Error("tx type not supported yet")
+		return txHash, result.Error("tx type not supported yet").WithErrorCode(result.CodeTxTypeNotSupported)
 	}
 
 	txExecutor := exec.getTxExecutor(tx)
@@ -162,7 +200,7 @@ func (exec *Executor) process(chainID string, view *st.StoreView, tx types.Tx) (
 			logger.Warnf("Tx processing error: %v", processResult.Message)
 		}
 	} else {
-		processResult = result.Error("Unknown tx type")
+		processResult = result.Error("Unknown tx type").WithErrorCode(result.CodeUnknownTxType)
 	}
 
 	return txHash, processResult
@@ -180,6 +218,14 @@ func (exec *Executor) isTxTypeSupported(view *st.StoreView, tx types.Tx) bool {
 		if blockHeight < common.HeightEnableTheta3 {
 			return false
 		}
+	case *types.VestingTx:
+		if blockHeight < common.HeightEnableVesting {
+			return false
+		}
+	case *types.RotateValidatorKeyTx:
+		if blockHeight < common.HeightEnableValidatorKeyRotation {
+			return false
+		}
 	default:
 		return true
 	}
@@ -214,6 +260,10 @@ func (exec *Executor) getTxExecutor(tx types.Tx) TxExecutor {
 		txExecutor = exec.depositStakeTxExec
 	case *types.StakeRewardDistributionTx:
 		txExecutor = exec.stakeRewardDistributionTxExec
+	case *types.VestingTx:
+		txExecutor = exec.vestingTxExec
+	case *types.RotateValidatorKeyTx:
+		txExecutor = exec.rotateValidatorKeyTxExec
 	default:
 		txExecutor = nil
 	}
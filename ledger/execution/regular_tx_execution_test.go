@@ -7,8 +7,10 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/blockchain"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/ledger/types"
 )
 
@@ -2251,3 +2253,45 @@ func TestSplitRuleZeroDuration(t *testing.T) {
 	retrievedSplitRule2ndTime := et.state().Delivered().GetSplitRule(resourceID)
 	assert.Nil(retrievedSplitRule2ndTime) // Should be expired and got deleted
 }
+
+func TestIssueFixedRewardEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	validator := common.HexToAddress("0x2000000000000000000000000000000000000001")
+	guardian := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	source1 := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	source2 := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	holderReasons := map[common.Address]string{
+		validator: blockchain.RewardReasonValidator,
+		guardian:  blockchain.RewardReasonGuardian,
+	}
+	effectiveStakes := [][]*core.Stake{
+		{
+			{Holder: validator, Source: source1, Amount: big.NewInt(100)},
+			{Holder: guardian, Source: source1, Amount: big.NewInt(100)},
+		},
+		{
+			{Holder: guardian, Source: source2, Amount: big.NewInt(200)},
+		},
+	}
+
+	accountReward := map[string]types.Coins{}
+	events := []*blockchain.RewardEvent{}
+	issueFixedReward(effectiveStakes, big.NewInt(400), &accountReward, big.NewInt(4000), nil, "Block", &events, holderReasons)
+
+	assert.Equal(2, len(events))
+	assert.Equal(source1, events[0].Beneficiary)
+	assert.Equal(blockchain.RewardReasonStaking, events[0].Reason)
+	assert.Equal(int64(2000), events[0].Amount.Int64())
+	assert.Equal(source2, events[1].Beneficiary)
+	assert.Equal(blockchain.RewardReasonGuardian, events[1].Reason)
+	assert.Equal(int64(2000), events[1].Amount.Int64())
+	for _, event := range events {
+		assert.Equal(0, accountReward[string(event.Beneficiary[:])].TFuelWei.Cmp(event.Amount))
+	}
+
+	// The events are not recorded without the events slice
+	accountReward = map[string]types.Coins{}
+	issueFixedReward(effectiveStakes, big.NewInt(400), &accountReward, big.NewInt(4000), nil, "Block", nil, holderReasons)
+	assert.Equal(2, len(accountReward))
+}
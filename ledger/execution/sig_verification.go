@@ -0,0 +1,157 @@
+package execution
+
+import (
+	"runtime"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// SignatureCacheSize is the max number of verified signatures kept in the signature cache
+const SignatureCacheSize = 32768
+
+// sigCache records the signatures that have been verified. It is shared by the mempool, which
+// screens the incoming transactions, and the block processing, so a signature is not verified
+// again when the transaction is included in a block.
+var sigCache *lru.Cache
+
+func init() {
+	sigCache, _ = lru.New(SignatureCacheSize)
+}
+
+// sigCacheKey returns the signature cache key, which is the hash of the signed bytes (i.e. the
+// transaction hash), the signature and the signer.
+func sigCacheKey(sig *crypto.Signature, signBytes common.Bytes, addr common.Address) common.Hash {
+	return crypto.Keccak256Hash(signBytes, sig.ToBytes(), addr[:])
+}
+
+// verifySignature checks the signature is signed by the given address. The ECDSA recovery is
+// skipped if the signature has been verified before.
+func verifySignature(sig *crypto.Signature, signBytes common.Bytes, addr common.Address) bool {
+	if sig == nil || sig.IsEmpty() {
+		return false
+	}
+	key := sigCacheKey(sig, signBytes, addr)
+	if sigCache.Contains(key) {
+		return true
+	}
+	if !sig.Verify(signBytes, addr) {
+		return false
+	}
+	sigCache.Add(key, struct{}{})
+	return true
+}
+
+// verifyMultisigSignature checks the signature is a multisig signature signed by at least threshold
+// signers of the multisig account with the given address
+func verifyMultisigSignature(sig *crypto.Signature, signBytes common.Bytes, addr common.Address) bool {
+	ms, err := types.MultisigSignatureFromSignature(sig)
+	if err != nil {
+		return false
+	}
+	return ms.Verify(signBytes, addr, verifySignature)
+}
+
+//
+// txSignature is a signature carried by a transaction
+//
+type txSignature struct {
+	sig       *crypto.Signature
+	signBytes common.Bytes
+	addr      common.Address
+	wrapped   bool // whether the signature could be signed with the extended tx wrapper
+}
+
+// getTxSignatures returns the signatures a transaction carries, which can be verified without
+// the ledger state.
+func (exec *Executor) getTxSignatures(chainID string, tx types.Tx) []txSignature {
+	sigs := []txSignature{}
+	switch tx := tx.(type) {
+	case *types.CoinbaseTx:
+		sigs = append(sigs, txSignature{tx.Proposer.Signature, tx.SignBytes(chainID), tx.Proposer.Address, false})
+	case *types.SlashTx:
+		sigs = append(sigs, txSignature{tx.Proposer.Signature, tx.SignBytes(chainID), tx.Proposer.Address, false})
+	case *types.SendTx:
+		signBytes := tx.SignBytes(chainID)
+		for _, in := range tx.Inputs {
+			sigs = append(sigs, txSignature{in.Signature, signBytes, in.Address, true})
+		}
+	case *types.ReserveFundTx:
+		sigs = append(sigs, txSignature{tx.Source.Signature, tx.SignBytes(chainID), tx.Source.Address, true})
+	case *types.ReleaseFundTx:
+		sigs = append(sigs, txSignature{tx.Source.Signature, tx.SignBytes(chainID), tx.Source.Address, true})
+	case *types.ServicePaymentTx:
+		sigs = append(sigs, txSignature{tx.Source.Signature, tx.SourceSignBytes(chainID), tx.Source.Address, false})
+		sigs = append(sigs, txSignature{tx.Target.Signature, tx.TargetSignBytes(chainID), tx.Target.Address, false})
+	case *types.SplitRuleTx:
+		sigs = append(sigs, txSignature{tx.Initiator.Signature, tx.SignBytes(chainID), tx.Initiator.Address, true})
+	case *types.SmartContractTx:
+		sigs = append(sigs, txSignature{tx.From.Signature, tx.SignBytes(chainID), tx.From.Address, true})
+	case *types.DepositStakeTx, *types.DepositStakeTxV2:
+		dtx := exec.depositStakeTxExec.castTx(tx) // signed in the V2 format
+		sigs = append(sigs, txSignature{dtx.Source.Signature, dtx.SignBytes(chainID), dtx.Source.Address, true})
+	case *types.WithdrawStakeTx:
+		sigs = append(sigs, txSignature{tx.Source.Signature, tx.SignBytes(chainID), tx.Source.Address, true})
+	case *types.StakeRewardDistributionTx:
+		sigs = append(sigs, txSignature{tx.Holder.Signature, tx.SignBytes(chainID), tx.Holder.Address, true})
+	case *types.VestingTx:
+		sigs = append(sigs, txSignature{tx.Source.Signature, tx.SignBytes(chainID), tx.Source.Address, true})
+	case *types.RotateValidatorKeyTx:
+		sigs = append(sigs, txSignature{tx.Holder.Signature, tx.SignBytes(chainID), tx.Holder.Address, true})
+		if tx.SigningSig != nil {
+			sigs = append(sigs, txSignature{tx.SigningSig, tx.SigningKeySignBytes(chainID), tx.SigningAddress, false})
+		}
+	}
+	return sigs
+}
+
+// PreverifySignatures verifies the signatures of the given block transactions with a pool of
+// workers, and records the valid ones in the signature cache. The transactions are then executed
+// sequentially, without verifying the signatures again. Invalid transactions are left to the
+// execution to reject.
+func (exec *Executor) PreverifySignatures(rawTxs []common.Bytes) {
+	if exec.skipSanityCheck {
+		return
+	}
+
+	chainID := exec.state.GetChainID()
+	blockHeight := getBlockHeight(exec.state)
+
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(rawTxs) {
+		numWorkers = len(rawTxs)
+	}
+
+	rawTxCh := make(chan common.Bytes, len(rawTxs))
+	for _, rawTx := range rawTxs {
+		rawTxCh <- rawTx
+	}
+	close(rawTxCh)
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rawTx := range rawTxCh {
+				tx, err := types.TxFromBytes(rawTx)
+				if err != nil {
+					continue
+				}
+				for _, ts := range exec.getTxSignatures(chainID, tx) {
+					if verifySignature(ts.sig, ts.signBytes, ts.addr) {
+						continue
+					}
+					if ts.wrapped && blockHeight >= common.HeightTxWrapperExtension {
+						verifySignature(ts.sig, types.ChangeEthereumTxWrapper(ts.signBytes, 2), ts.addr)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
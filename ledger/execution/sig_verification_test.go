@@ -0,0 +1,46 @@
+package execution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestPreverifySignatures(t *testing.T) {
+	assert := assert.New(t)
+	et := NewExecTest()
+	et.acc2State(et.accIn)
+	et.acc2State(et.accOut)
+
+	tx := types.MakeSendTx(1, et.accOut, et.accIn)
+	et.signSendTx(tx, et.accIn)
+	rawTx, err := types.TxToBytes(tx)
+	assert.Nil(err)
+
+	// A tx signed by the wrong account
+	badTx := types.MakeSendTx(2, et.accOut, et.accIn)
+	et.signSendTx(badTx, et.accOut)
+	badRawTx, err := types.TxToBytes(badTx)
+	assert.Nil(err)
+
+	et.executor.PreverifySignatures([]common.Bytes{rawTx, badRawTx, common.Bytes("invalid tx")})
+
+	signBytes := tx.SignBytes(et.chainID)
+	in := tx.Inputs[0]
+	assert.True(sigCache.Contains(sigCacheKey(in.Signature, signBytes, in.Address)))
+
+	badSignBytes := badTx.SignBytes(et.chainID)
+	badIn := badTx.Inputs[0]
+	assert.False(sigCache.Contains(sigCacheKey(badIn.Signature, badSignBytes, badIn.Address)))
+
+	// The cached signature verifies, and the execution results are not affected
+	assert.True(verifySignature(in.Signature, signBytes, in.Address))
+	assert.False(verifySignature(badIn.Signature, badSignBytes, badIn.Address))
+
+	// The cache entries are keyed by the signer as well
+	assert.False(verifySignature(in.Signature, signBytes, et.accOut.Account.Address))
+	res, _, _, _, _ := et.execSendTx(tx, false)
+	assert.True(res.IsOK(), res.Message)
+}
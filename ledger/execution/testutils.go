@@ -2,6 +2,7 @@ package execution
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -29,6 +30,7 @@ type TestConsensusEngine struct {
 
 func (tce *TestConsensusEngine) ID() string                        { return tce.privKey.PublicKey().Address().Hex() }
 func (tce *TestConsensusEngine) PrivateKey() *crypto.PrivateKey    { return tce.privKey }
+func (tce *TestConsensusEngine) Signer() core.Signer               { return core.NewLocalSigner(tce.privKey) }
 func (tce *TestConsensusEngine) GetTip(bool) *core.ExtendedBlock   { return nil }
 func (tce *TestConsensusEngine) GetEpoch() uint64                  { return 100 }
 func (tce *TestConsensusEngine) AddMessage(msg interface{})        {}
@@ -37,6 +39,9 @@ func (tce *TestConsensusEngine) GetLedger() core.Ledger            { return nil
 func (tce *TestConsensusEngine) GetLastFinalizedBlock() *core.ExtendedBlock {
 	return &core.ExtendedBlock{}
 }
+func (tce *TestConsensusEngine) FindBlock(blockHash common.Hash) (*core.ExtendedBlock, error) {
+	return nil, errors.New("Block not found")
+}
 
 func NewTestConsensusEngine(seed string) *TestConsensusEngine {
 	privKey, _, _ := crypto.TEST_GenerateKeyPairWithSeed(seed)
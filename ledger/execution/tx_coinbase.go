@@ -35,6 +35,9 @@ type CoinbaseTxExecutor struct {
 	state     *st.LedgerState
 	consensus core.ConsensusEngine
 	valMgr    core.ValidatorManager
+
+	recordRewardEvents  bool
+	pendingRewardEvents []*blockchain.RewardEvent // reward events of the delivered coinbase tx, saved when the block is committed
 }
 
 // NewCoinbaseTxExecutor creates a new instance of CoinbaseTxExecutor
@@ -77,7 +80,7 @@ func (exec *CoinbaseTxExecutor) sanityCheck(chainID string, view *st.StoreView,
 
 	// verify the proposer's signature
 	signBytes := tx.SignBytes(chainID)
-	if !tx.Proposer.Signature.Verify(signBytes, proposerAccount.Address) {
+	if !verifySignature(tx.Proposer.Signature, signBytes, proposerAccount.Address) {
 		return result.Error("SignBytes: %X", signBytes)
 	}
 
@@ -136,10 +139,33 @@ func (exec *CoinbaseTxExecutor) process(chainID string, view *st.StoreView, tran
 
 	view.SetCoinbaseTransactionProcessed(true)
 
+	if exec.recordRewardEvents && view == exec.state.Delivered() && common.IsCheckPointHeight(view.Height()+1) {
+		exec.pendingRewardEvents = append(exec.pendingRewardEvents, exec.calculateRewardEvents(view, tx)...)
+	}
+
 	txHash := types.TxID(chainID, tx)
 	return txHash, result.OK
 }
 
+// calculateRewardEvents calculates the rewards of the coinbase transaction the same way as the
+// sanity check, and returns the reward events
+func (exec *CoinbaseTxExecutor) calculateRewardEvents(view *st.StoreView, tx *types.CoinbaseTx) []*blockchain.RewardEvent {
+	validatorSet := getValidatorSet(exec.consensus.GetLedger(), exec.valMgr)
+	currentBlock := exec.consensus.GetLedger().GetCurrentBlock()
+	guardianVotes := currentBlock.GuardianVotes
+	eliteEdgeNodeVotes := currentBlock.EliteEdgeNodeVotes
+	guardianPool, eliteEdgeNodePool := RetrievePools(exec.consensus.GetLedger(), exec.chain, exec.db, tx.BlockHeight, guardianVotes, eliteEdgeNodeVotes)
+	_, events := CalculateRewardEvents(exec.consensus.GetLedger(), view, validatorSet, guardianVotes, guardianPool, eliteEdgeNodeVotes, eliteEdgeNodePool)
+	return events
+}
+
+// popPendingRewardEvents returns and clears the reward events of the delivered coinbase tx
+func (exec *CoinbaseTxExecutor) popPendingRewardEvents() []*blockchain.RewardEvent {
+	events := exec.pendingRewardEvents
+	exec.pendingRewardEvents = nil
+	return events
+}
+
 func RetrievePools(ledger core.Ledger, chain *blockchain.Chain, db database.Database, blockHeight uint64, guardianVotes *core.AggregatedVotes,
 	eliteEdgeNodeVotes *core.AggregatedEENVotes) (guardianPool *core.GuardianCandidatePool, eliteEdgeNodePool core.EliteEdgeNodePool) {
 	guardianPool = nil
@@ -188,17 +214,39 @@ func RetrievePools(ledger core.Ledger, chain *blockchain.Chain, db database.Data
 func CalculateReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet,
 	guardianVotes *core.AggregatedVotes, guardianPool *core.GuardianCandidatePool,
 	eliteEdgeNodeVotes *core.AggregatedEENVotes, eliteEdgeNodePool core.EliteEdgeNodePool) map[string]types.Coins {
+	return calculateReward(ledger, view, validatorSet, guardianVotes, guardianPool, eliteEdgeNodeVotes, eliteEdgeNodePool, nil)
+}
+
+// CalculateRewardEvents calculates the block reward for each account like CalculateReward, and also
+// returns the reward events which break the rewards down by the stake holders and the reasons
+func CalculateRewardEvents(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet,
+	guardianVotes *core.AggregatedVotes, guardianPool *core.GuardianCandidatePool,
+	eliteEdgeNodeVotes *core.AggregatedEENVotes, eliteEdgeNodePool core.EliteEdgeNodePool) (map[string]types.Coins, []*blockchain.RewardEvent) {
+	events := []*blockchain.RewardEvent{}
+	accountReward := calculateReward(ledger, view, validatorSet, guardianVotes, guardianPool, eliteEdgeNodeVotes, eliteEdgeNodePool, &events)
+	blockHeight := view.Height() + 1
+	for _, event := range events {
+		event.Height = blockHeight
+	}
+	return accountReward, events
+}
+
+// calculateReward calculates the block reward for each account, and appends the reward events to
+// the events if not nil
+func calculateReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet,
+	guardianVotes *core.AggregatedVotes, guardianPool *core.GuardianCandidatePool,
+	eliteEdgeNodeVotes *core.AggregatedEENVotes, eliteEdgeNodePool core.EliteEdgeNodePool, events *[]*blockchain.RewardEvent) map[string]types.Coins {
 	accountReward := map[string]types.Coins{}
 	blockHeight := view.Height() + 1 // view points to the parent block
 	if blockHeight < common.HeightEnableValidatorReward {
 		grantValidatorsWithZeroReward(validatorSet, &accountReward)
 	} else if blockHeight < common.HeightEnableTheta2 || guardianVotes == nil || guardianPool == nil {
-		grantValidatorReward(ledger, view, validatorSet, &accountReward, blockHeight)
+		grantValidatorReward(ledger, view, validatorSet, &accountReward, events, blockHeight)
 	} else if blockHeight < common.HeightEnableTheta3 {
-		grantValidatorAndGuardianReward(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, blockHeight)
+		grantValidatorAndGuardianReward(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, events, blockHeight)
 	} else { // blockHeight >= common.HeightEnableTheta3
-		grantValidatorAndGuardianReward(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, blockHeight)
-		grantEliteEdgeNodeReward(ledger, view, guardianVotes, eliteEdgeNodeVotes, eliteEdgeNodePool, &accountReward, blockHeight)
+		grantValidatorAndGuardianReward(ledger, view, validatorSet, guardianVotes, guardianPool, &accountReward, events, blockHeight)
+		grantEliteEdgeNodeReward(ledger, view, guardianVotes, eliteEdgeNodeVotes, eliteEdgeNodePool, &accountReward, events, blockHeight)
 	}
 
 	addrs := []string{}
@@ -222,7 +270,8 @@ func grantValidatorsWithZeroReward(validatorSet *core.ValidatorSet, accountRewar
 	}
 }
 
-func grantValidatorReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet, accountReward *map[string]types.Coins, blockHeight uint64) {
+func grantValidatorReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet, accountReward *map[string]types.Coins,
+	events *[]*blockchain.RewardEvent, blockHeight uint64) {
 	if !common.IsCheckPointHeight(blockHeight) {
 		return
 	}
@@ -275,6 +324,7 @@ func grantValidatorReward(ledger core.Ledger, view *st.StoreView, validatorSet *
 			TFuelWei: rewardAmount,
 		}.NoNil()
 		(*accountReward)[string(stakeSourceAddr[:])] = reward
+		recordRewardEvent(events, stakeSourceAddr, common.Address{}, rewardAmount, blockchain.RewardReasonValidator)
 
 		logger.Infof("Block reward for staker %v : %v", hex.EncodeToString(stakeSourceAddr[:]), reward)
 	}
@@ -282,7 +332,7 @@ func grantValidatorReward(ledger core.Ledger, view *st.StoreView, validatorSet *
 
 // grant block rewards to both the validators and active guardians (they are both theta stakers)
 func grantValidatorAndGuardianReward(ledger core.Ledger, view *st.StoreView, validatorSet *core.ValidatorSet, guardianVotes *core.AggregatedVotes,
-	guardianPool *core.GuardianCandidatePool, accountReward *map[string]types.Coins, blockHeight uint64) {
+	guardianPool *core.GuardianCandidatePool, accountReward *map[string]types.Coins, events *[]*blockchain.RewardEvent, blockHeight uint64) {
 	if !common.IsCheckPointHeight(blockHeight) {
 		return
 	}
@@ -302,6 +352,7 @@ func grantValidatorAndGuardianReward(ledger core.Ledger, view *st.StoreView, val
 
 	effectiveStakes := [][]*core.Stake{}          // For compatiblity with old sampling algorithm, stakes from the same staker are grouped together
 	stakeGroupMap := make(map[common.Address]int) // stake source address -> index of the group in the effectiveStakes slice
+	holderReasons := make(map[common.Address]string)
 
 	// TODO - Need to confirm: should we get the VCP from the current view? What if there is a stake deposit/withdraw?
 	vcp := view.GetValidatorCandidatePool()
@@ -311,6 +362,7 @@ func grantValidatorAndGuardianReward(ledger core.Ledger, view *st.StoreView, val
 		if stakeDelegate == nil { // should not happen
 			panic(fmt.Sprintf("Failed to find stake delegate in the VCP: %v", hex.EncodeToString(validatorAddr[:])))
 		}
+		holderReasons[stakeDelegate.Holder] = blockchain.RewardReasonValidator
 
 		stakes := stakeDelegate.Stakes
 		for _, stake := range stakes {
@@ -331,6 +383,9 @@ func grantValidatorAndGuardianReward(ledger core.Ledger, view *st.StoreView, val
 		if guardianVotes.Multiplies[i] == 0 {
 			continue
 		}
+		if _, exists := holderReasons[g.Holder]; !exists {
+			holderReasons[g.Holder] = blockchain.RewardReasonGuardian
+		}
 		stakes := g.Stakes
 		for _, stake := range stakes {
 			if stake.Withdrawn {
@@ -358,17 +413,17 @@ func grantValidatorAndGuardianReward(ledger core.Ledger, view *st.StoreView, val
 
 	if blockHeight < common.HeightSampleStakingReward {
 		// the source of the stake divides the block reward proportional to their stake
-		issueFixedReward(effectiveStakes, totalStake, accountReward, totalReward, srdsr, "Block")
+		issueFixedReward(effectiveStakes, totalStake, accountReward, totalReward, srdsr, "Block", events, holderReasons)
 	} else {
 		// randomly select (proportional to the stake) a constant-sized set of stakers and grand the block reward
 		issueRandomizedReward(ledger, guardianVotes, view, effectiveStakes,
-			totalStake, accountReward, totalReward, srdsr, "Block")
+			totalStake, accountReward, totalReward, srdsr, "Block", events, holderReasons)
 	}
 }
 
 // grant uptime mining rewards to active elite edge nodes (they are the tfuel stakers)
 func grantEliteEdgeNodeReward(ledger core.Ledger, view *st.StoreView, guardianVotes *core.AggregatedVotes, eliteEdgeNodeVotes *core.AggregatedEENVotes,
-	eliteEdgeNodePool core.EliteEdgeNodePool, accountReward *map[string]types.Coins, blockHeight uint64) {
+	eliteEdgeNodePool core.EliteEdgeNodePool, accountReward *map[string]types.Coins, events *[]*blockchain.RewardEvent, blockHeight uint64) {
 	if !common.IsCheckPointHeight(blockHeight) {
 		return
 	}
@@ -386,6 +441,7 @@ func grantEliteEdgeNodeReward(ledger core.Ledger, view *st.StoreView, guardianVo
 
 	effectiveStakes := [][]*core.Stake{}          // For compatiblity with old sampling algorithm, stakes from the same staker are grouped together
 	stakeGroupMap := make(map[common.Address]int) // stake source address -> index of the group in the effectiveStakes slice
+	holderReasons := make(map[common.Address]string)
 
 	totalEffectiveStake := new(big.Int)
 	amplifier := new(big.Int).SetUint64(1e18)
@@ -399,6 +455,7 @@ func grantEliteEdgeNodeReward(ledger core.Ledger, view *st.StoreView, guardianVo
 		}
 
 		amplifiedWeight := big.NewInt(1).Mul(amplifier, weight)
+		holderReasons[een.Holder] = blockchain.RewardReasonEliteEdgeNode
 		for _, stake := range een.Stakes {
 			if stake.Withdrawn {
 				continue
@@ -439,7 +496,7 @@ func grantEliteEdgeNodeReward(ledger core.Ledger, view *st.StoreView, guardianVo
 	}
 
 	// the source of the stake divides the block reward proportional to their stake
-	issueFixedReward(effectiveStakes, totalEffectiveStake, accountReward, totalReward, srdsr, "EEN  ")
+	issueFixedReward(effectiveStakes, totalEffectiveStake, accountReward, totalReward, srdsr, "EEN  ", events, holderReasons)
 
 }
 
@@ -457,7 +514,33 @@ func addRewardToMap(receiver common.Address, amount *big.Int, accountReward *map
 	}
 }
 
-func handleSplit(stake *core.Stake, srdsr *st.StakeRewardDistributionRuleSet, reward *big.Int, accountRewardMap *map[string]types.Coins) {
+// recordRewardEvent appends the reward event to the events if they are being recorded
+func recordRewardEvent(events *[]*blockchain.RewardEvent, beneficiary common.Address, holder common.Address, amount *big.Int, reason string) {
+	if events == nil || amount.Sign() == 0 {
+		return
+	}
+	*events = append(*events, &blockchain.RewardEvent{
+		Beneficiary: beneficiary,
+		Holder:      holder,
+		Reason:      reason,
+		Amount:      new(big.Int).Set(amount),
+	})
+}
+
+// stakeGroupReason returns the reward reason of a group of stakes from the same source, which is
+// RewardReasonStaking if the stakes are delegated to the holders of different reasons
+func stakeGroupReason(stakes []*core.Stake, holderReasons map[common.Address]string) string {
+	reason := holderReasons[stakes[0].Holder]
+	for _, stake := range stakes[1:] {
+		if holderReasons[stake.Holder] != reason {
+			return blockchain.RewardReasonStaking
+		}
+	}
+	return reason
+}
+
+func handleSplit(stake *core.Stake, srdsr *st.StakeRewardDistributionRuleSet, reward *big.Int, accountRewardMap *map[string]types.Coins,
+	events *[]*blockchain.RewardEvent, reason string) {
 	if srdsr == nil {
 		// Should not happen
 		logger.Panic("srdsr is nil")
@@ -470,6 +553,7 @@ func handleSplit(stake *core.Stake, srdsr *st.StakeRewardDistributionRuleSet, re
 	rewardDistribution := srdsr.Get(stake.Holder)
 	if rewardDistribution == nil {
 		addRewardToMap(stake.Source, reward, accountRewardMap)
+		recordRewardEvent(events, stake.Source, stake.Holder, reward, reason)
 		return
 	}
 
@@ -493,9 +577,12 @@ func handleSplit(stake *core.Stake, srdsr *st.StakeRewardDistributionRuleSet, re
 
 	addRewardToMap(stake.Source, sourceReward, accountRewardMap)
 	addRewardToMap(rewardDistribution.Beneficiary, splitReward, accountRewardMap)
+	recordRewardEvent(events, stake.Source, stake.Holder, sourceReward, reason)
+	recordRewardEvent(events, rewardDistribution.Beneficiary, stake.Holder, splitReward, blockchain.RewardReasonSplit)
 }
 
-func issueFixedReward(effectiveStakes [][]*core.Stake, totalStake *big.Int, accountReward *map[string]types.Coins, totalReward *big.Int, srdsr *st.StakeRewardDistributionRuleSet, rewardType string) {
+func issueFixedReward(effectiveStakes [][]*core.Stake, totalStake *big.Int, accountReward *map[string]types.Coins, totalReward *big.Int, srdsr *st.StakeRewardDistributionRuleSet, rewardType string,
+	events *[]*blockchain.RewardEvent, holderReasons map[common.Address]string) {
 	if totalStake.Cmp(big.NewInt(0)) == 0 {
 		return
 	}
@@ -510,7 +597,7 @@ func issueFixedReward(effectiveStakes [][]*core.Stake, totalStake *big.Int, acco
 				logger.Infof("%v reward for staker %v : %v  (before split)", rewardType, hex.EncodeToString(stake.Source[:]), rewardAmount)
 
 				// Calculate split
-				handleSplit(stake, srdsr, rewardAmount, accountReward)
+				handleSplit(stake, srdsr, rewardAmount, accountReward, events, holderReasons[stake.Holder])
 			}
 		}
 	} else {
@@ -527,6 +614,7 @@ func issueFixedReward(effectiveStakes [][]*core.Stake, totalStake *big.Int, acco
 			rewardAmount.Mul(totalReward, totalSourceStake)
 			rewardAmount.Div(rewardAmount, totalStake)
 			addRewardToMap(stakes[0].Source, rewardAmount, accountReward)
+			recordRewardEvent(events, stakes[0].Source, common.Address{}, rewardAmount, stakeGroupReason(stakes, holderReasons))
 
 			logger.Infof("%v reward for staker %v : %v  (before split)", rewardType, hex.EncodeToString(stakes[0].Source[:]), rewardAmount)
 		}
@@ -534,7 +622,8 @@ func issueFixedReward(effectiveStakes [][]*core.Stake, totalStake *big.Int, acco
 }
 
 func issueRandomizedReward(ledger core.Ledger, guardianVotes *core.AggregatedVotes, view *st.StoreView, effectiveStakes [][]*core.Stake,
-	totalStake *big.Int, accountReward *map[string]types.Coins, totalReward *big.Int, srdsr *st.StakeRewardDistributionRuleSet, rewardType string) {
+	totalStake *big.Int, accountReward *map[string]types.Coins, totalReward *big.Int, srdsr *st.StakeRewardDistributionRuleSet, rewardType string,
+	events *[]*blockchain.RewardEvent, holderReasons map[common.Address]string) {
 
 	if guardianVotes == nil {
 		// Should never reach here
@@ -596,7 +685,7 @@ func issueRandomizedReward(ledger core.Ledger, guardianVotes *core.AggregatedVot
 					logger.Infof("%v reward for staker %v : %v (before split)", rewardType, hex.EncodeToString(stakeSourceAddr[:]), rewardAmount)
 
 					// Calculate split
-					handleSplit(stake, srdsr, rewardAmount, accountReward)
+					handleSplit(stake, srdsr, rewardAmount, accountReward, events, holderReasons[stake.Holder])
 				}
 			}
 		}
@@ -635,6 +724,7 @@ func issueRandomizedReward(ledger core.Ledger, guardianVotes *core.AggregatedVot
 				rewardAmount := tmp.Div(tmp, big.NewInt(int64(tfuelRewardN)))
 
 				addRewardToMap(stakeSourceAddr, rewardAmount, accountReward)
+				recordRewardEvent(events, stakeSourceAddr, common.Address{}, rewardAmount, stakeGroupReason(stakes, holderReasons))
 
 				logger.Infof("%v reward for staker %v : %v (before split)", rewardType, hex.EncodeToString(stakeSourceAddr[:]), rewardAmount)
 			}
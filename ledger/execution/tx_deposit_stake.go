@@ -44,7 +44,8 @@ func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView
 
 	sourceAccount, success := getInput(view, tx.Source)
 	if success.IsError() {
-		return result.Error("Failed to get the source account: %v", tx.Source.Address)
+		return result.Error("Failed to get the source account: %v", tx.Source.Address).
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	signBytes := tx.SignBytes(chainID)
@@ -131,6 +132,12 @@ func (exec *DepositStakeExecutor) sanityCheck(chainID string, view *st.StoreView
 			sourceAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientStake)
 	}
 
+	// The locked coins can be staked, but not spent on the fee
+	res = validateUnlockedCoins(view, sourceAccount, tx.Fee, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	return result.OK
 }
 
@@ -144,7 +151,7 @@ func (exec *DepositStakeExecutor) process(chainID string, view *st.StoreView, tr
 		return common.Hash{}, result.Error("Failed to get the source account")
 	}
 
-	if !chargeFee(sourceAccount, tx.Fee) {
+	if !chargeFee(view, sourceAccount, tx.Fee) {
 		return common.Hash{}, result.Error("Failed to charge transaction fee")
 	}
 
@@ -160,11 +167,25 @@ func (exec *DepositStakeExecutor) process(chainID string, view *st.StoreView, tr
 		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
 		stakeAmount := stake.ThetaWei
 		vcp := view.GetValidatorCandidatePool()
+
+		// Register the BLS key used to aggregate the votes of the validator. A later
+		// deposit with a new key replaces the registered one.
+		registerBLSKey := blockHeight >= common.HeightEnableValidatorVoteAggregation && !tx.BlsPubkey.IsEmpty()
+		if registerBLSKey {
+			checkBLSRes := exec.checkBLSSummary(tx)
+			if checkBLSRes.IsError() {
+				return common.Hash{}, checkBLSRes
+			}
+		}
+
 		err := vcp.DepositStake(sourceAddress, holderAddress, stakeAmount)
 		if err != nil {
 			return common.Hash{}, result.Error("Failed to deposit stake, err: %v", err)
 		}
 		view.UpdateValidatorCandidatePool(vcp)
+		if registerBLSKey {
+			view.SetValidatorBLSPubkey(holderAddress, tx.BlsPubkey)
+		}
 	} else if tx.Purpose == core.StakeForGuardian {
 		sourceAccount.Balance = sourceAccount.Balance.Minus(stake)
 		stakeAmount := stake.ThetaWei
@@ -231,7 +252,7 @@ func (exec *DepositStakeExecutor) checkBLSSummary(tx *types.DepositStakeTxV2) re
 		return result.Error("Must provide Holder Signature")
 	}
 
-	if !tx.HolderSig.Verify(tx.BlsPop.ToBytes(), tx.Holder.Address) {
+	if !verifySignature(tx.HolderSig, tx.BlsPop.ToBytes(), tx.Holder.Address) {
 		return result.Error("BLS key info is not properly signed")
 	}
 
@@ -40,7 +40,8 @@ func (exec *ReleaseFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 	// Get input account
 	sourceAccount, success := getInput(view, tx.Source)
 	if success.IsError() {
-		return result.Error("Unknown address: %v", tx.Source.Address)
+		return result.Error("Unknown address: %v", tx.Source.Address).
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	// Validate input, advanced
@@ -63,6 +64,11 @@ func (exec *ReleaseFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 			sourceAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
 	}
 
+	res = validateUnlockedCoins(view, sourceAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	currentBlockHeight := exec.state.Height()
 	reserveSequence := tx.ReserveSequence
 	err := sourceAccount.CheckReleaseFund(currentBlockHeight, reserveSequence)
@@ -89,7 +95,7 @@ func (exec *ReleaseFundTxExecutor) process(chainID string, view *st.StoreView, t
 
 	currentBlockHeight := exec.state.Height()
 	sourceAccount.ReleaseFund(currentBlockHeight, reserveSequence)
-	if !chargeFee(sourceAccount, tx.Fee) {
+	if !chargeFee(view, sourceAccount, tx.Fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
 
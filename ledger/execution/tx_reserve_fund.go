@@ -40,7 +40,8 @@ func (exec *ReserveFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 	// Get input account
 	sourceAccount, success := getInput(view, tx.Source)
 	if success.IsError() {
-		return result.Error("Failed to get the source account: %v", tx.Source.Address)
+		return result.Error("Failed to get the source account: %v", tx.Source.Address).
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	// Validate input, advanced
@@ -80,6 +81,11 @@ func (exec *ReserveFundTxExecutor) sanityCheck(chainID string, view *st.StoreVie
 			sourceAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
 	}
 
+	res = validateUnlockedCoins(view, sourceAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	err := sourceAccount.CheckReserveFund(collateral, fund, duration, reserveSequence)
 	if err != nil {
 		return result.Error(err.Error()).WithErrorCode(result.CodeReserveFundCheckFailed)
@@ -105,7 +111,7 @@ func (exec *ReserveFundTxExecutor) process(chainID string, view *st.StoreView, t
 	endBlockHeight := exec.state.Height() + duration
 
 	sourceAccount.ReserveFund(collateral, fund, resourceIDs, endBlockHeight, reserveSequence)
-	if !chargeFee(sourceAccount, tx.Fee) {
+	if !chargeFee(view, sourceAccount, tx.Fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
 
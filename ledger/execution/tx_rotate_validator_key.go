@@ -0,0 +1,190 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+var _ TxExecutor = (*RotateValidatorKeyTxExecutor)(nil)
+
+// ------------------------------- RotateValidatorKeyTx Transaction -----------------------------------
+
+// RotateValidatorKeyTxExecutor implements the TxExecutor interface
+type RotateValidatorKeyTxExecutor struct {
+	state *st.LedgerState
+}
+
+// NewRotateValidatorKeyTxExecutor creates a new instance of RotateValidatorKeyTxExecutor
+func NewRotateValidatorKeyTxExecutor(state *st.LedgerState) *RotateValidatorKeyTxExecutor {
+	return &RotateValidatorKeyTxExecutor{
+		state: state,
+	}
+}
+
+func (exec *RotateValidatorKeyTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	tx := transaction.(*types.RotateValidatorKeyTx)
+
+	// Validate holder, basic
+	res := tx.Holder.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	// Get holder account
+	holderAccount, success := getInput(view, tx.Holder)
+	if success.IsError() {
+		return result.Error("Failed to get the holder account: %v", tx.Holder.Address).
+			WithErrorCode(result.CodeUnknownAccount)
+	}
+
+	// Validate holder, advanced
+	signBytes := tx.SignBytes(chainID)
+	res = validateInputAdvanced(holderAccount, signBytes, tx.Holder, blockHeight)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Holder.Address.Hex(), res))
+		return res
+	}
+
+	holderAddress := tx.Holder.Address
+	vcp := view.GetValidatorCandidatePool()
+	if vcp == nil || vcp.FindStakeDelegate(holderAddress) == nil {
+		return result.Error("%v is not a validator stake holder", holderAddress.Hex()).
+			WithErrorCode(result.CodeNotValidatorStakeHolder)
+	}
+
+	res = exec.checkSigningKey(chainID, view, vcp, tx)
+	if res.IsError() {
+		return res
+	}
+
+	if tx.ActivationHeight < blockHeight+types.ValidatorKeyRotationMinDelay {
+		return result.Error("Activation height %v must be at least %v blocks after the current block height %v",
+			tx.ActivationHeight, types.ValidatorKeyRotationMinDelay, blockHeight).WithErrorCode(result.CodeInvalidActivationHeight)
+	}
+
+	if key := view.GetValidatorSigningKey(holderAddress); key != nil && key.IsPending(blockHeight) {
+		return result.Error("The signing key rotation to %v is pending until block height %v",
+			key.Address.Hex(), key.ActivationHeight).WithErrorCode(result.CodeSigningKeyRotationPending)
+	}
+
+	if minTxFee, success := sanityCheckForFee(tx.Fee, blockHeight); !success {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v TFuelWei",
+			minTxFee).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	if !holderAccount.Balance.IsGTE(tx.Fee) {
+		return result.Error("Insufficient fund: Holder balance is %v, but the fee is %v",
+			holderAccount.Balance, tx.Fee).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	res = validateUnlockedCoins(view, holderAccount, tx.Fee, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
+	return result.OK
+}
+
+// checkSigningKey checks the possession of the new signing key, and that the key is not used by other validators
+func (exec *RotateValidatorKeyTxExecutor) checkSigningKey(chainID string, view *st.StoreView,
+	vcp *core.ValidatorCandidatePool, tx *types.RotateValidatorKeyTx) result.Result {
+	if tx.SigningAddress.IsEmpty() {
+		return result.Error("Must provide the signing address").WithErrorCode(result.CodeInvalidSigningKey)
+	}
+	if tx.SigningSig == nil || tx.SigningSig.IsEmpty() {
+		return result.Error("Must provide the signature of the signing key").WithErrorCode(result.CodeInvalidSigningKey)
+	}
+	if !verifySignature(tx.SigningSig, tx.SigningKeySignBytes(chainID), tx.SigningAddress) {
+		return result.Error("Signature of the signing key %v is invalid", tx.SigningAddress.Hex()).
+			WithErrorCode(result.CodeInvalidSigningKey)
+	}
+
+	for _, candidate := range vcp.SortedCandidates {
+		if candidate.Holder == tx.Holder.Address {
+			continue
+		}
+		inUse := candidate.Holder == tx.SigningAddress
+		if key := view.GetValidatorSigningKey(candidate.Holder); key != nil {
+			inUse = inUse || key.Address == tx.SigningAddress || key.PrevAddress == tx.SigningAddress
+		}
+		if inUse {
+			return result.Error("Signing key %v is already used by validator %v",
+				tx.SigningAddress.Hex(), candidate.Holder.Hex()).WithErrorCode(result.CodeInvalidSigningKey)
+		}
+	}
+
+	if !tx.BlsPubkey.IsEmpty() {
+		if tx.BlsPop.IsEmpty() {
+			return result.Error("Must provide BLS POP").WithErrorCode(result.CodeInvalidSigningKey)
+		}
+		if !tx.BlsPop.PopVerify(tx.BlsPubkey) {
+			return result.Error("BLS pop is invalid").WithErrorCode(result.CodeInvalidSigningKey)
+		}
+	}
+
+	return result.OK
+}
+
+func (exec *RotateValidatorKeyTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	tx := transaction.(*types.RotateValidatorKeyTx)
+
+	holderAddress := tx.Holder.Address
+	holderAccount, success := getInput(view, tx.Holder)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the holder account")
+	}
+
+	if !chargeFee(view, holderAccount, tx.Fee) {
+		return common.Hash{}, result.Error("failed to charge transaction fee")
+	}
+
+	holderAccount.Sequence++
+	view.SetAccount(holderAddress, holderAccount)
+
+	// The key in use until the activation height
+	prevAddress := holderAddress
+	prevBlsPubkey := view.GetValidatorBLSPubkey(holderAddress)
+	if prev := view.GetValidatorSigningKey(holderAddress); prev != nil {
+		prevAddress = prev.SigningAddress(blockHeight)
+		prevBlsPubkey = prev.BLSPubkey(blockHeight)
+	}
+
+	key := &types.ValidatorSigningKey{
+		Address:          tx.SigningAddress,
+		ActivationHeight: tx.ActivationHeight,
+		PrevAddress:      prevAddress,
+		PrevBlsPubkey:    prevBlsPubkey,
+	}
+	if !tx.BlsPubkey.IsEmpty() {
+		key.BlsPubkey = tx.BlsPubkey
+	}
+	view.SetValidatorSigningKey(holderAddress, key)
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *RotateValidatorKeyTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.RotateValidatorKeyTx)
+	return &core.TxInfo{
+		Address:           tx.Holder.Address,
+		Sequence:          tx.Holder.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *RotateValidatorKeyTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.RotateValidatorKeyTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(getRegularTxGas(exec.state))
+	effectiveGasPrice := new(big.Int).Div(fee.TFuelWei, gas)
+	return effectiveGasPrice
+}
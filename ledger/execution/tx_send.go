@@ -41,13 +41,14 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 	}
 
 	if len(tx.Inputs) == 0 || len(tx.Outputs) == 0 {
-		return result.Error("Invalid sendTx, Inputs and/or Outputs are empty")
+		return result.Error("Invalid sendTx, Inputs and/or Outputs are empty").
+			WithErrorCode(result.CodeInvalidTxAmount)
 	}
 
 	numAccountsAffected := uint64(len(tx.Inputs) + len(tx.Outputs))
 	if numAccountsAffected > types.MaxAccountsAffectedPerTx {
 		return result.Error("Trasaction modifying too many accounts. At most %v accounts are allowed per transaction",
-			types.MaxAccountsAffectedPerTx)
+			types.MaxAccountsAffectedPerTx).WithErrorCode(result.CodeTooManyAccounts)
 	}
 
 	// Get inputs
@@ -78,6 +79,12 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 	if res.IsError() {
 		return res
 	}
+	for _, in := range tx.Inputs {
+		res = validateUnlockedCoins(view, accounts[string(in.Address[:])], in.Coins, blockHeight)
+		if res.IsError() {
+			return res
+		}
+	}
 
 	if minTxFee, success := sanityCheckForSendTxFee(tx.Fee, numAccountsAffected, blockHeight); !success {
 		return result.Error("Insufficient fee. Transaction fee needs to be at least %v TFuelWei",
@@ -88,7 +95,8 @@ func (exec *SendTxExecutor) sanityCheck(chainID string, view *st.StoreView, tran
 	outPlusFees := outTotal
 	outPlusFees = outTotal.Plus(tx.Fee)
 	if !inTotal.IsEqual(outPlusFees) {
-		return result.Error("Input total (%v) != output total + fees (%v)", inTotal, outPlusFees)
+		return result.Error("Input total (%v) != output total + fees (%v)", inTotal, outPlusFees).
+			WithErrorCode(result.CodeInvalidTxAmount)
 	}
 
 	return result.OK
@@ -109,6 +117,7 @@ func (exec *SendTxExecutor) process(chainID string, view *st.StoreView, transact
 
 	adjustByInputs(view, accounts, tx.Inputs)
 	adjustByOutputs(view, accounts, tx.Outputs)
+	view.RecordBurnedFees(tx.Fee.NoNil().TFuelWei) // the inputs exceed the outputs by the fee
 
 	txHash := types.TxID(chainID, tx)
 	return txHash, result.OK
@@ -64,14 +64,14 @@ func (exec *ServicePaymentTxExecutor) sanityCheck(chainID string, view *st.Store
 
 	// Verify source
 	sourceSignBytes := tx.SourceSignBytes(chainID)
-	if !tx.Source.Signature.Verify(sourceSignBytes, sourceAccount.Address) {
+	if !verifySignature(tx.Source.Signature, sourceSignBytes, sourceAccount.Address) {
 		errMsg := fmt.Sprintf("sanityCheckForServicePaymentTx failed on source signature, addr: %v", sourceAddress.Hex())
 		logger.Infof(errMsg)
 		return result.Error(errMsg)
 	}
 
 	targetSignBytes := tx.TargetSignBytes(chainID)
-	if !tx.Target.Signature.Verify(targetSignBytes, targetAccount.Address) {
+	if !verifySignature(tx.Target.Signature, targetSignBytes, targetAccount.Address) {
 		errMsg := fmt.Sprintf("sanityCheckForServicePaymentTx failed on target signature, addr: %v", targetAddress.Hex())
 		logger.Infof(errMsg)
 		return result.Error(errMsg)
@@ -83,6 +83,11 @@ func (exec *ServicePaymentTxExecutor) sanityCheck(chainID string, view *st.Store
 			minTxFee).WithErrorCode(result.CodeInvalidFee)
 	}
 
+	res = validateUnlockedCoins(view, targetAccount, tx.Fee, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	transferAmount := tx.Source.Coins
 	currentBlockHeight := view.Height()
 	reserveSequence := tx.ReserveSequence
@@ -143,7 +148,7 @@ func (exec *ServicePaymentTxExecutor) process(chainID string, view *st.StoreView
 	if shouldSlash {
 		//view.AddSlashIntent(slashIntent)
 	}
-	if !chargeFee(targetAccount, tx.Fee) {
+	if !chargeFee(view, targetAccount, tx.Fee) {
 		// should charge after transfer the fund, so an empty address has some fund to pay the tx fee
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
@@ -171,7 +176,9 @@ func (exec *ServicePaymentTxExecutor) splitPayment(view *st.StoreView, splitRule
 	// the splitRule has expired, full payment goes to the target account. also delete the splitRule
 	if exec.state.Height() > splitRule.EndBlockHeight {
 		addressCoinsMap[targetAddress] = fullAmount
-		view.DeleteSplitRule(resourceID)
+		if view.DeleteSplitRule(resourceID) {
+			view.AddLapsedSplitRule(splitRule)
+		}
 		return true, addressCoinsMap
 	}
 
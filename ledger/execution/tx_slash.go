@@ -52,7 +52,7 @@ func (exec *SlashTxExecutor) sanityCheck(chainID string, view *st.StoreView, tra
 
 	// verify the proposer's signature
 	signBytes := tx.SignBytes(chainID)
-	if !tx.Proposer.Signature.Verify(signBytes, proposerAccount.Address) {
+	if !verifySignature(tx.Proposer.Signature, signBytes, proposerAccount.Address) {
 		return result.Error("SignBytes: %X", signBytes)
 	}
 
@@ -166,7 +166,7 @@ func (exec *SlashTxExecutor) verifySlashProof(chainID string, slashedAccount *ty
 			}
 
 			sourceSignedBytes := servicePaymentTx.SourceSignBytes(chainID)
-			if !servicePaymentTx.Source.Signature.Verify(sourceSignedBytes, slashedAccount.Address) {
+			if !verifySignature(servicePaymentTx.Source.Signature, sourceSignedBytes, slashedAccount.Address) {
 				return false // servicePaymentTx not signed by the slashed account
 			}
 
@@ -23,6 +23,9 @@ var _ TxExecutor = (*SmartContractTxExecutor)(nil)
 type SmartContractTxExecutor struct {
 	state *st.LedgerState
 	chain *blockchain.Chain
+
+	pendingReceipts    []*blockchain.TxReceiptEntry   // receipts of the delivered txs, saved when the block is committed
+	pendingInternalTxs []*blockchain.InternalTxsEntry // internal txs of the delivered txs, saved when the block is committed
 }
 
 // NewSmartContractTxExecutor creates a new instance of SmartContractTxExecutor
@@ -45,10 +48,10 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 
 	// Check signatures
 	signBytes := tx.SignBytes(chainID)
-	nativeSignatureValid := tx.From.Signature.Verify(signBytes, tx.From.Address)
+	nativeSignatureValid := verifySignature(tx.From.Signature, signBytes, tx.From.Address)
 	if blockHeight >= common.HeightTxWrapperExtension {
 		signBytesV2 := types.ChangeEthereumTxWrapper(signBytes, 2)
-		nativeSignatureValid = nativeSignatureValid || tx.From.Signature.Verify(signBytesV2, tx.From.Address)
+		nativeSignatureValid = nativeSignatureValid || verifySignature(tx.From.Signature, signBytesV2, tx.From.Address)
 	}
 
 	if !nativeSignatureValid {
@@ -73,7 +76,8 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 	// Get input account
 	fromAccount, success := getInput(view, tx.From)
 	if success.IsError() {
-		return result.Error("Failed to get the account (the address has no Theta nor TFuel)")
+		return result.Error("Failed to get the account (the address has no Theta nor TFuel)").
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	// Validate input, advanced
@@ -103,6 +107,14 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 			WithErrorCode(result.CodeInvalidGasPrice)
 	}
 
+	if blockHeight >= common.HeightEnableBaseFee {
+		baseFee := view.GetBaseFee(blockHeight)
+		if tx.GasPrice.Cmp(baseFee) < 0 {
+			return result.Error("Insufficient gas price. Gas price needs to be at least the base fee %v TFuelWei", baseFee).
+				WithErrorCode(result.CodeInvalidGasPrice)
+		}
+	}
+
 	maxGasLimit := types.GetMaxGasLimit(blockHeight)
 	if new(big.Int).SetUint64(tx.GasLimit).Cmp(maxGasLimit) > 0 {
 		return result.Error("Invalid gas limit. Gas limit needs to be at most %v", maxGasLimit).
@@ -139,6 +151,11 @@ func (exec *SmartContractTxExecutor) sanityCheck(chainID string, view *st.StoreV
 			fromAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
 	}
 
+	res = validateUnlockedCoins(view, fromAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	return result.OK
 }
 
@@ -146,6 +163,7 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 	tx := transaction.(*types.SmartContractTx)
 
 	view.ResetLogs()
+	view.ResetInternalTxs()
 
 	// Note: for contract deployment, vm.Execute() might transfer coins from the fromAccount to the
 	//       deployed smart contract. Thus, we should call vm.Execute() before calling getInput().
@@ -163,7 +181,7 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 		ThetaWei: big.NewInt(int64(0)),
 		TFuelWei: feeAmount,
 	}
-	if !chargeFee(fromAccount, fee) {
+	if !chargeFee(view, fromAccount, fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
 
@@ -172,6 +190,7 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 		fromAccount.Sequence++
 	}
 	view.SetAccount(fromAddress, fromAccount)
+	view.RecordGasUsed(gasUsed)
 
 	txHash := types.TxID(chainID, tx)
 
@@ -181,11 +200,35 @@ func (exec *SmartContractTxExecutor) process(chainID string, view *st.StoreView,
 		// Do not record events if transaction is reverted
 		logs = nil
 	}
-	exec.chain.AddTxReceipt(tx, logs, evmRet, contractAddr, gasUsed, evmErr)
+	internalTxs := view.PopInternalTxs()
+	if view == exec.state.Delivered() {
+		receipt := blockchain.NewTxReceiptEntry(tx, logs, evmRet, contractAddr, gasUsed, evmErr)
+		exec.pendingReceipts = append(exec.pendingReceipts, receipt)
+		if len(internalTxs) > 0 {
+			exec.pendingInternalTxs = append(exec.pendingInternalTxs, &blockchain.InternalTxsEntry{
+				TxHash:      receipt.TxHash,
+				InternalTxs: internalTxs,
+			})
+		}
+	}
 
 	return txHash, result.OK
 }
 
+// popPendingReceipts returns and clears the receipts of the delivered txs
+func (exec *SmartContractTxExecutor) popPendingReceipts() []*blockchain.TxReceiptEntry {
+	receipts := exec.pendingReceipts
+	exec.pendingReceipts = nil
+	return receipts
+}
+
+// popPendingInternalTxs returns and clears the internal txs of the delivered txs
+func (exec *SmartContractTxExecutor) popPendingInternalTxs() []*blockchain.InternalTxsEntry {
+	internalTxs := exec.pendingInternalTxs
+	exec.pendingInternalTxs = nil
+	return internalTxs
+}
+
 func (exec *SmartContractTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
 	tx := transaction.(*types.SmartContractTx)
 	return &core.TxInfo{
@@ -57,13 +57,19 @@ func (exec *SplitRuleTxExecutor) sanityCheck(chainID string, view *st.StoreView,
 	minimalBalance := tx.Fee
 	if !initiatorAccount.Balance.IsGTE(minimalBalance) {
 		logger.Infof(fmt.Sprintf("the contract initiator did not have enough to cover the fee %X", tx.Initiator.Address))
-		return result.Error("the contract initiator account balance is %v, but required minimal balance is %v", initiatorAccount.Balance, minimalBalance)
+		return result.Error("the contract initiator account balance is %v, but required minimal balance is %v", initiatorAccount.Balance, minimalBalance).
+			WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	res = validateUnlockedCoins(view, initiatorAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
 	}
 
 	numAccountsAffected := len(tx.Splits) + 1
 	if numAccountsAffected > types.MaxAccountsAffectedPerTx {
 		return result.Error("This allows one trasaction to modify many accounts. At most %v accounts are allowed per transaction.",
-			types.MaxAccountsAffectedPerTx)
+			types.MaxAccountsAffectedPerTx).WithErrorCode(result.CodeTooManyAccounts)
 	}
 
 	totalPercentage := uint(0)
@@ -102,7 +108,7 @@ func (exec *SplitRuleTxExecutor) process(chainID string, view *st.StoreView, tra
 		return common.Hash{}, res
 	}
 
-	if !chargeFee(initiatorAccount, tx.Fee) {
+	if !chargeFee(view, initiatorAccount, tx.Fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
 
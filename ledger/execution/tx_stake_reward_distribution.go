@@ -107,7 +107,13 @@ func (exec *StakeRewardDistributionTxExecutor) sanityCheck(chainID string, view
 	minimalBalance := tx.Fee
 	if !stakeHolderAccount.Balance.IsGTE(minimalBalance) {
 		logger.Infof(fmt.Sprintf("the contract initiator did not have enough to cover the fee %X", tx.Holder.Address))
-		return result.Error("the contract initiator account balance is %v, but required minimal balance is %v", stakeHolderAccount.Balance, minimalBalance)
+		return result.Error("the contract initiator account balance is %v, but required minimal balance is %v", stakeHolderAccount.Balance, minimalBalance).
+			WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	res = validateUnlockedCoins(view, stakeHolderAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
 	}
 
 	return result.OK
@@ -121,7 +127,7 @@ func (exec *StakeRewardDistributionTxExecutor) process(chainID string, view *st.
 		return common.Hash{}, res
 	}
 
-	if !chargeFee(stakeHolderAccount, tx.Fee) {
+	if !chargeFee(view, stakeHolderAccount, tx.Fee) {
 		return common.Hash{}, result.Error("failed to charge transaction fee")
 	}
 
@@ -0,0 +1,163 @@
+package execution
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+var _ TxExecutor = (*VestingTxExecutor)(nil)
+
+// ------------------------------- VestingTx Transaction -----------------------------------
+
+// VestingTxExecutor implements the TxExecutor interface
+type VestingTxExecutor struct {
+	state *st.LedgerState
+}
+
+// NewVestingTxExecutor creates a new instance of VestingTxExecutor
+func NewVestingTxExecutor(state *st.LedgerState) *VestingTxExecutor {
+	return &VestingTxExecutor{
+		state: state,
+	}
+}
+
+func (exec *VestingTxExecutor) sanityCheck(chainID string, view *st.StoreView, transaction types.Tx) result.Result {
+	blockHeight := view.Height() + 1 // the view points to the parent of the current block
+	tx := transaction.(*types.VestingTx)
+
+	// Validate source, basic
+	res := tx.Source.ValidateBasic()
+	if res.IsError() {
+		return res
+	}
+
+	// Validate beneficiary, basic
+	res = validateOutputsBasic([]types.TxOutput{tx.Beneficiary})
+	if res.IsError() {
+		return res
+	}
+
+	// Get input account
+	sourceAccount, success := getInput(view, tx.Source)
+	if success.IsError() {
+		return result.Error("Failed to get the source account: %v", tx.Source.Address).
+			WithErrorCode(result.CodeUnknownAccount)
+	}
+
+	// Validate input, advanced
+	signBytes := tx.SignBytes(chainID)
+	res = validateInputAdvanced(sourceAccount, signBytes, tx.Source, blockHeight)
+	if res.IsError() {
+		logger.Debugf(fmt.Sprintf("validateSourceAdvanced failed on %v: %v", tx.Source.Address.Hex(), res))
+		return res
+	}
+
+	coins := tx.Source.Coins.NoNil()
+	if !tx.Beneficiary.Coins.NoNil().IsEqual(coins) {
+		return result.Error("Beneficiary coins %v do not match the source coins %v", tx.Beneficiary.Coins, coins).
+			WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	if err := tx.Schedule().Validate(); err != nil {
+		return result.Error(err.Error()).WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	minVestingWei := new(big.Int).SetUint64(types.MinVestingWei)
+	if coins.ThetaWei.Cmp(minVestingWei) < 0 && coins.TFuelWei.Cmp(minVestingWei) < 0 {
+		return result.Error("Vesting coins %v must include at least %v ThetaWei or TFuelWei", coins, minVestingWei).
+			WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	// The smart contracts could move the locked coins by the value transfers in the EVM
+	if len(view.GetCode(tx.Beneficiary.Address)) > 0 {
+		return result.Error("Vesting beneficiary %v cannot be a smart contract", tx.Beneficiary.Address.Hex()).
+			WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	numActiveSchedules := 0
+	for _, vs := range view.GetVestingSchedules(tx.Beneficiary.Address) {
+		if vs.EndHeight > view.Height() { // the ended schedules are removed when the schedule is added
+			numActiveSchedules++
+		}
+	}
+	if numActiveSchedules >= types.MaxVestingSchedulesPerAccount {
+		return result.Error("Vesting beneficiary %v already has %v active vesting schedules",
+			tx.Beneficiary.Address.Hex(), numActiveSchedules).WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	if tx.EndHeight <= blockHeight {
+		return result.Error("Vesting end height %v must be greater than the current block height %v",
+			tx.EndHeight, blockHeight).WithErrorCode(result.CodeInvalidVestingSchedule)
+	}
+
+	if minTxFee, success := sanityCheckForFee(tx.Fee, blockHeight); !success {
+		return result.Error("Insufficient fee. Transaction fee needs to be at least %v TFuelWei",
+			minTxFee).WithErrorCode(result.CodeInvalidFee)
+	}
+
+	minimalBalance := coins.Plus(tx.Fee)
+	if !sourceAccount.Balance.IsGTE(minimalBalance) {
+		logger.Infof(fmt.Sprintf("Vesting: Source did not have enough balance %v", tx.Source.Address.Hex()))
+		return result.Error("Insufficient fund: Source balance is %v, but required minimal balance is %v",
+			sourceAccount.Balance, minimalBalance).WithErrorCode(result.CodeInsufficientFund)
+	}
+
+	res = validateUnlockedCoins(view, sourceAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
+	return result.OK
+}
+
+func (exec *VestingTxExecutor) process(chainID string, view *st.StoreView, transaction types.Tx) (common.Hash, result.Result) {
+	tx := transaction.(*types.VestingTx)
+
+	sourceAddress := tx.Source.Address
+	sourceAccount, success := getInput(view, tx.Source)
+	if success.IsError() {
+		return common.Hash{}, result.Error("Failed to get the source account")
+	}
+
+	if !chargeFee(view, sourceAccount, tx.Fee) {
+		return common.Hash{}, result.Error("failed to charge transaction fee")
+	}
+
+	coins := tx.Source.Coins.NoNil()
+	sourceAccount.Balance = sourceAccount.Balance.Minus(coins)
+	sourceAccount.Sequence++
+	view.SetAccount(sourceAddress, sourceAccount)
+
+	beneficiaryAddress := tx.Beneficiary.Address
+	beneficiaryAccount := getOrMakeAccount(view, beneficiaryAddress)
+	beneficiaryAccount.Balance = beneficiaryAccount.Balance.Plus(coins)
+	view.SetAccount(beneficiaryAddress, beneficiaryAccount)
+
+	view.AddVestingSchedule(beneficiaryAddress, tx.Schedule(), view.Height())
+
+	txHash := types.TxID(chainID, tx)
+	return txHash, result.OK
+}
+
+func (exec *VestingTxExecutor) getTxInfo(transaction types.Tx) *core.TxInfo {
+	tx := transaction.(*types.VestingTx)
+	return &core.TxInfo{
+		Address:           tx.Source.Address,
+		Sequence:          tx.Source.Sequence,
+		EffectiveGasPrice: exec.calculateEffectiveGasPrice(transaction),
+	}
+}
+
+func (exec *VestingTxExecutor) calculateEffectiveGasPrice(transaction types.Tx) *big.Int {
+	tx := transaction.(*types.VestingTx)
+	fee := tx.Fee
+	gas := new(big.Int).SetUint64(getRegularTxGas(exec.state))
+	effectiveGasPrice := new(big.Int).Div(fee.TFuelWei, gas)
+	return effectiveGasPrice
+}
@@ -39,7 +39,8 @@ func (exec *WithdrawStakeExecutor) sanityCheck(chainID string, view *st.StoreVie
 
 	sourceAccount, success := getInput(view, tx.Source)
 	if success.IsError() {
-		return result.Error("Failed to get the source account: %v", tx.Source.Address)
+		return result.Error("Failed to get the source account: %v", tx.Source.Address).
+			WithErrorCode(result.CodeUnknownAccount)
 	}
 
 	signBytes := tx.SignBytes(chainID)
@@ -66,6 +67,11 @@ func (exec *WithdrawStakeExecutor) sanityCheck(chainID string, view *st.StoreVie
 			sourceAccount.Balance, minimalBalance)
 	}
 
+	res = validateUnlockedCoins(view, sourceAccount, minimalBalance, blockHeight)
+	if res.IsError() {
+		return res
+	}
+
 	return result.OK
 }
 
@@ -80,7 +86,7 @@ func (exec *WithdrawStakeExecutor) process(chainID string, view *st.StoreView, t
 		return common.Hash{}, result.Error("Failed to get the source account")
 	}
 
-	if !chargeFee(sourceAccount, tx.Fee) {
+	if !chargeFee(view, sourceAccount, tx.Fee) {
 		return common.Hash{}, result.Error("Failed to charge transaction fee")
 	}
 
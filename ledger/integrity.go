@@ -0,0 +1,161 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/metrics"
+	"github.com/thetatoken/theta/crypto"
+	st "github.com/thetatoken/theta/ledger/state"
+)
+
+var (
+	integrityCheckCounter   = metrics.NewRegisteredCounter("ledger/integrity/checks", nil)
+	integrityFailureCounter = metrics.NewRegisteredCounter("ledger/integrity/failures", nil)
+	integrityAlertGauge     = metrics.NewRegisteredGauge("ledger/integrity/alert", nil) // 1 if the last check failed
+)
+
+//
+// IntegrityChecker periodically verifies the finalized state, so that a silent corruption of the
+// database is detected before it surfaces as a consensus divergence. Each check walks a random
+// sample of the state trie and verifies the hashes of the nodes against their content, and sums
+// up the Theta held by the accounts and staked in the validator and guardian candidate pools. The
+// Theta supply never changes, so the total must match the one found by the first check.
+//
+type IntegrityChecker struct {
+	ledger     *Ledger
+	interval   time.Duration
+	sampleSize int
+	rand       *rand.Rand
+
+	thetaSupply *big.Int // The Theta supply found by the first check
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewIntegrityChecker creates an IntegrityChecker of the ledger state with the configured interval
+// and sample size
+func NewIntegrityChecker(ledger *Ledger) *IntegrityChecker {
+	return &IntegrityChecker{
+		ledger:     ledger,
+		interval:   time.Duration(viper.GetInt(common.CfgLedgerIntegrityCheckInterval)) * time.Second,
+		sampleSize: viper.GetInt(common.CfgLedgerIntegrityCheckSampleSize),
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		wg:         &sync.WaitGroup{},
+	}
+}
+
+// Start starts the periodic checks
+func (ic *IntegrityChecker) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	ic.ctx = c
+	ic.cancel = cancel
+
+	ic.wg.Add(1)
+	go ic.mainLoop()
+}
+
+// Stop stops the periodic checks
+func (ic *IntegrityChecker) Stop() {
+	ic.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (ic *IntegrityChecker) Wait() {
+	ic.wg.Wait()
+}
+
+func (ic *IntegrityChecker) mainLoop() {
+	defer ic.wg.Done()
+
+	ticker := time.NewTicker(ic.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ic.ctx.Done():
+			return
+		case <-ticker.C:
+			ic.Check()
+		}
+	}
+}
+
+// Check verifies the finalized state once, and raises the alert metric on mismatch
+func (ic *IntegrityChecker) Check() error {
+	integrityCheckCounter.Inc(1)
+
+	err := ic.check()
+	if err != nil {
+		integrityFailureCounter.Inc(1)
+		integrityAlertGauge.Update(1)
+		logger.Errorf("State integrity check failed: %v", err)
+		return err
+	}
+	integrityAlertGauge.Update(0)
+	return nil
+}
+
+func (ic *IntegrityChecker) check() error {
+	view, err := ic.ledger.GetFinalizedSnapshot()
+	if err != nil {
+		return fmt.Errorf("Failed to get the finalized state: %v", err)
+	}
+
+	var address common.Address
+	ic.rand.Read(address[:])
+	numNodes, err := verifyTrieNodes(view, st.AccountKey(address), ic.sampleSize)
+	if err != nil {
+		return fmt.Errorf("Corrupted state trie at height %v, root %v: %v", view.Height(), view.Hash().Hex(), err)
+	}
+
+	supply, err := CalculateSupply(view)
+	if err != nil {
+		return fmt.Errorf("Failed to calculate the supply at height %v: %v", view.Height(), err)
+	}
+	thetaSupply := supply.ThetaWei
+	if ic.thetaSupply == nil {
+		ic.thetaSupply = thetaSupply
+	} else if ic.thetaSupply.Cmp(thetaSupply) != 0 {
+		return fmt.Errorf("Theta supply mismatch at height %v: expected = %v, calculated = %v", view.Height(), ic.thetaSupply, thetaSupply)
+	}
+
+	logger.Infof("State integrity check passed, height: %v, root: %v, verified nodes: %v, Theta supply: %v",
+		view.Height(), view.Hash().Hex(), numNodes, thetaSupply)
+	return nil
+}
+
+// verifyTrieNodes walks up to maxNodes nodes of the state trie from the given key, and checks the
+// hash of each node against its content. It returns the number of the verified nodes.
+func verifyTrieNodes(view *st.StoreView, start common.Bytes, maxNodes int) (int, error) {
+	trie := view.GetStore().Trie
+	it := trie.NodeIterator(start)
+	numNodes := 0
+	for numNodes < maxNodes && it.Next(true) {
+		hash := it.Hash()
+		if hash.IsEmpty() {
+			continue // the node is embedded in its parent, or is a value
+		}
+		blob, err := trie.GetDB().Node(hash)
+		if err != nil {
+			return numNodes, fmt.Errorf("Missing trie node %v: %v", hash.Hex(), err)
+		}
+		if crypto.Keccak256Hash(blob) != hash {
+			return numNodes, fmt.Errorf("Hash mismatch of trie node %v", hash.Hex())
+		}
+		numNodes++
+	}
+	if err := it.Error(); err != nil {
+		return numNodes, err
+	}
+	return numNodes, nil
+}
@@ -0,0 +1,24 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyTrieNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ledger, _ := newTestLedger()
+	prepareInitLedgerState(ledger, 20)
+
+	view := ledger.state.Delivered()
+	numNodes, err := verifyTrieNodes(view, nil, 10000)
+	assert.Nil(err)
+	assert.True(numNodes > 0)
+
+	// The walk stops at the sample size
+	numNodes, err = verifyTrieNodes(view, nil, 1)
+	assert.Nil(err)
+	assert.Equal(1, numNodes)
+}
@@ -0,0 +1,266 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"sort"
+
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/metrics"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store/database"
+)
+
+var invariantCheckCounter = metrics.NewRegisteredCounter("ledger/invariants/checks", nil)
+
+// The invariants of the state transitions
+const (
+	InvariantNonNegativeBalance  = "non_negative_balance"
+	InvariantSequenceMonotonic   = "sequence_monotonic"
+	InvariantStakeConservation   = "stake_conservation"
+	InvariantStakePoolConsistent = "stake_pool_consistent"
+)
+
+// InvariantViolation describes a state transition that breaks an invariant
+type InvariantViolation struct {
+	Invariant string `json:"invariant"`
+	Detail    string `json:"detail"`
+}
+
+// accountChange is the state of an account before and after a block, nil if the account does not exist
+type accountChange struct {
+	Address common.Address `json:"address"`
+	Before  *types.Account `json:"before"`
+	After   *types.Account `json:"after"`
+}
+
+// invariantViolationDump is logged and saved when the invariants are violated
+type invariantViolationDump struct {
+	BlockHeight     uint64               `json:"block_height"`
+	BlockHash       common.Hash          `json:"block_hash"`
+	ParentStateRoot common.Hash          `json:"parent_state_root"`
+	StateRoot       common.Hash          `json:"state_root"`
+	Violations      []InvariantViolation `json:"violations"`
+	Accounts        []accountChange      `json:"accounts"`
+	ValidatorPool   *stakePoolChange     `json:"validator_candidate_pool,omitempty"`
+	GuardianPool    *stakePoolChange     `json:"guardian_candidate_pool,omitempty"`
+}
+
+// stakePoolChange is the stake holders of a pool before and after a block
+type stakePoolChange struct {
+	Before []*core.StakeHolder `json:"before"`
+	After  []*core.StakeHolder `json:"after"`
+}
+
+//
+// InvariantChecker validates the state transition of each applied block before it is committed:
+// the balances of the accounts stay non-negative, the sequences of the accounts never decrease,
+// the Theta held by the accounts and staked in the validator and guardian candidate pools is
+// conserved, and the stakes in the pools are consistent with the accounts. Only the accounts and
+// the pools written by the block are checked. On a violation, the node halts with a dump of the
+// state transition, so that a state transition bug is caught before the block is finalized.
+//
+type InvariantChecker struct {
+	db      database.Database
+	dumpDir string // where the dumps are saved, no file is saved if empty
+}
+
+// NewInvariantChecker creates an InvariantChecker of the ledger state in the given database
+func NewInvariantChecker(db database.Database) *InvariantChecker {
+	return &InvariantChecker{
+		db:      db,
+		dumpDir: viper.GetString(common.CfgDataPath),
+	}
+}
+
+// CheckBlock validates the state transition of the block, where the view has recorded the writes
+// since the parent state. It panics with a dump if any invariant is violated.
+func (ic *InvariantChecker) CheckBlock(parentBlock, block *core.Block, view *st.StoreView) {
+	invariantCheckCounter.Inc(1)
+
+	parent := st.NewStoreView(parentBlock.Height, parentBlock.StateHash, ic.db)
+	dump := checkInvariants(parent, view)
+	if len(dump.Violations) == 0 {
+		return
+	}
+	dump.BlockHeight = block.Height
+	dump.BlockHash = block.Hash()
+	dump.ParentStateRoot = parentBlock.StateHash
+	dump.StateRoot = block.StateHash
+
+	raw, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		logger.Errorf("Failed to encode the invariant violation dump: %v", err)
+	} else if ic.dumpDir != "" {
+		filePath := path.Join(ic.dumpDir, fmt.Sprintf("invariant_violation_%v.json", block.Height))
+		if err := ioutil.WriteFile(filePath, raw, 0600); err != nil {
+			logger.Errorf("Failed to save the invariant violation dump %v: %v", filePath, err)
+		} else {
+			logger.Errorf("Saved the invariant violation dump to %v", filePath)
+		}
+	}
+	logger.Panicf("Ledger invariants violated by block %v at height %v: %v\n%s",
+		block.Hash().Hex(), block.Height, dump.Violations, raw)
+}
+
+// checkInvariants checks the transition from the parent view to the view, which has recorded the writes
+func checkInvariants(parent *st.StoreView, view *st.StoreView) *invariantViolationDump {
+	dump := &invariantViolationDump{
+		Violations: []InvariantViolation{},
+		Accounts:   []accountChange{},
+	}
+	violate := func(invariant string, format string, args ...interface{}) {
+		dump.Violations = append(dump.Violations, InvariantViolation{
+			Invariant: invariant,
+			Detail:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	accountPrefix := st.AccountKeyPrefix()
+	addresses := []common.Address{}
+	vcpWritten, gcpWritten := false, false
+	for key := range view.Writes() {
+		k := common.Bytes(key)
+		switch {
+		case bytes.HasPrefix(k, accountPrefix) && len(k) == len(accountPrefix)+common.AddressLength:
+			addresses = append(addresses, common.BytesToAddress(k[len(accountPrefix):]))
+		case bytes.Equal(k, st.ValidatorCandidatePoolKey()):
+			vcpWritten = true
+		case bytes.Equal(k, st.GuardianCandidatePoolKey()):
+			gcpWritten = true
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+
+	// The change of the Theta held by the accounts and staked in the pools
+	thetaDelta := new(big.Int)
+
+	for _, addr := range addresses {
+		before := parent.GetAccount(addr)
+		after := view.GetAccount(addr)
+		dump.Accounts = append(dump.Accounts, accountChange{Address: addr, Before: before, After: after})
+
+		if after != nil {
+			for _, coins := range accountHoldings(after) {
+				if coins.ThetaWei.Sign() < 0 || coins.TFuelWei.Sign() < 0 {
+					violate(InvariantNonNegativeBalance, "account %v holds negative coins %v", addr.Hex(), coins)
+				}
+			}
+		}
+		if before != nil && after != nil && after.Sequence < before.Sequence {
+			violate(InvariantSequenceMonotonic, "the sequence of account %v decreased from %v to %v", addr.Hex(), before.Sequence, after.Sequence)
+		}
+		thetaDelta.Add(thetaDelta, accountTheta(after))
+		thetaDelta.Sub(thetaDelta, accountTheta(before))
+	}
+
+	if vcpWritten {
+		change := &stakePoolChange{Before: []*core.StakeHolder{}, After: []*core.StakeHolder{}}
+		if vcp := parent.GetValidatorCandidatePool(); vcp != nil {
+			change.Before = vcp.SortedCandidates
+		}
+		if vcp := view.GetValidatorCandidatePool(); vcp != nil {
+			change.After = vcp.SortedCandidates
+		}
+		dump.ValidatorPool = change
+		thetaDelta.Add(thetaDelta, totalStake(change.After))
+		thetaDelta.Sub(thetaDelta, totalStake(change.Before))
+		for _, detail := range checkStakePool(view, "validator candidate", change.After) {
+			violate(InvariantStakePoolConsistent, "%v", detail)
+		}
+	}
+	if gcpWritten {
+		change := &stakePoolChange{
+			Before: guardianStakeHolders(parent.GetGuardianCandidatePool()),
+			After:  guardianStakeHolders(view.GetGuardianCandidatePool()),
+		}
+		dump.GuardianPool = change
+		thetaDelta.Add(thetaDelta, totalStake(change.After))
+		thetaDelta.Sub(thetaDelta, totalStake(change.Before))
+		for _, detail := range checkStakePool(view, "guardian", change.After) {
+			violate(InvariantStakePoolConsistent, "%v", detail)
+		}
+	}
+
+	// The Theta supply never changes, so the Theta only moves between the accounts and the stakes
+	if thetaDelta.Sign() != 0 {
+		violate(InvariantStakeConservation, "the Theta held by the accounts and staked in the pools changed by %v ThetaWei", thetaDelta)
+	}
+
+	return dump
+}
+
+// checkStakePool returns the inconsistencies of the stake holders with the accounts
+func checkStakePool(view *st.StoreView, pool string, holders []*core.StakeHolder) []string {
+	details := []string{}
+	seen := make(map[common.Address]bool)
+	for _, holder := range holders {
+		if seen[holder.Holder] {
+			details = append(details, fmt.Sprintf("duplicate %v %v", pool, holder.Holder.Hex()))
+		}
+		seen[holder.Holder] = true
+
+		for _, stake := range holder.Stakes {
+			if stake.Amount == nil || stake.Amount.Sign() < 0 {
+				details = append(details, fmt.Sprintf("invalid stake amount %v from %v to %v %v", stake.Amount, stake.Source.Hex(), pool, holder.Holder.Hex()))
+			}
+			if view.GetAccount(stake.Source) == nil {
+				details = append(details, fmt.Sprintf("the source account %v of the stake to %v %v does not exist", stake.Source.Hex(), pool, holder.Holder.Hex()))
+			}
+		}
+	}
+	return details
+}
+
+// accountHoldings returns the coins held by the account, i.e. the balance, and the collateral and
+// the remaining fund of each reserved fund
+func accountHoldings(account *types.Account) []types.Coins {
+	holdings := []types.Coins{account.Balance.NoNil()}
+	for _, fund := range account.ReservedFunds {
+		holdings = append(holdings, fund.Collateral.NoNil(), fund.InitialFund.Minus(fund.UsedFund).NoNil())
+	}
+	return holdings
+}
+
+// accountTheta returns the Theta held by the account, zero if the account does not exist
+func accountTheta(account *types.Account) *big.Int {
+	total := new(big.Int)
+	if account == nil {
+		return total
+	}
+	for _, coins := range accountHoldings(account) {
+		total.Add(total, coins.ThetaWei)
+	}
+	return total
+}
+
+func totalStake(holders []*core.StakeHolder) *big.Int {
+	total := new(big.Int)
+	for _, holder := range holders {
+		for _, stake := range holder.Stakes {
+			if stake.Amount != nil {
+				total.Add(total, stake.Amount)
+			}
+		}
+	}
+	return total
+}
+
+func guardianStakeHolders(gcp *core.GuardianCandidatePool) []*core.StakeHolder {
+	holders := []*core.StakeHolder{}
+	if gcp == nil {
+		return holders
+	}
+	for _, guardian := range gcp.SortedGuardians {
+		holders = append(holders, guardian.StakeHolder)
+	}
+	return holders
+}
@@ -0,0 +1,68 @@
+package ledger
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestCheckInvariants(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ledger, _ := newTestLedger()
+	_, accIns := prepareInitLedgerState(ledger, 2)
+	view := ledger.state.Delivered()
+	parent := st.NewStoreView(view.Height(), view.Hash(), ledger.db)
+	addr1 := accIns[0].Account.Address
+	addr2 := accIns[1].Account.Address
+
+	// Theta moves between the accounts and into a stake
+	view.RecordWrites()
+	acc1 := view.GetAccount(addr1)
+	acc1.Sequence++
+	acc1.Balance = acc1.Balance.Minus(types.NewCoins(300, 10))
+	view.SetAccount(addr1, acc1)
+	acc2 := view.GetAccount(addr2)
+	acc2.Balance = acc2.Balance.Plus(types.NewCoins(200, 0))
+	view.SetAccount(addr2, acc2)
+	vcp := &core.ValidatorCandidatePool{
+		SortedCandidates: []*core.StakeHolder{
+			core.NewStakeHolder(addr2, []*core.Stake{core.NewStake(addr1, big.NewInt(100))}),
+		},
+	}
+	view.UpdateValidatorCandidatePool(vcp)
+
+	dump := checkInvariants(parent, view)
+	assert.Equal(0, len(dump.Violations))
+	assert.Equal(2, len(dump.Accounts))
+	assert.NotNil(dump.ValidatorPool)
+
+	// Minted Theta, decreased sequence and overused reserved fund
+	acc1.Sequence = 0
+	acc1.Balance = acc1.Balance.Plus(types.NewCoins(1, 0))
+	view.SetAccount(addr1, acc1)
+	acc2.ReserveFund(types.NewCoins(0, 100), types.NewCoins(0, 200), []string{"rid"}, 100, 1)
+	acc2.ReservedFunds[0].UsedFund = types.NewCoins(0, 201)
+	view.SetAccount(addr2, acc2)
+
+	dump = checkInvariants(parent, view)
+	assert.ElementsMatch([]string{InvariantSequenceMonotonic, InvariantNonNegativeBalance, InvariantStakeConservation}, violatedInvariants(dump))
+
+	// A stake from an unknown account
+	vcp.SortedCandidates[0].Stakes = append(vcp.SortedCandidates[0].Stakes, core.NewStake(types.MakeAcc("unknown").Address, big.NewInt(0)))
+	view.UpdateValidatorCandidatePool(vcp)
+	dump = checkInvariants(parent, view)
+	assert.Contains(violatedInvariants(dump), InvariantStakePoolConsistent)
+}
+
+func violatedInvariants(dump *invariantViolationDump) []string {
+	invariants := []string{}
+	for _, violation := range dump.Violations {
+		invariants = append(invariants, violation.Invariant)
+	}
+	return invariants
+}
@@ -3,6 +3,7 @@ package ledger
 import (
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strconv"
 	"sync"
 	"time"
@@ -18,10 +19,12 @@ import (
 	"github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
 	exec "github.com/thetatoken/theta/ledger/execution"
 	"github.com/thetatoken/theta/ledger/state"
 	st "github.com/thetatoken/theta/ledger/state"
 	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/ledger/vm"
 	mp "github.com/thetatoken/theta/mempool"
 	"github.com/thetatoken/theta/store/database"
 )
@@ -30,6 +33,9 @@ var logger *log.Entry = log.WithFields(log.Fields{"prefix": "ledger"})
 
 var _ core.Ledger = (*Ledger)(nil)
 
+// heightEnableBlockLimits is the height to start enforcing the block limits, which the tests can lower
+var heightEnableBlockLimits = common.HeightEnableBlockLimits
+
 //
 // Ledger implements the core.Ledger interface
 //
@@ -44,25 +50,67 @@ type Ledger struct {
 	mu       *sync.RWMutex // Lock for accessing ledger state.
 	state    *st.LedgerState
 	executor *exec.Executor
+
+	minTxFees           *MinTxFees
+	prefetchEnabled     bool
+	parallelExecEnabled bool
+	invariantChecker    *InvariantChecker  // nil if the invariant checks are disabled
+	stateDiffRecorder   *StateDiffRecorder // nil if the state diffs are disabled
 }
 
 // NewLedger creates an instance of Ledger
 func NewLedger(chainID string, db database.Database, tagger st.Tagger, chain *blockchain.Chain, consensus core.ConsensusEngine, valMgr core.ValidatorManager, mempool *mp.Mempool) *Ledger {
 	state := st.NewLedgerState(chainID, db, tagger)
+	if size := viper.GetInt(common.CfgStorageFlatStateSize); size > 0 {
+		flat, err := st.NewFlatState(size)
+		if err != nil {
+			logger.Panic(err)
+		}
+		state.SetFlatState(flat)
+	}
 	executor := exec.NewExecutor(db, chain, state, consensus, valMgr)
+	executor.SetRecordRewardEvents(viper.GetBool(common.CfgLedgerRewardIndexEnabled))
+	minTxFees, err := NewMinTxFeesFromConfig()
+	if err != nil {
+		logger.Panic(err)
+	}
 	ledger := &Ledger{
-		db:        db,
-		chain:     chain,
-		consensus: consensus,
-		valMgr:    valMgr,
-		mempool:   mempool,
-		mu:        &sync.RWMutex{},
-		state:     state,
-		executor:  executor,
+		db:                  db,
+		chain:               chain,
+		consensus:           consensus,
+		valMgr:              valMgr,
+		mempool:             mempool,
+		mu:                  &sync.RWMutex{},
+		state:               state,
+		executor:            executor,
+		minTxFees:           minTxFees,
+		prefetchEnabled:     viper.GetBool(common.CfgStoragePrefetchEnabled),
+		parallelExecEnabled: viper.GetBool(common.CfgLedgerParallelExecEnabled),
+	}
+	if viper.GetBool(common.CfgLedgerInvariantCheckEnabled) {
+		ledger.invariantChecker = NewInvariantChecker(db)
+	}
+	if viper.GetBool(common.CfgLedgerStateDiffEnabled) {
+		ledger.stateDiffRecorder = NewStateDiffRecorder(db)
 	}
 	return ledger
 }
 
+// MinTxFees returns the minimum fees the node accepts into its mempool
+func (ledger *Ledger) MinTxFees() *MinTxFees {
+	return ledger.minTxFees
+}
+
+// StateDiffs returns the recorder of the state diffs of the applied blocks, nil if disabled
+func (ledger *Ledger) StateDiffs() *StateDiffRecorder {
+	return ledger.stateDiffRecorder
+}
+
+// recordsWrites returns whether the state writes of the applied blocks need to be recorded
+func (ledger *Ledger) recordsWrites() bool {
+	return ledger.invariantChecker != nil || ledger.stateDiffRecorder != nil
+}
+
 // State returns the state of the ledger
 func (ledger *Ledger) State() *st.LedgerState {
 	return ledger.state
@@ -99,6 +147,85 @@ func (ledger *Ledger) GetFinalizedSnapshot() (*st.StoreView, error) {
 
 // GetFinalizedValidatorCandidatePool returns the validator candidate pool of the latest DIRECTLY finalized block
 func (ledger *Ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash, isNext bool) (*core.ValidatorCandidatePool, error) {
+	storeView, err := ledger.getFinalizedValidatorStoreView(blockHash, isNext)
+	if err != nil {
+		return nil, err
+	}
+	vcp := storeView.GetValidatorCandidatePool()
+	return vcp, nil
+}
+
+// GetFinalizedValidatorBLSPubkeys returns the BLS pubkeys registered by the validators as of the
+// latest DIRECTLY finalized block, in the order of the validator set. The pubkey is nil for the
+// validators that have not registered one.
+func (ledger *Ledger) GetFinalizedValidatorBLSPubkeys(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]*bls.PublicKey, error) {
+	storeView, err := ledger.getFinalizedValidatorStoreView(blockHash, isNext)
+	if err != nil {
+		return nil, err
+	}
+	height, err := ledger.getValidatorSetHeight(blockHash, isNext)
+	if err != nil {
+		return nil, err
+	}
+	pubkeys := []*bls.PublicKey{}
+	for _, validator := range validators.Validators() {
+		pubkey := storeView.GetValidatorBLSPubkey(validator.ID())
+		if height >= common.HeightEnableValidatorKeyRotation {
+			if key := storeView.GetValidatorSigningKey(validator.ID()); key != nil {
+				pubkey = key.BLSPubkey(height) // the BLS pubkey registered along with the signing key
+			}
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
+
+// GetFinalizedValidatorSigningAddresses returns the addresses of the keys the validators sign the votes
+// and proposals with, in the order of the validator set. The signing address is the validator ID unless
+// the validator rotated its key with a RotateValidatorKeyTx.
+func (ledger *Ledger) GetFinalizedValidatorSigningAddresses(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]common.Address, error) {
+	signers := []common.Address{}
+	height, err := ledger.getValidatorSetHeight(blockHash, isNext)
+	if err != nil {
+		return nil, err
+	}
+	if height < common.HeightEnableValidatorKeyRotation {
+		for _, validator := range validators.Validators() {
+			signers = append(signers, validator.ID())
+		}
+		return signers, nil
+	}
+
+	storeView, err := ledger.getFinalizedValidatorStoreView(blockHash, isNext)
+	if err != nil {
+		return nil, err
+	}
+	for _, validator := range validators.Validators() {
+		signers = append(signers, storeView.GetValidatorSigningAddress(validator.ID(), height))
+	}
+	return signers, nil
+}
+
+// getValidatorSetHeight returns the height of the block the validator set applies to, i.e. the given
+// block, or its child if isNext is true.
+func (ledger *Ledger) getValidatorSetHeight(blockHash common.Hash, isNext bool) (uint64, error) {
+	store := kvstore.NewKVStore(ledger.state.DB())
+	block, err := findBlock(store, blockHash)
+	if err != nil {
+		return 0, err
+	}
+	if block == nil {
+		return 0, fmt.Errorf("Block is nil for hash %v", blockHash.Hex())
+	}
+	if isNext {
+		return block.Height + 1, nil
+	}
+	return block.Height, nil
+}
+
+// getFinalizedValidatorStoreView returns the state of the latest DIRECTLY finalized block, which
+// determines the validator set of the given block.
+func (ledger *Ledger) getFinalizedValidatorStoreView(blockHash common.Hash, isNext bool) (*st.StoreView, error) {
 	db := ledger.state.DB()
 	store := kvstore.NewKVStore(db)
 
@@ -131,8 +258,7 @@ func (ledger *Ledger) GetFinalizedValidatorCandidatePool(blockHash common.Hash,
 					"block.Status.IsTrusted()":    block.Status.IsTrusted(),
 				}).Panic("Failed to load state for validator pool")
 			}
-			vcp := storeView.GetValidatorCandidatePool()
-			return vcp, nil
+			return storeView, nil
 		}
 		blockHash = block.HCC.BlockHash
 	}
@@ -210,7 +336,7 @@ func (ledger *Ledger) ScreenTxUnsafe(rawTx common.Bytes) (res result.Result) {
 	var tx types.Tx
 	tx, err := types.TxFromBytes(rawTx)
 	if err != nil {
-		return result.Error("Error decoding tx: %v", err)
+		return result.Error("Error decoding tx: %v", err).WithErrorCode(result.CodeInvalidTxFormat)
 	}
 
 	_, res = ledger.executor.ScreenTx(tx)
@@ -222,7 +348,7 @@ func (ledger *Ledger) ScreenTx(rawTx common.Bytes) (txInfo *core.TxInfo, res res
 	var tx types.Tx
 	tx, err := types.TxFromBytes(rawTx)
 	if err != nil {
-		return nil, result.Error("Error decoding tx: %v", err)
+		return nil, result.Error("Error decoding tx: %v", err).WithErrorCode(result.CodeInvalidTxFormat)
 	}
 
 	if ledger.shouldSkipCheckTx(tx) {
@@ -230,6 +356,10 @@ func (ledger *Ledger) ScreenTx(rawTx common.Bytes) (txInfo *core.TxInfo, res res
 			WithErrorCode(result.CodeUnauthorizedTx)
 	}
 
+	if res := ledger.minTxFees.Check(tx); res.IsError() {
+		return nil, res
+	}
+
 	ledger.mu.RLock()
 	defer ledger.mu.RUnlock()
 
@@ -264,6 +394,10 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorU
 	defer func() { ledger.currentBlock = nil }()
 
 	view := ledger.state.Checked()
+	view.ResetBurnedFees()
+	view.ResetGasUsed()
+	view.ResetLapsedSplitRules()
+	ledger.recordParentBlockHash(view, block)
 
 	logger.Debugf("ProposeBlockTxs: Start adding block transactions, block.height = %v", block.Height)
 	preparationTime := time.Since(start)
@@ -272,6 +406,7 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorU
 	// Add special transactions
 	rawTxCandidates := []common.Bytes{}
 	ledger.addSpecialTransactions(block, view, &rawTxCandidates)
+	numSpecialTxs := len(rawTxCandidates)
 
 	// Add regular transactions submitted by the clients
 	regularRawTxs := ledger.mempool.ReapUnsafe(core.MaxNumRegularTxsPerBlock)
@@ -283,8 +418,15 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorU
 	addTxsTime := time.Since(start)
 	start = time.Now()
 
+	enforceBlockLimits := block.Height >= heightEnableBlockLimits
+	blockLimits := view.GetBlockLimits(ledger.state.GetChainID())
+	blockGas := uint64(0)
+	blockGasUsed := uint64(0)
+	blockSize := uint64(0)
+	isBlockFull := false
+
 	blockRawTxs = []common.Bytes{}
-	for _, rawTxCandidate := range rawTxCandidates {
+	for idx, rawTxCandidate := range rawTxCandidates {
 		tx, err := types.TxFromBytes(rawTxCandidate)
 		if err != nil {
 			continue
@@ -300,18 +442,37 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorU
 			}
 		}
 
+		txGas := types.GetBlockGas(tx, block.Height)
+		txSize := uint64(len(rawTxCandidate))
+		if enforceBlockLimits && idx >= numSpecialTxs {
+			if isBlockFull || blockGas+txGas > blockLimits.MaxGas || blockSize+txSize > blockLimits.MaxSize {
+				// Once a regular transaction does not fit, the later ones are left for the next
+				// blocks too, so the transactions of the same account stay in sequence.
+				isBlockFull = true
+				ledger.reinsertTx(tx, rawTxCandidate)
+				continue
+			}
+		}
+
 		_, res := ledger.executor.CheckTx(tx)
 		if res.IsError() {
 			logger.Errorf("Transaction check failed: errMsg = %v, tx = %v", res.Message, tx)
 			continue
 		}
 		blockRawTxs = append(blockRawTxs, rawTxCandidate)
+		blockGas += txGas
+		blockGasUsed += nonContractTxGas(tx, block.Height)
+		blockSize += txSize
 	}
+	blockGasUsed += view.PopGasUsed()
 
-	logger.Debugf("ProposeBlockTxs: block transactions executed, block.height = %v", block.Height)
+	logger.Debugf("ProposeBlockTxs: block transactions executed, block.height = %v, blockGas = %v, blockSize = %v, blockLimits = %v",
+		block.Height, blockGas, blockSize, blockLimits)
 	execTxsTime := time.Since(start)
 	start = time.Now()
 
+	ledger.updateBaseFee(view, block.Height, blockGasUsed, blockLimits)
+	ledger.updateTotalBurnedFees(view, block.Height)
 	ledger.handleDelayedStateUpdates(view)
 
 	stateRootHash = view.Hash()
@@ -325,6 +486,79 @@ func (ledger *Ledger) ProposeBlockTxs(block *core.Block, shouldIncludeValidatorU
 	return stateRootHash, blockRawTxs, result.OK
 }
 
+// recordParentBlockHash records the hash of the parent block in the state, so that the smart contracts
+// can query the hashes of the recent blocks with BLOCKHASH once the EVM forks of the chain are enabled
+func (ledger *Ledger) recordParentBlockHash(view *st.StoreView, block *core.Block) {
+	forkHeight := common.GetEVMForkHeights(ledger.state.GetChainID()).EarliestHeight()
+	if block.Height == 0 || (forkHeight > st.NumRecentBlockHashes && block.Height < forkHeight-st.NumRecentBlockHashes) {
+		return
+	}
+	view.SetRecentBlockHash(block.Height-1, block.Parent)
+}
+
+// updateTotalBurnedFees adds the TFuel fees burned by the block transactions to the total
+func (ledger *Ledger) updateTotalBurnedFees(view *st.StoreView, blockHeight uint64) {
+	burnedFees := view.PopBurnedFees()
+	if blockHeight < common.HeightEnableFeeBurnTracking {
+		return
+	}
+	totalBurnedFees := view.GetTotalBurnedFees()
+	totalBurnedFees.Add(totalBurnedFees, burnedFees)
+	view.SetTotalBurnedFees(totalBurnedFees)
+	logger.Debugf("Total burned fees updated: block.height = %v, burnedFees = %v, totalBurnedFees = %v", blockHeight, burnedFees, totalBurnedFees)
+}
+
+// updateBaseFee adjusts the base fee of the smart contract transactions in the later blocks
+// according to the gas used by the block transactions
+func (ledger *Ledger) updateBaseFee(view *st.StoreView, blockHeight uint64, blockGasUsed uint64, blockLimits core.BlockLimits) {
+	if blockHeight < common.HeightEnableBaseFee {
+		return
+	}
+	baseFee := view.GetBaseFee(blockHeight)
+	nextBaseFee := types.CalculateNextBaseFee(baseFee, blockGasUsed, blockLimits.MaxGas, blockHeight)
+	view.SetBaseFee(nextBaseFee)
+	logger.Debugf("Base fee updated: block.height = %v, blockGasUsed = %v, baseFee = %v -> %v", blockHeight, blockGasUsed, baseFee, nextBaseFee)
+}
+
+// nonContractTxGas returns the gas of the transaction unless it is a smart contract transaction,
+// which might not use up its gas limit. The gas used by the smart contract transactions is only
+// known after the execution, and is recorded in the view instead.
+func nonContractTxGas(tx types.Tx, blockHeight uint64) uint64 {
+	if _, ok := tx.(*types.SmartContractTx); ok {
+		return 0
+	}
+	return types.GetBlockGas(tx, blockHeight)
+}
+
+// reinsertTx puts a reaped transaction that does not fit into the proposed block back to the mempool
+func (ledger *Ledger) reinsertTx(tx types.Tx, rawTx common.Bytes) {
+	txInfo, res := ledger.executor.GetTxInfo(tx)
+	if res.IsError() {
+		// The transaction has already been reaped, mark it abandoned so its status does not stay pending
+		logger.Warnf("Failed to reinsert transaction into the mempool: errMsg = %v, tx = %v", res.Message, tx)
+		ledger.mempool.AbandonUnsafe(rawTx, res.Message)
+		return
+	}
+	ledger.mempool.ReinsertUnsafe(rawTx, txInfo)
+}
+
+// getTxInfos returns the tx infos of the raw transactions, nil for the ones that fail to decode
+func (ledger *Ledger) getTxInfos(rawTxs []common.Bytes) []*core.TxInfo {
+	txInfos := make([]*core.TxInfo, len(rawTxs))
+	for i, rawTx := range rawTxs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		txInfo, res := ledger.executor.GetTxInfo(tx)
+		if res.IsError() {
+			continue
+		}
+		txInfos[i] = txInfo
+	}
+	return txInfos
+}
+
 // ApplyBlockTxs applies the given block transactions. If any of the transactions failed, it returns
 // an error immediately. If all the transactions execute successfully, it then validates the state
 // root hash. If the states root hash matches the expected value, it clears the transactions from the mempool
@@ -343,6 +577,9 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	expectedStateRoot := ledger.currentBlock.StateHash
 
 	view := ledger.state.Delivered()
+	if ledger.recordsWrites() {
+		view.RecordWrites()
+	}
 
 	// currHeight := view.Height()
 	// currStateRoot := view.Hash()
@@ -353,37 +590,52 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	parentBlock := extParentBlock.Block
 	logger.Debugf("ApplyBlockTxs: Start applying block transactions, block.height = %v", block.Height)
 
-	hasValidatorUpdate := false
-	txProcessTime := []time.Duration{}
-	for _, rawTx := range blockRawTxs {
-		start := time.Now()
-		tx, err := types.TxFromBytes(rawTx)
-		if err != nil {
-			//ledger.resetState(currHeight, currStateRoot)
-			ledger.resetState(parentBlock)
-			return result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
-		}
-		if dtx, ok := tx.(*types.DepositStakeTx); ok && dtx.Purpose == core.StakeForValidator {
-			hasValidatorUpdate = true
-		} else if wtx, ok := tx.(*types.WithdrawStakeTx); ok && wtx.Purpose == core.StakeForValidator {
-			hasValidatorUpdate = true
-		}
-		_, res := ledger.executor.ExecuteTx(tx)
-		if res.IsError() {
-			//ledger.resetState(currHeight, currStateRoot)
-			ledger.resetState(parentBlock)
-			return res
-		}
-		txProcessTime = append(txProcessTime, time.Since(start))
-	}
+	blockLimits := view.GetBlockLimits(ledger.state.GetChainID())
 
-	logger.Debugf("ApplyBlockTxs: Finish applying block transactions, block.height=%v, txProcessTime=%v", block.Height, txProcessTime)
+	var prefetcher *txPrefetcher
+	if ledger.prefetchEnabled {
+		prefetcher = newTxPrefetcher(ledger.db, parentBlock, blockRawTxs)
+	}
+	if prefetcher != nil {
+		prefetcher.start()
+		defer prefetcher.stop()
+	}
 
 	start := time.Now()
+	ledger.executor.PreverifySignatures(blockRawTxs)
+	preverifyTime := time.Since(start)
+
+	hasValidatorUpdate, txProcessTime, res := ledger.executeBlockTxs(view, block, blockLimits, prefetcher, ledger.parallelExecEnabled)
+	if res.IsError() {
+		//ledger.resetState(currHeight, currStateRoot)
+		ledger.resetState(parentBlock)
+		return res
+	}
+
+	logger.Debugf("ApplyBlockTxs: Finish applying block transactions, block.height=%v, preverifyTime=%v, txProcessTime=%v", block.Height, preverifyTime, txProcessTime)
+
+	start = time.Now()
 	ledger.handleDelayedStateUpdates(view)
 	handleDelayedUpdateTime := time.Since(start)
 
 	newStateRoot := view.Hash()
+	if newStateRoot != expectedStateRoot && ledger.parallelExecEnabled {
+		// The parallel execution is validated against the serial one, the block is only rejected
+		// if the serial execution does not reach the expected state root either
+		logger.Warnf("ApplyBlockTxs: State root mismatch after the parallel execution, re-applying the block serially, block.height = %v", block.Height)
+		ledger.resetState(parentBlock)
+		view = ledger.state.Delivered()
+		if ledger.recordsWrites() {
+			view.RecordWrites()
+		}
+		hasValidatorUpdate, _, res = ledger.executeBlockTxs(view, block, blockLimits, nil, false)
+		if res.IsError() {
+			ledger.resetState(parentBlock)
+			return res
+		}
+		ledger.handleDelayedStateUpdates(view)
+		newStateRoot = view.Hash()
+	}
 	if newStateRoot != expectedStateRoot {
 		//ledger.resetState(currHeight, currStateRoot)
 		ledger.resetState(parentBlock)
@@ -392,17 +644,28 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 			hex.EncodeToString(expectedStateRoot[:]))
 	}
 
+	if ledger.invariantChecker != nil {
+		ledger.invariantChecker.CheckBlock(parentBlock, block, view)
+	}
+	if ledger.stateDiffRecorder != nil {
+		ledger.stateDiffRecorder.Record(parentBlock, block, view)
+	}
+
 	start = time.Now()
 	ledger.state.Commit() // commit to persistent storage
+	ledger.saveTxReceipts()
+	ledger.saveSplitRuleLapseEvents(view, block.Height)
 	commitTime := time.Since(start)
 
 	logger.Debugf("ApplyBlockTxs: Committed state change, block.height = %v", block.Height)
 
 	go func() {
+		committedTxInfos := ledger.getTxInfos(blockRawTxs)
+
 		ledger.mempool.Lock()
 		defer ledger.mempool.Unlock()
 
-		ledger.mempool.UpdateUnsafe(blockRawTxs) // clear txs from the mempool
+		ledger.mempool.UpdateUnsafe(block.Height, blockRawTxs, committedTxInfos) // clear txs from the mempool
 	}()
 
 	logger.Debugf("ApplyBlockTxs: Cleared mempool transactions, block.height = %v", block.Height)
@@ -413,6 +676,74 @@ func (ledger *Ledger) ApplyBlockTxs(block *core.Block) result.Result {
 	return result.OKWith(result.Info{"hasValidatorUpdate": hasValidatorUpdate})
 }
 
+// executeBlockTxs executes the block transactions on the given view and updates the base fee. If
+// parallel is set, the independent send transactions are executed in parallel. The view is not
+// reset if any of the transactions failed.
+func (ledger *Ledger) executeBlockTxs(view *st.StoreView, block *core.Block, blockLimits core.BlockLimits,
+	prefetcher *txPrefetcher, parallel bool) (hasValidatorUpdate bool, txProcessTime []time.Duration, res result.Result) {
+	enforceBlockLimits := block.Height >= heightEnableBlockLimits
+	blockGas := uint64(0)
+	blockGasUsed := uint64(0)
+	blockSize := uint64(0)
+	view.ResetBurnedFees()
+	view.ResetGasUsed()
+	view.ResetLapsedSplitRules()
+	ledger.recordParentBlockHash(view, block)
+
+	batch := newTxBatch()
+	for idx, rawTx := range block.Txs {
+		start := time.Now()
+		if prefetcher != nil {
+			prefetcher.advance(idx)
+		}
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			return false, nil, result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
+		}
+		blockGas += types.GetBlockGas(tx, block.Height)
+		blockGasUsed += nonContractTxGas(tx, block.Height)
+		blockSize += uint64(len(rawTx))
+		if enforceBlockLimits {
+			if blockGas > blockLimits.MaxGas || blockSize > blockLimits.MaxSize {
+				return false, nil, result.Error("Block exceeds the block limits: blockGas = %v, blockSize = %v, blockLimits = %v",
+					blockGas, blockSize, blockLimits).WithErrorCode(result.CodeBlockLimitsExceeded)
+			}
+		}
+		if types.IsValidatorUpdateTx(tx) {
+			hasValidatorUpdate = true
+		}
+		if parallel {
+			if batch.add(tx) {
+				continue
+			}
+			res = ledger.executeTxBatch(view, batch.txs)
+			if res.IsError() {
+				return false, nil, res
+			}
+			txProcessTime = append(txProcessTime, batch.processTime()...)
+			batch = newTxBatch()
+			if batch.add(tx) {
+				continue
+			}
+		}
+		_, res = ledger.executor.ExecuteTx(tx)
+		if res.IsError() {
+			return false, nil, res
+		}
+		txProcessTime = append(txProcessTime, time.Since(start))
+	}
+	res = ledger.executeTxBatch(view, batch.txs)
+	if res.IsError() {
+		return false, nil, res
+	}
+	txProcessTime = append(txProcessTime, batch.processTime()...)
+	blockGasUsed += view.PopGasUsed()
+
+	ledger.updateBaseFee(view, block.Height, blockGasUsed, blockLimits)
+	ledger.updateTotalBurnedFees(view, block.Height)
+	return hasValidatorUpdate, txProcessTime, result.OK
+}
+
 // ApplyBlockTxsForChainCorrection applies all block's txs and re-calculate root hash
 func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common.Hash, result.Result) {
 	ledger.mempool.Lock()
@@ -436,6 +767,13 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 	}
 	parentBlock := extParentBlock.Block
 
+	blockLimits := view.GetBlockLimits(ledger.state.GetChainID())
+	blockGasUsed := uint64(0)
+	view.ResetBurnedFees()
+	view.ResetGasUsed()
+	view.ResetLapsedSplitRules()
+	ledger.recordParentBlockHash(view, block)
+
 	hasValidatorUpdate := false
 	for _, rawTx := range blockRawTxs {
 		tx, err := types.TxFromBytes(rawTx)
@@ -444,9 +782,8 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 			ledger.resetState(parentBlock)
 			return common.Hash{}, result.Error("Failed to parse transaction: %v", hex.EncodeToString(rawTx))
 		}
-		if dtx, ok := tx.(*types.DepositStakeTx); ok && dtx.Purpose == core.StakeForValidator {
-			hasValidatorUpdate = true
-		} else if wtx, ok := tx.(*types.WithdrawStakeTx); ok && wtx.Purpose == core.StakeForValidator {
+		blockGasUsed += nonContractTxGas(tx, block.Height)
+		if types.IsValidatorUpdateTx(tx) {
 			hasValidatorUpdate = true
 		}
 		_, res := ledger.executor.ExecuteTx(tx)
@@ -456,14 +793,196 @@ func (ledger *Ledger) ApplyBlockTxsForChainCorrection(block *core.Block) (common
 			return common.Hash{}, res
 		}
 	}
+	blockGasUsed += view.PopGasUsed()
 
+	ledger.updateBaseFee(view, block.Height, blockGasUsed, blockLimits)
+	ledger.updateTotalBurnedFees(view, block.Height)
 	ledger.handleDelayedStateUpdates(view)
 
 	ledger.state.Commit() // commit to persistent storage
+	ledger.saveTxReceipts()
+	ledger.saveSplitRuleLapseEvents(view, block.Height)
 
 	return view.Hash(), result.OKWith(result.Info{"hasValidatorUpdate": hasValidatorUpdate})
 }
 
+// TraceSmartContractTx re-executes the smart contract transaction at the given index of the block
+// with the tracer. The transactions before it are replayed first on top of the state of the parent
+// block, so the transaction sees the same state as when the block was applied.
+func (ledger *Ledger) TraceSmartContractTx(block *core.Block, txIndex int, tracer vm.Tracer) (evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error, err error) {
+	if txIndex < 0 || txIndex >= len(block.Txs) {
+		return nil, common.Address{}, 0, nil, fmt.Errorf("Tx index %v out of range", txIndex)
+	}
+	tx, err := types.TxFromBytes(block.Txs[txIndex])
+	if err != nil {
+		return nil, common.Address{}, 0, nil, err
+	}
+	sctx, ok := tx.(*types.SmartContractTx)
+	if !ok {
+		return nil, common.Address{}, 0, nil, fmt.Errorf("Not a smart contract transaction")
+	}
+
+	extParentBlock, err := ledger.chain.FindBlock(block.Parent)
+	if err != nil {
+		return nil, common.Address{}, 0, nil, fmt.Errorf("Failed to find the parent block: %v, err: %v", block.Parent.Hex(), err)
+	}
+	parentBlock := extParentBlock.Block
+
+	// Replay on a separate ledger state, which is never committed
+	replayState := st.NewLedgerState(ledger.state.GetChainID(), ledger.db, nil)
+	if res := replayState.ResetState(parentBlock); res.IsError() {
+		return nil, common.Address{}, 0, nil, fmt.Errorf("Failed to load the state of block %v, the state might have been pruned: %v",
+			parentBlock.Hash().Hex(), res.Message)
+	}
+	replayExecutor := exec.NewExecutor(ledger.db, ledger.chain, replayState, ledger.consensus, ledger.valMgr)
+	replayExecutor.SetSkipSanityCheck(true) // the block has been validated
+	for i := 0; i < txIndex; i++ {
+		prevTx, err := types.TxFromBytes(block.Txs[i])
+		if err != nil {
+			return nil, common.Address{}, 0, nil, err
+		}
+		if _, res := replayExecutor.ExecuteTx(prevTx); res.IsError() {
+			return nil, common.Address{}, 0, nil, fmt.Errorf("Failed to replay tx %v: %v", i, res.Message)
+		}
+	}
+
+	view := replayState.Delivered()
+	view.ResetLogs()
+	evmRet, contractAddr, gasUsed, evmErr = vm.ExecuteWithTracer(parentBlock, sctx, view, tracer)
+	return evmRet, contractAddr, gasUsed, evmErr, nil
+}
+
+// TxSimulation is the would-be outcome of a transaction, see SimulateTx
+type TxSimulation struct {
+	TxHash          common.Hash
+	Fee             types.Coins
+	GasUsed         uint64
+	ContractAddress common.Address
+	EvmRet          common.Bytes
+	EvmErr          string
+	BalanceChanges  []BalanceChange
+}
+
+// BalanceChange is the balance of an account involved in a transaction before and after the transaction
+type BalanceChange struct {
+	Address common.Address
+	Before  types.Coins
+	After   types.Coins
+}
+
+// SimulateTx checks and executes the transaction on a copy of the latest state as if it were included in
+// the next block. The copy is never committed, so the transaction has no effect on the ledger.
+func (ledger *Ledger) SimulateTx(rawTx common.Bytes) (*TxSimulation, result.Result) {
+	tx, err := types.TxFromBytes(rawTx)
+	if err != nil {
+		return nil, result.Error("Error decoding tx: %v", err).WithErrorCode(result.CodeInvalidTxFormat)
+	}
+	if ledger.shouldSkipCheckTx(tx) {
+		return nil, result.Error("Unauthorized transaction, should skip").
+			WithErrorCode(result.CodeUnauthorizedTx)
+	}
+
+	ledger.mu.Lock()
+	header := *ledger.state.ParentBlock().BlockHeader
+	header.Height = ledger.state.Delivered().Height()
+	header.StateHash = ledger.state.Delivered().Hash()
+	ledger.mu.Unlock()
+
+	simState := st.NewLedgerState(ledger.state.GetChainID(), ledger.db, nil)
+	if res := simState.ResetState(&core.Block{BlockHeader: &header}); res.IsError() {
+		return nil, res
+	}
+	simExecutor := exec.NewExecutor(ledger.db, ledger.chain, simState, ledger.consensus, ledger.valMgr)
+	view := simState.Delivered()
+
+	addresses := types.TxAccountAddresses(tx)
+	balances := make(map[common.Address]types.Coins)
+	for _, address := range addresses {
+		balances[address] = accountBalance(view, address)
+	}
+
+	txHash, res := simExecutor.ExecuteTx(tx)
+	if res.IsError() {
+		return nil, res
+	}
+
+	simulation := &TxSimulation{
+		TxHash: txHash,
+		Fee:    types.FixedTxFee(tx),
+	}
+	if sctx, ok := tx.(*types.SmartContractTx); ok {
+		for _, receipt := range simExecutor.PopTxReceipts() {
+			simulation.GasUsed = receipt.GasUsed
+			simulation.ContractAddress = receipt.ContractAddress
+			simulation.EvmRet = receipt.EvmRet
+			simulation.EvmErr = receipt.EvmErr
+			simulation.Fee = types.Coins{
+				ThetaWei: big.NewInt(0),
+				TFuelWei: new(big.Int).Mul(sctx.GasPrice, new(big.Int).SetUint64(receipt.GasUsed)),
+			}
+		}
+		if _, ok := balances[simulation.ContractAddress]; !ok && !simulation.ContractAddress.IsEmpty() {
+			addresses = append(addresses, simulation.ContractAddress)
+			balances[simulation.ContractAddress] = types.NewCoins(0, 0)
+		}
+	}
+
+	for _, address := range addresses {
+		after := accountBalance(view, address)
+		if after.IsEqual(balances[address]) {
+			continue
+		}
+		simulation.BalanceChanges = append(simulation.BalanceChanges, BalanceChange{
+			Address: address,
+			Before:  balances[address],
+			After:   after,
+		})
+	}
+	return simulation, result.OK
+}
+
+func accountBalance(view *st.StoreView, address common.Address) types.Coins {
+	account := view.GetAccount(address)
+	if account == nil {
+		return types.NewCoins(0, 0)
+	}
+	return account.Balance.NoNil()
+}
+
+// saveTxReceipts saves the receipts and the internal transactions of the transactions of the committed block,
+// and its reward events
+func (ledger *Ledger) saveTxReceipts() {
+	for _, receipt := range ledger.executor.PopTxReceipts() {
+		ledger.chain.SaveTxReceipt(receipt)
+	}
+	for _, internalTxs := range ledger.executor.PopInternalTxs() {
+		ledger.chain.SaveInternalTxs(internalTxs)
+	}
+	ledger.chain.SaveRewardEvents(ledger.executor.PopRewardEvents())
+}
+
+// saveSplitRuleLapseEvents saves the events of the split rules which lapsed in the committed block
+func (ledger *Ledger) saveSplitRuleLapseEvents(view *st.StoreView, blockHeight uint64) {
+	events := []*blockchain.SplitRuleLapseEvent{}
+	for _, splitRule := range view.PopLapsedSplitRules() {
+		logger.WithFields(log.Fields{
+			"event":          "SplitRuleLapsed",
+			"resourceID":     splitRule.ResourceID,
+			"initiator":      splitRule.InitiatorAddress.Hex(),
+			"endBlockHeight": splitRule.EndBlockHeight,
+			"height":         blockHeight,
+		}).Infof("Split rule lapsed")
+		events = append(events, &blockchain.SplitRuleLapseEvent{
+			Height:         blockHeight,
+			ResourceID:     splitRule.ResourceID,
+			Initiator:      splitRule.InitiatorAddress,
+			EndBlockHeight: splitRule.EndBlockHeight,
+		})
+	}
+	ledger.chain.SaveSplitRuleLapseEvents(events)
+}
+
 // PruneState attempts to prune the state up to the targetEndHeight
 func (ledger *Ledger) PruneState(targetEndHeight uint64) error {
 	// Permanently disabled
@@ -620,6 +1139,9 @@ func (ledger *Ledger) resetState(block *core.Block) result.Result {
 
 	//res := ledger.state.ResetState(height, rootHash)
 	res := ledger.state.ResetState(block)
+	ledger.executor.PopTxReceipts() // discard the receipts of the rejected block
+	ledger.executor.PopInternalTxs()
+	ledger.executor.PopRewardEvents()
 	if res.IsError() {
 		return result.Error("Failed to set state root: %v", hex.EncodeToString(rootHash[:]))
 	}
@@ -650,6 +1172,24 @@ func (ledger *Ledger) handleDelayedStateUpdates(view *st.StoreView) {
 	if blockHeight >= common.HeightEnableTheta3 {
 		ledger.handleEliteEdgeNodeStakeReturns(view)
 	}
+	if blockHeight >= heightEnableBlockLimits {
+		ledger.handleBlockLimitsUpdate(view, blockHeight)
+	}
+}
+
+// handleBlockLimitsUpdate writes the block limits adjustment scheduled at the block height, if any,
+// to the state. The block limits of the block itself are not affected since they have been read
+// before the block transactions are executed.
+func (ledger *Ledger) handleBlockLimitsUpdate(view *st.StoreView, blockHeight uint64) {
+	limits, ok := core.GetBlockLimitsUpdate(ledger.state.GetChainID(), blockHeight)
+	if !ok {
+		return
+	}
+	if err := limits.Validate(); err != nil {
+		log.Panicf("Invalid block limits update at height %v: %v, error: %v", blockHeight, limits, err)
+	}
+	view.SetBlockLimits(limits)
+	logger.Infof("Block limits updated: block.height = %v, blockLimits = %v", blockHeight, limits)
 }
 
 func (ledger *Ledger) handleValidatorStakeReturn(view *st.StoreView) {
@@ -772,7 +1312,7 @@ func (ledger *Ledger) addSpecialTransactions(block *core.Block, view *st.StoreVi
 // addCoinbaseTx adds a Coinbase transaction
 func (ledger *Ledger) addCoinbaseTx(view *st.StoreView, proposer *core.Validator,
 	validatorSet *core.ValidatorSet, rawTxs *[]common.Bytes) {
-	proposerAddress := proposer.Address
+	proposerAddress := validatorSet.SigningAddress(proposer.Address)
 	proposerTxIn := types.TxInput{
 		Address: proposerAddress,
 	}
@@ -824,7 +1364,7 @@ func (ledger *Ledger) addCoinbaseTx(view *st.StoreView, proposer *core.Validator
 
 // addsSlashTx adds Slash transactions
 func (ledger *Ledger) addSlashTxs(view *st.StoreView, proposer *core.Validator, validatorSet *core.ValidatorSet, rawTxs *[]common.Bytes) {
-	proposerAddress := proposer.Address
+	proposerAddress := validatorSet.SigningAddress(proposer.Address)
 	proposerTxIn := types.TxInput{
 		Address: proposerAddress,
 	}
@@ -860,7 +1400,7 @@ func (ledger *Ledger) addSlashTxs(view *st.StoreView, proposer *core.Validator,
 func (ledger *Ledger) signTransaction(tx types.Tx) (*crypto.Signature, error) {
 	chainID := ledger.state.GetChainID()
 	signBytes := tx.SignBytes(chainID)
-	signature, err := ledger.consensus.PrivateKey().Sign(signBytes)
+	signature, err := ledger.consensus.Signer().SignTx(signBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +44,44 @@ func TestLedgerScreenTx(t *testing.T) {
 	assert.Equal(result.CodeUnauthorizedTx, res.Code, res.Message)
 }
 
+func TestLedgerSimulateTx(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID, ledger, _ := newTestLedger()
+	numInAccs := 1
+	accOut, accIns := prepareInitLedgerState(ledger, numInAccs)
+	accIn := accIns[0]
+
+	sendTxBytes := newRawSendTx(chainID, 1, true, accOut, accIn, false)
+	simulation, res := ledger.SimulateTx(sendTxBytes)
+	assert.True(res.IsOK(), res.Message)
+	txFee := getMinimumTxFee()
+	assert.True(types.NewCoins(0, txFee).IsEqual(simulation.Fee))
+	assert.Equal(2, len(simulation.BalanceChanges))
+	for _, change := range simulation.BalanceChanges {
+		if change.Address == accIn.Address {
+			assert.True(change.Before.Minus(types.NewCoins(15, txFee)).IsEqual(change.After))
+		} else {
+			assert.Equal(accOut.Address, change.Address)
+			assert.True(change.Before.Plus(types.NewCoins(15, 0)).IsEqual(change.After))
+		}
+	}
+
+	// The ledger state is not changed
+	account := ledger.state.Delivered().GetAccount(accIn.Address)
+	assert.Equal(uint64(0), account.Sequence)
+	assert.True(accIn.Account.Balance.IsEqual(account.Balance))
+
+	// Wrong sequence
+	sendTxBytes = newRawSendTx(chainID, 2, true, accOut, accIn, false)
+	_, res = ledger.SimulateTx(sendTxBytes)
+	assert.Equal(result.CodeInvalidSequence, res.Code, res.Message)
+
+	coinbaseTxBytes := newRawCoinbaseTx(chainID, ledger, 1)
+	_, res = ledger.SimulateTx(coinbaseTxBytes)
+	assert.Equal(result.CodeUnauthorizedTx, res.Code, res.Message)
+}
+
 func TestLedgerProposerBlockTxs(t *testing.T) {
 	assert := assert.New(t)
 
@@ -163,6 +201,55 @@ func TestLedgerApplyBlockTxs(t *testing.T) {
 	}
 }
 
+func TestLedgerBlockLimits(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	defer func(height uint64) { heightEnableBlockLimits = height }(heightEnableBlockLimits)
+	heightEnableBlockLimits = 0
+
+	chainID, ledger, mempool := newTestLedger()
+	numInAccs := 5
+	accOut, accIns := prepareInitLedgerState(ledger, numInAccs)
+
+	// Governance lowers the block gas limit so only two send transactions fit into a block
+	limits := core.BlockLimits{MaxGas: 2 * types.GasRegularTx, MaxSize: core.DefaultMaxBlockSize}
+	view := ledger.state.Delivered()
+	core.BlockLimitsUpdates[chainID] = []core.BlockLimitsUpdate{{Height: view.Height() + 1, Limits: limits}}
+	defer delete(core.BlockLimitsUpdates, chainID)
+	require.Equal(core.DefaultBlockLimits(chainID), view.GetBlockLimits(chainID))
+	ledger.handleDelayedStateUpdates(view)
+	require.Equal(limits, view.GetBlockLimits(chainID))
+	ledger.state.Commit()
+
+	rawSendTxs := []common.Bytes{}
+	for idx := 0; idx < numInAccs; idx++ {
+		sendTxBytes := newRawSendTx(chainID, 1, true, accOut, accIns[idx], false)
+		require.Nil(mempool.InsertTransaction(sendTxBytes))
+		rawSendTxs = append(rawSendTxs, sendTxBytes)
+	}
+
+	// The proposer leaves the transactions beyond the limits in the mempool
+	block := &core.Block{BlockHeader: &core.BlockHeader{ChainID: chainID, Height: ledger.state.Height() + 1}}
+	_, blockTxs, res := ledger.ProposeBlockTxs(block, true)
+	require.True(res.IsOK(), res.Message)
+	numSendTxs := 0
+	for _, rawTx := range blockTxs {
+		tx, err := types.TxFromBytes(rawTx)
+		require.Nil(err)
+		if _, ok := tx.(*types.SendTx); ok {
+			numSendTxs++
+		}
+	}
+	assert.Equal(2, numSendTxs)
+	assert.Equal(numInAccs-2, mempool.Size())
+
+	// A block beyond the limits is rejected
+	block = &core.Block{BlockHeader: &core.BlockHeader{ChainID: chainID, Height: ledger.state.Height() + 1}, Txs: rawSendTxs[:3]}
+	_, _, res = ledger.executeBlockTxs(ledger.state.Delivered(), block, ledger.state.Delivered().GetBlockLimits(chainID), nil, false)
+	assert.Equal(result.CodeBlockLimitsExceeded, res.Code, res.Message)
+}
+
 // Test case for validator stake deposit, withdrawal, and return
 func TestValidatorStakeUpdate(t *testing.T) {
 	assert := assert.New(t)
@@ -0,0 +1,151 @@
+package ledger
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// MinTxFeeTxTypes are the tx types submitted by the clients, which are subject to the minimum fees
+var MinTxFeeTxTypes = []types.TxType{
+	types.TxSend,
+	types.TxReserveFund,
+	types.TxReleaseFund,
+	types.TxServicePayment,
+	types.TxSplitRule,
+	types.TxSmartContract,
+	types.TxDepositStake,
+	types.TxWithdrawStake,
+	types.TxDepositStakeV2,
+	types.TxStakeRewardDistribution,
+	types.TxVesting,
+	types.TxRotateValidatorKey,
+}
+
+// MinTxFees holds the node local minimum fees of the tx types, enforced when the transactions are
+// screened for the mempool, on top of the protocol minimums enforced by the sanity checks. Unlike
+// the protocol minimums, they can be adjusted through the config or at runtime without a release.
+// For the smart contract transactions, the minimum applies to the gas price.
+type MinTxFees struct {
+	mu   *sync.RWMutex
+	fees map[types.TxType]*big.Int
+}
+
+// NewMinTxFees creates an instance of MinTxFees without any minimum fee
+func NewMinTxFees() *MinTxFees {
+	return &MinTxFees{
+		mu:   &sync.RWMutex{},
+		fees: make(map[types.TxType]*big.Int),
+	}
+}
+
+// NewMinTxFeesFromConfig loads the minimum fees from the config
+func NewMinTxFeesFromConfig() (*MinTxFees, error) {
+	m := NewMinTxFees()
+	for name, amount := range common.GetReloadableStringMapString(common.CfgMempoolMinTxFees) {
+		txType, ok := types.TxTypeFromString(name)
+		if !ok || !IsMinTxFeeTxType(txType) {
+			return nil, fmt.Errorf("Invalid tx type in %v: %v", common.CfgMempoolMinTxFees, name)
+		}
+		fee, ok := new(big.Int).SetString(amount, 10)
+		if !ok || fee.Sign() < 0 {
+			return nil, fmt.Errorf("Invalid minimum fee for %v: %v", name, amount)
+		}
+		m.Set(txType, fee)
+	}
+	return m, nil
+}
+
+// ReloadFromConfig replaces the minimum fees with the ones in the config, including the ones set
+// at runtime
+func (m *MinTxFees) ReloadFromConfig() error {
+	loaded, err := NewMinTxFeesFromConfig()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fees = loaded.fees
+	return nil
+}
+
+// Set sets the minimum fee of the tx type, a nil or zero fee removes the minimum
+func (m *MinTxFees) Set(txType types.TxType, fee *big.Int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if fee == nil || fee.Sign() == 0 {
+		delete(m.fees, txType)
+		return
+	}
+	m.fees[txType] = new(big.Int).Set(fee)
+}
+
+// Get returns the minimum fee of the tx type, or nil if there is none
+func (m *MinTxFees) Get(txType types.TxType) *big.Int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	fee, ok := m.fees[txType]
+	if !ok {
+		return nil
+	}
+	return new(big.Int).Set(fee)
+}
+
+// Check checks the fee of the transaction against the minimum fee of its type
+func (m *MinTxFees) Check(tx types.Tx) result.Result {
+	txType, err := types.GetTxType(tx)
+	if err != nil {
+		return result.OK // left to the sanity checks
+	}
+	minFee := m.Get(txType)
+	if minFee == nil {
+		return result.OK
+	}
+
+	if sctx, ok := tx.(*types.SmartContractTx); ok {
+		if sctx.GasPrice == nil || sctx.GasPrice.Cmp(minFee) < 0 {
+			return result.Error("Gas price %v is below the minimum gas price %v accepted by the node", sctx.GasPrice, minFee).
+				WithErrorCode(result.CodeInvalidGasPrice)
+		}
+		return result.OK
+	}
+
+	fee := types.FixedTxFee(tx)
+	if fee.TFuelWei.Cmp(minFee) < 0 {
+		return result.Error("Fee %v TFuelWei is below the minimum fee %v TFuelWei accepted by the node for %v txs", fee.TFuelWei, minFee, txType).
+			WithErrorCode(result.CodeInvalidFee)
+	}
+	return result.OK
+}
+
+// ProtocolMinTxFee returns the protocol minimum fee of the tx type at the block height, or the
+// minimum gas price for the smart contract transactions. For the send transactions, the minimum
+// grows with the number of accounts, and the minimum of the transactions with two accounts is
+// returned.
+func ProtocolMinTxFee(txType types.TxType, blockHeight uint64) *big.Int {
+	switch txType {
+	case types.TxSmartContract:
+		return types.GetMinimumGasPrice(blockHeight)
+	case types.TxSend:
+		return types.GetSendTxMinimumTransactionFeeTFuelWei(2, blockHeight)
+	default:
+		return types.GetMinimumTransactionFeeTFuelWei(blockHeight)
+	}
+}
+
+// IsMinTxFeeTxType returns whether the tx type is subject to the minimum fees
+func IsMinTxFeeTxType(txType types.TxType) bool {
+	for _, t := range MinTxFeeTxTypes {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
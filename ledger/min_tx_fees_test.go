@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestMinTxFees(t *testing.T) {
+	assert := assert.New(t)
+
+	viper.Set(common.CfgMempoolMinTxFees, map[string]string{
+		"send":           "1000",
+		"smart_contract": "50",
+	})
+	defer viper.Set(common.CfgMempoolMinTxFees, map[string]string{})
+
+	minTxFees, err := NewMinTxFeesFromConfig()
+	assert.Nil(err)
+	assert.Equal(big.NewInt(1000), minTxFees.Get(types.TxSend))
+	assert.Equal(big.NewInt(50), minTxFees.Get(types.TxSmartContract))
+	assert.Nil(minTxFees.Get(types.TxVesting))
+
+	assert.True(minTxFees.Check(&types.SendTx{Fee: types.NewCoins(0, 1000)}).IsOK())
+	res := minTxFees.Check(&types.SendTx{Fee: types.NewCoins(0, 999)})
+	assert.True(res.IsError())
+	assert.Equal(result.CodeInvalidFee, res.Code)
+
+	assert.True(minTxFees.Check(&types.SmartContractTx{GasPrice: big.NewInt(50)}).IsOK())
+	res = minTxFees.Check(&types.SmartContractTx{GasPrice: big.NewInt(49)})
+	assert.Equal(result.CodeInvalidGasPrice, res.Code)
+
+	// No minimum for the vesting txs
+	assert.True(minTxFees.Check(&types.VestingTx{}).IsOK())
+
+	// Adjusted at runtime
+	minTxFees.Set(types.TxSend, big.NewInt(2000))
+	assert.True(minTxFees.Check(&types.SendTx{Fee: types.NewCoins(0, 1000)}).IsError())
+	minTxFees.Set(types.TxSend, big.NewInt(0))
+	assert.Nil(minTxFees.Get(types.TxSend))
+	assert.True(minTxFees.Check(&types.SendTx{Fee: types.NewCoins(0, 1)}).IsOK())
+
+	viper.Set(common.CfgMempoolMinTxFees, map[string]string{"coinbase": "1"})
+	_, err = NewMinTxFeesFromConfig()
+	assert.NotNil(err)
+	viper.Set(common.CfgMempoolMinTxFees, map[string]string{"send": "-1"})
+	_, err = NewMinTxFeesFromConfig()
+	assert.NotNil(err)
+}
@@ -0,0 +1,148 @@
+package ledger
+
+import (
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+//
+// txBatch collects consecutive send transactions of a block which touch disjoint sets of accounts.
+// A send transaction only reads and writes the accounts of its inputs and outputs (and the vesting
+// schedules of the input accounts), so the transactions of a batch are independent of each other
+// and can be executed in parallel. Executing them in any order leads to the same state as the
+// serial execution.
+//
+type txBatch struct {
+	txs       []types.Tx
+	addresses map[common.Address]bool
+	start     time.Time // when the first transaction was added
+}
+
+func newTxBatch() *txBatch {
+	return &txBatch{
+		addresses: make(map[common.Address]bool),
+	}
+}
+
+// add appends the transaction to the batch, it returns false if the transaction is not a send
+// transaction or touches an account of the transactions already in the batch
+func (b *txBatch) add(tx types.Tx) bool {
+	if _, ok := tx.(*types.SendTx); !ok {
+		return false
+	}
+	addresses := types.TxAccountAddresses(tx)
+	for _, address := range addresses {
+		if b.addresses[address] {
+			return false
+		}
+	}
+	for _, address := range addresses {
+		b.addresses[address] = true
+	}
+	if len(b.txs) == 0 {
+		b.start = time.Now()
+	}
+	b.txs = append(b.txs, tx)
+	return true
+}
+
+// processTime returns the processing time of each transaction of the batch. The transactions are
+// executed together, so they share the time since the first one was added.
+func (b *txBatch) processTime() []time.Duration {
+	elapsed := time.Since(b.start)
+	ret := make([]time.Duration, len(b.txs))
+	for i := range ret {
+		ret[i] = elapsed
+	}
+	return ret
+}
+
+// executeTxBatch executes the independent transactions of a batch on the view, in parallel if
+// there are more than one. It falls back to the serial execution if the parallel one fails.
+func (ledger *Ledger) executeTxBatch(view *st.StoreView, txs []types.Tx) result.Result {
+	if len(txs) > 1 {
+		if ledger.executeTxsInParallel(view, txs) {
+			return result.OK
+		}
+		logger.Debugf("Parallel execution of %v txs failed, falling back to the serial execution", len(txs))
+	}
+	for _, tx := range txs {
+		_, res := ledger.executor.ExecuteTx(tx)
+		if res.IsError() {
+			return res
+		}
+	}
+	return result.OK
+}
+
+// executeTxsInParallel executes each transaction on its own copy of the view with a pool of
+// workers, and then applies the keys the transactions have written to the view. The copies do not
+// commit to the database, the ref count updates of the account state trees are only made when the
+// writes are applied. It returns false and leaves the view and the database untouched if any of the
+// transactions failed, or if the transactions turn out to write the same keys.
+func (ledger *Ledger) executeTxsInParallel(view *st.StoreView, txs []types.Tx) bool {
+	views := make([]*st.StoreView, len(txs))
+	for i := range txs {
+		txView, err := view.Copy()
+		if err != nil {
+			logger.Warnf("Failed to copy the view for the parallel execution: %v", err)
+			return false
+		}
+		txView.RecordWrites()
+		txView.DeferRefCountUpdates()
+		views[i] = txView
+	}
+
+	numWorkers := runtime.NumCPU()
+	if numWorkers > len(txs) {
+		numWorkers = len(txs)
+	}
+
+	idxCh := make(chan int, len(txs))
+	for i := range txs {
+		idxCh <- i
+	}
+	close(idxCh)
+
+	results := make([]result.Result, len(txs))
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range idxCh {
+				_, results[idx] = ledger.executor.ExecuteTxOnView(views[idx], txs[idx])
+			}
+		}()
+	}
+	wg.Wait()
+
+	written := make(map[string]bool)
+	writes := make([]map[string]common.Bytes, len(txs))
+	for i := range txs {
+		if results[i].IsError() {
+			return false
+		}
+		writes[i] = views[i].Writes()
+		for key := range writes[i] {
+			if written[key] {
+				logger.Debugf("Conflicting writes of the parallel txs: key = %v", common.Bytes2Hex([]byte(key)))
+				return false
+			}
+			written[key] = true
+		}
+	}
+
+	for i, txWrites := range writes {
+		view.RecordBurnedFees(views[i].PopBurnedFees())
+		view.RecordGasUsed(views[i].PopGasUsed())
+		view.ApplyWrites(txWrites)
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestTxBatch(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID, ledger, _ := newTestLedger()
+	accOut, accIns := prepareInitLedgerState(ledger, 4)
+
+	parseTx := func(rawTx common.Bytes) types.Tx {
+		tx, err := types.TxFromBytes(rawTx)
+		assert.Nil(err)
+		return tx
+	}
+
+	batch := newTxBatch()
+	assert.True(batch.add(parseTx(newRawSendTx(chainID, 1, true, accIns[1], accIns[0], false))))
+	assert.True(batch.add(parseTx(newRawSendTx(chainID, 1, true, accIns[3], accIns[2], false))))
+
+	// Conflicting accounts
+	assert.False(batch.add(parseTx(newRawSendTx(chainID, 1, true, accOut, accIns[1], false))))
+	assert.Equal(2, len(batch.txs))
+
+	// Only the send txs are batched
+	assert.False(newTxBatch().add(&types.SmartContractTx{}))
+}
+
+func TestParallelExecution(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID, serialLedger, _ := newTestLedger()
+	_, parallelLedger, _ := newTestLedger()
+	accOut, accIns := prepareInitLedgerState(serialLedger, 5)
+	prepareInitLedgerState(parallelLedger, 5)
+	assert.Equal(serialLedger.state.Delivered().Hash(), parallelLedger.state.Delivered().Hash())
+
+	blockRawTxs := []common.Bytes{
+		newRawSendTx(chainID, 1, true, accIns[1], accIns[0], false),
+		newRawSendTx(chainID, 1, true, accIns[3], accIns[2], false),
+		newRawSendTx(chainID, 1, true, accOut, accIns[4], false),
+		newRawSendTx(chainID, 1, true, accOut, accIns[1], false), // conflicts with the txs above
+		newRawSendTx(chainID, 2, true, accIns[0], accIns[2], false),
+	}
+	block := &core.Block{BlockHeader: &core.BlockHeader{Height: 1}, Txs: blockRawTxs}
+	blockLimits := serialLedger.state.Delivered().GetBlockLimits(chainID)
+
+	serialView := serialLedger.state.Delivered()
+	_, _, res := serialLedger.executeBlockTxs(serialView, block, blockLimits, nil, false)
+	assert.True(res.IsOK(), res.Message)
+
+	parallelView := parallelLedger.state.Delivered()
+	_, _, res = parallelLedger.executeBlockTxs(parallelView, block, blockLimits, nil, true)
+	assert.True(res.IsOK(), res.Message)
+
+	assert.Equal(serialView.Hash(), parallelView.Hash())
+	for _, acc := range accIns {
+		assert.Equal(serialView.GetAccount(acc.Address).Balance, parallelView.GetAccount(acc.Address).Balance)
+	}
+
+	// A failed tx in a batch fails the block the same way as the serial execution
+	invalidTxs := []common.Bytes{
+		newRawSendTx(chainID, 2, true, accIns[1], accIns[0], false),
+		newRawSendTx(chainID, 5, true, accIns[3], accIns[2], false), // invalid sequence
+	}
+	block = &core.Block{BlockHeader: &core.BlockHeader{Height: 1}, Txs: invalidTxs}
+	_, _, res = parallelLedger.executeBlockTxs(parallelLedger.state.Delivered(), block, blockLimits, nil, true)
+	assert.True(res.IsError())
+}
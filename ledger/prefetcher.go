@@ -0,0 +1,97 @@
+package ledger
+
+import (
+	"sync/atomic"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/ledger/vm"
+	"github.com/thetatoken/theta/store/database"
+)
+
+//
+// txPrefetcher warms the trie nodes the transactions of a block are going to touch, while the
+// transactions are being validated and executed one by one. It reads the accounts on a separate
+// view of the parent block state, so the trie nodes end up in the database caches without
+// interfering with the state the block is applied to. The smart contract transactions are
+// executed speculatively on the separate view to load the contract code and the storage slots.
+// The results of the speculative execution are discarded.
+//
+type txPrefetcher struct {
+	parentBlock *core.Block
+	view        *st.StoreView
+	rawTxs      []common.Bytes
+
+	executed int64 // number of the transactions the ledger has already executed
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+func newTxPrefetcher(db database.Database, parentBlock *core.Block, rawTxs []common.Bytes) *txPrefetcher {
+	view := st.NewStoreView(parentBlock.Height, parentBlock.StateHash, db)
+	if view == nil {
+		return nil
+	}
+	return &txPrefetcher{
+		parentBlock: parentBlock,
+		view:        view,
+		rawTxs:      rawTxs,
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// start launches the prefetching in the background
+func (p *txPrefetcher) start() {
+	go p.mainLoop()
+}
+
+// advance notifies the prefetcher that the ledger has executed the first numExecuted
+// transactions, which are no longer worth prefetching
+func (p *txPrefetcher) advance(numExecuted int) {
+	atomic.StoreInt64(&p.executed, int64(numExecuted))
+}
+
+// stop terminates the prefetching and waits for the background goroutine to exit
+func (p *txPrefetcher) stop() {
+	close(p.quit)
+	<-p.done
+}
+
+func (p *txPrefetcher) mainLoop() {
+	defer close(p.done)
+	for idx, rawTx := range p.rawTxs {
+		select {
+		case <-p.quit:
+			return
+		default:
+		}
+		if int64(idx) < atomic.LoadInt64(&p.executed) {
+			continue
+		}
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		p.prefetch(tx)
+	}
+}
+
+func (p *txPrefetcher) prefetch(tx types.Tx) {
+	defer func() {
+		if err := recover(); err != nil {
+			// The speculative execution runs on a state that can be off from the actual one, the
+			// failures are expected and harmless
+			logger.Debugf("Failed to prefetch tx %v: %v", tx, err)
+		}
+	}()
+
+	for _, address := range types.TxAccountAddresses(tx) {
+		p.view.GetAccount(address)
+	}
+	if sctx, ok := tx.(*types.SmartContractTx); ok {
+		vm.Execute(p.parentBlock, sctx, p.view)
+	}
+}
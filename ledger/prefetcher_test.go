@@ -0,0 +1,41 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+func TestTxPrefetcher(t *testing.T) {
+	assert := assert.New(t)
+
+	chainID, ledger, _ := newTestLedger()
+	accOut, accIns := prepareInitLedgerState(ledger, 2)
+
+	blockRawTxs := []common.Bytes{
+		newRawSendTx(chainID, 1, true, accOut, accIns[0], false),
+		common.Bytes("invalid tx"),
+		newRawSendTx(chainID, 1, true, accOut, accIns[1], false),
+	}
+	stateRoot := ledger.state.Delivered().Hash()
+	parentBlock := &core.Block{BlockHeader: &core.BlockHeader{Height: 1, StateHash: stateRoot}}
+
+	prefetcher := newTxPrefetcher(ledger.db, parentBlock, blockRawTxs)
+	assert.NotNil(prefetcher)
+	prefetcher.start()
+	<-prefetcher.done
+	prefetcher.stop()
+
+	// The prefetcher reads on its own view of the parent state
+	assert.NotNil(prefetcher.view.GetAccount(accIns[1].Account.Address))
+	assert.Equal(stateRoot, prefetcher.view.Hash())
+	assert.Equal(stateRoot, ledger.state.Delivered().Hash())
+
+	// Stopping the prefetcher before it finishes
+	prefetcher = newTxPrefetcher(ledger.db, parentBlock, blockRawTxs)
+	prefetcher.advance(len(blockRawTxs))
+	prefetcher.start()
+	prefetcher.stop()
+}
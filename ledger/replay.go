@@ -0,0 +1,180 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/store/database"
+)
+
+// BlockReplay is the outcome of replaying a block
+type BlockReplay struct {
+	Height            uint64
+	BlockHash         common.Hash
+	ExpectedStateRoot common.Hash // the state root recorded in the block
+	StateRoot         common.Hash // the state root computed by the replay
+}
+
+// Diverged returns true if the replay did not reach the state root recorded in the block
+func (r *BlockReplay) Diverged() bool {
+	return r.StateRoot != r.ExpectedStateRoot
+}
+
+//
+// Replayer re-applies the finalized blocks of a chain on top of an imported state, and verifies
+// the state root after each block. The blocks are applied the same way as by the consensus
+// engine, except that the mempool and the tx receipts are left untouched. It is meant for
+// reproducing a state transition bug offline: the first diverging block pinpoints the bug.
+//
+type Replayer struct {
+	chain  *blockchain.Chain
+	ledger *Ledger
+}
+
+// NewReplayer creates a Replayer of the blocks in the chain. The state of the parent of the first
+// replayed block must be present in the db, and the replayed states are committed to the db.
+func NewReplayer(chainID string, db database.Database, chain *blockchain.Chain, valMgr core.ValidatorManager) *Replayer {
+	engine := &replayConsensusEngine{chain: chain}
+	ledger := NewLedger(chainID, db, replayTagger{}, chain, engine, valMgr, nil)
+	engine.ledger = ledger
+	valMgr.SetConsensusEngine(engine)
+
+	return &Replayer{
+		chain:  chain,
+		ledger: ledger,
+	}
+}
+
+// Replay re-applies the finalized blocks from height fromHeight to toHeight, calling the callback
+// after each block. It stops at the first block that does not reach its recorded state root, and
+// returns its replay. It returns nil if all the blocks are verified.
+func (r *Replayer) Replay(fromHeight, toHeight uint64, callback func(*BlockReplay)) (*BlockReplay, error) {
+	if fromHeight == 0 || fromHeight > toHeight {
+		return nil, fmt.Errorf("Invalid height range [%v, %v]", fromHeight, toHeight)
+	}
+	parentBlock, err := r.findFinalizedBlock(fromHeight - 1)
+	if err != nil {
+		return nil, err
+	}
+	for height := fromHeight; height <= toHeight; height++ {
+		block, err := r.findFinalizedBlock(height)
+		if err != nil {
+			return nil, err
+		}
+		if block.Parent != parentBlock.Hash() {
+			return nil, fmt.Errorf("The finalized block %v at height %v is not a child of block %v",
+				block.Hash().Hex(), height, parentBlock.Hash().Hex())
+		}
+
+		stateRoot, res := r.ledger.ReplayBlock(parentBlock, block)
+		if res.IsError() {
+			return nil, fmt.Errorf("Failed to replay block %v at height %v: %v", block.Hash().Hex(), height, res.Message)
+		}
+		replay := &BlockReplay{
+			Height:            height,
+			BlockHash:         block.Hash(),
+			ExpectedStateRoot: block.StateHash,
+			StateRoot:         stateRoot,
+		}
+		if callback != nil {
+			callback(replay)
+		}
+		if replay.Diverged() {
+			return replay, nil
+		}
+		parentBlock = block
+	}
+	return nil, nil
+}
+
+func (r *Replayer) findFinalizedBlock(height uint64) (*core.Block, error) {
+	for _, eb := range r.chain.FindBlocksByHeight(height) {
+		if eb.Status.IsFinalized() {
+			return eb.Block, nil
+		}
+	}
+	return nil, fmt.Errorf("No finalized block found at height %v", height)
+}
+
+// ReplayBlock re-applies the transactions of a finalized block on top of the state of its parent,
+// and returns the resulting state root. Unlike ApplyBlockTxs, the state is committed even if the
+// root does not match the one recorded in the block, and the mempool and the tx receipts are left
+// untouched.
+func (ledger *Ledger) ReplayBlock(parentBlock, block *core.Block) (stateRoot common.Hash, res result.Result) {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	defer func() {
+		if err := recover(); err != nil {
+			stateRoot, res = common.Hash{}, result.Error("Panic during the replay: %v", err)
+		}
+	}()
+
+	if res := ledger.resetState(parentBlock); res.IsError() {
+		return common.Hash{}, res
+	}
+
+	ledger.currentBlock = block
+	defer func() { ledger.currentBlock = nil }()
+
+	view := ledger.state.Delivered()
+	if ledger.recordsWrites() {
+		view.RecordWrites()
+	}
+	blockLimits := view.GetBlockLimits(ledger.state.GetChainID())
+	ledger.executor.PreverifySignatures(block.Txs)
+	if _, _, res := ledger.executeBlockTxs(view, block, blockLimits, nil, false); res.IsError() {
+		ledger.resetState(parentBlock)
+		return common.Hash{}, res
+	}
+	ledger.handleDelayedStateUpdates(view)
+
+	stateRoot = view.Hash()
+	if ledger.invariantChecker != nil {
+		ledger.invariantChecker.CheckBlock(parentBlock, block, view)
+	}
+	if ledger.stateDiffRecorder != nil {
+		ledger.stateDiffRecorder.Record(parentBlock, block, view)
+	}
+	ledger.state.Commit()
+	return stateRoot, result.OK
+}
+
+// replayTagger does not tag the replayed states, which are never pruned
+type replayTagger struct{}
+
+func (replayTagger) Tag(height uint64, root common.Hash) {}
+
+// replayConsensusEngine provides the chain and the ledger to the executor and the validator
+// manager during a replay
+type replayConsensusEngine struct {
+	chain  *blockchain.Chain
+	ledger *Ledger
+}
+
+func (e *replayConsensusEngine) ID() string                        { return "" }
+func (e *replayConsensusEngine) PrivateKey() *crypto.PrivateKey    { return nil }
+func (e *replayConsensusEngine) Signer() core.Signer               { return nil }
+func (e *replayConsensusEngine) GetTip(bool) *core.ExtendedBlock   { return nil }
+func (e *replayConsensusEngine) GetEpoch() uint64                  { return 0 }
+func (e *replayConsensusEngine) GetLedger() core.Ledger            { return e.ledger }
+func (e *replayConsensusEngine) AddMessage(msg interface{})        {}
+func (e *replayConsensusEngine) FinalizedBlocks() chan *core.Block { return nil }
+func (e *replayConsensusEngine) GetLastFinalizedBlock() *core.ExtendedBlock {
+	return nil
+}
+func (e *replayConsensusEngine) FindBlock(blockHash common.Hash) (*core.ExtendedBlock, error) {
+	eb, err := e.chain.FindBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if eb == nil {
+		return nil, errors.New("Block not found")
+	}
+	return eb, nil
+}
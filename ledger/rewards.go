@@ -0,0 +1,46 @@
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	exec "github.com/thetatoken/theta/ledger/execution"
+	st "github.com/thetatoken/theta/ledger/state"
+)
+
+// GetBlockRewardEvents recalculates the rewards paid by the coinbase transaction of the block on the
+// state of its parent, and returns the reward events. Only the checkpoint blocks pay rewards. It
+// returns an error if the states needed for the calculation have been pruned.
+func (ledger *Ledger) GetBlockRewardEvents(block *core.Block) ([]*blockchain.RewardEvent, error) {
+	if !common.IsCheckPointHeight(block.Height) {
+		return []*blockchain.RewardEvent{}, nil
+	}
+
+	parent, err := ledger.chain.FindBlock(block.Parent)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find the parent block %v: %v", block.Parent.Hex(), err)
+	}
+	view := st.NewStoreView(parent.Height, parent.StateHash, ledger.db)
+	if view == nil {
+		return nil, fmt.Errorf("The state of height %v is not available, it might have been pruned", parent.Height)
+	}
+
+	// RetrievePools loads the pools from the state of the block the guardians voted for
+	guardianVotes := block.GuardianVotes
+	if guardianVotes != nil && block.Height >= common.HeightEnableTheta2 {
+		voteBlock, err := ledger.chain.FindBlock(guardianVotes.Block)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find the block %v voted by the guardians: %v", guardianVotes.Block.Hex(), err)
+		}
+		if st.NewStoreView(voteBlock.Height, voteBlock.StateHash, ledger.db) == nil {
+			return nil, fmt.Errorf("The state of height %v is not available, it might have been pruned", voteBlock.Height)
+		}
+	}
+
+	validatorSet := ledger.valMgr.GetNextValidatorSet(block.Parent)
+	guardianPool, eliteEdgeNodePool := exec.RetrievePools(ledger, ledger.chain, ledger.db, block.Height, guardianVotes, block.EliteEdgeNodeVotes)
+	_, events := exec.CalculateRewardEvents(ledger, view, validatorSet, guardianVotes, guardianPool, block.EliteEdgeNodeVotes, eliteEdgeNodePool)
+	return events, nil
+}
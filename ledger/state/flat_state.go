@@ -0,0 +1,126 @@
+package state
+
+import (
+	"fmt"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/thetatoken/theta/common"
+)
+
+//
+// FlatState is a flattened key-value table of the latest committed state, which serves the reads
+// of the hot accounts and storage slots with O(1) lookups instead of trie traversals.
+//
+// The entries of the state trie are only valid for the state root the table is at. At each commit,
+// the table moves to the new root and takes the values of the keys the committed view has written,
+// so the table follows the chain incrementally. Resetting the ledger state to a different root
+// purges the entries. The storage slots are keyed by the storage root of the account, which
+// identifies the content of the account storage, so they never go stale.
+//
+// The table only holds the most recently used entries, the other reads fall through to the trie
+// and fill the table.
+//
+type FlatState struct {
+	mu      *sync.RWMutex
+	root    common.Hash
+	entries *lru.Cache // state key -> value, nil if the key is not in the state
+	slots   *lru.Cache // account storage root + slot -> value
+}
+
+// NewFlatState creates a FlatState holding up to size entries and up to size storage slots
+func NewFlatState(size int) (*FlatState, error) {
+	entries, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the flat state entries: %v", err)
+	}
+	slots, err := lru.New(size)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create the flat state slots: %v", err)
+	}
+	return &FlatState{
+		mu:      &sync.RWMutex{},
+		entries: entries,
+		slots:   slots,
+	}, nil
+}
+
+// Root returns the state root the entries are valid for
+func (fs *FlatState) Root() common.Hash {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.root
+}
+
+// Reset moves the table to the given root. The entries are purged if the root changes.
+func (fs *FlatState) Reset(root common.Hash) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.root != root {
+		fs.entries.Purge()
+		fs.root = root
+	}
+}
+
+// Update moves the table from the parent root to the root of the committed state, with the values
+// of the keys written in between. The entries are purged if the table is not at the parent root.
+func (fs *FlatState) Update(parentRoot common.Hash, root common.Hash, writes map[string]common.Bytes) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.root != parentRoot {
+		fs.entries.Purge()
+	} else {
+		for key, value := range writes {
+			fs.entries.Add(key, value)
+		}
+	}
+	fs.root = root
+}
+
+// get returns the value of the key in the state at the given root, and whether the table has it
+func (fs *FlatState) get(root common.Hash, key common.Bytes) (common.Bytes, bool) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	if fs.root != root {
+		return nil, false
+	}
+	value, ok := fs.entries.Get(string(key))
+	if !ok {
+		return nil, false
+	}
+	return value.(common.Bytes), true
+}
+
+// add puts the value of the key read from the state at the given root into the table
+func (fs *FlatState) add(root common.Hash, key common.Bytes, value common.Bytes) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	if fs.root != root {
+		return
+	}
+	fs.entries.Add(string(key), value)
+}
+
+func slotKey(storageRoot common.Hash, slot common.Hash) string {
+	return string(append(storageRoot.Bytes(), slot.Bytes()...))
+}
+
+// getSlot returns the value of the slot in the account storage with the given root, and whether
+// the table has it
+func (fs *FlatState) getSlot(storageRoot common.Hash, slot common.Hash) (common.Hash, bool) {
+	value, ok := fs.slots.Get(slotKey(storageRoot, slot))
+	if !ok {
+		return common.Hash{}, false
+	}
+	return value.(common.Hash), true
+}
+
+// addSlot puts the value of the slot in the account storage with the given root into the table
+func (fs *FlatState) addSlot(storageRoot common.Hash, slot common.Hash, value common.Hash) {
+	fs.slots.Add(slotKey(storageRoot, slot), value)
+}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+type noopTagger struct{}
+
+func (t noopTagger) Tag(height uint64, root common.Hash) {}
+
+func TestFlatState(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	ls := NewLedgerState("testchain", db, noopTagger{})
+	flat, err := NewFlatState(16)
+	assert.Nil(err)
+	ls.SetFlatState(flat)
+	ls.ResetState(&core.Block{BlockHeader: &core.BlockHeader{Height: 1, StateHash: common.Hash{}}})
+
+	key1 := common.Bytes("key1")
+	key2 := common.Bytes("key2")
+	ls.Delivered().Set(key1, common.Bytes("a"))
+	root1 := ls.Commit()
+	assert.Equal(root1, flat.Root())
+
+	// The committed writes are in the table
+	value, ok := flat.get(root1, key1)
+	assert.True(ok)
+	assert.Equal(common.Bytes("a"), value)
+
+	// The absent keys are cached as well
+	_, ok = flat.get(root1, key2)
+	assert.False(ok)
+	assert.Nil(ls.Checked().Get(key2))
+	value, ok = flat.get(root1, key2)
+	assert.True(ok)
+	assert.Nil(value)
+
+	// The uncommitted writes are read from the trie, and do not leak into the table
+	ls.Checked().Set(key1, common.Bytes("b"))
+	assert.Equal(common.Bytes("b"), ls.Checked().Get(key1))
+	assert.Equal(common.Bytes("a"), ls.Delivered().Get(key1))
+	assert.Equal(common.Bytes("a"), ls.Screened().Get(key1))
+
+	ls.Delivered().Set(key1, common.Bytes("c"))
+	ls.Delivered().Set(key2, common.Bytes("d"))
+	root2 := ls.Commit()
+	assert.Equal(root2, flat.Root())
+	assert.Equal(common.Bytes("c"), ls.Checked().Get(key1))
+	assert.Equal(common.Bytes("d"), ls.Checked().Get(key2))
+
+	// The entries are not served for the other roots
+	_, ok = flat.get(root1, key1)
+	assert.False(ok)
+
+	// Resetting to a different root purges the entries
+	ls.ResetState(&core.Block{BlockHeader: &core.BlockHeader{Height: 2, StateHash: root1}})
+	assert.Equal(root1, flat.Root())
+	_, ok = flat.get(root1, key2)
+	assert.False(ok)
+	assert.Equal(common.Bytes("a"), ls.Delivered().Get(key1))
+	assert.Nil(ls.Delivered().Get(key2))
+
+	// The storage slots are keyed by the storage root
+	storageRoot := common.BytesToHash([]byte("storage"))
+	slot := common.BytesToHash([]byte("slot"))
+	_, ok = flat.getSlot(storageRoot, slot)
+	assert.False(ok)
+	flat.addSlot(storageRoot, slot, common.BytesToHash([]byte("value")))
+	slotValue, ok := flat.getSlot(storageRoot, slot)
+	assert.True(ok)
+	assert.Equal(common.BytesToHash([]byte("value")), slotValue)
+}
@@ -17,7 +17,12 @@ func ChainIDKey() common.Bytes {
 
 // AccountKey constructs the state key for the given address
 func AccountKey(addr common.Address) common.Bytes {
-	return append(common.Bytes("ls/a/"), addr[:]...)
+	return append(AccountKeyPrefix(), addr[:]...)
+}
+
+// AccountKeyPrefix returns the prefix of the account keys
+func AccountKeyPrefix() common.Bytes {
+	return common.Bytes("ls/a/")
 }
 
 // SplitRuleKeyPrefix returns the prefix for the split rule key
@@ -31,9 +36,24 @@ func SplitRuleKey(resourceID string) common.Bytes {
 	return append(SplitRuleKeyPrefix(), resourceIDBytes[:]...)
 }
 
+// VestingSchedulesKeyPrefix returns the prefix for the vesting schedules key
+func VestingSchedulesKeyPrefix() common.Bytes {
+	return common.Bytes("ls/vest/")
+}
+
+// VestingSchedulesKey constructs the state key for the vesting schedules of the given address
+func VestingSchedulesKey(addr common.Address) common.Bytes {
+	return append(VestingSchedulesKeyPrefix(), addr[:]...)
+}
+
+// CodeKeyPrefix returns the prefix of the code keys
+func CodeKeyPrefix() common.Bytes {
+	return common.Bytes("ls/ch/")
+}
+
 // CodeKey constructs the state key for the given code hash
 func CodeKey(codeHash common.Bytes) common.Bytes {
-	return append(common.Bytes("ls/ch/"), codeHash...)
+	return append(CodeKeyPrefix(), codeHash...)
 }
 
 // ValidatorCandidatePoolKey returns the state key for the validator stake holder set
@@ -41,6 +61,49 @@ func ValidatorCandidatePoolKey() common.Bytes {
 	return common.Bytes("ls/vcp")
 }
 
+// ValidatorBLSPubkeyKeyPrefix returns the prefix of the validator BLS pubkey key
+func ValidatorBLSPubkeyKeyPrefix() common.Bytes {
+	return common.Bytes("ls/vbls/")
+}
+
+// ValidatorBLSPubkeyKey returns the key of the BLS pubkey registered by the given validator
+func ValidatorBLSPubkeyKey(addr common.Address) common.Bytes {
+	prefix := ValidatorBLSPubkeyKeyPrefix()
+	return append(prefix, addr[:]...)
+}
+
+// ValidatorSigningKeyKeyPrefix returns the prefix of the validator signing key key
+func ValidatorSigningKeyKeyPrefix() common.Bytes {
+	return common.Bytes("ls/vsk/")
+}
+
+// ValidatorSigningKeyKey returns the key of the signing key rotation of the given validator stake holder
+func ValidatorSigningKeyKey(holder common.Address) common.Bytes {
+	prefix := ValidatorSigningKeyKeyPrefix()
+	return append(prefix, holder[:]...)
+}
+
+// BlockLimitsKey returns the state key for the block limits set by governance
+func BlockLimitsKey() common.Bytes {
+	return common.Bytes("ls/blim")
+}
+
+// BaseFeeKey returns the state key for the base fee of the smart contract transactions
+func BaseFeeKey() common.Bytes {
+	return common.Bytes("ls/bfee")
+}
+
+// TotalBurnedFeesKey returns the state key for the TFuel burned by the transaction fees
+func TotalBurnedFeesKey() common.Bytes {
+	return common.Bytes("ls/tbf")
+}
+
+// RecentBlockHashKey returns the state key of the slot holding the hash of the recent block at the given height
+func RecentBlockHashKey(height uint64) common.Bytes {
+	slot := strconv.FormatUint(height%NumRecentBlockHashes, 10)
+	return common.Bytes("ls/rbh/" + slot)
+}
+
 // GuardianCandidatePoolKey returns the state key for the guadian stake holder set
 func GuardianCandidatePoolKey() common.Bytes {
 	return common.Bytes("ls/gcp")
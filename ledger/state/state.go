@@ -29,6 +29,8 @@ type LedgerState struct {
 	delivered *StoreView // for actually applying the transactions
 	checked   *StoreView // for block proposal check
 	screened  *StoreView // for mempool screening
+
+	flat *FlatState // flattened table of the latest committed state, nil if disabled
 }
 
 // NewLedgerState creates a new Leger State with given store.
@@ -63,6 +65,10 @@ func (s *LedgerState) ResetState(block *core.Block) result.Result {
 		return result.Error(fmt.Sprintf("Failed to set ledger state with state root hash: %v", stateRootHash))
 	}
 	s.delivered = storeview
+	if s.flat != nil {
+		s.flat.Reset(stateRootHash)
+		s.delivered.attachFlatState(s.flat, stateRootHash)
+	}
 
 	var err error
 	s.checked, err = s.delivered.Copy()
@@ -77,6 +83,12 @@ func (s *LedgerState) ResetState(block *core.Block) result.Result {
 	return result.OK
 }
 
+// SetFlatState sets the flat state the delivered, checked and screened views read through.
+// It takes effect from the next ResetState() call.
+func (s *LedgerState) SetFlatState(flat *FlatState) {
+	s.flat = flat
+}
+
 // Finalize updates the finalized view.
 func (s *LedgerState) Finalize(height uint64, stateRootHash common.Hash) result.Result {
 	storeview := NewStoreView(height, stateRootHash, s.db)
@@ -135,7 +147,15 @@ func (s *LedgerState) Finalized() *StoreView {
 // Commit stores the current delivered view as committed, starts new delivered/checked state and
 // returns the hash for the commit.
 func (s *LedgerState) Commit() common.Hash {
+	var writes map[string]common.Bytes
+	if s.flat != nil {
+		writes = s.delivered.dirtyEntries()
+	}
 	hash := s.delivered.Save()
+	if s.flat != nil {
+		s.flat.Update(s.delivered.flatRoot, hash, writes)
+		s.delivered.attachFlatState(s.flat, hash)
+	}
 	s.delivered.IncrementHeight()
 	s.dbTagger.Tag(s.delivered.height, hash)
 
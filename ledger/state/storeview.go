@@ -9,10 +9,12 @@ import (
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
 	"github.com/thetatoken/theta/ledger/types"
 	"github.com/thetatoken/theta/rlp"
 	"github.com/thetatoken/theta/store/database"
 	"github.com/thetatoken/theta/store/treestore"
+	"github.com/thetatoken/theta/store/trie"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "ledger"})
@@ -29,6 +31,20 @@ type StoreView struct {
 	slashIntents                []types.SlashIntent
 	refund                      uint64       // Gas refund during smart contract execution
 	logs                        []*types.Log // Temporary store of events during smart contract execution
+
+	internalTxs      []*types.InternalTransaction // Temporary store of value transfers made by contracts during smart contract execution
+	burnedFees       *big.Int                     // Temporary store of the TFuel fees burned by the transactions of a block
+	gasUsed          uint64                       // Temporary store of the gas used by the smart contract transactions of a block
+	lapsedSplitRules []*types.SplitRule           // Temporary store of the split rules deleted after their end block heights
+
+	flat     *FlatState          // Flattened table of the committed state to read through, nil if disabled
+	flatRoot common.Hash         // The state root the view started from
+	dirty    map[string]struct{} // Keys written since the view started from flatRoot
+
+	writes     map[string]struct{}                         // Keys written since RecordWrites was called, nil if not recording
+	slotWrites map[common.Address]map[common.Hash]struct{} // Storage slots written since RecordWrites was called
+
+	deferRefCountUpdates bool // Whether to leave the ref count updates of the account state trees to ApplyWrites
 }
 
 // NewStoreView creates an instance of the StoreView
@@ -59,9 +75,108 @@ func (sv *StoreView) Copy() (*StoreView, error) {
 		slashIntents: []types.SlashIntent{},
 		refund:       0,
 	}
+	if sv.flat != nil {
+		copiedStoreView.attachFlatState(sv.flat, sv.flatRoot)
+		for key := range sv.dirty {
+			copiedStoreView.dirty[key] = struct{}{}
+		}
+	}
 	return copiedStoreView, nil
 }
 
+// attachFlatState makes the view read through the flat state. The view must not have
+// written anything since it started from the given root.
+func (sv *StoreView) attachFlatState(flat *FlatState, root common.Hash) {
+	sv.flat = flat
+	sv.flatRoot = root
+	sv.dirty = make(map[string]struct{})
+}
+
+func (sv *StoreView) markDirty(key common.Bytes) {
+	if sv.flat != nil {
+		sv.dirty[string(key)] = struct{}{}
+	}
+	if sv.writes != nil {
+		sv.writes[string(key)] = struct{}{}
+	}
+}
+
+// dirtyEntries returns the current values of the keys written since the view started from flatRoot
+func (sv *StoreView) dirtyEntries() map[string]common.Bytes {
+	entries := make(map[string]common.Bytes, len(sv.dirty))
+	for key := range sv.dirty {
+		entries[key] = sv.store.Get(common.Bytes(key))
+	}
+	return entries
+}
+
+// RecordWrites makes the view record the keys and the storage slots written from now on, see
+// Writes and WrittenSlots
+func (sv *StoreView) RecordWrites() {
+	sv.writes = make(map[string]struct{})
+	sv.slotWrites = make(map[common.Address]map[common.Hash]struct{})
+}
+
+// Writes returns the current values of the keys written since RecordWrites was called. The values
+// of the deleted keys are nil.
+func (sv *StoreView) Writes() map[string]common.Bytes {
+	entries := make(map[string]common.Bytes, len(sv.writes))
+	for key := range sv.writes {
+		entries[key] = sv.store.Get(common.Bytes(key))
+	}
+	return entries
+}
+
+// DeferRefCountUpdates makes the view skip the ref count updates of the account state trees when
+// setting the accounts, so that a copy of the view does not commit to the shared database. The
+// updates are made once the writes of the copy are applied to the original view, see ApplyWrites.
+func (sv *StoreView) DeferRefCountUpdates() {
+	sv.deferRefCountUpdates = true
+}
+
+// ApplyWrites sets the keys written on a copy of the view, see Writes. The values of the deleted
+// keys are nil. The ref count updates of the account state trees deferred on the copy are made here.
+func (sv *StoreView) ApplyWrites(writes map[string]common.Bytes) {
+	accountKeyPrefix := AccountKeyPrefix()
+	for key, value := range writes {
+		if value == nil {
+			sv.Delete(common.Bytes(key))
+			continue
+		}
+		sv.Set(common.Bytes(key), value)
+
+		if !bytes.HasPrefix(common.Bytes(key), accountKeyPrefix) {
+			continue
+		}
+		acc := &types.Account{}
+		if err := types.FromBytes(value, acc); err != nil {
+			log.Panicf("Error reading account %X, error: %v", value, err.Error())
+		}
+		sv.updateAccountStorageRefCount(acc)
+	}
+}
+
+// WrittenSlots returns the storage slots of the smart contracts written since RecordWrites was called
+func (sv *StoreView) WrittenSlots() map[common.Address][]common.Hash {
+	slots := make(map[common.Address][]common.Hash, len(sv.slotWrites))
+	for addr, keys := range sv.slotWrites {
+		for key := range keys {
+			slots[addr] = append(slots[addr], key)
+		}
+	}
+	return slots
+}
+
+func (sv *StoreView) markSlotDirty(addr common.Address, key common.Hash) {
+	if sv.slotWrites == nil {
+		return
+	}
+	if sv.slotWrites[addr] == nil {
+		sv.slotWrites[addr] = make(map[common.Hash]struct{})
+	}
+	sv.slotWrites[addr][key] = struct{}{}
+}
+
 // GetDB returns the underlying database.
 func (sv *StoreView) GetDB() database.Database {
 	return sv.store.GetDB()
@@ -96,7 +211,17 @@ func (sv *StoreView) Save() common.Hash {
 
 // Get returns the value corresponding to the key
 func (sv *StoreView) Get(key common.Bytes) common.Bytes {
+	if sv.flat == nil {
+		return sv.store.Get(key)
+	}
+	if _, ok := sv.dirty[string(key)]; ok {
+		return sv.store.Get(key)
+	}
+	if value, ok := sv.flat.get(sv.flatRoot, key); ok {
+		return value
+	}
 	value := sv.store.Get(key)
+	sv.flat.add(sv.flatRoot, key, value)
 	return value
 }
 
@@ -110,13 +235,20 @@ func (sv *StoreView) ProveVCP(vcpKey []byte, vp *core.VCPProof) error {
 	return sv.store.ProveVCP(vcpKey, vp)
 }
 
+// Prove collects the Merkle proof of the given key against the state root
+func (sv *StoreView) Prove(key []byte, proof *core.VCPProof) error {
+	return sv.store.Prove(key, proof)
+}
+
 // Delete removes the value corresponding to the key
 func (sv *StoreView) Delete(key common.Bytes) {
+	sv.markDirty(key)
 	sv.store.Delete(key)
 }
 
 // Set returns the value corresponding to the key
 func (sv *StoreView) Set(key common.Bytes, value common.Bytes) {
+	sv.markDirty(key)
 	sv.store.Set(key, value)
 }
 
@@ -187,16 +319,19 @@ func (sv *StoreView) setAccount(addr common.Address, acc *types.Account, updateR
 	}
 	sv.Set(AccountKey(addr), accBytes)
 
-	if !updateRefCountForAccountStateTree {
+	if !updateRefCountForAccountStateTree || sv.deferRefCountUpdates {
 		return
 	}
+	sv.updateAccountStorageRefCount(acc)
+}
 
+func (sv *StoreView) updateAccountStorageRefCount(acc *types.Account) {
 	if (acc == nil || acc.Root == common.Hash{}) || (acc.Root == core.EmptyRootHash) {
 		return
 	}
 
 	tree := sv.getAccountStorage(acc)
-	_, err = tree.Commit() // update the reference count of the account state trie root
+	_, err := tree.Commit() // update the reference count of the account state trie root
 	if err != nil {
 		log.Panic(err)
 	}
@@ -260,18 +395,38 @@ func (sv *StoreView) SetSplitRule(resourceID string, splitRule *types.SplitRule)
 // DeleteSplitRule deletes a split rule.
 func (sv *StoreView) DeleteSplitRule(resourceID string) bool {
 	key := SplitRuleKey(resourceID)
+	sv.markDirty(key)
 	deleted := sv.store.Delete(key)
 	return deleted
 }
 
+// GetSplitRulesByInitiator gets the split rules initiated by the given address, including the expired
+// ones which are not deleted yet.
+func (sv *StoreView) GetSplitRulesByInitiator(initiator common.Address) []*types.SplitRule {
+	splitRules := []*types.SplitRule{}
+	sv.store.Traverse(SplitRuleKeyPrefix(), func(key, value common.Bytes) bool {
+		splitRule := &types.SplitRule{}
+		err := types.FromBytes(value, splitRule)
+		if err != nil {
+			log.Panicf("Error reading splitRule %X error: %v", value, err.Error())
+		}
+		if splitRule.InitiatorAddress == initiator {
+			splitRules = append(splitRules, splitRule)
+		}
+		return true
+	})
+	return splitRules
+}
+
 // DeleteExpiredSplitRules deletes a split rule.
 func (sv *StoreView) DeleteExpiredSplitRules(currentBlockHeight uint64) bool {
 	prefix := SplitRuleKeyPrefix()
 
 	expiredKeys := []common.Bytes{}
+	expiredSplitRules := []*types.SplitRule{}
 	sv.store.Traverse(prefix, func(key, value common.Bytes) bool {
-		var splitRule types.SplitRule
-		err := types.FromBytes(value, &splitRule)
+		splitRule := &types.SplitRule{}
+		err := types.FromBytes(value, splitRule)
 		if err != nil {
 			log.Panicf("Error reading splitRule %X error: %v", value, err.Error())
 		}
@@ -279,21 +434,74 @@ func (sv *StoreView) DeleteExpiredSplitRules(currentBlockHeight uint64) bool {
 		expired := (splitRule.EndBlockHeight < currentBlockHeight)
 		if expired {
 			expiredKeys = append(expiredKeys, key)
+			expiredSplitRules = append(expiredSplitRules, splitRule)
 		}
 		return true
 	})
 
-	for _, key := range expiredKeys {
+	for i, key := range expiredKeys {
+		sv.markDirty(key)
 		deleted := sv.store.Delete(key)
 		if !deleted {
 			logger.Errorf("Failed to delete expired split rules")
 			return false
 		}
+		sv.AddLapsedSplitRule(expiredSplitRules[i])
 	}
 
 	return true
 }
 
+// GetVestingSchedules gets the vesting schedules of the account
+func (sv *StoreView) GetVestingSchedules(addr common.Address) []types.VestingSchedule {
+	data := sv.Get(VestingSchedulesKey(addr))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	schedules := []types.VestingSchedule{}
+	err := types.FromBytes(data, &schedules)
+	if err != nil {
+		log.Panicf("Error reading vesting schedules %X error: %v", data, err.Error())
+	}
+	return schedules
+}
+
+// SetVestingSchedules sets the vesting schedules of the account
+func (sv *StoreView) SetVestingSchedules(addr common.Address, schedules []types.VestingSchedule) {
+	if len(schedules) == 0 {
+		sv.Delete(VestingSchedulesKey(addr))
+		return
+	}
+	schedulesBytes, err := types.ToBytes(schedules)
+	if err != nil {
+		log.Panicf("Error writing vesting schedules %v error: %v", schedules, err.Error())
+	}
+	sv.Set(VestingSchedulesKey(addr), schedulesBytes)
+}
+
+// AddVestingSchedule adds a vesting schedule to the account, and removes the schedules that have ended
+func (sv *StoreView) AddVestingSchedule(addr common.Address, schedule types.VestingSchedule, currentBlockHeight uint64) {
+	schedules := []types.VestingSchedule{}
+	for _, vs := range sv.GetVestingSchedules(addr) {
+		if vs.EndHeight > currentBlockHeight {
+			schedules = append(schedules, vs)
+		}
+	}
+	schedules = append(schedules, schedule)
+	sv.SetVestingSchedules(addr, schedules)
+}
+
+// GetLockedCoins returns the coins of the account locked by the vesting schedules at the given block height
+func (sv *StoreView) GetLockedCoins(addr common.Address, blockHeight uint64) types.Coins {
+	return types.TotalLockedCoins(sv.GetVestingSchedules(addr), blockHeight)
+}
+
+// HasLockedCoins returns whether the vesting schedules of the account lock part of its balance at
+// the block being executed
+func (sv *StoreView) HasLockedCoins(addr common.Address) bool {
+	return !sv.GetLockedCoins(addr, sv.GetBlockHeight()).IsZero()
+}
+
 // GetValidatorCandidatePool gets the validator candidate pool.
 func (sv *StoreView) GetValidatorCandidatePool() *core.ValidatorCandidatePool {
 	data := sv.Get(ValidatorCandidatePoolKey())
@@ -319,6 +527,229 @@ func (sv *StoreView) UpdateValidatorCandidatePool(vcp *core.ValidatorCandidatePo
 	sv.Set(ValidatorCandidatePoolKey(), vcpBytes)
 }
 
+// GetValidatorBLSPubkey gets the BLS pubkey registered by the given validator, returns nil if
+// the validator has not registered one.
+func (sv *StoreView) GetValidatorBLSPubkey(addr common.Address) *bls.PublicKey {
+	data := sv.Get(ValidatorBLSPubkeyKey(addr))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	pubkey, err := bls.PublicKeyFromBytes(data)
+	if err != nil {
+		log.Panicf("Error reading validator BLS pubkey %X, error: %v",
+			data, err.Error())
+	}
+	return pubkey
+}
+
+// SetValidatorBLSPubkey sets the BLS pubkey of the given validator.
+func (sv *StoreView) SetValidatorBLSPubkey(addr common.Address, pubkey *bls.PublicKey) {
+	sv.Set(ValidatorBLSPubkeyKey(addr), pubkey.ToBytes())
+}
+
+// GetValidatorSigningKey gets the signing key rotation of the given validator stake holder, returns
+// nil if the holder has never rotated its key.
+func (sv *StoreView) GetValidatorSigningKey(holder common.Address) *types.ValidatorSigningKey {
+	data := sv.Get(ValidatorSigningKeyKey(holder))
+	if data == nil || len(data) == 0 {
+		return nil
+	}
+	key := &types.ValidatorSigningKey{}
+	err := types.FromBytes(data, key)
+	if err != nil {
+		log.Panicf("Error reading validator signing key %X, error: %v",
+			data, err.Error())
+	}
+	return key
+}
+
+// SetValidatorSigningKey sets the signing key rotation of the given validator stake holder.
+func (sv *StoreView) SetValidatorSigningKey(holder common.Address, key *types.ValidatorSigningKey) {
+	keyBytes, err := types.ToBytes(key)
+	if err != nil {
+		log.Panicf("Error writing validator signing key %v, error: %v",
+			key, err.Error())
+	}
+	sv.Set(ValidatorSigningKeyKey(holder), keyBytes)
+}
+
+// GetValidatorSigningAddress returns the address of the key the given validator stake holder signs
+// the votes and proposals with at the given block height.
+func (sv *StoreView) GetValidatorSigningAddress(holder common.Address, height uint64) common.Address {
+	key := sv.GetValidatorSigningKey(holder)
+	if key == nil {
+		return holder
+	}
+	return key.SigningAddress(height)
+}
+
+// ProveValidatorSigningKeys collects the Merkle proofs of the signing key rotations of the given validator
+// stake holders into the proof, including the proofs of absence for the holders that never rotated their keys.
+func (sv *StoreView) ProveValidatorSigningKeys(holders []common.Address, proof *core.VCPProof) error {
+	for _, holder := range holders {
+		if err := sv.Prove(ValidatorSigningKeyKey(holder), proof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProveValidatorBLSPubkeys collects the Merkle proofs of the BLS pubkeys registered by the given validators
+// into the proof, including the proofs of absence for the validators without a registered BLS pubkey.
+func (sv *StoreView) ProveValidatorBLSPubkeys(validators []common.Address, proof *core.VCPProof) error {
+	for _, validator := range validators {
+		if err := sv.Prove(ValidatorBLSPubkeyKey(validator), proof); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetValidatorBLSPubkeyFromProof retrieves the BLS pubkey registered by the given validator from the Merkle
+// proof against the state root, returns nil if the proof shows the validator has not registered a BLS pubkey.
+func GetValidatorBLSPubkeyFromProof(stateHash common.Hash, validator common.Address, proof *core.VCPProof) (*bls.PublicKey, error) {
+	data, _, err := trie.VerifyProof(stateHash, ValidatorBLSPubkeyKey(validator), proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return bls.PublicKeyFromBytes(data)
+}
+
+// GetValidatorSigningKeyFromProof retrieves the signing key rotation of the given validator stake holder from
+// the Merkle proof against the state root, returns nil if the proof shows the holder never rotated its key.
+func GetValidatorSigningKeyFromProof(stateHash common.Hash, holder common.Address, proof *core.VCPProof) (*types.ValidatorSigningKey, error) {
+	data, _, err := trie.VerifyProof(stateHash, ValidatorSigningKeyKey(holder), proof)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	key := &types.ValidatorSigningKey{}
+	if err := types.FromBytes(data, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetBlockLimits gets the block limits. It returns the initial limits of the chain if
+// the limits have not been adjusted by governance.
+func (sv *StoreView) GetBlockLimits(chainID string) core.BlockLimits {
+	data := sv.Get(BlockLimitsKey())
+	if data == nil || len(data) == 0 {
+		return core.DefaultBlockLimits(chainID)
+	}
+	limits := core.BlockLimits{}
+	err := types.FromBytes(data, &limits)
+	if err != nil {
+		log.Panicf("Error reading block limits %X, error: %v",
+			data, err.Error())
+	}
+	return limits
+}
+
+// SetBlockLimits sets the block limits.
+func (sv *StoreView) SetBlockLimits(limits core.BlockLimits) {
+	limitsBytes, err := types.ToBytes(&limits)
+	if err != nil {
+		log.Panicf("Error writing block limits %v, error: %v",
+			limits, err.Error())
+	}
+	sv.Set(BlockLimitsKey(), limitsBytes)
+}
+
+// GetBaseFee gets the base fee of the smart contract transactions. It returns the initial base fee
+// if the base fee has not been adjusted by any block yet.
+func (sv *StoreView) GetBaseFee(blockHeight uint64) *big.Int {
+	data := sv.Get(BaseFeeKey())
+	if data == nil || len(data) == 0 {
+		return types.GetInitialBaseFee(blockHeight)
+	}
+	baseFee := new(big.Int)
+	err := types.FromBytes(data, baseFee)
+	if err != nil {
+		log.Panicf("Error reading base fee %X, error: %v",
+			data, err.Error())
+	}
+	return baseFee
+}
+
+// SetBaseFee sets the base fee of the smart contract transactions.
+func (sv *StoreView) SetBaseFee(baseFee *big.Int) {
+	baseFeeBytes, err := types.ToBytes(baseFee)
+	if err != nil {
+		log.Panicf("Error writing base fee %v, error: %v",
+			baseFee, err.Error())
+	}
+	sv.Set(BaseFeeKey(), baseFeeBytes)
+}
+
+// GetTotalBurnedFees gets the TFuel burned by the transaction fees since fee burn tracking is enabled.
+func (sv *StoreView) GetTotalBurnedFees() *big.Int {
+	data := sv.Get(TotalBurnedFeesKey())
+	if data == nil || len(data) == 0 {
+		return big.NewInt(0)
+	}
+	totalBurnedFees := new(big.Int)
+	err := types.FromBytes(data, totalBurnedFees)
+	if err != nil {
+		log.Panicf("Error reading total burned fees %X, error: %v",
+			data, err.Error())
+	}
+	return totalBurnedFees
+}
+
+// SetTotalBurnedFees sets the TFuel burned by the transaction fees since fee burn tracking is enabled.
+func (sv *StoreView) SetTotalBurnedFees(totalBurnedFees *big.Int) {
+	totalBurnedFeesBytes, err := types.ToBytes(totalBurnedFees)
+	if err != nil {
+		log.Panicf("Error writing total burned fees %v, error: %v",
+			totalBurnedFees, err.Error())
+	}
+	sv.Set(TotalBurnedFeesKey(), totalBurnedFeesBytes)
+}
+
+// NumRecentBlockHashes is the number of the recent block hashes kept in the state
+const NumRecentBlockHashes = 256
+
+type recentBlockHash struct {
+	Height uint64
+	Hash   common.Hash
+}
+
+// GetRecentBlockHash gets the hash of the block at the given height, if it is one of the recent blocks
+// recorded in the state. Otherwise it returns an empty hash.
+func (sv *StoreView) GetRecentBlockHash(height uint64) common.Hash {
+	data := sv.Get(RecentBlockHashKey(height))
+	if data == nil || len(data) == 0 {
+		return common.Hash{}
+	}
+	var entry recentBlockHash
+	err := types.FromBytes(data, &entry)
+	if err != nil {
+		log.Panicf("Error reading recent block hash %X, error: %v",
+			data, err.Error())
+	}
+	if entry.Height != height {
+		return common.Hash{} // the slot has been overwritten by a later block
+	}
+	return entry.Hash
+}
+
+// SetRecentBlockHash records the hash of the block at the given height, replacing the hash recorded
+// NumRecentBlockHashes blocks earlier.
+func (sv *StoreView) SetRecentBlockHash(height uint64, hash common.Hash) {
+	entryBytes, err := types.ToBytes(&recentBlockHash{Height: height, Hash: hash})
+	if err != nil {
+		log.Panicf("Error writing recent block hash %v, error: %v",
+			hash, err.Error())
+	}
+	sv.Set(RecentBlockHashKey(height), entryBytes)
+}
+
 // GetGuardianCandidatePool gets the guardian candidate pool.
 func (sv *StoreView) GetGuardianCandidatePool() *core.GuardianCandidatePool {
 	data := sv.Get(GuardianCandidatePoolKey())
@@ -431,6 +862,73 @@ func (sv *StoreView) PopLogs() []*types.Log {
 	return ret
 }
 
+func (sv *StoreView) ResetInternalTxs() {
+	sv.internalTxs = []*types.InternalTransaction{}
+}
+
+func (sv *StoreView) PopInternalTxs() []*types.InternalTransaction {
+	ret := sv.internalTxs
+	sv.ResetInternalTxs()
+	return ret
+}
+
+func (sv *StoreView) AddInternalTx(itx *types.InternalTransaction) {
+	sv.internalTxs = append(sv.internalTxs, itx)
+}
+
+func (sv *StoreView) ResetLapsedSplitRules() {
+	sv.lapsedSplitRules = []*types.SplitRule{}
+}
+
+func (sv *StoreView) PopLapsedSplitRules() []*types.SplitRule {
+	ret := sv.lapsedSplitRules
+	sv.ResetLapsedSplitRules()
+	return ret
+}
+
+// AddLapsedSplitRule records a split rule which lapsed, i.e. deleted after its end block height
+func (sv *StoreView) AddLapsedSplitRule(splitRule *types.SplitRule) {
+	sv.lapsedSplitRules = append(sv.lapsedSplitRules, splitRule)
+}
+
+func (sv *StoreView) ResetBurnedFees() {
+	sv.burnedFees = nil
+}
+
+// PopBurnedFees returns the TFuel fees burned since the last reset, and resets them
+func (sv *StoreView) PopBurnedFees() *big.Int {
+	ret := sv.burnedFees
+	sv.ResetBurnedFees()
+	if ret == nil {
+		return big.NewInt(0)
+	}
+	return ret
+}
+
+// RecordBurnedFees adds the TFuel fee burned by a transaction
+func (sv *StoreView) RecordBurnedFees(amount *big.Int) {
+	if sv.burnedFees == nil {
+		sv.burnedFees = new(big.Int)
+	}
+	sv.burnedFees.Add(sv.burnedFees, amount)
+}
+
+func (sv *StoreView) ResetGasUsed() {
+	sv.gasUsed = 0
+}
+
+// PopGasUsed returns the gas used by the smart contract transactions since the last reset, and resets it
+func (sv *StoreView) PopGasUsed() uint64 {
+	ret := sv.gasUsed
+	sv.ResetGasUsed()
+	return ret
+}
+
+// RecordGasUsed adds the gas used by a smart contract transaction
+func (sv *StoreView) RecordGasUsed(gasUsed uint64) {
+	sv.gasUsed += gasUsed
+}
+
 //
 // ---------- Implement vm.StateDB interface -----------
 //
@@ -545,6 +1043,47 @@ func (sv *StoreView) GetThetaStake(addr common.Address) *big.Int {
 	return totalStake
 }
 
+// GetValidatorStake returns the amount of ThetaWei the source staked to the validator candidate, and the total
+// amount of ThetaWei staked to the validator candidate. Withdrawn stakes do not count
+func (sv *StoreView) GetValidatorStake(source common.Address, holder common.Address) (stake *big.Int, holderTotalStake *big.Int) {
+	vcp := sv.GetValidatorCandidatePool()
+	return getStakeOfHolder(vcp.FindStakeDelegate(holder), source)
+}
+
+// GetGuardianStake returns the amount of ThetaWei the source staked to the guardian, and the total amount of
+// ThetaWei staked to the guardian. Withdrawn stakes do not count
+func (sv *StoreView) GetGuardianStake(source common.Address, holder common.Address) (stake *big.Int, holderTotalStake *big.Int) {
+	gcp := sv.GetGuardianCandidatePool()
+	g := gcp.GetWithHolderAddress(holder)
+	if g == nil {
+		return big.NewInt(0), big.NewInt(0)
+	}
+	return getStakeOfHolder(g.StakeHolder, source)
+}
+
+// GetRewardDistribution returns the beneficiary and the split basis point of the stake reward of the given holder.
+// The split basis point is zero if the holder has no reward distribution rule
+func (sv *StoreView) GetRewardDistribution(holder common.Address) (beneficiary common.Address, splitBasisPoint uint) {
+	rewardDistr := NewStakeRewardDistributionRuleSet(sv).Get(holder)
+	if rewardDistr == nil {
+		return common.Address{}, 0
+	}
+	return rewardDistr.Beneficiary, rewardDistr.SplitBasisPoint
+}
+
+func getStakeOfHolder(stakeHolder *core.StakeHolder, source common.Address) (stake *big.Int, holderTotalStake *big.Int) {
+	stake = big.NewInt(0)
+	if stakeHolder == nil {
+		return stake, big.NewInt(0)
+	}
+	for _, s := range stakeHolder.Stakes {
+		if s.Source == source && !s.Withdrawn {
+			stake = new(big.Int).Add(stake, s.Amount)
+		}
+	}
+	return stake, stakeHolder.TotalStake()
+}
+
 func (sv *StoreView) GetNonce(addr common.Address) uint64 {
 	return sv.GetOrCreateAccount(addr).Sequence
 }
@@ -633,6 +1172,13 @@ func (sv *StoreView) GetState(addr common.Address, key common.Hash) common.Hash
 	}
 	logger.Debugf("StoreView.GetState, address: %v, account.root: %v, key: %v", addr, account.Root.Hex(), key.Hex())
 
+	if sv.flat != nil {
+		if value, ok := sv.flat.getSlot(account.Root, key); ok {
+			return value
+		}
+	}
+
+	value := common.Hash{}
 	enc, err := sv.getAccountStorage(account).TryGet(key[:])
 	if err != nil {
 		log.Panic(err)
@@ -642,12 +1188,17 @@ func (sv *StoreView) GetState(addr common.Address, key common.Hash) common.Hash
 		if err != nil {
 			log.Panic(err)
 		}
-		return common.BytesToHash(content)
+		value = common.BytesToHash(content)
 	}
-	return common.Hash{}
+
+	if sv.flat != nil {
+		sv.flat.addSlot(account.Root, key, value)
+	}
+	return value
 }
 
 func (sv *StoreView) SetState(addr common.Address, key, val common.Hash) {
+	sv.markSlotDirty(addr, key)
 	account := sv.GetAccount(addr)
 	if account == nil {
 		account = types.NewAccount(addr)
@@ -169,6 +169,7 @@ func TestStoreViewSplitRuleAccess(t *testing.T) {
 	assert.Nil(sv.GetSplitRule(rid1))
 	assert.Nil(sv.GetSplitRule(rid2))
 	assert.Nil(sv.GetSplitRule(rid3))
+	assert.Equal(2, len(sv.PopLapsedSplitRules()))
 
 	sv.SetSplitRule(rid1, sc1)
 	sv.SetSplitRule(rid2, sc2)
@@ -177,6 +178,17 @@ func TestStoreViewSplitRuleAccess(t *testing.T) {
 	assert.NotNil(sv.GetSplitRule(rid1))
 	assert.Nil(sv.GetSplitRule(rid2))
 	assert.NotNil(sv.GetSplitRule(rid3))
+	lapsedSplitRules := sv.PopLapsedSplitRules()
+	assert.Equal(1, len(lapsedSplitRules))
+	assert.Equal(rid2, lapsedSplitRules[0].ResourceID)
+	assert.Equal(0, len(sv.PopLapsedSplitRules()))
+
+	sv.SetSplitRule("rid4", &types.SplitRule{ResourceID: "rid4", EndBlockHeight: 50})
+	splitRules := sv.GetSplitRulesByInitiator(initiatorAddr)
+	assert.Equal(2, len(splitRules))
+	assert.Equal(rid1, splitRules[0].ResourceID)
+	assert.Equal(rid3, splitRules[1].ResourceID)
+	assert.Equal(0, len(sv.GetSplitRulesByInitiator(common.Address{1})))
 }
 
 func TestRevertAndPruneStoreView(t *testing.T) {
@@ -254,6 +266,40 @@ func TestRevertAndPruneStoreView(t *testing.T) {
 	assert.Equal(value2, sv.GetState(acc1Addr, key1))
 }
 
+func TestApplyWritesDefersRefCounts(t *testing.T) {
+	assert := assert.New(t)
+
+	accAddr := common.HexToAddress("0x111")
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+	sv.SetAccount(accAddr, &types.Account{
+		Address: accAddr,
+		Balance: types.Coins{ThetaWei: big.NewInt(786), TFuelWei: big.NewInt(0)},
+	})
+	sv.SetState(accAddr, common.BytesToHash([]byte{1}), common.BytesToHash([]byte{11}))
+	sv.Save()
+
+	root := sv.GetAccount(accAddr).Root
+	ref0, _ := db.CountReference(root[:])
+
+	// Setting the account on the copy does not touch the ref count in the shared database
+	cp, err := sv.Copy()
+	assert.Nil(err)
+	cp.RecordWrites()
+	cp.DeferRefCountUpdates()
+	acc := cp.GetAccount(accAddr)
+	acc.Balance = types.Coins{ThetaWei: big.NewInt(1000), TFuelWei: big.NewInt(0)}
+	cp.SetAccount(accAddr, acc)
+	ref, _ := db.CountReference(root[:])
+	assert.Equal(ref0, ref)
+
+	// The ref count is updated once the writes are applied
+	sv.ApplyWrites(cp.Writes())
+	ref, _ = db.CountReference(root[:])
+	assert.Equal(ref0+1, ref)
+	assert.Equal("1000", sv.GetAccount(accAddr).Balance.ThetaWei.String())
+}
+
 func TestGetAndUpdateValidatorCandidatePool(t *testing.T) {
 	assert := assert.New(t)
 
@@ -386,6 +432,72 @@ func TestGetAndUpdateHeightList(t *testing.T) {
 	log.Infof("")
 }
 
+func TestGetAndSetBlockLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	limits := sv.GetBlockLimits(core.MainnetChainID)
+	assert.Equal(core.DefaultBlockLimits(core.MainnetChainID), limits)
+	assert.Nil(limits.Validate())
+
+	adjusted := core.BlockLimits{MaxGas: 50e6, MaxSize: 1024 * 1024}
+	sv.SetBlockLimits(adjusted)
+	assert.Equal(adjusted, sv.GetBlockLimits(core.MainnetChainID))
+	assert.Equal(adjusted, sv.GetBlockLimits("privatenet"))
+}
+
+func TestBurnedFees(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	assert.Equal(big.NewInt(0), sv.PopBurnedFees())
+	sv.RecordBurnedFees(big.NewInt(300))
+	sv.RecordBurnedFees(big.NewInt(200))
+	assert.Equal(big.NewInt(500), sv.PopBurnedFees())
+	assert.Equal(big.NewInt(0), sv.PopBurnedFees())
+
+	assert.Equal(big.NewInt(0), sv.GetTotalBurnedFees())
+	sv.SetTotalBurnedFees(big.NewInt(1000))
+	assert.Equal(big.NewInt(1000), sv.GetTotalBurnedFees())
+}
+
+func TestGasUsed(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	assert.Equal(uint64(0), sv.PopGasUsed())
+	sv.RecordGasUsed(21000)
+	sv.RecordGasUsed(53000)
+	assert.Equal(uint64(74000), sv.PopGasUsed())
+	assert.Equal(uint64(0), sv.PopGasUsed())
+
+	sv.RecordGasUsed(21000)
+	sv.ResetGasUsed()
+	assert.Equal(uint64(0), sv.PopGasUsed())
+}
+
+func TestRecentBlockHashes(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(1), common.Hash{}, db)
+
+	assert.Equal(common.Hash{}, sv.GetRecentBlockHash(10))
+	sv.SetRecentBlockHash(10, common.HexToHash("0x0a"))
+	assert.Equal(common.HexToHash("0x0a"), sv.GetRecentBlockHash(10))
+
+	// The later block in the same slot replaces the earlier one
+	sv.SetRecentBlockHash(10+NumRecentBlockHashes, common.HexToHash("0x0b"))
+	assert.Equal(common.HexToHash("0x0b"), sv.GetRecentBlockHash(10+NumRecentBlockHashes))
+	assert.Equal(common.Hash{}, sv.GetRecentBlockHash(10))
+}
+
 // ------------------------ Utilities ------------------------ //
 
 func compareValidatorCandidatePools(vcp1, vcp2 *core.ValidatorCandidatePool) bool {
@@ -443,3 +555,35 @@ func compareHeightList(hl1, hl2 *types.HeightList) bool {
 
 	return true
 }
+
+func TestVestingSchedules(t *testing.T) {
+	assert := assert.New(t)
+
+	db := backend.NewMemDatabase()
+	sv := NewStoreView(uint64(99), common.Hash{}, db)
+	addr := common.HexToAddress("0x1234")
+
+	assert.False(sv.HasLockedCoins(addr))
+
+	sv.AddVestingSchedule(addr, types.VestingSchedule{
+		Coins:       types.NewCoins(1000, 0),
+		StartHeight: 100,
+		CliffHeight: 100,
+		EndHeight:   200,
+	}, sv.Height())
+	assert.Equal(1, len(sv.GetVestingSchedules(addr)))
+	assert.True(sv.HasLockedCoins(addr))
+	assert.True(sv.GetLockedCoins(addr, 150).IsEqual(types.NewCoins(500, 0)))
+
+	// The ended schedules are removed when a schedule is added
+	sv = NewStoreView(uint64(200), sv.Save(), db)
+	assert.False(sv.HasLockedCoins(addr))
+	sv.AddVestingSchedule(addr, types.VestingSchedule{
+		Coins:       types.NewCoins(0, 1000),
+		StartHeight: 200,
+		CliffHeight: 300,
+		EndHeight:   400,
+	}, sv.Height())
+	assert.Equal(1, len(sv.GetVestingSchedules(addr)))
+	assert.True(sv.HasLockedCoins(addr))
+}
@@ -0,0 +1,162 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/store/database"
+)
+
+// maxRecentStateDiffs is the number of the recent state diffs kept in memory for the queries
+const maxRecentStateDiffs = 100
+
+// StateDiffEntry is the change of the value of a state key, or of a storage slot of a smart contract.
+// The values are nil if absent.
+type StateDiffEntry struct {
+	Key    string // the state key in hex, or <contract address>/<slot> in hex for a storage slot
+	Before common.Bytes
+	After  common.Bytes
+}
+
+// String returns the entry as "<key> <before> <after>", where the values are in hex, and "-" if absent
+func (e StateDiffEntry) String() string {
+	return fmt.Sprintf("%v %v %v", e.Key, formatStateDiffValue(e.Before), formatStateDiffValue(e.After))
+}
+
+func formatStateDiffValue(value common.Bytes) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return hex.EncodeToString(value)
+}
+
+// StateDiff is the changes of the state made by a block, sorted by the keys
+type StateDiff struct {
+	BlockHeight     uint64
+	BlockHash       common.Hash
+	ParentStateRoot common.Hash
+	StateRoot       common.Hash
+	Entries         []StateDiffEntry
+}
+
+// Lines returns the canonical text of the diff, a header followed by one line per entry. Two
+// implementations applying the same block from the same state produce the same lines.
+func (d *StateDiff) Lines() []string {
+	lines := []string{fmt.Sprintf("# height %v block %v parent_state_root %v state_root %v",
+		d.BlockHeight, d.BlockHash.Hex(), d.ParentStateRoot.Hex(), d.StateRoot.Hex())}
+	for _, entry := range d.Entries {
+		lines = append(lines, entry.String())
+	}
+	return lines
+}
+
+//
+// StateDiffRecorder computes the diff of the state for each applied block, and saves it to a file
+// in the configured directory, and keeps the recent ones in memory for the queries. It is meant for
+// the differential testing against the alternative implementations, where the first diverging key
+// localizes a consensus splitting bug.
+//
+type StateDiffRecorder struct {
+	mu     *sync.Mutex
+	db     database.Database
+	dir    string       // where the diffs are saved, no file is saved if empty
+	recent []*StateDiff // the most recent last
+}
+
+// NewStateDiffRecorder creates a StateDiffRecorder of the ledger state in the given database
+func NewStateDiffRecorder(db database.Database) *StateDiffRecorder {
+	return &StateDiffRecorder{
+		mu:     &sync.Mutex{},
+		db:     db,
+		dir:    viper.GetString(common.CfgLedgerStateDiffDir),
+		recent: []*StateDiff{},
+	}
+}
+
+// Record computes the diff of the state made by the block, where the view has recorded the writes
+// since the parent state
+func (r *StateDiffRecorder) Record(parentBlock, block *core.Block, view *st.StoreView) {
+	parent := st.NewStoreView(parentBlock.Height, parentBlock.StateHash, r.db)
+	diff := &StateDiff{
+		BlockHeight:     block.Height,
+		BlockHash:       block.Hash(),
+		ParentStateRoot: parentBlock.StateHash,
+		StateRoot:       block.StateHash,
+		Entries:         computeStateDiff(parent, view),
+	}
+
+	if r.dir != "" {
+		filePath := path.Join(r.dir, fmt.Sprintf("%v_%v.diff", block.Height, block.Hash().Hex()))
+		raw := []byte(strings.Join(diff.Lines(), "\n") + "\n")
+		if err := common.WriteFileAtomic(filePath, raw, 0600); err != nil {
+			logger.Warnf("Failed to save the state diff %v: %v", filePath, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recent = append(r.recent, diff)
+	if len(r.recent) > maxRecentStateDiffs {
+		r.recent = r.recent[len(r.recent)-maxRecentStateDiffs:]
+	}
+}
+
+// Get returns the recent diff of the block with the given hash, or of the latest block applied at
+// the given height if the hash is empty
+func (r *StateDiffRecorder) Get(height uint64, hash common.Hash) (*StateDiff, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.recent) - 1; i >= 0; i-- {
+		diff := r.recent[i]
+		if (hash.IsEmpty() && diff.BlockHeight == height) || (!hash.IsEmpty() && diff.BlockHash == hash) {
+			return diff, true
+		}
+	}
+	return nil, false
+}
+
+// computeStateDiff returns the changed keys and storage slots from the parent view to the view,
+// which has recorded the writes, sorted by the keys
+func computeStateDiff(parent *st.StoreView, view *st.StoreView) []StateDiffEntry {
+	entries := []StateDiffEntry{}
+	for key, after := range view.Writes() {
+		before := parent.Get(common.Bytes(key))
+		if bytes.Equal(before, after) {
+			continue
+		}
+		entries = append(entries, StateDiffEntry{Key: hex.EncodeToString([]byte(key)), Before: before, After: after})
+	}
+	for addr, slots := range view.WrittenSlots() {
+		for _, slot := range slots {
+			before := parent.GetState(addr, slot)
+			after := view.GetState(addr, slot)
+			if before == after {
+				continue
+			}
+			entries = append(entries, StateDiffEntry{
+				Key:    hex.EncodeToString(addr[:]) + "/" + hex.EncodeToString(slot[:]),
+				Before: slotValue(before),
+				After:  slotValue(after),
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+func slotValue(value common.Hash) common.Bytes {
+	if value.IsEmpty() {
+		return nil
+	}
+	return value[:]
+}
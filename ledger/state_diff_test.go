@@ -0,0 +1,51 @@
+package ledger
+
+import (
+	"encoding/hex"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestComputeStateDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ledger, _ := newTestLedger()
+	_, accIns := prepareInitLedgerState(ledger, 2)
+	view := ledger.state.Delivered()
+	parent := st.NewStoreView(view.Height(), view.Hash(), ledger.db)
+	addr1 := accIns[0].Account.Address
+	addr2 := accIns[1].Account.Address
+	slot := common.BytesToHash([]byte{1})
+
+	view.RecordWrites()
+	acc1 := view.GetAccount(addr1)
+	acc1.Balance = acc1.Balance.Plus(types.NewCoins(1, 0))
+	view.SetAccount(addr1, acc1)
+	view.SetAccount(addr2, view.GetAccount(addr2)) // unchanged
+	view.SetState(addr2, slot, common.BytesToHash([]byte{2}))
+	view.Set(common.Bytes("test/key"), common.Bytes("value"))
+
+	entries := computeStateDiff(parent, view)
+	keys := []string{}
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	assert.True(sort.StringsAreSorted(keys))
+	assert.Contains(keys, hex.EncodeToString(st.AccountKey(addr1)))
+	assert.Contains(keys, hex.EncodeToString(st.AccountKey(addr2))) // the storage root changed
+	assert.Contains(keys, hex.EncodeToString(addr2[:])+"/"+hex.EncodeToString(slot[:]))
+	assert.Contains(keys, hex.EncodeToString([]byte("test/key")))
+	assert.Equal(4, len(entries))
+
+	diff := &StateDiff{BlockHeight: 10, Entries: entries}
+	lines := diff.Lines()
+	assert.Equal(5, len(lines))
+	assert.True(strings.HasPrefix(lines[0], "# height 10 "))
+	assert.Contains(lines, hex.EncodeToString([]byte("test/key"))+" - "+hex.EncodeToString([]byte("value")))
+}
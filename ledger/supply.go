@@ -0,0 +1,71 @@
+package ledger
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	st "github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// Supply is the amount of Theta and TFuel held in the ledger state
+type Supply struct {
+	ThetaWei *big.Int
+	TFuelWei *big.Int
+}
+
+// CalculateSupply sums up the coins held in the ledger state, i.e. the balances and the reserved
+// funds of the accounts, and the stakes of the validator candidates, guardians and elite edge nodes.
+// Since the state reflects the minted rewards and the burned fees, so does the total.
+func CalculateSupply(view *st.StoreView) (*Supply, error) {
+	supply := &Supply{
+		ThetaWei: new(big.Int),
+		TFuelWei: new(big.Int),
+	}
+	addCoins := func(coins types.Coins) {
+		coins = coins.NoNil()
+		supply.ThetaWei.Add(supply.ThetaWei, coins.ThetaWei)
+		supply.TFuelWei.Add(supply.TFuelWei, coins.TFuelWei)
+	}
+
+	var err error
+	prefix := st.AccountKeyPrefix()
+	view.Traverse(prefix, func(key, value common.Bytes) bool {
+		var account types.Account
+		if err = rlp.DecodeBytes(value, &account); err != nil {
+			err = fmt.Errorf("Failed to decode account %v: %v", common.Bytes2Hex(key[len(prefix):]), err)
+			return false
+		}
+		addCoins(account.Balance)
+		for _, fund := range account.ReservedFunds {
+			addCoins(fund.Collateral)
+			addCoins(fund.InitialFund.Minus(fund.UsedFund))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	addStakes := func(holder *core.StakeHolder, amount *big.Int) {
+		for _, stake := range holder.Stakes {
+			amount.Add(amount, stake.Amount)
+		}
+	}
+	if vcp := view.GetValidatorCandidatePool(); vcp != nil {
+		for _, candidate := range vcp.SortedCandidates {
+			addStakes(candidate, supply.ThetaWei)
+		}
+	}
+	for _, guardian := range view.GetGuardianCandidatePool().SortedGuardians {
+		addStakes(guardian.StakeHolder, supply.ThetaWei)
+	}
+	for _, een := range st.NewEliteEdgeNodePool(view, true).GetAll(false) {
+		addStakes(een.StakeHolder, supply.TFuelWei)
+	}
+
+	return supply, nil
+}
@@ -0,0 +1,47 @@
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestCalculateSupply(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ledger, _ := newTestLedger()
+	accOut, accIns := prepareInitLedgerState(ledger, 5)
+
+	view := ledger.state.Delivered()
+	expected := types.NewCoins(0, 0)
+	for _, val := range ledger.valMgr.GetValidatorSet(common.Hash{}).Validators() {
+		expected = expected.Plus(view.GetAccount(val.Address).Balance)
+	}
+	expected = expected.Plus(accOut.Account.Balance)
+	for _, accIn := range accIns {
+		expected = expected.Plus(accIn.Account.Balance)
+	}
+
+	supply, err := CalculateSupply(view)
+	assert.Nil(err)
+	assert.Equal(expected.ThetaWei, supply.ThetaWei)
+	assert.Equal(expected.TFuelWei, supply.TFuelWei)
+
+	// The reserved funds are part of the supply
+	account := view.GetAccount(accOut.Account.Address)
+	account.ReserveFund(types.NewCoins(0, 100), types.NewCoins(0, 200), []string{"rid"}, 100, 1)
+	view.SetAccount(account.Address, account)
+	supply, err = CalculateSupply(view)
+	assert.Nil(err)
+	assert.Equal(expected.TFuelWei, supply.TFuelWei)
+
+	// Minted coins change the supply
+	account.Balance = account.Balance.Plus(types.NewCoins(1, 2))
+	view.SetAccount(account.Address, account)
+	supply, err = CalculateSupply(view)
+	assert.Nil(err)
+	assert.Equal(expected.Plus(types.NewCoins(1, 2)).ThetaWei, supply.ThetaWei)
+	assert.Equal(expected.Plus(types.NewCoins(1, 2)).TFuelWei, supply.TFuelWei)
+}
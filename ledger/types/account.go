@@ -3,10 +3,12 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/pkg/errors"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/rlp"
 )
 
 var EmptyCodeHash = common.BytesToHash(crypto.Keccak256(nil))
@@ -23,6 +25,118 @@ type Account struct {
 	CodeHash common.Hash `json:"code_hash"` // hash of the smart contract code
 }
 
+var _ rlp.Encoder = (*Account)(nil)
+
+// EncodeRLP implements RLP Encoder interface. It produces the same bytes as the reflection
+// based encoding of the struct, but accounts are encoded on every state write, so it is
+// hand-written for speed.
+func (acc *Account) EncodeRLP(w io.Writer) error {
+	if acc == nil {
+		_, err := w.Write(rlp.EmptyList)
+		return err
+	}
+
+	var err error
+	b := make([]byte, 0, 160)
+	b = rlp.AppendString(b, acc.Address[:])
+	b = rlp.AppendUint64(b, acc.Sequence)
+	b, err = appendCoinsRLP(b, acc.Balance)
+	if err != nil {
+		return err
+	}
+	if len(acc.ReservedFunds) == 0 {
+		b = append(b, rlp.EmptyList...)
+	} else {
+		b, err = rlp.AppendValue(b, acc.ReservedFunds)
+		if err != nil {
+			return err
+		}
+	}
+	b = rlp.AppendUint64(b, acc.LastUpdatedBlockHeight)
+	b = rlp.AppendString(b, acc.Root[:])
+	b = rlp.AppendString(b, acc.CodeHash[:])
+
+	_, err = w.Write(rlp.AppendList(nil, b))
+	return err
+}
+
+var _ rlp.Decoder = (*Account)(nil)
+
+// DecodeRLP implements RLP Decoder interface.
+func (acc *Account) DecodeRLP(stream *rlp.Stream) error {
+	_, err := stream.List()
+	if err != nil {
+		return err
+	}
+
+	err = stream.ReadBytes(acc.Address[:])
+	if err != nil {
+		return err
+	}
+
+	acc.Sequence, err = stream.Uint()
+	if err != nil {
+		return err
+	}
+
+	acc.Balance, err = decodeCoinsRLP(stream)
+	if err != nil {
+		return err
+	}
+
+	err = stream.Decode(&acc.ReservedFunds)
+	if err != nil {
+		return err
+	}
+
+	acc.LastUpdatedBlockHeight, err = stream.Uint()
+	if err != nil {
+		return err
+	}
+
+	err = stream.ReadBytes(acc.Root[:])
+	if err != nil {
+		return err
+	}
+
+	err = stream.ReadBytes(acc.CodeHash[:])
+	if err != nil {
+		return err
+	}
+
+	return stream.ListEnd()
+}
+
+func appendCoinsRLP(b []byte, coins Coins) ([]byte, error) {
+	var err error
+	content := make([]byte, 0, 32)
+	content, err = rlp.AppendBigInt(content, coins.ThetaWei)
+	if err != nil {
+		return b, err
+	}
+	content, err = rlp.AppendBigInt(content, coins.TFuelWei)
+	if err != nil {
+		return b, err
+	}
+	return rlp.AppendList(b, content), nil
+}
+
+func decodeCoinsRLP(stream *rlp.Stream) (coins Coins, err error) {
+	_, err = stream.List()
+	if err != nil {
+		return coins, err
+	}
+	coins.ThetaWei, err = stream.BigInt()
+	if err != nil {
+		return coins, err
+	}
+	coins.TFuelWei, err = stream.BigInt()
+	if err != nil {
+		return coins, err
+	}
+	return coins, stream.ListEnd()
+}
+
 type AccountJSON struct {
 	Sequence               common.JSONUint64 `json:"sequence"`
 	Balance                Coins             `json:"coins"`
@@ -3,10 +3,13 @@ package types
 import (
 	"encoding/json"
 	"math"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rlp"
 )
 
 func makeAccount(secret string, balance Coins) Account {
@@ -284,3 +287,92 @@ func TestTransferReservedFund4(t *testing.T) {
 // 	}()
 // 	acc.UpdateAccountTFuelReward(currentBlockHeight) // Should panic
 // }
+
+// accountNoEncoder has the fields of Account without its RLP methods, i.e. it is encoded
+// through reflection
+type accountNoEncoder Account
+
+func makeRLPTestAccount() *Account {
+	acc := makeAccountAndReserveFund(NewCoins(1000, 20000), NewCoins(0, 1001), NewCoins(0, 1000), "rid001", 199, 1)
+	acc.Sequence = 1234
+	acc.LastUpdatedBlockHeight = 5678
+	acc.Root = common.BytesToHash([]byte("root"))
+	acc.CodeHash = EmptyCodeHash
+	return &acc
+}
+
+func TestAccountRLP(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	accounts := []*Account{
+		makeRLPTestAccount(),
+		NewAccount(common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")),
+		{Balance: Coins{ThetaWei: nil, TFuelWei: new(big.Int).Lsh(big.NewInt(1), 100)}},
+		{},
+	}
+	for _, acc := range accounts {
+		// The encoding is the same as the reflection based one
+		expected, err := rlp.EncodeToBytes((*accountNoEncoder)(acc))
+		require.Nil(err)
+		encoded, err := rlp.EncodeToBytes(acc)
+		require.Nil(err)
+		assert.Equal(expected, encoded)
+
+		decoded := &Account{}
+		require.Nil(rlp.DecodeBytes(encoded, decoded))
+		assert.Equal(acc.Address, decoded.Address)
+		assert.Equal(acc.Sequence, decoded.Sequence)
+		assert.True(acc.Balance.NoNil().IsEqual(decoded.Balance))
+		reencoded, err := rlp.EncodeToBytes((*accountNoEncoder)(decoded))
+		require.Nil(err)
+		assert.Equal(expected, reencoded)
+	}
+
+	var nilAccount *Account
+	encoded, err := rlp.EncodeToBytes(nilAccount)
+	require.Nil(err)
+	assert.Equal(rlp.EmptyList, encoded)
+
+	// Malformed inputs are rejected
+	encoded, err = rlp.EncodeToBytes(accounts[0])
+	require.Nil(err)
+	assert.NotNil(rlp.DecodeBytes(encoded[:len(encoded)-1], &Account{}))
+	content, _, err := rlp.SplitList(encoded)
+	require.Nil(err)
+	withExtraField := rlp.AppendList(nil, rlp.AppendUint64(append([]byte{}, content...), 1))
+	assert.NotNil(rlp.DecodeBytes(withExtraField, &Account{}))
+	assert.NotNil(rlp.DecodeBytes(rlp.EmptyList, &Account{}))
+}
+
+func BenchmarkAccountEncodeRLP(b *testing.B) {
+	acc := makeRLPTestAccount()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(acc)
+	}
+}
+
+func BenchmarkAccountEncodeRLPReflect(b *testing.B) {
+	acc := (*accountNoEncoder)(makeRLPTestAccount())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.EncodeToBytes(acc)
+	}
+}
+
+func BenchmarkAccountDecodeRLP(b *testing.B) {
+	encoded, _ := rlp.EncodeToBytes(makeRLPTestAccount())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.DecodeBytes(encoded, &Account{})
+	}
+}
+
+func BenchmarkAccountDecodeRLPReflect(b *testing.B) {
+	encoded, _ := rlp.EncodeToBytes(makeRLPTestAccount())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		rlp.DecodeBytes(encoded, &accountNoEncoder{})
+	}
+}
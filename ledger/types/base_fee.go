@@ -0,0 +1,60 @@
+package types
+
+import (
+	"math/big"
+)
+
+const (
+	// BaseFeeElasticityMultiplier bounds the block gas target, i.e. the target is the max block
+	// gas divided by the multiplier
+	BaseFeeElasticityMultiplier uint64 = 2
+
+	// BaseFeeChangeDenominator bounds the change of the base fee between two blocks, i.e. the base
+	// fee changes by at most 1/BaseFeeChangeDenominator of itself per block
+	BaseFeeChangeDenominator uint64 = 8
+)
+
+// GetInitialBaseFee returns the base fee before any block adjusts it, which is the minimum gas price
+func GetInitialBaseFee(blockHeight uint64) *big.Int {
+	return GetMinimumGasPrice(blockHeight)
+}
+
+// CalculateNextBaseFee returns the base fee for the block following the one with the given block
+// gas. The base fee goes up if the block gas is above the target, and down if it is below the
+// target, but never below the minimum gas price.
+func CalculateNextBaseFee(baseFee *big.Int, blockGas uint64, maxBlockGas uint64, blockHeight uint64) *big.Int {
+	minimumGasPrice := GetMinimumGasPrice(blockHeight)
+	if baseFee == nil || baseFee.Cmp(minimumGasPrice) < 0 {
+		baseFee = minimumGasPrice
+	}
+
+	gasTarget := maxBlockGas / BaseFeeElasticityMultiplier
+	if gasTarget == 0 || blockGas == gasTarget {
+		return new(big.Int).Set(baseFee)
+	}
+
+	var gasDelta uint64
+	if blockGas > gasTarget {
+		gasDelta = blockGas - gasTarget
+	} else {
+		gasDelta = gasTarget - blockGas
+	}
+
+	// baseFeeDelta = baseFee * gasDelta / gasTarget / BaseFeeChangeDenominator
+	baseFeeDelta := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasDelta))
+	baseFeeDelta.Div(baseFeeDelta, new(big.Int).SetUint64(gasTarget))
+	baseFeeDelta.Div(baseFeeDelta, new(big.Int).SetUint64(BaseFeeChangeDenominator))
+
+	if blockGas > gasTarget {
+		if baseFeeDelta.Sign() == 0 {
+			baseFeeDelta.SetUint64(1)
+		}
+		return new(big.Int).Add(baseFee, baseFeeDelta)
+	}
+
+	nextBaseFee := new(big.Int).Sub(baseFee, baseFeeDelta)
+	if nextBaseFee.Cmp(minimumGasPrice) < 0 {
+		return minimumGasPrice
+	}
+	return nextBaseFee
+}
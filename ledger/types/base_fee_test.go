@@ -0,0 +1,38 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateNextBaseFee(t *testing.T) {
+	assert := assert.New(t)
+
+	height := uint64(0)
+	minimumGasPrice := GetMinimumGasPrice(height)
+	maxBlockGas := uint64(1000)
+	baseFee := new(big.Int).Mul(minimumGasPrice, big.NewInt(10))
+
+	// At the target the base fee stays the same
+	assert.Equal(0, CalculateNextBaseFee(baseFee, 500, maxBlockGas, height).Cmp(baseFee))
+
+	// A full block raises the base fee by 1/8
+	expected := new(big.Int).Add(baseFee, new(big.Int).Div(baseFee, big.NewInt(8)))
+	assert.Equal(0, CalculateNextBaseFee(baseFee, maxBlockGas, maxBlockGas, height).Cmp(expected))
+
+	// An empty block lowers the base fee by 1/8
+	expected = new(big.Int).Sub(baseFee, new(big.Int).Div(baseFee, big.NewInt(8)))
+	assert.Equal(0, CalculateNextBaseFee(baseFee, 0, maxBlockGas, height).Cmp(expected))
+
+	// The base fee never goes below the minimum gas price
+	assert.Equal(0, CalculateNextBaseFee(minimumGasPrice, 0, maxBlockGas, height).Cmp(minimumGasPrice))
+	assert.Equal(0, CalculateNextBaseFee(nil, 0, maxBlockGas, height).Cmp(minimumGasPrice))
+
+	// A block slightly above the target raises the base fee by at least 1
+	assert.True(CalculateNextBaseFee(minimumGasPrice, 501, maxBlockGas, height).Cmp(minimumGasPrice) > 0)
+
+	// The input is not modified
+	assert.Equal(0, baseFee.Cmp(new(big.Int).Mul(minimumGasPrice, big.NewInt(10))))
+}
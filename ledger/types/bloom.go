@@ -0,0 +1,79 @@
+package types
+
+import (
+	"github.com/thetatoken/theta/common/hexutil"
+	"github.com/thetatoken/theta/crypto"
+)
+
+const (
+	// BloomByteLength represents the number of bytes used in a log bloom.
+	BloomByteLength = 256
+
+	// BloomBitLength represents the number of bits used in a log bloom.
+	BloomBitLength = 8 * BloomByteLength
+)
+
+// Bloom represents a 2048 bit bloom filter of the addresses and topics of event logs. It
+// is compatible with the Ethereum log bloom.
+type Bloom [BloomByteLength]byte
+
+// Add adds d to the filter.
+func (b *Bloom) Add(d []byte) {
+	h := crypto.Keccak256(d)
+	for i := 0; i < 6; i += 2 {
+		idx, mask := bloomBit(h, i)
+		b[idx] |= mask
+	}
+}
+
+// Test checks whether d might be in the filter.
+func (b Bloom) Test(d []byte) bool {
+	h := crypto.Keccak256(d)
+	for i := 0; i < 6; i += 2 {
+		idx, mask := bloomBit(h, i)
+		if b[idx]&mask == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Or merges the other filter into the filter.
+func (b *Bloom) Or(other Bloom) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+// Bytes returns the backing byte slice of the bloom.
+func (b Bloom) Bytes() []byte {
+	return b[:]
+}
+
+// MarshalText encodes b as a hex string with 0x prefix.
+func (b Bloom) MarshalText() ([]byte, error) {
+	return hexutil.Bytes(b[:]).MarshalText()
+}
+
+// UnmarshalText b as a hex string with 0x prefix.
+func (b *Bloom) UnmarshalText(input []byte) error {
+	return hexutil.UnmarshalFixedText("Bloom", input, b[:])
+}
+
+// bloomBit returns the byte index and the bit mask set by the pair of hash bytes at i.
+func bloomBit(h []byte, i int) (int, byte) {
+	bit := (uint(h[i])<<8 | uint(h[i+1])) & (BloomBitLength - 1)
+	return BloomByteLength - 1 - int(bit/8), byte(1) << (bit % 8)
+}
+
+// CreateBloom creates the bloom filter of the addresses and topics of the logs.
+func CreateBloom(logs []*Log) Bloom {
+	var b Bloom
+	for _, log := range logs {
+		b.Add(log.Address.Bytes())
+		for _, topic := range log.Topics {
+			b.Add(topic.Bytes())
+		}
+	}
+	return b
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
 )
 
 const (
@@ -132,3 +133,33 @@ func GetSendTxMinimumTransactionFeeTFuelWei(numAccountsAffected uint64, blockHei
 
 	return minSendTxFee
 }
+
+// GetBlockGas returns the gas a transaction counts against the block gas limit. A smart contract
+// transaction counts its gas limit since its gas usage is not known before execution. Special
+// transactions do not count since the proposer has to include them.
+func GetBlockGas(tx Tx, blockHeight uint64) uint64 {
+	switch tx := tx.(type) {
+	case *CoinbaseTx, *SlashTx:
+		return 0
+	case *SmartContractTx:
+		return tx.GasLimit
+	default:
+		if blockHeight < common.HeightJune2021FeeAdjustment {
+			return GasRegularTx
+		}
+		return GasRegularTxJune2021
+	}
+}
+
+// IsValidatorUpdateTx returns whether the transaction updates the validator set, in which case
+// the block including it is marked with the validator update
+func IsValidatorUpdateTx(tx Tx) bool {
+	switch tx := tx.(type) {
+	case *DepositStakeTx:
+		return tx.Purpose == core.StakeForValidator
+	case *WithdrawStakeTx:
+		return tx.Purpose == core.StakeForValidator
+	default:
+		return false
+	}
+}
@@ -0,0 +1,15 @@
+package types
+
+import (
+	"github.com/thetatoken/theta/common"
+)
+
+// InternalTransaction represents a value transfer made by a contract during the execution of
+// a smart contract transaction, e.g. a contract forwarding the deposit it receives to another address
+type InternalTransaction struct {
+	Type  string // CALL, CREATE, CREATE2, or SELFDESTRUCT
+	From  common.Address
+	To    common.Address
+	Coins Coins
+	Depth uint64 // Call depth of the contract making the transfer
+}
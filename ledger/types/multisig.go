@@ -0,0 +1,186 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// ** Multisig: M-of-N control over an account **
+//
+// The address of a multisig account is derived from its key set, i.e. the threshold and the signer
+// addresses, so the account does not need to be registered before receiving coins. To spend from the
+// account, the signers sign the sign bytes of the tx individually, and the partial signatures are
+// assembled into a MultisigSignature, which is carried by the TxInput as a regular signature.
+
+// MultisigSignaturePrefix distinguishes an encoded multisig signature from an ECDSA signature
+const MultisigSignaturePrefix byte = 0xff
+
+// MaxMultisigSigners is the max number of signers of a multisig account
+const MaxMultisigSigners = 16
+
+var (
+	ErrInvalidMultisigThreshold = errors.New("Multisig threshold must be between 1 and the number of signers")
+	ErrTooManyMultisigSigners   = errors.New("Too many multisig signers")
+	ErrDuplicateMultisigSigner  = errors.New("Duplicate multisig signer")
+	ErrNotMultisigSigner        = errors.New("Not a signer of the multisig account")
+	ErrNotMultisigSignature     = errors.New("Not a multisig signature")
+)
+
+// MultisigKeySet specifies the signers of a multisig account, and the number of signatures required
+type MultisigKeySet struct {
+	Threshold uint64
+	Signers   []common.Address // sorted in ascending order
+}
+
+// NewMultisigKeySet creates the key set of a M-of-N multisig account
+func NewMultisigKeySet(threshold uint64, signers []common.Address) (*MultisigKeySet, error) {
+	sorted := make([]common.Address, len(signers))
+	copy(sorted, signers)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+	keySet := &MultisigKeySet{
+		Threshold: threshold,
+		Signers:   sorted,
+	}
+	if err := keySet.Validate(); err != nil {
+		return nil, err
+	}
+	return keySet, nil
+}
+
+// Validate checks the threshold, and whether the signers are sorted without duplicates
+func (ks *MultisigKeySet) Validate() error {
+	if len(ks.Signers) > MaxMultisigSigners {
+		return ErrTooManyMultisigSigners
+	}
+	if ks.Threshold == 0 || ks.Threshold > uint64(len(ks.Signers)) {
+		return ErrInvalidMultisigThreshold
+	}
+	for i := 1; i < len(ks.Signers); i++ {
+		if bytes.Compare(ks.Signers[i-1][:], ks.Signers[i][:]) >= 0 {
+			return ErrDuplicateMultisigSigner
+		}
+	}
+	return nil
+}
+
+// Address returns the address of the multisig account
+func (ks *MultisigKeySet) Address() common.Address {
+	raw, _ := rlp.EncodeToBytes(ks)
+	return common.BytesToAddress(crypto.Keccak256([]byte("multisig"), raw)[12:])
+}
+
+func (ks *MultisigKeySet) signerIndex(signer common.Address) int {
+	for idx, addr := range ks.Signers {
+		if addr == signer {
+			return idx
+		}
+	}
+	return -1
+}
+
+// MultisigSignature contains the key set and the partial signatures of the signers
+type MultisigSignature struct {
+	KeySet     MultisigKeySet
+	Signatures []*crypto.Signature // aligned with KeySet.Signers, empty for the signers that have not signed
+}
+
+// NewMultisigSignature creates a multisig signature without any partial signature
+func NewMultisigSignature(keySet *MultisigKeySet) *MultisigSignature {
+	sigs := make([]*crypto.Signature, len(keySet.Signers))
+	for i := range sigs {
+		sigs[i], _ = crypto.SignatureFromBytes(nil)
+	}
+	return &MultisigSignature{
+		KeySet:     *keySet,
+		Signatures: sigs,
+	}
+}
+
+// AddSignature adds the partial signature of a signer
+func (ms *MultisigSignature) AddSignature(signer common.Address, sig *crypto.Signature) error {
+	idx := ms.KeySet.signerIndex(signer)
+	if idx < 0 {
+		return ErrNotMultisigSigner
+	}
+	ms.Signatures[idx] = sig
+	return nil
+}
+
+// Merge adds the partial signatures collected by another copy of the multisig signature
+func (ms *MultisigSignature) Merge(other *MultisigSignature) error {
+	if ms.KeySet.Address() != other.KeySet.Address() {
+		return ErrNotMultisigSigner
+	}
+	for idx, sig := range other.Signatures {
+		if sig != nil && !sig.IsEmpty() {
+			ms.Signatures[idx] = sig
+		}
+	}
+	return nil
+}
+
+// NumSigned returns the number of the partial signatures
+func (ms *MultisigSignature) NumSigned() int {
+	count := 0
+	for _, sig := range ms.Signatures {
+		if sig != nil && !sig.IsEmpty() {
+			count++
+		}
+	}
+	return count
+}
+
+// Verify checks whether the multisig signature is signed by at least threshold signers of the multisig
+// account with the given address. The verifySig function verifies the partial signatures.
+func (ms *MultisigSignature) Verify(msg common.Bytes, addr common.Address,
+	verifySig func(sig *crypto.Signature, msg common.Bytes, addr common.Address) bool) bool {
+	if ms.KeySet.Validate() != nil || len(ms.Signatures) != len(ms.KeySet.Signers) {
+		return false
+	}
+	if ms.KeySet.Address() != addr {
+		return false
+	}
+	count := uint64(0)
+	for idx, sig := range ms.Signatures {
+		if sig == nil || sig.IsEmpty() {
+			continue
+		}
+		if !verifySig(sig, msg, ms.KeySet.Signers[idx]) {
+			return false
+		}
+		count++
+	}
+	return count >= ms.KeySet.Threshold
+}
+
+// ToSignature encodes the multisig signature, so it can be carried by the TxInput
+func (ms *MultisigSignature) ToSignature() (*crypto.Signature, error) {
+	raw, err := rlp.EncodeToBytes(ms)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SignatureFromBytes(append([]byte{MultisigSignaturePrefix}, raw...))
+}
+
+// MultisigSignatureFromSignature decodes the multisig signature carried by a TxInput
+func MultisigSignatureFromSignature(sig *crypto.Signature) (*MultisigSignature, error) {
+	if sig == nil {
+		return nil, ErrNotMultisigSignature
+	}
+	data := sig.ToBytes()
+	if len(data) == 0 || data[0] != MultisigSignaturePrefix {
+		return nil, ErrNotMultisigSignature
+	}
+	ms := &MultisigSignature{}
+	if err := rlp.DecodeBytes(data[1:], ms); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
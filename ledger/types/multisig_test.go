@@ -0,0 +1,83 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+func TestMultisigKeySet(t *testing.T) {
+	assert := assert.New(t)
+
+	a1, a2, a3 := common.HexToAddress("0x01"), common.HexToAddress("0x02"), common.HexToAddress("0x03")
+
+	ks1, err := NewMultisigKeySet(2, []common.Address{a3, a1, a2})
+	assert.Nil(err)
+	assert.Equal([]common.Address{a1, a2, a3}, ks1.Signers)
+
+	// The address does not depend on the order of the signers
+	ks2, err := NewMultisigKeySet(2, []common.Address{a1, a2, a3})
+	assert.Nil(err)
+	assert.Equal(ks1.Address(), ks2.Address())
+
+	ks3, err := NewMultisigKeySet(3, []common.Address{a1, a2, a3})
+	assert.Nil(err)
+	assert.NotEqual(ks1.Address(), ks3.Address())
+
+	_, err = NewMultisigKeySet(0, []common.Address{a1, a2})
+	assert.Equal(ErrInvalidMultisigThreshold, err)
+	_, err = NewMultisigKeySet(3, []common.Address{a1, a2})
+	assert.Equal(ErrInvalidMultisigThreshold, err)
+	_, err = NewMultisigKeySet(1, []common.Address{a1, a1})
+	assert.Equal(ErrDuplicateMultisigSigner, err)
+}
+
+func TestMultisigSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	privKeys := []*crypto.PrivateKey{}
+	signers := []common.Address{}
+	for i := 0; i < 3; i++ {
+		privKey, pubKey, err := crypto.GenerateKeyPair()
+		assert.Nil(err)
+		privKeys = append(privKeys, privKey)
+		signers = append(signers, pubKey.Address())
+	}
+	keySet, err := NewMultisigKeySet(2, signers)
+	assert.Nil(err)
+	msg := common.Bytes("multisig tx")
+	verify := func(sig *crypto.Signature, msg common.Bytes, addr common.Address) bool {
+		return sig.Verify(msg, addr)
+	}
+
+	// Signatures collected in parallel are merged
+	ms1 := NewMultisigSignature(keySet)
+	ms2 := NewMultisigSignature(keySet)
+	sig0, _ := privKeys[0].Sign(msg)
+	sig2, _ := privKeys[2].Sign(msg)
+	assert.Nil(ms1.AddSignature(signers[0], sig0))
+	assert.False(ms1.Verify(msg, keySet.Address(), verify))
+	assert.Nil(ms2.AddSignature(signers[2], sig2))
+	assert.Nil(ms1.Merge(ms2))
+	assert.Equal(2, ms1.NumSigned())
+	assert.True(ms1.Verify(msg, keySet.Address(), verify))
+	assert.False(ms1.Verify(msg, signers[0], verify))
+	assert.False(ms1.Verify(common.Bytes("another tx"), keySet.Address(), verify))
+
+	outsider, _, _ := crypto.GenerateKeyPair()
+	sig, _ := outsider.Sign(msg)
+	assert.Equal(ErrNotMultisigSigner, ms1.AddSignature(outsider.PublicKey().Address(), sig))
+
+	// The multisig signature is carried as a regular signature
+	encoded, err := ms1.ToSignature()
+	assert.Nil(err)
+	decoded, err := MultisigSignatureFromSignature(encoded)
+	assert.Nil(err)
+	assert.True(decoded.Verify(msg, keySet.Address(), verify))
+	assert.False(encoded.Verify(msg, keySet.Address()))
+
+	_, err = MultisigSignatureFromSignature(sig0)
+	assert.NotNil(err)
+}
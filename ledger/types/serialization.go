@@ -37,8 +37,45 @@ const (
 	TxWithdrawStake
 	TxDepositStakeV2
 	TxStakeRewardDistribution
+	TxVesting
+	TxRotateValidatorKey
 )
 
+var txTypeNames = map[TxType]string{
+	TxCoinbase:                "coinbase",
+	TxSlash:                   "slash",
+	TxSend:                    "send",
+	TxReserveFund:             "reserve_fund",
+	TxReleaseFund:             "release_fund",
+	TxServicePayment:          "service_payment",
+	TxSplitRule:               "split_rule",
+	TxSmartContract:           "smart_contract",
+	TxDepositStake:            "deposit_stake",
+	TxWithdrawStake:           "withdraw_stake",
+	TxDepositStakeV2:          "deposit_stake_v2",
+	TxStakeRewardDistribution: "stake_reward_distribution",
+	TxVesting:                 "vesting",
+	TxRotateValidatorKey:      "rotate_validator_key",
+}
+
+// String returns the name of the tx type, e.g. "send"
+func (t TxType) String() string {
+	if name, ok := txTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("TxType(%d)", t)
+}
+
+// TxTypeFromString parses the name of a tx type
+func TxTypeFromString(name string) (TxType, bool) {
+	for txType, txTypeName := range txTypeNames {
+		if txTypeName == name {
+			return txType, true
+		}
+	}
+	return 0, false
+}
+
 func Fuzz(data []byte) int {
 	if len(data) == 0 {
 		return -1
@@ -114,13 +151,21 @@ func TxFromBytes(raw []byte) (Tx, error) {
 		data := &StakeRewardDistributionTx{}
 		err = s.Decode(data)
 		return data, err
+	} else if txType == TxVesting {
+		data := &VestingTx{}
+		err = s.Decode(data)
+		return data, err
+	} else if txType == TxRotateValidatorKey {
+		data := &RotateValidatorKeyTx{}
+		err = s.Decode(data)
+		return data, err
 	} else {
 		return nil, fmt.Errorf("Unknown TX type: %v", txType)
 	}
 }
 
-func TxToBytes(t Tx) ([]byte, error) {
-	var buf bytes.Buffer
+// GetTxType returns the type of the transaction
+func GetTxType(t Tx) (TxType, error) {
 	var txType TxType
 	switch t.(type) {
 	case *CoinbaseTx:
@@ -147,10 +192,23 @@ func TxToBytes(t Tx) ([]byte, error) {
 		txType = TxDepositStakeV2
 	case *StakeRewardDistributionTx:
 		txType = TxStakeRewardDistribution
+	case *VestingTx:
+		txType = TxVesting
+	case *RotateValidatorKeyTx:
+		txType = TxRotateValidatorKey
 	default:
-		return nil, errors.New("Unsupported message type")
+		return txType, errors.New("Unsupported message type")
+	}
+	return txType, nil
+}
+
+func TxToBytes(t Tx) ([]byte, error) {
+	var buf bytes.Buffer
+	txType, err := GetTxType(t)
+	if err != nil {
+		return nil, err
 	}
-	err := rlp.Encode(&buf, txType)
+	err = rlp.Encode(&buf, txType)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +35,8 @@ Transaction Types:
  - WithdrawStakeTx         Withdraw stake from a target address (e.g. a validator)
  - SmartContractTx         Execute smart contract
  - StakeRewardDistribution Defines how stake reward is distributed
+ - VestingTx               Send coins to address, locked by a vesting schedule
+ - RotateValidatorKeyTx    Rotate the key a validator signs the votes and proposals with
 */
 
 // Gas of regular transactions
@@ -73,6 +75,90 @@ func TxID(chainID string, tx Tx) common.Hash {
 	return crypto.Keccak256Hash(signBytes)
 }
 
+// FixedTxFee returns the fee of the transaction types with a fixed fee, and zero for the others
+func FixedTxFee(tx Tx) Coins {
+	var fee Coins
+	switch tx := tx.(type) {
+	case *SendTx:
+		fee = tx.Fee
+	case *ReserveFundTx:
+		fee = tx.Fee
+	case *ReleaseFundTx:
+		fee = tx.Fee
+	case *ServicePaymentTx:
+		fee = tx.Fee
+	case *SplitRuleTx:
+		fee = tx.Fee
+	case *DepositStakeTx:
+		fee = tx.Fee
+	case *WithdrawStakeTx:
+		fee = tx.Fee
+	case *DepositStakeTxV2:
+		fee = tx.Fee
+	case *StakeRewardDistributionTx:
+		fee = tx.Fee
+	case *VestingTx:
+		fee = tx.Fee
+	case *RotateValidatorKeyTx:
+		fee = tx.Fee
+	}
+	return fee.NoNil()
+}
+
+// TxAccountAddresses returns the addresses of the accounts the transaction might change the balance of
+func TxAccountAddresses(tx Tx) []common.Address {
+	var addresses []common.Address
+	switch tx := tx.(type) {
+	case *SendTx:
+		for _, input := range tx.Inputs {
+			addresses = append(addresses, input.Address)
+		}
+		for _, output := range tx.Outputs {
+			addresses = append(addresses, output.Address)
+		}
+	case *ReserveFundTx:
+		addresses = append(addresses, tx.Source.Address)
+	case *ReleaseFundTx:
+		addresses = append(addresses, tx.Source.Address)
+	case *ServicePaymentTx:
+		addresses = append(addresses, tx.Source.Address, tx.Target.Address)
+	case *SplitRuleTx:
+		addresses = append(addresses, tx.Initiator.Address)
+	case *SmartContractTx:
+		addresses = append(addresses, tx.From.Address)
+		if !tx.To.Address.IsEmpty() {
+			addresses = append(addresses, tx.To.Address)
+		}
+	case *CoinbaseTx:
+		for _, output := range tx.Outputs {
+			addresses = append(addresses, output.Address)
+		}
+	case *DepositStakeTx:
+		addresses = append(addresses, tx.Source.Address, tx.Holder.Address)
+	case *DepositStakeTxV2:
+		addresses = append(addresses, tx.Source.Address, tx.Holder.Address)
+	case *WithdrawStakeTx:
+		addresses = append(addresses, tx.Source.Address, tx.Holder.Address)
+	case *StakeRewardDistributionTx:
+		addresses = append(addresses, tx.Holder.Address, tx.Beneficiary.Address)
+	case *VestingTx:
+		addresses = append(addresses, tx.Source.Address, tx.Beneficiary.Address)
+	case *RotateValidatorKeyTx:
+		addresses = append(addresses, tx.Holder.Address)
+	}
+
+	// Remove the duplicates, e.g. a stake deposited to the source itself
+	seen := make(map[common.Address]bool)
+	unique := addresses[:0]
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+	return unique
+}
+
 //--------------------------------------------------------------------------------
 
 // Contract: This function is deterministic and completely reversible.
@@ -1018,6 +1104,218 @@ func (tx *StakeRewardDistributionTx) String() string {
 		tx.Holder.Address, tx.Beneficiary.Address, tx.SplitBasisPoint)
 }
 
+//-----------------------------------------------------------------------------
+
+//
+// VestingTx sends coins from the source account to the beneficiary account, where the coins are locked
+// by the vesting schedule specified by the heights, e.g. for the investor and team token distributions.
+//
+type VestingTx struct {
+	Fee         Coins    // Fee
+	Source      TxInput  // Source account, the coins of the input are locked in the beneficiary account
+	Beneficiary TxOutput // Beneficiary account
+	StartHeight uint64   // The block height when the coins start to unlock linearly
+	CliffHeight uint64   // The block height before which none of the coins unlocks
+	EndHeight   uint64   // The block height when all the coins are unlocked
+}
+
+type VestingTxJSON struct {
+	Fee         Coins             `json:"fee"`
+	Source      TxInput           `json:"source"`
+	Beneficiary TxOutput          `json:"beneficiary"`
+	StartHeight common.JSONUint64 `json:"start_height"`
+	CliffHeight common.JSONUint64 `json:"cliff_height"`
+	EndHeight   common.JSONUint64 `json:"end_height"`
+}
+
+func NewVestingTxJSON(a VestingTx) VestingTxJSON {
+	return VestingTxJSON{
+		Fee:         a.Fee,
+		Source:      a.Source,
+		Beneficiary: a.Beneficiary,
+		StartHeight: common.JSONUint64(a.StartHeight),
+		CliffHeight: common.JSONUint64(a.CliffHeight),
+		EndHeight:   common.JSONUint64(a.EndHeight),
+	}
+}
+
+func (a VestingTxJSON) VestingTx() VestingTx {
+	return VestingTx{
+		Fee:         a.Fee,
+		Source:      a.Source,
+		Beneficiary: a.Beneficiary,
+		StartHeight: uint64(a.StartHeight),
+		CliffHeight: uint64(a.CliffHeight),
+		EndHeight:   uint64(a.EndHeight),
+	}
+}
+
+func (a VestingTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewVestingTxJSON(a))
+}
+
+func (a *VestingTx) UnmarshalJSON(data []byte) error {
+	var b VestingTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.VestingTx()
+	return nil
+}
+
+func (_ *VestingTx) AssertIsTx() {}
+
+func (tx *VestingTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Source.Signature
+	tx.Source.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Source.Signature = sig
+	return signBytes
+}
+
+func (tx *VestingTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Source.Address == addr {
+		tx.Source.Signature = sig
+		return true
+	}
+	return false
+}
+
+// Schedule returns the vesting schedule created by the tx
+func (tx *VestingTx) Schedule() VestingSchedule {
+	return VestingSchedule{
+		Coins:       tx.Source.Coins.NoNil(),
+		StartHeight: tx.StartHeight,
+		CliffHeight: tx.CliffHeight,
+		EndHeight:   tx.EndHeight,
+	}
+}
+
+func (tx *VestingTx) String() string {
+	return fmt.Sprintf("VestingTx{fee: %v, source: %v, beneficiary: %v, start: %v, cliff: %v, end: %v}",
+		tx.Fee, tx.Source, tx.Beneficiary.Address, tx.StartHeight, tx.CliffHeight, tx.EndHeight)
+}
+
+//-----------------------------------------------------------------------------
+
+//
+// RotateValidatorKeyTx switches the key the validator stake holder signs the consensus votes and the
+// block proposals with, starting from the activation height. The stake stays with the holder, so the
+// validator does not need to withdraw and re-deposit the stake, e.g. when its key is compromised.
+//
+type RotateValidatorKeyTx struct {
+	Fee              Coins             // Fee
+	Holder           TxInput           // The validator stake holder
+	SigningAddress   common.Address    // Address of the new signing key
+	ActivationHeight uint64            // The block height from which the new key signs the votes and proposals
+	SigningSig       *crypto.Signature `rlp:"nil"` // Signature of the new key over SigningKeySignBytes, proving its possession
+	BlsPubkey        *bls.PublicKey    `rlp:"nil"` // BLS pubkey of the new key, optional
+	BlsPop           *bls.Signature    `rlp:"nil"` // BLS proof of possession, required if the BLS pubkey is set
+}
+
+type RotateValidatorKeyTxJSON struct {
+	Fee              Coins             `json:"fee"`
+	Holder           TxInput           `json:"holder"`
+	SigningAddress   common.Address    `json:"signing_address"`
+	ActivationHeight common.JSONUint64 `json:"activation_height"`
+	SigningSig       *crypto.Signature `json:"signing_sig"`
+	BlsPubkey        *bls.PublicKey    `json:"bls_pubkey"`
+	BlsPop           *bls.Signature    `json:"bls_pop"`
+}
+
+func NewRotateValidatorKeyTxJSON(a RotateValidatorKeyTx) RotateValidatorKeyTxJSON {
+	return RotateValidatorKeyTxJSON{
+		Fee:              a.Fee,
+		Holder:           a.Holder,
+		SigningAddress:   a.SigningAddress,
+		ActivationHeight: common.JSONUint64(a.ActivationHeight),
+		SigningSig:       a.SigningSig,
+		BlsPubkey:        a.BlsPubkey,
+		BlsPop:           a.BlsPop,
+	}
+}
+
+func (a RotateValidatorKeyTxJSON) RotateValidatorKeyTx() RotateValidatorKeyTx {
+	return RotateValidatorKeyTx{
+		Fee:              a.Fee,
+		Holder:           a.Holder,
+		SigningAddress:   a.SigningAddress,
+		ActivationHeight: uint64(a.ActivationHeight),
+		SigningSig:       a.SigningSig,
+		BlsPubkey:        a.BlsPubkey,
+		BlsPop:           a.BlsPop,
+	}
+}
+
+func (a RotateValidatorKeyTx) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewRotateValidatorKeyTxJSON(a))
+}
+
+func (a *RotateValidatorKeyTx) UnmarshalJSON(data []byte) error {
+	var b RotateValidatorKeyTxJSON
+	if err := json.Unmarshal(data, &b); err != nil {
+		return err
+	}
+	*a = b.RotateValidatorKeyTx()
+	return nil
+}
+
+func (_ *RotateValidatorKeyTx) AssertIsTx() {}
+
+func (tx *RotateValidatorKeyTx) SignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+	sig := tx.Holder.Signature
+	tx.Holder.Signature = nil
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	tx.Holder.Signature = sig
+	return signBytes
+}
+
+// SigningKeySignBytes returns the bytes signed by the new signing key. The fee and the holder sequence
+// are not covered, so the new key can be prepared offline before the holder signs the tx.
+func (tx *RotateValidatorKeyTx) SigningKeySignBytes(chainID string) []byte {
+	signBytes := encodeToBytes(chainID)
+
+	holder := tx.Holder
+	fee := tx.Fee
+	signingSig := tx.SigningSig
+
+	tx.Holder = TxInput{Address: holder.Address}
+	tx.Fee = NewCoins(0, 0)
+	tx.SigningSig = nil
+
+	txBytes, _ := TxToBytes(tx)
+	signBytes = append(signBytes, txBytes...)
+
+	tx.Holder = holder
+	tx.Fee = fee
+	tx.SigningSig = signingSig
+
+	signBytes = addPrefixForSignBytes(signBytes)
+
+	return signBytes
+}
+
+func (tx *RotateValidatorKeyTx) SetSignature(addr common.Address, sig *crypto.Signature) bool {
+	if tx.Holder.Address == addr {
+		tx.Holder.Signature = sig
+		return true
+	}
+	return false
+}
+
+func (tx *RotateValidatorKeyTx) String() string {
+	return fmt.Sprintf("RotateValidatorKeyTx{fee: %v, holder: %v, signing_address: %v, activation: %v, bls_pubkey: %v}",
+		tx.Fee, tx.Holder, tx.SigningAddress.Hex(), tx.ActivationHeight, tx.BlsPubkey)
+}
+
 // --------------- Utils --------------- //
 
 type EthereumTxWrapper struct {
@@ -0,0 +1,25 @@
+// +build gofuzz
+
+package types
+
+import (
+	"fmt"
+)
+
+// FuzzTx fuzzes the decoding of the transactions received from the peers and the RPC clients.
+// A decoded transaction must survive a round trip through the encoding.
+func FuzzTx(data []byte) int {
+	tx, err := TxFromBytes(data)
+	if err != nil {
+		return 0
+	}
+	tx.SignBytes("testchain")
+	raw, err := TxToBytes(tx)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to re-encode a decoded tx: %v", err))
+	}
+	if _, err := TxFromBytes(raw); err != nil {
+		panic(fmt.Sprintf("Failed to decode a re-encoded tx: %v", err))
+	}
+	return 1
+}
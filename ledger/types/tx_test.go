@@ -787,3 +787,13 @@ func TestSmartContractTxJSON(t *testing.T) {
 	assert.Equal(uint64(math.MaxUint64), d.GasLimit)
 	assert.Equal(0, gasPrice.Cmp(d.GasPrice))
 }
+
+func TestGetBlockGas(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(uint64(0), GetBlockGas(&CoinbaseTx{}, 1))
+	assert.Equal(uint64(0), GetBlockGas(&SlashTx{}, 1))
+	assert.Equal(uint64(123456), GetBlockGas(&SmartContractTx{GasLimit: 123456}, 1))
+	assert.Equal(GasRegularTx, GetBlockGas(&SendTx{}, 1))
+	assert.Equal(GasRegularTxJune2021, GetBlockGas(&SendTx{}, common.HeightJune2021FeeAdjustment))
+}
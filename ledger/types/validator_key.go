@@ -0,0 +1,54 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto/bls"
+)
+
+// ** Validator key rotation **
+//
+// A validator stake holder signs the consensus votes and the block proposals with the key of the
+// holder address by default. With a RotateValidatorKeyTx, the holder can switch to a different
+// signing key without withdrawing the stake. The new key takes effect at the activation height,
+// which has to be far enough in the future so that the validator set of the activation height,
+// which is derived from a finalized ancestor block, already includes the rotation.
+
+// ValidatorKeyRotationMinDelay is the minimal number of blocks between the rotation tx and the activation height
+const ValidatorKeyRotationMinDelay uint64 = 100
+
+// ValidatorSigningKey records the signing key rotation of a validator stake holder
+type ValidatorSigningKey struct {
+	Address          common.Address // Address of the signing key after the activation height
+	ActivationHeight uint64         // The block height from which the votes and proposals are signed by the new key
+	PrevAddress      common.Address // Address of the signing key before the activation height
+	BlsPubkey        *bls.PublicKey `rlp:"nil"` // BLS pubkey of the new key, optional
+	PrevBlsPubkey    *bls.PublicKey `rlp:"nil"` // BLS pubkey before the activation height, optional
+}
+
+func (k ValidatorSigningKey) String() string {
+	return fmt.Sprintf("ValidatorSigningKey{address: %v, activation: %v, prev_address: %v}",
+		k.Address.Hex(), k.ActivationHeight, k.PrevAddress.Hex())
+}
+
+// SigningAddress returns the address of the signing key at the given block height
+func (k ValidatorSigningKey) SigningAddress(height uint64) common.Address {
+	if height >= k.ActivationHeight {
+		return k.Address
+	}
+	return k.PrevAddress
+}
+
+// BLSPubkey returns the BLS pubkey of the signing key at the given block height, nil if not registered
+func (k ValidatorSigningKey) BLSPubkey(height uint64) *bls.PublicKey {
+	if height >= k.ActivationHeight {
+		return k.BlsPubkey
+	}
+	return k.PrevBlsPubkey
+}
+
+// IsPending returns whether the new key is not active yet at the given block height
+func (k ValidatorSigningKey) IsPending(height uint64) bool {
+	return height < k.ActivationHeight
+}
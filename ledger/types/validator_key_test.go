@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestValidatorSigningKey(t *testing.T) {
+	assert := assert.New(t)
+
+	prev := common.HexToAddress("0x111")
+	next := common.HexToAddress("0x222")
+	key := ValidatorSigningKey{
+		Address:          next,
+		ActivationHeight: 1000,
+		PrevAddress:      prev,
+	}
+
+	assert.Equal(prev, key.SigningAddress(999))
+	assert.Equal(next, key.SigningAddress(1000))
+	assert.Equal(next, key.SigningAddress(1001))
+	assert.True(key.IsPending(999))
+	assert.False(key.IsPending(1000))
+	assert.Nil(key.BLSPubkey(1000))
+
+	raw, err := ToBytes(&key)
+	assert.Nil(err)
+	decoded := ValidatorSigningKey{}
+	assert.Nil(FromBytes(raw, &decoded))
+	assert.Equal(next, decoded.Address)
+	assert.Equal(prev, decoded.PrevAddress)
+	assert.Equal(uint64(1000), decoded.ActivationHeight)
+	assert.True(decoded.BlsPubkey.IsEmpty())
+}
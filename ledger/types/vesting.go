@@ -0,0 +1,138 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+)
+
+// ** Vesting: time-locked account balances **
+//
+// A vesting schedule locks part of the balance of an account. Nothing unlocks before the cliff
+// height, and afterwards the coins unlock linearly from the start height to the end height. The
+// locked coins cannot be sent, but they can be staked. Since the lock applies to the balance, the
+// stake withdrawn to the account remains subject to the schedule. The lock is enforced by the
+// transactions signed by the account, so the beneficiary cannot be a smart contract, and no smart
+// contract can be deployed to an account with locked coins.
+
+const (
+	// MaxVestingSchedulesPerAccount specifies the max number of active vesting schedules of an account,
+	// since all of them are read whenever the account spends its balance
+	MaxVestingSchedulesPerAccount = 16
+
+	// MinVestingWei specifies the minimal amount of either ThetaWei or TFuelWei locked by a vesting schedule
+	MinVestingWei uint64 = 1e18
+)
+
+var (
+	ErrInvalidVestingHeights = errors.New("Vesting heights must satisfy start <= cliff <= end, and start < end")
+	ErrInvalidVestingCoins   = errors.New("Vesting coins must be positive")
+)
+
+// VestingSchedule specifies the coins locked in an account, and how they unlock
+type VestingSchedule struct {
+	Coins       Coins  // Coins locked by the schedule
+	StartHeight uint64 // The block height when the coins start to unlock linearly
+	CliffHeight uint64 // The block height before which none of the coins unlocks
+	EndHeight   uint64 // The block height when all the coins are unlocked
+}
+
+type VestingScheduleJSON struct {
+	Coins       Coins             `json:"coins"`
+	StartHeight common.JSONUint64 `json:"start_height"`
+	CliffHeight common.JSONUint64 `json:"cliff_height"`
+	EndHeight   common.JSONUint64 `json:"end_height"`
+}
+
+func NewVestingScheduleJSON(vs VestingSchedule) VestingScheduleJSON {
+	return VestingScheduleJSON{
+		Coins:       vs.Coins,
+		StartHeight: common.JSONUint64(vs.StartHeight),
+		CliffHeight: common.JSONUint64(vs.CliffHeight),
+		EndHeight:   common.JSONUint64(vs.EndHeight),
+	}
+}
+
+func (vs VestingScheduleJSON) VestingSchedule() VestingSchedule {
+	return VestingSchedule{
+		Coins:       vs.Coins,
+		StartHeight: uint64(vs.StartHeight),
+		CliffHeight: uint64(vs.CliffHeight),
+		EndHeight:   uint64(vs.EndHeight),
+	}
+}
+
+func (vs VestingSchedule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewVestingScheduleJSON(vs))
+}
+
+func (vs *VestingSchedule) UnmarshalJSON(data []byte) error {
+	var a VestingScheduleJSON
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*vs = a.VestingSchedule()
+	return nil
+}
+
+func (vs VestingSchedule) String() string {
+	return fmt.Sprintf("VestingSchedule{coins: %v, start: %v, cliff: %v, end: %v}",
+		vs.Coins, vs.StartHeight, vs.CliffHeight, vs.EndHeight)
+}
+
+// Validate checks the coins and the heights of the schedule
+func (vs VestingSchedule) Validate() error {
+	if !vs.Coins.IsValid() || !vs.Coins.IsPositive() {
+		return ErrInvalidVestingCoins
+	}
+	if vs.StartHeight > vs.CliffHeight || vs.CliffHeight > vs.EndHeight || vs.StartHeight >= vs.EndHeight {
+		return ErrInvalidVestingHeights
+	}
+	return nil
+}
+
+// LockedCoins returns the coins still locked at the given block height
+func (vs VestingSchedule) LockedCoins(height uint64) Coins {
+	coins := vs.Coins.NoNil()
+	if height < vs.CliffHeight || height < vs.StartHeight {
+		return coins
+	}
+	if height >= vs.EndHeight {
+		return NewCoins(0, 0)
+	}
+
+	remaining := new(big.Int).SetUint64(vs.EndHeight - height)
+	duration := new(big.Int).SetUint64(vs.EndHeight - vs.StartHeight)
+	theta := new(big.Int).Mul(coins.ThetaWei, remaining)
+	theta.Div(theta, duration)
+	tfuel := new(big.Int).Mul(coins.TFuelWei, remaining)
+	tfuel.Div(tfuel, duration)
+	return Coins{
+		ThetaWei: theta,
+		TFuelWei: tfuel,
+	}
+}
+
+// TotalLockedCoins returns the coins locked by all the schedules at the given block height
+func TotalLockedCoins(schedules []VestingSchedule, height uint64) Coins {
+	locked := NewCoins(0, 0)
+	for _, vs := range schedules {
+		locked = locked.Plus(vs.LockedCoins(height))
+	}
+	return locked
+}
+
+// SpendableCoins returns the part of the balance that is not locked
+func SpendableCoins(balance Coins, locked Coins) Coins {
+	spendable := balance.NoNil().Minus(locked)
+	if spendable.ThetaWei.Sign() < 0 {
+		spendable.ThetaWei = big.NewInt(0)
+	}
+	if spendable.TFuelWei.Sign() < 0 {
+		spendable.TFuelWei = big.NewInt(0)
+	}
+	return spendable
+}
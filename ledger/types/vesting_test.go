@@ -0,0 +1,56 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVestingScheduleLockedCoins(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := VestingSchedule{
+		Coins:       NewCoins(1000, 100),
+		StartHeight: 100,
+		CliffHeight: 200,
+		EndHeight:   1100,
+	}
+	assert.Nil(vs.Validate())
+
+	// Nothing unlocks before the cliff
+	assert.True(vs.LockedCoins(0).IsEqual(NewCoins(1000, 100)))
+	assert.True(vs.LockedCoins(199).IsEqual(NewCoins(1000, 100)))
+
+	// Linear unlock from the start height after the cliff
+	assert.True(vs.LockedCoins(200).IsEqual(NewCoins(900, 90)))
+	assert.True(vs.LockedCoins(600).IsEqual(NewCoins(500, 50)))
+	assert.True(vs.LockedCoins(1099).IsEqual(NewCoins(1, 0)))
+
+	// Everything unlocks at the end height
+	assert.True(vs.LockedCoins(1100).IsZero())
+	assert.True(vs.LockedCoins(5000).IsZero())
+
+	schedules := []VestingSchedule{vs, {
+		Coins:       NewCoins(0, 50),
+		StartHeight: 0,
+		CliffHeight: 0,
+		EndHeight:   1000,
+	}}
+	locked := TotalLockedCoins(schedules, 600)
+	assert.True(locked.IsEqual(NewCoins(500, 70)))
+
+	spendable := SpendableCoins(NewCoins(800, 60), locked)
+	assert.Equal(big.NewInt(300), spendable.ThetaWei)
+	assert.Equal(big.NewInt(0), spendable.TFuelWei)
+}
+
+func TestVestingScheduleValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ErrInvalidVestingCoins, VestingSchedule{Coins: NewCoins(0, 0), StartHeight: 1, CliffHeight: 1, EndHeight: 2}.Validate())
+	assert.Equal(ErrInvalidVestingHeights, VestingSchedule{Coins: NewCoins(1, 0), StartHeight: 10, CliffHeight: 5, EndHeight: 20}.Validate())
+	assert.Equal(ErrInvalidVestingHeights, VestingSchedule{Coins: NewCoins(1, 0), StartHeight: 10, CliffHeight: 30, EndHeight: 20}.Validate())
+	assert.Equal(ErrInvalidVestingHeights, VestingSchedule{Coins: NewCoins(1, 0), StartHeight: 10, CliffHeight: 10, EndHeight: 10}.Validate())
+	assert.Nil(VestingSchedule{Coins: NewCoins(1, 0), StartHeight: 10, CliffHeight: 20, EndHeight: 20}.Validate())
+}
@@ -77,6 +77,39 @@ var PrecompiledContractsThetaSupport = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{203}): &transferTheta{},
 }
 
+var PrecompiledContractsStakingQuery = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256Add{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMul{},
+	common.BytesToAddress([]byte{8}): &bn256Pairing{},
+
+	common.BytesToAddress([]byte{201}): &thetaBalance{},
+	common.BytesToAddress([]byte{202}): &thetaStake{},
+	common.BytesToAddress([]byte{203}): &transferTheta{},
+	common.BytesToAddress([]byte{204}): &stakingQuery{},
+}
+
+var PrecompiledContractsThetaTransferV2 = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1}): &ecrecover{},
+	common.BytesToAddress([]byte{2}): &sha256hash{},
+	common.BytesToAddress([]byte{3}): &ripemd160hash{},
+	common.BytesToAddress([]byte{4}): &dataCopy{},
+	common.BytesToAddress([]byte{5}): &bigModExp{},
+	common.BytesToAddress([]byte{6}): &bn256Add{},
+	common.BytesToAddress([]byte{7}): &bn256ScalarMul{},
+	common.BytesToAddress([]byte{8}): &bn256Pairing{},
+
+	common.BytesToAddress([]byte{201}): &thetaBalance{},
+	common.BytesToAddress([]byte{202}): &thetaStake{},
+	common.BytesToAddress([]byte{203}): &transferTheta{},
+	common.BytesToAddress([]byte{204}): &stakingQuery{},
+	common.BytesToAddress([]byte{205}): &transferTokens{},
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 func RunPrecompiledContract(evm *EVM, p PrecompiledContract, input []byte, contract *Contract) (ret []byte, err error) {
 	blockHeight := evm.StateDB.GetBlockHeight()
@@ -442,6 +475,105 @@ func (c *transferTheta) Run(evm *EVM, input []byte, callerAddr common.Address) (
 
 	// send Theta from the contract to the specified recipient
 	TransferTheta(evm.StateDB, callerAddr, recipient, thetaWeiAmount)
+	evm.recordInternalTx(CALL, callerAddr, recipient, nil, thetaWeiAmount)
 
 	return common.Bytes{}, nil
 }
+
+// transferTokens transfers both the Theta and the TFuel tokens
+type transferTokens struct {
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *transferTokens) RequiredGas(input []byte, blockHeight uint64) uint64 {
+	return params.ThetaTransferGas
+}
+
+func (c *transferTokens) Run(evm *EVM, input []byte, callerAddr common.Address) ([]byte, error) {
+	recipient := common.BytesToAddress(getData(input, 0, 20))
+	thetaWeiAmount := new(big.Int).SetBytes(getData(input, 20, 32))
+	tfuelWeiAmount := new(big.Int).SetBytes(getData(input, 52, 32))
+	if !CanTransferTheta(evm.StateDB, callerAddr, thetaWeiAmount) {
+		return common.Bytes{}, ErrInsufficientThetaBlance
+	}
+	if !CanTransfer(evm.StateDB, callerAddr, tfuelWeiAmount) {
+		return common.Bytes{}, ErrInsufficientBalance
+	}
+
+	// send Theta and TFuel from the contract to the specified recipient
+	TransferTheta(evm.StateDB, callerAddr, recipient, thetaWeiAmount)
+	Transfer(evm.StateDB, callerAddr, recipient, tfuelWeiAmount)
+	evm.recordInternalTx(CALL, callerAddr, recipient, tfuelWeiAmount, thetaWeiAmount)
+
+	return common.Bytes{}, nil
+}
+
+// isTokenTransfer returns whether the precompiled contract transfers the tokens of the caller
+func isTokenTransfer(p PrecompiledContract) bool {
+	switch p.(type) {
+	case *transferTheta, *transferTokens:
+		return true
+	}
+	return false
+}
+
+// checkTokenTransferContext ensures the tokens can only be transferred by the calling contract itself,
+// i.e. not in a static call, nor on behalf of another address through delegatecall/callcode
+func checkTokenTransferContext(evm *EVM, contract *Contract, readOnly bool) error {
+	if in, ok := evm.interpreter.(*EVMInterpreter); readOnly || (ok && in.readOnly) {
+		return errWriteProtection
+	}
+	if *contract.CodeAddr != contract.Address() {
+		return errDelegatedTokenTransfer
+	}
+	return nil
+}
+
+// Queries supported by the stakingQuery precompiled contract
+const (
+	StakingQueryValidatorStake     uint64 = 1 // (source, holder) => (stake, holderTotalStake)
+	StakingQueryGuardianStake      uint64 = 2 // (source, holder) => (stake, holderTotalStake)
+	StakingQueryRewardDistribution uint64 = 3 // (holder) => (beneficiary, splitBasisPoint)
+)
+
+// errInvalidStakingQuery is returned if the query ID of the staking query is not supported.
+var errInvalidStakingQuery = errors.New("invalid staking query")
+
+// errDelegatedTokenTransfer is returned if the token transfer precompiled contracts are invoked through delegatecall/callcode.
+var errDelegatedTokenTransfer = errors.New("token transfer cannot be delegated")
+
+// stakingQuery retrieves the validator/guardian stakes and the stake reward distribution. The input is
+// the ABI encoded query ID followed by the addresses, and the output is ABI encoded 32-byte words
+type stakingQuery struct {
+}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+func (c *stakingQuery) RequiredGas(input []byte, blockHeight uint64) uint64 {
+	return params.ThetaStakeQueryGas
+}
+
+func (c *stakingQuery) Run(evm *EVM, input []byte, callerAddr common.Address) ([]byte, error) {
+	query := new(big.Int).SetBytes(getData(input, 0, 32))
+	if !query.IsUint64() {
+		return nil, errInvalidStakingQuery
+	}
+	firstAddr := common.BytesToAddress(getData(input, 32, 32))
+	secondAddr := common.BytesToAddress(getData(input, 64, 32))
+
+	var ret []byte
+	switch query.Uint64() {
+	case StakingQueryValidatorStake:
+		stake, holderTotalStake := evm.StateDB.GetValidatorStake(firstAddr, secondAddr)
+		ret = append(common.LeftPadBytes(stake.Bytes(), 32), common.LeftPadBytes(holderTotalStake.Bytes(), 32)...)
+	case StakingQueryGuardianStake:
+		stake, holderTotalStake := evm.StateDB.GetGuardianStake(firstAddr, secondAddr)
+		ret = append(common.LeftPadBytes(stake.Bytes(), 32), common.LeftPadBytes(holderTotalStake.Bytes(), 32)...)
+	case StakingQueryRewardDistribution:
+		beneficiary, splitBasisPoint := evm.StateDB.GetRewardDistribution(firstAddr)
+		splitBasisPointBytes := new(big.Int).SetUint64(uint64(splitBasisPoint)).Bytes()
+		ret = append(common.LeftPadBytes(beneficiary.Bytes(), 32), common.LeftPadBytes(splitBasisPointBytes, 32)...)
+	default:
+		return nil, errInvalidStakingQuery
+	}
+	return ret, nil
+}
@@ -0,0 +1,60 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/vm/params"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+func TestPrecompiledStakingQuery(t *testing.T) {
+	assert := assert.New(t)
+
+	source1 := common.HexToAddress("0x111")
+	source2 := common.HexToAddress("0x222")
+	validator := common.HexToAddress("0xf01")
+	beneficiary := common.HexToAddress("0xb01")
+	stake1 := new(big.Int).Mul(big.NewInt(1000), core.MinValidatorStakeDeposit)
+	stake2 := new(big.Int).Mul(big.NewInt(3000), core.MinValidatorStakeDeposit)
+
+	vcp := &core.ValidatorCandidatePool{}
+	assert.Nil(vcp.DepositStake(source1, validator, stake1))
+	assert.Nil(vcp.DepositStake(source2, validator, stake2))
+
+	sv := state.NewStoreView(uint64(1), common.Hash{}, backend.NewMemDatabase())
+	sv.UpdateValidatorCandidatePool(vcp)
+	rd, err := core.NewRewardDistribution(validator, beneficiary, 500)
+	assert.Nil(err)
+	state.NewStakeRewardDistributionRuleSet(sv).Upsert(rd)
+
+	evm := NewEVM(Context{}, sv, params.TestChainConfig, Config{})
+	query := &stakingQuery{}
+	word := func(b []byte) []byte { return common.LeftPadBytes(b, 32) }
+	input := func(queryID uint64, addrs ...common.Address) []byte {
+		ret := word(new(big.Int).SetUint64(queryID).Bytes())
+		for _, addr := range addrs {
+			ret = append(ret, word(addr.Bytes())...)
+		}
+		return ret
+	}
+
+	ret, err := query.Run(evm, input(StakingQueryValidatorStake, source1, validator), common.Address{})
+	assert.Nil(err)
+	assert.Equal(append(word(stake1.Bytes()), word(new(big.Int).Add(stake1, stake2).Bytes())...), ret)
+
+	ret, err = query.Run(evm, input(StakingQueryGuardianStake, source1, validator), common.Address{})
+	assert.Nil(err)
+	assert.Equal(make([]byte, 64), ret)
+
+	ret, err = query.Run(evm, input(StakingQueryRewardDistribution, validator), common.Address{})
+	assert.Nil(err)
+	assert.Equal(append(word(beneficiary.Bytes()), word(big.NewInt(500).Bytes())...), ret)
+
+	_, err = query.Run(evm, input(99, validator), common.Address{})
+	assert.Equal(errInvalidStakingQuery, err)
+}
@@ -0,0 +1,66 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/vm/params"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+func TestPrecompiledTransferTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	contractAddr := common.HexToAddress("0x111")
+	recipient := common.HexToAddress("0x222")
+	sv := state.NewStoreView(uint64(1), common.Hash{}, backend.NewMemDatabase())
+	sv.AddThetaBalance(contractAddr, big.NewInt(1000))
+	sv.AddBalance(contractAddr, big.NewInt(2000))
+
+	evm := NewEVM(Context{}, sv, params.TestChainConfig, Config{})
+	evm.depth = 1
+	transfer := &transferTokens{}
+	input := func(thetaWei, tfuelWei int64) []byte {
+		ret := append(recipient.Bytes(), common.LeftPadBytes(big.NewInt(thetaWei).Bytes(), 32)...)
+		return append(ret, common.LeftPadBytes(big.NewInt(tfuelWei).Bytes(), 32)...)
+	}
+
+	_, err := transfer.Run(evm, input(300, 500), contractAddr)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(700), sv.GetThetaBalance(contractAddr))
+	assert.Equal(big.NewInt(1500), sv.GetBalance(contractAddr))
+	assert.Equal(big.NewInt(300), sv.GetThetaBalance(recipient))
+	assert.Equal(big.NewInt(500), sv.GetBalance(recipient))
+
+	_, err = transfer.Run(evm, input(800, 0), contractAddr)
+	assert.Equal(ErrInsufficientThetaBlance, err)
+	_, err = transfer.Run(evm, input(0, 1600), contractAddr)
+	assert.Equal(ErrInsufficientBalance, err)
+
+	itxs := evm.InternalTxs()
+	assert.Equal(1, len(itxs))
+	assert.Equal(contractAddr, itxs[0].From)
+	assert.Equal(recipient, itxs[0].To)
+	assert.Equal(big.NewInt(300), itxs[0].Coins.ThetaWei)
+	assert.Equal(big.NewInt(500), itxs[0].Coins.TFuelWei)
+}
+
+func TestTokenTransferContext(t *testing.T) {
+	assert := assert.New(t)
+
+	precompileAddr := common.BytesToAddress([]byte{205})
+	evm := NewEVM(Context{}, nil, params.TestChainConfig, Config{})
+
+	contract := NewContract(&dummyContractRef{}, AccountRef(precompileAddr), new(big.Int), 0)
+	contract.CodeAddr = &precompileAddr
+	assert.Nil(checkTokenTransferContext(evm, contract, false))
+	assert.Equal(errWriteProtection, checkTokenTransferContext(evm, contract, true))
+
+	// Delegated to the precompiled contract, the token transfer would be made on behalf of the caller of the contract
+	delegated := NewContract(&dummyContractRef{}, AccountRef(common.HexToAddress("0x111")), new(big.Int), 0)
+	delegated.CodeAddr = &precompileAddr
+	assert.Equal(errDelegatedTokenTransfer, checkTokenTransferContext(evm, delegated, false))
+}
@@ -26,7 +26,9 @@ var (
 	ErrTraceLimitReached        = errors.New("the number of logs reached the specified limit")
 	ErrInsufficientBalance      = errors.New("insufficient balance for transfer")
 	ErrContractAddressCollision = errors.New("contract address collision")
+	ErrContractAddressLocked    = errors.New("contract address has locked coins")
 	ErrNoCompatibleInterpreter  = errors.New("no compatible interpreter")
 	ErrInvalidGasLimit          = errors.New("invalid gas limit")
 	ErrInsufficientThetaBlance  = errors.New("insufficient Theta balance for transfer")
+	ErrSStoreSentry             = errors.New("not enough gas for reentrancy sentry")
 )
@@ -13,6 +13,17 @@ import (
 
 // Execute executes the given smart contract
 func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *state.StoreView) (evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error) {
+	return execute(parentBlock, tx, storeView, Config{})
+}
+
+// ExecuteWithTracer executes the given smart contract with the tracer capturing each step of the execution
+func ExecuteWithTracer(parentBlock *core.Block, tx *types.SmartContractTx, storeView *state.StoreView, tracer Tracer) (evmRet common.Bytes,
+	contractAddr common.Address, gasUsed uint64, evmErr error) {
+	return execute(parentBlock, tx, storeView, Config{Debug: true, Tracer: tracer})
+}
+
+func execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *state.StoreView, config Config) (evmRet common.Bytes,
 	contractAddr common.Address, gasUsed uint64, evmErr error) {
 	context := Context{
 		CanTransfer: CanTransfer,
@@ -20,15 +31,21 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 		Origin:      tx.From.Address,
 		GasPrice:    tx.GasPrice,
 		GasLimit:    tx.GasLimit,
+		ChainID:     parentBlock.ChainID,
 		BlockNumber: new(big.Int).SetUint64(parentBlock.Height + 1),
 		Time:        parentBlock.Timestamp,
 		Difficulty:  new(big.Int).SetInt64(0),
+		GetHash: func(n uint64) common.Hash {
+			if n == parentBlock.Height {
+				return parentBlock.Hash()
+			}
+			return storeView.GetRecentBlockHash(n)
+		},
 	}
 	chainIDBigInt := types.MapChainID(parentBlock.ChainID, context.BlockNumber.Uint64())
 	chainConfig := &params.ChainConfig{
 		ChainID: chainIDBigInt,
 	}
-	config := Config{}
 	evm := NewEVM(context, storeView, chainConfig, config)
 
 	value := tx.From.Coins.TFuelWei
@@ -55,7 +72,7 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 		return common.Bytes{}, common.Address{}, 0, ErrInvalidGasLimit
 	}
 
-	intrinsicGas, err := calculateIntrinsicGas(tx.Data, createContract)
+	intrinsicGas, err := calculateIntrinsicGas(tx.Data, createContract, evm.forkLevel)
 	if err != nil {
 		return common.Bytes{}, common.Address{}, 0, err
 	}
@@ -63,6 +80,10 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 		return common.Bytes{}, common.Address{}, 0, ErrOutOfGas
 	}
 
+	if evm.forkLevel.appliesRefunds() {
+		storeView.ResetRefund()
+	}
+
 	var leftOverGas uint64
 	remainingGas := gasLimit - intrinsicGas
 	if createContract {
@@ -73,17 +94,24 @@ func Execute(parentBlock *core.Block, tx *types.SmartContractTx, storeView *stat
 		evmRet, leftOverGas, evmErr = evm.Call(AccountRef(fromAddr), contractAddr, input, remainingGas, value, thetaValue)
 	}
 
+	for _, itx := range evm.InternalTxs() {
+		storeView.AddInternalTx(itx)
+	}
+
 	if leftOverGas > gasLimit { // should not happen
 		gasUsed = uint64(0)
 	} else {
 		gasUsed = gasLimit - leftOverGas
 	}
+	if evm.forkLevel.appliesRefunds() && evmErr == nil {
+		gasUsed = applyRefund(gasUsed, storeView.GetRefund())
+	}
 
 	return evmRet, contractAddr, gasUsed, evmErr
 }
 
 // calculateIntrinsicGas computes the 'intrinsic gas' for a message with the given data.
-func calculateIntrinsicGas(data []byte, createContract bool) (uint64, error) {
+func calculateIntrinsicGas(data []byte, createContract bool, forkLevel ForkLevel) (uint64, error) {
 	// Set the starting gas for the raw transaction
 	var gas uint64
 	if createContract {
@@ -101,10 +129,11 @@ func calculateIntrinsicGas(data []byte, createContract bool) (uint64, error) {
 			}
 		}
 		// Make sure we don't exceed uint64 for all data combinations
-		if (math.MaxUint64-gas)/params.TxDataNonZeroGas < nz {
+		nonZeroGas := forkLevel.txDataNonZeroGas()
+		if (math.MaxUint64-gas)/nonZeroGas < nz {
 			return 0, ErrOutOfGas
 		}
-		gas += nz * params.TxDataNonZeroGas
+		gas += nz * nonZeroGas
 
 		z := uint64(len(data)) - nz
 		if (math.MaxUint64-gas)/params.TxDataZeroGas < z {
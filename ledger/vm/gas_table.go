@@ -17,6 +17,8 @@
 package vm
 
 import (
+	"math/big"
+
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/math"
 	"github.com/thetatoken/theta/ledger/vm/params"
@@ -121,22 +123,25 @@ func gasSStore(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, m
 		current = evm.StateDB.GetState(contract.Address(), common.BigToHash(x))
 	)
 	// The legacy gas metering only takes into consideration the current state
-	// if !evm.chainRules.IsConstantinople {
-	// 	// This checks for 3 scenario's and calculates gas accordingly:
-	// 	//
-	// 	// 1. From a zero-value address to a non-zero value         (NEW VALUE)
-	// 	// 2. From a non-zero value address to a zero-value address (DELETE)
-	// 	// 3. From a non-zero to a non-zero                         (CHANGE)
-	// 	switch {
-	// 	case current == (common.Hash{}) && y.Sign() != 0: // 0 => non 0
-	// 		return params.SstoreSetGas, nil
-	// 	case current != (common.Hash{}) && y.Sign() == 0: // non 0 => 0
-	// 		evm.StateDB.AddRefund(params.SstoreRefundGas)
-	// 		return params.SstoreClearGas, nil
-	// 	default: // non 0 => non 0 (or 0 => 0)
-	// 		return params.SstoreResetGas, nil
-	// 	}
-	// }
+	if evm.forkLevel == ForkLevelByzantium {
+		// This checks for 3 scenario's and calculates gas accordingly:
+		//
+		// 1. From a zero-value address to a non-zero value         (NEW VALUE)
+		// 2. From a non-zero value address to a zero-value address (DELETE)
+		// 3. From a non-zero to a non-zero                         (CHANGE)
+		switch {
+		case current == (common.Hash{}) && y.Sign() != 0: // 0 => non 0
+			return params.SstoreSetGas, nil
+		case current != (common.Hash{}) && y.Sign() == 0: // non 0 => 0
+			evm.StateDB.AddRefund(params.SstoreRefundGas)
+			return params.SstoreClearGas, nil
+		default: // non 0 => non 0 (or 0 => 0)
+			return params.SstoreResetGas, nil
+		}
+	}
+	if evm.forkLevel == ForkLevelIstanbul {
+		return gasSStoreEIP2200(evm, contract, x, y, current)
+	}
 	// The new gas metering is based on net gas costs (EIP-1283):
 	//
 	// 1. If current value equals new value (this is a no-op), 200 gas is deducted.
@@ -182,6 +187,43 @@ func gasSStore(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, m
 	return params.NetSstoreDirtyGas, nil
 }
 
+// gasSStoreEIP2200 is the net gas metering of EIP-1283 repriced by EIP-2200, which also requires
+// more gas than the call stipend to be left to prevent reentrancy
+func gasSStoreEIP2200(evm *EVM, contract *Contract, x, y *big.Int, current common.Hash) (uint64, error) {
+	if contract.Gas <= params.SstoreSentryGasEIP2200 {
+		return 0, ErrSStoreSentry
+	}
+	value := common.BigToHash(y)
+	if current == value { // noop (1)
+		return params.SstoreNoopGasEIP2200, nil
+	}
+	original := evm.StateDB.GetCommittedState(contract.Address(), common.BigToHash(x))
+	if original == current {
+		if original == (common.Hash{}) { // create slot (2.1.1)
+			return params.SstoreInitGasEIP2200, nil
+		}
+		if value == (common.Hash{}) { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(params.SstoreClearRefundEIP2200)
+		}
+		return params.SstoreCleanGasEIP2200, nil // write existing slot (2.1.2)
+	}
+	if original != (common.Hash{}) {
+		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(params.SstoreClearRefundEIP2200)
+		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(params.SstoreClearRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == (common.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(params.SstoreInitRefundEIP2200)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund(params.SstoreCleanRefundEIP2200)
+		}
+	}
+	return params.SstoreDirtyGasEIP2200, nil // dirty update (2.2)
+}
+
 func makeGasLog(n uint64) gasFunc {
 	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 		requestedSize, overflow := bigUint64(stack.Back(1))
@@ -879,7 +879,9 @@ func opStop(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory
 
 func opSuicide(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
 	balance := interpreter.evm.StateDB.GetBalance(contract.Address())
-	interpreter.evm.StateDB.AddBalance(common.BigToAddress(stack.pop()), balance)
+	beneficiary := common.BigToAddress(stack.pop())
+	interpreter.evm.StateDB.AddBalance(beneficiary, balance)
+	interpreter.evm.recordInternalTx(SELFDESTRUCT, contract.Address(), beneficiary, balance, nil)
 
 	interpreter.evm.StateDB.Suicide(contract.Address())
 	return nil, nil
@@ -38,6 +38,10 @@ type StateDB interface {
 	GetThetaBalance(common.Address) *big.Int // GetThetaBalance returns the ThetaWei balance of the given address
 	GetThetaStake(common.Address) *big.Int   // GetThetaStake returns the total amount of ThetaWei the address staked to validators and/or guardians
 
+	GetValidatorStake(source common.Address, holder common.Address) (*big.Int, *big.Int) // GetValidatorStake returns the stake from the source to the validator candidate, and the total stake of the candidate
+	GetGuardianStake(source common.Address, holder common.Address) (*big.Int, *big.Int)  // GetGuardianStake returns the stake from the source to the guardian, and the total stake of the guardian
+	GetRewardDistribution(holder common.Address) (common.Address, uint)                  // GetRewardDistribution returns the reward beneficiary and the split basis point of the holder
+
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64)
 
@@ -56,6 +60,8 @@ type StateDB interface {
 
 	GetBlockHeight() uint64
 
+	HasLockedCoins(common.Address) bool // HasLockedCoins returns whether the vesting schedules of the address lock part of its balance
+
 	Suicide(common.Address) bool
 	HasSuicided(common.Address) bool
 
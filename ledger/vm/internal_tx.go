@@ -0,0 +1,49 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// recordInternalTx records the value transfer made by a contract. The top level call/create is
+// the transaction itself, and hence not recorded.
+func (evm *EVM) recordInternalTx(op OpCode, from, to common.Address, value *big.Int, thetaValue *big.Int) {
+	if evm.depth == 0 && op != SELFDESTRUCT {
+		return
+	}
+
+	coins := types.Coins{
+		ThetaWei: new(big.Int),
+		TFuelWei: new(big.Int),
+	}
+	if value != nil {
+		coins.TFuelWei.Set(value)
+	}
+	if thetaValue != nil && SupportThetaTransferInEVM(evm.StateDB.GetBlockHeight()) {
+		coins.ThetaWei.Set(thetaValue)
+	}
+	if coins.IsZero() {
+		return
+	}
+
+	evm.internalTxs = append(evm.internalTxs, &types.InternalTransaction{
+		Type:  op.String(),
+		From:  from,
+		To:    to,
+		Coins: coins,
+		Depth: uint64(evm.depth),
+	})
+}
+
+// revertInternalTxs discards the value transfers recorded by a call/create that is reverted
+func (evm *EVM) revertInternalTxs(numInternalTxs int) {
+	evm.internalTxs = evm.internalTxs[:numInternalTxs]
+}
+
+// InternalTxs returns the value transfers made by the contracts during the execution, in the order
+// they were made. The transfers of the reverted calls are excluded.
+func (evm *EVM) InternalTxs() []*types.InternalTransaction {
+	return evm.internalTxs
+}
@@ -0,0 +1,49 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/vm/params"
+)
+
+func TestRecordInternalTxs(t *testing.T) {
+	assert := assert.New(t)
+
+	evm := NewEVM(Context{}, nil, params.TestChainConfig, Config{})
+	contract := common.HexToAddress("0x01")
+	alice := common.HexToAddress("0x02")
+	bob := common.HexToAddress("0x03")
+
+	// The top level call is the transaction itself
+	evm.recordInternalTx(CALL, alice, contract, big.NewInt(100), nil)
+	assert.Equal(0, len(evm.InternalTxs()))
+
+	evm.depth = 1
+	evm.recordInternalTx(CALL, contract, alice, big.NewInt(40), nil)
+	evm.recordInternalTx(CALL, contract, bob, big.NewInt(0), nil) // no value transferred
+
+	// Transfers of the reverted calls are discarded
+	numInternalTxs := len(evm.InternalTxs())
+	evm.depth = 2
+	evm.recordInternalTx(CALL, alice, bob, big.NewInt(10), nil)
+	assert.Equal(2, len(evm.InternalTxs()))
+	evm.revertInternalTxs(numInternalTxs)
+
+	evm.depth = 1
+	evm.recordInternalTx(SELFDESTRUCT, contract, bob, big.NewInt(60), nil)
+
+	itxs := evm.InternalTxs()
+	assert.Equal(2, len(itxs))
+	assert.Equal("CALL", itxs[0].Type)
+	assert.Equal(contract, itxs[0].From)
+	assert.Equal(alice, itxs[0].To)
+	assert.Equal(big.NewInt(40), itxs[0].Coins.TFuelWei)
+	assert.Equal(big.NewInt(0), itxs[0].Coins.ThetaWei)
+	assert.Equal(uint64(1), itxs[0].Depth)
+	assert.Equal("SELFDESTRUCT", itxs[1].Type)
+	assert.Equal(bob, itxs[1].To)
+	assert.Equal(big.NewInt(60), itxs[1].Coins.TFuelWei)
+}
@@ -85,14 +85,14 @@ func NewEVMInterpreter(evm *EVM, cfg Config) *EVMInterpreter {
 	// the jump table was initialised. If it was not
 	// we'll set the default jump table.
 	if !cfg.JumpTable[STOP].valid {
-		cfg.JumpTable = constantinopleInstructionSet
+		cfg.JumpTable = evm.forkLevel.jumpTable()
 	}
 
 	return &EVMInterpreter{
 		evm: evm,
 		cfg: cfg,
 		// gasTable: evm.ChainConfig().GasTable(evm.BlockNumber),
-		gasTable: params.ThetaGasTable,
+		gasTable: evm.forkLevel.gasTable(),
 	}
 }
 
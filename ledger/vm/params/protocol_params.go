@@ -52,6 +52,15 @@ const (
 	NetSstoreResetRefund      uint64 = 4800  // Once per SSTORE operation for resetting to the original non-zero value
 	NetSstoreResetClearRefund uint64 = 19800 // Once per SSTORE operation for resetting to the original zero value
 
+	SstoreSentryGasEIP2200   uint64 = 2300  // Minimum gas required to be present for an SSTORE call, not consumed
+	SstoreNoopGasEIP2200     uint64 = 800   // Once per SSTORE operation if the value doesn't change.
+	SstoreDirtyGasEIP2200    uint64 = 800   // Once per SSTORE operation if a dirty value is changed.
+	SstoreInitGasEIP2200     uint64 = 20000 // Once per SSTORE operation from clean zero to non-zero
+	SstoreInitRefundEIP2200  uint64 = 19200 // Once per SSTORE operation for resetting to the original zero value
+	SstoreCleanGasEIP2200    uint64 = 5000  // Once per SSTORE operation from clean non-zero to something else
+	SstoreCleanRefundEIP2200 uint64 = 4200  // Once per SSTORE operation for resetting to the original non-zero value
+	SstoreClearRefundEIP2200 uint64 = 15000 // Once per SSTORE operation for clearing an originally existing storage slot
+
 	JumpdestGas      uint64 = 1     // Refunded gas, once per SSTORE operation if the zeroness changes to zero.
 	EpochDuration    uint64 = 30000 // Duration between proof-of-work epochs.
 	CallGas          uint64 = 40    // Once per CALL operation & message call transaction.
@@ -93,10 +102,12 @@ const (
 	Bn256ScalarMulGasIstanbul       uint64 = 6000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGasIstanbul     uint64 = 45000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGasIstanbul uint64 = 34000 // Per-point price for an elliptic curve pairing check
+	TxDataNonZeroGasEIP2028         uint64 = 16    // Per byte of non zero data attached to a transaction after EIP 2028
 
-	ThetaBalanceGas  uint64 = 4     // Retrieve the Theta balance for an address
-	ThetaStakeGas    uint64 = 200   // Retrieve the total amount of staked Theta for an address
-	ThetaTransferGas uint64 = 21000 // Transfer Theta balance
+	ThetaBalanceGas    uint64 = 4     // Retrieve the Theta balance for an address
+	ThetaStakeGas      uint64 = 200   // Retrieve the total amount of staked Theta for an address
+	ThetaTransferGas   uint64 = 21000 // Transfer Theta balance
+	ThetaStakeQueryGas uint64 = 400   // Retrieve the validator/guardian stake, or the stake reward distribution
 )
 
 var (
@@ -0,0 +1,162 @@
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/vm/params"
+)
+
+// ForkLevel specifies the Ethereum hard fork whose EVM semantics, i.e. the opcodes, the gas costs,
+// and the gas refunds, are followed by the smart contract execution
+type ForkLevel byte
+
+const (
+	ForkLevelTheta ForkLevel = iota // The EVM semantics prior to the EVM forks of the chain
+	ForkLevelByzantium
+	ForkLevelConstantinople
+	ForkLevelIstanbul
+)
+
+var forkLevelNames = map[ForkLevel]string{
+	ForkLevelTheta:          "theta",
+	ForkLevelByzantium:      "byzantium",
+	ForkLevelConstantinople: "constantinople",
+	ForkLevelIstanbul:       "istanbul",
+}
+
+var (
+	alignedByzantiumInstructionSet      = newAlignedInstructionSet(newByzantiumInstructionSet())
+	alignedConstantinopleInstructionSet = newAlignedInstructionSet(newConstantinopleInstructionSet())
+	alignedIstanbulInstructionSet       = newAlignedIstanbulInstructionSet()
+)
+
+func (level ForkLevel) String() string {
+	if name, ok := forkLevelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("ForkLevel(%d)", byte(level))
+}
+
+// GetForkLevel returns the fork level of the smart contract execution at the given block height of
+// the chain. It only depends on the fork heights of the chain, so that all the nodes agree on it.
+func GetForkLevel(chainID string, blockHeight uint64) ForkLevel {
+	heights := common.GetEVMForkHeights(chainID)
+	switch {
+	case blockHeight >= heights.Istanbul:
+		return ForkLevelIstanbul
+	case blockHeight >= heights.Constantinople:
+		return ForkLevelConstantinople
+	case blockHeight >= heights.Byzantium:
+		return ForkLevelByzantium
+	default:
+		return ForkLevelTheta
+	}
+}
+
+// jumpTable returns the instructions available at the fork level
+func (level ForkLevel) jumpTable() [256]operation {
+	switch level {
+	case ForkLevelByzantium:
+		return alignedByzantiumInstructionSet
+	case ForkLevelConstantinople:
+		return alignedConstantinopleInstructionSet
+	case ForkLevelIstanbul:
+		return alignedIstanbulInstructionSet
+	default:
+		return constantinopleInstructionSet
+	}
+}
+
+// gasTable returns the gas prices at the fork level
+func (level ForkLevel) gasTable() params.GasTable {
+	if level == ForkLevelIstanbul {
+		return params.IstanbulGasTable
+	}
+	return params.ThetaGasTable
+}
+
+// appliesRefunds returns whether the gas refunds, e.g. for clearing the storage, are deducted from the gas used
+func (level ForkLevel) appliesRefunds() bool {
+	return level != ForkLevelTheta
+}
+
+// txDataNonZeroGas returns the intrinsic gas charged per non-zero byte of the transaction data
+func (level ForkLevel) txDataNonZeroGas() uint64 {
+	if level == ForkLevelIstanbul {
+		return params.TxDataNonZeroGasEIP2028
+	}
+	return params.TxDataNonZeroGas
+}
+
+// newAlignedInstructionSet replaces the block information instructions which fail in the
+// legacy instruction sets with the ones that behave like Ethereum, and removes the instructions
+// introduced by Istanbul, which the legacy instruction sets include
+func newAlignedInstructionSet(instructionSet [256]operation) [256]operation {
+	instructionSet[BLOCKHASH].execute = opBlockhashV2
+	instructionSet[COINBASE].execute = opCoinbaseV2
+	instructionSet[DIFFICULTY].execute = opDifficultyV2
+	instructionSet[CHAINID] = operation{}
+	instructionSet[SELFBALANCE] = operation{}
+	return instructionSet
+}
+
+// newAlignedIstanbulInstructionSet adds CHAINID (EIP-1344) and SELFBALANCE (EIP-1884) to the
+// aligned Constantinople instructions
+func newAlignedIstanbulInstructionSet() [256]operation {
+	legacyInstructionSet := newConstantinopleInstructionSet()
+	instructionSet := newAlignedInstructionSet(legacyInstructionSet)
+	instructionSet[CHAINID] = legacyInstructionSet[CHAINID]
+	instructionSet[SELFBALANCE] = legacyInstructionSet[SELFBALANCE]
+	return instructionSet
+}
+
+// applyRefund deducts the refund counter from the gas used, capped at half of the gas used
+func applyRefund(gasUsed uint64, refund uint64) uint64 {
+	if maxRefund := gasUsed / 2; refund > maxRefund {
+		refund = maxRefund
+	}
+	return gasUsed - refund
+}
+
+// revertRefund restores the refund counter when a call/create is reverted, since the counter
+// is not part of the state snapshot
+func (evm *EVM) revertRefund(refund uint64) {
+	if !evm.forkLevel.appliesRefunds() {
+		return
+	}
+	if current := evm.StateDB.GetRefund(); current > refund {
+		evm.StateDB.SubRefund(current - refund)
+	} else if current < refund {
+		evm.StateDB.AddRefund(refund - current)
+	}
+}
+
+// opBlockhashV2 pushes the hash of one of the 256 most recent blocks if available, and zero otherwise
+func opBlockhashV2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	num := stack.pop()
+
+	n := interpreter.intPool.get().Sub(interpreter.evm.BlockNumber, common.Big257)
+	if interpreter.evm.GetHash != nil && num.Cmp(n) > 0 && num.Cmp(interpreter.evm.BlockNumber) < 0 {
+		stack.push(interpreter.evm.GetHash(num.Uint64()).Big())
+	} else {
+		stack.push(interpreter.intPool.getZero())
+	}
+	interpreter.intPool.put(num, n)
+	return nil, nil
+}
+
+func opCoinbaseV2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	stack.push(interpreter.evm.Coinbase.Big())
+	return nil, nil
+}
+
+func opDifficultyV2(pc *uint64, interpreter *EVMInterpreter, contract *Contract, memory *Memory, stack *Stack) ([]byte, error) {
+	difficulty := interpreter.evm.Difficulty
+	if difficulty == nil {
+		difficulty = new(big.Int)
+	}
+	stack.push(interpreter.intPool.get().Set(difficulty))
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/vm/params"
+)
+
+func TestForkLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	// The chains without their own schedule switch to Istanbul at HeightEnableEVMForkLevel
+	assert.Equal(ForkLevelTheta, GetForkLevel("mainnet", common.HeightEnableEVMForkLevel-1))
+	assert.Equal(ForkLevelIstanbul, GetForkLevel("mainnet", common.HeightEnableEVMForkLevel))
+	assert.Equal(ForkLevelIstanbul, GetForkLevel("", common.HeightEnableEVMForkLevel))
+
+	// The legacy instruction set is kept, and the Byzantium instruction set has no Constantinople opcodes
+	assert.True(ForkLevelTheta.jumpTable()[SHR].valid)
+	assert.True(ForkLevelIstanbul.jumpTable()[CREATE2].valid)
+	assert.False(ForkLevelByzantium.jumpTable()[SHR].valid)
+	assert.False(ForkLevelByzantium.jumpTable()[CREATE2].valid)
+
+	// CHAINID and SELFBALANCE are only available from Istanbul
+	assert.True(ForkLevelTheta.jumpTable()[CHAINID].valid)
+	assert.False(ForkLevelByzantium.jumpTable()[CHAINID].valid)
+	assert.False(ForkLevelConstantinople.jumpTable()[SELFBALANCE].valid)
+	assert.True(ForkLevelIstanbul.jumpTable()[CHAINID].valid)
+	assert.True(ForkLevelIstanbul.jumpTable()[SELFBALANCE].valid)
+
+	assert.Equal(params.ThetaGasTable, ForkLevelTheta.gasTable())
+	assert.Equal(uint64(800), ForkLevelIstanbul.gasTable().SLoad)
+	assert.False(ForkLevelTheta.appliesRefunds())
+	assert.True(ForkLevelConstantinople.appliesRefunds())
+}
+
+func TestIntrinsicGasAndRefund(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte{0, 1, 2}
+	gas, err := calculateIntrinsicGas(data, false, ForkLevelTheta)
+	assert.Nil(err)
+	assert.Equal(params.TxGas+params.TxDataZeroGas+2*params.TxDataNonZeroGas, gas)
+	gas, err = calculateIntrinsicGas(data, true, ForkLevelIstanbul)
+	assert.Nil(err)
+	assert.Equal(params.TxGasContractCreation+params.TxDataZeroGas+2*params.TxDataNonZeroGasEIP2028, gas)
+
+	// The refund is capped at half of the gas used
+	assert.Equal(uint64(80000), applyRefund(100000, 20000))
+	assert.Equal(uint64(50000), applyRefund(100000, 60000))
+}
+
+func TestAlignedBlockInformationOps(t *testing.T) {
+	assert := assert.New(t)
+
+	parentHash := common.HexToHash("0x1234")
+	var (
+		env = NewEVM(Context{
+			BlockNumber: big.NewInt(100),
+			GetHash: func(n uint64) common.Hash {
+				if n == 99 {
+					return parentHash
+				}
+				return common.Hash{}
+			},
+		}, nil, params.TestChainConfig, Config{})
+		stack          = newstack()
+		pc             = uint64(0)
+		evmInterpreter = NewEVMInterpreter(env, env.vmConfig)
+	)
+	evmInterpreter.intPool = poolOfIntPools.get()
+
+	// The legacy ops fail
+	_, err := opDifficulty(&pc, evmInterpreter, nil, nil, stack)
+	assert.NotNil(err)
+
+	stack.push(big.NewInt(99))
+	_, err = opBlockhashV2(&pc, evmInterpreter, nil, nil, stack)
+	assert.Nil(err)
+	assert.Equal(parentHash.Big(), stack.pop())
+
+	stack.push(big.NewInt(100)) // the hash of the current block is not available
+	_, err = opBlockhashV2(&pc, evmInterpreter, nil, nil, stack)
+	assert.Nil(err)
+	assert.Equal(0, stack.pop().Sign())
+
+	_, err = opCoinbaseV2(&pc, evmInterpreter, nil, nil, stack)
+	assert.Nil(err)
+	assert.Equal(0, stack.pop().Sign())
+
+	_, err = opDifficultyV2(&pc, evmInterpreter, nil, nil, stack)
+	assert.Nil(err)
+	assert.Equal(0, stack.pop().Sign())
+}
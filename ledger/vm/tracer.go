@@ -0,0 +1,69 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+)
+
+// InternalCall represents a message call or a contract creation made by a contract
+type InternalCall struct {
+	Type  OpCode
+	From  common.Address
+	To    common.Address // Empty for contract creations, since the address is not known before the execution
+	Value *big.Int
+	Gas   *big.Int // Gas requested by the caller, empty for contract creations
+	Depth int
+}
+
+// TxTracer captures the structured logs of each step like the StructLogger, together
+// with the internal calls made by the contracts.
+type TxTracer struct {
+	*StructLogger
+
+	calls []InternalCall
+}
+
+// NewTxTracer returns a new tracer
+func NewTxTracer(cfg *LogConfig) *TxTracer {
+	return &TxTracer{
+		StructLogger: NewStructLogger(cfg),
+	}
+}
+
+// CaptureState records the internal call if the op makes one, and then logs a new structured log message
+func (t *TxTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if err == nil {
+		if call, ok := newInternalCall(op, stack, contract, depth); ok {
+			t.calls = append(t.calls, call)
+		}
+	}
+	return t.StructLogger.CaptureState(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+}
+
+// InternalCalls returns the captured internal calls, in the order they were made.
+func (t *TxTracer) InternalCalls() []InternalCall { return t.calls }
+
+// newInternalCall reads the arguments of the call from the stack before the op is executed
+func newInternalCall(op OpCode, stack *Stack, contract *Contract, depth int) (InternalCall, bool) {
+	call := InternalCall{
+		Type:  op,
+		From:  contract.Address(),
+		Value: new(big.Int),
+		Depth: depth,
+	}
+	switch op {
+	case CALL, CALLCODE:
+		call.Gas = new(big.Int).Set(stack.Back(0))
+		call.To = common.BigToAddress(stack.Back(1))
+		call.Value.Set(stack.Back(2))
+	case DELEGATECALL, STATICCALL:
+		call.Gas = new(big.Int).Set(stack.Back(0))
+		call.To = common.BigToAddress(stack.Back(1))
+	case CREATE, CREATE2:
+		call.Value.Set(stack.Back(0))
+	default:
+		return call, false
+	}
+	return call, true
+}
@@ -0,0 +1,48 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/vm/params"
+)
+
+func TestTxTracerCapturesInternalCalls(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		env      = NewEVM(Context{}, nil, params.TestChainConfig, Config{})
+		tracer   = NewTxTracer(nil)
+		mem      = NewMemory()
+		stack    = newstack()
+		contract = NewContract(&dummyContractRef{}, &dummyContractRef{}, new(big.Int), 0)
+		callee   = common.HexToAddress("0x1234")
+	)
+
+	// CALL(gas, addr, value, inOffset, inSize, outOffset, outSize), the gas is on the top
+	stack.push(big.NewInt(0))
+	stack.push(big.NewInt(0))
+	stack.push(big.NewInt(0))
+	stack.push(big.NewInt(0))
+	stack.push(big.NewInt(500))
+	stack.push(callee.Big())
+	stack.push(big.NewInt(21000))
+	tracer.CaptureState(env, 0, CALL, 100000, 700, mem, stack, contract, 1, nil)
+
+	// Non-call ops and failed ops are not recorded as internal calls
+	tracer.CaptureState(env, 1, ADD, 100000, 3, mem, stack, contract, 1, nil)
+	tracer.CaptureState(env, 2, CALL, 100000, 700, mem, stack, contract, 1, ErrOutOfGas)
+
+	calls := tracer.InternalCalls()
+	assert.Equal(1, len(calls))
+	assert.Equal(CALL, calls[0].Type)
+	assert.Equal(contract.Address(), calls[0].From)
+	assert.Equal(callee, calls[0].To)
+	assert.Equal(big.NewInt(500), calls[0].Value)
+	assert.Equal(big.NewInt(21000), calls[0].Gas)
+	assert.Equal(1, calls[0].Depth)
+
+	assert.Equal(3, len(tracer.StructLogs()))
+}
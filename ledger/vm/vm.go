@@ -68,8 +68,12 @@ func getPrecompiledContracts(blockHeight uint64) map[common.Address]PrecompiledC
 	var precompiles map[common.Address]PrecompiledContract
 	if blockHeight < common.HeightSupportThetaTokenInSmartContract {
 		precompiles = PrecompiledContractsByzantium
-	} else {
+	} else if blockHeight < common.HeightEnableStakingQueryPrecompile {
 		precompiles = PrecompiledContractsThetaSupport
+	} else if blockHeight < common.HeightEnableThetaTransferV2 {
+		precompiles = PrecompiledContractsStakingQuery
+	} else {
+		precompiles = PrecompiledContractsThetaTransferV2
 	}
 	return precompiles
 }
@@ -80,6 +84,11 @@ func run(evm *EVM, contract *Contract, input []byte, readOnly bool) ([]byte, err
 		blockHeight := evm.StateDB.GetBlockHeight()
 		precompiles := getPrecompiledContracts(blockHeight)
 		if p := precompiles[*contract.CodeAddr]; p != nil {
+			if isTokenTransfer(p) && blockHeight >= common.HeightEnableThetaTransferV2 {
+				if err := checkTokenTransferContext(evm, contract, readOnly); err != nil {
+					return nil, err
+				}
+			}
 			return RunPrecompiledContract(evm, p, input, contract)
 		}
 	}
@@ -115,6 +124,7 @@ type Context struct {
 	GasPrice *big.Int       // Provides information for GASPRICE
 
 	// Block information
+	ChainID     string         // Determines the fork level together with the block height
 	Coinbase    common.Address // Provides information for COINBASE
 	GasLimit    uint64         // Provides information for GASLIMIT
 	BlockNumber *big.Int       // Provides information for NUMBER
@@ -143,6 +153,8 @@ type EVM struct {
 	chainConfig *params.ChainConfig
 	// chain rules contains the chain rules for the current epoch
 	chainRules params.Rules
+	// forkLevel specifies the Ethereum hard fork whose EVM semantics are followed
+	forkLevel ForkLevel
 	// virtual machine configuration options used to initialise the
 	// evm.
 	vmConfig Config
@@ -157,6 +169,8 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// internalTxs holds the value transfers made by the contracts
+	internalTxs []*types.InternalTransaction
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -170,6 +184,9 @@ func NewEVM(ctx Context, statedb StateDB, chainConfig *params.ChainConfig, vmCon
 		// chainRules:   chainConfig.Rules(ctx.BlockNumber),
 		interpreters: make([]Interpreter, 0, 1),
 	}
+	if statedb != nil {
+		evm.forkLevel = GetForkLevel(ctx.ChainID, statedb.GetBlockHeight())
+	}
 
 	// vmConfig.EVMInterpreter will be used by EVM-C, it won't be checked here
 	// as we always want to have the built-in EVM as the failover option.
@@ -216,8 +233,10 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	}
 
 	var (
-		to       = AccountRef(addr)
-		snapshot = evm.StateDB.Snapshot()
+		to             = AccountRef(addr)
+		snapshot       = evm.StateDB.Snapshot()
+		refund         = evm.StateDB.GetRefund()
+		numInternalTxs = len(evm.internalTxs)
 	)
 	if !evm.StateDB.Exist(addr) {
 
@@ -242,6 +261,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	if SupportThetaTransferInEVM(blockHeight) {
 		TransferTheta(evm.StateDB, caller.Address(), to.Address(), thetaValue)
 	}
+	evm.recordInternalTx(CALL, caller.Address(), to.Address(), value, thetaValue)
 
 	// Initialise a new contract and set the code that is to be used by the EVM.
 	// The contract is a scoped environment for this execution context only.
@@ -255,6 +275,8 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 	// when we're in homestead this also counts for code storage gas errors.
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertRefund(refund)
+		evm.revertInternalTxs(numInternalTxs)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -285,6 +307,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 
 	var (
 		snapshot = evm.StateDB.Snapshot()
+		refund   = evm.StateDB.GetRefund()
 		to       = AccountRef(caller.Address())
 	)
 	// initialise a new contract and set the code that is to be used by the
@@ -296,6 +319,7 @@ func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte,
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertRefund(refund)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -319,6 +343,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 
 	var (
 		snapshot = evm.StateDB.Snapshot()
+		refund   = evm.StateDB.GetRefund()
 		to       = AccountRef(caller.Address())
 	)
 
@@ -329,6 +354,7 @@ func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []by
 	ret, err = run(evm, contract, input, false)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertRefund(refund)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -352,6 +378,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	var (
 		to       = AccountRef(addr)
 		snapshot = evm.StateDB.Snapshot()
+		refund   = evm.StateDB.GetRefund()
 	)
 	// Initialise a new contract and set the code that is to be used by the
 	// EVM. The contract is a scoped environment for this execution context
@@ -365,6 +392,7 @@ func (evm *EVM) StaticCall(caller ContractRef, addr common.Address, input []byte
 	ret, err = run(evm, contract, input, true)
 	if err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertRefund(refund)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -385,7 +413,7 @@ func (c *codeAndHash) Hash() common.Hash {
 }
 
 // create creates a new contract using code as deployment code.
-func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, thetaValue *big.Int, address common.Address) ([]byte, common.Address, uint64, error) {
+func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64, value *big.Int, thetaValue *big.Int, address common.Address, op OpCode) ([]byte, common.Address, uint64, error) {
 	// Depth check execution. Fail if we're trying to execute above the
 	// limit.
 	if evm.depth > int(params.CallCreateDepth) {
@@ -407,8 +435,14 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if evm.StateDB.GetNonce(address) != 0 || (contractHash != (common.Hash{}) && contractHash != types.EmptyCodeHash) {
 		return nil, common.Address{}, 0, ErrContractAddressCollision
 	}
+	// The contract could move the coins locked by the vesting schedules of the address
+	if evm.StateDB.HasLockedCoins(address) {
+		return nil, common.Address{}, 0, ErrContractAddressLocked
+	}
 	// Create a new account on the state
 	snapshot := evm.StateDB.Snapshot()
+	refund := evm.StateDB.GetRefund()
+	numInternalTxs := len(evm.internalTxs)
 
 	if !SupportThetaTransferInEVM(blockHeight) { // just for backward compatibility
 		evm.StateDB.CreateAccount(address)
@@ -420,6 +454,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	if SupportThetaTransferInEVM(blockHeight) {
 		TransferTheta(evm.StateDB, caller.Address(), address, thetaValue)
 	}
+	evm.recordInternalTx(op, caller.Address(), address, value, thetaValue)
 
 	// initialise a new contract and set the code that is to be used by the
 	// EVM. The contract is a scoped environment for this execution context
@@ -458,6 +493,8 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 	// when we're in homestead this also counts for code storage gas errors.
 	if maxCodeSizeExceeded || err != nil {
 		evm.StateDB.RevertToSnapshot(snapshot)
+		evm.revertRefund(refund)
+		evm.revertInternalTxs(numInternalTxs)
 		if err != errExecutionReverted {
 			contract.UseGas(contract.Gas)
 		}
@@ -476,7 +513,7 @@ func (evm *EVM) create(caller ContractRef, codeAndHash *codeAndHash, gas uint64,
 // Create creates a new contract using code as deployment code.
 func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.Int, thetaValue *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	contractAddr = crypto.CreateAddress(caller.Address(), evm.StateDB.GetNonce(caller.Address()))
-	return evm.create(caller, &codeAndHash{code: code}, gas, value, thetaValue, contractAddr)
+	return evm.create(caller, &codeAndHash{code: code}, gas, value, thetaValue, contractAddr, CREATE)
 }
 
 // Create2 creates a new contract using code as deployment code.
@@ -486,7 +523,7 @@ func (evm *EVM) Create(caller ContractRef, code []byte, gas uint64, value *big.I
 func (evm *EVM) Create2(caller ContractRef, code []byte, gas uint64, endowment *big.Int, thetaEndowment *big.Int, salt *big.Int) (ret []byte, contractAddr common.Address, leftOverGas uint64, err error) {
 	codeAndHash := &codeAndHash{code: code}
 	contractAddr = crypto.CreateAddress2(caller.Address(), common.BigToHash(salt), codeAndHash.Hash().Bytes())
-	return evm.create(caller, codeAndHash, gas, endowment, thetaEndowment, contractAddr)
+	return evm.create(caller, codeAndHash, gas, endowment, thetaEndowment, contractAddr, CREATE2)
 }
 
 // ChainConfig returns the environment's chain configuration
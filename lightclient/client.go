@@ -0,0 +1,353 @@
+package lightclient
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/rlp"
+	"github.com/thetatoken/theta/rpc"
+)
+
+//
+// LightClient syncs the block headers and validator set changes from a full node, and
+// verifies them locally. State queries are answered with the Merkle proofs provided by
+// the full node, checked against the state roots of the certified headers.
+//
+type LightClient struct {
+	remote         rpc.Client
+	verifier       *Verifier
+	checkpointPath string
+	syncInterval   time.Duration
+
+	// Life cycle
+	wg      *sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+// NewLightClient creates a light client. It resumes from the checkpoint if one exists,
+// otherwise it starts from the trusted block specified in the config.
+func NewLightClient(remoteEndpoint string, checkpointPath string) (*LightClient, error) {
+	lc := &LightClient{
+		remote:         rpc.NewClient(remoteEndpoint),
+		checkpointPath: checkpointPath,
+		syncInterval:   time.Duration(viper.GetInt(common.CfgLightSyncIntervalSecs)) * time.Second,
+		wg:             &sync.WaitGroup{},
+	}
+
+	checkpoint, err := loadCheckpoint(checkpointPath)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		checkpoint, err = lc.bootstrap()
+		if err != nil {
+			return nil, err
+		}
+	}
+	lc.verifier = NewVerifier(checkpoint.Header, checkpoint.ValidatorSets, checkpoint.SigningKeys)
+
+	logger.Infof("Light client starts from block %v, height: %v", checkpoint.Header.Hash().Hex(), checkpoint.Header.Height)
+
+	return lc, nil
+}
+
+// Verifier returns the header verifier of the light client
+func (lc *LightClient) Verifier() *Verifier {
+	return lc.verifier
+}
+
+// Start creates the main goroutine.
+func (lc *LightClient) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	lc.ctx = c
+	lc.cancel = cancel
+
+	lc.wg.Add(1)
+	go lc.mainLoop()
+}
+
+// Stop notifies all goroutines to stop without blocking.
+func (lc *LightClient) Stop() {
+	lc.cancel()
+}
+
+// Wait blocks until all goroutines stop.
+func (lc *LightClient) Wait() {
+	lc.wg.Wait()
+}
+
+func (lc *LightClient) mainLoop() {
+	defer lc.wg.Done()
+
+	ticker := time.NewTicker(lc.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := lc.syncHeaders(); err != nil {
+			logger.Warnf("Failed to sync headers: %v", err)
+		}
+		if err := saveCheckpoint(lc.checkpointPath, lc.verifier.Checkpoint()); err != nil {
+			logger.Warnf("Failed to save checkpoint: %v", err)
+		}
+
+		select {
+		case <-lc.ctx.Done():
+			lc.stopped = true
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncHeaders downloads and verifies the headers up to the latest finalized block of the full node
+func (lc *LightClient) syncHeaders() error {
+	status := &rpc.GetStatusResult{}
+	err := lc.remote.Call("theta.GetStatus", []interface{}{&rpc.GetStatusArgs{}}, status)
+	if err != nil {
+		return err
+	}
+	latest := uint64(status.LatestFinalizedBlockHeight)
+
+	for lc.verifier.LastHeader().Height < latest {
+		select {
+		case <-lc.ctx.Done():
+			return nil
+		default:
+		}
+
+		start := lc.verifier.LastHeader().Height + 1
+		headers, err := lc.getHeaders(start, latest)
+		if err != nil {
+			return err
+		}
+		if len(headers) == 0 {
+			return fmt.Errorf("no header returned for height %v", start)
+		}
+		for _, lh := range headers {
+			hasValidatorUpdate, err := HasValidatorUpdate(lh.txs)
+			if err != nil {
+				return err
+			}
+			var vcpProof *core.VCPProof
+			if hasValidatorUpdate {
+				vcpProof, err = lc.getValidatorSetProof(lh.header)
+				if err != nil {
+					return err
+				}
+			}
+			if err := lc.verifier.AddHeader(lh.header, lh.txs, vcpProof); err != nil {
+				return err
+			}
+		}
+
+		certified := lc.verifier.LatestCertifiedHeader()
+		logger.Debugf("Synced headers up to height %v, certified height: %v", lc.verifier.LastHeader().Height, certified.Height)
+	}
+	return nil
+}
+
+type lightHeader struct {
+	header *core.BlockHeader
+	txs    []common.Bytes
+}
+
+func (lc *LightClient) getHeaders(start, end uint64) ([]lightHeader, error) {
+	result := &rpc.GetBlockHeadersResult{}
+	args := &rpc.GetBlockHeadersArgs{
+		Start: common.JSONUint64(start),
+		End:   common.JSONUint64(end),
+	}
+	err := lc.remote.Call("theta.GetBlockHeaders", []interface{}{args}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := []lightHeader{}
+	for _, h := range result.Headers {
+		raw, err := hex.DecodeString(h.Header)
+		if err != nil {
+			return nil, err
+		}
+		header := &core.BlockHeader{}
+		err = rlp.DecodeBytes(raw, header)
+		if err != nil {
+			return nil, err
+		}
+		txs := []common.Bytes{}
+		for _, t := range h.Txs {
+			rawTx, err := hex.DecodeString(t)
+			if err != nil {
+				return nil, err
+			}
+			txs = append(txs, rawTx)
+		}
+		headers = append(headers, lightHeader{
+			header: header,
+			txs:    txs,
+		})
+	}
+	return headers, nil
+}
+
+// getValidatorSetProof returns the proof of the validator candidate pool at the given header, along
+// with the proofs of the registered BLS pubkeys of the selected validators, and their signing keys
+// once the key rotation is enabled
+func (lc *LightClient) getValidatorSetProof(header *core.BlockHeader) (*core.VCPProof, error) {
+	vcpProof, err := lc.getStateProof(state.ValidatorCandidatePoolKey(), header.Height)
+	if err != nil {
+		return nil, err
+	}
+	valSet, _, err := ValidatorSetFromProof(header.StateHash, 0, vcpProof)
+	if err != nil {
+		return nil, err
+	}
+	keys := []common.Bytes{}
+	for _, validator := range valSet.Validators() {
+		keys = append(keys, state.ValidatorBLSPubkeyKey(validator.ID()))
+		if header.Height >= common.HeightEnableValidatorKeyRotation {
+			keys = append(keys, state.ValidatorSigningKeyKey(validator.ID()))
+		}
+	}
+	return lc.getStateProof(state.ValidatorCandidatePoolKey(), header.Height, keys...)
+}
+
+// getStateProof returns the Merkle proof of the given key, which also covers the extra keys if any
+func (lc *LightClient) getStateProof(key common.Bytes, height uint64, extraKeys ...common.Bytes) (*core.VCPProof, error) {
+	result := &rpc.GetStateProofResult{}
+	args := &rpc.GetStateProofArgs{
+		Key:    hex.EncodeToString(key),
+		Height: common.JSONUint64(height),
+	}
+	for _, extraKey := range extraKeys {
+		args.ExtraKeys = append(args.ExtraKeys, hex.EncodeToString(extraKey))
+	}
+	err := lc.remote.Call("theta.GetStateProof", []interface{}{args}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := hex.DecodeString(result.Proof)
+	if err != nil {
+		return nil, err
+	}
+	proof := &core.VCPProof{}
+	err = rlp.DecodeBytes(raw, proof)
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// GetProvenState returns the value of the state key at the given height, proven against the state
+// root of the certified header. Height 0 means the latest certified header.
+func (lc *LightClient) GetProvenState(key common.Bytes, height uint64) (common.Bytes, *core.BlockHeader, error) {
+	var header *core.BlockHeader
+	if height == 0 {
+		header = lc.verifier.LatestCertifiedHeader()
+	} else {
+		var ok bool
+		header, ok = lc.verifier.CertifiedHeader(height)
+		if !ok {
+			return nil, nil, fmt.Errorf("no certified header at height %v", height)
+		}
+	}
+
+	proof, err := lc.getStateProof(key, header.Height)
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err := VerifyStateProof(header.StateHash, key, proof)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid state proof: %v", err)
+	}
+	return value, header, nil
+}
+
+// bootstrap retrieves the trusted block and its validator set
+func (lc *LightClient) bootstrap() (*Checkpoint, error) {
+	height := uint64(viper.GetInt64(common.CfgLightTrustedHeight))
+	headers, err := lc.getHeaders(height, height)
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("trusted block at height %v is not available", height)
+	}
+	trusted := headers[0].header
+
+	expectedHash := viper.GetString(common.CfgLightTrustedBlockHash)
+	if expectedHash == "" {
+		if height != core.GenesisBlockHeight {
+			return nil, fmt.Errorf("%v must be specified for trusted height %v", common.CfgLightTrustedBlockHash, height)
+		}
+		if trusted.ChainID == core.MainnetChainID {
+			expectedHash = core.MainnetGenesisBlockHash
+		} else {
+			expectedHash = viper.GetString(common.CfgGenesisHash)
+		}
+	}
+	if trusted.Hash() != common.HexToHash(expectedHash) {
+		return nil, fmt.Errorf("trusted block hash mismatch, expected: %v, got: %v", expectedHash, trusted.Hash().Hex())
+	}
+
+	vcpProof, err := lc.getValidatorSetProof(trusted)
+	if err != nil {
+		return nil, err
+	}
+	valSet, signingKeys, err := ValidatorSetFromProof(trusted.StateHash, height, vcpProof)
+	if err != nil {
+		return nil, err
+	}
+	blsPubkeys, err := ValidatorBLSPubkeysFromProof(trusted.StateHash, valSet, vcpProof)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checkpoint{
+		Header: trusted,
+		ValidatorSets: []ValidatorSetEntry{
+			ValidatorSetEntry{FromHeight: height, Validators: valSet.Validators(), BLSPubkeys: blsPubkeys},
+		},
+		SigningKeys: signingKeys,
+	}, nil
+}
+
+func loadCheckpoint(filePath string) (*Checkpoint, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	checkpoint := &Checkpoint{}
+	err = rlp.DecodeBytes(raw, checkpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %v: %v", filePath, err)
+	}
+	return checkpoint, nil
+}
+
+func saveCheckpoint(filePath string, checkpoint *Checkpoint) error {
+	raw, err := rlp.EncodeToBytes(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmpPath := filePath + ".tmp"
+	err = ioutil.WriteFile(tmpPath, raw, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
@@ -0,0 +1,166 @@
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	netrpc "net/rpc"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc"
+	"github.com/thetatoken/theta/rpc/lib/rpc-codec/jsonrpc2"
+	"golang.org/x/net/netutil"
+)
+
+// LightRPCService serves the queries verified by the light client
+type LightRPCService struct {
+	client *LightClient
+}
+
+// LightRPCServer is an instance of the light client RPC service.
+type LightRPCServer struct {
+	*LightRPCService
+
+	server *http.Server
+
+	// Life cycle
+	wg      *sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+}
+
+// NewLightRPCServer creates a new instance of LightRPCServer.
+func NewLightRPCServer(client *LightClient) *LightRPCServer {
+	t := &LightRPCServer{
+		LightRPCService: &LightRPCService{
+			client: client,
+		},
+		wg: &sync.WaitGroup{},
+	}
+
+	s := netrpc.NewServer()
+	s.RegisterName("theta", t.LightRPCService)
+
+	router := mux.NewRouter()
+	router.Handle("/rpc", rpc.TimeoutHandler(jsonrpc2.HTTPHandler(s), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, ""))
+	t.server = &http.Server{
+		Handler: router,
+	}
+
+	return t
+}
+
+// Start creates the main goroutine.
+func (t *LightRPCServer) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	t.ctx = c
+	t.cancel = cancel
+
+	t.wg.Add(1)
+	go t.mainLoop()
+}
+
+// Stop notifies all goroutines to stop without blocking.
+func (t *LightRPCServer) Stop() {
+	t.cancel()
+}
+
+// Wait blocks until all goroutines stop.
+func (t *LightRPCServer) Wait() {
+	t.wg.Wait()
+}
+
+func (t *LightRPCServer) mainLoop() {
+	defer t.wg.Done()
+
+	go t.serve()
+
+	<-t.ctx.Done()
+	t.stopped = true
+	t.server.Shutdown(context.Background())
+}
+
+func (t *LightRPCServer) serve() {
+	address := viper.GetString(common.CfgRPCAddress)
+	port := viper.GetString(common.CfgLightRPCPort)
+	l, err := net.Listen("tcp", address+":"+port)
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err}).Fatal("Failed to create listener")
+	} else {
+		logger.WithFields(log.Fields{"address": address, "port": port}).Info("Light client RPC server started")
+	}
+	defer l.Close()
+
+	ll := netutil.LimitListener(l, viper.GetInt(common.CfgRPCMaxConnections))
+	logger.Info(t.server.Serve(ll))
+}
+
+// ------------------------------ GetStatus -----------------------------------
+
+type GetStatusArgs struct{}
+
+type GetStatusResult struct {
+	ChainID                    string            `json:"chain_id"`
+	LatestCertifiedBlockHash   common.Hash       `json:"latest_certified_block_hash"`
+	LatestCertifiedBlockHeight common.JSONUint64 `json:"latest_certified_block_height"`
+	LatestCertifiedBlockTime   *common.JSONBig   `json:"latest_certified_block_time"`
+	LastSyncedBlockHeight      common.JSONUint64 `json:"last_synced_block_height"`
+}
+
+func (t *LightRPCService) GetStatus(args *GetStatusArgs, result *GetStatusResult) (err error) {
+	certified := t.client.Verifier().LatestCertifiedHeader()
+	result.ChainID = certified.ChainID
+	result.LatestCertifiedBlockHash = certified.Hash()
+	result.LatestCertifiedBlockHeight = common.JSONUint64(certified.Height)
+	result.LatestCertifiedBlockTime = (*common.JSONBig)(certified.Timestamp)
+	result.LastSyncedBlockHeight = common.JSONUint64(t.client.Verifier().LastHeader().Height)
+	return nil
+}
+
+// ------------------------------ GetAccount -----------------------------------
+
+type GetAccountResult struct {
+	*types.Account
+	Address     string            `json:"address"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+}
+
+// GetAccount returns the account proven against the state root of a certified header
+func (t *LightRPCService) GetAccount(args *rpc.GetAccountArgs, result *GetAccountResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address := common.HexToAddress(args.Address)
+	result.Address = args.Address
+
+	data, header, err := t.client.GetProvenState(state.AccountKey(address), uint64(args.Height))
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("Account with address %s is not found", address.Hex())
+	}
+	account := &types.Account{}
+	err = types.FromBytes(data, account)
+	if err != nil {
+		return err
+	}
+	account.UpdateToHeight(header.Height)
+
+	result.Account = account
+	result.BlockHash = header.Hash()
+	result.BlockHeight = common.JSONUint64(header.Height)
+
+	return nil
+}
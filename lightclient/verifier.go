@@ -0,0 +1,469 @@
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto/bls"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rlp"
+	"github.com/thetatoken/theta/store/trie"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "lightclient"})
+
+// maxRecentHeaders is the number of recent headers kept in memory
+const maxRecentHeaders = 4096
+
+// heightEnableValidatorVoteAggregation is the fork height from which the headers carry the aggregated
+// validator votes instead of the votes of the commit certificate
+var heightEnableValidatorVoteAggregation = common.HeightEnableValidatorVoteAggregation
+
+// ValidatorSetEntry is a validator set along with the first block height whose
+// commit certificate should be signed by it
+type ValidatorSetEntry struct {
+	FromHeight uint64
+	Validators []core.Validator
+	BLSPubkeys []*bls.PublicKey `rlp:"tail"` // registered BLS pubkeys in the order of the validators, optional
+}
+
+// SigningKeyEntry is the signing key rotation of a validator stake holder
+type SigningKeyEntry struct {
+	Holder common.Address
+	Key    types.ValidatorSigningKey
+}
+
+// validatorUpdate is the validator change of an uncertified header
+type validatorUpdate struct {
+	valSet      *core.ValidatorSet            // the new validator set, nil if the stakes are not updated
+	signingKeys []SigningKeyEntry             // the signing keys of the new validators, proven along with the validator set
+	blsPubkeys  []*bls.PublicKey              // the registered BLS pubkeys of the new validators, proven along with the validator set
+	rotations   []*types.RotateValidatorKeyTx // the signing key rotations of the block
+}
+
+//
+// Verifier verifies a chain of block headers starting from a trusted block. A header is
+// certified once a later header carries a commit certificate for it, signed by the majority
+// of the validators. After the validator vote aggregation fork, the certificate is the
+// aggregated votes, verified with the registered BLS pubkeys of the validators. Validator
+// set changes are tracked with the Merkle proofs of the validator candidate pool, in the
+// same way as the snapshot validation. The signing key rotations are tracked with the
+// proofs of the signing keys and the rotation transactions.
+//
+type Verifier struct {
+	mutex *sync.RWMutex
+
+	chainID   string
+	last      *core.BlockHeader // the last accepted header
+	certified *core.BlockHeader // the latest certified header
+
+	headers           map[common.Hash]*core.BlockHeader
+	certifiedByHeight map[uint64]*core.BlockHeader
+	valSets           []ValidatorSetEntry                          // ordered by FromHeight
+	signingKeys       map[common.Address]types.ValidatorSigningKey // signing key rotations of the certified blocks
+	pendingUpdates    map[common.Hash]*validatorUpdate             // validator updates of the uncertified headers
+}
+
+// NewVerifier creates a verifier starting from the given trusted header, validator sets, and signing keys
+func NewVerifier(trusted *core.BlockHeader, valSets []ValidatorSetEntry, signingKeys []SigningKeyEntry) *Verifier {
+	v := &Verifier{
+		mutex:             &sync.RWMutex{},
+		chainID:           trusted.ChainID,
+		last:              trusted,
+		certified:         trusted,
+		headers:           make(map[common.Hash]*core.BlockHeader),
+		certifiedByHeight: make(map[uint64]*core.BlockHeader),
+		valSets:           valSets,
+		signingKeys:       make(map[common.Address]types.ValidatorSigningKey),
+		pendingUpdates:    make(map[common.Hash]*validatorUpdate),
+	}
+	for _, entry := range signingKeys {
+		v.signingKeys[entry.Holder] = entry.Key
+	}
+	v.headers[trusted.Hash()] = trusted
+	v.certifiedByHeight[trusted.Height] = trusted
+	return v
+}
+
+// AddHeader verifies and accepts the next header of the chain along with the transactions of the
+// block, from which the validator set changes are derived locally rather than trusting the remote
+// node. The Merkle proof of the validator candidate pool, along with the signing keys of the
+// selected validators, is required if the block updates the validator set. The new validator set
+// and the signing key rotations are only adopted once the header is certified by the current
+// validator set.
+func (v *Verifier) AddHeader(header *core.BlockHeader, txs []common.Bytes, vcpProof *core.VCPProof) error {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if header.ChainID != v.chainID {
+		return fmt.Errorf("chain ID mismatch, expected: %v, got: %v", v.chainID, header.ChainID)
+	}
+	if header.Height != v.last.Height+1 || header.Parent != v.last.Hash() {
+		return fmt.Errorf("header %v at height %v is not a child of the last header %v",
+			header.Hash().Hex(), header.Height, v.last.Hash().Hex())
+	}
+	if header.TxHash != core.CalculateRootHash(txs) {
+		return fmt.Errorf("transactions do not match the tx hash of header %v", header.Hash().Hex())
+	}
+
+	hasValidatorUpdate, rotations, err := parseValidatorUpdates(txs)
+	if err != nil {
+		return err
+	}
+	update := &validatorUpdate{rotations: rotations}
+	if hasValidatorUpdate {
+		if vcpProof == nil {
+			return fmt.Errorf("VCP proof is required for header %v, which updates the validator set", header.Hash().Hex())
+		}
+		update.valSet, update.signingKeys, err = ValidatorSetFromProof(header.StateHash, header.Height, vcpProof)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve validator set from VCP proof: %v", err)
+		}
+		update.blsPubkeys, err = ValidatorBLSPubkeysFromProof(header.StateHash, update.valSet, vcpProof)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve validator BLS pubkeys from VCP proof: %v", err)
+		}
+	}
+
+	var target *core.BlockHeader
+	if header.ValidatorVotes != nil {
+		if header.Height < heightEnableValidatorVoteAggregation {
+			return fmt.Errorf("validator votes of header %v are not enabled yet", header.Hash().Hex())
+		}
+		var ok bool
+		target, ok = v.headers[header.HCC.BlockHash]
+		if !ok {
+			return fmt.Errorf("HCC of header %v references unknown block %v", header.Hash().Hex(), header.HCC.BlockHash.Hex())
+		}
+		res := header.ValidatorVotes.Validate(target.Hash(), v.validatorSetFor(target.Height), v.validatorBLSPubkeysFor(target.Height))
+		if res.IsError() {
+			return fmt.Errorf("invalid validator votes for block %v: %v", target.Hash().Hex(), res.Message)
+		}
+	} else if header.HCC.Votes != nil && !header.HCC.Votes.IsEmpty() {
+		var ok bool
+		target, ok = v.headers[header.HCC.BlockHash]
+		if !ok {
+			return fmt.Errorf("HCC of header %v references unknown block %v", header.Hash().Hex(), header.HCC.BlockHash.Hex())
+		}
+		if !header.HCC.IsValid(v.validatorSetFor(target.Height)) {
+			return fmt.Errorf("invalid commit certificate for block %v", target.Hash().Hex())
+		}
+	}
+	if target != nil && target.Height > v.certified.Height {
+		updates, err := v.collectValidatorUpdates(target)
+		if err != nil {
+			return err
+		}
+		v.certify(target)
+		for _, u := range updates {
+			v.applyValidatorUpdate(u.header, u.update)
+		}
+	}
+
+	v.last = header
+	v.headers[header.Hash()] = header
+	if update.valSet != nil || len(update.rotations) > 0 {
+		v.pendingUpdates[header.Hash()] = update
+	}
+	if header.Height%256 == 0 {
+		v.prune()
+	}
+
+	return nil
+}
+
+type headerUpdate struct {
+	header *core.BlockHeader
+	update *validatorUpdate
+}
+
+// collectValidatorUpdates returns the validator changes of the headers certified along with the
+// target, in the ascending order of the heights. Same as the snapshot validation, the votes for the
+// child of the validator update block are still signed by the previous validator set. Since the
+// consensus requires the validator update block to be certified by its child, a commit certificate
+// skipping over a validator update, i.e. signed by the stale validator set, is rejected.
+func (v *Verifier) collectValidatorUpdates(target *core.BlockHeader) ([]headerUpdate, error) {
+	updates := []headerUpdate{}
+	for h := target; h != nil && h.Height > v.certified.Height; h = v.headers[h.Parent] {
+		update, ok := v.pendingUpdates[h.Hash()]
+		if !ok {
+			continue
+		}
+		if update.valSet != nil && h.Height+2 <= target.Height {
+			return nil, fmt.Errorf("commit certificate for block %v skips the validator update at height %v",
+				target.Hash().Hex(), h.Height)
+		}
+		updates = append([]headerUpdate{headerUpdate{header: h, update: update}}, updates...)
+	}
+	return updates, nil
+}
+
+// applyValidatorUpdate adopts the validator changes of a certified header. The rotation transactions
+// are applied the same way as the ledger, i.e. the key in use at the block height stays in use until
+// the activation height of the new key.
+func (v *Verifier) applyValidatorUpdate(header *core.BlockHeader, update *validatorUpdate) {
+	for _, tx := range update.rotations {
+		holder := tx.Holder.Address
+		prevAddress := holder
+		prevBlsPubkey := v.registeredBLSPubkey(holder)
+		if prev, ok := v.signingKeys[holder]; ok {
+			prevAddress = prev.SigningAddress(header.Height)
+			prevBlsPubkey = prev.BLSPubkey(header.Height)
+		}
+		key := types.ValidatorSigningKey{
+			Address:          tx.SigningAddress,
+			ActivationHeight: tx.ActivationHeight,
+			PrevAddress:      prevAddress,
+			PrevBlsPubkey:    prevBlsPubkey,
+		}
+		if !tx.BlsPubkey.IsEmpty() {
+			key.BlsPubkey = tx.BlsPubkey
+		}
+		v.signingKeys[holder] = key
+		logger.Infof("Signing key of validator %v rotated at height %v: %v", holder.Hex(), header.Height, tx.SigningAddress.Hex())
+	}
+	if update.valSet == nil {
+		return
+	}
+
+	// The proven signing keys reflect the state after the block, including its rotations
+	for _, validator := range update.valSet.Validators() {
+		delete(v.signingKeys, validator.ID())
+	}
+	for _, entry := range update.signingKeys {
+		v.signingKeys[entry.Holder] = entry.Key
+	}
+	v.valSets = append(v.valSets, ValidatorSetEntry{
+		FromHeight: header.Height + 2,
+		Validators: update.valSet.Validators(),
+		BLSPubkeys: update.blsPubkeys,
+	})
+	logger.Infof("Validator set updated at height %v: %v", header.Height, update.valSet)
+}
+
+// certify marks the header and its uncertified ancestors as certified
+func (v *Verifier) certify(header *core.BlockHeader) {
+	for h := header; h != nil && h.Height > v.certified.Height; h = v.headers[h.Parent] {
+		v.certifiedByHeight[h.Height] = h
+		delete(v.pendingUpdates, h.Hash())
+	}
+	v.certified = header
+}
+
+// validatorSetFor returns the validator set signing the commit certificate of the block at the given
+// height, with the signing addresses of the validators at the height
+func (v *Verifier) validatorSetFor(height uint64) *core.ValidatorSet {
+	valSet := core.NewValidatorSet()
+	if entry := v.validatorSetEntryFor(height); entry != nil {
+		valSet.SetValidators(entry.Validators)
+	}
+	for _, validator := range valSet.Validators() {
+		if key, ok := v.signingKeys[validator.ID()]; ok {
+			valSet.SetSigningAddress(validator.ID(), key.SigningAddress(height))
+		}
+	}
+	return valSet
+}
+
+// validatorBLSPubkeysFor returns the BLS pubkeys of the validator set signing the commit certificate
+// of the block at the given height, in the order of the validator set. Same as the ledger, the BLS
+// pubkey of a rotated signing key replaces the registered one.
+func (v *Verifier) validatorBLSPubkeysFor(height uint64) []*bls.PublicKey {
+	entry := v.validatorSetEntryFor(height)
+	if entry == nil {
+		return nil
+	}
+	pubkeys := []*bls.PublicKey{}
+	for idx, validator := range entry.Validators {
+		var pubkey *bls.PublicKey
+		if idx < len(entry.BLSPubkeys) {
+			pubkey = entry.BLSPubkeys[idx]
+		}
+		if key, ok := v.signingKeys[validator.ID()]; ok {
+			pubkey = key.BLSPubkey(height)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys
+}
+
+// registeredBLSPubkey returns the BLS pubkey registered by the validator in the latest validator set
+func (v *Verifier) registeredBLSPubkey(validator common.Address) *bls.PublicKey {
+	if len(v.valSets) == 0 {
+		return nil
+	}
+	entry := v.valSets[len(v.valSets)-1]
+	for idx, val := range entry.Validators {
+		if val.ID() == validator && idx < len(entry.BLSPubkeys) {
+			return entry.BLSPubkeys[idx]
+		}
+	}
+	return nil
+}
+
+// validatorSetEntryFor returns the validator set entry in effect at the given height
+func (v *Verifier) validatorSetEntryFor(height uint64) *ValidatorSetEntry {
+	for i := len(v.valSets) - 1; i >= 0; i-- {
+		if v.valSets[i].FromHeight <= height {
+			return &v.valSets[i]
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) prune() {
+	if v.last.Height < maxRecentHeaders {
+		return
+	}
+	minHeight := v.last.Height - maxRecentHeaders
+	for hash, header := range v.headers {
+		if header.Height < minHeight && header != v.certified {
+			delete(v.headers, hash)
+			delete(v.pendingUpdates, hash)
+		}
+	}
+	for height, header := range v.certifiedByHeight {
+		if height < minHeight && header != v.certified {
+			delete(v.certifiedByHeight, height)
+		}
+	}
+}
+
+// LastHeader returns the last accepted header
+func (v *Verifier) LastHeader() *core.BlockHeader {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.last
+}
+
+// LatestCertifiedHeader returns the latest header with a verified commit certificate
+func (v *Verifier) LatestCertifiedHeader() *core.BlockHeader {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	return v.certified
+}
+
+// CertifiedHeader returns the certified header at the given height, if still in memory
+func (v *Verifier) CertifiedHeader(height uint64) (*core.BlockHeader, bool) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	header, ok := v.certifiedByHeight[height]
+	return header, ok
+}
+
+// Checkpoint returns the latest certified header along with the validator sets and the
+// signing keys, from which a verifier can be restored
+func (v *Verifier) Checkpoint() *Checkpoint {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	signingKeys := []SigningKeyEntry{}
+	for holder, key := range v.signingKeys {
+		signingKeys = append(signingKeys, SigningKeyEntry{Holder: holder, Key: key})
+	}
+	return &Checkpoint{
+		Header:        v.certified,
+		ValidatorSets: append([]ValidatorSetEntry{}, v.valSets...),
+		SigningKeys:   signingKeys,
+	}
+}
+
+// Checkpoint is the persisted state of the light client
+type Checkpoint struct {
+	Header        *core.BlockHeader
+	ValidatorSets []ValidatorSetEntry
+	SigningKeys   []SigningKeyEntry `rlp:"tail"` // optional, so that the earlier checkpoints can still be decoded
+}
+
+// ValidatorSetFromProof retrieves the validator set from the Merkle proof of the validator
+// candidate pool against the given state root at the given height. Once the key rotation is
+// enabled, the proof also needs to cover the signing keys of the selected validators, which
+// are returned for the validators that rotated their keys.
+func ValidatorSetFromProof(stateHash common.Hash, height uint64, vcpProof *core.VCPProof) (*core.ValidatorSet, []SigningKeyEntry, error) {
+	serializedVCP, err := VerifyStateProof(stateHash, state.ValidatorCandidatePoolKey(), vcpProof)
+	if err != nil {
+		return nil, nil, err
+	}
+	if serializedVCP == nil {
+		return nil, nil, errors.New("validator candidate pool not found in the proof")
+	}
+
+	vcp := &core.ValidatorCandidatePool{}
+	err = rlp.DecodeBytes(serializedVCP, vcp)
+	if err != nil {
+		return nil, nil, err
+	}
+	valSet := consensus.SelectTopStakeHoldersAsValidators(vcp)
+
+	signingKeys := []SigningKeyEntry{}
+	if height < common.HeightEnableValidatorKeyRotation {
+		return valSet, signingKeys, nil
+	}
+	for _, validator := range valSet.Validators() {
+		key, err := state.GetValidatorSigningKeyFromProof(stateHash, validator.ID(), vcpProof)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve the signing key of validator %v: %v", validator.ID().Hex(), err)
+		}
+		if key != nil {
+			signingKeys = append(signingKeys, SigningKeyEntry{Holder: validator.ID(), Key: *key})
+		}
+	}
+	return valSet, signingKeys, nil
+}
+
+// ValidatorBLSPubkeysFromProof retrieves the registered BLS pubkeys of the validators from the Merkle
+// proof against the given state root, in the order of the validator set. A validator without a
+// registered BLS pubkey has a nil entry.
+func ValidatorBLSPubkeysFromProof(stateHash common.Hash, valSet *core.ValidatorSet, vcpProof *core.VCPProof) ([]*bls.PublicKey, error) {
+	pubkeys := []*bls.PublicKey{}
+	for _, validator := range valSet.Validators() {
+		pubkey, err := state.GetValidatorBLSPubkeyFromProof(stateHash, validator.ID(), vcpProof)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve the BLS pubkey of validator %v: %v", validator.ID().Hex(), err)
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return pubkeys, nil
+}
+
+// HasValidatorUpdate returns whether the transactions of a block update the validator set, by the
+// same rule as the ledger
+func HasValidatorUpdate(txs []common.Bytes) (bool, error) {
+	hasValidatorUpdate, _, err := parseValidatorUpdates(txs)
+	return hasValidatorUpdate, err
+}
+
+// parseValidatorUpdates returns whether the transactions of a block update the validator set, along
+// with the signing key rotations of the block
+func parseValidatorUpdates(txs []common.Bytes) (bool, []*types.RotateValidatorKeyTx, error) {
+	hasValidatorUpdate := false
+	rotations := []*types.RotateValidatorKeyTx{}
+	for _, rawTx := range txs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to parse transaction: %v", err)
+		}
+		if types.IsValidatorUpdateTx(tx) {
+			hasValidatorUpdate = true
+		}
+		if rotation, ok := tx.(*types.RotateValidatorKeyTx); ok {
+			rotations = append(rotations, rotation)
+		}
+	}
+	return hasValidatorUpdate, rotations, nil
+}
+
+// VerifyStateProof verifies the Merkle proof of the key against the given state root, and
+// returns the proven value. A nil value means the key does not exist in the state.
+func VerifyStateProof(stateHash common.Hash, key common.Bytes, proof *core.VCPProof) (common.Bytes, error) {
+	value, _, err := trie.VerifyProof(stateHash, key, proof)
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
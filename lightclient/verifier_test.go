@@ -0,0 +1,291 @@
+package lightclient
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/crypto/bls"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store/database/backend"
+)
+
+func TestVerifierCertifiesHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	validator := core.Validator{Address: privKey.PublicKey().Address(), Stake: big.NewInt(1000)}
+
+	trusted := newTestHeader(10, common.Hash{})
+	v := NewVerifier(trusted, []ValidatorSetEntry{{FromHeight: 10, Validators: []core.Validator{validator}}}, nil)
+
+	h11 := newTestHeader(11, trusted.Hash())
+	assert.Nil(v.AddHeader(h11, nil, nil))
+	assert.Equal(uint64(10), v.LatestCertifiedHeader().Height)
+
+	// Not linked to the last header
+	assert.NotNil(v.AddHeader(newTestHeader(12, trusted.Hash()), nil, nil))
+
+	// Commit certificate signed by an unknown validator
+	otherKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	h12 := newTestHeader(12, h11.Hash())
+	h12.HCC = newTestCC(h11, otherKey)
+	assert.NotNil(v.AddHeader(h12, nil, nil))
+
+	h12.HCC = newTestCC(h11, privKey)
+	assert.Nil(v.AddHeader(h12, nil, nil))
+	assert.Equal(h11.Hash(), v.LatestCertifiedHeader().Hash())
+	certified, ok := v.CertifiedHeader(11)
+	assert.True(ok)
+	assert.Equal(h11.Hash(), certified.Hash())
+	_, ok = v.CertifiedHeader(12)
+	assert.False(ok)
+
+	checkpoint := v.Checkpoint()
+	assert.Equal(h11.Hash(), checkpoint.Header.Hash())
+	assert.Equal(1, len(checkpoint.ValidatorSets))
+
+	// The transactions must match the tx hash
+	h13 := newTestHeader(13, h12.Hash())
+	assert.NotNil(v.AddHeader(h13, []common.Bytes{common.Bytes("tx")}, nil))
+}
+
+func TestVerifierValidatorUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	keyA, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	keyB, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	validatorA := core.Validator{Address: keyA.PublicKey().Address(), Stake: core.MinValidatorStakeDeposit}
+
+	newVerifier := func() (*Verifier, *core.BlockHeader) {
+		trusted := newTestHeader(10, common.Hash{})
+		return NewVerifier(trusted, []ValidatorSetEntry{{FromHeight: 10, Validators: []core.Validator{validatorA}}}, nil), trusted
+	}
+
+	// Block 11 replaces validator A with validator B
+	depositTx, err := types.TxToBytes(&types.DepositStakeTx{
+		Fee:     types.NewCoins(0, 0),
+		Source:  types.NewTxInput(keyB.PublicKey().Address(), types.NewCoins(0, 0), 1),
+		Holder:  types.TxOutput{Address: keyB.PublicKey().Address()},
+		Purpose: core.StakeForValidator,
+	})
+	assert.Nil(err)
+	txs := []common.Bytes{depositTx}
+	vcp := &core.ValidatorCandidatePool{SortedCandidates: []*core.StakeHolder{
+		core.NewStakeHolder(keyB.PublicKey().Address(), []*core.Stake{core.NewStake(keyB.PublicKey().Address(), core.MinValidatorStakeDeposit)}),
+	}}
+	sv := state.NewStoreView(11, common.Hash{}, backend.NewMemDatabase())
+	sv.UpdateValidatorCandidatePool(vcp)
+	stateHash := sv.Save()
+	vcpProof := &core.VCPProof{}
+	assert.Nil(sv.Prove(state.ValidatorCandidatePoolKey(), vcpProof))
+	assert.Nil(sv.ProveValidatorBLSPubkeys([]common.Address{keyB.PublicKey().Address()}, vcpProof))
+
+	v, trusted := newVerifier()
+	h11 := newTestHeader(11, trusted.Hash())
+	h11.TxHash = core.CalculateRootHash(txs)
+	h11.StateHash = stateHash
+	assert.NotNil(v.AddHeader(h11, txs, nil)) // the VCP proof is required
+	assert.Nil(v.AddHeader(h11, txs, vcpProof))
+
+	// The new validator set is not adopted before block 11 is certified by validator A
+	h12 := newTestHeader(12, h11.Hash())
+	h12.HCC = newTestCC(h11, keyB)
+	assert.NotNil(v.AddHeader(h12, nil, nil))
+	h12.HCC = newTestCC(h11, keyA)
+	assert.Nil(v.AddHeader(h12, nil, nil))
+
+	// The child of the validator update block is still certified by the previous validator set
+	h13 := newTestHeader(13, h12.Hash())
+	h13.HCC = newTestCC(h12, keyA)
+	assert.Nil(v.AddHeader(h13, nil, nil))
+
+	h14 := newTestHeader(14, h13.Hash())
+	h14.HCC = newTestCC(h13, keyA)
+	assert.NotNil(v.AddHeader(h14, nil, nil))
+	h14.HCC = newTestCC(h13, keyB)
+	assert.Nil(v.AddHeader(h14, nil, nil))
+	assert.Equal(h13.Hash(), v.LatestCertifiedHeader().Hash())
+	assert.Equal(2, len(v.Checkpoint().ValidatorSets))
+
+	// A commit certificate of the previous validator set skipping over the validator update is rejected
+	v, trusted = newVerifier()
+	assert.Nil(v.AddHeader(h11, txs, vcpProof))
+	h12 = newTestHeader(12, h11.Hash())
+	assert.Nil(v.AddHeader(h12, nil, nil))
+	h13 = newTestHeader(13, h12.Hash())
+	assert.Nil(v.AddHeader(h13, nil, nil))
+	h14 = newTestHeader(14, h13.Hash())
+	h14.HCC = newTestCC(h13, keyA)
+	assert.NotNil(v.AddHeader(h14, nil, nil))
+	assert.Equal(trusted.Hash(), v.LatestCertifiedHeader().Hash())
+}
+
+func TestVerifierValidatorKeyRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	newKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	validator := core.Validator{Address: privKey.PublicKey().Address(), Stake: core.MinValidatorStakeDeposit}
+
+	trusted := newTestHeader(10, common.Hash{})
+	v := NewVerifier(trusted, []ValidatorSetEntry{{FromHeight: 10, Validators: []core.Validator{validator}}}, nil)
+
+	// Block 11 rotates the signing key of the validator from height 13
+	rotateTx, err := types.TxToBytes(&types.RotateValidatorKeyTx{
+		Fee:              types.NewCoins(0, 0),
+		Holder:           types.NewTxInput(validator.Address, types.NewCoins(0, 0), 1),
+		SigningAddress:   newKey.PublicKey().Address(),
+		ActivationHeight: 13,
+	})
+	assert.Nil(err)
+	txs := []common.Bytes{rotateTx}
+	h11 := newTestHeader(11, trusted.Hash())
+	h11.TxHash = core.CalculateRootHash(txs)
+	assert.Nil(v.AddHeader(h11, txs, nil)) // a key rotation alone does not require a VCP proof
+
+	h12 := newTestHeader(12, h11.Hash())
+	h12.HCC = newTestCC(h11, privKey)
+	assert.Nil(v.AddHeader(h12, nil, nil))
+
+	// Block 12 is still certified by the previous key
+	h13 := newTestHeader(13, h12.Hash())
+	h13.HCC = newTestCC(h12, newKey)
+	assert.NotNil(v.AddHeader(h13, nil, nil))
+	h13.HCC = newTestCC(h12, privKey)
+	assert.Nil(v.AddHeader(h13, nil, nil))
+
+	// The new key certifies the blocks from the activation height
+	h14 := newTestHeader(14, h13.Hash())
+	h14.HCC = newTestCC(h13, privKey)
+	assert.NotNil(v.AddHeader(h14, nil, nil))
+	h14.HCC = newTestCC(h13, newKey)
+	assert.Nil(v.AddHeader(h14, nil, nil))
+	assert.Equal(h13.Hash(), v.LatestCertifiedHeader().Hash())
+
+	// The checkpoint carries the rotated key
+	checkpoint := v.Checkpoint()
+	assert.Equal(1, len(checkpoint.SigningKeys))
+	assert.Equal(validator.Address, checkpoint.SigningKeys[0].Holder)
+	assert.Equal(newKey.PublicKey().Address(), checkpoint.SigningKeys[0].Key.SigningAddress(14))
+}
+
+func TestVerifierAggregatedValidatorVotes(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	blsKey, err := bls.RandKey()
+	assert.Nil(err)
+	otherBlsKey, err := bls.RandKey()
+	assert.Nil(err)
+	validator := core.Validator{Address: privKey.PublicKey().Address(), Stake: core.MinValidatorStakeDeposit}
+
+	trusted := newTestHeader(10, common.Hash{})
+	v := NewVerifier(trusted, []ValidatorSetEntry{{
+		FromHeight: 10,
+		Validators: []core.Validator{validator},
+		BLSPubkeys: []*bls.PublicKey{blsKey.PublicKey()},
+	}}, nil)
+
+	h11 := newTestHeader(11, trusted.Hash())
+	assert.Nil(v.AddHeader(h11, nil, nil))
+
+	// After the fork, the votes are moved out of the commit certificate into the aggregated votes
+	h12 := newTestHeader(12, h11.Hash())
+	h12.HCC = core.CommitCertificate{BlockHash: h11.Hash(), Votes: core.NewVoteSet()}
+	h12.ValidatorVotes = newTestValidatorVotes(h11, validator, privKey, blsKey.PublicKey(), blsKey)
+	assert.NotNil(v.AddHeader(h12, nil, nil)) // before the fork height
+
+	defer func(height uint64) { heightEnableValidatorVoteAggregation = height }(heightEnableValidatorVoteAggregation)
+	heightEnableValidatorVoteAggregation = 12
+
+	// Signed by a BLS key other than the registered one
+	h12.ValidatorVotes = newTestValidatorVotes(h11, validator, privKey, otherBlsKey.PublicKey(), otherBlsKey)
+	assert.NotNil(v.AddHeader(h12, nil, nil))
+	assert.Equal(trusted.Hash(), v.LatestCertifiedHeader().Hash())
+
+	h12.ValidatorVotes = newTestValidatorVotes(h11, validator, privKey, blsKey.PublicKey(), blsKey)
+	assert.Nil(v.AddHeader(h12, nil, nil))
+	assert.Equal(h11.Hash(), v.LatestCertifiedHeader().Hash())
+
+	// The aggregated votes need to be on the HCC block
+	h13 := newTestHeader(13, h12.Hash())
+	h13.HCC = core.CommitCertificate{BlockHash: h12.Hash(), Votes: core.NewVoteSet()}
+	h13.ValidatorVotes = newTestValidatorVotes(h11, validator, privKey, blsKey.PublicKey(), blsKey)
+	assert.NotNil(v.AddHeader(h13, nil, nil))
+	h13.ValidatorVotes = newTestValidatorVotes(h12, validator, privKey, blsKey.PublicKey(), blsKey)
+	assert.Nil(v.AddHeader(h13, nil, nil))
+	assert.Equal(h12.Hash(), v.LatestCertifiedHeader().Hash())
+}
+
+func TestVerifyStateProof(t *testing.T) {
+	assert := assert.New(t)
+
+	sv := state.NewStoreView(0, common.Hash{}, backend.NewMemDatabase())
+	key := common.Bytes("ls/a/test")
+	sv.Set(key, common.Bytes("value"))
+	sv.Set(common.Bytes("ls/a/other"), common.Bytes("other value"))
+	stateHash := sv.Save()
+
+	proof := &core.VCPProof{}
+	assert.Nil(sv.Prove(key, proof))
+	value, err := VerifyStateProof(stateHash, key, proof)
+	assert.Nil(err)
+	assert.Equal(common.Bytes("value"), value)
+
+	// Proof against a different state root
+	_, err = VerifyStateProof(common.HexToHash("0x01"), key, proof)
+	assert.NotNil(err)
+}
+
+func newTestHeader(height uint64, parent common.Hash) *core.BlockHeader {
+	return &core.BlockHeader{
+		ChainID:   "testchain",
+		Height:    height,
+		Epoch:     height,
+		Parent:    parent,
+		Timestamp: big.NewInt(int64(height)),
+		TxHash:    core.CalculateRootHash(nil),
+	}
+}
+
+func newTestValidatorVotes(header *core.BlockHeader, validator core.Validator, privKey *crypto.PrivateKey,
+	pubkey *bls.PublicKey, blsKey *bls.SecretKey) *core.AggregatedValidatorVotes {
+	vote := core.Vote{
+		Block:  header.Hash(),
+		Height: header.Height,
+		Epoch:  header.Epoch,
+		ID:     privKey.PublicKey().Address(),
+	}
+	vote.Sign(privKey)
+	vote.SignBls(blsKey)
+	votes := core.NewVoteSet()
+	votes.AddVote(vote)
+	valSet := core.NewValidatorSet()
+	valSet.AddValidator(validator)
+	return core.NewAggregatedValidatorVotes(header.Hash(), valSet, []*bls.PublicKey{pubkey}, votes)
+}
+
+func newTestCC(header *core.BlockHeader, privKey *crypto.PrivateKey) core.CommitCertificate {
+	vote := core.Vote{
+		Block:  header.Hash(),
+		Height: header.Height,
+		Epoch:  header.Epoch,
+		ID:     privKey.PublicKey().Address(),
+	}
+	vote.Sign(privKey)
+	votes := core.NewVoteSet()
+	votes.AddVote(vote)
+	return core.CommitCertificate{BlockHash: header.Hash(), Votes: votes}
+}
@@ -0,0 +1,117 @@
+package mempool
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// journalRestoreCheckInterval is the interval to check whether the node has synced before restoring the journal
+const journalRestoreCheckInterval = time.Second
+
+// SaveJournal writes the candidate transactions to the journal file, so that they are not lost
+// when the node restarts. The transactions of a previous journal that have not been restored yet
+// are kept in the file as well.
+func (mp *Mempool) SaveJournal(filePath string) error {
+	mp.mutex.Lock()
+	rawTxs := []common.Bytes{}
+	seen := make(map[string]bool)
+	txgElemList := mp.candidateTxs.ElementList()
+	for _, txgElem := range *txgElemList {
+		txg := txgElem.(*mempoolTransactionGroup)
+		txElemList := txg.txs.ElementList()
+		for _, txElem := range *txElemList {
+			rawTx := txElem.(*mempoolTransaction).rawTransaction
+			seen[string(rawTx)] = true
+			rawTxs = append(rawTxs, rawTx)
+		}
+	}
+	for _, rawTx := range mp.journalTxs {
+		if !seen[string(rawTx)] {
+			seen[string(rawTx)] = true
+			rawTxs = append(rawTxs, rawTx)
+		}
+	}
+	mp.mutex.Unlock()
+
+	raw, err := rlp.EncodeToBytes(rawTxs)
+	if err != nil {
+		return err
+	}
+	if err := common.WriteFileAtomic(filePath, raw, 0600); err != nil {
+		return err
+	}
+	logger.Infof("Saved %v transactions to the mempool journal %v", len(rawTxs), filePath)
+	return nil
+}
+
+// RestoreJournal re-inserts the transactions of the journal file once the node has synced, and then
+// removes the file. The transactions committed or invalidated in the meantime are dropped by the
+// screening. It needs to be called after the Mempool starts.
+func (mp *Mempool) RestoreJournal(filePath string) error {
+	rawTxs, err := readJournal(filePath)
+	if err != nil {
+		return err
+	}
+	if len(rawTxs) == 0 {
+		return nil
+	}
+
+	mp.mutex.Lock()
+	mp.journalTxs = rawTxs
+	mp.mutex.Unlock()
+
+	mp.wg.Add(1)
+	go mp.restoreJournal(filePath, rawTxs)
+	return nil
+}
+
+func readJournal(filePath string) ([]common.Bytes, error) {
+	raw, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rawTxs := []common.Bytes{}
+	if err := rlp.DecodeBytes(raw, &rawTxs); err != nil {
+		return nil, err
+	}
+	return rawTxs, nil
+}
+
+func (mp *Mempool) restoreJournal(filePath string, rawTxs []common.Bytes) {
+	defer mp.wg.Done()
+
+	ticker := time.NewTicker(journalRestoreCheckInterval)
+	defer ticker.Stop()
+	// If the node stops before it has synced, the transactions stay in mp.journalTxs so that
+	// SaveJournal writes them back to the journal
+	for !mp.consensus.HasSynced() {
+		select {
+		case <-mp.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+
+	restored := 0
+	for _, rawTx := range rawTxs {
+		if err := mp.InsertTransaction(rawTx); err == nil {
+			restored++
+		}
+	}
+
+	mp.mutex.Lock()
+	mp.journalTxs = nil
+	mp.mutex.Unlock()
+
+	if err := os.Remove(filePath); err != nil {
+		logger.Warnf("Failed to remove the mempool journal %v: %v", filePath, err)
+	}
+	logger.Infof("Restored %v of %v transactions from the mempool journal %v", restored, len(rawTxs), filePath)
+}
@@ -0,0 +1,93 @@
+package mempool
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	"github.com/thetatoken/theta/core"
+)
+
+func TestMempoolJournalRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mempool_journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	journalPath := path.Join(dir, "mempool_journal")
+
+	mempool := newJournalTestMempool()
+	addCandidateTx(mempool, common.Bytes("tx1"), "A1")
+	addCandidateTx(mempool, common.Bytes("tx2"), "A1")
+	addCandidateTx(mempool, common.Bytes("tx3"), "B1")
+	assert.Nil(mempool.SaveJournal(journalPath))
+
+	rawTxs, err := readJournal(journalPath)
+	assert.Nil(err)
+	assert.ElementsMatch([]common.Bytes{common.Bytes("tx1"), common.Bytes("tx2"), common.Bytes("tx3")}, rawTxs)
+
+	// No journal file, nothing to restore
+	assert.Nil(os.Remove(journalPath))
+	assert.Nil(newJournalTestMempool().RestoreJournal(journalPath))
+}
+
+func TestMempoolJournalEarlyShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mempool_journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	journalPath := path.Join(dir, "mempool_journal")
+
+	mempool := newJournalTestMempool()
+	addCandidateTx(mempool, common.Bytes("tx1"), "A1")
+	addCandidateTx(mempool, common.Bytes("tx2"), "B1")
+	assert.Nil(mempool.SaveJournal(journalPath))
+
+	// The node stops before it has synced, the journal is not restored
+	restarted := newJournalTestMempool()
+	assert.Nil(restarted.RestoreJournal(journalPath))
+	addCandidateTx(restarted, common.Bytes("tx2"), "B1")
+	addCandidateTx(restarted, common.Bytes("tx3"), "C1")
+	restarted.Stop()
+	restarted.Wait()
+	assert.Equal(2, len(restarted.journalTxs))
+
+	// Saving the journal on shutdown keeps the transactions not restored yet
+	assert.Nil(restarted.SaveJournal(journalPath))
+	rawTxs, err := readJournal(journalPath)
+	assert.Nil(err)
+	assert.ElementsMatch([]common.Bytes{common.Bytes("tx1"), common.Bytes("tx2"), common.Bytes("tx3")}, rawTxs)
+}
+
+// --------------- Test Utilities --------------- //
+
+func newJournalTestMempool() *Mempool {
+	// The zero value engine has not synced, so the journal restoration waits
+	mempool := CreateMempool(nil, &consensus.ConsensusEngine{})
+	mempool.Start(context.Background())
+	return mempool
+}
+
+func addCandidateTx(mempool *Mempool, rawTx common.Bytes, address string) {
+	txInfo := &core.TxInfo{
+		EffectiveGasPrice: big.NewInt(1),
+		Address:           common.HexToAddress(address),
+	}
+	txGroup, ok := mempool.addressToTxGroup[txInfo.Address]
+	if ok {
+		txGroup.AddTx(rawTx, txInfo)
+		mempool.candidateTxs.Remove(txGroup.index)
+	} else {
+		txGroup = createMempoolTransactionGroup(rawTx, txInfo)
+		mempool.addressToTxGroup[txInfo.Address] = txGroup
+	}
+	mempool.candidateTxs.Push(txGroup)
+	mempool.size++
+}
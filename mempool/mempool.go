@@ -3,12 +3,13 @@ package mempool
 import (
 	"context"
 	"encoding/hex"
-	"errors"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/clist"
@@ -124,6 +125,12 @@ func (mtg *mempoolTransactionGroup) RemoveTxs(committedRawTxMap map[string]bool)
 	return
 }
 
+// txSequence identifies the transactions that spend the same sequence of an account
+type txSequence struct {
+	address  common.Address
+	sequence uint64
+}
+
 func createMempoolTransactionGroup(rawTx common.Bytes, txInfo *core.TxInfo) *mempoolTransactionGroup {
 	txGroup := &mempoolTransactionGroup{
 		address: txInfo.Address,
@@ -149,6 +156,7 @@ type Mempool struct {
 	txBookeepper     transactionBookkeeper
 	addressToTxGroup map[common.Address]*mempoolTransactionGroup
 	size             int
+	journalTxs       []common.Bytes // transactions of the journal not restored yet
 
 	// Life cycle
 	wg      *sync.WaitGroup
@@ -160,6 +168,9 @@ type Mempool struct {
 
 // CreateMempool creates an instance of Mempool
 func CreateMempool(dispatcher *dp.Dispatcher, engine *consensus.ConsensusEngine) *Mempool {
+	txBookkeeper := createTransactionBookkeeper(defaultMaxNumTxs)
+	txBookkeeper.setRetention(time.Duration(viper.GetInt64(common.CfgMempoolTxStatusRetentionSecs)) * time.Second)
+
 	return &Mempool{
 		mutex:            &sync.Mutex{},
 		consensus:        engine,
@@ -167,7 +178,7 @@ func CreateMempool(dispatcher *dp.Dispatcher, engine *consensus.ConsensusEngine)
 		newTxs:           clist.New(),
 		candidateTxs:     pqueue.CreatePriorityQueue(),
 		addressToTxGroup: make(map[common.Address]*mempoolTransactionGroup),
-		txBookeepper:     createTransactionBookkeeper(defaultMaxNumTxs),
+		txBookeepper:     txBookkeeper,
 		wg:               &sync.WaitGroup{},
 	}
 }
@@ -201,7 +212,7 @@ func (mp *Mempool) InsertTransaction(rawTx common.Bytes) error {
 		txInfo, checkTxRes = mp.ledger.ScreenTx(rawTx)
 		if !checkTxRes.IsOK() {
 			logger.Debugf("Transaction screening failed, tx: %v, error: %v", hex.EncodeToString(rawTx), checkTxRes.Message)
-			return errors.New(checkTxRes.Message)
+			return checkTxRes.Err()
 		}
 
 		// only record the transactions that passed the screening. This is because that
@@ -298,9 +309,9 @@ func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
 
 		// Check for outdated txs
 		txHash := getTransactionHash(rawTx)
-		_, exists := mp.txBookeepper.getStatus(txHash)
-		if exists {
-			// Only add back Txs that has not been removed from bookkeeper due to timeout
+		status, exists := mp.txBookeepper.getStatus(txHash)
+		if exists && status == TxStatusPending {
+			// Only add back Txs that has not expired or been removed from bookkeeper
 			txs = append(txs, rawTx)
 		}
 
@@ -319,20 +330,51 @@ func (mp *Mempool) ReapUnsafe(maxNumTxs int) []common.Bytes {
 	return txs
 }
 
-// Update removes the committed transactions from the transaction candidate list
+// ReinsertUnsafe puts a reaped transaction back to the transaction candidate list, so it can be
+// included in a later block. Caller must call Mempool.Lock() before calling this method.
+func (mp *Mempool) ReinsertUnsafe(rawTx common.Bytes, txInfo *core.TxInfo) {
+	txGroup, ok := mp.addressToTxGroup[txInfo.Address]
+	if ok {
+		txGroup.AddTx(rawTx, txInfo)
+		mp.candidateTxs.Remove(txGroup.index) // Need to re-insert txGroup into queue since its priority could change.
+	} else {
+		txGroup = createMempoolTransactionGroup(rawTx, txInfo)
+		mp.addressToTxGroup[txInfo.Address] = txGroup
+	}
+	mp.candidateTxs.Push(txGroup)
+	mp.size++
+}
+
+// AbandonUnsafe marks a reaped transaction which cannot be put back to the transaction candidate
+// list as abandoned. Caller must call Mempool.Lock() before calling this method.
+func (mp *Mempool) AbandonUnsafe(rawTx common.Bytes, reason string) {
+	mp.txBookeepper.markAbandoned(rawTx, reason)
+}
+
+// Update removes the committed transactions of the block at the given height from the transaction
+// candidate list. The tx infos of the committed transactions, nil if unknown, are used to tell the
+// candidate transactions replaced by a committed transaction of the same sequence.
 // RUNTIME COMPLEXITY: O(k + n), where k is the number committed raw transactions,
 // and n is the number of transactions in the candidate pool.
-func (mp *Mempool) Update(committedRawTxs []common.Bytes) {
+func (mp *Mempool) Update(height uint64, committedRawTxs []common.Bytes, committedTxInfos []*core.TxInfo) {
 	mp.mutex.Lock()
 	defer mp.mutex.Unlock()
-	mp.UpdateUnsafe(committedRawTxs)
+	mp.UpdateUnsafe(height, committedRawTxs, committedTxInfos)
 }
 
 // UpdateUnsafe is the non-locking version of Update. Caller must call Mempool.Lock() before
 // calling this method.
-func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
+func (mp *Mempool) UpdateUnsafe(height uint64, committedRawTxs []common.Bytes, committedTxInfos []*core.TxInfo) {
 	start := time.Now()
 	mp.removeTxs(committedRawTxs)
+	committedSequences := make(map[txSequence]string)
+	for i, rawTx := range committedRawTxs {
+		mp.txBookeepper.markIncluded(rawTx, height)
+		if i < len(committedTxInfos) && committedTxInfos[i] != nil {
+			seq := txSequence{committedTxInfos[i].Address, committedTxInfos[i].Sequence}
+			committedSequences[seq] = getTransactionHash(rawTx)
+		}
+	}
 	removeCommittedTxTime := time.Since(start)
 
 	// Remove Txs that have become obsolete.
@@ -350,9 +392,9 @@ func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
 
 			// Check for outdated txs
 			txHash := getTransactionHash(mempoolTx.rawTransaction)
-			_, exists := mp.txBookeepper.getStatus(txHash)
-			if !exists {
-				// Tx has been removed from bookkeeper due to timeout
+			status, exists := mp.txBookeepper.getStatus(txHash)
+			if !exists || status == TxStatusExpired {
+				// Tx has expired or been removed from bookkeeper
 				invalidTxs = append(invalidTxs, mempoolTx.rawTransaction)
 				continue
 			}
@@ -360,7 +402,12 @@ func (mp *Mempool) UpdateUnsafe(committedRawTxs []common.Bytes) {
 			checkTxRes := mp.ledger.ScreenTxUnsafe(mempoolTx.rawTransaction)
 			if !checkTxRes.IsOK() {
 				invalidTxs = append(invalidTxs, mempoolTx.rawTransaction)
-				mp.txBookeepper.markAbandoned(mempoolTx.rawTransaction)
+				seq := txSequence{mempoolTx.txInfo.Address, mempoolTx.txInfo.Sequence}
+				if replacedBy, ok := committedSequences[seq]; ok {
+					mp.txBookeepper.markReplaced(mempoolTx.rawTransaction, replacedBy)
+				} else {
+					mp.txBookeepper.markAbandoned(mempoolTx.rawTransaction, checkTxRes.Message)
+				}
 			}
 		}
 	}
@@ -399,7 +446,19 @@ func (mp *Mempool) removeTxs(committedRawTxs []common.Bytes) {
 }
 
 func (mp *Mempool) GetTransactionStatus(hash string) (TxStatus, bool) {
-	return mp.txBookeepper.getStatus(hash)
+	return mp.txBookeepper.getStatus(normalizeTxHash(hash))
+}
+
+// GetTransactionRecord returns the latest record of the transaction seen within the retention window
+func (mp *Mempool) GetTransactionRecord(hash string) (TxRecord, bool) {
+	return mp.txBookeepper.getRecord(normalizeTxHash(hash))
+}
+
+// normalizeTxHash converts the hash into the lower case hex string without the 0x prefix the
+// bookkeeper uses
+func normalizeTxHash(hash string) string {
+	hash = strings.ToLower(hash)
+	return strings.TrimPrefix(hash, "0x")
 }
 
 // GetCandidateTransactions returns all the currently candidate transactions
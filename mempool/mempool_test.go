@@ -13,6 +13,7 @@ import (
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto/bls"
 	dp "github.com/thetatoken/theta/dispatcher"
 	p2psim "github.com/thetatoken/theta/p2p/simulation"
 	p2ptypes "github.com/thetatoken/theta/p2p/types"
@@ -238,7 +239,7 @@ func TestMempoolUpdate(t *testing.T) {
 		common.Bytes("tx4"), // intentionally repeated tx
 	}
 
-	mempool.Update(committedRawTxs)
+	mempool.Update(1, committedRawTxs, nil)
 	assert.Equal(5, mempool.Size())
 
 	log.Infof("----- Reap all remaining transactions -----")
@@ -278,7 +279,7 @@ func TestMempoolUpdateAndInsert(t *testing.T) {
 		common.Bytes("tx1"),
 	}
 
-	mempool.Update(committedRawTxs)
+	mempool.Update(1, committedRawTxs, nil)
 	assert.Equal(2, mempool.Size())
 
 	// tx4 and tx1 are from the same address.
@@ -314,7 +315,7 @@ func TestMempoolBigBatchUpdateAndReaping(t *testing.T) {
 
 	t1 := time.Now()
 
-	mempool.Update(committedRawTxs)
+	mempool.Update(1, committedRawTxs, nil)
 
 	t2 := time.Now()
 	elapsedA := t2.Sub(t1)
@@ -494,6 +495,14 @@ func (tl *TestLedger) GetFinalizedValidatorCandidatePool(blockHash common.Hash,
 	return nil, nil
 }
 
+func (tl *TestLedger) GetFinalizedValidatorBLSPubkeys(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]*bls.PublicKey, error) {
+	return nil, nil
+}
+
+func (tl *TestLedger) GetFinalizedValidatorSigningAddresses(blockHash common.Hash, isNext bool, validators *core.ValidatorSet) ([]common.Address, error) {
+	return nil, nil
+}
+
 func (tl *TestLedger) GetGuardianCandidatePool(blockHash common.Hash) (*core.GuardianCandidatePool, error) {
 	return nil, nil
 }
@@ -15,32 +15,49 @@ const defaultMaxNumTxs = uint(200000)
 const maxTxLife = 1 * time.Minute
 
 //
-// transactionBookkeeper keeps tracks of recently seen transactions
+// transactionBookkeeper keeps tracks of recently seen transactions, and their status for the
+// retention window after they are first seen
 //
 type transactionBookkeeper struct {
 	mutex *sync.Mutex
 
-	txMap  map[string]*TxRecord // map: transaction hash -> bool
-	txList list.List            // FIFO list of transaction hashes
+	txMap  map[string]*TxRecord // map: transaction hash -> the latest record of the transaction
+	txList list.List            // FIFO list of transaction records
 
 	maxNumTxs uint
+	retention time.Duration // how long the records are kept, no shorter than maxTxLife
 }
 
 type TxRecord struct {
-	Hash      string
-	Status    TxStatus
-	CreatedAt time.Time
+	Hash        string
+	Status      TxStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time // when the status last changed
+	BlockHeight uint64    // the height of the block including the tx, for TxStatusIncluded
+	ReplacedBy  string    // the hash of the tx included instead, for TxStatusReplaced
+	Reason      string    // why the tx failed the screening, for TxStatusAbandoned
 }
 
 func (r *TxRecord) IsOutdated() bool {
 	return time.Since(r.CreatedAt) > maxTxLife
 }
 
+// isSeen returns whether a re-submission of the tx should be rejected as a duplicate
+func (r *TxRecord) isSeen() bool {
+	if r.Status == TxStatusIncluded || r.Status == TxStatusReplaced {
+		return true
+	}
+	return !r.IsOutdated()
+}
+
 type TxStatus int
 
 const (
-	TxStatusPending TxStatus = iota
-	TxStatusAbandoned
+	TxStatusPending   TxStatus = iota
+	TxStatusAbandoned          // evicted from the mempool as it failed the screening after a block commit
+	TxStatusIncluded           // included in a committed block
+	TxStatusReplaced           // another tx of the account with the same sequence is included instead
+	TxStatusExpired            // not included within maxTxLife
 )
 
 func createTransactionBookkeeper(maxNumTxs uint) transactionBookkeeper {
@@ -48,7 +65,18 @@ func createTransactionBookkeeper(maxNumTxs uint) transactionBookkeeper {
 		mutex:     &sync.Mutex{},
 		txMap:     make(map[string]*TxRecord),
 		maxNumTxs: maxNumTxs,
+		retention: maxTxLife,
+	}
+}
+
+// setRetention sets how long the records are kept after the txs are first seen
+func (tb *transactionBookkeeper) setRetention(retention time.Duration) {
+	tb.mutex.Lock()
+	defer tb.mutex.Unlock()
+	if retention < maxTxLife {
+		retention = maxTxLife
 	}
+	tb.retention = retention
 }
 
 func (tb *transactionBookkeeper) reset() {
@@ -66,12 +94,21 @@ func (tb *transactionBookkeeper) hasSeen(rawTx common.Bytes) bool {
 	tb.removeOutdatedTxsUnsafe()
 
 	txhash := getTransactionHash(rawTx)
-	_, exists := tb.txMap[txhash]
-	return exists
+	txRecord, exists := tb.txMap[txhash]
+	return exists && txRecord.isSeen()
 }
 
 // getStatus returns a tx status and a boolean of whether the tx is known.
 func (tb *transactionBookkeeper) getStatus(txhash string) (TxStatus, bool) {
+	txRecord, exists := tb.getRecord(txhash)
+	if !exists {
+		return TxStatusAbandoned, false
+	}
+	return txRecord.Status, true
+}
+
+// getRecord returns a copy of the latest record of the tx, and a boolean of whether the tx is known.
+func (tb *transactionBookkeeper) getRecord(txhash string) (TxRecord, bool) {
 	tb.mutex.Lock()
 	defer tb.mutex.Unlock()
 
@@ -79,30 +116,39 @@ func (tb *transactionBookkeeper) getStatus(txhash string) (TxStatus, bool) {
 	tb.removeOutdatedTxsUnsafe()
 
 	txRecord, exists := tb.txMap[txhash]
-
 	if !exists {
-		return TxStatusAbandoned, false
+		return TxRecord{}, false
 	}
-	return txRecord.Status, true
+	if txRecord.Status == TxStatusPending && txRecord.IsOutdated() {
+		txRecord.Status = TxStatusExpired
+		txRecord.UpdatedAt = txRecord.CreatedAt.Add(maxTxLife)
+	}
+	return *txRecord, true
 }
 
 func (tb *transactionBookkeeper) removeOutdatedTxsUnsafe() {
-	// Loop and remove all outdated Tx records
+	// Loop and remove all Tx records past the retention window
 	for {
 		el := tb.txList.Front()
 		if el == nil {
 			return
 		}
 		txRecord := el.Value.(*TxRecord)
-		if !txRecord.IsOutdated() {
+		if time.Since(txRecord.CreatedAt) <= tb.retention {
 			return
 		}
+		tb.removeElementUnsafe(el)
+	}
+}
 
-		if _, exists := tb.txMap[txRecord.Hash]; exists {
-			delete(tb.txMap, txRecord.Hash)
-		}
-		tb.txList.Remove(el)
+// removeElementUnsafe removes the record from the list, and from the map unless the tx has been
+// recorded again since
+func (tb *transactionBookkeeper) removeElementUnsafe(el *list.Element) {
+	txRecord := el.Value.(*TxRecord)
+	if tb.txMap[txRecord.Hash] == txRecord {
+		delete(tb.txMap, txRecord.Hash)
 	}
+	tb.txList.Remove(el)
 }
 
 func (tb *transactionBookkeeper) record(rawTx common.Bytes) bool {
@@ -113,21 +159,20 @@ func (tb *transactionBookkeeper) record(rawTx common.Bytes) bool {
 	// Remove outdated Tx records
 	tb.removeOutdatedTxsUnsafe()
 
-	if _, exists := tb.txMap[txhash]; exists {
+	if txRecord, exists := tb.txMap[txhash]; exists && txRecord.isSeen() {
 		return false
 	}
 
 	if uint(tb.txList.Len()) >= tb.maxNumTxs { // remove the oldest transactions
-		popped := tb.txList.Front()
-		poppedTxhash := popped.Value.(*TxRecord).Hash
-		delete(tb.txMap, poppedTxhash)
-		tb.txList.Remove(popped)
+		tb.removeElementUnsafe(tb.txList.Front())
 	}
 
+	now := time.Now()
 	record := &TxRecord{
 		Hash:      txhash,
 		Status:    TxStatusPending,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 	tb.txMap[txhash] = record
 
@@ -136,15 +181,39 @@ func (tb *transactionBookkeeper) record(rawTx common.Bytes) bool {
 	return true
 }
 
-func (tb *transactionBookkeeper) markAbandoned(rawTx common.Bytes) {
+func (tb *transactionBookkeeper) markAbandoned(rawTx common.Bytes, reason string) {
+	tb.update(rawTx, func(txRecord *TxRecord) {
+		txRecord.Status = TxStatusAbandoned
+		txRecord.Reason = reason
+	})
+}
+
+func (tb *transactionBookkeeper) markIncluded(rawTx common.Bytes, height uint64) {
+	tb.update(rawTx, func(txRecord *TxRecord) {
+		txRecord.Status = TxStatusIncluded
+		txRecord.BlockHeight = height
+	})
+}
+
+func (tb *transactionBookkeeper) markReplaced(rawTx common.Bytes, replacedBy string) {
+	tb.update(rawTx, func(txRecord *TxRecord) {
+		txRecord.Status = TxStatusReplaced
+		txRecord.ReplacedBy = replacedBy
+	})
+}
+
+// update applies the status change to the record of the tx, if the tx is known
+func (tb *transactionBookkeeper) update(rawTx common.Bytes, change func(txRecord *TxRecord)) {
 	tb.mutex.Lock()
 	defer tb.mutex.Unlock()
 
 	txhash := getTransactionHash(rawTx)
-	if _, exists := tb.txMap[txhash]; !exists {
+	txRecord, exists := tb.txMap[txhash]
+	if !exists {
 		return
 	}
-	tb.txMap[txhash].Status = TxStatusAbandoned
+	change(txRecord)
+	txRecord.UpdatedAt = time.Now()
 }
 
 func (tb *transactionBookkeeper) remove(rawTx common.Bytes) {
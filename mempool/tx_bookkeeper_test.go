@@ -2,6 +2,7 @@ package mempool
 
 import (
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/thetatoken/theta/common"
@@ -54,6 +55,67 @@ func TestTxBookkeeper(t *testing.T) {
 	assert.False(txb.hasSeen(tx5))
 }
 
+func TestTxBookkeeperLifecycle(t *testing.T) {
+	assert := assert.New(t)
+
+	tx1 := createTestRawTx("1")
+	tx2 := createTestRawTx("2")
+	tx3 := createTestRawTx("3")
+	tx4 := createTestRawTx("4")
+
+	txb := createTransactionBookkeeper(defaultMaxNumTxs)
+	txb.setRetention(10 * time.Minute)
+	for _, tx := range []common.Bytes{tx1, tx2, tx3, tx4} {
+		assert.True(txb.record(tx))
+	}
+
+	txb.markIncluded(tx1, 100)
+	txb.markReplaced(tx2, getTransactionHash(tx1))
+	txb.markAbandoned(tx3, "insufficient fund")
+
+	record, exists := txb.getRecord(getTransactionHash(tx1))
+	assert.True(exists)
+	assert.Equal(TxStatusIncluded, record.Status)
+	assert.Equal(uint64(100), record.BlockHeight)
+
+	record, _ = txb.getRecord(getTransactionHash(tx2))
+	assert.Equal(TxStatusReplaced, record.Status)
+	assert.Equal(getTransactionHash(tx1), record.ReplacedBy)
+
+	record, _ = txb.getRecord(getTransactionHash(tx3))
+	assert.Equal(TxStatusAbandoned, record.Status)
+	assert.Equal("insufficient fund", record.Reason)
+
+	status, _ := txb.getStatus(getTransactionHash(tx4))
+	assert.Equal(TxStatusPending, status)
+
+	// Past maxTxLife, the pending tx expires and can be submitted again, while the records are kept
+	for el := txb.txList.Front(); el != nil; el = el.Next() {
+		el.Value.(*TxRecord).CreatedAt = time.Now().Add(-2 * maxTxLife)
+	}
+	status, exists = txb.getStatus(getTransactionHash(tx4))
+	assert.True(exists)
+	assert.Equal(TxStatusExpired, status)
+	assert.True(txb.hasSeen(tx1))
+	assert.True(txb.hasSeen(tx2))
+	assert.False(txb.hasSeen(tx3))
+	assert.False(txb.hasSeen(tx4))
+
+	assert.True(txb.record(tx4))
+	status, _ = txb.getStatus(getTransactionHash(tx4))
+	assert.Equal(TxStatusPending, status)
+
+	// Past the retention window, the records are removed
+	for el := txb.txList.Front(); el != nil; el = el.Next() {
+		el.Value.(*TxRecord).CreatedAt = time.Now().Add(-time.Hour)
+	}
+	_, exists = txb.getRecord(getTransactionHash(tx1))
+	assert.False(exists)
+	_, exists = txb.getRecord(getTransactionHash(tx4))
+	assert.False(exists)
+	assert.Equal(0, txb.txList.Len())
+}
+
 // --------------- Test Utilities --------------- //
 
 func createTestRawTx(rawTxStr string) common.Bytes {
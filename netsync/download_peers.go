@@ -0,0 +1,121 @@
+package netsync
+
+import (
+	"time"
+
+	"github.com/thetatoken/theta/common/util"
+)
+
+const MinPeerRequestTimeout = 2 * time.Second
+const PeerRequestTimeoutMultiplier = 4
+const MaxPeerConsecutiveTimeouts = 3
+
+// downloadPeer tracks the outstanding block requests and the responsiveness of a peer
+type downloadPeer struct {
+	inflight   int
+	avgLatency time.Duration
+	timeouts   int // number of consecutive timed out requests
+}
+
+// downloadPeerSet distributes block requests among the peers during catch-up sync,
+// so that multiple peers serve the blocks concurrently
+type downloadPeerSet struct {
+	peers          map[string]*downloadPeer
+	maxPerPeer     int
+	requestTimeout time.Duration
+}
+
+func newDownloadPeerSet(maxPerPeer int, requestTimeout time.Duration) *downloadPeerSet {
+	return &downloadPeerSet{
+		peers:          make(map[string]*downloadPeer),
+		maxPerPeer:     maxPerPeer,
+		requestTimeout: requestTimeout,
+	}
+}
+
+func (ps *downloadPeerSet) get(peerID string) *downloadPeer {
+	p, ok := ps.peers[peerID]
+	if !ok {
+		p = &downloadPeer{}
+		ps.peers[peerID] = p
+	}
+	return p
+}
+
+// capacity returns the number of additional requests that can be sent to the peer. A peer
+// that keeps timing out is only given one request at a time until it responds again
+func (ps *downloadPeerSet) capacity(peerID string) int {
+	p := ps.get(peerID)
+	limit := ps.maxPerPeer
+	if p.timeouts >= MaxPeerConsecutiveTimeouts {
+		limit = 1
+	}
+	return limit - p.inflight
+}
+
+// timeout returns the request timeout of the peer, adapted to its observed latency
+func (ps *downloadPeerSet) timeout(peerID string) time.Duration {
+	p := ps.get(peerID)
+	if p.avgLatency == 0 {
+		return ps.requestTimeout
+	}
+	timeout := PeerRequestTimeoutMultiplier * p.avgLatency
+	if timeout < MinPeerRequestTimeout {
+		timeout = MinPeerRequestTimeout
+	}
+	if timeout > ps.requestTimeout {
+		timeout = ps.requestTimeout
+	}
+	return timeout
+}
+
+// selectPeer picks the least loaded peer with spare capacity among the candidates,
+// preferring peers other than the excluded one
+func (ps *downloadPeerSet) selectPeer(candidates []string, exclude string) string {
+	selected := ""
+	selectedLoad := 0
+	for _, pid := range util.Shuffle(candidates) {
+		if ps.capacity(pid) <= 0 {
+			continue
+		}
+		load := ps.get(pid).inflight
+		if pid == exclude {
+			// Only fall back to the excluded peer if no other peer is available
+			load += ps.maxPerPeer
+		}
+		if selected == "" || load < selectedLoad {
+			selected = pid
+			selectedLoad = load
+		}
+	}
+	return selected
+}
+
+func (ps *downloadPeerSet) onRequest(peerID string) {
+	ps.get(peerID).inflight++
+}
+
+func (ps *downloadPeerSet) onResponse(peerID string, latency time.Duration) {
+	p := ps.get(peerID)
+	if p.inflight > 0 {
+		p.inflight--
+	}
+	p.timeouts = 0
+	if p.avgLatency == 0 {
+		p.avgLatency = latency
+	} else {
+		p.avgLatency = (3*p.avgLatency + latency) / 4
+	}
+}
+
+func (ps *downloadPeerSet) onTimeout(peerID string) {
+	p := ps.get(peerID)
+	if p.inflight > 0 {
+		p.inflight--
+	}
+	p.timeouts++
+}
+
+func (ps *downloadPeerSet) remove(peerID string) {
+	delete(ps.peers, peerID)
+}
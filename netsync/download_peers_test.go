@@ -0,0 +1,62 @@
+package netsync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadPeerSetSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	ps := newDownloadPeerSet(2, RequestTimeout)
+	candidates := []string{"peer1", "peer2"}
+
+	// Requests are spread over the least loaded peers
+	first := ps.selectPeer(candidates, "")
+	ps.onRequest(first)
+	second := ps.selectPeer(candidates, "")
+	assert.NotEqual(first, second)
+	ps.onRequest(second)
+	ps.onRequest(ps.selectPeer(candidates, ""))
+	ps.onRequest(ps.selectPeer(candidates, ""))
+
+	// All peers are at capacity
+	assert.Equal("", ps.selectPeer(candidates, ""))
+
+	ps.onResponse("peer1", time.Second)
+	assert.Equal("peer1", ps.selectPeer(candidates, ""))
+
+	// The excluded peer is only selected if no other peer is available
+	ps.onResponse("peer2", time.Second)
+	ps.onResponse("peer2", time.Second)
+	assert.Equal("peer2", ps.selectPeer(candidates, "peer1"))
+	assert.Equal("peer1", ps.selectPeer([]string{"peer1"}, "peer1"))
+}
+
+func TestDownloadPeerSetTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	ps := newDownloadPeerSet(4, RequestTimeout)
+	assert.Equal(RequestTimeout, ps.timeout("peer1"))
+
+	ps.onRequest("peer1")
+	ps.onResponse("peer1", 100*time.Millisecond)
+	assert.Equal(MinPeerRequestTimeout, ps.timeout("peer1"))
+
+	ps.onRequest("peer1")
+	ps.onResponse("peer1", 20*time.Second)
+	assert.Equal(RequestTimeout, ps.timeout("peer1"))
+
+	// A peer that keeps timing out only gets one request at a time
+	for i := 0; i < MaxPeerConsecutiveTimeouts; i++ {
+		ps.onRequest("peer1")
+		ps.onTimeout("peer1")
+	}
+	assert.Equal(1, ps.capacity("peer1"))
+	ps.onRequest("peer1")
+	assert.Equal(0, ps.capacity("peer1"))
+	ps.onResponse("peer1", time.Second)
+	assert.Equal(4, ps.capacity("peer1"))
+}
@@ -0,0 +1,92 @@
+package netsync
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// syncRateWindow is the time window over which the sync rate is measured
+	syncRateWindow = time.Minute
+
+	// syncProgressSampleInterval is the interval to sample the height of the latest finalized block
+	syncProgressSampleInterval = 5 * time.Second
+)
+
+// SyncProgress summarizes the progress of syncing to the best known height of the peers
+type SyncProgress struct {
+	CurrentHeight   uint64  // Height of the latest finalized block
+	BestPeerHeight  uint64  // Highest block height announced by the peers
+	BlocksRemaining uint64  // Number of blocks to sync to reach the best peer height
+	BlocksPerSecond float64 // Sync rate measured over the last minute
+}
+
+type heightSample struct {
+	time   time.Time
+	height uint64
+}
+
+// progressTracker tracks the best height announced by the peers, and the heights of the latest
+// finalized block over the last minute to measure the sync rate
+type progressTracker struct {
+	mu             sync.Mutex
+	bestPeerHeight uint64
+	samples        []heightSample // oldest first
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		samples: []heightSample{},
+	}
+}
+
+// observePeerHeight records the height of a block or header received from the peers
+func (pt *progressTracker) observePeerHeight(height uint64) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	if height > pt.bestPeerHeight {
+		pt.bestPeerHeight = height
+	}
+}
+
+// sample records the height of the latest finalized block, and drops the samples out of the window
+func (pt *progressTracker) sample(now time.Time, height uint64) {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.samples = append(pt.samples, heightSample{time: now, height: height})
+	idx := 0
+	for idx < len(pt.samples)-1 && now.Sub(pt.samples[idx].time) > syncRateWindow {
+		idx++
+	}
+	pt.samples = pt.samples[idx:]
+}
+
+// progress returns the sync progress given the height of the latest finalized block
+func (pt *progressTracker) progress(currentHeight uint64) SyncProgress {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	p := SyncProgress{
+		CurrentHeight:  currentHeight,
+		BestPeerHeight: pt.bestPeerHeight,
+	}
+	if p.BestPeerHeight < currentHeight {
+		p.BestPeerHeight = currentHeight
+	}
+	p.BlocksRemaining = p.BestPeerHeight - currentHeight
+
+	if len(pt.samples) >= 2 {
+		first, last := pt.samples[0], pt.samples[len(pt.samples)-1]
+		if elapsed := last.time.Sub(first.time).Seconds(); elapsed > 0 && last.height > first.height {
+			p.BlocksPerSecond = float64(last.height-first.height) / elapsed
+		}
+	}
+	return p
+}
+
+// GetSyncProgress returns the progress of syncing to the best known height of the peers
+func (sm *SyncManager) GetSyncProgress() SyncProgress {
+	return sm.progress.progress(sm.consensus.GetLastFinalizedBlock().Height)
+}
@@ -0,0 +1,37 @@
+package netsync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTracker(t *testing.T) {
+	assert := assert.New(t)
+
+	pt := newProgressTracker()
+	p := pt.progress(100)
+	assert.Equal(uint64(100), p.BestPeerHeight)
+	assert.Equal(uint64(0), p.BlocksRemaining)
+	assert.Equal(float64(0), p.BlocksPerSecond)
+
+	pt.observePeerHeight(1000)
+	pt.observePeerHeight(900)
+
+	start := time.Now()
+	pt.sample(start, 100)
+	pt.sample(start.Add(10*time.Second), 150)
+	pt.sample(start.Add(20*time.Second), 300)
+	p = pt.progress(300)
+	assert.Equal(uint64(300), p.CurrentHeight)
+	assert.Equal(uint64(1000), p.BestPeerHeight)
+	assert.Equal(uint64(700), p.BlocksRemaining)
+	assert.Equal(float64(10), p.BlocksPerSecond)
+
+	// The samples out of the window are dropped
+	pt.sample(start.Add(80*time.Second), 600)
+	assert.Equal(2, len(pt.samples))
+	p = pt.progress(600)
+	assert.Equal(float64(5), p.BlocksPerSecond)
+}
@@ -27,7 +27,6 @@ const Expiration = 300 * time.Second
 const MinInventoryRequestInterval = 6 * time.Second
 const MaxInventoryRequestInterval = 6 * time.Second
 
-const FastsyncRequestQuota = 8 // Max number of outstanding block requests
 const GossipRequestQuotaPerSecond = 10
 const MaxNumPeersToSendRequests = 4
 const RefreshCounterLimit = 4
@@ -44,14 +43,15 @@ const (
 )
 
 type PendingBlock struct {
-	hash       common.Hash
-	block      *core.Block
-	header     *core.BlockHeader
-	peers      []string
-	lastUpdate time.Time
-	createdAt  time.Time
-	status     RequestState
-	fromGossip bool
+	hash          common.Hash
+	block         *core.Block
+	header        *core.BlockHeader
+	peers         []string
+	requestedFrom string // the peer the outstanding body request was sent to
+	lastUpdate    time.Time
+	createdAt     time.Time
+	status        RequestState
+	fromGossip    bool
 }
 
 func NewPendingBlock(x common.Hash, peerIds []string, fromGossip bool) *PendingBlock {
@@ -120,6 +120,7 @@ type RequestManager struct {
 
 	lastInventoryRequest time.Time
 	blockNotify          chan *core.ExtendedBlock
+	downloadNotify       chan struct{}
 	tip                  atomic.Value
 
 	mu                      *sync.RWMutex
@@ -128,6 +129,8 @@ type RequestManager struct {
 	pendingBlocksWithHeader *HeaderHeap
 	gossipQuota             uint
 	fastsyncQuota           uint
+	maxPendingBlocks        uint
+	downloadPeers           *downloadPeerSet
 	ifDownloadByHash        bool
 	ifDownloadByHeader      bool
 
@@ -164,10 +167,13 @@ func NewRequestManager(syncMgr *SyncManager, reporter *rp.Reporter) *RequestMana
 		pendingBlocks:           list.New(),
 		pendingBlocksByHash:     make(map[string]*list.Element),
 		pendingBlocksWithHeader: &HeaderHeap{},
+		maxPendingBlocks:        uint(viper.GetInt(common.CfgSyncMaxPendingBlocks)),
+		downloadPeers:           newDownloadPeerSet(viper.GetInt(common.CfgSyncMaxPendingBlocksPerPeer), RequestTimeout),
 		ifDownloadByHash:        viper.GetBool(common.CfgSyncDownloadByHash),
 		ifDownloadByHeader:      viper.GetBool(common.CfgSyncDownloadByHeader),
 
 		blockNotify:    make(chan *core.ExtendedBlock, 1),
+		downloadNotify: make(chan struct{}, 1),
 		dumpBlockCache: dumpBlockCache,
 
 		activePeers:    make(map[string]int),
@@ -196,6 +202,8 @@ func (rm *RequestManager) mainLoop() {
 			return
 		case <-rm.ticker.C:
 			rm.tryToDownload()
+		case <-rm.downloadNotify:
+			rm.refillDownloads()
 		}
 	}
 }
@@ -293,11 +301,11 @@ func (rm *RequestManager) buildInventoryRequest() dispatcher.InventoryRequest {
 }
 
 func (rm *RequestManager) tryToDownload() {
-	rm.mu.RLock()
-	defer rm.mu.RUnlock()
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
 
 	rm.gossipQuota = GossipRequestQuotaPerSecond
-	rm.fastsyncQuota = FastsyncRequestQuota
+	rm.fastsyncQuota = rm.maxPendingBlocks
 
 	hasUndownloadedBlocks := rm.pendingBlocks.Len() > 0 || len(rm.pendingBlocksByHash) > 0 || rm.pendingBlocksWithHeader.Len() > 0
 
@@ -328,8 +336,26 @@ func (rm *RequestManager) tryToDownload() {
 		rm.downloadBlockFromHash()
 	}
 
-	// Remove downloaded blocks from header queue
-	// newQ := []*PendingBlock{}
+	rm.pruneHeaderQueue()
+}
+
+// refillDownloads keeps the block download pipeline full by sending new body requests
+// as soon as outstanding ones are answered, instead of waiting for the next tick.
+func (rm *RequestManager) refillDownloads() {
+	if !rm.ifDownloadByHeader {
+		return
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.fastsyncQuota = rm.maxPendingBlocks
+	rm.downloadBlockFromHeader()
+	rm.pruneHeaderQueue()
+}
+
+// Remove downloaded blocks from header queue
+func (rm *RequestManager) pruneHeaderQueue() {
 	newQ := &HeaderHeap{}
 	for _, header := range *rm.pendingBlocksWithHeader {
 		if _, ok := rm.pendingBlocksByHash[header.hash.Hex()]; ok {
@@ -422,6 +448,9 @@ func (rm *RequestManager) downloadBlockFromHeader() {
 
 		// Remove expired header from queue
 		if pendingBlock.HasExpired() {
+			if pendingBlock.status == RequestWaitingBodyResp {
+				rm.downloadPeers.onTimeout(pendingBlock.requestedFrom)
+			}
 			if el, ok := rm.pendingBlocksByHash[pendingBlock.hash.String()]; ok {
 				elToRemove = append(elToRemove, el)
 			}
@@ -452,45 +481,62 @@ func (rm *RequestManager) downloadBlockFromHeader() {
 			}).Debug("Skip block with no peer")
 			continue
 		}
-		if pendingBlock.status == RequestWaitingBodyResp && !pendingBlock.HasTimedOut() {
-			rm.fastsyncQuota--
-			continue
+		if pendingBlock.status == RequestWaitingBodyResp {
+			if !rm.hasBodyRequestTimedOut(pendingBlock) {
+				rm.fastsyncQuota--
+				continue
+			}
+			rm.logger.WithFields(log.Fields{
+				"pendingBlock": pendingBlock.hash.String(),
+				"peer":         pendingBlock.requestedFrom,
+			}).Debug("Block request timed out, reassigning")
+			rm.downloadPeers.onTimeout(pendingBlock.requestedFrom)
+			pendingBlock.status = RequestToSendBodyReq
 		}
-		if pendingBlock.status == RequestToSendBodyReq ||
-			(pendingBlock.status == RequestWaitingBodyResp && pendingBlock.HasTimedOut()) {
-
-			peersWithBlock := util.Shuffle(pendingBlock.peers)
-			var randomPeerID string
-			for i := 0; i < len(peersWithBlock); i++ {
-				if rm.dispatcher.PeerExists(peersWithBlock[i]) { // the peer may have been purged
-					randomPeerID = peersWithBlock[i]
-					break
+		if pendingBlock.status == RequestToSendBodyReq {
+			candidates := []string{}
+			for _, pid := range pendingBlock.peers {
+				if rm.dispatcher.PeerExists(pid) { // the peer may have been purged
+					candidates = append(candidates, pid)
+					continue
 				}
+				rm.downloadPeers.remove(pid)
 
 				rm.logger.WithFields(log.Fields{
 					"pendingBlock": pendingBlock.hash.String(),
-					"peer":         peersWithBlock[i],
+					"peer":         pid,
 				}).Debug("Skipped peer that may have been purged")
-
 			}
-			if len(randomPeerID) == 0 {
+			if len(candidates) == 0 {
 				rm.logger.WithFields(log.Fields{
 					"pendingBlock": pendingBlock.hash.String(),
 				}).Debug("All peers skipped")
 				continue
 			}
 
-			if blockBuffer, ok = peerMap[randomPeerID]; !ok {
+			// Spread the requests over the least loaded peers, and avoid the peer
+			// that has just timed out on this block if possible
+			peerID := rm.downloadPeers.selectPeer(candidates, pendingBlock.requestedFrom)
+			if len(peerID) == 0 {
+				rm.logger.WithFields(log.Fields{
+					"pendingBlock": pendingBlock.hash.String(),
+				}).Debug("All peers busy")
+				continue
+			}
+
+			if blockBuffer, ok = peerMap[peerID]; !ok {
 				blockBuffer = []string{}
 			}
 			blockBuffer := append(blockBuffer, pendingBlock.hash.String())
 			if len(blockBuffer) == MaxBlocksPerRequest {
-				rm.sendBlocksRequest(randomPeerID, blockBuffer)
+				rm.sendBlocksRequest(peerID, blockBuffer)
 				blockBuffer = []string{}
 			}
-			peerMap[randomPeerID] = blockBuffer
+			peerMap[peerID] = blockBuffer
 			pendingBlock.UpdateTimestamp()
 			pendingBlock.status = RequestWaitingBodyResp
+			pendingBlock.requestedFrom = peerID
+			rm.downloadPeers.onRequest(peerID)
 			rm.fastsyncQuota--
 		}
 	}
@@ -606,6 +652,15 @@ func (rm *RequestManager) sendBlocksRequest(peerID string, entries []string) {
 	rm.syncMgr.dispatcher.GetData([]string{peerID}, request)
 }
 
+// hasBodyRequestTimedOut checks whether the outstanding body request of the block should
+// be sent again. The timeout adapts to the latency of the peer the request was sent to.
+func (rm *RequestManager) hasBodyRequestTimedOut(pb *PendingBlock) bool {
+	if !rm.dispatcher.PeerExists(pb.requestedFrom) {
+		return true
+	}
+	return time.Since(pb.lastUpdate) > rm.downloadPeers.timeout(pb.requestedFrom)
+}
+
 func (rm *RequestManager) removeEl(el *list.Element) {
 	pendingBlock := el.Value.(*PendingBlock)
 	hash := pendingBlock.hash.Hex()
@@ -716,6 +771,10 @@ func (rm *RequestManager) AddBlock(block *core.Block) {
 	hash := block.Hash().String()
 
 	if pendingBlockEl, ok := rm.pendingBlocksByHash[hash]; ok {
+		pendingBlock := pendingBlockEl.Value.(*PendingBlock)
+		if pendingBlock.status == RequestWaitingBodyResp {
+			rm.downloadPeers.onResponse(pendingBlock.requestedFrom, time.Since(pendingBlock.lastUpdate))
+		}
 		rm.pendingBlocks.Remove(pendingBlockEl)
 		delete(rm.pendingBlocksByHash, hash)
 	}
@@ -724,6 +783,10 @@ func (rm *RequestManager) AddBlock(block *core.Block) {
 	case rm.blockNotify <- eb:
 	default:
 	}
+	select {
+	case rm.downloadNotify <- struct{}{}:
+	default:
+	}
 }
 
 func (rm *RequestManager) passReadyBlocks() {
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	log "github.com/sirupsen/logrus"
@@ -53,12 +55,15 @@ type SyncManager struct {
 	cancel   context.CancelFunc
 	stopped  bool
 	incoming chan p2ptypes.Message
+	paused   uint32
 
 	whitelist []string
 
 	logger *log.Entry
 
 	voteCache *lru.Cache // Cache for votes
+
+	progress *progressTracker
 }
 
 func NewSyncManager(chain *blockchain.Chain, cons core.ConsensusEngine, networkOld p2p.Network, network p2pl.Network, disp *dispatcher.Dispatcher, consumer MessageConsumer, reporter *rp.Reporter) *SyncManager {
@@ -72,6 +77,7 @@ func NewSyncManager(chain *blockchain.Chain, cons core.ConsensusEngine, networkO
 		incoming:   make(chan p2ptypes.Message, viper.GetInt(common.CfgSyncMessageQueueSize)),
 
 		voteCache: voteCache,
+		progress:  newProgressTracker(),
 	}
 	sm.requestMgr = NewRequestManager(sm, reporter)
 
@@ -118,6 +124,9 @@ func (sm *SyncManager) Wait() {
 func (sm *SyncManager) mainLoop() {
 	defer sm.wg.Done()
 
+	progressTicker := time.NewTicker(syncProgressSampleInterval)
+	defer progressTicker.Stop()
+
 	for {
 		select {
 		case <-sm.ctx.Done():
@@ -125,6 +134,8 @@ func (sm *SyncManager) mainLoop() {
 			return
 		case msg := <-sm.incoming:
 			sm.processMessage(msg)
+		case now := <-progressTicker.C:
+			sm.progress.sample(now, sm.consensus.GetLastFinalizedBlock().Height)
 		}
 	}
 }
@@ -162,10 +173,23 @@ func (sm *SyncManager) EncodeMessage(message interface{}) (common.Bytes, error)
 
 // HandleMessage implements p2p.MessageHandler interface.
 func (sm *SyncManager) HandleMessage(msg p2ptypes.Message) (err error) {
+	if atomic.LoadUint32(&sm.paused) == 1 {
+		return
+	}
 	sm.incoming <- msg
 	return
 }
 
+// Pause makes the SyncManager drop all the incoming messages, e.g. while the node is in state sync.
+func (sm *SyncManager) Pause() {
+	atomic.StoreUint32(&sm.paused, 1)
+}
+
+// Resume makes the SyncManager process the incoming messages again.
+func (sm *SyncManager) Resume() {
+	atomic.StoreUint32(&sm.paused, 0)
+}
+
 func (sm *SyncManager) processMessage(message p2ptypes.Message) {
 	inboundAllowed := true
 	// If whitelist is set, only process message from peers in the whitelist.
@@ -545,6 +569,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 					"error":     err,
 					"peerID":    peerID,
 				}).Warn("Failed to decode DataResponse payload")
+				m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 				return
 			}
 			for _, block = range blocks.BlockArray {
@@ -554,7 +579,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 					"block.Height": block.Height,
 					"peer":         peerID,
 				}).Debug("Received block")
-				m.handleBlock(block)
+				m.handleBlock(block, peerID)
 				if block.Height > maxReceivedHeight {
 					maxReceivedHeight = block.Height
 				}
@@ -566,7 +591,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"block.Height": block.Height,
 				"peer":         peerID,
 			}).Debug("Received block")
-			m.handleBlock(block)
+			m.handleBlock(block, peerID)
 			maxReceivedHeight = block.Height
 		}
 	case common.ChannelIDVote:
@@ -579,6 +604,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Warn("Failed to decode DataResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		m.logger.WithFields(log.Fields{
@@ -587,7 +613,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 			"vote.Epoch": vote.Epoch,
 			"peer":       peerID,
 		}).Debug("Received vote")
-		m.handleVote(vote)
+		m.handleVote(vote, peerID)
 	case common.ChannelIDProposal:
 		proposal := &core.Proposal{}
 		err := rlp.DecodeBytes(data.Payload, proposal)
@@ -598,13 +624,14 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Warn("Failed to decode DataResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		m.logger.WithFields(log.Fields{
 			"proposal": proposal,
 			"peer":     peerID,
 		}).Debug("Received proposal")
-		m.handleProposal(proposal)
+		m.handleProposal(proposal, peerID)
 	case common.ChannelIDGuardian:
 		vote := &core.AggregatedVotes{}
 		err := rlp.DecodeBytes(data.Payload, vote)
@@ -615,6 +642,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Warn("Failed to decode DataResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		m.logger.WithFields(log.Fields{
@@ -634,6 +662,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Warn("Failed to decode DataResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		// m.logger.WithFields(log.Fields{
@@ -651,6 +680,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Warn("Failed to decode DataResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		m.logger.WithFields(log.Fields{
@@ -669,6 +699,7 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 				"error":     err,
 				"peerID":    peerID,
 			}).Debug("Failed to decode HeaderResponse payload")
+			m.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 		for _, header := range headers.HeaderArray {
@@ -687,13 +718,13 @@ func (m *SyncManager) handleDataResponse(peerID string, data *dispatcher.DataRes
 	}
 }
 
-func (sm *SyncManager) handleProposal(p *core.Proposal) {
+func (sm *SyncManager) handleProposal(p *core.Proposal, peerID string) {
 	if p.Votes != nil {
 		for _, vote := range p.Votes.Votes() {
-			sm.handleVote(vote)
+			sm.handleVote(vote, peerID)
 		}
 	}
-	sm.handleBlock(p.Block)
+	sm.handleBlock(p.Block, peerID)
 }
 
 func (sm *SyncManager) handleHeader(header *core.BlockHeader, peerID []string) {
@@ -718,11 +749,12 @@ func (sm *SyncManager) handleHeader(header *core.BlockHeader, peerID []string) {
 	lfbHeight := sm.consensus.GetLastFinalizedBlock().Height
 	tipHeight := sm.consensus.GetTip(true).Height
 	if header.Height > lfbHeight && header.Height <= tipHeight+dispatcher.MaxInventorySize+1 {
+		sm.progress.observePeerHeight(header.Height)
 		sm.requestMgr.AddHeader(header, peerID)
 	}
 }
 
-func (sm *SyncManager) handleBlock(block *core.Block) {
+func (sm *SyncManager) handleBlock(block *core.Block, peerID string) {
 	if eb, err := sm.chain.FindBlock(block.Hash()); err == nil && !eb.Status.IsPending() {
 		sm.logger.WithFields(log.Fields{
 			"block hash":   block.Hash().String(),
@@ -737,6 +769,7 @@ func (sm *SyncManager) handleBlock(block *core.Block) {
 				"block hash":   block.Hash().String(),
 				"block height": block.Height,
 			}).Debug("hardcoded block")
+			sm.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorInvalidBlock)
 			return
 		}
 	} else if res := block.Validate(sm.chain.ChainID); res.IsError() {
@@ -744,9 +777,11 @@ func (sm *SyncManager) handleBlock(block *core.Block) {
 			"block hash":   block.Hash().String(),
 			"block height": block.Height,
 		}).Debug("chain ID is invalid")
+		sm.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorInvalidBlock)
 		return
 	}
 
+	sm.progress.observePeerHeight(block.Height)
 	sm.requestMgr.AddBlock(block)
 
 	p2pOpt := common.P2POptEnum(viper.GetInt(common.CfgP2POpt))
@@ -775,7 +810,7 @@ func (sm *SyncManager) handleBlock(block *core.Block) {
 	}
 }
 
-func (sm *SyncManager) handleVote(vote core.Vote) {
+func (sm *SyncManager) handleVote(vote core.Vote, peerID string) {
 	votes := sm.chain.FindVotesByHash(vote.Block).Votes()
 	for _, v := range votes {
 		// Check if vote already processed.
@@ -789,6 +824,14 @@ func (sm *SyncManager) handleVote(vote core.Vote) {
 			return
 		}
 	}
+	if res := vote.Validate(); res.IsError() {
+		sm.logger.WithFields(log.Fields{
+			"vote":  vote,
+			"error": res.Message,
+		}).Debug("Received invalid vote")
+		sm.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorInvalidVote)
+		return
+	}
 
 	sm.PassdownMessage(vote)
 
@@ -2,6 +2,7 @@ package netsync
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -186,6 +187,7 @@ func NewMockConsensus(chain *blockchain.Chain, lfb *core.ExtendedBlock) *MockCon
 
 // ID() string
 // PrivateKey() *crypto.PrivateKey
+// Signer() Signer
 // GetTip(includePendingBlockingLeaf bool) *ExtendedBlock
 // GetEpoch() uint64
 // GetLedger() Ledger
@@ -201,6 +203,10 @@ func (c *MockConsensus) PrivateKey() *crypto.PrivateKey {
 	return nil
 }
 
+func (c *MockConsensus) Signer() core.Signer {
+	return nil
+}
+
 func (c *MockConsensus) GetTip(includePendingBlockingLeaf bool) *core.ExtendedBlock {
 	return nil
 }
@@ -209,6 +215,10 @@ func (c *MockConsensus) GetEpoch() uint64 {
 	return 0
 }
 
+func (c *MockConsensus) FindBlock(blockHash common.Hash) (*core.ExtendedBlock, error) {
+	return nil, errors.New("Block not found")
+}
+
 func (c *MockConsensus) GetLedger() core.Ledger {
 	return (*ledger.Ledger)(nil)
 }
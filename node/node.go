@@ -3,10 +3,14 @@ package node
 import (
 	"context"
 	"log"
+	"path"
+	"path/filepath"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/alert"
 	"github.com/thetatoken/theta/blockchain"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/consensus"
@@ -21,10 +25,14 @@ import (
 	rp "github.com/thetatoken/theta/report"
 	"github.com/thetatoken/theta/rpc"
 	"github.com/thetatoken/theta/snapshot"
+	"github.com/thetatoken/theta/statesync"
 	"github.com/thetatoken/theta/store"
 	"github.com/thetatoken/theta/store/database"
 	"github.com/thetatoken/theta/store/kvstore"
 	"github.com/thetatoken/theta/store/rollingdb"
+	"github.com/thetatoken/theta/telemetry"
+	"github.com/thetatoken/theta/watch"
+	"github.com/thetatoken/theta/webhook"
 )
 
 type Node struct {
@@ -33,12 +41,23 @@ type Node struct {
 	Consensus        *consensus.ConsensusEngine
 	ValidatorManager core.ValidatorManager
 	SyncManager      *netsync.SyncManager
+	StateSync        *statesync.StateSyncManager
 	Dispatcher       *dp.Dispatcher
 	Ledger           core.Ledger
 	Mempool          *mp.Mempool
 	RPC              *rpc.ThetaRPCServer
 	reporter         *rp.Reporter
 
+	root               *core.Block
+	db                 database.Database
+	rollingDB          *rollingdb.RollingDB
+	ledger             *ld.Ledger
+	integrityChecker   *ld.IntegrityChecker
+	webhookNotifier    *webhook.Notifier
+	telemetryReporter  *telemetry.Reporter
+	alerter            *alert.Alerter
+	mempoolJournalPath string
+
 	// Life cycle
 	wg      *sync.WaitGroup
 	quit    chan struct{}
@@ -58,9 +77,13 @@ type Params struct {
 	SnapshotPath        string
 	ChainImportDirPath  string
 	ChainCorrectionPath string
+	MempoolJournalPath  string      // the mempool journal is disabled if empty
+	Signer              core.Signer // signs with PrivateKey if nil
 }
 
 func NewNode(params *Params) *Node {
+	loadTrustedCheckpoint()
+
 	store := kvstore.NewKVStore(params.DB)
 	chain := blockchain.NewChain(params.ChainID, store, params.Root)
 	params.RollingDB.SetChain(chain)
@@ -68,12 +91,17 @@ func NewNode(params *Params) *Node {
 	validatorManager := consensus.NewRotatingValidatorManager()
 	dispatcher := dp.NewDispatcher(params.NetworkOld, params.Network)
 	consensus := consensus.NewConsensusEngine(params.PrivateKey, store, chain, dispatcher, validatorManager)
+	if params.Signer != nil {
+		consensus.SetSigner(params.Signer)
+	}
 	reporter := rp.NewReporter(dispatcher, consensus, chain)
 
 	// TODO: check if this is a guardian node
 	syncMgr := netsync.NewSyncManager(chain, consensus, params.NetworkOld, params.Network, dispatcher, consensus, reporter)
+	stateSync := statesync.NewStateSyncManager(params.ChainID, params.NetworkOld, params.Network, dispatcher)
 	mempool := mp.CreateMempool(dispatcher, consensus)
 	ledger := ld.NewLedger(params.ChainID, params.RollingDB, params.RollingDB, chain, consensus, validatorManager, mempool)
+	common.OnConfigReload(common.CfgMempoolMinTxFees, ledger.MinTxFees().ReloadFromConfig)
 
 	validatorManager.SetConsensusEngine(consensus)
 	consensus.SetLedger(ledger)
@@ -90,36 +118,47 @@ func NewNode(params *Params) *Node {
 	currentHeight := consensus.GetLastFinalizedBlock().Height
 	if currentHeight <= params.Root.Height {
 		snapshotPath := params.SnapshotPath
-		chainImportDirPath := params.ChainImportDirPath
-		chainCorrectionPath := params.ChainCorrectionPath
-		var lastCC *core.ExtendedBlock
-		var err error
-		if _, lastCC, err = snapshot.ImportSnapshot(snapshotPath, chainImportDirPath, chainCorrectionPath, chain, params.DB, ledger); err != nil {
+		if err := importSnapshot(snapshotPath, params.ChainImportDirPath, params.ChainCorrectionPath, chain, params.DB, ledger, consensus); err != nil {
 			log.Fatalf("Failed to load snapshot: %v, err: %v", snapshotPath, err)
 		}
-		if lastCC != nil {
-			state := consensus.State()
-			state.SetLastFinalizedBlock(lastCC)
-			state.SetHighestCCBlock(lastCC)
-			state.SetLastVote(core.Vote{})
-			state.SetLastProposal(core.Proposal{})
-		}
 	}
 
 	node := &Node{
-		Store:            store,
-		Chain:            chain,
-		Consensus:        consensus,
-		ValidatorManager: validatorManager,
-		SyncManager:      syncMgr,
-		Dispatcher:       dispatcher,
-		Ledger:           ledger,
-		Mempool:          mempool,
-		reporter:         reporter,
+		Store:              store,
+		Chain:              chain,
+		Consensus:          consensus,
+		ValidatorManager:   validatorManager,
+		SyncManager:        syncMgr,
+		StateSync:          stateSync,
+		Dispatcher:         dispatcher,
+		Ledger:             ledger,
+		Mempool:            mempool,
+		reporter:           reporter,
+		root:               params.Root,
+		db:                 params.DB,
+		rollingDB:          params.RollingDB,
+		ledger:             ledger,
+		mempoolJournalPath: params.MempoolJournalPath,
 	}
 
+	if viper.GetBool(common.CfgLedgerIntegrityCheckEnabled) {
+		node.integrityChecker = ld.NewIntegrityChecker(ledger)
+	}
+	watched, err := watch.NewRegistry(store)
+	if err != nil {
+		log.Fatalf("Failed to load the watched addresses: %v", err)
+	}
+	if viper.GetBool(common.CfgWebhookEnabled) {
+		node.webhookNotifier = webhook.NewNotifier(chain, consensus, store, watched)
+	}
+	if viper.GetBool(common.CfgTelemetryEnabled) {
+		node.telemetryReporter = telemetry.NewReporter(params.ChainID, consensus, dispatcher)
+	}
+	if viper.GetBool(common.CfgAlertEnabled) {
+		node.alerter = alert.NewAlerter(chain, consensus, dispatcher, syncMgr)
+	}
 	if viper.GetBool(common.CfgRPCEnabled) {
-		node.RPC = rpc.NewThetaRPCServer(mempool, ledger, dispatcher, chain, consensus)
+		node.RPC = rpc.NewThetaRPCServer(mempool, ledger, dispatcher, chain, consensus, syncMgr, watched)
 	}
 	return node
 }
@@ -130,17 +169,99 @@ func (n *Node) Start(ctx context.Context) {
 	n.ctx = c
 	n.cancel = cancel
 
+	n.Dispatcher.Start(n.ctx)
+	if viper.GetBool(common.CfgSyncStateSyncEnabled) && n.Consensus.GetLastFinalizedBlock().Height <= n.root.Height {
+		n.runStateSync()
+	}
+
 	n.Consensus.Start(n.ctx)
 	n.SyncManager.Start(n.ctx)
-	n.Dispatcher.Start(n.ctx)
 	n.Mempool.Start(n.ctx)
 	n.reporter.Start(n.ctx)
+	if n.integrityChecker != nil {
+		n.integrityChecker.Start(n.ctx)
+	}
+	if n.webhookNotifier != nil {
+		n.webhookNotifier.Start(n.ctx)
+	}
+	if n.telemetryReporter != nil {
+		n.telemetryReporter.Start(n.ctx)
+	}
+	if n.alerter != nil {
+		n.alerter.Start(n.ctx)
+	}
+
+	if n.mempoolJournalPath != "" {
+		if err := n.Mempool.RestoreJournal(n.mempoolJournalPath); err != nil {
+			log.Printf("Failed to restore the mempool journal %v: %v", n.mempoolJournalPath, err)
+		}
+	}
 
 	if viper.GetBool(common.CfgRPCEnabled) {
 		n.RPC.Start(n.ctx)
 	}
 }
 
+// runStateSync downloads the latest snapshot from the peers and imports it, so that the node only
+// needs to sync the recent blocks. The node falls back to block sync if the state sync fails.
+func (n *Node) runStateSync() {
+	n.SyncManager.Pause()
+	defer n.SyncManager.Resume()
+
+	timeout := time.Duration(viper.GetInt(common.CfgSyncStateSyncTimeoutSecs)) * time.Second
+	ctx, cancel := context.WithTimeout(n.ctx, timeout)
+	defer cancel()
+
+	downloadDir := path.Join(filepath.Dir(viper.ConfigFileUsed()), "statesync")
+	snapshotPath, err := n.StateSync.Sync(ctx, n.root.Height, downloadDir)
+	if err != nil {
+		log.Printf("State sync failed, fall back to block sync: %v", err)
+		return
+	}
+	if _, err := snapshot.ValidateSnapshot(snapshotPath, "", ""); err != nil {
+		log.Printf("Downloaded snapshot %v is invalid, fall back to block sync: %v", snapshotPath, err)
+		return
+	}
+	if err := importSnapshot(snapshotPath, "", "", n.Chain, n.db, n.ledger, n.Consensus); err != nil {
+		log.Fatalf("Failed to import the downloaded snapshot: %v, err: %v", snapshotPath, err)
+	}
+	log.Printf("State sync completed, snapshot: %v", snapshotPath)
+}
+
+// loadTrustedCheckpoint sets the trusted checkpoint specified in the config, if any
+func loadTrustedCheckpoint() {
+	height := viper.GetUint64(common.CfgSyncCheckpointHeight)
+	if height == 0 {
+		return
+	}
+	blockHash := common.HexToHash(viper.GetString(common.CfgSyncCheckpointBlockHash))
+	if blockHash.IsEmpty() {
+		log.Fatalf("%v must be specified for the trusted checkpoint at height %v", common.CfgSyncCheckpointBlockHash, height)
+	}
+	core.SetTrustedCheckpoint(&core.TrustedCheckpoint{
+		Height:           height,
+		BlockHash:        blockHash,
+		ValidatorSetHash: common.HexToHash(viper.GetString(common.CfgSyncCheckpointValidatorSetHash)),
+	})
+	log.Printf("Trusted checkpoint, height: %v, block: %v", height, blockHash.Hex())
+}
+
+func importSnapshot(snapshotPath, chainImportDirPath, chainCorrectionPath string, chain *blockchain.Chain,
+	db database.Database, ledger *ld.Ledger, consensus *consensus.ConsensusEngine) error {
+	_, lastCC, err := snapshot.ImportSnapshot(snapshotPath, chainImportDirPath, chainCorrectionPath, chain, db, ledger)
+	if err != nil {
+		return err
+	}
+	if lastCC != nil {
+		state := consensus.State()
+		state.SetLastFinalizedBlock(lastCC)
+		state.SetHighestCCBlock(lastCC)
+		state.SetLastVote(core.Vote{})
+		state.SetLastProposal(core.Proposal{})
+	}
+	return nil
+}
+
 // Stop notifies all sub components to stop without blocking.
 func (n *Node) Stop() {
 	n.cancel()
@@ -150,7 +271,32 @@ func (n *Node) Stop() {
 func (n *Node) Wait() {
 	n.Consensus.Wait()
 	n.SyncManager.Wait()
+	n.Mempool.Wait()
+	if n.integrityChecker != nil {
+		n.integrityChecker.Wait()
+	}
+	if n.webhookNotifier != nil {
+		n.webhookNotifier.Wait()
+	}
+	if n.telemetryReporter != nil {
+		n.telemetryReporter.Wait()
+	}
+	if n.alerter != nil {
+		n.alerter.Wait()
+	}
 	if n.RPC != nil {
 		n.RPC.Wait()
 	}
 }
+
+// Close saves the mempool journal and closes the databases. It needs to be called after all sub
+// components have stopped, so that no block commit is in flight.
+func (n *Node) Close() {
+	if n.mempoolJournalPath != "" {
+		if err := n.Mempool.SaveJournal(n.mempoolJournalPath); err != nil {
+			log.Printf("Failed to save the mempool journal %v: %v", n.mempoolJournalPath, err)
+		}
+	}
+	n.rollingDB.Close()
+	n.db.Close()
+}
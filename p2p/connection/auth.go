@@ -102,6 +102,33 @@ func (conn *Connection) DoEncHandshake(prv *ecdsa.PrivateKey, dial *ecdsa.Public
 	return crypto.ECDSAToPubKey(sec.Remote.ExportECDSA()), nil
 }
 
+// IsEncrypted indicates whether the encryption handshake has completed,
+// i.e. all the subsequent packets are sent through the encrypted transport
+func (conn *Connection) IsEncrypted() bool {
+	conn.wmu.Lock()
+	defer conn.wmu.Unlock()
+	return conn.rw != nil
+}
+
+// EnableCompression turns on the snappy compression of the encrypted frames. It
+// should only be called after both sides agreed on the compression during the handshake
+func (conn *Connection) EnableCompression() bool {
+	conn.wmu.Lock()
+	defer conn.wmu.Unlock()
+	if conn.rw == nil {
+		return false
+	}
+	conn.rw.snappy = true
+	return true
+}
+
+// IsCompressed indicates whether the frames are compressed
+func (conn *Connection) IsCompressed() bool {
+	conn.wmu.Lock()
+	defer conn.wmu.Unlock()
+	return conn.rw != nil && conn.rw.snappy
+}
+
 // encHandshake contains the state of the encryption handshake.
 type encHandshake struct {
 	initiator            bool
@@ -0,0 +1,51 @@
+package connection
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto/sha3"
+)
+
+func TestRLPXFrameCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	var pipe bytes.Buffer
+	writer := newRLPXFrameRW(&pipe, newTestSecrets())
+	reader := newRLPXFrameRW(&pipe, newTestSecrets())
+
+	payload := bytes.Repeat([]byte("theta"), 200)
+	packet := &Packet{
+		ChannelID: common.ChannelIDBlock,
+		Bytes:     payload,
+		IsEOF:     byte(0x01),
+	}
+
+	// Plain frame
+	assert.Nil(writer.WritePacket(packet))
+	plainSize := pipe.Len()
+	received, err := reader.ReadPacket()
+	assert.Nil(err)
+	assert.Equal(payload, received.Bytes)
+
+	// Compressed frame
+	writer.snappy = true
+	reader.snappy = true
+	assert.Nil(writer.WritePacket(packet))
+	assert.True(pipe.Len() < plainSize)
+	received, err = reader.ReadPacket()
+	assert.Nil(err)
+	assert.Equal(common.ChannelIDBlock, received.ChannelID)
+	assert.Equal(payload, received.Bytes)
+}
+
+func newTestSecrets() secrets {
+	return secrets{
+		AES:        bytes.Repeat([]byte{0x01}, 16),
+		MAC:        bytes.Repeat([]byte{0x02}, 16),
+		EgressMAC:  sha3.NewKeccak256(),
+		IngressMAC: sha3.NewKeccak256(),
+	}
+}
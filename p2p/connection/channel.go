@@ -13,7 +13,8 @@ type Channel struct {
 	sendBuf SendBuffer
 	recvBuf RecvBuffer
 
-	config ChannelConfig
+	config  ChannelConfig
+	metrics *channelMetrics
 }
 
 //
@@ -23,6 +24,32 @@ type ChannelConfig struct {
 	priority uint
 }
 
+// The priorities of the channels. The packets of the high priority channels are sent before those
+// of the default priority channels, up to maxConsecutivePriorityPackets packets in a row.
+const (
+	channelPriorityDefault uint = 0
+	channelPriorityHigh    uint = 1
+)
+
+// dropOldestChannels are the channels of the bulk messages, i.e. the blocks, the headers and the
+// txs, which are re-requested or re-gossiped if lost. The drop-oldest send queue policy only
+// applies to these channels, the other channels always wait for the queue.
+var dropOldestChannels = map[common.ChannelIDEnum]bool{
+	common.ChannelIDBlock:       true,
+	common.ChannelIDHeader:      true,
+	common.ChannelIDTransaction: true,
+}
+
+// priorityChannels are the channels of the consensus-critical messages, i.e. the votes, the
+// proposals (which carry the commit certificates), and the aggregated votes of the guardians and
+// the elite edge nodes, so that they are not delayed behind the block sync and the tx gossip traffic
+var priorityChannels = map[common.ChannelIDEnum]bool{
+	common.ChannelIDProposal:                     true,
+	common.ChannelIDVote:                         true,
+	common.ChannelIDGuardian:                     true,
+	common.ChannelIDAggregatedEliteEdgeNodeVotes: true,
+}
+
 // createDefaultChannel creates a channel with default configs
 func createDefaultChannel(channelID common.ChannelIDEnum) Channel {
 	chCfg := getDefaultChannelConfig()
@@ -33,22 +60,42 @@ func createDefaultChannel(channelID common.ChannelIDEnum) Channel {
 	return channel
 }
 
+// createConnectionChannel creates a channel with the send queue specified by the connection config
+func createConnectionChannel(channelID common.ChannelIDEnum, connConfig ConnectionConfig) Channel {
+	chCfg := getDefaultChannelConfig()
+	sbCfg := getDefaultSendBufferConfig()
+	rbCfg := getDefaultRecvBufferConfig()
+	if connConfig.SendQueueCapacity > 0 {
+		sbCfg.queueCapacity = connConfig.SendQueueCapacity
+	}
+	sbCfg.dropOldest = (connConfig.SendQueuePolicy == SendQueuePolicyDropOldest) && dropOldestChannels[channelID]
+	if priorityChannels[channelID] {
+		chCfg.priority = channelPriorityHigh
+	}
+
+	channel := createChannel(channelID, chCfg, sbCfg, rbCfg)
+	return channel
+}
+
 // createChannel creates a channel for the given configs
 func createChannel(channelID common.ChannelIDEnum, channelConf ChannelConfig, sbConf SendBufferConfig, rbConf RecvBufferConfig) Channel {
+	chMetrics := getChannelMetrics(channelID)
 	sendBuf := createSendBuffer(sbConf)
+	sendBuf.metrics = chMetrics
 	recvBuf := createRecvBuffer(rbConf)
 	return Channel{
 		id:      channelID,
 		sendBuf: sendBuf,
 		recvBuf: recvBuf,
 		config:  channelConf,
+		metrics: chMetrics,
 	}
 }
 
 // createChannel creates the default channel config
 func getDefaultChannelConfig() ChannelConfig {
 	return ChannelConfig{
-		priority: 0,
+		priority: channelPriorityDefault,
 	}
 }
 
@@ -72,6 +119,12 @@ func (ch *Channel) attemptToEnqueueMessage(bytes []byte) bool {
 // receivePacket receives packet and return the converted bytes
 func (ch *Channel) receivePacket(packet *Packet) ([]byte, bool) {
 	bytes, success := ch.recvBuf.receivePacket(packet)
+	if !success {
+		ch.metrics.recvRejected.Inc(1)
+	} else if bytes != nil {
+		ch.metrics.recvMessages.Inc(1)
+		ch.metrics.recvDepth.Update(int64(packet.SeqID + 1))
+	}
 	return bytes, success
 }
 
@@ -92,6 +145,11 @@ func (ch *Channel) sendPacketTo(conn *Connection) (nonemptyPacket bool, numBytes
 	return true, numBytes, err
 }
 
+// isHighPriority returns whether the packets of the channel are sent before the default priority ones
+func (ch *Channel) isHighPriority() bool {
+	return ch.config.priority > channelPriorityDefault
+}
+
 // canEnqueueMessage returns whether more messages can be queued into the channel
 func (ch *Channel) canEnqueueMessage() bool {
 	return ch.sendBuf.canInsert()
@@ -10,6 +10,10 @@ const (
 	channelSelectionRoundRobinStrategy = 1
 )
 
+// maxConsecutivePriorityPackets bounds the number of high priority packets sent in a row while the
+// default priority channels have pending packets, so that a burst of votes cannot starve the others
+const maxConsecutivePriorityPackets = 8
+
 //
 // ChannelGroup contains multiple channels to facilitate fair scheduling
 //
@@ -19,6 +23,10 @@ type ChannelGroup struct {
 	channelMap map[common.ChannelIDEnum]*Channel // map: ChannelID |-> *Channel
 	channels   []*Channel                        // For iteration with deterministic order
 
+	priorityChannels              []*Channel // The high priority channels, served before the others
+	lastUsedPriorityIndex         int
+	numConsecutivePriorityPackets int // High priority packets sent since the last default priority one
+
 	channelSelector ChannelSelector
 
 	config ChannelGroupConfig
@@ -48,10 +56,11 @@ func createChannelGroup(cgConfig ChannelGroupConfig, channels []*Channel) (bool,
 	}
 
 	channelGroup := ChannelGroup{
-		mutex:           &sync.Mutex{},
-		channelMap:      make(map[common.ChannelIDEnum]*Channel),
-		channelSelector: channelSelector,
-		config:          cgConfig,
+		mutex:                 &sync.Mutex{},
+		channelMap:            make(map[common.ChannelIDEnum]*Channel),
+		lastUsedPriorityIndex: -1,
+		channelSelector:       channelSelector,
+		config:                cgConfig,
 	}
 
 	for _, channel := range channels {
@@ -78,6 +87,9 @@ func (cg *ChannelGroup) addChannel(channel *Channel) bool {
 
 	cg.channelMap[channel.id] = channel
 	cg.channels = append(cg.channels, channel)
+	if channel.isHighPriority() {
+		cg.priorityChannels = append(cg.priorityChannels, channel)
+	}
 
 	return true
 }
@@ -97,6 +109,12 @@ func (cg *ChannelGroup) deleteChannel(channelID common.ChannelIDEnum) {
 			break
 		}
 	}
+	for idx, ch := range cg.priorityChannels {
+		if ch.id == channelID {
+			cg.priorityChannels = append(cg.priorityChannels[:idx], cg.priorityChannels[idx+1:]...)
+			break
+		}
+	}
 }
 
 func (cg *ChannelGroup) getChannel(channelID common.ChannelIDEnum) *Channel {
@@ -133,6 +151,13 @@ func (cg *ChannelGroup) getTotalNumChannels() uint {
 }
 
 func (cg *ChannelGroup) nextChannelToSendPacket() (success bool, channel *Channel) {
+	if cg.numConsecutivePriorityPackets < maxConsecutivePriorityPackets {
+		if priorityChannel := cg.nextPriorityChannelToSendPacket(); priorityChannel != nil {
+			cg.numConsecutivePriorityPackets++
+			return true, priorityChannel
+		}
+	}
+
 	channels := cg.getAllChannels()
 	totalNumberOfChannels := cg.getTotalNumChannels()
 	for i := uint(0); i < totalNumberOfChannels; i++ {
@@ -144,14 +169,37 @@ func (cg *ChannelGroup) nextChannelToSendPacket() (success bool, channel *Channe
 			return false, nil
 		}
 		selectedChannel := (*channels)[selectedChannelIndex]
-		if !selectedChannel.hasPacketToSend() {
+		if selectedChannel.isHighPriority() || !selectedChannel.hasPacketToSend() {
 			continue
 		}
+		cg.numConsecutivePriorityPackets = 0
 		return true, selectedChannel
 	}
+
+	// No default priority packets are pending, keep serving the high priority channels
+	if priorityChannel := cg.nextPriorityChannelToSendPacket(); priorityChannel != nil {
+		return true, priorityChannel
+	}
 	return true, nil
 }
 
+// nextPriorityChannelToSendPacket returns the next high priority channel with pending packets in
+// the round robin order, or nil if there is none
+func (cg *ChannelGroup) nextPriorityChannelToSendPacket() *Channel {
+	cg.mutex.Lock()
+	defer cg.mutex.Unlock()
+
+	numPriorityChannels := len(cg.priorityChannels)
+	for i := 1; i <= numPriorityChannels; i++ {
+		index := (cg.lastUsedPriorityIndex + i) % numPriorityChannels
+		if cg.priorityChannels[index].hasPacketToSend() {
+			cg.lastUsedPriorityIndex = index
+			return cg.priorityChannels[index]
+		}
+	}
+	return nil
+}
+
 //
 // RoundRobinChannelSelector implments the ChannelSelector interface
 // with the round robin strategy
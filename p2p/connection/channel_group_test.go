@@ -253,6 +253,100 @@ func TestRoundRobinChannelSelector2(t *testing.T) {
 	assert.Equal(&ch5, ch)
 }
 
+func TestPriorityChannelsSentFirst(t *testing.T) {
+	assert := assert.New(t)
+
+	cg := newTestEmptyChannelGroup()
+	connConfig := GetDefaultConnectionConfig()
+	ch1 := createConnectionChannel(common.ChannelIDBlock, connConfig)
+	ch2 := createConnectionChannel(common.ChannelIDProposal, connConfig)
+	ch3 := createConnectionChannel(common.ChannelIDVote, connConfig)
+	ch4 := createConnectionChannel(common.ChannelIDTransaction, connConfig)
+
+	assert.True(cg.addChannel(&ch1))
+	assert.True(cg.addChannel(&ch2))
+	assert.True(cg.addChannel(&ch3))
+	assert.True(cg.addChannel(&ch4))
+
+	assert.True(ch1.enqueueMessage([]byte("block")))
+	assert.True(ch4.enqueueMessage([]byte("tx")))
+	assert.True(ch3.enqueueMessage([]byte("vote")))
+
+	success, ch := cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch3, ch)
+	ch.sendBuf.emitPacket(ch.id)
+
+	// The priority channels are served round robin among themselves
+	assert.True(ch3.enqueueMessage([]byte("vote")))
+	assert.True(ch2.enqueueMessage([]byte("proposal")))
+
+	success, ch = cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch2, ch)
+	ch.sendBuf.emitPacket(ch.id)
+
+	success, ch = cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch3, ch)
+	ch.sendBuf.emitPacket(ch.id)
+
+	success, ch = cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch1, ch)
+	ch.sendBuf.emitPacket(ch.id)
+
+	success, ch = cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch4, ch)
+}
+
+func TestPriorityChannelsStarvationBound(t *testing.T) {
+	assert := assert.New(t)
+
+	cg := newTestEmptyChannelGroup()
+	connConfig := GetDefaultConnectionConfig()
+	connConfig.SendQueueCapacity = 2 * maxConsecutivePriorityPackets
+	ch1 := createConnectionChannel(common.ChannelIDBlock, connConfig)
+	ch2 := createConnectionChannel(common.ChannelIDVote, connConfig)
+	ch3 := createConnectionChannel(common.ChannelIDGuardian, connConfig)
+
+	assert.True(cg.addChannel(&ch1))
+	assert.True(cg.addChannel(&ch2))
+	assert.True(cg.addChannel(&ch3))
+	assert.True(ch3.isHighPriority())
+
+	for i := 0; i < maxConsecutivePriorityPackets+2; i++ {
+		assert.True(ch2.enqueueMessage([]byte("vote")))
+	}
+	assert.True(ch1.enqueueMessage([]byte("block")))
+
+	for i := 0; i < maxConsecutivePriorityPackets; i++ {
+		success, ch := cg.nextChannelToSendPacket()
+		assert.True(success)
+		assert.Equal(&ch2, ch)
+		ch.sendBuf.emitPacket(ch.id)
+	}
+
+	// The default priority channel is served once the bound is reached
+	success, ch := cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Equal(&ch1, ch)
+	ch.sendBuf.emitPacket(ch.id)
+
+	// The high priority channels keep being served when the others are idle
+	for i := 0; i < 2; i++ {
+		success, ch = cg.nextChannelToSendPacket()
+		assert.True(success)
+		assert.Equal(&ch2, ch)
+		ch.sendBuf.emitPacket(ch.id)
+	}
+
+	success, ch = cg.nextChannelToSendPacket()
+	assert.True(success)
+	assert.Nil(ch)
+}
+
 // --------------- Test Utilities --------------- //
 
 func newTestEmptyChannelGroup() ChannelGroup {
@@ -265,3 +359,22 @@ func newTestEmptyChannelGroup() ChannelGroup {
 
 	return dcg
 }
+
+func TestDropOldestOnlyOnBulkChannels(t *testing.T) {
+	assert := assert.New(t)
+
+	connConfig := GetDefaultConnectionConfig()
+	connConfig.SendQueuePolicy = SendQueuePolicyDropOldest
+	for _, channelID := range []common.ChannelIDEnum{common.ChannelIDBlock, common.ChannelIDHeader, common.ChannelIDTransaction} {
+		ch := createConnectionChannel(channelID, connConfig)
+		assert.True(ch.sendBuf.config.dropOldest)
+	}
+	for _, channelID := range []common.ChannelIDEnum{common.ChannelIDVote, common.ChannelIDProposal, common.ChannelIDCC} {
+		ch := createConnectionChannel(channelID, connConfig)
+		assert.False(ch.sendBuf.config.dropOldest)
+	}
+
+	connConfig.SendQueuePolicy = SendQueuePolicyBlock
+	ch := createConnectionChannel(common.ChannelIDBlock, connConfig)
+	assert.False(ch.sendBuf.config.dropOldest)
+}
@@ -22,11 +22,23 @@ import (
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "p2p"})
 
+// The global monitors are shared by all the connections to limit the aggregated flow rate
+var (
+	globalSendMonitor = flowrate.New(0, 0)
+	globalRecvMonitor = flowrate.New(0, 0)
+)
+
 //
 // Connection models the connection between the current node and a peer node.
 // A connection has a ChannelGroup which can contain multiple Channels
 //
 type Connection struct {
+	// Accessed atomically, keep them at the top of the struct for 64-bit alignment
+	bytesSent   uint64
+	bytesRecv   uint64
+	pingSentAt  int64 // unix nano of the last ping sent
+	pingLatency int64 // nanoseconds
+
 	netconn net.Conn
 
 	bufWriter   *bufio.Writer
@@ -37,12 +49,14 @@ type Connection struct {
 
 	bufConn io.ReadWriter
 
-	channelGroup ChannelGroup
-	onParse      MessageParser
-	onEncode     MessageEncoder
-	onReceive    ReceiveHandler
-	onError      ErrorHandler
-	errored      uint32
+	channelGroup   ChannelGroup
+	msgRateLimiter *msgRateLimiter
+	onParse        MessageParser
+	onEncode       MessageEncoder
+	onReceive      ReceiveHandler
+	onError        ErrorHandler
+	channelFilter  ChannelFilter
+	errored        uint32
 
 	sendPulse chan bool
 	pongPulse chan bool
@@ -70,13 +84,24 @@ type Connection struct {
 // ConnectionConfig specifies the configurations of the Connection
 //
 type ConnectionConfig struct {
-	SendRate        int64
-	RecvRate        int64
-	PacketBatchSize int64
-	FlushThrottle   time.Duration
-	PingTimeout     time.Duration
-	MaxPendingPings uint32
-}
+	SendRate             int64
+	RecvRate             int64
+	GlobalSendRate       int64
+	GlobalRecvRate       int64
+	MaxMsgRatePerChannel int64
+	PacketBatchSize      int64
+	FlushThrottle        time.Duration
+	PingTimeout          time.Duration
+	MaxPendingPings      uint32
+	SendQueueCapacity    int    // max number of messages queued per channel
+	SendQueuePolicy      string // SendQueuePolicyBlock or SendQueuePolicyDropOldest
+}
+
+// The policies applied when a message is sent to a channel whose send queue is full
+const (
+	SendQueuePolicyBlock      = "block"      // wait for the queue until the timeout
+	SendQueuePolicyDropOldest = "dropOldest" // evict the oldest queued message
+)
 
 // MessageParser parses the raw message bytes to type p2ptypes.Message
 type MessageParser func(channelID common.ChannelIDEnum, rawMessageBytes common.Bytes) (p2ptypes.Message, error)
@@ -94,24 +119,29 @@ type ReceiveHandler func(message p2ptypes.Message) error
 // ErrorHandler is the callback function to handle channel read errors
 type ErrorHandler func(interface{})
 
+// ChannelFilter returns whether the messages on the given channel are accepted, i.e. whether the
+// channel has been negotiated with the peer
+type ChannelFilter func(channelID common.ChannelIDEnum) bool
+
 // CreateConnection creates a Connection instance
 func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 	if netconn != nil {
 		logger.Debugf("Create connection, local: %v, remote: %v", netconn.LocalAddr(), netconn.RemoteAddr())
 	}
 
-	channelCheckpoint := createDefaultChannel(common.ChannelIDCheckpoint)
-	channelHeader := createDefaultChannel(common.ChannelIDHeader)
-	channelBlock := createDefaultChannel(common.ChannelIDBlock)
-	channelProposal := createDefaultChannel(common.ChannelIDProposal)
-	channelVote := createDefaultChannel(common.ChannelIDVote)
-	channelTransaction := createDefaultChannel(common.ChannelIDTransaction)
-	channelPeerDiscover := createDefaultChannel(common.ChannelIDPeerDiscovery)
-	channelPing := createDefaultChannel(common.ChannelIDPing)
-	channelGuardian := createDefaultChannel(common.ChannelIDGuardian)
-	channelNATMapping := createDefaultChannel(common.ChannelIDNATMapping)
-	channelEliteEdgeNodeVote := createDefaultChannel(common.ChannelIDEliteEdgeNodeVote)
-	channelEliteAggregatedEdgeNodeVotes := createDefaultChannel(common.ChannelIDAggregatedEliteEdgeNodeVotes)
+	channelCheckpoint := createConnectionChannel(common.ChannelIDCheckpoint, config)
+	channelHeader := createConnectionChannel(common.ChannelIDHeader, config)
+	channelBlock := createConnectionChannel(common.ChannelIDBlock, config)
+	channelProposal := createConnectionChannel(common.ChannelIDProposal, config)
+	channelVote := createConnectionChannel(common.ChannelIDVote, config)
+	channelTransaction := createConnectionChannel(common.ChannelIDTransaction, config)
+	channelPeerDiscover := createConnectionChannel(common.ChannelIDPeerDiscovery, config)
+	channelPing := createConnectionChannel(common.ChannelIDPing, config)
+	channelGuardian := createConnectionChannel(common.ChannelIDGuardian, config)
+	channelNATMapping := createConnectionChannel(common.ChannelIDNATMapping, config)
+	channelEliteEdgeNodeVote := createConnectionChannel(common.ChannelIDEliteEdgeNodeVote, config)
+	channelEliteAggregatedEdgeNodeVotes := createConnectionChannel(common.ChannelIDAggregatedEliteEdgeNodeVotes, config)
+	channelSnapshot := createConnectionChannel(common.ChannelIDSnapshot, config)
 	channels := []*Channel{
 		&channelCheckpoint,
 		&channelHeader,
@@ -125,6 +155,7 @@ func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 		&channelNATMapping,
 		&channelEliteEdgeNodeVote,
 		&channelEliteAggregatedEdgeNodeVotes,
+		&channelSnapshot,
 	}
 
 	success, channelGroup := createChannelGroup(getDefaultChannelGroupConfig(), channels)
@@ -133,19 +164,20 @@ func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 	}
 
 	conn := &Connection{
-		netconn:      netconn,
-		bufWriter:    bufio.NewWriter(netconn),
-		sendMonitor:  flowrate.New(0, 0),
-		bufReader:    bufio.NewReader(netconn),
-		recvMonitor:  flowrate.New(0, 0),
-		channelGroup: channelGroup,
-		sendPulse:    make(chan bool, 1),
-		pongPulse:    make(chan bool, 1),
-		quitPulse:    make(chan bool, 1),
-		flushTimer:   timer.NewThrottleTimer("flush", config.FlushThrottle),
-		pingTimer:    timer.NewRepeatTimer("ping", config.PingTimeout),
-		config:       config,
-		wg:           &sync.WaitGroup{},
+		netconn:        netconn,
+		bufWriter:      bufio.NewWriter(netconn),
+		sendMonitor:    flowrate.New(0, 0),
+		bufReader:      bufio.NewReader(netconn),
+		recvMonitor:    flowrate.New(0, 0),
+		channelGroup:   channelGroup,
+		msgRateLimiter: newMsgRateLimiter(config.MaxMsgRatePerChannel),
+		sendPulse:      make(chan bool, 1),
+		pongPulse:      make(chan bool, 1),
+		quitPulse:      make(chan bool, 1),
+		flushTimer:     timer.NewThrottleTimer("flush", config.FlushThrottle),
+		pingTimer:      timer.NewRepeatTimer("ping", config.PingTimeout),
+		config:         config,
+		wg:             &sync.WaitGroup{},
 
 		onEncode: defaultMessageEncoder,
 	}
@@ -159,12 +191,17 @@ func CreateConnection(netconn net.Conn, config ConnectionConfig) *Connection {
 // GetDefaultConnectionConfig returns the default ConnectionConfig
 func GetDefaultConnectionConfig() ConnectionConfig {
 	return ConnectionConfig{
-		SendRate:        int64(512000), // 500KB/s
-		RecvRate:        int64(512000), // 500KB/s
-		PacketBatchSize: int64(10),
-		FlushThrottle:   100 * time.Millisecond,
-		PingTimeout:     40 * time.Second,
-		MaxPendingPings: 3,
+		SendRate:             int64(512000),   // 500KB/s
+		RecvRate:             int64(10240000), // 10MB/s
+		GlobalSendRate:       int64(0),        // no limit
+		GlobalRecvRate:       int64(0),        // no limit
+		MaxMsgRatePerChannel: int64(0),        // no limit
+		PacketBatchSize:      int64(10),
+		FlushThrottle:        100 * time.Millisecond,
+		PingTimeout:          40 * time.Second,
+		MaxPendingPings:      3,
+		SendQueueCapacity:    1,
+		SendQueuePolicy:      SendQueuePolicyBlock,
 	}
 }
 
@@ -237,6 +274,12 @@ func (conn *Connection) SetErrorHandler(errorHandler ErrorHandler) {
 	conn.onError = errorHandler
 }
 
+// SetChannelFilter sets the filter of the channels accepted by the connection. All the channels
+// known to the node are accepted if not set.
+func (conn *Connection) SetChannelFilter(channelFilter ChannelFilter) {
+	conn.channelFilter = channelFilter
+}
+
 // EnqueueMessage enqueues the given message to the target channel.
 // The message will be sent out later
 func (conn *Connection) EnqueueMessage(channelID common.ChannelIDEnum, message interface{}) bool {
@@ -252,9 +295,7 @@ func (conn *Connection) EnqueueMessage(channelID common.ChannelIDEnum, message i
 		return false
 	}
 	success := channel.enqueueMessage(msgBytes)
-	if success {
-		conn.scheduleSendPulse()
-	}
+	conn.scheduleSendPulse() // the message is queued even if it evicted an older one
 
 	return success
 }
@@ -348,6 +389,7 @@ func (conn *Connection) sendPingSignal() error {
 	}
 	conn.sendMonitor.Update(int(1))
 	conn.flush()
+	atomic.StoreInt64(&conn.pingSentAt, time.Now().UnixNano())
 	atomic.AddUint32(&conn.pendingPings, 1)
 	return nil
 }
@@ -376,29 +418,38 @@ func (conn *Connection) sendPacketBatchAndScheduleSendPulse() {
 
 // --------------------- Recv goroutine --------------------- //
 
-func (conn *Connection) readPacket() (*Packet, error) {
+func (conn *Connection) readPacket() (packet *Packet, err error) {
 	// Plaintext transport.
 	if conn.rw == nil {
-		packet := &Packet{}
+		packet = &Packet{}
 		s := rlp.NewStream(conn.bufReader, maxPayloadSize*1024)
-		err := s.Decode(packet)
-		return packet, err
+		err = s.Decode(packet)
+	} else {
+		// Encrypted transport.
+		conn.rmu.Lock()
+		packet, err = conn.rw.ReadPacket()
+		conn.rmu.Unlock()
+	}
+	if err == nil && packet != nil {
+		atomic.AddUint64(&conn.bytesRecv, uint64(len(packet.Bytes)))
 	}
-	// Encrypted transport.
-	conn.rmu.Lock()
-	defer conn.rmu.Unlock()
-	return conn.rw.ReadPacket()
+	return packet, err
 }
 
-func (conn *Connection) writePacket(packet *Packet) error {
+func (conn *Connection) writePacket(packet *Packet) (err error) {
 	// Plaintext transport.
 	if conn.rw == nil {
-		return rlp.Encode(conn.bufWriter, packet)
+		err = rlp.Encode(conn.bufWriter, packet)
+	} else {
+		// Encrypted transport.
+		conn.wmu.Lock()
+		err = conn.rw.WritePacket(packet)
+		conn.wmu.Unlock()
+	}
+	if err == nil {
+		atomic.AddUint64(&conn.bytesSent, uint64(len(packet.Bytes)))
 	}
-	// Encrypted transport.
-	conn.wmu.Lock()
-	defer conn.wmu.Unlock()
-	return conn.rw.WritePacket(packet)
+	return err
 }
 
 func (conn *Connection) recvRoutine() {
@@ -415,15 +466,18 @@ func (conn *Connection) recvRoutine() {
 		default:
 		}
 
-		// Block until recvMonitor allows reading
+		// Block until recvMonitor and globalRecvMonitor allow reading
 		conn.recvMonitor.Limit(maxPacketTotalSize, atomic.LoadInt64(&conn.config.RecvRate), true)
+		globalRecvMonitor.Limit(maxPacketTotalSize, atomic.LoadInt64(&conn.config.GlobalRecvRate), true)
 
 		packet, err := conn.readPacket()
 		if err != nil {
 			logger.Warnf("recvRoutine: failed to decode packet: %v, error: %v", packet, err)
 			return
 		}
-		conn.recvMonitor.Update(int(1))
+		numBytes := len(packet.Bytes) + 1 // at least one byte per packet
+		conn.recvMonitor.Update(numBytes)
+		globalRecvMonitor.Update(numBytes)
 		switch packet.ChannelID {
 		case common.ChannelIDPing:
 			conn.handlePingPong(packet)
@@ -451,7 +505,9 @@ func (conn *Connection) handlePingPong(packet *Packet) (success bool) {
 	case p2ptypes.PingSignal:
 		conn.schedulePongPulse()
 	case p2ptypes.PongSignal:
-		// do nothing for now
+		if sentAt := atomic.LoadInt64(&conn.pingSentAt); sentAt > 0 {
+			atomic.StoreInt64(&conn.pingLatency, time.Now().UnixNano()-sentAt)
+		}
 	default:
 		logger.Errorf("Invalid Ping/Pong signal")
 		return false
@@ -463,7 +519,13 @@ func (conn *Connection) handlePingPong(packet *Packet) (success bool) {
 func (conn *Connection) handleReceivedPacket(packet *Packet) (success bool) {
 	channelID := packet.ChannelID
 	channel := conn.channelGroup.getChannel(channelID)
-	if channel == nil {
+	if channel == nil || (conn.channelFilter != nil && !conn.channelFilter(channelID)) {
+		// A peer only sends on the channels negotiated during the handshake, even if it runs a
+		// newer release with more channels
+		logger.Debugf("Rejected packet of unnegotiated channel %v from %v", channelID, conn.netconn.RemoteAddr())
+		if channel != nil {
+			channel.metrics.recvRejected.Inc(1)
+		}
 		return false
 	}
 
@@ -476,15 +538,25 @@ func (conn *Connection) handleReceivedPacket(packet *Packet) (success bool) {
 		return true
 	}
 
+	if !conn.msgRateLimiter.allow(channelID) {
+		logger.Debugf("Dropped message from %v, channel %v exceeded the message rate limit", conn.netconn.RemoteAddr(), channelID)
+		channel.metrics.recvRejected.Inc(1)
+		return false
+	}
+
 	message, err := conn.onParse(packet.ChannelID, aggregatedBytes)
 	if err != nil {
 		logger.Errorf("Error parsing packet: %v, err: %v", packet, err)
+		channel.metrics.recvRejected.Inc(1)
 		return false
 	}
 
+	start := time.Now()
 	err = conn.onReceive(message)
+	channel.metrics.recvHandle.UpdateSince(start)
 	if err != nil {
 		logger.Debugf("Error handling message: %v, err: %v", message, err)
+		channel.metrics.recvRejected.Inc(1)
 		return false
 	}
 
@@ -499,8 +571,9 @@ func (conn *Connection) flush() error {
 }
 
 func (conn *Connection) sendPacketBatch() (success bool, exhausted bool) {
-	// Block until sendMonitor allows sending
+	// Block until sendMonitor and globalSendMonitor allow sending
 	conn.sendMonitor.Limit(maxPacketTotalSize, atomic.LoadInt64(&conn.config.SendRate), true)
+	globalSendMonitor.Limit(maxPacketTotalSize, atomic.LoadInt64(&conn.config.GlobalSendRate), true)
 
 	// Now send out the packet batch
 	packetBatchSize := conn.config.PacketBatchSize
@@ -537,6 +610,7 @@ func (conn *Connection) sendPacket() (success bool, exhausted bool) {
 	}
 
 	conn.sendMonitor.Update(numBytes)
+	globalSendMonitor.Update(numBytes)
 	conn.flushTimer.Set()
 
 	return true, false
@@ -559,6 +633,21 @@ func (conn *Connection) GetBufReader() *bufio.Reader {
 	return conn.bufReader
 }
 
+// GetBytesSent returns the total number of payload bytes sent through the connection
+func (conn *Connection) GetBytesSent() uint64 {
+	return atomic.LoadUint64(&conn.bytesSent)
+}
+
+// GetBytesReceived returns the total number of payload bytes received through the connection
+func (conn *Connection) GetBytesReceived() uint64 {
+	return atomic.LoadUint64(&conn.bytesRecv)
+}
+
+// GetLatency returns the most recent ping round trip time, or zero if not yet measured
+func (conn *Connection) GetLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&conn.pingLatency))
+}
+
 func (conn *Connection) stopForError(r interface{}) {
 	logger.Warnf("Connection error: %v", r)
 	if atomic.CompareAndSwapUint32(&conn.errored, 0, 1) {
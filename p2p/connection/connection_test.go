@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io/ioutil"
+	"net"
 	"testing"
 	"time"
 
@@ -282,3 +283,71 @@ func TestConnectionRecvNodeInfo(t *testing.T) {
 		assert.True(resultMatched)
 	}
 }
+
+func TestConnectionByteCounters(t *testing.T) {
+	assert := assert.New(t)
+	msgBytes := []byte("Hello world")
+	port := 43256
+
+	sent := make(chan uint64)
+	go func() {
+		netconn := p2ptypes.GetTestNetconn(port)
+		defer netconn.Close()
+		cfg := GetDefaultConnectionConfig()
+		conn := CreateConnection(netconn, cfg)
+		packet := &Packet{
+			ChannelID: common.ChannelIDTransaction,
+			Bytes:     msgBytes,
+			IsEOF:     byte(0x01),
+		}
+		err := conn.writePacket(packet)
+		assert.Nil(err)
+		conn.flush()
+		sent <- conn.GetBytesSent()
+	}()
+
+	listener := p2ptypes.GetTestListener(port)
+	netconn, err := listener.Accept()
+	assert.Nil(err)
+	defer netconn.Close()
+
+	cfg := GetDefaultConnectionConfig()
+	conn := CreateConnection(netconn, cfg)
+	packet, err := conn.readPacket()
+	assert.Nil(err)
+	assert.Equal(msgBytes, packet.Bytes)
+
+	assert.Equal(uint64(len(msgBytes)), <-sent)
+	assert.Equal(uint64(len(msgBytes)), conn.GetBytesReceived())
+	assert.Equal(uint64(0), conn.GetBytesSent())
+	assert.Equal(time.Duration(0), conn.GetLatency())
+}
+
+func TestConnectionRejectsUnnegotiatedChannels(t *testing.T) {
+	assert := assert.New(t)
+
+	netconn, peerconn := net.Pipe()
+	defer netconn.Close()
+	defer peerconn.Close()
+
+	conn := CreateConnection(netconn, GetDefaultConnectionConfig())
+	received := 0
+	conn.SetMessageParser(func(channelID common.ChannelIDEnum, rawMessageBytes common.Bytes) (p2ptypes.Message, error) {
+		return p2ptypes.Message{ChannelID: channelID, Content: rawMessageBytes}, nil
+	})
+	conn.SetReceiveHandler(func(message p2ptypes.Message) error {
+		received++
+		return nil
+	})
+	conn.SetChannelFilter(func(channelID common.ChannelIDEnum) bool {
+		return channelID != common.ChannelIDSnapshot
+	})
+
+	newPacket := func(channelID common.ChannelIDEnum) *Packet {
+		return &Packet{ChannelID: channelID, Bytes: []byte("Hello world"), IsEOF: byte(0x01)}
+	}
+	assert.True(conn.handleReceivedPacket(newPacket(common.ChannelIDTransaction)))
+	assert.False(conn.handleReceivedPacket(newPacket(common.ChannelIDSnapshot)))   // not negotiated
+	assert.False(conn.handleReceivedPacket(newPacket(common.ChannelIDEnum(0xff)))) // unknown
+	assert.Equal(1, received)
+}
@@ -0,0 +1,64 @@
+package connection
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/metrics"
+)
+
+//
+// channelMetrics instruments the messages of a channel, aggregated over the connections
+// with all the peers
+//
+type channelMetrics struct {
+	sendDepth    metrics.Histogram // number of messages ahead in the send queue upon insertion
+	sendMessages metrics.Counter   // messages fully emitted
+	sendDropped  metrics.Counter   // messages evicted by the drop-oldest policy
+	sendTimeouts metrics.Counter   // messages rejected after waiting for the full send queue
+	recvMessages metrics.Counter   // messages fully assembled
+	recvDepth    metrics.Histogram // number of packets buffered to assemble each message
+	recvRejected metrics.Counter   // messages rejected as out of sequence, above the rate limit, unparsable or failed to handle
+	recvHandle   metrics.Timer     // time spent handling the received messages
+}
+
+var (
+	channelMetricsLock sync.Mutex
+	channelMetricsMap  = make(map[common.ChannelIDEnum]*channelMetrics)
+
+	// nilChannelMetrics is used by the buffers created outside of a channel
+	nilChannelMetrics = &channelMetrics{
+		sendDepth:    metrics.NilHistogram{},
+		sendMessages: metrics.NilCounter{},
+		sendDropped:  metrics.NilCounter{},
+		sendTimeouts: metrics.NilCounter{},
+		recvMessages: metrics.NilCounter{},
+		recvDepth:    metrics.NilHistogram{},
+		recvRejected: metrics.NilCounter{},
+		recvHandle:   metrics.NilTimer{},
+	}
+)
+
+// getChannelMetrics returns the metrics of the channel, registering them upon the first call
+func getChannelMetrics(channelID common.ChannelIDEnum) *channelMetrics {
+	channelMetricsLock.Lock()
+	defer channelMetricsLock.Unlock()
+
+	if cm, ok := channelMetricsMap[channelID]; ok {
+		return cm
+	}
+	prefix := fmt.Sprintf("p2p/channel/%d/", channelID)
+	cm := &channelMetrics{
+		sendDepth:    metrics.NewRegisteredHistogram(prefix+"send/depth", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		sendMessages: metrics.NewRegisteredCounter(prefix+"send/messages", nil),
+		sendDropped:  metrics.NewRegisteredCounter(prefix+"send/dropped", nil),
+		sendTimeouts: metrics.NewRegisteredCounter(prefix+"send/timeouts", nil),
+		recvMessages: metrics.NewRegisteredCounter(prefix+"recv/messages", nil),
+		recvDepth:    metrics.NewRegisteredHistogram(prefix+"recv/depth", nil, metrics.NewExpDecaySample(1028, 0.015)),
+		recvRejected: metrics.NewRegisteredCounter(prefix+"recv/rejected", nil),
+		recvHandle:   metrics.NewRegisteredTimer(prefix+"recv/handle", nil),
+	}
+	channelMetricsMap[channelID] = cm
+	return cm
+}
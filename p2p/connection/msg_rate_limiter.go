@@ -0,0 +1,68 @@
+package connection
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+)
+
+//
+// msgRateLimiter limits the number of messages per second received on each channel.
+// Each channel has a token bucket which holds up to one second worth of messages
+//
+type msgRateLimiter struct {
+	mutex   *sync.Mutex
+	rate    float64 // messages per second, non-positive means no limit
+	buckets map[common.ChannelIDEnum]*msgTokenBucket
+}
+
+type msgTokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newMsgRateLimiter(rate int64) *msgRateLimiter {
+	return &msgRateLimiter{
+		mutex:   &sync.Mutex{},
+		rate:    float64(rate),
+		buckets: make(map[common.ChannelIDEnum]*msgTokenBucket),
+	}
+}
+
+// allow indicates whether one more message can be accepted on the given channel
+func (mrl *msgRateLimiter) allow(channelID common.ChannelIDEnum) bool {
+	return mrl.allowAt(channelID, time.Now())
+}
+
+func (mrl *msgRateLimiter) allowAt(channelID common.ChannelIDEnum, now time.Time) bool {
+	if mrl.rate <= 0 {
+		return true
+	}
+
+	mrl.mutex.Lock()
+	defer mrl.mutex.Unlock()
+
+	bucket, exists := mrl.buckets[channelID]
+	if !exists {
+		bucket = &msgTokenBucket{
+			tokens:     mrl.rate,
+			lastRefill: now,
+		}
+		mrl.buckets[channelID] = bucket
+	}
+
+	if elapsed := now.Sub(bucket.lastRefill).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * mrl.rate
+		if bucket.tokens > mrl.rate {
+			bucket.tokens = mrl.rate
+		}
+		bucket.lastRefill = now
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
@@ -0,0 +1,44 @@
+package connection
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestMsgRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	mrl := newMsgRateLimiter(2)
+	now := time.Now()
+
+	assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	assert.False(mrl.allowAt(common.ChannelIDBlock, now))
+
+	// Channels are limited independently
+	assert.True(mrl.allowAt(common.ChannelIDHeader, now))
+
+	// Half a second refills one token
+	now = now.Add(500 * time.Millisecond)
+	assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	assert.False(mrl.allowAt(common.ChannelIDBlock, now))
+
+	// The bucket never holds more than one second worth of messages
+	now = now.Add(10 * time.Second)
+	assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	assert.False(mrl.allowAt(common.ChannelIDBlock, now))
+}
+
+func TestMsgRateLimiterNoLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	mrl := newMsgRateLimiter(0)
+	now := time.Now()
+	for i := 0; i < 1000; i++ {
+		assert.True(mrl.allowAt(common.ChannelIDBlock, now))
+	}
+}
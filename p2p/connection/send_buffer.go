@@ -14,11 +14,14 @@ type SendBuffer struct {
 
 	config  SendBufferConfig
 	chanSeq uint
+
+	metrics *channelMetrics
 }
 
 type SendBufferConfig struct {
 	queueCapacity int
 	timeOut       time.Duration
+	dropOldest    bool // evict the oldest queued message rather than waiting when the queue is full
 }
 
 // createSendBuffer creates a SendBuffer instance for the given config
@@ -27,6 +30,7 @@ func createSendBuffer(config SendBufferConfig) SendBuffer {
 		workspace: make([]byte, 0),
 		queue:     make(chan []byte, config.queueCapacity),
 		config:    config,
+		metrics:   nilChannelMetrics,
 	}
 }
 
@@ -35,6 +39,7 @@ func getDefaultSendBufferConfig() SendBufferConfig {
 	return SendBufferConfig{
 		queueCapacity: 1,
 		timeOut:       10 * time.Second,
+		dropOldest:    false,
 	}
 }
 
@@ -55,17 +60,50 @@ func (sb *SendBuffer) canInsert() bool {
 }
 
 // Insert insert the bytes to queue, and times out after
-// the configured timeout. It is goroutine safe
+// the configured timeout. With the drop-oldest policy, the oldest
+// queued bytes are evicted instead of waiting, and it returns false
+// if any bytes were evicted. It is goroutine safe
 func (sb *SendBuffer) insert(bytes []byte) bool {
+	if sb.config.dropOldest {
+		evicted := sb.insertDropOldest(bytes)
+		return !evicted
+	}
+
+	sb.metrics.sendDepth.Update(int64(sb.getSize()))
 	select {
 	case sb.queue <- bytes:
 		atomic.AddInt32(&sb.queueSize, 1)
 		return true
 	case <-time.After(sb.config.timeOut):
+		sb.metrics.sendTimeouts.Inc(1)
 		return false
 	}
 }
 
+// insertDropOldest inserts the bytes to the queue, evicting the oldest
+// queued bytes while the queue is full, and returns whether any bytes
+// were evicted. The message being emitted is never evicted. It is
+// goroutine safe
+func (sb *SendBuffer) insertDropOldest(bytes []byte) (evicted bool) {
+	sb.metrics.sendDepth.Update(int64(sb.getSize()))
+	for {
+		select {
+		case sb.queue <- bytes:
+			atomic.AddInt32(&sb.queueSize, 1)
+			return evicted
+		default:
+		}
+
+		select {
+		case <-sb.queue:
+			atomic.AddInt32(&sb.queueSize, -1)
+			sb.metrics.sendDropped.Inc(1)
+			evicted = true
+		default:
+		}
+	}
+}
+
 // attemptInsert attempts to insert bytes into the queue. It is a
 // non-blocking call. It is goroutine safe
 func (sb *SendBuffer) attemptInsert(bytes []byte) bool {
@@ -81,9 +119,11 @@ func (sb *SendBuffer) attemptInsert(bytes []byte) bool {
 // EmitPacket emits a packet extracted from the bytes stored in the workspace
 func (sb *SendBuffer) emitPacket(channelID common.ChannelIDEnum) Packet {
 	if sb.workspace == nil || len(sb.workspace) == 0 {
-		if len(sb.queue) > 0 {
-			sb.workspace = <-sb.queue // update workspace if necessary
-		} else {
+		// update workspace if necessary. The receive does not block since the
+		// queued bytes might have been evicted by the drop-oldest policy
+		select {
+		case sb.workspace = <-sb.queue:
+		default:
 			return Packet{
 				ChannelID: channelID,
 				Bytes:     nil,
@@ -101,6 +141,7 @@ func (sb *SendBuffer) emitPacket(channelID common.ChannelIDEnum) Packet {
 		sb.workspace = nil
 		sb.chanSeq = 0                     // reset sequence id
 		atomic.AddInt32(&sb.queueSize, -1) // decrement queueSize
+		sb.metrics.sendMessages.Inc(1)
 	} else {
 		bytes = sb.workspace[:maxPayloadSize]
 		isEOF = byte(0x00)
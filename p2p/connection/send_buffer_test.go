@@ -195,6 +195,52 @@ func TestAttemptInsert(t *testing.T) {
 	assert.Equal(uint(0), packet.SeqID)
 }
 
+func TestSendBufferDropOldest(t *testing.T) {
+	assert := assert.New(t)
+	config := getDefaultSendBufferConfig()
+	config.queueCapacity = 2
+	config.dropOldest = true
+	dsb := createSendBuffer(config)
+
+	assert.True(dsb.insert([]byte("msg1")))
+	assert.True(dsb.insert([]byte("msg2")))
+	assert.False(dsb.canInsert())
+
+	// The oldest message is evicted rather than waiting for the timeout
+	assert.False(dsb.insert([]byte("msg3")))
+	assert.Equal(2, dsb.getSize())
+
+	packet := dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal([]byte("msg2"), packet.Bytes)
+
+	// The message being emitted is never evicted
+	var msgStr string
+	for i := 0; i < 300; i++ {
+		msgStr = msgStr + "0123456789"
+	}
+	assert.True(dsb.insert([]byte(msgStr)))
+	packet = dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal([]byte("msg3"), packet.Bytes)
+	packet1 := dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal(byte(0x00), packet1.IsEOF)
+
+	assert.True(dsb.insert([]byte("msg4")))
+	assert.True(dsb.insert([]byte("msg5")))
+	assert.False(dsb.insert([]byte("msg6"))) // evicts msg4
+	assert.Equal(3, dsb.getSize())
+
+	packet2 := dsb.emitPacket(common.ChannelIDTransaction)
+	packet3 := dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal(byte(0x01), packet3.IsEOF)
+	assert.Equal(msgStr, string(packet1.Bytes)+string(packet2.Bytes)+string(packet3.Bytes))
+	packet = dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal([]byte("msg5"), packet.Bytes)
+	packet = dsb.emitPacket(common.ChannelIDTransaction)
+	assert.Equal([]byte("msg6"), packet.Bytes)
+	assert.True(dsb.isEmpty())
+	assert.Equal(0, dsb.getSize())
+}
+
 // --------------- Test Utilities --------------- //
 
 func newTestDefaultSendBuffer() SendBuffer {
@@ -0,0 +1,100 @@
+package messenger
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/thetatoken/theta/p2p/netutil"
+)
+
+// The DNS seeds are the seed entries without a port, e.g. seed.thetatoken.org. Their A/AAAA
+// records list the IPs of the seed peers, which listen on the same port as the local node. Their
+// TXT records list the comma or space separated seed peer addresses, i.e. ip or ip:port. The DNS
+// seeds are resolved again periodically, so that the seed peers can be changed without updating
+// the config of every node.
+
+// isDNSSeed returns whether the seed entry is a DNS seed rather than a network address
+func isDNSSeed(seed string) bool {
+	if _, _, err := net.SplitHostPort(seed); err == nil {
+		return false
+	}
+	return len(seed) > 0 && net.ParseIP(seed) == nil
+}
+
+//
+// dnsSeedResolver resolves the DNS seeds into the seed peer addresses
+//
+type dnsSeedResolver struct {
+	defaultPort uint16
+
+	lookupIP  func(host string) ([]net.IP, error)
+	lookupTXT func(host string) ([]string, error)
+}
+
+func newDNSSeedResolver(defaultPort uint16) *dnsSeedResolver {
+	return &dnsSeedResolver{
+		defaultPort: defaultPort,
+		lookupIP:    net.LookupIP,
+		lookupTXT:   net.LookupTXT,
+	}
+}
+
+// resolve returns the deduplicated seed peer addresses listed by the A/AAAA and the TXT records
+// of the DNS seed. It fails only if neither kind of records can be resolved.
+func (r *dnsSeedResolver) resolve(seed string) ([]netutil.NetAddress, error) {
+	addrs := []netutil.NetAddress{}
+	seen := make(map[string]bool)
+	add := func(addr *netutil.NetAddress) {
+		if !seen[addr.String()] {
+			seen[addr.String()] = true
+			addrs = append(addrs, *addr)
+		}
+	}
+
+	ips, ipErr := r.lookupIP(seed)
+	for _, ip := range ips {
+		add(netutil.NewNetAddressIPPort(ip, r.defaultPort))
+	}
+
+	records, txtErr := r.lookupTXT(seed)
+	for _, record := range records {
+		entries := strings.FieldsFunc(record, func(c rune) bool {
+			return c == ',' || c == ' ' || c == '\t'
+		})
+		for _, entry := range entries {
+			addr, ok := r.parseTXTEntry(entry)
+			if !ok {
+				logger.Debugf("Ignored invalid entry %v in the TXT records of DNS seed %v", entry, seed)
+				continue
+			}
+			add(addr)
+		}
+	}
+
+	if ipErr != nil && txtErr != nil {
+		return nil, ipErr
+	}
+	return addrs, nil
+}
+
+// parseTXTEntry parses an ip or ip:port entry of a TXT record. Host names are not accepted, to
+// avoid resolving further records.
+func (r *dnsSeedResolver) parseTXTEntry(entry string) (*netutil.NetAddress, bool) {
+	if ip := net.ParseIP(entry); ip != nil {
+		return netutil.NewNetAddressIPPort(ip, r.defaultPort), true
+	}
+	host, portStr, err := net.SplitHostPort(entry)
+	if err != nil {
+		return nil, false
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, false
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, false
+	}
+	return netutil.NewNetAddressIPPort(ip, uint16(port)), true
+}
@@ -0,0 +1,54 @@
+package messenger
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDNSSeed(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(isDNSSeed("seed.thetatoken.org"))
+	assert.False(isDNSSeed("seed.thetatoken.org:21000"))
+	assert.False(isDNSSeed("127.0.0.1:21000"))
+	assert.False(isDNSSeed("127.0.0.1"))
+	assert.False(isDNSSeed("::1"))
+	assert.False(isDNSSeed(""))
+}
+
+func TestDNSSeedResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	resolver := newDNSSeedResolver(21000)
+	resolver.lookupIP = func(host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("fd00::1")}, nil
+	}
+	resolver.lookupTXT = func(host string) ([]string, error) {
+		return []string{"10.0.0.2:22000, 10.0.0.1 not-an-ip:1", "[fd00::2]:23000"}, nil
+	}
+
+	addrs, err := resolver.resolve("seed.thetatoken.org")
+	assert.Nil(err)
+	strs := []string{}
+	for _, addr := range addrs {
+		strs = append(strs, addr.String())
+	}
+	assert.Equal([]string{"10.0.0.1:21000", "[fd00::1]:21000", "10.0.0.2:22000", "[fd00::2]:23000"}, strs)
+
+	// Either kind of records is sufficient
+	resolver.lookupIP = func(host string) ([]net.IP, error) {
+		return nil, errors.New("no such host")
+	}
+	addrs, err = resolver.resolve("seed.thetatoken.org")
+	assert.Nil(err)
+	assert.Equal(3, len(addrs))
+
+	resolver.lookupTXT = func(host string) ([]string, error) {
+		return nil, errors.New("no such host")
+	}
+	_, err = resolver.resolve("seed.thetatoken.org")
+	assert.NotNil(err)
+}
@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/p2p/netutil"
 )
 
@@ -23,7 +25,14 @@ type SeedPeerConnector struct {
 	discMgr *PeerDiscoveryManager
 
 	selfNetAddress       netutil.NetAddress
-	seedPeerNetAddresses []netutil.NetAddress
+	seedPeerNetAddresses []netutil.NetAddress // the static and the resolved DNS seed peers
+	seedMutex            *sync.RWMutex
+
+	staticSeedPeerNetAddresses []netutil.NetAddress
+	dnsSeeds                   []string
+	dnsSeedPeerNetAddresses    map[string][]netutil.NetAddress // map: DNS seed |-> last resolved addresses
+	dnsSeedResolver            *dnsSeedResolver
+	dnsSeedRefreshInterval     time.Duration
 
 	Connected chan bool
 
@@ -40,9 +49,12 @@ func createSeedPeerConnector(discMgr *PeerDiscoveryManager,
 	selfNetAddressStr string, seedPeerNetAddressStrs []string) (SeedPeerConnector, error) {
 	numSeedPeers := len(seedPeerNetAddressStrs)
 	spc := SeedPeerConnector{
-		discMgr:   discMgr,
-		Connected: make(chan bool, numSeedPeers),
-		wg:        &sync.WaitGroup{},
+		discMgr:                 discMgr,
+		seedMutex:               &sync.RWMutex{},
+		dnsSeedPeerNetAddresses: make(map[string][]netutil.NetAddress),
+		dnsSeedRefreshInterval:  time.Duration(viper.GetInt(common.CfgP2PDNSSeedRefreshInterval)) * time.Second,
+		Connected:               make(chan bool, numSeedPeers),
+		wg:                      &sync.WaitGroup{},
 	}
 
 	selfNetAddress, err := netutil.NewNetAddressString(selfNetAddressStr)
@@ -51,8 +63,13 @@ func createSeedPeerConnector(discMgr *PeerDiscoveryManager,
 		return spc, err
 	}
 	spc.selfNetAddress = *selfNetAddress
+	spc.dnsSeedResolver = newDNSSeedResolver(selfNetAddress.Port)
 
 	for _, seedPeerNetAddressStr := range seedPeerNetAddressStrs {
+		if isDNSSeed(seedPeerNetAddressStr) {
+			spc.dnsSeeds = append(spc.dnsSeeds, seedPeerNetAddressStr)
+			continue
+		}
 		seedNetAddress, err := netutil.NewNetAddressString(seedPeerNetAddressStr)
 		if err != nil {
 			logger.Errorf("Failed to parse the seed network address: %v", seedPeerNetAddressStr)
@@ -61,8 +78,9 @@ func createSeedPeerConnector(discMgr *PeerDiscoveryManager,
 		if seedNetAddress.Equals(selfNetAddress) {
 			continue
 		}
-		spc.seedPeerNetAddresses = append(spc.seedPeerNetAddresses, *seedNetAddress)
+		spc.staticSeedPeerNetAddresses = append(spc.staticSeedPeerNetAddresses, *seedNetAddress)
 	}
+	spc.resolveDNSSeeds()
 
 	return spc, nil
 }
@@ -75,6 +93,9 @@ func (spc *SeedPeerConnector) Start(ctx context.Context) error {
 
 	spc.connectToSeedPeers()
 	go spc.maintainConnectivityRoutine()
+	if len(spc.dnsSeeds) > 0 && spc.dnsSeedRefreshInterval > 0 {
+		go spc.refreshDNSSeedsRoutine()
+	}
 	return nil
 }
 
@@ -88,8 +109,66 @@ func (spc *SeedPeerConnector) Wait() {
 	spc.wg.Wait()
 }
 
+// getSeedPeerNetAddresses returns the addresses of the static and the resolved DNS seed peers
+func (spc *SeedPeerConnector) getSeedPeerNetAddresses() []netutil.NetAddress {
+	spc.seedMutex.RLock()
+	defer spc.seedMutex.RUnlock()
+
+	return spc.seedPeerNetAddresses
+}
+
+// resolveDNSSeeds resolves the DNS seeds and updates the seed peer addresses. The previously
+// resolved addresses of a DNS seed are kept if it fails to resolve.
+func (spc *SeedPeerConnector) resolveDNSSeeds() {
+	for _, dnsSeed := range spc.dnsSeeds {
+		addrs, err := spc.dnsSeedResolver.resolve(dnsSeed)
+		if err != nil {
+			logger.Warnf("Failed to resolve DNS seed %v: %v", dnsSeed, err)
+			continue
+		}
+		logger.Debugf("Resolved DNS seed %v: %v", dnsSeed, addrs)
+		spc.seedMutex.Lock()
+		spc.dnsSeedPeerNetAddresses[dnsSeed] = addrs
+		spc.seedMutex.Unlock()
+	}
+
+	spc.seedMutex.Lock()
+	defer spc.seedMutex.Unlock()
+
+	seedPeerNetAddresses := []netutil.NetAddress{}
+	seen := make(map[string]bool)
+	add := func(addrs []netutil.NetAddress) {
+		for _, addr := range addrs {
+			if addr.Equals(&spc.selfNetAddress) || seen[addr.String()] {
+				continue
+			}
+			seen[addr.String()] = true
+			seedPeerNetAddresses = append(seedPeerNetAddresses, addr)
+		}
+	}
+	add(spc.staticSeedPeerNetAddresses)
+	for _, dnsSeed := range spc.dnsSeeds {
+		add(spc.dnsSeedPeerNetAddresses[dnsSeed])
+	}
+	spc.seedPeerNetAddresses = seedPeerNetAddresses // replaced rather than updated in place, see getSeedPeerNetAddresses
+}
+
+func (spc *SeedPeerConnector) refreshDNSSeedsRoutine() {
+	refreshPulse := time.NewTicker(spc.dnsSeedRefreshInterval)
+	defer refreshPulse.Stop()
+
+	for {
+		select {
+		case <-spc.ctx.Done():
+			return
+		case <-refreshPulse.C:
+			spc.resolveDNSSeeds()
+		}
+	}
+}
+
 func (spc *SeedPeerConnector) isASeedPeerIgnoringPort(netAddr *netutil.NetAddress) bool {
-	for _, seedAddr := range spc.seedPeerNetAddresses {
+	for _, seedAddr := range spc.getSeedPeerNetAddresses() {
 		if bytes.Compare(netAddr.IP, seedAddr.IP) == 0 {
 			return true
 		}
@@ -98,7 +177,7 @@ func (spc *SeedPeerConnector) isASeedPeerIgnoringPort(netAddr *netutil.NetAddres
 }
 
 func (spc *SeedPeerConnector) isASeedPeer(netAddr *netutil.NetAddress) bool {
-	for _, seedAddr := range spc.seedPeerNetAddresses {
+	for _, seedAddr := range spc.getSeedPeerNetAddresses() {
 		if netAddr.Equals(&seedAddr) {
 			return true
 		}
@@ -111,7 +190,7 @@ func (spc *SeedPeerConnector) connectToSeedPeers() {
 
 	var peerNetAddresses []netutil.NetAddress
 	// add seed peers first
-	peerNetAddresses = append(peerNetAddresses, spc.seedPeerNetAddresses...)
+	peerNetAddresses = append(peerNetAddresses, spc.getSeedPeerNetAddresses()...)
 	// add persisted peers
 	persistedPeerAddrs, err := spc.discMgr.peerTable.RetrievePreviousPeers()
 	if err == nil {
@@ -170,7 +249,8 @@ func (spc *SeedPeerConnector) maintainConnectivity() {
 		}
 	}
 
-	perm := rand.Perm(len(spc.seedPeerNetAddresses))
+	seedPeerNetAddresses := spc.getSeedPeerNetAddresses()
+	perm := rand.Perm(len(seedPeerNetAddresses))
 	for i := 0; i < len(perm); i++ { // random order
 		spc.wg.Add(1)
 		go func(i int) {
@@ -178,7 +258,7 @@ func (spc *SeedPeerConnector) maintainConnectivity() {
 
 			time.Sleep(time.Duration(rand.Int63n(connectInterval)) * time.Millisecond)
 			j := perm[i]
-			peerNetAddress := spc.seedPeerNetAddresses[j]
+			peerNetAddress := seedPeerNetAddresses[j]
 			if !spc.discMgr.peerTable.PeerAddrExists(&peerNetAddress) {
 				_, err := spc.discMgr.connectToOutboundPeer(&peerNetAddress, true)
 				if err != nil {
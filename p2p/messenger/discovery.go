@@ -3,7 +3,9 @@ package messenger
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,6 +29,8 @@ type PeerDiscoveryManager struct {
 	seedPeers map[string]*pr.Peer
 	mutex     *sync.Mutex
 
+	privatePeerIDs map[string]bool // the addresses of the private peers are not shared with other peers
+
 	seedPeerOnly bool
 
 	// Three mechanisms for peer discovery
@@ -64,6 +68,13 @@ func CreatePeerDiscoveryManager(msgr *Messenger, nodeInfo *p2ptypes.NodeInfo, ad
 		mutex:        &sync.Mutex{},
 		seedPeerOnly: viper.GetBool(common.CfgP2PSeedPeerOnly),
 		wg:           &sync.WaitGroup{},
+
+		privatePeerIDs: make(map[string]bool),
+	}
+	for _, id := range strings.Split(viper.GetString(common.CfgP2PPrivatePeerIDs), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			discMgr.privatePeerIDs[strings.ToLower(id)] = true
+		}
 	}
 
 	//discMgr.addrBook = NewAddrBook(addrBookFilePath, routabilityRestrict)
@@ -204,7 +215,7 @@ func (discMgr *PeerDiscoveryManager) HandlePeerWithErrors(peer *pr.Peer) {
 func (discMgr *PeerDiscoveryManager) connectToOutboundPeer(peerNetAddress *netutil.NetAddress, persistent bool) (*pr.Peer, error) {
 	logger.Debugf("Connecting to outbound peer: %v...", peerNetAddress)
 	peerConfig := pr.GetDefaultPeerConfig()
-	connConfig := cn.GetDefaultConnectionConfig()
+	connConfig := getConnectionConfig()
 	peer, err := pr.CreateOutboundPeer(peerNetAddress, peerConfig, connConfig)
 	if err != nil {
 		logger.Debugf("Failed to create outbound peer: %v", peerNetAddress)
@@ -218,7 +229,7 @@ func (discMgr *PeerDiscoveryManager) connectToOutboundPeer(peerNetAddress *netut
 func (discMgr *PeerDiscoveryManager) connectWithInboundPeer(netconn net.Conn, persistent bool) (*pr.Peer, error) {
 	logger.Infof("Connecting with inbound peer: %v...", netconn.RemoteAddr())
 	peerConfig := pr.GetDefaultPeerConfig()
-	connConfig := cn.GetDefaultConnectionConfig()
+	connConfig := getConnectionConfig()
 	peer, err := pr.CreateInboundPeer(netconn, peerConfig, connConfig)
 	if err != nil {
 		logger.Warnf("Failed to create inbound peer: %v", netconn.RemoteAddr())
@@ -229,6 +240,19 @@ func (discMgr *PeerDiscoveryManager) connectWithInboundPeer(netconn net.Conn, pe
 	return peer, err
 }
 
+// getConnectionConfig returns the default ConnectionConfig with the rate limits specified in the config file
+func getConnectionConfig() cn.ConnectionConfig {
+	connConfig := cn.GetDefaultConnectionConfig()
+	connConfig.SendRate = viper.GetInt64(common.CfgP2PSendRate)
+	connConfig.RecvRate = viper.GetInt64(common.CfgP2PRecvRate)
+	connConfig.GlobalSendRate = viper.GetInt64(common.CfgP2PGlobalSendRate)
+	connConfig.GlobalRecvRate = viper.GetInt64(common.CfgP2PGlobalRecvRate)
+	connConfig.MaxMsgRatePerChannel = viper.GetInt64(common.CfgP2PMaxMsgRatePerChannel)
+	connConfig.SendQueueCapacity = viper.GetInt(common.CfgP2PSendQueueCapacity)
+	connConfig.SendQueuePolicy = viper.GetString(common.CfgP2PSendQueuePolicy)
+	return connConfig
+}
+
 // handshakeAndAddPeer performs handshake with a peer. Upon successful handshake,
 // it save the peer to the peer table
 func (discMgr *PeerDiscoveryManager) handshakeAndAddPeer(peer *pr.Peer) error {
@@ -237,8 +261,14 @@ func (discMgr *PeerDiscoveryManager) handshakeAndAddPeer(peer *pr.Peer) error {
 		return err
 	}
 
+	if discMgr.messenger != nil && discMgr.messenger.banList.IsBanned(peer.ID()) {
+		peer.Stop()
+		return fmt.Errorf("Peer %v is banned", peer.ID())
+	}
+
 	isSeed := discMgr.seedPeerConnector.isASeedPeer(peer.NetAddress())
 	peer.SetSeed(isSeed)
+	peer.SetPrivate(discMgr.privatePeerIDs[strings.ToLower(peer.ID())])
 	if isSeed {
 		logger.Infof("Handshaked with a seed peer: %v, isOutbound: %v", peer.NetAddress(), peer.IsOutbound())
 	}
@@ -274,6 +304,17 @@ func (discMgr *PeerDiscoveryManager) handshakeAndAddPeer(peer *pr.Peer) error {
 	return nil
 }
 
+// removePeer deletes the given peer from the peer table and closes the connection
+func (discMgr *PeerDiscoveryManager) removePeer(peer *pr.Peer) {
+	discMgr.peerTable.DeletePeer(peer.ID())
+
+	discMgr.mutex.Lock()
+	delete(discMgr.seedPeers, peer.ID())
+	discMgr.mutex.Unlock()
+
+	peer.Stop()
+}
+
 func (discMgr *PeerDiscoveryManager) isSeedPeer(pid string) bool {
 	discMgr.mutex.Lock()
 	defer discMgr.mutex.Unlock()
@@ -2,8 +2,10 @@ package messenger
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -15,12 +17,15 @@ import (
 	"github.com/thetatoken/theta/common/util"
 	"github.com/thetatoken/theta/crypto"
 	"github.com/thetatoken/theta/p2p"
+	"github.com/thetatoken/theta/p2p/netutil"
 	pr "github.com/thetatoken/theta/p2p/peer"
 	p2ptypes "github.com/thetatoken/theta/p2p/types"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "p2p"})
 
+const bandwidthCheckInterval = 10 * time.Second
+
 //
 // Messenger implements the Network interface
 //
@@ -32,6 +37,8 @@ type Messenger struct {
 	msgHandlerMap map[common.ChannelIDEnum](p2p.MessageHandler)
 
 	peerTable pr.PeerTable
+	banList   *p2ptypes.BanList
+	scoreBook *p2ptypes.PeerScoreBook
 	nodeInfo  p2ptypes.NodeInfo // information of our blockchain node
 
 	config MessengerConfig
@@ -71,6 +78,8 @@ func CreateMessenger(privKey *crypto.PrivateKey, seedPeerNetAddresses []string,
 	messenger := &Messenger{
 		msgHandlerMap: make(map[common.ChannelIDEnum](p2p.MessageHandler)),
 		peerTable:     pr.CreatePeerTable(),
+		banList:       p2ptypes.NewBanList(),
+		scoreBook:     p2ptypes.NewPeerScoreBook(viper.GetInt(common.CfgP2PPeerBanThreshold)),
 		nodeInfo:      p2ptypes.CreateLocalNodeInfo(privKey, uint16(eport)),
 		config:        msgrConfig,
 		wg:            &sync.WaitGroup{},
@@ -134,6 +143,9 @@ func (msgr *Messenger) Start(ctx context.Context) error {
 		err = msgr.natMgr.Start(c)
 	}
 
+	msgr.wg.Add(1)
+	go msgr.monitorPeerBandwidthRoutine(c)
+
 	return err
 }
 
@@ -255,6 +267,131 @@ func (msgr *Messenger) PeerExists(peerID string) bool {
 	return msgr.peerTable.PeerExists(peerID)
 }
 
+// PeerInfos returns the runtime information of all peers
+func (msgr *Messenger) PeerInfos(skipEdgeNode bool) []p2ptypes.PeerInfo {
+	allPeers := msgr.peerTable.GetAllPeers(skipEdgeNode)
+	peerInfos := []p2ptypes.PeerInfo{}
+	for _, peer := range *allPeers {
+		direction := p2ptypes.PeerDirectionInbound
+		if peer.IsOutbound() {
+			direction = p2ptypes.PeerDirectionOutbound
+		}
+		conn := peer.GetConnection()
+		peerInfos = append(peerInfos, p2ptypes.PeerInfo{
+			ID:              peer.ID(),
+			Address:         peer.NetAddress().String(),
+			Direction:       direction,
+			Version:         peer.Version(),
+			ProtocolVersion: peer.ProtocolVersion(),
+			Capabilities:    peer.Capabilities(),
+			NodeType:        peer.NodeType(),
+			IsSeed:          peer.IsSeed(),
+			Score:           msgr.scoreBook.Score(peer.ID()),
+			Encrypted:       conn.IsEncrypted(),
+			Compressed:      conn.IsCompressed(),
+			LatencyMs:       common.JSONUint64(conn.GetLatency() / time.Millisecond),
+			BytesIn:         common.JSONUint64(conn.GetBytesReceived()),
+			BytesOut:        common.JSONUint64(conn.GetBytesSent()),
+		})
+	}
+	return peerInfos
+}
+
+// ConnectPeer connects to the peer with the given network address, e.g. 127.0.0.1:12000
+func (msgr *Messenger) ConnectPeer(peerAddr string) error {
+	netAddr, err := netutil.NewNetAddressString(peerAddr)
+	if err != nil {
+		return fmt.Errorf("Invalid peer address %v: %v", peerAddr, err)
+	}
+	_, err = msgr.discMgr.connectToOutboundPeer(netAddr, true)
+	return err
+}
+
+// DisconnectPeer disconnects from the peer specified by the peerID
+func (msgr *Messenger) DisconnectPeer(peerID string) bool {
+	peerID = normalizePeerID(peerID)
+	peer := msgr.peerTable.GetPeer(peerID)
+	if peer == nil {
+		return false
+	}
+	msgr.discMgr.removePeer(peer)
+	logger.Infof("Disconnected from peer %v", peerID)
+	return true
+}
+
+// BanPeer disconnects from the given peer and rejects its connections for the specified duration
+func (msgr *Messenger) BanPeer(peerID string, duration time.Duration) bool {
+	if !common.IsHexAddress(peerID) {
+		return false
+	}
+	peerID = normalizePeerID(peerID)
+	msgr.banList.Ban(peerID, duration)
+	logger.Infof("Banned peer %v for %v", peerID, duration)
+	msgr.DisconnectPeer(peerID)
+	return true
+}
+
+// ReportMisbehavior penalizes the given peer, and bans it if its score drops below the threshold
+func (msgr *Messenger) ReportMisbehavior(peerID string, misbehavior p2ptypes.Misbehavior) {
+	if !viper.GetBool(common.CfgP2PPeerScoringEnabled) {
+		return
+	}
+	peerID = normalizePeerID(peerID)
+	if !msgr.peerTable.PeerExists(peerID) {
+		return
+	}
+
+	score, shouldBan := msgr.scoreBook.Penalize(peerID, misbehavior)
+	logger.Infof("Penalized peer %v for %v, score: %v", peerID, misbehavior, score)
+	if shouldBan {
+		msgr.scoreBook.Remove(peerID)
+		msgr.BanPeer(peerID, viper.GetDuration(common.CfgP2PPeerBanDurationSecs)*time.Second)
+	}
+}
+
+// monitorPeerBandwidthRoutine periodically penalizes the peers that send data above the allowed rate
+func (msgr *Messenger) monitorPeerBandwidthRoutine(ctx context.Context) {
+	defer msgr.wg.Done()
+
+	ticker := time.NewTicker(bandwidthCheckInterval)
+	defer ticker.Stop()
+
+	lastBytesRecv := make(map[string]uint64)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			maxRecvRate := uint64(viper.GetInt64(common.CfgP2PPeerMaxRecvRate))
+			bytesRecvMap := make(map[string]uint64)
+			for _, peer := range *msgr.peerTable.GetAllPeers(false) {
+				pid := peer.ID()
+				bytesRecv := peer.GetConnection().GetBytesReceived()
+				bytesRecvMap[pid] = bytesRecv
+
+				last, ok := lastBytesRecv[pid]
+				if !ok || maxRecvRate == 0 || bytesRecv < last {
+					continue // bytesRecv < last if the peer has reconnected
+				}
+				recvRate := (bytesRecv - last) / uint64(bandwidthCheckInterval/time.Second)
+				if recvRate > maxRecvRate {
+					logger.Warnf("Peer %v exceeded the receive rate limit: %v B/s", pid, recvRate)
+					msgr.ReportMisbehavior(pid, p2ptypes.MisbehaviorExcessiveBandwidth)
+				}
+			}
+			lastBytesRecv = bytesRecvMap
+		}
+	}
+}
+
+// normalizePeerID converts the given peer ID to the checksummed address format used by the peer table
+func normalizePeerID(peerID string) string {
+	if !common.IsHexAddress(peerID) {
+		return peerID
+	}
+	return common.HexToAddress(peerID).Hex()
+}
+
 // RegisterMessageHandler registers the message handler
 func (msgr *Messenger) RegisterMessageHandler(msgHandler p2p.MessageHandler) {
 	channelIDs := msgHandler.GetChannelIDs()
@@ -281,6 +418,9 @@ func (msgr *Messenger) AttachMessageHandlersToPeer(peer *pr.Peer) {
 			logger.Errorf("Failed to setup message parser for channelID %v", channelID)
 		}
 		message, err := msgHandler.ParseMessage(peerID, channelID, rawMessageBytes)
+		if err != nil {
+			msgr.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
+		}
 		return message, err
 	}
 	peer.GetConnection().SetMessageParser(messageParser)
@@ -19,6 +19,7 @@ import (
 	nu "github.com/thetatoken/theta/p2p/netutil"
 	p2ptypes "github.com/thetatoken/theta/p2p/types"
 	"github.com/thetatoken/theta/rlp"
+	"github.com/thetatoken/theta/version"
 )
 
 var logger *log.Entry = log.WithFields(log.Fields{"prefix": "p2p"})
@@ -34,12 +35,17 @@ type Peer struct {
 	isPersistent bool
 	isOutbound   bool
 	isSeed       bool
+	isPrivate    bool
 	netAddress   *nu.NetAddress
 
 	nodeInfo p2ptypes.NodeInfo // information of the blockchain node of the peer
 	nodeType cmn.NodeType
+	version  string // software version of the peer, empty if not advertised
 	config   PeerConfig
 
+	protocolVersion uint            // p2p protocol version negotiated with the peer
+	capabilities    map[string]bool // capabilities shared with the peer
+
 	// Life cycle
 	wg      *sync.WaitGroup
 	quit    chan struct{}
@@ -155,6 +161,9 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 	localChainID := viper.GetString(cmn.CfgGenesisChainID)
 	selfNodeType := viper.GetInt(cmn.CfgNodeType)
 	var peerType int
+	var peerVersion string
+	capabilities := localCapabilities()
+	peerProtocol := newHandshakeProtocol()
 	cmn.Parallel(
 		func() {
 			sendError = rlp.Encode(peer.connection.GetBufNetconn(), localChainID)
@@ -165,6 +174,16 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 			if sendError != nil {
 				return
 			}
+			sendError = rlp.Encode(peer.connection.GetBufNetconn(), version.Version)
+			if sendError != nil {
+				return
+			}
+			for _, token := range handshakeTokens(capabilities) {
+				sendError = rlp.Encode(peer.connection.GetBufNetconn(), token)
+				if sendError != nil {
+					return
+				}
+			}
 			sendError = rlp.Encode(peer.connection.GetBufNetconn(), "EOH")
 		},
 		func() {
@@ -197,6 +216,16 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 			}
 			logger.Infof("Peer Type: %v", peerType)
 
+			recvError = s.Decode(&msg)
+			if recvError != nil {
+				return
+			}
+			if msg == "EOH" {
+				return // older peers do not advertise their version
+			}
+			peerVersion = msg
+			logger.Infof("Peer Version: %v", peerVersion)
+
 			for {
 				recvError = s.Decode(&msg)
 				if recvError != nil {
@@ -205,6 +234,7 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 				if msg == "EOH" {
 					return
 				}
+				peerProtocol.parseToken(msg)
 			}
 		},
 	)
@@ -218,20 +248,34 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 	}
 
 	peer.nodeType = common.NodeType(peerType)
-
-	remotePub, err := peer.connection.DoEncHandshake(
-		crypto.PrivKeyToECDSA(sourceNodeInfo.PrivKey), crypto.PubKeyToECDSA(targetNodePubKey))
-	if err != nil {
-		logger.Warnf("Error during handshake/key exchange: %v", err)
-		return err
+	peer.version = peerVersion
+	peer.protocolVersion, peer.capabilities = peerProtocol.negotiate(capabilities)
+	peer.connection.SetChannelFilter(peer.SupportsChannel)
+	logger.Infof("Peer protocol version: %v, shared capabilities: %v", peer.protocolVersion, peer.Capabilities())
+
+	// The encryption is skipped only if neither side requires it. The older peers do not advertise
+	// the plaintext capability, and always encrypt the transport.
+	if peer.HasCapability(CapabilityPlaintext) {
+		logger.Warnf("Using plaintext transport for peer: %v, its identity is not authenticated", targetNodePubKey.Address())
 	} else {
-		if remotePub.Address() != targetNodePubKey.Address() {
-			err = fmt.Errorf("expected remote address: %v, actual address: %v", targetNodePubKey.Address(), remotePub.Address())
+		remotePub, err := peer.connection.DoEncHandshake(
+			crypto.PrivKeyToECDSA(sourceNodeInfo.PrivKey), crypto.PubKeyToECDSA(targetNodePubKey))
+		if err != nil {
 			logger.Warnf("Error during handshake/key exchange: %v", err)
 			return err
+		} else {
+			if remotePub.Address() != targetNodePubKey.Address() {
+				err = fmt.Errorf("expected remote address: %v, actual address: %v", targetNodePubKey.Address(), remotePub.Address())
+				logger.Warnf("Error during handshake/key exchange: %v", err)
+				return err
+			}
 		}
+		logger.Infof("Using encrypted transport for peer: %v", targetNodePubKey.Address())
+	}
+
+	if peer.HasCapability(CapabilityCompression) && peer.connection.EnableCompression() {
+		logger.Infof("Using compressed transport for peer: %v", targetNodePubKey.Address())
 	}
-	logger.Infof("Using encrypted transport for peer: %v", targetNodePubKey.Address())
 
 	if !peer.isOutbound {
 		peer.SetNetAddress(nu.NewNetAddressWithEnforcedPort(netconn.RemoteAddr(), int(peer.nodeInfo.Port)))
@@ -247,19 +291,27 @@ func (peer *Peer) Handshake(sourceNodeInfo *p2ptypes.NodeInfo) error {
 
 // Send sends the given message through the specified channel to the target peer
 func (peer *Peer) Send(channelID cmn.ChannelIDEnum, message interface{}) bool {
+	if !peer.SupportsChannel(channelID) {
+		logger.Debugf("Skipped message on channel %v unsupported by peer %v", channelID, peer.ID())
+		return false
+	}
 	success := peer.connection.EnqueueMessage(channelID, message)
 	return success
 }
 
 // AttemptToSend attempts to send the given message through the specified channel to the target peer (non-blocking)
 func (peer *Peer) AttemptToSend(channelID cmn.ChannelIDEnum, message interface{}) bool {
+	if !peer.SupportsChannel(channelID) {
+		logger.Debugf("Skipped message on channel %v unsupported by peer %v", channelID, peer.ID())
+		return false
+	}
 	success := peer.connection.AttemptToEnqueueMessage(channelID, message)
 	return success
 }
 
 // CanSend indicates whether more messages can be sent through the specified channel
 func (peer *Peer) CanSend(channelID cmn.ChannelIDEnum) bool {
-	canSend := peer.connection.CanEnqueueMessage(channelID)
+	canSend := peer.SupportsChannel(channelID) && peer.connection.CanEnqueueMessage(channelID)
 	return canSend
 }
 
@@ -293,6 +345,11 @@ func (peer *Peer) NodeType() cmn.NodeType {
 	return peer.nodeType
 }
 
+// Version returns the software version advertised by the peer during handshake
+func (peer *Peer) Version() string {
+	return peer.version
+}
+
 // SetSeed sets the isSeed for the given peer
 func (peer *Peer) SetSeed(isSeed bool) {
 	peer.isSeed = isSeed
@@ -303,6 +360,16 @@ func (peer *Peer) IsSeed() bool {
 	return peer.isSeed
 }
 
+// SetPrivate sets the private flag of the peer
+func (peer *Peer) SetPrivate(isPrivate bool) {
+	peer.isPrivate = isPrivate
+}
+
+// IsPrivate returns whether the peer is a private peer, whose address should not be shared with other peers
+func (peer *Peer) IsPrivate() bool {
+	return peer.isPrivate
+}
+
 // SetNetAddress sets the network address of the peer
 func (peer *Peer) SetNetAddress(netAddr *nu.NetAddress) {
 	peer.netAddress = netAddr
@@ -354,6 +421,9 @@ func createPeer(netconn net.Conn, isOutbound bool,
 		netAddress: netAddress,
 		config:     peerConfig,
 		wg:         &sync.WaitGroup{},
+
+		protocolVersion: ProtocolVersionBase,
+		capabilities:    make(map[string]bool),
 	}
 	return peer
 }
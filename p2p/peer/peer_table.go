@@ -243,6 +243,9 @@ func (pt *PeerTable) GetSelection(skipEdgeNode bool) (peerIDAddrs []PeerIDAddres
 		if skipEdgeNode && peer.NodeType() == common.NodeTypeEdgeNode {
 			continue
 		}
+		if peer.IsPrivate() {
+			continue
+		}
 		peerIDAddr := PeerIDAddress{
 			ID:   peer.ID(),
 			Addr: peer.netAddress,
@@ -136,6 +136,30 @@ func TestDefaultPeerIterationOrder(t *testing.T) {
 	assert.Equal((*allPeers)[5], peer3)
 }
 
+func TestDefaultPeerTableGetSelectionSkipsPrivatePeers(t *testing.T) {
+	assert := assert.New(t)
+
+	pt := newTestEmptyPeerTable()
+
+	port := 37859
+	netconn := newIncomingNetconn(port)
+
+	peer1 := newSimulatedInboundPeer(netconn, p2ptypes.GetTestRandPubKey())
+	peer2 := newSimulatedInboundPeer(netconn, p2ptypes.GetTestRandPubKey())
+	peer3 := newSimulatedInboundPeer(netconn, p2ptypes.GetTestRandPubKey())
+	peer2.SetPrivate(true)
+
+	assert.True(pt.AddPeer(peer1))
+	assert.True(pt.AddPeer(peer2))
+	assert.True(pt.AddPeer(peer3))
+
+	peerIDAddrs := pt.GetSelection(false)
+	assert.Equal(2, len(peerIDAddrs))
+	for _, peerIDAddr := range peerIDAddrs {
+		assert.NotEqual(peer2.ID(), peerIDAddr.ID)
+	}
+}
+
 // --------------- Test Utilities --------------- //
 
 func newTestEmptyPeerTable() PeerTable {
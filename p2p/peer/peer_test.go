@@ -45,6 +45,7 @@ func TestPeerHandshakeAndCommunication(t *testing.T) {
 		err := outboundPeer.Handshake(&peerANodeInfo) // send out PeerA's node info
 		assert.Nil(err)
 		assert.True(outboundPeer.IsOutbound())
+		assert.True(outboundPeer.GetConnection().IsEncrypted())
 
 		generatedPeerAAddr := peerANodeInfo.PubKey.Address().Hex()
 		receivedPeerBAddr := outboundPeer.nodeInfo.PubKey.Address().Hex()
@@ -80,6 +81,9 @@ func TestPeerHandshakeAndCommunication(t *testing.T) {
 	err = inboundPeer.Handshake(&peerBNodeInfo) // send out PeerB's node info
 	assert.Nil(err)
 	assert.False(inboundPeer.IsOutbound())
+	assert.True(inboundPeer.GetConnection().IsEncrypted())
+	assert.Equal(ProtocolVersion, inboundPeer.ProtocolVersion())
+	assert.True(inboundPeer.SupportsChannel(common.ChannelIDSnapshot))
 
 	receivedPeerAAddr := inboundPeer.nodeInfo.PubKey.Address().Hex()
 	generatedPeerBAddr := peerBNodeInfo.PubKey.Address().Hex()
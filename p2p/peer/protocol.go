@@ -0,0 +1,129 @@
+package peer
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	cmn "github.com/thetatoken/theta/common"
+)
+
+// The versions of the p2p protocol. The protocol version and the capabilities, i.e. the optional
+// features supported by a node, are advertised in the extra handshake info. The peers running the
+// older releases advertise neither, and are considered to speak the base protocol.
+const (
+	ProtocolVersionBase uint = 1
+	ProtocolVersion     uint = 2
+)
+
+// The capabilities advertised in the extra handshake info
+const (
+	CapabilityCompression = "snappy"
+	CapabilityStateSync   = "statesync"
+	CapabilityPlaintext   = "plaintext" // the transport is not encrypted if both sides allow it
+)
+
+const (
+	protocolVersionPrefix = "proto:"
+	capabilityPrefix      = "cap:"
+)
+
+// channelCapabilities are the capabilities both sides of a connection need to exchange messages
+// on the channels added after the base protocol. The messages on these channels are not sent to
+// the peers lacking the capability, so that mixed releases can interoperate during an upgrade.
+var channelCapabilities = map[cmn.ChannelIDEnum]string{
+	cmn.ChannelIDSnapshot: CapabilityStateSync,
+}
+
+// localCapabilities returns the capabilities advertised by the node
+func localCapabilities() []string {
+	capabilities := []string{CapabilityStateSync}
+	if viper.GetBool(cmn.CfgP2PCompression) {
+		capabilities = append(capabilities, CapabilityCompression)
+	}
+	if !viper.GetBool(cmn.CfgP2PRequireEncryption) {
+		capabilities = append(capabilities, CapabilityPlaintext)
+	}
+	return capabilities
+}
+
+// handshakeProtocol collects the protocol version and the capabilities advertised by a peer
+type handshakeProtocol struct {
+	version      uint
+	capabilities map[string]bool
+}
+
+func newHandshakeProtocol() *handshakeProtocol {
+	return &handshakeProtocol{
+		version:      ProtocolVersionBase,
+		capabilities: make(map[string]bool),
+	}
+}
+
+// handshakeTokens returns the extra handshake info advertising the local protocol
+func handshakeTokens(capabilities []string) []string {
+	tokens := []string{protocolVersionPrefix + strconv.FormatUint(uint64(ProtocolVersion), 10)}
+	for _, capability := range capabilities {
+		tokens = append(tokens, capabilityPrefix+capability)
+	}
+	return tokens
+}
+
+// parseToken records the protocol version or the capability in the extra handshake info. The
+// unknown tokens, e.g. from a newer release, are ignored.
+func (hp *handshakeProtocol) parseToken(token string) {
+	switch {
+	case strings.HasPrefix(token, protocolVersionPrefix):
+		version, err := strconv.ParseUint(strings.TrimPrefix(token, protocolVersionPrefix), 10, 32)
+		if err != nil || version < uint64(ProtocolVersionBase) {
+			logger.Warnf("Ignored invalid peer protocol version: %v", token)
+			return
+		}
+		hp.version = uint(version)
+	case strings.HasPrefix(token, capabilityPrefix):
+		hp.capabilities[strings.TrimPrefix(token, capabilityPrefix)] = true
+	}
+}
+
+// negotiate returns the protocol version and the capabilities shared with the peer
+func (hp *handshakeProtocol) negotiate(capabilities []string) (uint, map[string]bool) {
+	version := hp.version
+	if version > ProtocolVersion {
+		version = ProtocolVersion
+	}
+	shared := make(map[string]bool)
+	for _, capability := range capabilities {
+		if hp.capabilities[capability] {
+			shared[capability] = true
+		}
+	}
+	return version, shared
+}
+
+// ProtocolVersion returns the p2p protocol version negotiated with the peer, i.e. the lower of the
+// versions of both sides
+func (peer *Peer) ProtocolVersion() uint {
+	return peer.protocolVersion
+}
+
+// HasCapability returns whether the capability is supported by both the node and the peer
+func (peer *Peer) HasCapability(capability string) bool {
+	return peer.capabilities[capability]
+}
+
+// Capabilities returns the sorted capabilities shared with the peer
+func (peer *Peer) Capabilities() []string {
+	capabilities := []string{}
+	for capability := range peer.capabilities {
+		capabilities = append(capabilities, capability)
+	}
+	sort.Strings(capabilities)
+	return capabilities
+}
+
+// SupportsChannel returns whether messages can be exchanged with the peer on the channel
+func (peer *Peer) SupportsChannel(channelID cmn.ChannelIDEnum) bool {
+	capability, ok := channelCapabilities[channelID]
+	return !ok || peer.HasCapability(capability)
+}
@@ -0,0 +1,81 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestProtocolNegotiation(t *testing.T) {
+	assert := assert.New(t)
+
+	local := []string{CapabilityStateSync, CapabilityCompression}
+
+	// A peer of an older release advertises neither the version nor the capabilities
+	legacy := newHandshakeProtocol()
+	version, capabilities := legacy.negotiate(local)
+	assert.Equal(ProtocolVersionBase, version)
+	assert.Equal(0, len(capabilities))
+
+	// A peer of a newer release advertises a higher version and unknown capabilities
+	newer := newHandshakeProtocol()
+	for _, token := range []string{"proto:7", "cap:snappy", "cap:future", "unknown"} {
+		newer.parseToken(token)
+	}
+	version, capabilities = newer.negotiate(local)
+	assert.Equal(ProtocolVersion, version)
+	assert.Equal(map[string]bool{CapabilityCompression: true}, capabilities)
+
+	// The tokens advertised by the node are understood by the same release
+	same := newHandshakeProtocol()
+	for _, token := range handshakeTokens(local) {
+		same.parseToken(token)
+	}
+	version, capabilities = same.negotiate(local)
+	assert.Equal(ProtocolVersion, version)
+	assert.Equal(2, len(capabilities))
+
+	invalid := newHandshakeProtocol()
+	invalid.parseToken("proto:abc")
+	invalid.parseToken("proto:0")
+	assert.Equal(ProtocolVersionBase, invalid.version)
+
+	// The messages on the channels added after the base protocol are only sent to the capable peers
+	peer := &Peer{capabilities: capabilities}
+	assert.True(peer.SupportsChannel(common.ChannelIDSnapshot))
+	assert.Equal([]string{CapabilityCompression, CapabilityStateSync}, peer.Capabilities())
+	peer = &Peer{capabilities: map[string]bool{}}
+	assert.False(peer.SupportsChannel(common.ChannelIDSnapshot))
+	assert.True(peer.SupportsChannel(common.ChannelIDBlock))
+}
+
+func TestPlaintextCapability(t *testing.T) {
+	assert := assert.New(t)
+
+	viper.Set(common.CfgP2PRequireEncryption, true)
+	assert.NotContains(localCapabilities(), CapabilityPlaintext)
+
+	viper.Set(common.CfgP2PRequireEncryption, false)
+	defer viper.Set(common.CfgP2PRequireEncryption, true)
+	local := localCapabilities()
+	assert.Contains(local, CapabilityPlaintext)
+
+	// The transport is still encrypted with the peers requiring the encryption, or of the older releases
+	requiring := newHandshakeProtocol()
+	for _, token := range handshakeTokens([]string{CapabilityStateSync}) {
+		requiring.parseToken(token)
+	}
+	_, capabilities := requiring.negotiate(local)
+	assert.False(capabilities[CapabilityPlaintext])
+	_, capabilities = newHandshakeProtocol().negotiate(local)
+	assert.False(capabilities[CapabilityPlaintext])
+
+	allowing := newHandshakeProtocol()
+	for _, token := range handshakeTokens(local) {
+		allowing.parseToken(token)
+	}
+	_, capabilities = allowing.negotiate(local)
+	assert.True(capabilities[CapabilityPlaintext])
+}
@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -219,6 +220,30 @@ func (se *SimnetEndpoint) PeerExists(peerID string) bool {
 	return false
 }
 
+// PeerInfos returns the runtime information of all peers
+func (se *SimnetEndpoint) PeerInfos(skipEdgeNode bool) []p2ptypes.PeerInfo {
+	return []p2ptypes.PeerInfo{}
+}
+
+// ConnectPeer implements the Network interface.
+func (se *SimnetEndpoint) ConnectPeer(peerAddr string) error {
+	return errors.New("ConnectPeer is not supported by the simulated network")
+}
+
+// DisconnectPeer implements the Network interface.
+func (se *SimnetEndpoint) DisconnectPeer(peerID string) bool {
+	return false
+}
+
+// BanPeer implements the Network interface.
+func (se *SimnetEndpoint) BanPeer(peerID string, duration time.Duration) bool {
+	return false
+}
+
+// ReportMisbehavior implements the Network interface.
+func (se *SimnetEndpoint) ReportMisbehavior(peerID string, misbehavior p2ptypes.Misbehavior) {
+}
+
 // RegisterMessageHandler implements the Network interface.
 func (se *SimnetEndpoint) RegisterMessageHandler(handler p2p.MessageHandler) {
 	se.handlers = append(se.handlers, handler)
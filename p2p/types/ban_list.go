@@ -0,0 +1,56 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+//
+// BanList keeps track of the peers that are temporarily banned from connecting
+//
+type BanList struct {
+	mutex  *sync.Mutex
+	expiry map[string]time.Time // map: peerID |-> ban expiry time
+}
+
+// NewBanList creates an instance of the BanList
+func NewBanList() *BanList {
+	return &BanList{
+		mutex:  &sync.Mutex{},
+		expiry: make(map[string]time.Time),
+	}
+}
+
+// Ban bans the given peer for the specified duration
+func (bl *BanList) Ban(peerID string, duration time.Duration) {
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	bl.expiry[peerID] = time.Now().Add(duration)
+}
+
+// Unban lifts the ban on the given peer, returns false if the peer was not banned
+func (bl *BanList) Unban(peerID string) bool {
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	_, exists := bl.expiry[peerID]
+	delete(bl.expiry, peerID)
+	return exists
+}
+
+// IsBanned indicates whether the given peer is currently banned
+func (bl *BanList) IsBanned(peerID string) bool {
+	bl.mutex.Lock()
+	defer bl.mutex.Unlock()
+
+	expiry, exists := bl.expiry[peerID]
+	if !exists {
+		return false
+	}
+	if time.Now().After(expiry) {
+		delete(bl.expiry, peerID)
+		return false
+	}
+	return true
+}
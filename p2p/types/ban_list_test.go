@@ -0,0 +1,34 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBanList(t *testing.T) {
+	assert := assert.New(t)
+
+	bl := NewBanList()
+	assert.False(bl.IsBanned("peer1"))
+
+	bl.Ban("peer1", time.Hour)
+	assert.True(bl.IsBanned("peer1"))
+	assert.False(bl.IsBanned("peer2"))
+
+	assert.True(bl.Unban("peer1"))
+	assert.False(bl.IsBanned("peer1"))
+	assert.False(bl.Unban("peer1"))
+}
+
+func TestBanListExpiry(t *testing.T) {
+	assert := assert.New(t)
+
+	bl := NewBanList()
+	bl.Ban("peer1", 10*time.Millisecond)
+	assert.True(bl.IsBanned("peer1"))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.False(bl.IsBanned("peer1"))
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// Misbehavior enumerates the peer misbehaviors that are penalized by the PeerScoreBook
+type Misbehavior byte
+
+const (
+	// MisbehaviorInvalidBlock indicates the peer relayed a block that failed validation
+	MisbehaviorInvalidBlock Misbehavior = iota
+
+	// MisbehaviorInvalidVote indicates the peer relayed a vote that failed validation
+	MisbehaviorInvalidVote
+
+	// MisbehaviorMalformedMessage indicates the peer sent a message that could not be decoded
+	MisbehaviorMalformedMessage
+
+	// MisbehaviorExcessiveBandwidth indicates the peer sent data at a rate above the allowed limit
+	MisbehaviorExcessiveBandwidth
+)
+
+const (
+	// InitialPeerScore is the reputation score a peer starts with
+	InitialPeerScore = 100
+
+	// peerScoreRecoveryInterval is the interval at which a penalized peer regains one point
+	peerScoreRecoveryInterval = time.Minute
+)
+
+var misbehaviorPenalties = map[Misbehavior]int{
+	MisbehaviorInvalidBlock:       25,
+	MisbehaviorInvalidVote:        10,
+	MisbehaviorMalformedMessage:   5,
+	MisbehaviorExcessiveBandwidth: 20,
+}
+
+func (m Misbehavior) String() string {
+	switch m {
+	case MisbehaviorInvalidBlock:
+		return "invalid block"
+	case MisbehaviorInvalidVote:
+		return "invalid vote"
+	case MisbehaviorMalformedMessage:
+		return "malformed message"
+	case MisbehaviorExcessiveBandwidth:
+		return "excessive bandwidth"
+	default:
+		return "unknown"
+	}
+}
+
+// Penalty returns the number of points deducted for the misbehavior
+func (m Misbehavior) Penalty() int {
+	return misbehaviorPenalties[m]
+}
+
+type peerScore struct {
+	score       int
+	lastUpdated time.Time
+}
+
+//
+// PeerScoreBook keeps track of the reputation scores of the peers. A penalized peer
+// slowly recovers its score over time, so occasional glitches do not add up to a ban
+//
+type PeerScoreBook struct {
+	mutex  *sync.Mutex
+	scores map[string]*peerScore // map: peerID |-> score
+
+	banThreshold int
+}
+
+// NewPeerScoreBook creates an instance of the PeerScoreBook. Peers whose score
+// drop to or below the banThreshold should be banned
+func NewPeerScoreBook(banThreshold int) *PeerScoreBook {
+	return &PeerScoreBook{
+		mutex:        &sync.Mutex{},
+		scores:       make(map[string]*peerScore),
+		banThreshold: banThreshold,
+	}
+}
+
+// Penalize deducts the penalty of the misbehavior from the score of the given peer.
+// It returns the updated score, and whether the peer should be banned
+func (sb *PeerScoreBook) Penalize(peerID string, misbehavior Misbehavior) (score int, shouldBan bool) {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	ps := sb.recover(peerID)
+	ps.score -= misbehavior.Penalty()
+	return ps.score, ps.score <= sb.banThreshold
+}
+
+// Score returns the current score of the given peer
+func (sb *PeerScoreBook) Score(peerID string) int {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	if _, exists := sb.scores[peerID]; !exists {
+		return InitialPeerScore
+	}
+	return sb.recover(peerID).score
+}
+
+// Remove clears the score record of the given peer
+func (sb *PeerScoreBook) Remove(peerID string) {
+	sb.mutex.Lock()
+	defer sb.mutex.Unlock()
+
+	delete(sb.scores, peerID)
+}
+
+// recover applies the score recovered since the last update. Caller needs to hold the mutex
+func (sb *PeerScoreBook) recover(peerID string) *peerScore {
+	now := time.Now()
+	ps, exists := sb.scores[peerID]
+	if !exists {
+		ps = &peerScore{
+			score:       InitialPeerScore,
+			lastUpdated: now,
+		}
+		sb.scores[peerID] = ps
+		return ps
+	}
+
+	recovered := int(now.Sub(ps.lastUpdated) / peerScoreRecoveryInterval)
+	if recovered > 0 {
+		ps.score += recovered
+		if ps.score > InitialPeerScore {
+			ps.score = InitialPeerScore
+		}
+		ps.lastUpdated = ps.lastUpdated.Add(time.Duration(recovered) * peerScoreRecoveryInterval)
+	}
+	return ps
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerScoreBookPenalize(t *testing.T) {
+	assert := assert.New(t)
+
+	sb := NewPeerScoreBook(0)
+	assert.Equal(InitialPeerScore, sb.Score("peer1"))
+
+	score, shouldBan := sb.Penalize("peer1", MisbehaviorMalformedMessage)
+	assert.Equal(InitialPeerScore-MisbehaviorMalformedMessage.Penalty(), score)
+	assert.False(shouldBan)
+	assert.Equal(score, sb.Score("peer1"))
+	assert.Equal(InitialPeerScore, sb.Score("peer2"))
+
+	for i := 0; i < 3; i++ {
+		_, shouldBan = sb.Penalize("peer1", MisbehaviorInvalidBlock)
+		assert.False(shouldBan)
+	}
+	_, shouldBan = sb.Penalize("peer1", MisbehaviorInvalidBlock)
+	assert.True(shouldBan)
+
+	sb.Remove("peer1")
+	assert.Equal(InitialPeerScore, sb.Score("peer1"))
+}
+
+func TestPeerScoreBookRecovery(t *testing.T) {
+	assert := assert.New(t)
+
+	sb := NewPeerScoreBook(0)
+	score, _ := sb.Penalize("peer1", MisbehaviorInvalidVote)
+	assert.Equal(InitialPeerScore-MisbehaviorInvalidVote.Penalty(), score)
+
+	// Simulate the passage of time
+	sb.scores["peer1"].lastUpdated = time.Now().Add(-3 * peerScoreRecoveryInterval)
+	assert.Equal(InitialPeerScore-MisbehaviorInvalidVote.Penalty()+3, sb.Score("peer1"))
+
+	sb.scores["peer1"].lastUpdated = time.Now().Add(-1000 * peerScoreRecoveryInterval)
+	assert.Equal(InitialPeerScore, sb.Score("peer1"))
+}
@@ -48,6 +48,34 @@ func CreateLocalNodeInfo(privateKey *crypto.PrivateKey, port uint16) NodeInfo {
 	return nodeInfo
 }
 
+const (
+	// PeerDirectionInbound indicates the peer initiated the connection
+	PeerDirectionInbound = "inbound"
+
+	// PeerDirectionOutbound indicates the local node initiated the connection
+	PeerDirectionOutbound = "outbound"
+)
+
+//
+// PeerInfo provides the runtime information of a connected peer
+//
+type PeerInfo struct {
+	ID              string            `json:"id"`
+	Address         string            `json:"address"`
+	Direction       string            `json:"direction"`
+	Version         string            `json:"version"`
+	ProtocolVersion uint              `json:"protocol_version"` // negotiated p2p protocol version
+	Capabilities    []string          `json:"capabilities"`     // capabilities shared with the peer
+	NodeType        common.NodeType   `json:"node_type"`
+	IsSeed          bool              `json:"is_seed"`
+	Score           int               `json:"score"`
+	Encrypted       bool              `json:"encrypted"`
+	Compressed      bool              `json:"compressed"`
+	LatencyMs       common.JSONUint64 `json:"latency_ms"`
+	BytesIn         common.JSONUint64 `json:"bytes_in"`
+	BytesOut        common.JSONUint64 `json:"bytes_out"`
+}
+
 const (
 	// PingSignal represents a ping signal to a peer
 	PingSignal = byte(0x0)
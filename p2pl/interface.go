@@ -2,6 +2,7 @@ package p2pl
 
 import (
 	"context"
+	"time"
 
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/p2p/types"
@@ -60,6 +61,21 @@ type Network interface {
 	// PeerExists indicates if the given peerID is a neighboring peer
 	PeerExists(peerID string) bool
 
+	// PeerInfos returns the runtime information of all peers
+	PeerInfos(skipEdgeNode bool) []types.PeerInfo
+
+	// ConnectPeer connects to the peer with the given address
+	ConnectPeer(peerAddr string) error
+
+	// DisconnectPeer disconnects from the peer specified by the peerID
+	DisconnectPeer(peerID string) bool
+
+	// BanPeer disconnects from the given peer and rejects its connections for the specified duration
+	BanPeer(peerID string, duration time.Duration) bool
+
+	// ReportMisbehavior penalizes the given peer, which gets banned if its score drops below the threshold
+	ReportMisbehavior(peerID string, misbehavior types.Misbehavior)
+
 	// RegisterMessageHandler registers message handler
 	RegisterMessageHandler(messageHandler MessageHandler)
 
@@ -26,6 +26,7 @@ import (
 
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/metrics"
 	"github.com/libp2p/go-libp2p-core/network"
 
 	connmgr "github.com/libp2p/go-libp2p-connmgr"
@@ -59,6 +60,7 @@ const (
 	connectInterval                   = 1000 // 1 sec
 	lowConnectivityCheckInterval      = 60
 	highConnectivityCheckInterval     = 10
+	bandwidthCheckInterval            = 10 * time.Second
 )
 
 type Messenger struct {
@@ -66,12 +68,16 @@ type Messenger struct {
 	msgHandlerMap map[common.ChannelIDEnum](p2pl.MessageHandler)
 	config        MessengerConfig
 	seedPeers     map[pr.ID]*pr.AddrInfo
+	privatePeers  map[pr.ID]bool
 	pubsub        *ps.PubSub
 	dht           *kaddht.IpfsDHT
 	needMdns      bool
 	seedPeerOnly  bool
 
 	peerTable    *peer.PeerTable
+	banList      *p2ptypes.BanList
+	scoreBook    *p2ptypes.PeerScoreBook
+	bwCounter    *metrics.BandwidthCounter
 	newPeers     chan pr.ID
 	peerDead     chan pr.ID
 	newPeerError chan pr.ID
@@ -145,6 +151,9 @@ func CreateMessenger(pubKey *crypto.PublicKey, seedPeerMultiAddresses []string,
 
 	messenger := &Messenger{
 		peerTable:           &pt,
+		banList:             p2ptypes.NewBanList(),
+		scoreBook:           p2ptypes.NewPeerScoreBook(viper.GetInt(common.CfgP2PPeerBanThreshold)),
+		bwCounter:           metrics.NewBandwidthCounter(),
 		newPeers:            make(chan pr.ID),
 		peerDead:            make(chan pr.ID),
 		newPeerError:        make(chan pr.ID),
@@ -154,6 +163,7 @@ func CreateMessenger(pubKey *crypto.PublicKey, seedPeerMultiAddresses []string,
 		needMdns:            needMdns,
 		seedPeerOnly:        seedPeerOnly,
 		seedPeers:           make(map[pr.ID]*pr.AddrInfo),
+		privatePeers:        make(map[pr.ID]bool),
 		protocolPrefix:      protocolPrefix,
 		config:              msgrConfig,
 		statsCounter:        make(map[common.ChannelIDEnum]uint64),
@@ -200,6 +210,17 @@ func CreateMessenger(pubKey *crypto.PublicKey, seedPeerMultiAddresses []string,
 	minNumPeers := viper.GetInt(common.CfgP2PMinNumPeers)
 	maxNumPeers := viper.GetInt(common.CfgP2PMaxNumPeers)
 	cm := connmgr.NewConnManager(minNumPeers, maxNumPeers, defaultPeerDiscoveryPulseInterval)
+	for _, id := range strings.Split(viper.GetString(common.CfgP2PPrivatePeerIDs), ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		pid, err := pr.IDB58Decode(id)
+		if err != nil {
+			return messenger, fmt.Errorf("Invalid private peer ID %v: %v", id, err)
+		}
+		messenger.privatePeers[pid] = true
+		cm.Protect(pid, "private") // never trim the connections to the private peers
+	}
 	host, err := libp2p.New(
 		ctx,
 		libp2p.EnableRelay(),
@@ -207,6 +228,7 @@ func CreateMessenger(pubKey *crypto.PublicKey, seedPeerMultiAddresses []string,
 		libp2p.ListenAddrs([]ma.Multiaddr{localNetAddress}...),
 		libp2p.AddrsFactory(addressFactory),
 		libp2p.ConnectionManager(cm),
+		libp2p.BandwidthReporter(messenger.bwCounter),
 	)
 	if err != nil {
 		cancel()
@@ -270,6 +292,26 @@ func (msgr *Messenger) isSeedPeer(pid pr.ID) bool {
 	return isSeed
 }
 
+func (msgr *Messenger) isPrivatePeer(pid pr.ID) bool {
+	return msgr.privatePeers[pid]
+}
+
+// isSecureConnection checks that all the connections to the given peer are encrypted, and
+// authenticated with the public key the peer ID is derived from
+func (msgr *Messenger) isSecureConnection(pid pr.ID) bool {
+	conns := msgr.host.Network().ConnsToPeer(pid)
+	if len(conns) == 0 {
+		return false
+	}
+	for _, conn := range conns {
+		remotePubKey := conn.RemotePublicKey()
+		if remotePubKey == nil || !pid.MatchesPublicKey(remotePubKey) {
+			return false
+		}
+	}
+	return true
+}
+
 func (msgr *Messenger) processLoop(ctx context.Context) {
 	defer func() {
 		// Clean up go routines.
@@ -288,6 +330,17 @@ func (msgr *Messenger) processLoop(ctx context.Context) {
 				continue
 			}
 
+			if msgr.banList.IsBanned(pid.Pretty()) {
+				msgr.host.Network().ClosePeer(pid)
+				continue
+			}
+
+			if viper.GetBool(common.CfgP2PRequireEncryption) && !msgr.isSecureConnection(pid) {
+				logger.Warnf("Rejected peer %v, the connection is not encrypted or not bound to the peer ID", pid)
+				msgr.host.Network().ClosePeer(pid)
+				continue
+			}
+
 			if msgr.seedPeerOnly {
 				if !msgr.isSeedPeer(pid) {
 					msgr.host.Network().ClosePeer(pid)
@@ -296,7 +349,7 @@ func (msgr *Messenger) processLoop(ctx context.Context) {
 				}
 			}
 
-			if int(msgr.peerTable.GetTotalNumPeers(true)) >= viper.GetInt(common.CfgP2PMaxNumPeers) { // only account for blockchain nodes
+			if !msgr.isPrivatePeer(pid) && int(msgr.peerTable.GetTotalNumPeers(true)) >= viper.GetInt(common.CfgP2PMaxNumPeers) { // only account for blockchain nodes
 				msgr.host.Network().ClosePeer(pid)
 				continue
 			}
@@ -519,6 +572,9 @@ func (msgr *Messenger) Start(ctx context.Context) error {
 	go msgr.processLoop(ctx)
 	go msgr.maintainConnectivityRoutine(ctx)
 
+	msgr.wg.Add(1)
+	go msgr.monitorPeerBandwidthRoutine(ctx)
+
 	msgr.statsEnabled = viper.GetBool(common.CfgProfEnabled)
 	if msgr.statsEnabled {
 		go func() {
@@ -676,6 +732,140 @@ func (msgr *Messenger) PeerExists(peerID string) bool {
 	return msgr.peerTable.PeerExists(prID)
 }
 
+// PeerInfos returns the runtime information of all peers
+func (msgr *Messenger) PeerInfos(skipEdgeNode bool) []p2ptypes.PeerInfo {
+	// TODO: support skipEdgeNode
+	allPeers := msgr.peerTable.GetAllPeers(skipEdgeNode)
+	peerInfos := []p2ptypes.PeerInfo{}
+	for _, peer := range *allPeers {
+		pid := peer.ID()
+
+		address := ""
+		direction := p2ptypes.PeerDirectionInbound
+		conns := msgr.host.Network().ConnsToPeer(pid)
+		if len(conns) > 0 {
+			address = conns[0].RemoteMultiaddr().String()
+			if conns[0].Stat().Direction == network.DirOutbound {
+				direction = p2ptypes.PeerDirectionOutbound
+			}
+		} else if len(peer.Addrs()) > 0 {
+			address = peer.Addrs()[0].String()
+		}
+
+		agentVersion := ""
+		if av, err := msgr.host.Peerstore().Get(pid, "AgentVersion"); err == nil {
+			agentVersion, _ = av.(string)
+		}
+
+		bw := msgr.bwCounter.GetBandwidthForPeer(pid)
+		peerInfos = append(peerInfos, p2ptypes.PeerInfo{
+			ID:        pid.Pretty(),
+			Address:   address,
+			Direction: direction,
+			Version:   agentVersion,
+			NodeType:  common.NodeTypeBlockchainNode,
+			IsSeed:    msgr.isSeedPeer(pid),
+			Score:     msgr.scoreBook.Score(pid.Pretty()),
+			Encrypted: msgr.isSecureConnection(pid),
+			LatencyMs: common.JSONUint64(msgr.host.Peerstore().LatencyEWMA(pid) / time.Millisecond),
+			BytesIn:   common.JSONUint64(bw.TotalIn),
+			BytesOut:  common.JSONUint64(bw.TotalOut),
+		})
+	}
+	return peerInfos
+}
+
+// ConnectPeer connects to the peer with the given multiaddress, e.g. /ip4/127.0.0.1/tcp/12000/ipfs/<peerID>
+func (msgr *Messenger) ConnectPeer(peerAddr string) error {
+	addr, err := ma.NewMultiaddr(peerAddr)
+	if err != nil {
+		return err
+	}
+	addrInfo, err := peerstore.InfoFromP2pAddr(addr)
+	if err != nil {
+		return err
+	}
+	if msgr.banList.IsBanned(addrInfo.ID.Pretty()) {
+		return fmt.Errorf("Peer %v is banned", addrInfo.ID.Pretty())
+	}
+	return msgr.host.Connect(msgr.ctx, *addrInfo)
+}
+
+// DisconnectPeer disconnects from the peer specified by the peerID
+func (msgr *Messenger) DisconnectPeer(peerID string) bool {
+	prID, err := pr.IDB58Decode(peerID)
+	if err != nil {
+		return false
+	}
+	if !msgr.peerTable.PeerExists(prID) {
+		return false
+	}
+
+	// The peer will be removed from the peer table by the processLoop once it is declared dead
+	msgr.host.Network().ClosePeer(prID)
+	logger.Infof("Disconnected from peer %v", peerID)
+	return true
+}
+
+// BanPeer disconnects from the given peer and rejects its connections for the specified duration
+func (msgr *Messenger) BanPeer(peerID string, duration time.Duration) bool {
+	if _, err := pr.IDB58Decode(peerID); err != nil {
+		return false
+	}
+	msgr.banList.Ban(peerID, duration)
+	logger.Infof("Banned peer %v for %v", peerID, duration)
+	msgr.DisconnectPeer(peerID)
+	return true
+}
+
+// ReportMisbehavior penalizes the given peer, and bans it if its score drops below the threshold
+func (msgr *Messenger) ReportMisbehavior(peerID string, misbehavior p2ptypes.Misbehavior) {
+	if !viper.GetBool(common.CfgP2PPeerScoringEnabled) {
+		return
+	}
+	prID, err := pr.IDB58Decode(peerID)
+	if err != nil {
+		return
+	}
+	if !msgr.peerTable.PeerExists(prID) {
+		return
+	}
+
+	score, shouldBan := msgr.scoreBook.Penalize(peerID, misbehavior)
+	logger.Infof("Penalized peer %v for %v, score: %v", peerID, misbehavior, score)
+	if shouldBan {
+		msgr.scoreBook.Remove(peerID)
+		msgr.BanPeer(peerID, viper.GetDuration(common.CfgP2PPeerBanDurationSecs)*time.Second)
+	}
+}
+
+// monitorPeerBandwidthRoutine periodically penalizes the peers that send data above the allowed rate
+func (msgr *Messenger) monitorPeerBandwidthRoutine(ctx context.Context) {
+	defer msgr.wg.Done()
+
+	ticker := time.NewTicker(bandwidthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			maxRecvRate := viper.GetFloat64(common.CfgP2PPeerMaxRecvRate)
+			if maxRecvRate <= 0 {
+				continue
+			}
+			for _, pid := range *msgr.peerTable.GetAllPeerIDs() {
+				recvRate := msgr.bwCounter.GetBandwidthForPeer(pid).RateIn
+				if recvRate > maxRecvRate {
+					logger.Warnf("Peer %v exceeded the receive rate limit: %.0f B/s", pid, recvRate)
+					msgr.ReportMisbehavior(pid.Pretty(), p2ptypes.MisbehaviorExcessiveBandwidth)
+				}
+			}
+		}
+	}
+}
+
 func (msgr *Messenger) recordReceivedBytes(cid common.ChannelIDEnum, size int) {
 	if !msgr.statsEnabled {
 		return
@@ -697,7 +887,7 @@ func (msgr *Messenger) printStats() {
 	defer msgr.statsLock.Unlock()
 
 	ret := "Received bytes:"
-	for k := byte(0); k <= byte(common.ChannelIDAggregatedEliteEdgeNodeVotes); k++ {
+	for k := byte(0); k <= byte(common.ChannelIDSnapshot); k++ {
 		v, ok := msgr.statsCounter[common.ChannelIDEnum(k)]
 		if !ok {
 			continue
@@ -765,6 +955,11 @@ func (msgr *Messenger) registerStreamHandler(channelID common.ChannelIDEnum) {
 	msgr.host.SetStreamHandler(protocol.ID(msgr.protocolPrefix+strconv.Itoa(int(channelID))), func(strm network.Stream) {
 		peerID := strm.Conn().RemotePeer()
 
+		if msgr.banList.IsBanned(peerID.Pretty()) {
+			msgr.host.Network().ClosePeer(peerID)
+			return
+		}
+
 		if msgr.seedPeerOnly {
 			if !msgr.isSeedPeer(peerID) {
 				msgr.host.Network().ClosePeer(peerID)
@@ -810,6 +1005,7 @@ func (msgr *Messenger) registerStreamHandler(channelID common.ChannelIDEnum) {
 			message, err := msgHandler.ParseMessage(peerID.String(), channelID, rawPeerMsg)
 			if err != nil {
 				logger.Errorf("Failed to parse message, %v. len(): %v, channel: %v, peer: %v, msg: %v", err, len(rawPeerMsg), channelID, peerID, rawPeerMsg)
+				msgr.ReportMisbehavior(peerID.Pretty(), p2ptypes.MisbehaviorMalformedMessage)
 				return
 			}
 
@@ -835,7 +1031,7 @@ func (msgr *Messenger) readPeerMessageRoutine(stream *transport.BufferedStream,
 		var msgBuffer []byte
 		var bufferSize int
 		var bufferPool chan []byte
-		if channelID == common.ChannelIDBlock || channelID == common.ChannelIDProposal {
+		if channelID == common.ChannelIDBlock || channelID == common.ChannelIDProposal || channelID == common.ChannelIDSnapshot {
 			bufferSize = p2pcmn.MaxBlockMessageSize
 			bufferPool = msgr.msgBlockBufferPool
 		} else {
@@ -869,6 +1065,7 @@ func (msgr *Messenger) readPeerMessageRoutine(stream *transport.BufferedStream,
 		bufferPool <- msgBuffer
 		if err != nil {
 			logger.Errorf("Failed to parse message, %v. msgSize: %v, len(): %v, channel: %v, peer: %v, msg: %v", err, msgSize, len(rawPeerMsg), channelID, peerID, rawPeerMsg)
+			msgr.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
 			return
 		}
 
@@ -887,6 +1084,9 @@ func (msgr *Messenger) attachHandlersToPeer(peer *peer.Peer) {
 			logger.Errorf("Failed to setup message parser for channelID %v", channelID)
 		}
 		message, err := msgHandler.ParseMessage(peerID.String(), channelID, rawMessageBytes)
+		if err != nil {
+			msgr.ReportMisbehavior(peerID.Pretty(), p2ptypes.MisbehaviorMalformedMessage)
+		}
 
 		msgr.recordReceivedBytes(channelID, len(rawMessageBytes))
 
@@ -32,6 +32,7 @@ var Channels = []cmn.ChannelIDEnum{
 	cmn.ChannelIDGuardian,
 	cmn.ChannelIDEliteEdgeNodeVote,
 	cmn.ChannelIDAggregatedEliteEdgeNodeVotes,
+	cmn.ChannelIDSnapshot,
 }
 
 //
@@ -0,0 +1,232 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+/*
+Off-chain micropayments
+
+The payer first reserves a fund for a set of resource IDs with a ReserveFundTx. It then pays the payee
+off-chain with ServicePaymentTxs signed by the payer only. Within a payment sequence, each payment
+carries the accumulated amount, so the payee only needs to keep the latest one. To settle, the payee
+signs the latest payment with the fee and its own account sequence, and broadcasts it. Each on-chain
+settlement between the same payer and payee has to increase the payment sequence by 1.
+*/
+
+var (
+	ErrChannelMismatch   = errors.New("Service payment does not belong to the channel")
+	ErrInvalidSignature  = errors.New("Invalid source signature of the service payment")
+	ErrStalePayment      = errors.New("Service payment amount is not greater than the latest payment")
+	ErrInsufficientFund  = errors.New("Accumulated payment exceeds the reserved fund")
+	ErrNothingToSettle   = errors.New("No service payment to settle")
+	ErrInvalidPayAmount  = errors.New("Payment amount must be positive")
+	ErrThetaNotSupported = errors.New("Cannot send ThetaWei as service payment")
+)
+
+// Signer signs the sign bytes with the private key of the address. The wallets implement this interface.
+type Signer interface {
+	Sign(address common.Address, txrlp common.Bytes) (*crypto.Signature, error)
+}
+
+// ChannelID identifies a payment channel from the payer to the payee for a resource
+type ChannelID struct {
+	ChainID         string
+	Source          common.Address
+	Target          common.Address
+	ResourceID      string
+	ReserveSequence uint64
+}
+
+func (id ChannelID) String() string {
+	return fmt.Sprintf("ChannelID{chain: %v, source: %v, target: %v, resource_id: %v, reserve_sequence: %v}",
+		id.ChainID, id.Source.Hex(), id.Target.Hex(), id.ResourceID, id.ReserveSequence)
+}
+
+// NewServicePaymentTx constructs a service payment of the given TFuel amount, which is not signed yet
+func NewServicePaymentTx(id ChannelID, paymentSequence uint64, tfuelWei *big.Int) *types.ServicePaymentTx {
+	return &types.ServicePaymentTx{
+		Fee: types.NewCoins(0, 0),
+		Source: types.TxInput{
+			Address: id.Source,
+			Coins: types.Coins{
+				ThetaWei: big.NewInt(0),
+				TFuelWei: new(big.Int).Set(tfuelWei),
+			},
+		},
+		Target: types.TxInput{
+			Address: id.Target,
+		},
+		PaymentSequence: paymentSequence,
+		ReserveSequence: id.ReserveSequence,
+		ResourceID:      id.ResourceID,
+	}
+}
+
+// SignAsSource signs the service payment by the payer. The fee and the target sequence are not
+// covered by the source signature, so the payee can set them later.
+func SignAsSource(chainID string, tx *types.ServicePaymentTx, signer Signer) error {
+	sig, err := signer.Sign(tx.Source.Address, tx.SourceSignBytes(chainID))
+	if err != nil {
+		return err
+	}
+	tx.SetSourceSignature(sig)
+	return nil
+}
+
+// SignAsTarget sets the fee and the target sequence, and signs the service payment by the payee.
+// The resulting transaction is ready to be broadcasted.
+func SignAsTarget(chainID string, tx *types.ServicePaymentTx, fee types.Coins, targetSequence uint64, signer Signer) error {
+	tx.Fee = fee
+	tx.Target.Sequence = targetSequence
+	sig, err := signer.Sign(tx.Target.Address, tx.TargetSignBytes(chainID))
+	if err != nil {
+		return err
+	}
+	tx.SetTargetSignature(sig)
+	return nil
+}
+
+// VerifySourceSignature checks whether the service payment is signed by the payer
+func VerifySourceSignature(chainID string, tx *types.ServicePaymentTx) bool {
+	sig := tx.Source.Signature
+	if sig == nil || sig.IsEmpty() {
+		return false
+	}
+	return sig.Verify(tx.SourceSignBytes(chainID), tx.Source.Address)
+}
+
+// Aggregate returns the payment with the largest accumulated amount for each payment sequence
+// of the channel. The payments not belonging to the channel or not properly signed are skipped.
+func Aggregate(id ChannelID, txs []*types.ServicePaymentTx) map[uint64]*types.ServicePaymentTx {
+	latest := make(map[uint64]*types.ServicePaymentTx)
+	for _, tx := range txs {
+		if !belongsTo(id, tx) || !VerifySourceSignature(id.ChainID, tx) {
+			continue
+		}
+		if curr, ok := latest[tx.PaymentSequence]; ok && curr.Source.Coins.TFuelWei.Cmp(tx.Source.Coins.TFuelWei) >= 0 {
+			continue
+		}
+		latest[tx.PaymentSequence] = tx
+	}
+	return latest
+}
+
+func belongsTo(id ChannelID, tx *types.ServicePaymentTx) bool {
+	return tx.Source.Address == id.Source &&
+		tx.Target.Address == id.Target &&
+		tx.ResourceID == id.ResourceID &&
+		tx.ReserveSequence == id.ReserveSequence
+}
+
+//-----------------------------------------------------------------------------
+
+// SourceChannel tracks the payments made by the payer
+type SourceChannel struct {
+	ID              ChannelID
+	PaymentSequence uint64   // payment sequence of the payments not settled yet
+	Accumulated     *big.Int // TFuel paid in the current payment sequence
+	Fund            *big.Int // TFuel reserved for the payments, nil means not limited
+	Paid            *big.Int // TFuel paid in the previous payment sequences
+}
+
+// NewSourceChannel creates a payer side channel, starting from the given payment sequence
+func NewSourceChannel(id ChannelID, paymentSequence uint64, fund *big.Int) *SourceChannel {
+	return &SourceChannel{
+		ID:              id,
+		PaymentSequence: paymentSequence,
+		Accumulated:     big.NewInt(0),
+		Fund:            fund,
+		Paid:            big.NewInt(0),
+	}
+}
+
+// Pay adds the amount to the accumulated payment, and returns the payment signed by the payer
+func (ch *SourceChannel) Pay(tfuelWei *big.Int, signer Signer) (*types.ServicePaymentTx, error) {
+	if tfuelWei == nil || tfuelWei.Sign() <= 0 {
+		return nil, ErrInvalidPayAmount
+	}
+	accumulated := new(big.Int).Add(ch.Accumulated, tfuelWei)
+	if ch.Fund != nil && new(big.Int).Add(ch.Paid, accumulated).Cmp(ch.Fund) > 0 {
+		return nil, ErrInsufficientFund
+	}
+
+	tx := NewServicePaymentTx(ch.ID, ch.PaymentSequence, accumulated)
+	if err := SignAsSource(ch.ID.ChainID, tx, signer); err != nil {
+		return nil, err
+	}
+	ch.Accumulated = accumulated
+	return tx, nil
+}
+
+// Settled moves the channel to the next payment sequence once the payee settled the accumulated payment
+func (ch *SourceChannel) Settled() {
+	ch.Paid.Add(ch.Paid, ch.Accumulated)
+	ch.Accumulated = big.NewInt(0)
+	ch.PaymentSequence++
+}
+
+//-----------------------------------------------------------------------------
+
+// TargetChannel keeps the latest payment received by the payee
+type TargetChannel struct {
+	ID              ChannelID
+	PaymentSequence uint64
+	Latest          *types.ServicePaymentTx
+}
+
+// NewTargetChannel creates a payee side channel, expecting the payments of the given payment sequence
+func NewTargetChannel(id ChannelID, paymentSequence uint64) *TargetChannel {
+	return &TargetChannel{
+		ID:              id,
+		PaymentSequence: paymentSequence,
+	}
+}
+
+// Receive validates the payment, and keeps it if it pays more than the latest payment
+func (ch *TargetChannel) Receive(tx *types.ServicePaymentTx) error {
+	if !belongsTo(ch.ID, tx) || tx.PaymentSequence != ch.PaymentSequence {
+		return ErrChannelMismatch
+	}
+	coins := tx.Source.Coins.NoNil()
+	if coins.ThetaWei.Sign() != 0 {
+		return ErrThetaNotSupported
+	}
+	if !VerifySourceSignature(ch.ID.ChainID, tx) {
+		return ErrInvalidSignature
+	}
+	if ch.Latest != nil && ch.Latest.Source.Coins.TFuelWei.Cmp(coins.TFuelWei) >= 0 {
+		return ErrStalePayment
+	}
+	ch.Latest = tx
+	return nil
+}
+
+// Received returns the TFuel received in the current payment sequence
+func (ch *TargetChannel) Received() *big.Int {
+	if ch.Latest == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(ch.Latest.Source.Coins.TFuelWei)
+}
+
+// Settle signs the latest payment by the payee, and moves the channel to the next payment sequence.
+// The returned transaction is ready to be broadcasted.
+func (ch *TargetChannel) Settle(fee types.Coins, targetSequence uint64, signer Signer) (*types.ServicePaymentTx, error) {
+	if ch.Latest == nil {
+		return nil, ErrNothingToSettle
+	}
+	tx := ch.Latest
+	if err := SignAsTarget(ch.ID.ChainID, tx, fee, targetSequence, signer); err != nil {
+		return nil, err
+	}
+	ch.Latest = nil
+	ch.PaymentSequence++
+	return tx, nil
+}
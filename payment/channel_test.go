@@ -0,0 +1,109 @@
+package payment
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+type keySigner map[common.Address]*crypto.PrivateKey
+
+func (s keySigner) Sign(address common.Address, txrlp common.Bytes) (*crypto.Signature, error) {
+	key, ok := s[address]
+	if !ok {
+		return nil, errors.New("unknown address")
+	}
+	return key.Sign(txrlp)
+}
+
+func newKey(signer keySigner) common.Address {
+	privKey, pubKey, _ := crypto.GenerateKeyPair()
+	signer[pubKey.Address()] = privKey
+	return pubKey.Address()
+}
+
+func TestPaymentChannel(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := keySigner{}
+	id := ChannelID{
+		ChainID:         "testchain",
+		Source:          newKey(signer),
+		Target:          newKey(signer),
+		ResourceID:      "rid001",
+		ReserveSequence: 1,
+	}
+
+	source := NewSourceChannel(id, 1, big.NewInt(1000))
+	target := NewTargetChannel(id, 1)
+
+	tx1, err := source.Pay(big.NewInt(100), signer)
+	assert.Nil(err)
+	tx2, err := source.Pay(big.NewInt(200), signer)
+	assert.Nil(err)
+	assert.Equal(big.NewInt(300), tx2.Source.Coins.TFuelWei)
+
+	assert.Nil(target.Receive(tx2))
+	assert.Equal(ErrStalePayment, target.Receive(tx1))
+	assert.Equal(big.NewInt(300), target.Received())
+
+	// Payments exceeding the reserved fund are rejected by the payer
+	_, err = source.Pay(big.NewInt(800), signer)
+	assert.Equal(ErrInsufficientFund, err)
+
+	// Tampered payments are rejected by the payee
+	forged := NewServicePaymentTx(id, 1, big.NewInt(500))
+	forged.Source.Signature = tx2.Source.Signature
+	assert.Equal(ErrInvalidSignature, target.Receive(forged))
+
+	settled, err := target.Settle(types.NewCoins(0, 10), 5, signer)
+	assert.Nil(err)
+	assert.True(VerifySourceSignature(id.ChainID, settled))
+	assert.True(settled.Target.Signature.Verify(settled.TargetSignBytes(id.ChainID), id.Target))
+	assert.Equal(uint64(5), settled.Target.Sequence)
+	assert.Equal(uint64(2), target.PaymentSequence)
+
+	_, err = target.Settle(types.NewCoins(0, 10), 6, signer)
+	assert.Equal(ErrNothingToSettle, err)
+
+	source.Settled()
+	tx3, err := source.Pay(big.NewInt(50), signer)
+	assert.Nil(err)
+	assert.Equal(uint64(2), tx3.PaymentSequence)
+	assert.Equal(big.NewInt(50), tx3.Source.Coins.TFuelWei)
+	assert.Nil(target.Receive(tx3))
+}
+
+func TestAggregate(t *testing.T) {
+	assert := assert.New(t)
+
+	signer := keySigner{}
+	id := ChannelID{
+		ChainID:         "testchain",
+		Source:          newKey(signer),
+		Target:          newKey(signer),
+		ResourceID:      "rid001",
+		ReserveSequence: 1,
+	}
+
+	var txs []*types.ServicePaymentTx
+	for seq, amount := range map[uint64]int64{1: 100, 2: 30} {
+		for i := int64(1); i <= 3; i++ {
+			tx := NewServicePaymentTx(id, seq, big.NewInt(amount*i))
+			assert.Nil(SignAsSource(id.ChainID, tx, signer))
+			txs = append(txs, tx)
+		}
+	}
+	unsigned := NewServicePaymentTx(id, 1, big.NewInt(10000))
+	txs = append(txs, unsigned)
+
+	latest := Aggregate(id, txs)
+	assert.Equal(2, len(latest))
+	assert.Equal(big.NewInt(300), latest[1].Source.Coins.TFuelWei)
+	assert.Equal(big.NewInt(90), latest[2].Source.Coins.TFuelWei)
+}
@@ -17,7 +17,9 @@
 package rlp
 
 import (
+	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 )
 
@@ -154,3 +156,60 @@ func readSize(b []byte, slen byte) (uint64, error) {
 	}
 	return s, nil
 }
+
+// The Append functions below produce the same bytes as Encode, without the
+// reflection. They are meant for the EncodeRLP methods of the hot types.
+
+// AppendUint64 appends the RLP encoding of i to b.
+func AppendUint64(b []byte, i uint64) []byte {
+	if i == 0 {
+		return append(b, 0x80)
+	}
+	if i < 128 {
+		return append(b, byte(i))
+	}
+	var buf [9]byte
+	s := putint(buf[1:], i)
+	buf[0] = 0x80 + byte(s)
+	return append(b, buf[:s+1]...)
+}
+
+// AppendString appends the RLP encoding of the byte string s to b.
+func AppendString(b []byte, s []byte) []byte {
+	if len(s) == 1 && s[0] <= 0x7F {
+		return append(b, s[0])
+	}
+	var buf [9]byte
+	b = append(b, buf[:puthead(buf[:], 0x80, 0xB7, uint64(len(s)))]...)
+	return append(b, s...)
+}
+
+// AppendBigInt appends the RLP encoding of i to b. A nil i is encoded as zero.
+func AppendBigInt(b []byte, i *big.Int) ([]byte, error) {
+	if i == nil {
+		return append(b, 0x80), nil
+	}
+	if cmp := i.Sign(); cmp < 0 {
+		return b, fmt.Errorf("rlp: cannot encode negative *big.Int")
+	} else if cmp == 0 {
+		return append(b, 0x80), nil
+	}
+	return AppendString(b, i.Bytes()), nil
+}
+
+// AppendList appends the RLP encoding of the list with the given encoded
+// elements to b.
+func AppendList(b []byte, content []byte) []byte {
+	var buf [9]byte
+	b = append(b, buf[:puthead(buf[:], 0xC0, 0xF7, uint64(len(content)))]...)
+	return append(b, content...)
+}
+
+// AppendValue appends the RLP encoding of val to b, using Encode.
+func AppendValue(b []byte, val interface{}) ([]byte, error) {
+	enc, err := EncodeToBytes(val)
+	if err != nil {
+		return b, err
+	}
+	return append(b, enc...), nil
+}
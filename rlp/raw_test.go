@@ -19,6 +19,7 @@ package rlp
 import (
 	"bytes"
 	"io"
+	"math/big"
 	"reflect"
 	"testing"
 )
@@ -194,3 +195,64 @@ func TestReadSize(t *testing.T) {
 		}
 	}
 }
+
+func TestAppend(t *testing.T) {
+	for _, i := range []uint64{0, 1, 0x7F, 0x80, 0xFF, 0x0102, 0xFFFFFF, 1<<64 - 1} {
+		want, _ := EncodeToBytes(i)
+		if got := AppendUint64([]byte{0xAA}, i); !bytes.Equal(got, append([]byte{0xAA}, want...)) {
+			t.Errorf("AppendUint64(%d): got %x, want aa%x", i, got, want)
+		}
+	}
+
+	for _, s := range [][]byte{nil, {}, {0x00}, {0x7F}, {0x80}, bytes.Repeat([]byte{0x01}, 55), bytes.Repeat([]byte{0x01}, 56), bytes.Repeat([]byte{0x01}, 1024)} {
+		want, _ := EncodeToBytes(s)
+		if got := AppendString(nil, s); !bytes.Equal(got, want) {
+			t.Errorf("AppendString(%x): got %x, want %x", s, got, want)
+		}
+	}
+
+	for _, i := range []*big.Int{nil, big.NewInt(0), big.NewInt(1), big.NewInt(0x80), new(big.Int).Lsh(big.NewInt(1), 300)} {
+		want, _ := EncodeToBytes(i)
+		got, err := AppendBigInt(nil, i)
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("AppendBigInt(%v): got %x, %v, want %x", i, got, err, want)
+		}
+	}
+	if _, err := AppendBigInt(nil, big.NewInt(-1)); err == nil {
+		t.Errorf("AppendBigInt(-1): expected error")
+	}
+
+	for _, l := range [][]uint64{{}, {1, 2, 3}, make([]uint64, 60)} {
+		want, _ := EncodeToBytes(l)
+		content := []byte{}
+		for _, i := range l {
+			content = AppendUint64(content, i)
+		}
+		if got := AppendList(nil, content); !bytes.Equal(got, want) {
+			t.Errorf("AppendList(%v): got %x, want %x", l, got, want)
+		}
+	}
+}
+
+func TestStreamReadBytesAndBigInt(t *testing.T) {
+	var b [3]byte
+	if err := NewStream(bytes.NewReader(unhex("83010203")), 0).ReadBytes(b[:]); err != nil || b != [3]byte{1, 2, 3} {
+		t.Errorf("ReadBytes: got %x, %v", b, err)
+	}
+	if err := NewStream(bytes.NewReader(unhex("820102")), 0).ReadBytes(b[:]); err == nil {
+		t.Errorf("ReadBytes: expected size error")
+	}
+	if err := NewStream(bytes.NewReader(unhex("C0")), 0).ReadBytes(b[:]); err != ErrExpectedString {
+		t.Errorf("ReadBytes: got %v, want %v", err, ErrExpectedString)
+	}
+
+	if i, err := NewStream(bytes.NewReader(unhex("820102")), 0).BigInt(); err != nil || i.Cmp(big.NewInt(0x0102)) != 0 {
+		t.Errorf("BigInt: got %v, %v", i, err)
+	}
+	if i, err := NewStream(bytes.NewReader(unhex("80")), 0).BigInt(); err != nil || i.Sign() != 0 {
+		t.Errorf("BigInt: got %v, %v", i, err)
+	}
+	if _, err := NewStream(bytes.NewReader(unhex("820001")), 0).BigInt(); err != ErrCanonInt {
+		t.Errorf("BigInt: got %v, want %v", err, ErrCanonInt)
+	}
+}
@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+const defaultPeerBanDuration = time.Hour
+
+var errAdminRPCDisabled = errors.New("admin RPC methods are disabled, set rpc.adminEnabled to true to enable them")
+
+func checkAdminEnabled() error {
+	if !viper.GetBool(common.CfgRPCAdminEnabled) {
+		return errAdminRPCDisabled
+	}
+	return nil
+}
+
+// ------------------------------- AddPeer -----------------------------------
+
+type AddPeerArgs struct {
+	Address string `json:"address"` // host:port for the legacy network, multiaddress for libp2p
+}
+
+type AddPeerResult struct {
+	Success bool `json:"success"`
+}
+
+func (t *ThetaRPCService) AddPeer(args *AddPeerArgs, result *AddPeerResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if args.Address == "" {
+		return errors.New("peer address must be specified")
+	}
+
+	err = t.dispatcher.ConnectPeer(args.Address)
+	if err != nil {
+		return err
+	}
+	result.Success = true
+	return nil
+}
+
+// ------------------------------- RemovePeer -----------------------------------
+
+type RemovePeerArgs struct {
+	PeerID string `json:"peer_id"`
+}
+
+type RemovePeerResult struct {
+	Success bool `json:"success"`
+}
+
+func (t *ThetaRPCService) RemovePeer(args *RemovePeerArgs, result *RemovePeerResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if args.PeerID == "" {
+		return errors.New("peer ID must be specified")
+	}
+
+	result.Success = t.dispatcher.DisconnectPeer(args.PeerID)
+	return nil
+}
+
+// ------------------------------- BanPeer -----------------------------------
+
+type BanPeerArgs struct {
+	PeerID          string            `json:"peer_id"`
+	DurationSeconds common.JSONUint64 `json:"duration_seconds"` // defaults to one hour if not specified
+}
+
+type BanPeerResult struct {
+	Success bool `json:"success"`
+}
+
+func (t *ThetaRPCService) BanPeer(args *BanPeerArgs, result *BanPeerResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if args.PeerID == "" {
+		return errors.New("peer ID must be specified")
+	}
+
+	duration := time.Duration(args.DurationSeconds) * time.Second
+	if duration == 0 {
+		duration = defaultPeerBanDuration
+	}
+
+	result.Success = t.dispatcher.BanPeer(args.PeerID, duration)
+	return nil
+}
+
+// ------------------------------- SetMinTxFee -----------------------------------
+
+type SetMinTxFeeArgs struct {
+	TxType string          `json:"tx_type"` // e.g. send, smart_contract
+	Fee    *common.JSONBig `json:"fee"`     // in TFuelWei, the gas price for smart_contract; zero removes the minimum
+}
+
+type SetMinTxFeeResult struct {
+	Success bool `json:"success"`
+}
+
+// SetMinTxFee adjusts the minimum fee the node accepts into its mempool at runtime, until the
+// node restarts or reloads the minimum fees in the config.
+func (t *ThetaRPCService) SetMinTxFee(args *SetMinTxFeeArgs, result *SetMinTxFeeResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	txType, ok := types.TxTypeFromString(args.TxType)
+	if !ok || !ledger.IsMinTxFeeTxType(txType) {
+		return fmt.Errorf("invalid tx type: %v", args.TxType)
+	}
+	if args.Fee == nil || args.Fee.ToInt().Sign() < 0 {
+		return errors.New("fee must be non-negative")
+	}
+
+	t.ledger.MinTxFees().Set(txType, args.Fee.ToInt())
+	result.Success = true
+	return nil
+}
+
+// ------------------------------- ReloadConfig -----------------------------------
+
+type ReloadConfigArgs struct {
+}
+
+type ReloadConfigResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"` // changed in the config file, but only read on startup
+}
+
+// ReloadConfig reloads the non-critical parameters, e.g. the log levels and the min tx fees, from
+// the config file without restarting the node
+func (t *ThetaRPCService) ReloadConfig(args *ReloadConfigArgs, result *ReloadConfigResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	reloaded, err := common.ReloadConfig()
+	if reloaded != nil {
+		result.Applied = reloaded.Applied
+		result.RestartRequired = reloaded.RestartRequired
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestAdminRPCDisabledByDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &ThetaRPCService{}
+
+	err := service.AddPeer(&AddPeerArgs{Address: "127.0.0.1:12000"}, &AddPeerResult{})
+	assert.Equal(errAdminRPCDisabled, err)
+
+	err = service.RemovePeer(&RemovePeerArgs{PeerID: "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}, &RemovePeerResult{})
+	assert.Equal(errAdminRPCDisabled, err)
+
+	err = service.BanPeer(&BanPeerArgs{PeerID: "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}, &BanPeerResult{})
+	assert.Equal(errAdminRPCDisabled, err)
+}
+
+func TestAdminRPCArgsValidation(t *testing.T) {
+	assert := assert.New(t)
+
+	viper.Set(common.CfgRPCAdminEnabled, true)
+	defer viper.Set(common.CfgRPCAdminEnabled, false)
+
+	service := &ThetaRPCService{}
+
+	err := service.AddPeer(&AddPeerArgs{}, &AddPeerResult{})
+	assert.NotNil(err)
+
+	err = service.RemovePeer(&RemovePeerArgs{}, &RemovePeerResult{})
+	assert.NotNil(err)
+
+	err = service.BanPeer(&BanPeerArgs{}, &BanPeerResult{})
+	assert.NotNil(err)
+}
@@ -2,9 +2,12 @@ package rpc
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
 	"github.com/thetatoken/theta/ledger/state"
 	"github.com/thetatoken/theta/ledger/types"
 	"github.com/thetatoken/theta/ledger/vm"
@@ -65,3 +68,63 @@ func (t *ThetaRPCService) CallSmartContract(args *CallSmartContractArgs, result
 
 	return nil
 }
+
+// ------------------------------- ComputeContractAddress -----------------------------------
+
+type ComputeContractAddressArgs struct {
+	From     common.Address    `json:"from"`      // the deployer, i.e. the tx sender or the factory contract
+	Sequence common.JSONUint64 `json:"sequence"`  // sequence of the deploying tx, zero means the next sequence of the deployer
+	Salt     string            `json:"salt"`      // salt of the CREATE2 deployment, empty for the regular deployment
+	Code     string            `json:"code"`      // init code of the CREATE2 deployment
+	CodeHash string            `json:"code_hash"` // keccak256 hash of the init code, alternative to the init code
+}
+
+type ComputeContractAddressResult struct {
+	ContractAddress common.Address `json:"contract_address"`
+}
+
+// ComputeContractAddress computes the address of a contract before it is deployed. Without the salt, it computes
+// the address of the contract deployed by a SmartContractTx or the CREATE opcode, which depends on the sequence
+// of the deployer. Otherwise, it computes the address of the contract deployed by the CREATE2 opcode, which only
+// depends on the factory contract, the salt and the init code.
+func (t *ThetaRPCService) ComputeContractAddress(args *ComputeContractAddressArgs, result *ComputeContractAddressResult) (err error) {
+	if args.From.IsEmpty() {
+		return errors.New("Deployer address must be specified")
+	}
+
+	if args.Salt != "" {
+		salt, err := hex.DecodeString(strings.TrimPrefix(args.Salt, "0x"))
+		if err != nil || len(salt) > common.HashLength {
+			return fmt.Errorf("Invalid salt: %v", args.Salt)
+		}
+		var codeHash common.Hash
+		if args.CodeHash != "" {
+			codeHash = common.HexToHash(args.CodeHash)
+		} else {
+			code, err := hex.DecodeString(strings.TrimPrefix(args.Code, "0x"))
+			if err != nil || len(code) == 0 {
+				return errors.New("Either the init code or its hash must be specified")
+			}
+			codeHash = crypto.Keccak256Hash(code)
+		}
+		result.ContractAddress = crypto.CreateAddress2(args.From, common.BytesToHash(salt), codeHash.Bytes())
+		return nil
+	}
+
+	// The EVM derives the address from the account sequence before it is incremented by the deploying tx
+	var nonce uint64
+	if args.Sequence > 0 {
+		nonce = uint64(args.Sequence) - 1
+	} else {
+		ledgerState, err := t.ledger.GetDeliveredSnapshot()
+		if err != nil {
+			return err
+		}
+		if account := ledgerState.GetAccount(args.From); account != nil {
+			nonce = account.Sequence
+		}
+	}
+	result.ContractAddress = crypto.CreateAddress(args.From, nonce)
+
+	return nil
+}
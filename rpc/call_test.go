@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+func TestComputeContractAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &ThetaRPCService{}
+	deployer := common.HexToAddress("0xdeadbeef00000000000000000000000000000000")
+
+	// CREATE2 address, using the test vector of EIP-1014
+	args := &ComputeContractAddressArgs{
+		From: deployer,
+		Salt: "0x000000000000000000000000feed000000000000000000000000000000000000",
+		Code: "0x00",
+	}
+	result := &ComputeContractAddressResult{}
+	assert.Nil(service.ComputeContractAddress(args, result))
+	assert.Equal(common.HexToAddress("0xD04116cDd17beBE565EB2422F2497E06cC1C9833"), result.ContractAddress)
+
+	// The init code hash gives the same address as the init code
+	args.Code = ""
+	args.CodeHash = crypto.Keccak256Hash([]byte{0x00}).Hex()
+	result = &ComputeContractAddressResult{}
+	assert.Nil(service.ComputeContractAddress(args, result))
+	assert.Equal(common.HexToAddress("0xD04116cDd17beBE565EB2422F2497E06cC1C9833"), result.ContractAddress)
+
+	// CREATE address of the deploying tx with the given sequence
+	args = &ComputeContractAddressArgs{
+		From:     deployer,
+		Sequence: common.JSONUint64(3),
+	}
+	result = &ComputeContractAddressResult{}
+	assert.Nil(service.ComputeContractAddress(args, result))
+	assert.Equal(crypto.CreateAddress(deployer, 2), result.ContractAddress)
+
+	// Invalid arguments
+	assert.NotNil(service.ComputeContractAddress(&ComputeContractAddressArgs{}, result))
+	assert.NotNil(service.ComputeContractAddress(&ComputeContractAddressArgs{From: deployer, Salt: "0x01"}, result))
+}
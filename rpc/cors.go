@@ -0,0 +1,60 @@
+package rpc
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/thetatoken/theta/common"
+	"golang.org/x/net/websocket"
+)
+
+// corsMiddleware allows the browsers to call the handler from the origins specified by the config
+func corsMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := allowedOrigin(r.Header.Get("Origin")); origin != "" {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if origin != "*" {
+				w.Header().Add("Vary", "Origin")
+			}
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(common.GetReloadableStringSlice(common.CfgRPCCORSAllowedHeaders), ", "))
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		}
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// allowedOrigin returns the value of the Access-Control-Allow-Origin header for the request
+// origin, or an empty string if the origin is not allowed
+func allowedOrigin(origin string) string {
+	for _, allowed := range common.GetReloadableStringSlice(common.CfgRPCCORSAllowedOrigins) {
+		if allowed == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// checkWebsocketOrigin rejects the websocket connections from the browser origins not allowed
+// by the config. The connections without the Origin header, i.e. not from the browsers, are accepted.
+func checkWebsocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if allowedOrigin(origin) == "" {
+		return fmt.Errorf("Origin %v is not allowed", origin)
+	}
+	var err error
+	config.Origin, err = websocket.Origin(config, r)
+	return err
+}
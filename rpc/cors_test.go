@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	request := func(method string, origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/rpc", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	viper.Set(common.CfgRPCCORSAllowedOrigins, []string{"*"})
+	w := request("POST", "https://dapp.example.com")
+	assert.Equal(http.StatusTeapot, w.Code)
+	assert.Equal("*", w.Header().Get("Access-Control-Allow-Origin"))
+
+	viper.Set(common.CfgRPCCORSAllowedOrigins, []string{"https://dapp.example.com"})
+	viper.Set(common.CfgRPCCORSAllowedHeaders, []string{"Content-Type", "Authorization"})
+	defer viper.Set(common.CfgRPCCORSAllowedOrigins, []string{"*"})
+	defer viper.Set(common.CfgRPCCORSAllowedHeaders, []string{"*"})
+
+	w = request("OPTIONS", "https://dapp.example.com")
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal("https://dapp.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal("Content-Type, Authorization", w.Header().Get("Access-Control-Allow-Headers"))
+
+	w = request("POST", "https://other.example.com")
+	assert.Equal(http.StatusTeapot, w.Code)
+	assert.Equal("", w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -0,0 +1,142 @@
+package rpc
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// ------------------------------- DecodeTransaction -----------------------------------
+
+type DecodeTransactionArgs struct {
+	TxBytes string `json:"tx_bytes"`
+}
+
+// TxSigner is an address the transaction needs the signature of
+type TxSigner struct {
+	Address common.Address `json:"address"`
+	Role    string         `json:"role"`
+	Signed  bool           `json:"signed"`
+}
+
+type DecodeTransactionResult struct {
+	TxHash  common.Hash       `json:"hash"`
+	Type    string            `json:"type"`
+	TypeID  common.JSONUint64 `json:"type_id"`
+	Tx      types.Tx          `json:"transaction"`
+	Signers []TxSigner        `json:"signers"`
+	Fee     types.Coins       `json:"fee"`    // For a smart contract tx, the max fee, i.e. gas price * gas limit
+	Signed  bool              `json:"signed"` // Whether all the signers have signed
+}
+
+// DecodeTransaction decodes the raw transaction, signed or not, without checking it against the
+// ledger state
+func (t *ThetaRPCService) DecodeTransaction(args *DecodeTransactionArgs, result *DecodeTransactionResult) (err error) {
+	txBytes, err := decodeTxHexBytes(args.TxBytes)
+	if err != nil {
+		return err
+	}
+	decoded, err := DecodeTx(txBytes)
+	if err != nil {
+		return err
+	}
+	*result = *decoded
+	return nil
+}
+
+// DecodeTx decodes the raw transaction into its type, signers and fee
+func DecodeTx(txBytes []byte) (*DecodeTransactionResult, error) {
+	if len(txBytes) == 0 {
+		return nil, errors.New("tx_bytes must be specified")
+	}
+	tx, err := types.TxFromBytes(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	txType, err := types.GetTxType(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecodeTransactionResult{
+		TxHash:  crypto.Keccak256Hash(txBytes),
+		Type:    txType.String(),
+		TypeID:  common.JSONUint64(txType),
+		Tx:      tx,
+		Signers: txSigners(tx),
+		Fee:     txFee(tx),
+		Signed:  true,
+	}
+	for _, signer := range result.Signers {
+		result.Signed = result.Signed && signer.Signed
+	}
+	return result, nil
+}
+
+// txSigners returns the signers of the transaction, in the order of the signatures it carries
+func txSigners(tx types.Tx) []TxSigner {
+	signers := []TxSigner{}
+	add := func(role string, input types.TxInput) {
+		signers = append(signers, TxSigner{
+			Address: input.Address,
+			Role:    role,
+			Signed:  input.Signature != nil && !input.Signature.IsEmpty(),
+		})
+	}
+	switch tx := tx.(type) {
+	case *types.CoinbaseTx:
+		add("proposer", tx.Proposer)
+	case *types.SlashTx:
+		add("proposer", tx.Proposer)
+	case *types.SendTx:
+		for _, input := range tx.Inputs {
+			add("input", input)
+		}
+	case *types.ReserveFundTx:
+		add("source", tx.Source)
+	case *types.ReleaseFundTx:
+		add("source", tx.Source)
+	case *types.ServicePaymentTx:
+		add("source", tx.Source)
+		add("target", tx.Target)
+	case *types.SplitRuleTx:
+		add("initiator", tx.Initiator)
+	case *types.SmartContractTx:
+		add("from", tx.From)
+	case *types.DepositStakeTx:
+		add("source", tx.Source)
+	case *types.DepositStakeTxV2:
+		add("source", tx.Source)
+	case *types.WithdrawStakeTx:
+		add("source", tx.Source)
+	case *types.StakeRewardDistributionTx:
+		add("holder", tx.Holder)
+	case *types.VestingTx:
+		add("source", tx.Source)
+	case *types.RotateValidatorKeyTx:
+		add("holder", tx.Holder)
+		add("signing_key", types.TxInput{Address: tx.SigningAddress, Signature: tx.SigningSig})
+	}
+	return signers
+}
+
+// txFee returns the fee of the transaction. The fee of a smart contract tx depends on the gas
+// used, so its max fee is returned.
+func txFee(tx types.Tx) types.Coins {
+	if sctx, ok := tx.(*types.SmartContractTx); ok {
+		maxFee := new(big.Int).SetUint64(sctx.GasLimit)
+		if sctx.GasPrice != nil {
+			maxFee.Mul(maxFee, sctx.GasPrice)
+		} else {
+			maxFee.SetUint64(0)
+		}
+		return types.Coins{
+			ThetaWei: big.NewInt(0),
+			TFuelWei: maxFee,
+		}
+	}
+	return types.FixedTxFee(tx)
+}
@@ -0,0 +1,61 @@
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestDecodeTx(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	from := privKey.PublicKey().Address()
+	to := common.HexToAddress("0x70f587259738cB626A1720Af7038B8DcDb6a42a0")
+
+	tx := &types.SendTx{
+		Fee:     types.NewCoins(0, 1000),
+		Inputs:  []types.TxInput{{Address: from, Coins: types.NewCoins(0, 1010), Sequence: 1}},
+		Outputs: []types.TxOutput{{Address: to, Coins: types.NewCoins(0, 10)}},
+	}
+	raw, err := types.TxToBytes(tx)
+	assert.Nil(err)
+	decoded, err := DecodeTx(raw)
+	assert.Nil(err)
+	assert.Equal("send", decoded.Type)
+	assert.Equal(crypto.Keccak256Hash(raw), decoded.TxHash)
+	assert.Equal(int64(1000), decoded.Fee.TFuelWei.Int64())
+	assert.Equal(1, len(decoded.Signers))
+	assert.Equal(from, decoded.Signers[0].Address)
+	assert.False(decoded.Signed)
+
+	sig, err := privKey.Sign(tx.SignBytes("privatenet"))
+	assert.Nil(err)
+	tx.Inputs[0].Signature = sig
+	raw, err = types.TxToBytes(tx)
+	assert.Nil(err)
+	decoded, err = DecodeTx(raw)
+	assert.Nil(err)
+	assert.True(decoded.Signed)
+
+	// The max fee of a smart contract tx
+	raw, err = types.TxToBytes(&types.SmartContractTx{
+		From:     types.TxInput{Address: from, Coins: types.NewCoins(0, 0), Sequence: 2},
+		To:       types.TxOutput{Address: to},
+		GasLimit: 50000,
+		GasPrice: big.NewInt(4000),
+	})
+	assert.Nil(err)
+	decoded, err = DecodeTx(raw)
+	assert.Nil(err)
+	assert.Equal("smart_contract", decoded.Type)
+	assert.Equal(int64(200000000), decoded.Fee.TFuelWei.Int64())
+
+	_, err = DecodeTx([]byte{0x01, 0x02})
+	assert.NotNil(err)
+}
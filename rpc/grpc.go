@@ -0,0 +1,385 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc/grpcpb"
+)
+
+// The streaming methods are woken up by the chain events, and also check the chain for the new
+// and finalized blocks at the poll interval in case the event subscription is dropped
+const (
+	grpcStreamPollInterval = 1 * time.Second
+	grpcStreamEventBuffer  = 64
+)
+
+// ThetaGRPCService implements the gRPC API. It serves the queries and the broadcasts through the
+// ThetaRPCService, so both APIs return the same data.
+type ThetaGRPCService struct {
+	*ThetaRPCService
+
+	server *grpc.Server
+}
+
+var _ grpcpb.ThetaServer = (*ThetaGRPCService)(nil)
+
+func newThetaGRPCService(rpcService *ThetaRPCService) *ThetaGRPCService {
+	t := &ThetaGRPCService{
+		ThetaRPCService: rpcService,
+		server:          grpc.NewServer(),
+	}
+	grpcpb.RegisterThetaServer(t.server, t)
+	return t
+}
+
+func (t *ThetaGRPCService) serve() {
+	address := viper.GetString(common.CfgGRPCAddress)
+	port := viper.GetString(common.CfgGRPCPort)
+	l, err := net.Listen("tcp", address+":"+port)
+	if err != nil {
+		logger.WithFields(log.Fields{"error": err}).Fatal("Failed to create gRPC listener")
+	} else {
+		logger.WithFields(log.Fields{"address": address, "port": port}).Info("gRPC server started")
+	}
+
+	logger.Info(t.server.Serve(l))
+}
+
+func (t *ThetaGRPCService) stop() {
+	t.server.Stop()
+}
+
+// ------------------------------- GetStatus -----------------------------------
+
+func (t *ThetaGRPCService) GetStatus(ctx context.Context, req *grpcpb.GetStatusRequest) (*grpcpb.Status, error) {
+	result := &GetStatusResult{}
+	if err := t.ThetaRPCService.GetStatus(&GetStatusArgs{}, result); err != nil {
+		return nil, err
+	}
+	return &grpcpb.Status{
+		Address:                    common.HexToAddress(result.Address).Bytes(),
+		ChainId:                    result.ChainID,
+		PeerId:                     result.PeerID,
+		LatestFinalizedBlockHash:   result.LatestFinalizedBlockHash.Bytes(),
+		LatestFinalizedBlockHeight: uint64(result.LatestFinalizedBlockHeight),
+		LatestFinalizedBlockTime:   bigToString((*big.Int)(result.LatestFinalizedBlockTime)),
+		LatestFinalizedBlockEpoch:  uint64(result.LatestFinalizedBlockEpoch),
+		CurrentEpoch:               uint64(result.CurrentEpoch),
+		CurrentHeight:              uint64(result.CurrentHeight),
+		CurrentTime:                bigToString((*big.Int)(result.CurrentTime)),
+		Syncing:                    result.Syncing,
+		GenesisBlockHash:           result.GenesisBlockHash.Bytes(),
+	}, nil
+}
+
+// ------------------------------- GetAccount -----------------------------------
+
+func (t *ThetaGRPCService) GetAccount(ctx context.Context, req *grpcpb.GetAccountRequest) (*grpcpb.Account, error) {
+	if len(req.Address) != common.AddressLength {
+		return nil, status.Error(codes.InvalidArgument, "Invalid address")
+	}
+	address := common.BytesToAddress(req.Address)
+	result := &GetAccountResult{}
+	args := &GetAccountArgs{
+		Address: address.Hex(),
+		Height:  common.JSONUint64(req.Height),
+		Preview: req.Preview,
+	}
+	if err := t.ThetaRPCService.GetAccount(args, result); err != nil {
+		return nil, err
+	}
+	if result.Account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account with address %v is not found", address.Hex())
+	}
+
+	account := result.Account
+	balance := account.Balance.NoNil()
+	return &grpcpb.Account{
+		Address:                address.Bytes(),
+		Sequence:               account.Sequence,
+		ThetaWei:               balance.ThetaWei.String(),
+		TfuelWei:               balance.TFuelWei.String(),
+		Root:                   account.Root.Bytes(),
+		CodeHash:               account.CodeHash.Bytes(),
+		LastUpdatedBlockHeight: account.LastUpdatedBlockHeight,
+	}, nil
+}
+
+// ------------------------------- GetBlock -----------------------------------
+
+func (t *ThetaGRPCService) GetBlock(ctx context.Context, req *grpcpb.GetBlockRequest) (*grpcpb.Block, error) {
+	if len(req.Hash) != common.HashLength {
+		return nil, status.Error(codes.InvalidArgument, "Invalid block hash")
+	}
+	block, err := t.chain.FindBlock(common.BytesToHash(req.Hash))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return blockToProto(block), nil
+}
+
+// ------------------------------- GetBlockByHeight -----------------------------------
+
+func (t *ThetaGRPCService) GetBlockByHeight(ctx context.Context, req *grpcpb.GetBlockByHeightRequest) (*grpcpb.Block, error) {
+	block := t.findFinalizedBlockByHeight(req.Height)
+	if block == nil {
+		return nil, status.Errorf(codes.NotFound, "Finalized block at height %v is not found", req.Height)
+	}
+	return blockToProto(block), nil
+}
+
+// ------------------------------- GetTransaction -----------------------------------
+
+func (t *ThetaGRPCService) GetTransaction(ctx context.Context, req *grpcpb.GetTransactionRequest) (*grpcpb.TransactionStatus, error) {
+	if len(req.Hash) != common.HashLength {
+		return nil, status.Error(codes.InvalidArgument, "Invalid transaction hash")
+	}
+	hash := common.BytesToHash(req.Hash)
+	result := &GetTransactionResult{}
+	if err := t.ThetaRPCService.GetTransaction(&GetTransactionArgs{Hash: hash.Hex()}, result); err != nil {
+		return nil, err
+	}
+
+	ret := &grpcpb.TransactionStatus{
+		Status: string(result.Status),
+	}
+	if result.Tx == nil {
+		return ret, nil
+	}
+	ret.BlockHash = result.BlockHash.Bytes()
+	ret.BlockHeight = uint64(result.BlockHeight)
+	raw, err := types.TxToBytes(result.Tx)
+	if err != nil {
+		return nil, err
+	}
+	ret.Transaction = txToProto(raw)
+	return ret, nil
+}
+
+// ------------------------------- BroadcastRawTransaction -----------------------------------
+
+func (t *ThetaGRPCService) BroadcastRawTransaction(ctx context.Context, req *grpcpb.BroadcastRawTransactionRequest) (*grpcpb.BroadcastRawTransactionResponse, error) {
+	result := &BroadcastRawTransactionResult{}
+	args := &BroadcastRawTransactionArgs{TxBytes: hex.EncodeToString(req.TxBytes)}
+	if err := t.ThetaRPCService.BroadcastRawTransaction(args, result); err != nil {
+		return nil, err
+	}
+
+	ret := &grpcpb.BroadcastRawTransactionResponse{
+		Hash: common.HexToHash(result.TxHash).Bytes(),
+	}
+	if result.Block != nil {
+		if block, err := t.chain.FindBlock(result.Block.Hash()); err == nil {
+			ret.Block = blockToProto(block)
+		}
+	}
+	return ret, nil
+}
+
+// ------------------------------- BroadcastRawTransactionAsync -----------------------------------
+
+func (t *ThetaGRPCService) BroadcastRawTransactionAsync(ctx context.Context, req *grpcpb.BroadcastRawTransactionRequest) (*grpcpb.BroadcastRawTransactionResponse, error) {
+	result := &BroadcastRawTransactionAsyncResult{}
+	args := &BroadcastRawTransactionAsyncArgs{TxBytes: hex.EncodeToString(req.TxBytes)}
+	if err := t.ThetaRPCService.BroadcastRawTransactionAsync(args, result); err != nil {
+		return nil, err
+	}
+	return &grpcpb.BroadcastRawTransactionResponse{
+		Hash: common.HexToHash(result.TxHash).Bytes(),
+	}, nil
+}
+
+// ------------------------------- SubscribeNewBlocks -----------------------------------
+
+// SubscribeNewBlocks streams the blocks height by height. All the blocks of a height, including the
+// ones on the forks, are sent once the height is reached by the chain.
+func (t *ThetaGRPCService) SubscribeNewBlocks(req *grpcpb.SubscribeRequest, stream grpcpb.Theta_SubscribeNewBlocksServer) error {
+	next := req.StartHeight
+	if next == 0 {
+		next = t.consensus.GetLastFinalizedBlock().Height + 1
+	}
+
+	ticker := time.NewTicker(grpcStreamPollInterval)
+	defer ticker.Stop()
+	sub := t.chain.SubscribeEvents(grpcStreamEventBuffer)
+	defer func() { sub.Unsubscribe() }()
+	for {
+		for {
+			blocks := t.chain.FindBlocksByHeight(next)
+			if len(blocks) == 0 {
+				break
+			}
+			for _, block := range blocks {
+				if err := stream.Send(blockToProto(block)); err != nil {
+					return err
+				}
+			}
+			next++
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-t.ctx.Done():
+			return status.Error(codes.Unavailable, "Server is shutting down")
+		case _, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind, the heights are re-checked anyway
+				sub = t.chain.SubscribeEvents(grpcStreamEventBuffer)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// ------------------------------- SubscribeFinalizedBlocks -----------------------------------
+
+// SubscribeFinalizedBlocks streams the finalized blocks in the order of the heights, up to the
+// last finalized block.
+func (t *ThetaGRPCService) SubscribeFinalizedBlocks(req *grpcpb.SubscribeRequest, stream grpcpb.Theta_SubscribeFinalizedBlocksServer) error {
+	next := req.StartHeight
+	if next == 0 {
+		next = t.consensus.GetLastFinalizedBlock().Height + 1
+	}
+
+	ticker := time.NewTicker(grpcStreamPollInterval)
+	defer ticker.Stop()
+	sub := t.chain.SubscribeEvents(grpcStreamEventBuffer)
+	defer func() { sub.Unsubscribe() }()
+	for {
+		lastFinalizedHeight := t.consensus.GetLastFinalizedBlock().Height
+		for ; next <= lastFinalizedHeight; next++ {
+			block := t.findFinalizedBlockByHeight(next)
+			if block == nil { // might have been pruned
+				continue
+			}
+			event := &grpcpb.FinalityEvent{
+				Block:             blockToProto(block),
+				DirectlyFinalized: block.Status.IsDirectlyFinalized(),
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-t.ctx.Done():
+			return status.Error(codes.Unavailable, "Server is shutting down")
+		case _, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind, the heights are re-checked anyway
+				sub = t.chain.SubscribeEvents(grpcStreamEventBuffer)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// ------------------------------- Utils -----------------------------------
+
+func (t *ThetaGRPCService) findFinalizedBlockByHeight(height uint64) *core.ExtendedBlock {
+	for _, block := range t.chain.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return block
+		}
+	}
+	return nil
+}
+
+func blockToProto(block *core.ExtendedBlock) *grpcpb.Block {
+	ret := &grpcpb.Block{
+		Hash:             block.Hash().Bytes(),
+		ChainId:          block.ChainID,
+		Epoch:            block.Epoch,
+		Height:           block.Height,
+		Parent:           block.Parent.Bytes(),
+		TransactionsHash: block.TxHash.Bytes(),
+		StateHash:        block.StateHash.Bytes(),
+		Timestamp:        bigToString(block.Timestamp),
+		Proposer:         block.Proposer.Bytes(),
+		Status:           uint32(block.Status),
+		Hcc: &grpcpb.CommitCertificate{
+			BlockHash: block.HCC.BlockHash.Bytes(),
+		},
+	}
+	if block.HCC.Votes != nil {
+		for _, vote := range block.HCC.Votes.Votes() {
+			ret.Hcc.Votes = append(ret.Hcc.Votes, voteToProto(vote))
+		}
+	}
+	if block.ValidatorVotes != nil {
+		ret.ValidatorVotes = validatorVotesToProto(block.ValidatorVotes)
+	}
+	for _, child := range block.Children {
+		ret.Children = append(ret.Children, child.Bytes())
+	}
+	for _, raw := range block.Txs {
+		ret.Transactions = append(ret.Transactions, txToProto(raw))
+	}
+	return ret
+}
+
+func voteToProto(vote core.Vote) *grpcpb.Vote {
+	ret := &grpcpb.Vote{
+		BlockHash: vote.Block.Bytes(),
+		Height:    vote.Height,
+		Epoch:     vote.Epoch,
+		Id:        vote.ID.Bytes(),
+	}
+	if vote.Signature != nil {
+		ret.Signature = vote.Signature.ToBytes()
+	}
+	return ret
+}
+
+func validatorVotesToProto(vvotes *core.AggregatedValidatorVotes) *grpcpb.AggregatedValidatorVotes {
+	ret := &grpcpb.AggregatedValidatorVotes{
+		BlockHash:  vvotes.Block.Bytes(),
+		Multiplies: vvotes.Multiplies,
+	}
+	if vvotes.Signature != nil {
+		ret.Signature = vvotes.Signature.ToBytes()
+	}
+	return ret
+}
+
+func txToProto(raw common.Bytes) *grpcpb.Transaction {
+	ret := &grpcpb.Transaction{
+		Hash: crypto.Keccak256Hash(raw).Bytes(),
+		Raw:  raw,
+	}
+	tx, err := types.TxFromBytes(raw)
+	if err != nil {
+		logger.Warnf("Failed to decode transaction %v: %v", hex.EncodeToString(raw), err)
+		return ret
+	}
+	ret.Type = uint32(getTxType(tx))
+	if txJSON, err := json.Marshal(tx); err == nil {
+		ret.Json = string(txJSON)
+	}
+	return ret
+}
+
+func bigToString(value *big.Int) string {
+	if value == nil {
+		return "0"
+	}
+	return value.String()
+}
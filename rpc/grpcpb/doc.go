@@ -0,0 +1,8 @@
+// Package grpcpb contains the protobuf messages and the gRPC service of the Theta node, as
+// defined in theta.proto. The Go code is generated with protoc and protoc-gen-go v1.3.1, the
+// version of github.com/golang/protobuf in go.mod, which emits the messages and the gRPC service
+// into theta.pb.go.
+package grpcpb
+
+//go:generate rm -f theta_grpc.go
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. theta.proto
@@ -0,0 +1,210 @@
+package grpcpb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// This is a compile-time assertion to ensure that this file is compatible with the proto package
+// it is being compiled against.
+const _ = proto.ProtoPackageIsVersion3
+
+type GetStatusRequest struct{}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+
+type Status struct {
+	Address                    []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	ChainId                    string `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	PeerId                     string `protobuf:"bytes,3,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	LatestFinalizedBlockHash   []byte `protobuf:"bytes,4,opt,name=latest_finalized_block_hash,json=latestFinalizedBlockHash,proto3" json:"latest_finalized_block_hash,omitempty"`
+	LatestFinalizedBlockHeight uint64 `protobuf:"varint,5,opt,name=latest_finalized_block_height,json=latestFinalizedBlockHeight,proto3" json:"latest_finalized_block_height,omitempty"`
+	LatestFinalizedBlockTime   string `protobuf:"bytes,6,opt,name=latest_finalized_block_time,json=latestFinalizedBlockTime,proto3" json:"latest_finalized_block_time,omitempty"`
+	LatestFinalizedBlockEpoch  uint64 `protobuf:"varint,7,opt,name=latest_finalized_block_epoch,json=latestFinalizedBlockEpoch,proto3" json:"latest_finalized_block_epoch,omitempty"`
+	CurrentEpoch               uint64 `protobuf:"varint,8,opt,name=current_epoch,json=currentEpoch,proto3" json:"current_epoch,omitempty"`
+	CurrentHeight              uint64 `protobuf:"varint,9,opt,name=current_height,json=currentHeight,proto3" json:"current_height,omitempty"`
+	CurrentTime                string `protobuf:"bytes,10,opt,name=current_time,json=currentTime,proto3" json:"current_time,omitempty"`
+	Syncing                    bool   `protobuf:"varint,11,opt,name=syncing,proto3" json:"syncing,omitempty"`
+	GenesisBlockHash           []byte `protobuf:"bytes,12,opt,name=genesis_block_hash,json=genesisBlockHash,proto3" json:"genesis_block_hash,omitempty"`
+}
+
+func (m *Status) Reset()         { *m = Status{} }
+func (m *Status) String() string { return proto.CompactTextString(m) }
+func (*Status) ProtoMessage()    {}
+
+type GetAccountRequest struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Height  uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Preview bool   `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+}
+
+func (m *GetAccountRequest) Reset()         { *m = GetAccountRequest{} }
+func (m *GetAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAccountRequest) ProtoMessage()    {}
+
+type Account struct {
+	Address                []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Sequence               uint64 `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	ThetaWei               string `protobuf:"bytes,3,opt,name=theta_wei,json=thetaWei,proto3" json:"theta_wei,omitempty"`
+	TfuelWei               string `protobuf:"bytes,4,opt,name=tfuel_wei,json=tfuelWei,proto3" json:"tfuel_wei,omitempty"`
+	Root                   []byte `protobuf:"bytes,5,opt,name=root,proto3" json:"root,omitempty"`
+	CodeHash               []byte `protobuf:"bytes,6,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
+	LastUpdatedBlockHeight uint64 `protobuf:"varint,7,opt,name=last_updated_block_height,json=lastUpdatedBlockHeight,proto3" json:"last_updated_block_height,omitempty"`
+}
+
+func (m *Account) Reset()         { *m = Account{} }
+func (m *Account) String() string { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()    {}
+
+type GetBlockRequest struct {
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *GetBlockRequest) Reset()         { *m = GetBlockRequest{} }
+func (m *GetBlockRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockRequest) ProtoMessage()    {}
+
+type GetBlockByHeightRequest struct {
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *GetBlockByHeightRequest) Reset()         { *m = GetBlockByHeightRequest{} }
+func (m *GetBlockByHeightRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockByHeightRequest) ProtoMessage()    {}
+
+type Vote struct {
+	BlockHash []byte `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Height    uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Epoch     uint64 `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Id        []byte `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Vote) Reset()         { *m = Vote{} }
+func (m *Vote) String() string { return proto.CompactTextString(m) }
+func (*Vote) ProtoMessage()    {}
+
+type CommitCertificate struct {
+	BlockHash []byte  `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Votes     []*Vote `protobuf:"bytes,2,rep,name=votes,proto3" json:"votes,omitempty"`
+}
+
+func (m *CommitCertificate) Reset()         { *m = CommitCertificate{} }
+func (m *CommitCertificate) String() string { return proto.CompactTextString(m) }
+func (*CommitCertificate) ProtoMessage()    {}
+
+type AggregatedValidatorVotes struct {
+	BlockHash  []byte   `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	Multiplies []uint32 `protobuf:"varint,2,rep,packed,name=multiplies,proto3" json:"multiplies,omitempty"`
+	Signature  []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *AggregatedValidatorVotes) Reset()         { *m = AggregatedValidatorVotes{} }
+func (m *AggregatedValidatorVotes) String() string { return proto.CompactTextString(m) }
+func (*AggregatedValidatorVotes) ProtoMessage()    {}
+
+type Transaction struct {
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Type uint32 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Raw  []byte `protobuf:"bytes,3,opt,name=raw,proto3" json:"raw,omitempty"`
+	Json string `protobuf:"bytes,4,opt,name=json,proto3" json:"json,omitempty"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+
+type Block struct {
+	Hash             []byte                    `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	ChainId          string                    `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	Epoch            uint64                    `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Height           uint64                    `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Parent           []byte                    `protobuf:"bytes,5,opt,name=parent,proto3" json:"parent,omitempty"`
+	Hcc              *CommitCertificate        `protobuf:"bytes,6,opt,name=hcc,proto3" json:"hcc,omitempty"`
+	TransactionsHash []byte                    `protobuf:"bytes,7,opt,name=transactions_hash,json=transactionsHash,proto3" json:"transactions_hash,omitempty"`
+	StateHash        []byte                    `protobuf:"bytes,8,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"`
+	Timestamp        string                    `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Proposer         []byte                    `protobuf:"bytes,10,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	Status           uint32                    `protobuf:"varint,11,opt,name=status,proto3" json:"status,omitempty"`
+	Children         [][]byte                  `protobuf:"bytes,12,rep,name=children,proto3" json:"children,omitempty"`
+	Transactions     []*Transaction            `protobuf:"bytes,13,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	ValidatorVotes   *AggregatedValidatorVotes `protobuf:"bytes,14,opt,name=validator_votes,json=validatorVotes,proto3" json:"validator_votes,omitempty"`
+}
+
+func (m *Block) Reset()         { *m = Block{} }
+func (m *Block) String() string { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()    {}
+
+type GetTransactionRequest struct {
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()    {}
+
+type TransactionStatus struct {
+	BlockHash   []byte       `protobuf:"bytes,1,opt,name=block_hash,json=blockHash,proto3" json:"block_hash,omitempty"`
+	BlockHeight uint64       `protobuf:"varint,2,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Status      string       `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Transaction *Transaction `protobuf:"bytes,4,opt,name=transaction,proto3" json:"transaction,omitempty"`
+}
+
+func (m *TransactionStatus) Reset()         { *m = TransactionStatus{} }
+func (m *TransactionStatus) String() string { return proto.CompactTextString(m) }
+func (*TransactionStatus) ProtoMessage()    {}
+
+type BroadcastRawTransactionRequest struct {
+	TxBytes []byte `protobuf:"bytes,1,opt,name=tx_bytes,json=txBytes,proto3" json:"tx_bytes,omitempty"`
+}
+
+func (m *BroadcastRawTransactionRequest) Reset()         { *m = BroadcastRawTransactionRequest{} }
+func (m *BroadcastRawTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*BroadcastRawTransactionRequest) ProtoMessage()    {}
+
+type BroadcastRawTransactionResponse struct {
+	Hash  []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Block *Block `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+}
+
+func (m *BroadcastRawTransactionResponse) Reset()         { *m = BroadcastRawTransactionResponse{} }
+func (m *BroadcastRawTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*BroadcastRawTransactionResponse) ProtoMessage()    {}
+
+type SubscribeRequest struct {
+	StartHeight uint64 `protobuf:"varint,1,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+
+type FinalityEvent struct {
+	Block             *Block `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	DirectlyFinalized bool   `protobuf:"varint,2,opt,name=directly_finalized,json=directlyFinalized,proto3" json:"directly_finalized,omitempty"`
+}
+
+func (m *FinalityEvent) Reset()         { *m = FinalityEvent{} }
+func (m *FinalityEvent) String() string { return proto.CompactTextString(m) }
+func (*FinalityEvent) ProtoMessage()    {}
+
+func init() {
+	proto.RegisterType((*GetStatusRequest)(nil), "theta.GetStatusRequest")
+	proto.RegisterType((*Status)(nil), "theta.Status")
+	proto.RegisterType((*GetAccountRequest)(nil), "theta.GetAccountRequest")
+	proto.RegisterType((*Account)(nil), "theta.Account")
+	proto.RegisterType((*GetBlockRequest)(nil), "theta.GetBlockRequest")
+	proto.RegisterType((*GetBlockByHeightRequest)(nil), "theta.GetBlockByHeightRequest")
+	proto.RegisterType((*Vote)(nil), "theta.Vote")
+	proto.RegisterType((*CommitCertificate)(nil), "theta.CommitCertificate")
+	proto.RegisterType((*AggregatedValidatorVotes)(nil), "theta.AggregatedValidatorVotes")
+	proto.RegisterType((*Transaction)(nil), "theta.Transaction")
+	proto.RegisterType((*Block)(nil), "theta.Block")
+	proto.RegisterType((*GetTransactionRequest)(nil), "theta.GetTransactionRequest")
+	proto.RegisterType((*TransactionStatus)(nil), "theta.TransactionStatus")
+	proto.RegisterType((*BroadcastRawTransactionRequest)(nil), "theta.BroadcastRawTransactionRequest")
+	proto.RegisterType((*BroadcastRawTransactionResponse)(nil), "theta.BroadcastRawTransactionResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "theta.SubscribeRequest")
+	proto.RegisterType((*FinalityEvent)(nil), "theta.FinalityEvent")
+}
@@ -0,0 +1,390 @@
+package grpcpb
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+)
+
+// ThetaClient is the client API for the Theta service.
+type ThetaClient interface {
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error)
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*Block, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionStatus, error)
+	BroadcastRawTransaction(ctx context.Context, in *BroadcastRawTransactionRequest, opts ...grpc.CallOption) (*BroadcastRawTransactionResponse, error)
+	BroadcastRawTransactionAsync(ctx context.Context, in *BroadcastRawTransactionRequest, opts ...grpc.CallOption) (*BroadcastRawTransactionResponse, error)
+	SubscribeNewBlocks(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Theta_SubscribeNewBlocksClient, error)
+	SubscribeFinalizedBlocks(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Theta_SubscribeFinalizedBlocksClient, error)
+}
+
+type thetaClient struct {
+	cc *grpc.ClientConn
+}
+
+// NewThetaClient creates a client of the Theta service over the connection
+func NewThetaClient(cc *grpc.ClientConn) ThetaClient {
+	return &thetaClient{cc}
+}
+
+func (c *thetaClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*Status, error) {
+	out := new(Status)
+	err := c.cc.Invoke(ctx, "/theta.Theta/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*Account, error) {
+	out := new(Account)
+	err := c.cc.Invoke(ctx, "/theta.Theta/GetAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/theta.Theta/GetBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) GetBlockByHeight(ctx context.Context, in *GetBlockByHeightRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, "/theta.Theta/GetBlockByHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionStatus, error) {
+	out := new(TransactionStatus)
+	err := c.cc.Invoke(ctx, "/theta.Theta/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) BroadcastRawTransaction(ctx context.Context, in *BroadcastRawTransactionRequest, opts ...grpc.CallOption) (*BroadcastRawTransactionResponse, error) {
+	out := new(BroadcastRawTransactionResponse)
+	err := c.cc.Invoke(ctx, "/theta.Theta/BroadcastRawTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) BroadcastRawTransactionAsync(ctx context.Context, in *BroadcastRawTransactionRequest, opts ...grpc.CallOption) (*BroadcastRawTransactionResponse, error) {
+	out := new(BroadcastRawTransactionResponse)
+	err := c.cc.Invoke(ctx, "/theta.Theta/BroadcastRawTransactionAsync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thetaClient) SubscribeNewBlocks(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Theta_SubscribeNewBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Theta_serviceDesc.Streams[0], "/theta.Theta/SubscribeNewBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &thetaSubscribeNewBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Theta_SubscribeNewBlocksClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type thetaSubscribeNewBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *thetaSubscribeNewBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *thetaClient) SubscribeFinalizedBlocks(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Theta_SubscribeFinalizedBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Theta_serviceDesc.Streams[1], "/theta.Theta/SubscribeFinalizedBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &thetaSubscribeFinalizedBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Theta_SubscribeFinalizedBlocksClient interface {
+	Recv() (*FinalityEvent, error)
+	grpc.ClientStream
+}
+
+type thetaSubscribeFinalizedBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *thetaSubscribeFinalizedBlocksClient) Recv() (*FinalityEvent, error) {
+	m := new(FinalityEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ThetaServer is the server API for the Theta service.
+type ThetaServer interface {
+	GetStatus(context.Context, *GetStatusRequest) (*Status, error)
+	GetAccount(context.Context, *GetAccountRequest) (*Account, error)
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	GetBlockByHeight(context.Context, *GetBlockByHeightRequest) (*Block, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*TransactionStatus, error)
+	BroadcastRawTransaction(context.Context, *BroadcastRawTransactionRequest) (*BroadcastRawTransactionResponse, error)
+	BroadcastRawTransactionAsync(context.Context, *BroadcastRawTransactionRequest) (*BroadcastRawTransactionResponse, error)
+	SubscribeNewBlocks(*SubscribeRequest, Theta_SubscribeNewBlocksServer) error
+	SubscribeFinalizedBlocks(*SubscribeRequest, Theta_SubscribeFinalizedBlocksServer) error
+}
+
+// RegisterThetaServer registers the implementation of the Theta service to the gRPC server
+func RegisterThetaServer(s *grpc.Server, srv ThetaServer) {
+	s.RegisterService(&_Theta_serviceDesc, srv)
+}
+
+func _Theta_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/GetAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_GetBlockByHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).GetBlockByHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/GetBlockByHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).GetBlockByHeight(ctx, req.(*GetBlockByHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_BroadcastRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRawTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).BroadcastRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/BroadcastRawTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).BroadcastRawTransaction(ctx, req.(*BroadcastRawTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_BroadcastRawTransactionAsync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastRawTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThetaServer).BroadcastRawTransactionAsync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/theta.Theta/BroadcastRawTransactionAsync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThetaServer).BroadcastRawTransactionAsync(ctx, req.(*BroadcastRawTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Theta_SubscribeNewBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ThetaServer).SubscribeNewBlocks(m, &thetaSubscribeNewBlocksServer{stream})
+}
+
+type Theta_SubscribeNewBlocksServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type thetaSubscribeNewBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *thetaSubscribeNewBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Theta_SubscribeFinalizedBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ThetaServer).SubscribeFinalizedBlocks(m, &thetaSubscribeFinalizedBlocksServer{stream})
+}
+
+type Theta_SubscribeFinalizedBlocksServer interface {
+	Send(*FinalityEvent) error
+	grpc.ServerStream
+}
+
+type thetaSubscribeFinalizedBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *thetaSubscribeFinalizedBlocksServer) Send(m *FinalityEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Theta_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "theta.Theta",
+	HandlerType: (*ThetaServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _Theta_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _Theta_GetAccount_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Theta_GetBlock_Handler,
+		},
+		{
+			MethodName: "GetBlockByHeight",
+			Handler:    _Theta_GetBlockByHeight_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _Theta_GetTransaction_Handler,
+		},
+		{
+			MethodName: "BroadcastRawTransaction",
+			Handler:    _Theta_BroadcastRawTransaction_Handler,
+		},
+		{
+			MethodName: "BroadcastRawTransactionAsync",
+			Handler:    _Theta_BroadcastRawTransactionAsync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewBlocks",
+			Handler:       _Theta_SubscribeNewBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeFinalizedBlocks",
+			Handler:       _Theta_SubscribeFinalizedBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "theta.proto",
+}
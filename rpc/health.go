@@ -0,0 +1,88 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/thetatoken/theta/common"
+)
+
+// ** Health Checks **
+//
+// /healthz reports whether the node is alive, i.e. it serves HTTP requests and reads the blocks from
+// the database. /readyz additionally reports whether the node is ready to serve the RPC traffic, i.e.
+// it has synced to within CfgRPCReadyMaxBlockLag blocks of its peers, and has at least CfgRPCReadyMinPeers
+// peers. Both return 200 if the check passes, and 503 otherwise, so they can be used as the Kubernetes
+// liveness/readiness probes and the load balancer health checks.
+
+type healthResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+
+	LatestFinalizedBlockHeight common.JSONUint64 `json:"latest_finalized_block_height"`
+	BestPeerHeight             common.JSONUint64 `json:"best_peer_height,omitempty"`
+	NumPeers                   int               `json:"num_peers,omitempty"`
+}
+
+// registerHealthHandlers adds the health check endpoints to the router
+func (t *ThetaRPCService) registerHealthHandlers(router *mux.Router) {
+	router.HandleFunc("/healthz", t.serveHealthz).Methods("GET", "HEAD")
+	router.HandleFunc("/readyz", t.serveReadyz).Methods("GET", "HEAD")
+}
+
+func (t *ThetaRPCService) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	status := &GetStatusResult{}
+	if err := t.GetStatus(&GetStatusArgs{}, status); err != nil {
+		writeHealthResponse(w, false, fmt.Sprintf("Failed to read the latest finalized block: %v", err), &healthResponse{})
+		return
+	}
+	writeHealthResponse(w, true, "", &healthResponse{
+		LatestFinalizedBlockHeight: status.LatestFinalizedBlockHeight,
+	})
+}
+
+func (t *ThetaRPCService) serveReadyz(w http.ResponseWriter, r *http.Request) {
+	status := &GetStatusResult{}
+	if err := t.GetStatus(&GetStatusArgs{}, status); err != nil {
+		writeHealthResponse(w, false, fmt.Sprintf("Failed to read the latest finalized block: %v", err), &healthResponse{})
+		return
+	}
+	numPeers := len(t.dispatcher.Peers(true))
+	ready, reason := checkReadiness(status, numPeers,
+		uint64(common.GetReloadableInt64(common.CfgRPCReadyMaxBlockLag)), common.GetReloadableInt(common.CfgRPCReadyMinPeers))
+	writeHealthResponse(w, ready, reason, &healthResponse{
+		LatestFinalizedBlockHeight: status.LatestFinalizedBlockHeight,
+		BestPeerHeight:             status.BestPeerHeight,
+		NumPeers:                   numPeers,
+	})
+}
+
+// checkReadiness returns whether the node is ready to serve the RPC traffic, and the reason if not
+func checkReadiness(status *GetStatusResult, numPeers int, maxBlockLag uint64, minPeers int) (bool, string) {
+	if status.Syncing {
+		return false, "Node is syncing"
+	}
+	if numPeers < minPeers {
+		return false, fmt.Sprintf("Node has %v peers, at least %v required", numPeers, minPeers)
+	}
+	if lag := uint64(status.BlocksRemaining); lag > maxBlockLag {
+		return false, fmt.Sprintf("Node is %v blocks behind its peers, at most %v allowed", lag, maxBlockLag)
+	}
+	return true, ""
+}
+
+func writeHealthResponse(w http.ResponseWriter, ok bool, reason string, resp *healthResponse) {
+	resp.Status = "ok"
+	resp.Reason = reason
+	statusCode := http.StatusOK
+	if !ok {
+		resp.Status = "unavailable"
+		statusCode = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReadiness(t *testing.T) {
+	assert := assert.New(t)
+
+	status := &GetStatusResult{
+		LatestFinalizedBlockHeight: 100,
+		BestPeerHeight:             105,
+		BlocksRemaining:            5,
+	}
+	ready, reason := checkReadiness(status, 3, 10, 1)
+	assert.True(ready)
+	assert.Equal("", reason)
+
+	// Lagging behind the peers
+	status.BestPeerHeight = 120
+	status.BlocksRemaining = 20
+	ready, reason = checkReadiness(status, 3, 10, 1)
+	assert.False(ready)
+	assert.Contains(reason, "20 blocks behind")
+
+	// Not enough peers
+	status.BestPeerHeight = 100
+	status.BlocksRemaining = 0
+	ready, _ = checkReadiness(status, 0, 10, 1)
+	assert.False(ready)
+	ready, _ = checkReadiness(status, 0, 10, 0)
+	assert.True(ready)
+
+	// Still syncing
+	status.Syncing = true
+	ready, reason = checkReadiness(status, 3, 10, 1)
+	assert.False(ready)
+	assert.Equal("Node is syncing", reason)
+}
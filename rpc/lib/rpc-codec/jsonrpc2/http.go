@@ -45,7 +45,8 @@ func (conn *httpServerConn) Close() error {
 }
 
 type httpHandler struct {
-	rpc *rpc.Server
+	rpc  *rpc.Server
+	wrap func(req *http.Request, codec rpc.ServerCodec) rpc.ServerCodec
 }
 
 // HTTPHandler returns handler for HTTP requests which will execute
@@ -58,7 +59,17 @@ func HTTPHandler(srv *rpc.Server) http.Handler {
 	if srv == nil {
 		srv = rpc.DefaultServer
 	}
-	return &httpHandler{srv}
+	return &httpHandler{rpc: srv}
+}
+
+// HTTPHandlerWithCodecWrapper is like HTTPHandler, but the server codec
+// of each request is wrapped by wrap before serving the request, e.g. to
+// instrument the calls.
+func HTTPHandlerWithCodecWrapper(srv *rpc.Server, wrap func(req *http.Request, codec rpc.ServerCodec) rpc.ServerCodec) http.Handler {
+	if srv == nil {
+		srv = rpc.DefaultServer
+	}
+	return &httpHandler{rpc: srv, wrap: wrap}
 }
 
 func (h *httpHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -76,7 +87,11 @@ func (h *httpHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	ctx := context.WithValue(context.Background(), httpRequestContextKey, req)
 	conn := &httpServerConn{req: req.Body, res: w}
-	_ = h.rpc.ServeRequest(NewServerCodecContext(ctx, conn, h.rpc))
+	codec := NewServerCodecContext(ctx, conn, h.rpc)
+	if h.wrap != nil {
+		codec = h.wrap(req, codec)
+	}
+	_ = h.rpc.ServeRequest(codec)
 	if !conn.replied {
 		w.WriteHeader(http.StatusNoContent)
 	}
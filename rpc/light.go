@@ -0,0 +1,146 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// MaxLightBlockHeadersPerRequest is the max number of headers returned by GetBlockHeaders
+const MaxLightBlockHeadersPerRequest = 100
+
+// ------------------------------ GetBlockHeaders -----------------------------------
+
+type GetBlockHeadersArgs struct {
+	Start common.JSONUint64 `json:"start"`
+	End   common.JSONUint64 `json:"end"`
+}
+
+type LightBlockHeader struct {
+	Header string   `json:"header"` // RLP encoded block header in hex
+	Txs    []string `json:"txs"`    // raw transactions in hex, from which the light clients derive the validator updates
+}
+
+type GetBlockHeadersResult struct {
+	Headers []LightBlockHeader `json:"headers"`
+}
+
+// GetBlockHeaders returns the headers of the finalized blocks within [start, end]. It serves
+// the light clients, which verify the headers and their commit certificates by themselves.
+func (t *ThetaRPCService) GetBlockHeaders(args *GetBlockHeadersArgs, result *GetBlockHeadersResult) (err error) {
+	start := uint64(args.Start)
+	end := uint64(args.End)
+	if end < start {
+		return errors.New("End height must be no less than the start height")
+	}
+	if end-start+1 > MaxLightBlockHeadersPerRequest {
+		end = start + MaxLightBlockHeadersPerRequest - 1
+	}
+
+	result.Headers = []LightBlockHeader{}
+	for height := start; height <= end; height++ {
+		block := t.findFinalizedBlock(height)
+		if block == nil {
+			break
+		}
+		raw, err := rlp.EncodeToBytes(block.BlockHeader)
+		if err != nil {
+			return err
+		}
+		txs := []string{}
+		for _, rawTx := range block.Txs {
+			txs = append(txs, hex.EncodeToString(rawTx))
+		}
+		result.Headers = append(result.Headers, LightBlockHeader{
+			Header: hex.EncodeToString(raw),
+			Txs:    txs,
+		})
+	}
+
+	return nil
+}
+
+// ------------------------------ GetStateProof -----------------------------------
+
+// MaxStateProofExtraKeys is the max number of extra keys proven by GetStateProof, enough to cover the
+// signing keys of the validators
+const MaxStateProofExtraKeys = 64
+
+type GetStateProofArgs struct {
+	Key       string            `json:"key"` // state key in hex
+	Height    common.JSONUint64 `json:"height"`
+	ExtraKeys []string          `json:"extra_keys"` // optional, state keys in hex proven in the same proof
+}
+
+type GetStateProofResult struct {
+	BlockHash common.Hash `json:"block_hash"`
+	StateHash common.Hash `json:"state_hash"`
+	Proof     string      `json:"proof"` // RLP encoded Merkle proof in hex
+}
+
+// GetStateProof returns the Merkle proof of the given state key against the state root
+// of the finalized block at the given height.
+func (t *ThetaRPCService) GetStateProof(args *GetStateProofArgs, result *GetStateProofResult) (err error) {
+	key, err := hex.DecodeString(args.Key)
+	if err != nil || len(key) == 0 {
+		return errors.New("Invalid state key")
+	}
+	if len(args.ExtraKeys) > MaxStateProofExtraKeys {
+		return fmt.Errorf("At most %v extra keys can be proven", MaxStateProofExtraKeys)
+	}
+	keys := []common.Bytes{key}
+	for _, extraKeyStr := range args.ExtraKeys {
+		extraKey, err := hex.DecodeString(extraKeyStr)
+		if err != nil || len(extraKey) == 0 {
+			return errors.New("Invalid extra state key")
+		}
+		keys = append(keys, extraKey)
+	}
+	height := uint64(args.Height)
+
+	block := t.findFinalizedBlock(height)
+	if block == nil {
+		return fmt.Errorf("Finalized block for height %v is not found", height)
+	}
+
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+	sv := state.NewStoreView(height, block.StateHash, deliveredView.GetDB())
+	if sv == nil { // might have been pruned
+		return fmt.Errorf("the state for height %v is not available, it might have been pruned", height)
+	}
+
+	proof := &core.VCPProof{}
+	for _, k := range keys {
+		err = sv.Prove(k, proof)
+		if err != nil {
+			return err
+		}
+	}
+	raw, err := rlp.EncodeToBytes(proof)
+	if err != nil {
+		return err
+	}
+
+	result.BlockHash = block.Hash()
+	result.StateHash = block.StateHash
+	result.Proof = hex.EncodeToString(raw)
+
+	return nil
+}
+
+func (t *ThetaRPCService) findFinalizedBlock(height uint64) *core.ExtendedBlock {
+	for _, b := range t.chain.FindBlocksByHeight(height) {
+		if b.Status.IsFinalized() {
+			return b
+		}
+	}
+	return nil
+}
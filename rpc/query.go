@@ -1,12 +1,15 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"math/rand"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,11 +19,14 @@ import (
 	"github.com/thetatoken/theta/crypto/bls"
 
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger"
 	"github.com/thetatoken/theta/ledger/state"
 	"github.com/thetatoken/theta/ledger/types"
 	"github.com/thetatoken/theta/mempool"
+	p2ptypes "github.com/thetatoken/theta/p2p/types"
 	"github.com/thetatoken/theta/version"
 )
 
@@ -29,19 +35,64 @@ import (
 type GetVersionArgs struct {
 }
 
+// APIVersion is the version of the RPC API. It is bumped on the incompatible changes of the existing
+// methods, while the new methods are discovered from the method list of GetVersion.
+const APIVersion = 2
+
 type GetVersionResult struct {
-	Version   string `json:"version"`
-	GitHash   string `json:"git_hash"`
-	Timestamp string `json:"timestamp"`
+	Version         string          `json:"version"`
+	GitHash         string          `json:"git_hash"`
+	Timestamp       string          `json:"timestamp"`
+	APIVersion      uint            `json:"api_version"`
+	ProtocolVersion string          `json:"protocol_version"`
+	Methods         []string        `json:"methods"`
+	Features        map[string]bool `json:"features"`
 }
 
+// GetVersion returns the version of the node, along with the RPC methods it supports and the
+// features enabled, so that the clients can adapt to the node capabilities
 func (t *ThetaRPCService) GetVersion(args *GetVersionArgs, result *GetVersionResult) (err error) {
 	result.Version = version.Version
 	result.GitHash = version.GitHash
 	result.Timestamp = version.Timestamp
+	result.APIVersion = APIVersion
+	result.ProtocolVersion = viper.GetString(common.CfgP2PVersion)
+	result.Methods = rpcMethods()
+	result.Features = map[string]bool{
+		"ws":         true,
+		"eth_compat": true, // BroadcastRawEthTransaction
+		"archive":    !viper.GetBool(common.CfgStorageStatePruningEnabled),
+		"admin":      viper.GetBool(common.CfgRPCAdminEnabled),
+		"rest":       viper.GetBool(common.CfgRPCRESTEnabled),
+		"grpc":       viper.GetBool(common.CfgGRPCEnabled),
+		"watch":      t.watched != nil,
+		"webhook":    viper.GetBool(common.CfgWebhookEnabled),
+		"telemetry":  viper.GetBool(common.CfgTelemetryEnabled),
+	}
 	return nil
 }
 
+// rpcMethods returns the names of the methods served under the theta namespace, i.e. the exported
+// methods of ThetaRPCService in the form net/rpc registers
+func rpcMethods() []string {
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	serviceType := reflect.TypeOf(&ThetaRPCService{})
+	methods := []string{}
+	for i := 0; i < serviceType.NumMethod(); i++ {
+		method := serviceType.Method(i)
+		mtype := method.Type
+		if method.PkgPath != "" || mtype.NumIn() != 3 || mtype.NumOut() != 1 {
+			continue
+		}
+		if mtype.In(2).Kind() != reflect.Ptr || mtype.Out(0) != errorType {
+			continue
+		}
+		methods = append(methods, "theta."+method.Name)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
 // ------------------------------- GetAccount -----------------------------------
 
 type GetAccountArgs struct {
@@ -60,7 +111,10 @@ func (t *ThetaRPCService) GetAccount(args *GetAccountArgs, result *GetAccountRes
 	if args.Address == "" {
 		return errors.New("Address must be specified")
 	}
-	address := common.HexToAddress(args.Address)
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
 	result.Address = args.Address
 	height := uint64(args.Height)
 
@@ -116,6 +170,83 @@ func (t *ThetaRPCService) GetAccount(args *GetAccountArgs, result *GetAccountRes
 	return nil
 }
 
+// ------------------------------- GetReserveFunds -----------------------------------
+
+type GetReserveFundsArgs struct {
+	Address string `json:"address"`
+	Preview bool   `json:"preview"` // preview the reserved funds from the ScreenedView
+}
+
+type GetReserveFundsResult struct {
+	Address       string               `json:"address"`
+	BlockHeight   common.JSONUint64    `json:"block_height"`
+	ReservedFunds []ReservedFundResult `json:"reserved_funds"`
+}
+
+type ReservedFundResult struct {
+	ReserveSequence    common.JSONUint64 `json:"reserve_sequence"`
+	ResourceIDs        []string          `json:"resource_ids"`
+	Collateral         types.Coins       `json:"collateral"`
+	InitialFund        types.Coins       `json:"initial_fund"`
+	UsedFund           types.Coins       `json:"used_fund"`
+	RemainingFund      types.Coins       `json:"remaining_fund"`
+	EndBlockHeight     common.JSONUint64 `json:"end_block_height"`     // service payments are accepted up to this height
+	ReleaseBlockHeight common.JSONUint64 `json:"release_block_height"` // the fund and the collateral can be released from this height
+	Expired            bool              `json:"expired"`
+	NumTransferRecords common.JSONUint64 `json:"num_transfer_records"`
+}
+
+// GetReserveFunds returns the funds reserved by the account for the off-chain micropayments, which are not released yet.
+func (t *ThetaRPCService) GetReserveFunds(args *GetReserveFundsArgs, result *GetReserveFundsResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
+
+	var ledgerState *state.StoreView
+	if args.Preview {
+		ledgerState, err = t.ledger.GetScreenedSnapshot()
+	} else {
+		ledgerState, err = t.ledger.GetFinalizedSnapshot()
+	}
+	if err != nil {
+		return err
+	}
+
+	account := ledgerState.GetAccount(address)
+	if account == nil {
+		return fmt.Errorf("Account with address %s is not found", address.Hex())
+	}
+
+	height := ledgerState.Height()
+	result.Address = args.Address
+	result.BlockHeight = common.JSONUint64(height)
+	result.ReservedFunds = []ReservedFundResult{}
+	for _, reservedFund := range account.ReservedFunds {
+		remainingFund := reservedFund.InitialFund.Minus(reservedFund.UsedFund)
+		if !remainingFund.IsNonnegative() {
+			remainingFund = types.NewCoins(0, 0)
+		}
+		result.ReservedFunds = append(result.ReservedFunds, ReservedFundResult{
+			ReserveSequence:    common.JSONUint64(reservedFund.ReserveSequence),
+			ResourceIDs:        reservedFund.ResourceIDs,
+			Collateral:         reservedFund.Collateral,
+			InitialFund:        reservedFund.InitialFund,
+			UsedFund:           reservedFund.UsedFund,
+			RemainingFund:      remainingFund,
+			EndBlockHeight:     common.JSONUint64(reservedFund.EndBlockHeight),
+			ReleaseBlockHeight: common.JSONUint64(reservedFund.EndBlockHeight + types.ReservedFundFreezePeriodDuration),
+			Expired:            reservedFund.EndBlockHeight < height,
+			NumTransferRecords: common.JSONUint64(len(reservedFund.TransferRecords)),
+		})
+	}
+
+	return nil
+}
+
 // ------------------------------- GetSplitRule -----------------------------------
 
 type GetSplitRuleArgs struct {
@@ -139,6 +270,122 @@ func (t *ThetaRPCService) GetSplitRule(args *GetSplitRuleArgs, result *GetSplitR
 	return nil
 }
 
+// ------------------------------- GetSplitRulesByInitiator -----------------------------------
+
+type GetSplitRulesByInitiatorArgs struct {
+	Address string `json:"address"`
+}
+
+type GetSplitRulesByInitiatorResult struct {
+	BlockHeight      common.JSONUint64       `json:"block_height"`
+	SplitRules       []SplitRuleResult       `json:"split_rules"`
+	LapsedSplitRules []LapsedSplitRuleResult `json:"lapsed_split_rules"`
+}
+
+type SplitRuleResult struct {
+	SplitRule *types.SplitRule `json:"split_rule"`
+	Expired   bool             `json:"expired"` // expired split rules no longer apply, and are deleted by the next split rule tx
+}
+
+type LapsedSplitRuleResult struct {
+	ResourceID     string            `json:"resource_id"`
+	EndBlockHeight common.JSONUint64 `json:"end_block_height"`
+	LapseHeight    common.JSONUint64 `json:"lapse_height"` // height of the block which deleted the split rule
+}
+
+// GetSplitRulesByInitiator returns the split rules initiated by the given address, and the ones
+// which lapsed, i.e. deleted after their end block heights
+func (t *ThetaRPCService) GetSplitRulesByInitiator(args *GetSplitRulesByInitiatorArgs, result *GetSplitRulesByInitiatorResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	initiator, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
+	ledgerState, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+
+	height := ledgerState.Height()
+	result.BlockHeight = common.JSONUint64(height)
+	result.SplitRules = []SplitRuleResult{}
+	for _, splitRule := range ledgerState.GetSplitRulesByInitiator(initiator) {
+		result.SplitRules = append(result.SplitRules, SplitRuleResult{
+			SplitRule: splitRule,
+			Expired:   splitRule.EndBlockHeight < height,
+		})
+	}
+	result.LapsedSplitRules = []LapsedSplitRuleResult{}
+	for _, event := range t.chain.FindSplitRuleLapseEventsByInitiator(initiator) {
+		result.LapsedSplitRules = append(result.LapsedSplitRules, LapsedSplitRuleResult{
+			ResourceID:     event.ResourceID,
+			EndBlockHeight: common.JSONUint64(event.EndBlockHeight),
+			LapseHeight:    common.JSONUint64(event.Height),
+		})
+	}
+	return nil
+}
+
+// ------------------------------- GetVestingStatus -----------------------------------
+
+type GetVestingStatusArgs struct {
+	Address string `json:"address"`
+}
+
+type GetVestingStatusResult struct {
+	Address          string                  `json:"address"`
+	BlockHeight      common.JSONUint64       `json:"block_height"`
+	Balance          types.Coins             `json:"balance"`
+	LockedCoins      types.Coins             `json:"locked_coins"`
+	SpendableCoins   types.Coins             `json:"spendable_coins"`
+	VestingSchedules []VestingScheduleResult `json:"vesting_schedules"`
+}
+
+type VestingScheduleResult struct {
+	Schedule    types.VestingSchedule `json:"schedule"`
+	LockedCoins types.Coins           `json:"locked_coins"`
+}
+
+// GetVestingStatus returns the vesting schedules of the given address, and the coins still locked by them
+func (t *ThetaRPCService) GetVestingStatus(args *GetVestingStatusArgs, result *GetVestingStatusResult) (err error) {
+	if args.Address == "" {
+		return errors.New("Address must be specified")
+	}
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
+	ledgerState, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+
+	height := ledgerState.Height()
+	balance := types.NewCoins(0, 0)
+	if account := ledgerState.GetAccount(address); account != nil {
+		balance = account.Balance.NoNil()
+	}
+
+	schedules := ledgerState.GetVestingSchedules(address)
+	locked := types.TotalLockedCoins(schedules, height)
+
+	result.Address = args.Address
+	result.BlockHeight = common.JSONUint64(height)
+	result.Balance = balance
+	result.LockedCoins = locked
+	result.SpendableCoins = types.SpendableCoins(balance, locked)
+	result.VestingSchedules = []VestingScheduleResult{}
+	for _, vs := range schedules {
+		result.VestingSchedules = append(result.VestingSchedules, VestingScheduleResult{
+			Schedule:    vs,
+			LockedCoins: vs.LockedCoins(height),
+		})
+	}
+	return nil
+}
+
 // ------------------------------ GetTransaction -----------------------------------
 
 type GetTransactionArgs struct {
@@ -148,6 +395,8 @@ type GetTransactionArgs struct {
 type GetTransactionResult struct {
 	BlockHash   common.Hash                `json:"block_hash"`
 	BlockHeight common.JSONUint64          `json:"block_height"`
+	Index       common.JSONUint64          `json:"index"`     // position of the transaction in the block
+	Finalized   bool                       `json:"finalized"` // whether the containing block is finalized
 	Status      TxStatus                   `json:"status"`
 	TxHash      common.Hash                `json:"hash"`
 	Type        byte                       `json:"type"`
@@ -170,24 +419,29 @@ func (t *ThetaRPCService) GetTransaction(args *GetTransactionArgs, result *GetTr
 	}
 	hash := common.HexToHash(args.Hash)
 
-	raw, block, found := t.chain.FindTxByHash(hash)
+	// Looked up through the tx index, without decoding the containing block
+	lookup, found := t.chain.LookupTx(hash)
 	if !found {
 		txStatus, exists := t.mempool.GetTransactionStatus(args.Hash)
 		if exists {
-			if txStatus == mempool.TxStatusAbandoned {
-				result.Status = TxStatusAbandoned
-			} else {
+			switch txStatus {
+			case mempool.TxStatusPending, mempool.TxStatusIncluded:
 				result.Status = TxStatusPending
+			default: // evicted, replaced or expired
+				result.Status = TxStatusAbandoned
 			}
 		} else {
 			result.Status = TxStatusNotFound
 		}
 		return nil
 	}
-	result.BlockHash = block.Hash()
-	result.BlockHeight = common.JSONUint64(block.Height)
+	raw := lookup.RawTx
+	result.BlockHash = lookup.BlockHash
+	result.BlockHeight = common.JSONUint64(lookup.BlockHeight)
+	result.Index = common.JSONUint64(lookup.Index)
+	result.Finalized = lookup.BlockStatus.IsFinalized()
 
-	if block.Status.IsFinalized() {
+	if result.Finalized {
 		result.Status = TxStatusFinalized
 	} else {
 		result.Status = TxStatusPending
@@ -216,21 +470,262 @@ func (t *ThetaRPCService) GetTransaction(args *GetTransactionArgs, result *GetTr
 	return nil
 }
 
+// ------------------------------ GetTransactionReceipt -----------------------------------
+
+type GetTransactionReceiptArgs struct {
+	Hash string `json:"hash"`
+}
+
+type GetTransactionReceiptResult struct {
+	TxHash          common.Hash       `json:"hash"`
+	BlockHash       common.Hash       `json:"block_hash"`
+	BlockHeight     common.JSONUint64 `json:"block_height"`
+	TxIndex         common.JSONUint64 `json:"transaction_index"`
+	Finalized       bool              `json:"finalized"`
+	Status          ReceiptStatus     `json:"status"`
+	GasUsed         common.JSONUint64 `json:"gas_used"`
+	ContractAddress *common.Address   `json:"contract_address"` // the created contract, nil if no contract was created
+	EvmReturn       string            `json:"evm_return"`
+	EvmError        string            `json:"evm_error"`
+	Logs            []*types.Log      `json:"logs"`
+	Bloom           types.Bloom       `json:"logs_bloom"`
+}
+
+type ReceiptStatus string
+
+const (
+	ReceiptStatusSuccess = "success"
+	ReceiptStatusFailed  = "failed"
+)
+
+// GetTransactionReceipt returns the execution result of the smart contract transaction with the
+// given hash, which can be either the Theta or the ETH tx hash.
+func (t *ThetaRPCService) GetTransactionReceipt(args *GetTransactionReceiptArgs, result *GetTransactionReceiptResult) (err error) {
+	if args.Hash == "" {
+		return errors.New("Transanction hash must be specified")
+	}
+	hash := common.HexToHash(args.Hash)
+
+	raw, block, found := t.chain.FindTxByHash(hash)
+	if !found {
+		return fmt.Errorf("Transaction %v not found", args.Hash)
+	}
+
+	canonicalTxHash := crypto.Keccak256Hash(raw)
+	receipt, found := t.chain.FindTxReceiptByHash(canonicalTxHash)
+	if !found {
+		return fmt.Errorf("Receipt of transaction %v not found", args.Hash)
+	}
+
+	result.TxHash = canonicalTxHash
+	result.BlockHash = block.Hash()
+	result.BlockHeight = common.JSONUint64(block.Height)
+	for idx, rawTx := range block.Txs {
+		if crypto.Keccak256Hash(rawTx) == canonicalTxHash {
+			result.TxIndex = common.JSONUint64(idx)
+			break
+		}
+	}
+	result.Finalized = block.Status.IsFinalized()
+	if receipt.Succeeded() {
+		result.Status = ReceiptStatusSuccess
+	} else {
+		result.Status = ReceiptStatusFailed
+	}
+	result.GasUsed = common.JSONUint64(receipt.GasUsed)
+	tx, err := types.TxFromBytes(raw)
+	if err != nil {
+		return err
+	}
+	if sctx, ok := tx.(*types.SmartContractTx); ok && (sctx.To.Address == common.Address{}) {
+		contractAddr := receipt.ContractAddress
+		result.ContractAddress = &contractAddr
+	}
+	result.EvmReturn = hex.EncodeToString(receipt.EvmRet)
+	result.EvmError = receipt.EvmErr
+	result.Logs = receipt.Logs
+	if result.Logs == nil {
+		result.Logs = []*types.Log{}
+	}
+	result.Bloom = receipt.Bloom
+
+	return nil
+}
+
+// ------------------------------ GetInternalTransactions -----------------------------------
+
+type GetInternalTransactionsArgs struct {
+	BlockHash common.Hash `json:"block_hash"`
+	TxHash    common.Hash `json:"tx_hash"`
+}
+
+type GetInternalTransactionsResult struct {
+	InternalTxs []InternalTransactionResult `json:"internal_transactions"`
+}
+
+type InternalTransactionResult struct {
+	TxHash      common.Hash       `json:"hash"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	TxIndex     common.JSONUint64 `json:"transaction_index"`
+	Index       common.JSONUint64 `json:"index"` // index of the internal tx within the transaction
+	Type        string            `json:"type"`
+	From        common.Address    `json:"from"`
+	To          common.Address    `json:"to"`
+	Coins       types.Coins       `json:"coins"`
+	Depth       common.JSONUint64 `json:"depth"`
+}
+
+// GetInternalTransactions returns the value transfers made by the contracts during the execution of
+// the smart contract transactions, either of the given transaction, or of all the transactions in the
+// given block. Transfers of the reverted calls are not included.
+func (t *ThetaRPCService) GetInternalTransactions(args *GetInternalTransactionsArgs, result *GetInternalTransactionsResult) (err error) {
+	if args.BlockHash.IsEmpty() == args.TxHash.IsEmpty() {
+		return errors.New("Exactly one of the block hash and the transaction hash must be specified")
+	}
+
+	var raw common.Bytes
+	var block *core.ExtendedBlock
+	if !args.TxHash.IsEmpty() {
+		var found bool
+		raw, block, found = t.chain.FindTxByHash(args.TxHash) // the hash can be either the Theta or the ETH tx hash
+		if !found {
+			return fmt.Errorf("Transaction %v not found", args.TxHash.Hex())
+		}
+	} else {
+		block, err = t.chain.FindBlock(args.BlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	result.InternalTxs = []InternalTransactionResult{}
+	for txIndex, rawTx := range block.Txs {
+		if raw != nil && !bytes.Equal(rawTx, raw) {
+			continue
+		}
+		txHash := crypto.Keccak256Hash(rawTx)
+		entry, found := t.chain.FindInternalTxsByHash(txHash)
+		if !found {
+			continue
+		}
+		for idx, itx := range entry.InternalTxs {
+			result.InternalTxs = append(result.InternalTxs, InternalTransactionResult{
+				TxHash:      txHash,
+				BlockHash:   block.Hash(),
+				BlockHeight: common.JSONUint64(block.Height),
+				TxIndex:     common.JSONUint64(txIndex),
+				Index:       common.JSONUint64(idx),
+				Type:        itx.Type,
+				From:        itx.From,
+				To:          itx.To,
+				Coins:       itx.Coins,
+				Depth:       common.JSONUint64(itx.Depth),
+			})
+		}
+	}
+
+	return nil
+}
+
 // ------------------------------ GetPendingTransactions -----------------------------------
 
 type GetPendingTransactionsArgs struct {
+	TxHashes []string `json:"tx_hashes"` // optional, the transactions to report the lifecycle state of
 }
 
 type GetPendingTransactionsResult struct {
-	TxHashes []string `json:"tx_hashes"`
+	TxHashes []string          `json:"tx_hashes"`
+	Txs      []PendingTxStatus `json:"txs"`
+}
+
+type TxLifecycleState string
+
+const (
+	TxStateNotFound  = "not_found"
+	TxStatePending   = "pending"
+	TxStateIncluded  = "included"
+	TxStateFinalized = "finalized"
+	TxStateEvicted   = "evicted"
+	TxStateReplaced  = "replaced"
+	TxStateExpired   = "expired"
+)
+
+// PendingTxStatus is the lifecycle state of a transaction. The mempool keeps the states for the
+// mempool.txStatusRetentionSecs after a transaction is first seen, after which the transactions
+// not in the chain are reported as not found.
+type PendingTxStatus struct {
+	TxHash      string            `json:"hash"`
+	State       TxLifecycleState  `json:"state"`
+	BlockHeight common.JSONUint64 `json:"block_height,omitempty"` // for the included and finalized txs
+	ReplacedBy  string            `json:"replaced_by,omitempty"`  // the tx of the same sequence included instead
+	Reason      string            `json:"reason,omitempty"`       // why the tx was evicted
+	FirstSeen   *common.JSONBig   `json:"first_seen,omitempty"`   // unix timestamp
+	UpdatedAt   *common.JSONBig   `json:"updated_at,omitempty"`   // unix timestamp of the last state change
 }
 
 func (t *ThetaRPCService) GetPendingTransactions(args *GetPendingTransactionsArgs, result *GetPendingTransactionsResult) (err error) {
-	pendingTxHashes := t.mempool.GetCandidateTransactionHashes()
-	result.TxHashes = pendingTxHashes
+	if len(args.TxHashes) == 0 {
+		pendingTxHashes := t.mempool.GetCandidateTransactionHashes()
+		result.TxHashes = pendingTxHashes
+		return nil
+	}
+
+	result.TxHashes = []string{}
+	result.Txs = []PendingTxStatus{}
+	for _, txHash := range args.TxHashes {
+		status := t.getTxLifecycleState(txHash)
+		if status.State == TxStatePending {
+			result.TxHashes = append(result.TxHashes, txHash)
+		}
+		result.Txs = append(result.Txs, status)
+	}
 	return nil
 }
 
+// getTxLifecycleState combines the chain, which is authoritative for the included transactions,
+// with the mempool records of the transactions that left the mempool otherwise
+func (t *ThetaRPCService) getTxLifecycleState(txHash string) PendingTxStatus {
+	status := PendingTxStatus{TxHash: txHash, State: TxStateNotFound}
+
+	record, exists := t.mempool.GetTransactionRecord(txHash)
+	if exists {
+		status.FirstSeen = (*common.JSONBig)(big.NewInt(record.CreatedAt.Unix()))
+		status.UpdatedAt = (*common.JSONBig)(big.NewInt(record.UpdatedAt.Unix()))
+	}
+
+	if lookup, found := t.chain.LookupTx(common.HexToHash(txHash)); found {
+		status.BlockHeight = common.JSONUint64(lookup.BlockHeight)
+		if lookup.BlockStatus.IsFinalized() {
+			status.State = TxStateFinalized
+		} else {
+			status.State = TxStateIncluded
+		}
+		return status
+	}
+	if !exists {
+		return status
+	}
+
+	switch record.Status {
+	case mempool.TxStatusPending:
+		status.State = TxStatePending
+	case mempool.TxStatusIncluded:
+		// The block is not in the chain index, e.g. the tx hash is an ETH tx hash
+		status.State = TxStateIncluded
+		status.BlockHeight = common.JSONUint64(record.BlockHeight)
+	case mempool.TxStatusReplaced:
+		status.State = TxStateReplaced
+		status.ReplacedBy = "0x" + record.ReplacedBy
+	case mempool.TxStatusExpired:
+		status.State = TxStateExpired
+	default:
+		status.State = TxStateEvicted
+		status.Reason = record.Reason
+	}
+	return status
+}
+
 // ------------------------------ GetBlock -----------------------------------
 
 type GetBlockArgs struct {
@@ -260,17 +755,20 @@ type GetBlockResult struct {
 type GetBlocksResult []*GetBlockResultInner
 
 type GetBlockResultInner struct {
-	ChainID            string                   `json:"chain_id"`
-	Epoch              common.JSONUint64        `json:"epoch"`
-	Height             common.JSONUint64        `json:"height"`
-	Parent             common.Hash              `json:"parent"`
-	TxHash             common.Hash              `json:"transactions_hash"`
-	StateHash          common.Hash              `json:"state_hash"`
-	Timestamp          *common.JSONBig          `json:"timestamp"`
-	Proposer           common.Address           `json:"proposer"`
-	HCC                core.CommitCertificate   `json:"hcc"`
-	GuardianVotes      *core.AggregatedVotes    `json:"guardian_votes"`
-	EliteEdgeNodeVotes *core.AggregatedEENVotes `json:"elite_edge_node_votes"`
+	ChainID            string                         `json:"chain_id"`
+	Epoch              common.JSONUint64              `json:"epoch"`
+	Height             common.JSONUint64              `json:"height"`
+	Parent             common.Hash                    `json:"parent"`
+	TxHash             common.Hash                    `json:"transactions_hash"`
+	StateHash          common.Hash                    `json:"state_hash"`
+	Timestamp          *common.JSONBig                `json:"timestamp"`
+	Proposer           common.Address                 `json:"proposer"`
+	HCC                core.CommitCertificate         `json:"hcc"`
+	GuardianVotes      *core.AggregatedVotes          `json:"guardian_votes"`
+	EliteEdgeNodeVotes *core.AggregatedEENVotes       `json:"elite_edge_node_votes"`
+	ValidatorVotes     *core.AggregatedValidatorVotes `json:"validator_votes,omitempty"`
+	BurnedFees         *common.JSONBig                `json:"burned_fees,omitempty"` // TFuelWei burned by the tx fees, since fee burn tracking is enabled
+	CoinbaseBreakdown  *CoinbaseBreakdown             `json:"coinbase_breakdown,omitempty"`
 
 	Children []common.Hash    `json:"children"`
 	Status   core.BlockStatus `json:"status"`
@@ -279,6 +777,22 @@ type GetBlockResultInner struct {
 	Txs  []interface{} `json:"transactions"` // for backward conpatibility, see function ThetaRPCService.gatherTxs()
 }
 
+// CoinbaseBreakdown decodes the coinbase transaction of a block
+type CoinbaseBreakdown struct {
+	Proposer   common.Address      `json:"proposer"`
+	Recipients []CoinbaseRecipient `json:"recipients"`
+}
+
+// CoinbaseRecipient is an output of the coinbase transaction, with the roles the recipient is
+// rewarded for, i.e. the validator, guardian or elite edge node stakes, or the split of the
+// reward of another stake. The roles are empty if the states needed to attribute the rewards
+// have been pruned.
+type CoinbaseRecipient struct {
+	Address common.Address `json:"address"`
+	Coins   types.Coins    `json:"coins"`
+	Roles   []string       `json:"roles"`
+}
+
 type TxType byte
 
 const (
@@ -294,6 +808,7 @@ const (
 	TxTypeWithdrawStake
 	TxTypeDepositStakeTxV2
 	TxTypeStakeRewardDistributionTx
+	TxTypeVesting
 )
 
 func (t *ThetaRPCService) GetBlock(args *GetBlockArgs, result *GetBlockResult) (err error) {
@@ -319,6 +834,8 @@ func (t *ThetaRPCService) GetBlock(args *GetBlockArgs, result *GetBlockResult) (
 	result.Status = block.Status
 	result.HCC = block.HCC
 	result.GuardianVotes = block.GuardianVotes
+	result.BurnedFees = (*common.JSONBig)(t.getBlockBurnedFees(block))
+	result.CoinbaseBreakdown = t.getCoinbaseBreakdown(block)
 
 	result.Hash = block.Hash()
 
@@ -385,6 +902,9 @@ func (t *ThetaRPCService) GetBlockByHeight(args *GetBlockByHeightArgs, result *G
 	result.HCC = block.HCC
 	result.GuardianVotes = block.GuardianVotes
 	result.EliteEdgeNodeVotes = block.EliteEdgeNodeVotes
+	result.ValidatorVotes = block.ValidatorVotes
+	result.BurnedFees = (*common.JSONBig)(t.getBlockBurnedFees(block))
+	result.CoinbaseBreakdown = t.getCoinbaseBreakdown(block)
 
 	result.Hash = block.Hash()
 
@@ -465,6 +985,8 @@ func (t *ThetaRPCService) GetBlocksByRange(args *GetBlocksByRangeArgs, result *G
 		blkInner.HCC = block.HCC
 		blkInner.GuardianVotes = block.GuardianVotes
 		blkInner.EliteEdgeNodeVotes = block.EliteEdgeNodeVotes
+		blkInner.ValidatorVotes = block.ValidatorVotes
+		blkInner.BurnedFees = (*common.JSONBig)(t.getBlockBurnedFees(block))
 
 		blkInner.Hash = block.Hash()
 
@@ -501,6 +1023,13 @@ type GetStatusResult struct {
 	CurrentTime                *common.JSONBig   `json:"current_time"`
 	Syncing                    bool              `json:"syncing"`
 	GenesisBlockHash           common.Hash       `json:"genesis_block_hash"`
+	BestPeerHeight             common.JSONUint64 `json:"best_peer_height"`
+	BlocksRemaining            common.JSONUint64 `json:"blocks_remaining"`
+	SyncRate                   float64           `json:"sync_rate"` // blocks per second over the last minute
+	CatchingUp                 bool              `json:"catching_up"`
+	NumPeers                   int               `json:"num_peers"`
+	ClockSkewMs                int64             `json:"clock_skew_ms"`     // positive if the local clock is ahead
+	ClockSkewSource            string            `json:"clock_skew_source"` // peers or ntp, empty if not measured yet
 }
 
 func (t *ThetaRPCService) GetStatus(args *GetStatusArgs, result *GetStatusResult) (err error) {
@@ -548,6 +1077,24 @@ func (t *ThetaRPCService) GetStatus(args *GetStatusArgs, result *GetStatusResult
 	}
 	result.GenesisBlockHash = genesisHash
 
+	result.NumPeers = len(t.dispatcher.Peers(true))
+	clockSkew := t.consensus.ClockSkew()
+	result.ClockSkewMs = int64(clockSkew.Skew / time.Millisecond)
+	result.ClockSkewSource = clockSkew.Source
+	if t.syncMgr != nil {
+		progress := t.syncMgr.GetSyncProgress()
+		bestPeerHeight := progress.BestPeerHeight
+		if uint64(result.CurrentHeight) > bestPeerHeight {
+			bestPeerHeight = uint64(result.CurrentHeight)
+		}
+		result.BestPeerHeight = common.JSONUint64(bestPeerHeight)
+		if bestPeerHeight > uint64(result.LatestFinalizedBlockHeight) {
+			result.BlocksRemaining = common.JSONUint64(bestPeerHeight - uint64(result.LatestFinalizedBlockHeight))
+		}
+		result.SyncRate = progress.BlocksPerSecond
+	}
+	result.CatchingUp = result.Syncing || uint64(result.BlocksRemaining) > uint64(common.GetReloadableInt64(common.CfgRPCReadyMaxBlockLag))
+
 	return
 }
 
@@ -584,12 +1131,14 @@ type GetPeersArgs struct {
 }
 
 type GetPeersResult struct {
-	Peers []string `json:"peers"`
+	Peers     []string            `json:"peers"`
+	PeerInfos []p2ptypes.PeerInfo `json:"peer_infos"`
 }
 
 func (t *ThetaRPCService) GetPeers(args *GetPeersArgs, result *GetPeersResult) (err error) {
 	peers := t.dispatcher.Peers(args.SkipEdgeNode)
 	result.Peers = peers
+	result.PeerInfos = t.dispatcher.PeerInfos(args.SkipEdgeNode)
 
 	return
 }
@@ -605,9 +1154,10 @@ type GetVcpResult struct {
 }
 
 type BlockHashVcpPair struct {
-	BlockHash  common.Hash
-	Vcp        *core.ValidatorCandidatePool
-	HeightList *types.HeightList
+	BlockHash        common.Hash
+	Vcp              *core.ValidatorCandidatePool
+	HeightList       *types.HeightList
+	ValidatorSetHash common.Hash // the hash to use in the trusted checkpoint config
 }
 
 func (t *ThetaRPCService) GetVcpByHeight(args *GetVcpByHeightArgs, result *GetVcpResult) (err error) {
@@ -630,10 +1180,17 @@ func (t *ThetaRPCService) GetVcpByHeight(args *GetVcpByHeightArgs, result *GetVc
 		}
 		vcp := blockStoreView.GetValidatorCandidatePool()
 		hl := blockStoreView.GetStakeTransactionHeightList()
+		valSet, err := consensus.SelectTopStakeHoldersAsValidatorsWithSigners(vcp, height, func(id common.Address) (common.Address, error) {
+			return blockStoreView.GetValidatorSigningAddress(id, height), nil
+		})
+		if err != nil {
+			return err
+		}
 		blockHashVcpPairs = append(blockHashVcpPairs, BlockHashVcpPair{
-			BlockHash:  blockHash,
-			Vcp:        vcp,
-			HeightList: hl,
+			BlockHash:        blockHash,
+			Vcp:              vcp,
+			HeightList:       hl,
+			ValidatorSetHash: valSet.Hash(),
 		})
 	}
 
@@ -642,6 +1199,91 @@ func (t *ThetaRPCService) GetVcpByHeight(args *GetVcpByHeightArgs, result *GetVc
 	return nil
 }
 
+// ------------------------------ GetProposerSchedule -----------------------------------
+
+// MaxProposerScheduleCount is the maximum number of epochs returned by GetProposerSchedule.
+const MaxProposerScheduleCount = consensus.ProposerScheduleRoundLength
+
+type GetProposerScheduleArgs struct {
+	Epoch common.JSONUint64 `json:"epoch"` // first epoch of the schedule, the current epoch if zero
+	Count common.JSONUint64 `json:"count"` // number of epochs, 100 if zero
+}
+
+type GetProposerScheduleResult struct {
+	BlockHash     common.Hash                 `json:"block_hash"` // the tip the schedule is derived from
+	BlockHeight   common.JSONUint64           `json:"block_height"`
+	CurrentEpoch  common.JSONUint64           `json:"current_epoch"`
+	StakeWeighted bool                        `json:"stake_weighted"` // whether the round robin schedule is active
+	RoundLength   common.JSONUint64           `json:"round_length"`
+	Validators    []ProposerScheduleValidator `json:"validators"`
+	Slots         []ProposerScheduleSlot      `json:"slots"`
+}
+
+type ProposerScheduleValidator struct {
+	Address       common.Address    `json:"address"`
+	Stake         *common.JSONBig   `json:"stake"`
+	SlotsPerRound common.JSONUint64 `json:"slots_per_round"`
+}
+
+type ProposerScheduleSlot struct {
+	Epoch    common.JSONUint64 `json:"epoch"`
+	Proposer common.Address    `json:"proposer"`
+}
+
+// GetProposerSchedule returns the expected proposers of the block extending the current tip in the
+// upcoming epochs. The schedule changes if a new block is added or the validator set changes.
+func (t *ThetaRPCService) GetProposerSchedule(args *GetProposerScheduleArgs, result *GetProposerScheduleResult) (err error) {
+	epoch := uint64(args.Epoch)
+	currentEpoch := t.consensus.GetEpoch()
+	if epoch == 0 {
+		epoch = currentEpoch
+	}
+	count := uint64(args.Count)
+	if count == 0 {
+		count = 100
+	}
+	if count > MaxProposerScheduleCount {
+		return fmt.Errorf("count cannot exceed %v", MaxProposerScheduleCount)
+	}
+
+	tip := t.consensus.GetTipToExtend()
+	height := tip.Height + 1
+	valSet := t.consensus.GetValidatorManager().GetNextValidatorSet(tip.Hash())
+	schedule := consensus.NewProposerSchedule(valSet)
+	stakeWeighted := height >= common.HeightEnableProposerSchedule
+
+	result.BlockHash = tip.Hash()
+	result.BlockHeight = common.JSONUint64(tip.Height)
+	result.CurrentEpoch = common.JSONUint64(currentEpoch)
+	result.StakeWeighted = stakeWeighted
+	result.RoundLength = common.JSONUint64(consensus.ProposerScheduleRoundLength)
+
+	result.Validators = []ProposerScheduleValidator{}
+	for _, v := range valSet.Validators() {
+		result.Validators = append(result.Validators, ProposerScheduleValidator{
+			Address:       v.Address,
+			Stake:         (*common.JSONBig)(v.Stake),
+			SlotsPerRound: common.JSONUint64(schedule.NumSlots(v.Address)),
+		})
+	}
+
+	result.Slots = []ProposerScheduleSlot{}
+	for e := epoch; e < epoch+count; e++ {
+		var proposer core.Validator
+		if stakeWeighted {
+			proposer = schedule.Proposer(e)
+		} else {
+			proposer = consensus.SelectProposer(valSet, height, e)
+		}
+		result.Slots = append(result.Slots, ProposerScheduleSlot{
+			Epoch:    common.JSONUint64(e),
+			Proposer: proposer.Address,
+		})
+	}
+
+	return nil
+}
+
 // ------------------------------ GetGcp -----------------------------------
 
 type GetGcpByHeightArgs struct {
@@ -719,10 +1361,107 @@ func (t *ThetaRPCService) GetGuardianInfo(args *GetGuardianInfoArgs, result *Get
 	return nil
 }
 
+// ------------------------------ GetGuardianCertificate -----------------------------------
+
+// The max number of the later checkpoints searched for the guardian votes of a checkpoint
+const maxGuardianCertificateSearchCheckpoints = 10
+
+type GetGuardianCertificateArgs struct {
+	Height common.JSONUint64 `json:"height"` // height of the checkpoint block
+}
+
+type GetGuardianCertificateResult struct {
+	CheckpointHeight common.JSONUint64 `json:"checkpoint_height"`
+	CheckpointHash   common.Hash       `json:"checkpoint_hash"`
+	StateHash        common.Hash       `json:"state_hash"`
+	BlockHeight      common.JSONUint64 `json:"block_height"` // height of the block carrying the guardian votes
+	BlockHash        common.Hash       `json:"block_hash"`
+	GcpHash          common.Hash       `json:"gcp_hash"`
+	Guardians        []GuardianKey     `json:"guardians"` // guardians with stake, in the order of the bitmap
+	Multiplies       []uint32          `json:"multiplies"`
+	Bitmap           string            `json:"bitmap"`
+	NumSigners       int               `json:"num_signers"`
+	SignBytes        string            `json:"sign_bytes"`
+	Signature        string            `json:"signature"` // aggregated BLS signature
+}
+
+type GuardianKey struct {
+	Address   common.Address `json:"address"`
+	BLSPubkey string         `json:"bls_pubkey"`
+}
+
+// GetGuardianCertificate returns the aggregated guardian votes certifying the finalized checkpoint
+// block at the given height, together with the guardian keys, so that external verifiers can
+// check the aggregated signature independently.
+func (t *ThetaRPCService) GetGuardianCertificate(args *GetGuardianCertificateArgs, result *GetGuardianCertificateResult) (err error) {
+	height := uint64(args.Height)
+	if !common.IsCheckPointHeight(height) {
+		return fmt.Errorf("Height %v is not a checkpoint height", height)
+	}
+	checkpoint := t.findFinalizedBlock(height)
+	if checkpoint == nil {
+		return fmt.Errorf("Finalized checkpoint not found for height %v", height)
+	}
+	checkpointHash := checkpoint.Hash()
+
+	// The guardian votes of a checkpoint are carried by a later checkpoint block
+	var block *core.ExtendedBlock
+	lastFinalizedHeight := t.consensus.GetLastFinalizedBlock().Height
+	for i := 1; i <= maxGuardianCertificateSearchCheckpoints; i++ {
+		h := height + uint64(i)*uint64(common.CheckpointInterval)
+		if h > lastFinalizedHeight {
+			break
+		}
+		b := t.findFinalizedBlock(h)
+		if b != nil && b.GuardianVotes != nil && b.GuardianVotes.Block == checkpointHash {
+			block = b
+			break
+		}
+	}
+	if block == nil {
+		return fmt.Errorf("Guardian votes not found for the checkpoint at height %v", height)
+	}
+	votes := block.GuardianVotes
+
+	gcp, err := t.ledger.GetGuardianCandidatePool(checkpointHash)
+	if err != nil {
+		return err
+	}
+	guardians := gcp.WithStake()
+
+	result.CheckpointHeight = common.JSONUint64(checkpoint.Height)
+	result.CheckpointHash = checkpointHash
+	result.StateHash = checkpoint.StateHash
+	result.BlockHeight = common.JSONUint64(block.Height)
+	result.BlockHash = block.Hash()
+	result.GcpHash = votes.Gcp
+	result.Guardians = []GuardianKey{}
+	for _, g := range guardians.SortedGuardians {
+		result.Guardians = append(result.Guardians, GuardianKey{
+			Address:   g.Holder,
+			BLSPubkey: hex.EncodeToString(g.Pubkey.ToBytes()),
+		})
+	}
+	result.Multiplies = votes.Multiplies
+	result.Bitmap = hex.EncodeToString(votes.Bitmap())
+	result.NumSigners = votes.Abs()
+	result.SignBytes = hex.EncodeToString(votes.SignBytes())
+	if votes.Signature != nil {
+		result.Signature = hex.EncodeToString(votes.Signature.ToBytes())
+	}
+
+	return nil
+}
+
 // ------------------------------ GetEenp -----------------------------------
 
 type GetEenpByHeightArgs struct {
-	Height common.JSONUint64 `json:"height"`
+	Height    common.JSONUint64 `json:"height"`
+	Holder    string            `json:"holder"`     // only returns the elite edge node of the holder if set
+	Source    string            `json:"source"`     // only returns the elite edge nodes with stakes from the source if set
+	WithStake bool              `json:"with_stake"` // only returns the elite edge nodes with non-withdrawn stakes
+	Offset    common.JSONUint64 `json:"offset"`
+	Limit     common.JSONUint64 `json:"limit"` // returns all the matching elite edge nodes if zero
 }
 
 type GetEenpResult struct {
@@ -730,11 +1469,29 @@ type GetEenpResult struct {
 }
 
 type BlockHashEenpPair struct {
-	BlockHash common.Hash
-	EENs      []*core.EliteEdgeNode
+	BlockHash  common.Hash
+	EENs       []*core.EliteEdgeNode
+	NumNodes   int             // number of the matching elite edge nodes, before the pagination
+	TotalStake *common.JSONBig // non-withdrawn stakes of the matching elite edge nodes, before the pagination
 }
 
 func (t *ThetaRPCService) GetEenpByHeight(args *GetEenpByHeightArgs, result *GetEenpResult) (err error) {
+	filter := eenFilter{withStake: args.WithStake}
+	if args.Holder != "" {
+		holder, err := parseAddress(args.Holder)
+		if err != nil {
+			return err
+		}
+		filter.holder = &holder
+	}
+	if args.Source != "" {
+		source, err := parseAddress(args.Source)
+		if err != nil {
+			return err
+		}
+		filter.source = &source
+	}
+
 	deliveredView, err := t.ledger.GetDeliveredSnapshot()
 	if err != nil {
 		return err
@@ -752,11 +1509,21 @@ func (t *ThetaRPCService) GetEenpByHeight(args *GetEenpByHeightArgs, result *Get
 		if blockStoreView == nil { // might have been pruned
 			return fmt.Errorf("the EENP for height %v does not exists, it might have been pruned", height)
 		}
-		eenp := state.NewEliteEdgeNodePool(blockStoreView, true)
-		eens := eenp.GetAll(false)
+		var eens []*core.EliteEdgeNode
+		if filter.holder != nil {
+			eens = []*core.EliteEdgeNode{}
+			if een := state.NewEliteEdgeNodePool(blockStoreView, true).Get(*filter.holder); een != nil {
+				eens = append(eens, een)
+			}
+		} else {
+			eens = state.NewEliteEdgeNodePool(blockStoreView, true).GetAll(false)
+		}
+		eens, totalStake := filter.apply(eens)
 		blockHashEenpPairs = append(blockHashEenpPairs, BlockHashEenpPair{
-			BlockHash: blockHash,
-			EENs:      eens,
+			BlockHash:  blockHash,
+			EENs:       paginateEENs(eens, uint64(args.Offset), uint64(args.Limit)),
+			NumNodes:   len(eens),
+			TotalStake: (*common.JSONBig)(totalStake),
 		})
 	}
 
@@ -765,6 +1532,55 @@ func (t *ThetaRPCService) GetEenpByHeight(args *GetEenpByHeightArgs, result *Get
 	return nil
 }
 
+// eenFilter selects the elite edge nodes returned by GetEenpByHeight
+type eenFilter struct {
+	holder    *common.Address
+	source    *common.Address
+	withStake bool
+}
+
+// apply returns the matching elite edge nodes, and the sum of their non-withdrawn stakes
+func (f eenFilter) apply(eens []*core.EliteEdgeNode) ([]*core.EliteEdgeNode, *big.Int) {
+	matched := []*core.EliteEdgeNode{}
+	totalStake := new(big.Int)
+	for _, een := range eens {
+		if f.holder != nil && een.Holder != *f.holder {
+			continue
+		}
+		if f.source != nil && !hasStakeFrom(een.StakeHolder, *f.source) {
+			continue
+		}
+		stake := een.TotalStake()
+		if f.withStake && stake.Sign() == 0 {
+			continue
+		}
+		matched = append(matched, een)
+		totalStake.Add(totalStake, stake)
+	}
+	return matched, totalStake
+}
+
+func hasStakeFrom(holder *core.StakeHolder, source common.Address) bool {
+	for _, stake := range holder.Stakes {
+		if stake.Source == source {
+			return true
+		}
+	}
+	return false
+}
+
+// paginateEENs returns the elite edge nodes of the page, or all of them if the limit is zero
+func paginateEENs(eens []*core.EliteEdgeNode, offset, limit uint64) []*core.EliteEdgeNode {
+	if offset >= uint64(len(eens)) {
+		return []*core.EliteEdgeNode{}
+	}
+	eens = eens[offset:]
+	if limit > 0 && limit < uint64(len(eens)) {
+		eens = eens[:limit]
+	}
+	return eens
+}
+
 // ------------------------------ GetStakeRewardDistributionRuleSetByHeight -----------------------------------
 
 type GetStakeRewardDistributionRuleSetByHeightArgs struct {
@@ -805,7 +1621,10 @@ func (t *ThetaRPCService) GetStakeRewardDistributionByHeight(
 
 		var stakeDistrList []*core.RewardDistribution
 		if addressStr != "" {
-			address := common.HexToAddress(addressStr)
+			address, err := parseAddress(addressStr)
+			if err != nil {
+				return err
+			}
 			rewardDistr := srdrs.Get(address)
 			stakeDistrList = []*core.RewardDistribution{rewardDistr}
 		} else {
@@ -891,6 +1710,92 @@ func (t *ThetaRPCService) GetAllPendingEliteEdgeNodeStakeReturns(
 	return nil
 }
 
+// ------------------------------ GetPendingStakeWithdrawals -----------------------------------
+
+type GetPendingStakeWithdrawalsArgs struct {
+	Source string `json:"source"`
+}
+
+type GetPendingStakeWithdrawalsResult struct {
+	Source      string                   `json:"source"`
+	Height      common.JSONUint64        `json:"height"`
+	Withdrawals []PendingStakeWithdrawal `json:"withdrawals"`
+}
+
+type PendingStakeWithdrawal struct {
+	Purpose             uint8             `json:"purpose"` // 0: validator, 1: guardian, 2: elite edge node
+	Holder              common.Address    `json:"holder"`
+	Amount              *common.JSONBig   `json:"amount"` // ThetaWei for the validators and guardians, TFuelWei for the elite edge nodes
+	ReturnHeight        common.JSONUint64 `json:"return_height"`
+	RemainingBlocks     common.JSONUint64 `json:"remaining_blocks"`
+	EstimatedReturnSecs common.JSONUint64 `json:"estimated_return_secs"` // estimated with the min block interval
+}
+
+// GetPendingStakeWithdrawals returns the stakes the source has withdrawn from the validators, guardians
+// and elite edge nodes, which have not been returned to the source yet
+func (t *ThetaRPCService) GetPendingStakeWithdrawals(
+	args *GetPendingStakeWithdrawalsArgs, result *GetPendingStakeWithdrawalsResult) (err error) {
+	if args.Source == "" {
+		return errors.New("source must be specified")
+	}
+	source, err := parseAddress(args.Source)
+	if err != nil {
+		return err
+	}
+
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+	height := deliveredView.Height()
+
+	holders := map[uint8][]*core.StakeHolder{}
+	if vcp := deliveredView.GetValidatorCandidatePool(); vcp != nil {
+		holders[core.StakeForValidator] = vcp.SortedCandidates
+	}
+	for _, g := range deliveredView.GetGuardianCandidatePool().SortedGuardians {
+		holders[core.StakeForGuardian] = append(holders[core.StakeForGuardian], g.StakeHolder)
+	}
+	for _, een := range state.NewEliteEdgeNodePool(deliveredView, true).GetAll(false) {
+		holders[core.StakeForEliteEdgeNode] = append(holders[core.StakeForEliteEdgeNode], een.StakeHolder)
+	}
+
+	blockInterval := uint64(viper.GetInt(common.CfgConsensusMinBlockInterval))
+	result.Source = args.Source
+	result.Height = common.JSONUint64(height)
+	result.Withdrawals = findPendingStakeWithdrawals(source, holders, height, blockInterval)
+
+	return nil
+}
+
+// findPendingStakeWithdrawals returns the withdrawn stakes of the source, ordered by the purpose
+func findPendingStakeWithdrawals(source common.Address, holders map[uint8][]*core.StakeHolder,
+	height uint64, blockInterval uint64) []PendingStakeWithdrawal {
+	withdrawals := []PendingStakeWithdrawal{}
+	for _, purpose := range []uint8{core.StakeForValidator, core.StakeForGuardian, core.StakeForEliteEdgeNode} {
+		for _, holder := range holders[purpose] {
+			for _, stake := range holder.Stakes {
+				if stake.Source != source || !stake.Withdrawn {
+					continue
+				}
+				remainingBlocks := uint64(0)
+				if stake.ReturnHeight > height {
+					remainingBlocks = stake.ReturnHeight - height
+				}
+				withdrawals = append(withdrawals, PendingStakeWithdrawal{
+					Purpose:             purpose,
+					Holder:              holder.Holder,
+					Amount:              (*common.JSONBig)(stake.Amount),
+					ReturnHeight:        common.JSONUint64(stake.ReturnHeight),
+					RemainingBlocks:     common.JSONUint64(remainingBlocks),
+					EstimatedReturnSecs: common.JSONUint64(remainingBlocks * blockInterval),
+				})
+			}
+		}
+	}
+	return withdrawals
+}
+
 // ------------------------------- GetCode -----------------------------------
 
 type GetCodeArgs struct {
@@ -907,7 +1812,10 @@ func (t *ThetaRPCService) GetCode(args *GetCodeArgs, result *GetCodeResult) (err
 	if args.Address == "" {
 		return errors.New("address must be specified")
 	}
-	address := common.HexToAddress(args.Address)
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
 	result.Address = args.Address
 	height := uint64(args.Height)
 
@@ -966,7 +1874,10 @@ func (t *ThetaRPCService) GetStorageAt(args *GetStorageAtArgs, result *GetStorag
 	if args.Address == "" || args.StoragePosition == "" {
 		return fmt.Errorf("address and storage_position must be specified, address: %v, storage_position: %v", args.Address, args.StoragePosition)
 	}
-	address := common.HexToAddress(args.Address)
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
 	key := common.HexToHash(args.StoragePosition)
 	height := uint64(args.Height)
 
@@ -1008,8 +1919,455 @@ func (t *ThetaRPCService) GetStorageAt(args *GetStorageAtArgs, result *GetStorag
 	return nil
 }
 
+// ------------------------------ GetLogs -----------------------------------
+
+// MaxGetLogsBlockRange is the maximum number of blocks GetLogs searches in one call.
+const MaxGetLogsBlockRange = 5000
+
+type GetLogsArgs struct {
+	FromBlock common.JSONUint64 `json:"from_block"`
+	ToBlock   common.JSONUint64 `json:"to_block"` // the last finalized block if zero
+	Addresses []common.Address  `json:"addresses"`
+	Topics    [][]common.Hash   `json:"topics"` // topics at each position, any of them matches
+}
+
+type GetLogsResult struct {
+	Logs []LogResult `json:"logs"`
+}
+
+type LogResult struct {
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	TxHash      common.Hash       `json:"transaction_hash"`
+	TxIndex     common.JSONUint64 `json:"transaction_index"`
+	LogIndex    common.JSONUint64 `json:"log_index"`
+	Address     common.Address    `json:"address"`
+	Topics      []common.Hash     `json:"topics"`
+	Data        string            `json:"data"`
+}
+
+// GetLogs returns the event logs emitted by the smart contracts in the finalized blocks of the
+// given height range, filtered by the contract addresses and the topics.
+func (t *ThetaRPCService) GetLogs(args *GetLogsArgs, result *GetLogsResult) (err error) {
+	fromBlock := uint64(args.FromBlock)
+	toBlock := uint64(args.ToBlock)
+	lastFinalizedHeight := t.consensus.GetLastFinalizedBlock().Height
+	if toBlock == 0 || toBlock > lastFinalizedHeight {
+		toBlock = lastFinalizedHeight
+	}
+	if fromBlock > toBlock {
+		return fmt.Errorf("from_block %v is greater than to_block %v", fromBlock, toBlock)
+	}
+	if toBlock-fromBlock >= MaxGetLogsBlockRange {
+		return fmt.Errorf("Can't search more than %v blocks at a time", MaxGetLogsBlockRange)
+	}
+
+	filter := &blockchain.LogFilter{
+		Addresses: args.Addresses,
+		Topics:    args.Topics,
+	}
+	result.Logs = []LogResult{}
+	for height := fromBlock; height <= toBlock; height++ {
+		entry, found := t.chain.FindBlockLogsByHeight(height)
+		if !found || !filter.MatchBloom(entry.Bloom) {
+			continue
+		}
+		for _, le := range entry.Logs {
+			if !filter.Match(le.Log) {
+				continue
+			}
+			result.Logs = append(result.Logs, LogResult{
+				BlockHash:   entry.BlockHash,
+				BlockHeight: common.JSONUint64(entry.BlockHeight),
+				TxHash:      le.TxHash,
+				TxIndex:     common.JSONUint64(le.TxIndex),
+				LogIndex:    common.JSONUint64(le.LogIndex),
+				Address:     le.Log.Address,
+				Topics:      le.Log.Topics,
+				Data:        hex.EncodeToString(le.Log.Data),
+			})
+		}
+	}
+
+	return nil
+}
+
+// ------------------------------ GetMinTxFees -----------------------------------
+
+type GetMinTxFeesArgs struct{}
+
+type MinTxFee struct {
+	TxType          string          `json:"tx_type"`
+	ProtocolMinimum *common.JSONBig `json:"protocol_minimum"`
+	NodeMinimum     *common.JSONBig `json:"node_minimum"` // nil if the node does not set a minimum
+}
+
+type GetMinTxFeesResult struct {
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	Fees        []MinTxFee        `json:"fees"`
+}
+
+// GetMinTxFees returns the minimum fees in TFuelWei of the tx types for the next block, i.e. the
+// protocol minimums and the minimums the node accepts into its mempool. For the smart contract
+// transactions, the minimums are the gas prices.
+func (t *ThetaRPCService) GetMinTxFees(args *GetMinTxFeesArgs, result *GetMinTxFeesResult) (err error) {
+	blockHeight := t.ledger.State().Height() + 1
+	minTxFees := t.ledger.MinTxFees()
+
+	result.BlockHeight = common.JSONUint64(blockHeight)
+	result.Fees = []MinTxFee{}
+	for _, txType := range ledger.MinTxFeeTxTypes {
+		fee := MinTxFee{
+			TxType:          txType.String(),
+			ProtocolMinimum: (*common.JSONBig)(ledger.ProtocolMinTxFee(txType, blockHeight)),
+		}
+		if nodeMinimum := minTxFees.Get(txType); nodeMinimum != nil {
+			fee.NodeMinimum = (*common.JSONBig)(nodeMinimum)
+		}
+		result.Fees = append(result.Fees, fee)
+	}
+	return nil
+}
+
+// ------------------------------ GetBaseFee -----------------------------------
+
+type GetBaseFeeArgs struct{}
+
+type GetBaseFeeResult struct {
+	BlockHeight common.JSONUint64 `json:"block_height"`
+	BaseFee     *common.JSONBig   `json:"base_fee"`
+	Enforced    bool              `json:"enforced"`
+}
+
+// GetBaseFee returns the base fee in TFuelWei of the smart contract transactions in the next block,
+// i.e. the minimum gas price after the base fee is enforced.
+func (t *ThetaRPCService) GetBaseFee(args *GetBaseFeeArgs, result *GetBaseFeeResult) (err error) {
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return err
+	}
+	blockHeight := deliveredView.Height() + 1
+
+	result.BlockHeight = common.JSONUint64(blockHeight)
+	result.BaseFee = (*common.JSONBig)(deliveredView.GetBaseFee(blockHeight))
+	result.Enforced = blockHeight >= common.HeightEnableBaseFee
+	return nil
+}
+
+// ------------------------------ GetBurnedFees -----------------------------------
+
+type GetBurnedFeesArgs struct {
+	StartHeight common.JSONUint64 `json:"start_height"`
+	EndHeight   common.JSONUint64 `json:"end_height"`
+}
+
+type GetBurnedFeesResult struct {
+	StartHeight     common.JSONUint64 `json:"start_height"`
+	EndHeight       common.JSONUint64 `json:"end_height"`
+	BurnedFees      *common.JSONBig   `json:"burned_fees"`       // TFuelWei burned by the tx fees of the blocks in the range
+	TotalBurnedFees *common.JSONBig   `json:"total_burned_fees"` // TFuelWei burned by the tx fees up to the end height
+}
+
+// GetBurnedFees returns the TFuel burned by the transaction fees of the finalized blocks from the start
+// height to the end height (inclusive). The burned fees are tracked since HeightEnableFeeBurnTracking.
+func (t *ThetaRPCService) GetBurnedFees(args *GetBurnedFeesArgs, result *GetBurnedFeesResult) (err error) {
+	startHeight := uint64(args.StartHeight)
+	endHeight := uint64(args.EndHeight)
+	if startHeight > endHeight {
+		return errors.New("Start height must be less than or equal to end height")
+	}
+	if startHeight < common.HeightEnableFeeBurnTracking {
+		return fmt.Errorf("Burned fees are only tracked since height %v", common.HeightEnableFeeBurnTracking)
+	}
+
+	endBlock := t.findFinalizedBlock(endHeight)
+	if endBlock == nil {
+		return fmt.Errorf("Finalized block not found for height %v", endHeight)
+	}
+	totalBurnedFees, err := t.getTotalBurnedFees(endBlock.Height, endBlock.StateHash)
+	if err != nil {
+		return err
+	}
+
+	burnedFees := new(big.Int).Set(totalBurnedFees)
+	if startHeight > common.HeightEnableFeeBurnTracking {
+		startBlock := t.findFinalizedBlock(startHeight - 1)
+		if startBlock == nil {
+			return fmt.Errorf("Finalized block not found for height %v", startHeight-1)
+		}
+		startTotalBurnedFees, err := t.getTotalBurnedFees(startBlock.Height, startBlock.StateHash)
+		if err != nil {
+			return err
+		}
+		burnedFees.Sub(burnedFees, startTotalBurnedFees)
+	}
+
+	result.StartHeight = args.StartHeight
+	result.EndHeight = args.EndHeight
+	result.BurnedFees = (*common.JSONBig)(burnedFees)
+	result.TotalBurnedFees = (*common.JSONBig)(totalBurnedFees)
+	return nil
+}
+
+// ------------------------------ GetRewardsByAddress -----------------------------------
+
+type GetRewardsByAddressArgs struct {
+	Address     string            `json:"address"`
+	StartHeight common.JSONUint64 `json:"start_height"`
+	EndHeight   common.JSONUint64 `json:"end_height"` // the last finalized block if zero
+}
+
+type GetRewardsByAddressResult struct {
+	Address     common.Address    `json:"address"`
+	StartHeight common.JSONUint64 `json:"start_height"`
+	EndHeight   common.JSONUint64 `json:"end_height"`
+	TotalReward *common.JSONBig   `json:"total_reward"` // TFuelWei
+	Rewards     []RewardResult    `json:"rewards"`
+}
+
+type RewardResult struct {
+	Height common.JSONUint64 `json:"height"`
+	Holder common.Address    `json:"holder"`
+	Reason string            `json:"reason"`
+	Amount *common.JSONBig   `json:"amount"` // TFuelWei
+}
+
+// GetRewardsByAddress returns the rewards paid to the address by the coinbase transactions of the
+// finalized blocks in the height range (inclusive). The rewards are only indexed if the node runs
+// with ledger.rewardIndexEnabled.
+func (t *ThetaRPCService) GetRewardsByAddress(args *GetRewardsByAddressArgs, result *GetRewardsByAddressResult) (err error) {
+	address, err := parseAddress(args.Address)
+	if err != nil {
+		return err
+	}
+	startHeight := uint64(args.StartHeight)
+	endHeight := uint64(args.EndHeight)
+	lastFinalizedHeight := t.consensus.GetLastFinalizedBlock().Height
+	if endHeight == 0 || endHeight > lastFinalizedHeight {
+		endHeight = lastFinalizedHeight
+	}
+	if startHeight > endHeight {
+		return fmt.Errorf("start_height %v is greater than end_height %v", startHeight, endHeight)
+	}
+
+	totalReward := new(big.Int)
+	result.Rewards = []RewardResult{}
+	for _, event := range t.chain.FindRewardEventsByAddress(address, startHeight, endHeight) {
+		totalReward.Add(totalReward, event.Amount)
+		result.Rewards = append(result.Rewards, RewardResult{
+			Height: common.JSONUint64(event.Height),
+			Holder: event.Holder,
+			Reason: event.Reason,
+			Amount: (*common.JSONBig)(event.Amount),
+		})
+	}
+
+	result.Address = address
+	result.StartHeight = common.JSONUint64(startHeight)
+	result.EndHeight = common.JSONUint64(endHeight)
+	result.TotalReward = (*common.JSONBig)(totalReward)
+	return nil
+}
+
+// ------------------------------ GetChainStats -----------------------------------
+
+// The windows (in blocks) of the chain statistics if not specified.
+var defaultChainStatsWindows = []common.JSONUint64{100, 1000, blockchain.MaxStatsWindow}
+
+type GetChainStatsArgs struct {
+	Windows []common.JSONUint64 `json:"windows"`
+}
+
+type GetChainStatsResult struct {
+	Stats []ChainStatsWindow `json:"stats"`
+}
+
+type ChainStatsWindow struct {
+	Window        common.JSONUint64 `json:"window"`
+	NumBlocks     common.JSONUint64 `json:"num_blocks"` // less than the window if not enough blocks have been finalized since the node started
+	StartHeight   common.JSONUint64 `json:"start_height"`
+	EndHeight     common.JSONUint64 `json:"end_height"`
+	BlockInterval float64           `json:"block_interval"` // average in seconds
+	TxsPerBlock   float64           `json:"txs_per_block"`
+	TPS           float64           `json:"tps"`
+	GasPerBlock   float64           `json:"gas_per_block"`
+	TotalFees     *common.JSONBig   `json:"total_fees"` // TFuelWei
+	FeesPerBlock  *common.JSONBig   `json:"fees_per_block"`
+}
+
+// GetChainStats returns the rolling averages of the block interval, the transactions, the gas used
+// and the fees of the latest finalized blocks over the given windows. The statistics are kept for
+// up to blockchain.MaxStatsWindow blocks finalized since the node started.
+func (t *ThetaRPCService) GetChainStats(args *GetChainStatsArgs, result *GetChainStatsResult) (err error) {
+	windows := args.Windows
+	if len(windows) == 0 {
+		windows = defaultChainStatsWindows
+	}
+
+	result.Stats = []ChainStatsWindow{}
+	for _, window := range windows {
+		if window == 0 || window > blockchain.MaxStatsWindow {
+			return fmt.Errorf("Window must be between 1 and %v blocks", blockchain.MaxStatsWindow)
+		}
+		stats := t.chain.Stats().Window(uint64(window))
+		if stats == nil {
+			return errors.New("Not enough blocks have been finalized since the node started")
+		}
+		feesPerBlock := new(big.Int).Div(stats.TotalFees, new(big.Int).SetUint64(stats.NumBlocks))
+		result.Stats = append(result.Stats, ChainStatsWindow{
+			Window:        window,
+			NumBlocks:     common.JSONUint64(stats.NumBlocks),
+			StartHeight:   common.JSONUint64(stats.StartHeight),
+			EndHeight:     common.JSONUint64(stats.EndHeight),
+			BlockInterval: stats.BlockInterval,
+			TxsPerBlock:   stats.TxsPerBlock,
+			TPS:           stats.TPS,
+			GasPerBlock:   stats.GasPerBlock,
+			TotalFees:     (*common.JSONBig)(stats.TotalFees),
+			FeesPerBlock:  (*common.JSONBig)(feesPerBlock),
+		})
+	}
+	return nil
+}
+
+// ------------------------------ GetStateDiff -----------------------------------
+
+type GetStateDiffArgs struct {
+	Height common.JSONUint64 `json:"height"`
+	Hash   common.Hash       `json:"hash"` // optional, takes precedence over the height
+}
+
+type GetStateDiffResult struct {
+	BlockHeight     common.JSONUint64 `json:"block_height"`
+	BlockHash       common.Hash       `json:"block_hash"`
+	ParentStateRoot common.Hash       `json:"parent_state_root"`
+	StateRoot       common.Hash       `json:"state_root"`
+	Lines           []string          `json:"lines"` // the canonical text of the diff, see ledger.StateDiff
+}
+
+// GetStateDiff returns the sorted key/value diff of the state made by one of the recently applied
+// blocks. It requires ledger.stateDiffEnabled.
+func (t *ThetaRPCService) GetStateDiff(args *GetStateDiffArgs, result *GetStateDiffResult) (err error) {
+	recorder := t.ledger.StateDiffs()
+	if recorder == nil {
+		return errors.New("State diffs are disabled, please set ledger.stateDiffEnabled")
+	}
+	diff, found := recorder.Get(uint64(args.Height), args.Hash)
+	if !found {
+		return errors.New("State diff not found, only the recently applied blocks are kept")
+	}
+	result.BlockHeight = common.JSONUint64(diff.BlockHeight)
+	result.BlockHash = diff.BlockHash
+	result.ParentStateRoot = diff.ParentStateRoot
+	result.StateRoot = diff.StateRoot
+	result.Lines = diff.Lines()
+	return nil
+}
+
 // ------------------------------ Utils ------------------------------
 
+// getCoinbaseBreakdown decodes the coinbase transaction of the block, and attributes the rewards to
+// the roles of the recipients. It returns nil if the block has no coinbase transaction.
+func (t *ThetaRPCService) getCoinbaseBreakdown(block *core.ExtendedBlock) *CoinbaseBreakdown {
+	if len(block.Txs) == 0 {
+		return nil
+	}
+	tx, err := types.TxFromBytes(block.Txs[0])
+	if err != nil {
+		return nil
+	}
+	coinbaseTx, ok := tx.(*types.CoinbaseTx)
+	if !ok {
+		return nil
+	}
+
+	roles := make(map[common.Address][]string)
+	if len(coinbaseTx.Outputs) > 0 {
+		events, err := t.ledger.GetBlockRewardEvents(block.Block)
+		if err != nil {
+			logger.Debugf("Failed to attribute the rewards of block %v: %v", block.Hash().Hex(), err)
+		}
+		for _, event := range events {
+			for _, role := range rewardReasonRoles(event.Reason) {
+				roles[event.Beneficiary] = appendRole(roles[event.Beneficiary], role)
+			}
+		}
+	}
+
+	breakdown := &CoinbaseBreakdown{
+		Proposer:   coinbaseTx.Proposer.Address,
+		Recipients: []CoinbaseRecipient{},
+	}
+	for _, output := range coinbaseTx.Outputs {
+		recipientRoles := roles[output.Address]
+		if recipientRoles == nil {
+			recipientRoles = []string{}
+		}
+		breakdown.Recipients = append(breakdown.Recipients, CoinbaseRecipient{
+			Address: output.Address,
+			Coins:   output.Coins,
+			Roles:   recipientRoles,
+		})
+	}
+	return breakdown
+}
+
+// rewardReasonRoles returns the recipient roles of a reward reason. The reward aggregated over the
+// validator and guardian stakes of a source counts for both roles.
+func rewardReasonRoles(reason string) []string {
+	if reason == blockchain.RewardReasonStaking {
+		return []string{blockchain.RewardReasonValidator, blockchain.RewardReasonGuardian}
+	}
+	return []string{reason}
+}
+
+// appendRole appends the role to the roles if not included yet
+func appendRole(roles []string, role string) []string {
+	for _, r := range roles {
+		if r == role {
+			return roles
+		}
+	}
+	return append(roles, role)
+}
+
+// getTotalBurnedFees returns the TFuel burned by the transaction fees up to the block with the given state root
+func (t *ThetaRPCService) getTotalBurnedFees(height uint64, stateHash common.Hash) (*big.Int, error) {
+	deliveredView, err := t.ledger.GetDeliveredSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	sv := state.NewStoreView(height, stateHash, deliveredView.GetDB())
+	if sv == nil { // might have been pruned
+		return nil, fmt.Errorf("the state for height %v does not exists, it might have been pruned", height)
+	}
+	return sv.GetTotalBurnedFees(), nil
+}
+
+// getBlockBurnedFees returns the TFuel burned by the transaction fees of the block, or nil if the
+// burned fees are not tracked at the block height or the states are not available
+func (t *ThetaRPCService) getBlockBurnedFees(block *core.ExtendedBlock) *big.Int {
+	if block.Height < common.HeightEnableFeeBurnTracking {
+		return nil
+	}
+	totalBurnedFees, err := t.getTotalBurnedFees(block.Height, block.StateHash)
+	if err != nil {
+		return nil
+	}
+	if block.Height == common.HeightEnableFeeBurnTracking {
+		return totalBurnedFees
+	}
+	parent, err := t.chain.FindBlock(block.Parent)
+	if err != nil {
+		return nil
+	}
+	parentTotalBurnedFees, err := t.getTotalBurnedFees(parent.Height, parent.StateHash)
+	if err != nil {
+		return nil
+	}
+	return totalBurnedFees.Sub(totalBurnedFees, parentTotalBurnedFees)
+}
+
 func (t *ThetaRPCService) gatherTxs(block *core.ExtendedBlock, txs *[]interface{}, includeEthTxHashes bool) error {
 	// Parse and fulfill Txs.
 	//var tx types.Tx
@@ -1078,7 +2436,18 @@ func getTxType(tx types.Tx) byte {
 		t = TxTypeDepositStakeTxV2
 	case *types.StakeRewardDistributionTx:
 		t = TxTypeStakeRewardDistributionTx
+	case *types.VestingTx:
+		t = TxTypeVesting
 	}
 
 	return t
 }
+
+// parseAddress parses the address passed to the RPC methods, which must carry a valid checksum if in mixed case
+func parseAddress(addressStr string) (common.Address, error) {
+	address, err := common.ParseAddress(addressStr, viper.GetBool(common.CfgRPCStrictAddressChecksum))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%v: %v", err, addressStr)
+	}
+	return address, nil
+}
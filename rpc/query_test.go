@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+func TestEENFilterAndPagination(t *testing.T) {
+	assert := assert.New(t)
+
+	source1 := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	source2 := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	newEEN := func(holder string, source common.Address, amount int64, withdrawn bool) *core.EliteEdgeNode {
+		stake := core.NewStake(source, big.NewInt(amount))
+		stake.Withdrawn = withdrawn
+		return core.NewEliteEdgeNode(core.NewStakeHolder(common.HexToAddress(holder), []*core.Stake{stake}), nil)
+	}
+	eens := []*core.EliteEdgeNode{
+		newEEN("0x2000000000000000000000000000000000000001", source1, 100, false),
+		newEEN("0x2000000000000000000000000000000000000002", source2, 200, false),
+		newEEN("0x2000000000000000000000000000000000000003", source1, 300, true),
+		newEEN("0x2000000000000000000000000000000000000004", source1, 400, false),
+	}
+
+	matched, totalStake := eenFilter{}.apply(eens)
+	assert.Equal(4, len(matched))
+	assert.Equal(big.NewInt(700), totalStake)
+
+	matched, totalStake = eenFilter{source: &source1, withStake: true}.apply(eens)
+	assert.Equal(2, len(matched))
+	assert.Equal(big.NewInt(500), totalStake)
+
+	holder := eens[1].Holder
+	matched, _ = eenFilter{holder: &holder}.apply(eens)
+	assert.Equal([]*core.EliteEdgeNode{eens[1]}, matched)
+
+	assert.Equal(eens, paginateEENs(eens, 0, 0))
+	assert.Equal(eens[1:3], paginateEENs(eens, 1, 2))
+	assert.Equal(eens[3:], paginateEENs(eens, 3, 10))
+	assert.Empty(paginateEENs(eens, 4, 10))
+}
+
+func TestFindPendingStakeWithdrawals(t *testing.T) {
+	assert := assert.New(t)
+
+	source := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	newStake := func(source common.Address, amount int64, returnHeight uint64) *core.Stake {
+		stake := core.NewStake(source, big.NewInt(amount))
+		if returnHeight != 0 {
+			stake.Withdrawn = true
+			stake.ReturnHeight = returnHeight
+		}
+		return stake
+	}
+	validator := common.HexToAddress("0x2000000000000000000000000000000000000001")
+	guardian := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	holders := map[uint8][]*core.StakeHolder{
+		core.StakeForValidator: {core.NewStakeHolder(validator, []*core.Stake{
+			newStake(source, 100, 0),
+			newStake(source, 200, 1100),
+		})},
+		core.StakeForGuardian: {core.NewStakeHolder(guardian, []*core.Stake{
+			newStake(other, 300, 1200),
+			newStake(source, 400, 900),
+		})},
+	}
+
+	withdrawals := findPendingStakeWithdrawals(source, holders, 1000, 6)
+	assert.Equal(2, len(withdrawals))
+
+	assert.Equal(core.StakeForValidator, withdrawals[0].Purpose)
+	assert.Equal(validator, withdrawals[0].Holder)
+	assert.Equal(big.NewInt(200), (*big.Int)(withdrawals[0].Amount))
+	assert.Equal(common.JSONUint64(100), withdrawals[0].RemainingBlocks)
+	assert.Equal(common.JSONUint64(600), withdrawals[0].EstimatedReturnSecs)
+
+	assert.Equal(core.StakeForGuardian, withdrawals[1].Purpose)
+	assert.Equal(common.JSONUint64(0), withdrawals[1].RemainingBlocks)
+}
+
+func TestRewardReasonRoles(t *testing.T) {
+	assert := assert.New(t)
+
+	roles := []string{}
+	for _, reason := range []string{blockchain.RewardReasonGuardian, blockchain.RewardReasonStaking, blockchain.RewardReasonSplit} {
+		for _, role := range rewardReasonRoles(reason) {
+			roles = appendRole(roles, role)
+		}
+	}
+	assert.Equal([]string{blockchain.RewardReasonGuardian, blockchain.RewardReasonValidator, blockchain.RewardReasonSplit}, roles)
+}
+
+func TestRPCMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	methods := rpcMethods()
+	assert.Contains(methods, "theta.GetVersion")
+	assert.Contains(methods, "theta.BroadcastRawTransaction")
+	assert.Contains(methods, "theta.DecodeTransaction")
+	assert.NotContains(methods, "theta.txCallback")
+	assert.NotContains(methods, "theta.getCoinbaseBreakdown")
+}
@@ -0,0 +1,344 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/thetatoken/theta/common"
+)
+
+// ** REST API **
+//
+// The REST API is a facade of the JSON-RPC API for the clients without a JSON-RPC library. Each
+// endpoint calls the corresponding RPC method, and returns its result as the response body. The
+// errors are returned as {"error": {"message": "..."}}, same as the timeout responses of the RPC
+// server. The endpoints are documented by the OpenAPI document served at /openapi.json.
+
+type restErrorResponse struct {
+	Error restError `json:"error"`
+}
+
+type restError struct {
+	Message string `json:"message"`
+}
+
+type restBroadcastTransactionRequest struct {
+	TxBytes string `json:"tx_bytes"`
+	Async   bool   `json:"async"`
+}
+
+// registerRESTHandlers adds the REST endpoints to the router
+func (t *ThetaRPCService) registerRESTHandlers(router *mux.Router) {
+	handle := func(path string, method string, handler http.HandlerFunc) {
+		router.Handle(path, corsMiddleware(handler)).Methods(method, "OPTIONS")
+	}
+	handle("/openapi.json", "GET", serveOpenAPISpec)
+	handle("/status", "GET", t.restGetStatus)
+	handle("/blocks/{height:[0-9]+}", "GET", t.restGetBlockByHeight)
+	handle("/accounts/{address}", "GET", t.restGetAccount)
+	handle("/transactions/{hash}", "GET", t.restGetTransaction)
+	handle("/transactions", "POST", t.restBroadcastTransaction)
+}
+
+func (t *ThetaRPCService) restGetStatus(w http.ResponseWriter, r *http.Request) {
+	result := &GetStatusResult{}
+	if err := t.GetStatus(&GetStatusArgs{}, result); err != nil {
+		writeRESTError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeRESTResult(w, result)
+}
+
+func (t *ThetaRPCService) restGetBlockByHeight(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(mux.Vars(r)["height"], 10, 64)
+	if err != nil {
+		writeRESTError(w, http.StatusBadRequest, "Invalid block height")
+		return
+	}
+	args := &GetBlockByHeightArgs{
+		Height:             common.JSONUint64(height),
+		IncludeEthTxHashes: r.URL.Query().Get("include_eth_tx_hashes") == "true",
+	}
+	result := &GetBlockResult{}
+	if err := t.GetBlockByHeight(args, result); err != nil {
+		writeRESTError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if result.GetBlockResultInner == nil {
+		writeRESTError(w, http.StatusNotFound, "Finalized block is not found")
+		return
+	}
+	writeRESTResult(w, result)
+}
+
+func (t *ThetaRPCService) restGetAccount(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+	if _, err := parseAddress(address); err != nil {
+		writeRESTError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	args := &GetAccountArgs{
+		Address: address,
+		Preview: r.URL.Query().Get("preview") == "true",
+	}
+	if heightStr := r.URL.Query().Get("height"); heightStr != "" {
+		height, err := strconv.ParseUint(heightStr, 10, 64)
+		if err != nil {
+			writeRESTError(w, http.StatusBadRequest, "Invalid block height")
+			return
+		}
+		args.Height = common.JSONUint64(height)
+	}
+	result := &GetAccountResult{}
+	if err := t.GetAccount(args, result); err != nil {
+		writeRESTError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if result.Account == nil {
+		writeRESTError(w, http.StatusNotFound, "Account is not found")
+		return
+	}
+	writeRESTResult(w, result)
+}
+
+func (t *ThetaRPCService) restGetTransaction(w http.ResponseWriter, r *http.Request) {
+	result := &GetTransactionResult{}
+	if err := t.GetTransaction(&GetTransactionArgs{Hash: mux.Vars(r)["hash"]}, result); err != nil {
+		writeRESTError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if result.Status == TxStatusNotFound {
+		writeRESTError(w, http.StatusNotFound, "Transaction is not found")
+		return
+	}
+	writeRESTResult(w, result)
+}
+
+func (t *ThetaRPCService) restBroadcastTransaction(w http.ResponseWriter, r *http.Request) {
+	req := &restBroadcastTransactionRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil || req.TxBytes == "" {
+		writeRESTError(w, http.StatusBadRequest, "Request body must contain the tx_bytes")
+		return
+	}
+
+	if req.Async {
+		result := &BroadcastRawTransactionAsyncResult{}
+		if err := t.BroadcastRawTransactionAsync(&BroadcastRawTransactionAsyncArgs{TxBytes: req.TxBytes}, result); err != nil {
+			writeRESTError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeRESTResult(w, result)
+		return
+	}
+
+	result := &BroadcastRawTransactionResult{}
+	if err := t.BroadcastRawTransaction(&BroadcastRawTransactionArgs{TxBytes: req.TxBytes}, result); err != nil {
+		writeRESTError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeRESTResult(w, result)
+}
+
+func writeRESTResult(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		logger.Warnf("Failed to encode REST response: %v", err)
+	}
+}
+
+func writeRESTError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(restErrorResponse{Error: restError{Message: message}})
+}
+
+func serveOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPISpec))
+}
+
+// openAPISpec is the OpenAPI document of the REST API
+const openAPISpec = `{
+  "openapi": "3.0.0",
+  "info": {
+    "title": "Theta REST API",
+    "description": "REST facade of the Theta JSON-RPC API. The responses have the same fields as the results of the corresponding RPC methods.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/status": {
+      "get": {
+        "summary": "Get the status of the node",
+        "description": "Same as theta.GetStatus",
+        "responses": {
+          "200": {"description": "Status of the node", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Status"}}}},
+          "500": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/blocks/{height}": {
+      "get": {
+        "summary": "Get the finalized block at the height",
+        "description": "Same as theta.GetBlockByHeight",
+        "parameters": [
+          {"name": "height", "in": "path", "required": true, "schema": {"type": "integer", "format": "uint64"}},
+          {"name": "include_eth_tx_hashes", "in": "query", "schema": {"type": "boolean"}}
+        ],
+        "responses": {
+          "200": {"description": "The block", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Block"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/accounts/{address}": {
+      "get": {
+        "summary": "Get the account",
+        "description": "Same as theta.GetAccount",
+        "parameters": [
+          {"name": "address", "in": "path", "required": true, "schema": {"$ref": "#/components/schemas/Address"}},
+          {"name": "height", "in": "query", "description": "The latest finalized height if not specified", "schema": {"type": "integer", "format": "uint64"}},
+          {"name": "preview", "in": "query", "description": "Preview the account from the screened view", "schema": {"type": "boolean"}}
+        ],
+        "responses": {
+          "200": {"description": "The account", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Account"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/transactions/{hash}": {
+      "get": {
+        "summary": "Get the transaction and its status",
+        "description": "Same as theta.GetTransaction",
+        "parameters": [
+          {"name": "hash", "in": "path", "required": true, "schema": {"$ref": "#/components/schemas/Hash"}}
+        ],
+        "responses": {
+          "200": {"description": "The transaction", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Transaction"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/transactions": {
+      "post": {
+        "summary": "Broadcast a signed transaction",
+        "description": "Same as theta.BroadcastRawTransaction, or theta.BroadcastRawTransactionAsync if async is true",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {
+            "type": "object",
+            "required": ["tx_bytes"],
+            "properties": {
+              "tx_bytes": {"type": "string", "description": "Hex encoded signed transaction"},
+              "async": {"type": "boolean", "description": "Return without waiting for the transaction to be included in a block"}
+            }
+          }}}
+        },
+        "responses": {
+          "200": {"description": "Hash of the transaction, and the header of the block including it for the sync broadcast", "content": {"application/json": {"schema": {
+            "type": "object",
+            "properties": {
+              "hash": {"$ref": "#/components/schemas/Hash"},
+              "block": {"type": "object"}
+            }
+          }}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Address": {"type": "string", "example": "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"},
+      "Hash": {"type": "string", "example": "0x9f1e77b08c9fa8984096a735d0aae6b0e43aee297e42c54ce36334103ddd67a7"},
+      "Uint64": {"type": "string", "description": "Decimal encoded unsigned integer"},
+      "Coins": {
+        "type": "object",
+        "properties": {
+          "thetawei": {"type": "string"},
+          "tfuelwei": {"type": "string"}
+        }
+      },
+      "Status": {
+        "type": "object",
+        "properties": {
+          "address": {"$ref": "#/components/schemas/Address"},
+          "chain_id": {"type": "string"},
+          "peer_id": {"type": "string"},
+          "latest_finalized_block_hash": {"$ref": "#/components/schemas/Hash"},
+          "latest_finalized_block_height": {"$ref": "#/components/schemas/Uint64"},
+          "latest_finalized_block_time": {"type": "string"},
+          "latest_finalized_block_epoch": {"$ref": "#/components/schemas/Uint64"},
+          "current_epoch": {"$ref": "#/components/schemas/Uint64"},
+          "current_height": {"$ref": "#/components/schemas/Uint64"},
+          "current_time": {"type": "string"},
+          "syncing": {"type": "boolean"},
+          "genesis_block_hash": {"$ref": "#/components/schemas/Hash"},
+          "best_peer_height": {"$ref": "#/components/schemas/Uint64"},
+          "blocks_remaining": {"$ref": "#/components/schemas/Uint64"},
+          "sync_rate": {"type": "number", "description": "blocks per second over the last minute"},
+          "catching_up": {"type": "boolean"},
+          "num_peers": {"type": "integer"}
+        }
+      },
+      "Account": {
+        "type": "object",
+        "properties": {
+          "address": {"$ref": "#/components/schemas/Address"},
+          "sequence": {"$ref": "#/components/schemas/Uint64"},
+          "coins": {"$ref": "#/components/schemas/Coins"},
+          "reserved_funds": {"type": "array", "items": {"type": "object"}},
+          "last_updated_block_height": {"$ref": "#/components/schemas/Uint64"},
+          "root": {"$ref": "#/components/schemas/Hash"},
+          "code": {"$ref": "#/components/schemas/Hash"}
+        }
+      },
+      "Block": {
+        "type": "object",
+        "properties": {
+          "chain_id": {"type": "string"},
+          "epoch": {"$ref": "#/components/schemas/Uint64"},
+          "height": {"$ref": "#/components/schemas/Uint64"},
+          "parent": {"$ref": "#/components/schemas/Hash"},
+          "transactions_hash": {"$ref": "#/components/schemas/Hash"},
+          "state_hash": {"$ref": "#/components/schemas/Hash"},
+          "timestamp": {"type": "string"},
+          "proposer": {"$ref": "#/components/schemas/Address"},
+          "hcc": {"type": "object"},
+          "guardian_votes": {"type": "object"},
+          "children": {"type": "array", "items": {"$ref": "#/components/schemas/Hash"}},
+          "status": {"type": "integer"},
+          "hash": {"$ref": "#/components/schemas/Hash"},
+          "transactions": {"type": "array", "items": {"type": "object"}}
+        }
+      },
+      "Transaction": {
+        "type": "object",
+        "properties": {
+          "block_hash": {"$ref": "#/components/schemas/Hash"},
+          "block_height": {"$ref": "#/components/schemas/Uint64"},
+          "status": {"type": "string", "enum": ["not_found", "pending", "finalized", "abandoned"]},
+          "hash": {"$ref": "#/components/schemas/Hash"},
+          "type": {"type": "integer"},
+          "transaction": {"type": "object"},
+          "receipt": {"type": "object"}
+        }
+      }
+    },
+    "responses": {
+      "Error": {
+        "description": "Error",
+        "content": {"application/json": {"schema": {
+          "type": "object",
+          "properties": {
+            "error": {"type": "object", "properties": {"message": {"type": "string"}}}
+          }
+        }}}
+      }
+    }
+  }
+}
+`
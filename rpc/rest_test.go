@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRESTOpenAPISpec(t *testing.T) {
+	assert := assert.New(t)
+
+	router := mux.NewRouter()
+	(&ThetaRPCService{}).registerRESTHandlers(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	assert.Equal(http.StatusOK, w.Code)
+
+	var spec map[string]interface{}
+	assert.Nil(json.Unmarshal(w.Body.Bytes(), &spec))
+	paths, ok := spec["paths"].(map[string]interface{})
+	assert.True(ok)
+	for _, path := range []string{"/status", "/blocks/{height}", "/accounts/{address}", "/transactions/{hash}", "/transactions"} {
+		assert.Contains(paths, path)
+	}
+}
+
+func TestRESTInvalidRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	router := mux.NewRouter()
+	(&ThetaRPCService{}).registerRESTHandlers(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/accounts/0xinvalid", nil))
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	var resp restErrorResponse
+	assert.Nil(json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEmpty(resp.Error.Message)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/transactions", strings.NewReader("{}")))
+	assert.Equal(http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/blocks/latest", nil))
+	assert.Equal(http.StatusNotFound, w.Code)
+}
@@ -24,7 +24,9 @@ import (
 	"github.com/thetatoken/theta/dispatcher"
 	"github.com/thetatoken/theta/ledger"
 	"github.com/thetatoken/theta/mempool"
+	"github.com/thetatoken/theta/netsync"
 	"github.com/thetatoken/theta/rpc/lib/rpc-codec/jsonrpc2"
+	"github.com/thetatoken/theta/watch"
 	"golang.org/x/net/netutil"
 	"golang.org/x/net/websocket"
 )
@@ -37,6 +39,10 @@ type ThetaRPCService struct {
 	dispatcher *dispatcher.Dispatcher
 	chain      *blockchain.Chain
 	consensus  *consensus.ConsensusEngine
+	syncMgr    *netsync.SyncManager
+	watched    *watch.Registry
+
+	supplyCache supplyCache
 
 	// Life cycle
 	wg      *sync.WaitGroup
@@ -53,11 +59,13 @@ type ThetaRPCServer struct {
 	handler  *rpc.Server
 	router   *mux.Router
 	listener net.Listener
+
+	grpc *ThetaGRPCService // nil if the gRPC service is disabled
 }
 
 // NewThetaRPCServer creates a new instance of ThetaRPCServer.
 func NewThetaRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatcher *dispatcher.Dispatcher,
-	chain *blockchain.Chain, consensus *consensus.ConsensusEngine) *ThetaRPCServer {
+	chain *blockchain.Chain, consensus *consensus.ConsensusEngine, syncMgr *netsync.SyncManager, watched *watch.Registry) *ThetaRPCServer {
 	t := &ThetaRPCServer{
 		ThetaRPCService: &ThetaRPCService{
 			wg: &sync.WaitGroup{},
@@ -69,6 +77,8 @@ func NewThetaRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatch
 	t.dispatcher = dispatcher
 	t.chain = chain
 	t.consensus = consensus
+	t.syncMgr = syncMgr
+	t.watched = watched
 
 	s := rpc.NewServer()
 	s.RegisterName("theta", t.ThetaRPCService)
@@ -77,15 +87,34 @@ func NewThetaRPCServer(mempool *mempool.Mempool, ledger *ledger.Ledger, dispatch
 
 	t.router = mux.NewRouter()
 	t.router.Handle("/", &defaultHTTPHandler{})
-	t.router.Handle("/rpc", corsMiddleware(TimeoutHandler(jsonrpc2.HTTPHandler(s), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, "")))
-	t.router.Handle("/ws", websocket.Handler(func(ws *websocket.Conn) {
-		s.ServeCodec(jsonrpc2.NewServerCodec(ws, s))
-	}))
+	t.router.Handle("/rpc", corsMiddleware(requestIDMiddleware(TimeoutHandler(jsonrpc2.HTTPHandlerWithCodecWrapper(s, wrapHTTPServerCodec), viper.GetDuration(common.CfgRPCTimeoutSecs)*time.Second, ""))))
+	t.router.Handle("/ws", websocket.Server{
+		Handshake: checkWebsocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			requestID := ""
+			if viper.GetBool(common.CfgRPCRequestIDEnabled) {
+				requestID = newRequestID()
+			}
+			s.ServeCodec(newStatsServerCodec(jsonrpc2.NewServerCodec(ws, s), requestID))
+		},
+	})
+	t.router.Handle("/ws/watch", websocket.Server{
+		Handshake: checkWebsocketOrigin,
+		Handler:   t.serveWatchedTxs,
+	})
+	t.registerHealthHandlers(t.router)
+	if viper.GetBool(common.CfgRPCRESTEnabled) {
+		t.registerRESTHandlers(t.router)
+	}
 
 	t.server = &http.Server{
 		Handler: t.router,
 	}
 
+	if viper.GetBool(common.CfgGRPCEnabled) {
+		t.grpc = newThetaGRPCService(t.ThetaRPCService)
+	}
+
 	logger = util.GetLoggerForModule("rpc")
 
 	return t
@@ -108,10 +137,16 @@ func (t *ThetaRPCServer) mainLoop() {
 	defer t.wg.Done()
 
 	go t.serve()
+	if t.grpc != nil {
+		go t.grpc.serve()
+	}
 
 	<-t.ctx.Done()
 	t.stopped = true
 	t.server.Shutdown(t.ctx)
+	if t.grpc != nil {
+		t.grpc.stop()
+	}
 }
 
 func (t *ThetaRPCServer) serve() {
@@ -128,22 +163,18 @@ func (t *ThetaRPCServer) serve() {
 	ll := netutil.LimitListener(l, viper.GetInt(common.CfgRPCMaxConnections))
 	t.listener = ll
 
-	logger.Info(t.server.Serve(ll))
-}
-
-func corsMiddleware(handler http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		//Allow CORS here By * or specific origin
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Headers", "*")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+	certFile := viper.GetString(common.CfgRPCTLSCertFile)
+	keyFile := viper.GetString(common.CfgRPCTLSKeyFile)
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			logger.Fatalf("Both %v and %v need to be set to enable TLS", common.CfgRPCTLSCertFile, common.CfgRPCTLSKeyFile)
 		}
+		logger.Info("RPC server serves TLS")
+		logger.Info(t.server.ServeTLS(ll, certFile, keyFile))
+		return
+	}
 
-		handler.ServeHTTP(w, r)
-	})
+	logger.Info(t.server.Serve(ll))
 }
 
 // Stop notifies all goroutines to stop without blocking.
@@ -0,0 +1,257 @@
+package rpc
+
+import (
+	"context"
+	"encoding/hex"
+	"math/rand"
+	"net/http"
+	"net/rpc"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+)
+
+// The max number of the latest latencies kept for each method to compute the percentiles
+const rpcLatencySamples = 1024
+
+const requestIDHeader = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+var rpcCallStats = newRPCStats()
+
+// ------------------------------- Stats -----------------------------------
+
+type rpcStats struct {
+	mu      sync.Mutex
+	methods map[string]*rpcMethodStats
+	since   time.Time
+}
+
+type rpcMethodStats struct {
+	calls     uint64
+	errors    uint64
+	total     time.Duration
+	max       time.Duration
+	latencies []time.Duration // ring buffer of the latest latencies
+	next      int
+}
+
+func newRPCStats() *rpcStats {
+	return &rpcStats{
+		methods: make(map[string]*rpcMethodStats),
+		since:   time.Now(),
+	}
+}
+
+func (s *rpcStats) record(method string, latency time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ms, ok := s.methods[method]
+	if !ok {
+		ms = &rpcMethodStats{}
+		s.methods[method] = ms
+	}
+	ms.calls++
+	if failed {
+		ms.errors++
+	}
+	ms.total += latency
+	if latency > ms.max {
+		ms.max = latency
+	}
+	if len(ms.latencies) < rpcLatencySamples {
+		ms.latencies = append(ms.latencies, latency)
+	} else {
+		ms.latencies[ms.next] = latency
+		ms.next = (ms.next + 1) % rpcLatencySamples
+	}
+}
+
+func (s *rpcStats) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.methods = make(map[string]*rpcMethodStats)
+	s.since = time.Now()
+}
+
+// summary returns the stats of the methods, the most called first
+func (s *rpcStats) summary() (time.Time, []RPCMethodStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := []RPCMethodStats{}
+	for method, ms := range s.methods {
+		sorted := make([]time.Duration, len(ms.latencies))
+		copy(sorted, ms.latencies)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		ret = append(ret, RPCMethodStats{
+			Method:    method,
+			Calls:     common.JSONUint64(ms.calls),
+			Errors:    common.JSONUint64(ms.errors),
+			AverageMs: toMillis(ms.total / time.Duration(ms.calls)),
+			P50Ms:     toMillis(percentile(sorted, 50)),
+			P90Ms:     toMillis(percentile(sorted, 90)),
+			P99Ms:     toMillis(percentile(sorted, 99)),
+			MaxMs:     toMillis(ms.max),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Calls != ret[j].Calls {
+			return ret[i].Calls > ret[j].Calls
+		}
+		return ret[i].Method < ret[j].Method
+	})
+	return s.since, ret
+}
+
+// percentile returns the p-th percentile of the sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := (len(sorted)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// ------------------------------- Codec -----------------------------------
+
+type pendingRPCCall struct {
+	method string
+	start  time.Time
+}
+
+// statsServerCodec measures the latency of each call served through the codec, records it in
+// the stats, and logs the calls slower than the threshold
+type statsServerCodec struct {
+	rpc.ServerCodec
+
+	requestID string
+	mu        sync.Mutex
+	pending   map[uint64]pendingRPCCall
+}
+
+func newStatsServerCodec(codec rpc.ServerCodec, requestID string) rpc.ServerCodec {
+	return &statsServerCodec{
+		ServerCodec: codec,
+		requestID:   requestID,
+		pending:     make(map[uint64]pendingRPCCall),
+	}
+}
+
+func (c *statsServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	if err == nil {
+		c.mu.Lock()
+		c.pending[r.Seq] = pendingRPCCall{method: r.ServiceMethod, start: time.Now()}
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *statsServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.mu.Lock()
+	call, ok := c.pending[r.Seq]
+	delete(c.pending, r.Seq)
+	c.mu.Unlock()
+
+	if ok {
+		latency := time.Since(call.start)
+		rpcCallStats.record(call.method, latency, r.Error != "")
+
+		threshold := time.Duration(common.GetReloadableInt(common.CfgRPCSlowCallThresholdMs)) * time.Millisecond
+		if threshold > 0 && latency >= threshold {
+			logger.WithFields(log.Fields{
+				"requestID": c.requestID,
+				"method":    call.method,
+				"latency":   latency,
+				"error":     r.Error,
+			}).Warn("Slow RPC call")
+		}
+	}
+
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+// wrapHTTPServerCodec instruments the codec serving a HTTP request
+func wrapHTTPServerCodec(req *http.Request, codec rpc.ServerCodec) rpc.ServerCodec {
+	requestID, _ := req.Context().Value(requestIDContextKey{}).(string)
+	return newStatsServerCodec(codec, requestID)
+}
+
+// requestIDMiddleware assigns an ID to each request if enabled by the config. The ID provided by the
+// client in the X-Request-ID header is kept. The ID is returned in the same header, and is logged
+// with the slow calls.
+func requestIDMiddleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !viper.GetBool(common.CfgRPCRequestIDEnabled) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, requestID)))
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// ------------------------------- GetRPCStats -----------------------------------
+
+type GetRPCStatsArgs struct {
+	Reset bool `json:"reset"` // reset the stats after returning them
+}
+
+type GetRPCStatsResult struct {
+	Since   string           `json:"since"`
+	Methods []RPCMethodStats `json:"methods"`
+}
+
+type RPCMethodStats struct {
+	Method    string            `json:"method"`
+	Calls     common.JSONUint64 `json:"calls"`
+	Errors    common.JSONUint64 `json:"errors"`
+	AverageMs float64           `json:"average_ms"`
+	P50Ms     float64           `json:"p50_ms"`
+	P90Ms     float64           `json:"p90_ms"`
+	P99Ms     float64           `json:"p99_ms"`
+	MaxMs     float64           `json:"max_ms"`
+}
+
+// GetRPCStats returns the call counts and the latency percentiles of the RPC methods. The
+// percentiles are computed over the latest calls of each method.
+func (t *ThetaRPCService) GetRPCStats(args *GetRPCStatsArgs, result *GetRPCStatsResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+
+	since, methods := rpcCallStats.summary()
+	result.Since = since.UTC().Format(time.RFC3339)
+	result.Methods = methods
+	if args.Reset {
+		rpcCallStats.reset()
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+)
+
+func TestRPCStats(t *testing.T) {
+	assert := assert.New(t)
+
+	stats := newRPCStats()
+	for i := 1; i <= 100; i++ {
+		stats.record("theta.GetStatus", time.Duration(i)*time.Millisecond, false)
+	}
+	stats.record("theta.GetAccount", 5*time.Millisecond, true)
+
+	_, methods := stats.summary()
+	assert.Equal(2, len(methods))
+	assert.Equal("theta.GetStatus", methods[0].Method)
+	assert.Equal(common.JSONUint64(100), methods[0].Calls)
+	assert.Equal(common.JSONUint64(0), methods[0].Errors)
+	assert.Equal(float64(50), methods[0].P50Ms)
+	assert.Equal(float64(90), methods[0].P90Ms)
+	assert.Equal(float64(99), methods[0].P99Ms)
+	assert.Equal(float64(100), methods[0].MaxMs)
+	assert.Equal(common.JSONUint64(1), methods[1].Errors)
+
+	stats.reset()
+	_, methods = stats.summary()
+	assert.Equal(0, len(methods))
+}
+
+func TestGetRPCStatsAdminOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	service := &ThetaRPCService{}
+	assert.Equal(errAdminRPCDisabled, service.GetRPCStats(&GetRPCStatsArgs{}, &GetRPCStatsResult{}))
+
+	viper.Set(common.CfgRPCAdminEnabled, true)
+	defer viper.Set(common.CfgRPCAdminEnabled, false)
+	assert.Nil(service.GetRPCStats(&GetRPCStatsArgs{}, &GetRPCStatsResult{}))
+}
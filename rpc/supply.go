@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger"
+	"github.com/thetatoken/theta/ledger/state"
+)
+
+// The number of blocks the calculated supply is reused for, since the calculation traverses the
+// whole ledger state
+const supplyCacheBlocks = 100
+
+// supplyCache keeps the latest supply calculated from the finalized state
+type supplyCache struct {
+	mu        sync.Mutex
+	height    uint64
+	stateHash common.Hash
+	supply    *ledger.Supply
+}
+
+// ------------------------------- GetTotalSupply -----------------------------------
+
+type GetTotalSupplyArgs struct {
+}
+
+type GetTotalSupplyResult struct {
+	Height   common.JSONUint64 `json:"height"`
+	ThetaWei *common.JSONBig   `json:"thetawei"`
+	TFuelWei *common.JSONBig   `json:"tfuelwei"`
+}
+
+// GetTotalSupply returns the Theta and TFuel supply calculated from the finalized state, which
+// includes the minted rewards and excludes the burned fees. The result is refreshed every
+// supplyCacheBlocks blocks.
+func (t *ThetaRPCService) GetTotalSupply(args *GetTotalSupplyArgs, result *GetTotalSupplyResult) (err error) {
+	height, supply, _, err := t.getSupply()
+	if err != nil {
+		return err
+	}
+
+	result.Height = common.JSONUint64(height)
+	result.ThetaWei = (*common.JSONBig)(supply.ThetaWei)
+	result.TFuelWei = (*common.JSONBig)(supply.TFuelWei)
+	return nil
+}
+
+// ------------------------------- GetCirculatingSupply -----------------------------------
+
+type GetCirculatingSupplyArgs struct {
+}
+
+type GetCirculatingSupplyResult struct {
+	Height   common.JSONUint64 `json:"height"`
+	ThetaWei *common.JSONBig   `json:"thetawei"`
+	TFuelWei *common.JSONBig   `json:"tfuelwei"`
+}
+
+// GetCirculatingSupply returns the total supply less the balances of the locked addresses set by
+// the rpc.supplyLockedAddresses config
+func (t *ThetaRPCService) GetCirculatingSupply(args *GetCirculatingSupplyArgs, result *GetCirculatingSupplyResult) (err error) {
+	lockedAddresses := []common.Address{}
+	for _, addressStr := range common.GetReloadableStringSlice(common.CfgRPCSupplyLockedAddresses) {
+		address, err := parseAddress(addressStr)
+		if err != nil {
+			return fmt.Errorf("Invalid locked address in the config: %v", err)
+		}
+		lockedAddresses = append(lockedAddresses, address)
+	}
+
+	height, supply, view, err := t.getSupply()
+	if err != nil {
+		return err
+	}
+
+	thetaWei := new(big.Int).Set(supply.ThetaWei)
+	tfuelWei := new(big.Int).Set(supply.TFuelWei)
+	for _, address := range lockedAddresses {
+		account := view.GetAccount(address)
+		if account == nil {
+			continue
+		}
+		balance := account.Balance.NoNil()
+		thetaWei.Sub(thetaWei, balance.ThetaWei)
+		tfuelWei.Sub(tfuelWei, balance.TFuelWei)
+	}
+
+	result.Height = common.JSONUint64(height)
+	result.ThetaWei = (*common.JSONBig)(thetaWei)
+	result.TFuelWei = (*common.JSONBig)(tfuelWei)
+	return nil
+}
+
+// getSupply returns the cached supply, and recalculates it if the cache is stale. It also returns
+// the finalized state at the height of the supply.
+func (t *ThetaRPCService) getSupply() (uint64, *ledger.Supply, *state.StoreView, error) {
+	t.supplyCache.mu.Lock()
+	defer t.supplyCache.mu.Unlock()
+
+	view, err := t.ledger.GetFinalizedSnapshot()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	cache := &t.supplyCache
+	if cache.supply != nil && view.Height() < cache.height+supplyCacheBlocks {
+		cachedView := state.NewStoreView(cache.height, cache.stateHash, view.GetDB())
+		if cachedView != nil { // might have been pruned
+			return cache.height, cache.supply, cachedView, nil
+		}
+	}
+
+	supply, err := ledger.CalculateSupply(view)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	cache.height = view.Height()
+	cache.stateHash = view.Hash()
+	cache.supply = supply
+	return cache.height, cache.supply, view, nil
+}
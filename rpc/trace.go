@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/vm"
+)
+
+// ------------------------------- TraceTransaction -----------------------------------
+
+type TraceTransactionArgs struct {
+	Hash           string `json:"hash"`
+	DisableMemory  bool   `json:"disable_memory"`
+	DisableStack   bool   `json:"disable_stack"`
+	DisableStorage bool   `json:"disable_storage"`
+	Limit          int    `json:"limit"` // max number of struct logs, zero means unlimited
+}
+
+type TraceTransactionResult struct {
+	TxHash          common.Hash         `json:"hash"`
+	BlockHash       common.Hash         `json:"block_hash"`
+	BlockHeight     common.JSONUint64   `json:"block_height"`
+	VmReturn        string              `json:"vm_return"`
+	ContractAddress common.Address      `json:"contract_address"`
+	GasUsed         common.JSONUint64   `json:"gas_used"`
+	VmError         string              `json:"vm_error"`
+	StructLogs      []vm.StructLog      `json:"struct_logs"`
+	InternalCalls   []TraceInternalCall `json:"internal_calls"`
+}
+
+type TraceInternalCall struct {
+	Type  string          `json:"type"`
+	From  common.Address  `json:"from"`
+	To    common.Address  `json:"to"`
+	Value *common.JSONBig `json:"value"`
+	Gas   *common.JSONBig `json:"gas,omitempty"`
+	Depth int             `json:"depth"`
+}
+
+// TraceTransaction re-executes a finalized smart contract transaction, and returns the trace of
+// the execution, including the opcodes, gas, stack, memory, and the internal calls.
+func (t *ThetaRPCService) TraceTransaction(args *TraceTransactionArgs, result *TraceTransactionResult) (err error) {
+	if args.Hash == "" {
+		return errors.New("Transanction hash must be specified")
+	}
+	hash := common.HexToHash(args.Hash)
+
+	raw, block, found := t.chain.FindTxByHash(hash)
+	if !found {
+		return fmt.Errorf("Transaction %v not found", args.Hash)
+	}
+	if !block.Status.IsFinalized() {
+		return fmt.Errorf("Transaction %v is not finalized yet", args.Hash)
+	}
+
+	txIndex := -1
+	for idx, rawTx := range block.Txs {
+		if bytes.Equal(rawTx, raw) {
+			txIndex = idx
+			break
+		}
+	}
+	if txIndex < 0 {
+		return fmt.Errorf("Transaction %v not found in block %v", args.Hash, block.Hash().Hex())
+	}
+
+	tracer := vm.NewTxTracer(&vm.LogConfig{
+		DisableMemory:  args.DisableMemory,
+		DisableStack:   args.DisableStack,
+		DisableStorage: args.DisableStorage,
+		Limit:          args.Limit,
+	})
+	vmRet, contractAddr, gasUsed, vmErr, err := t.ledger.TraceSmartContractTx(block.Block, txIndex, tracer)
+	if err != nil {
+		return err
+	}
+
+	result.TxHash = crypto.Keccak256Hash(raw)
+	result.BlockHash = block.Hash()
+	result.BlockHeight = common.JSONUint64(block.Height)
+	result.VmReturn = hex.EncodeToString(vmRet)
+	result.ContractAddress = contractAddr
+	result.GasUsed = common.JSONUint64(gasUsed)
+	if vmErr != nil {
+		result.VmError = vmErr.Error()
+	}
+	result.StructLogs = tracer.StructLogs()
+	result.InternalCalls = []TraceInternalCall{}
+	for _, call := range tracer.InternalCalls() {
+		tc := TraceInternalCall{
+			Type:  call.Type.String(),
+			From:  call.From,
+			To:    call.To,
+			Value: (*common.JSONBig)(call.Value),
+			Gas:   (*common.JSONBig)(call.Gas),
+			Depth: call.Depth,
+		}
+		result.InternalCalls = append(result.InternalCalls, tc)
+	}
+
+	return nil
+}
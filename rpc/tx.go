@@ -11,14 +11,19 @@ import (
 	"github.com/thetatoken/theta/cmd/thetacli/cmd/utils"
 	"github.com/thetatoken/theta/common"
 	"github.com/thetatoken/theta/common/hexutil"
+	txresult "github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/core"
 	"github.com/thetatoken/theta/crypto"
 	"github.com/thetatoken/theta/ledger/types"
 	"github.com/thetatoken/theta/mempool"
+	"github.com/thetatoken/theta/rpc/lib/rpc-codec/jsonrpc2"
 )
 
 const txTimeout = 60 * time.Second
 
+// rpcServerErrorCode is the JSON-RPC error code of the errors returned by the RPC methods
+const rpcServerErrorCode = -32000
+
 type Callback struct {
 	txHash   string
 	created  time.Time
@@ -112,6 +117,10 @@ func (t *ThetaRPCService) txCallback() {
 				}
 			}
 
+			if t.watched != nil {
+				t.watched.ProcessBlock(block)
+			}
+
 			logger.Infof("Done processing finalized block, height=%v", block.Height)
 		case <-timer.C:
 			logger.Debugf("txCallbackManager.Trim()")
@@ -152,7 +161,7 @@ func (t *ThetaRPCService) BroadcastRawTransaction(
 		logger.Infof("Broadcasted raw transaction (sync): %v, hash: %v", hex.EncodeToString(txBytes), hash.Hex())
 	} else {
 		logger.Warnf("Failed to broadcast raw transaction (sync): %v, hash: %v, err: %v", hex.EncodeToString(txBytes), hash.Hex(), err)
-		return err
+		return txError(err)
 	}
 
 	finalized := make(chan *core.Block)
@@ -210,7 +219,7 @@ func (t *ThetaRPCService) BroadcastRawTransactionAsync(
 
 	logger.Warnf("Failed to broadcast raw transaction (async): %v, hash: %v, err: %v", hex.EncodeToString(txBytes), hash.Hex(), err)
 
-	return err
+	return txError(err)
 }
 
 // ------------------------------- BroadcastRawEthTransaction -----------------------------------
@@ -265,6 +274,69 @@ func (t *ThetaRPCService) BroadcastRawEthTransactionAsync(
 	return err
 }
 
+// ------------------------------- SimulateTransaction -----------------------------------
+
+type SimulateTransactionArgs struct {
+	TxBytes string `json:"tx_bytes"`
+}
+
+type BalanceChange struct {
+	Address common.Address `json:"address"`
+	Before  types.Coins    `json:"before"`
+	After   types.Coins    `json:"after"`
+}
+
+type SimulateTransactionResult struct {
+	TxHash          string            `json:"hash"`
+	Success         bool              `json:"success"`
+	Error           string            `json:"error"`
+	ErrorCode       int               `json:"error_code"`
+	ErrorName       string            `json:"error_name"`
+	Fee             types.Coins       `json:"fee"`
+	GasUsed         common.JSONUint64 `json:"gas_used"`
+	ContractAddress common.Address    `json:"contract_address"`
+	VmReturn        string            `json:"vm_return"`
+	VmError         string            `json:"vm_error"`
+	BalanceChanges  []BalanceChange   `json:"balance_changes"`
+}
+
+// SimulateTransaction checks and executes the transaction against a copy of the latest state without
+// broadcasting it, and returns the would-be balance changes and fee, or the reason the transaction
+// would fail, e.g. insufficient stake or wrong sequence.
+func (t *ThetaRPCService) SimulateTransaction(
+	args *SimulateTransactionArgs, result *SimulateTransactionResult) (err error) {
+	txBytes, err := decodeTxHexBytes(args.TxBytes)
+	if err != nil {
+		return err
+	}
+
+	result.TxHash = crypto.Keccak256Hash(txBytes).Hex()
+	result.BalanceChanges = []BalanceChange{}
+
+	simulation, res := t.ledger.SimulateTx(txBytes)
+	if res.IsError() {
+		result.Error = res.Message
+		result.ErrorCode = int(res.Code)
+		result.ErrorName = res.Code.String()
+		return nil
+	}
+
+	result.Success = true
+	result.Fee = simulation.Fee
+	result.GasUsed = common.JSONUint64(simulation.GasUsed)
+	result.ContractAddress = simulation.ContractAddress
+	result.VmReturn = hex.EncodeToString(simulation.EvmRet)
+	result.VmError = simulation.EvmErr
+	for _, change := range simulation.BalanceChanges {
+		result.BalanceChanges = append(result.BalanceChanges, BalanceChange{
+			Address: change.Address,
+			Before:  change.Before,
+			After:   change.After,
+		})
+	}
+	return nil
+}
+
 func translateEthTx(ethTxStr string) (string, error) {
 	thetaSmartContractTx, err := types.TranslateEthTx(ethTxStr)
 	if err != nil {
@@ -285,6 +357,27 @@ func translateEthTx(ethTxStr string) (string, error) {
 
 // -------------------------- Utilities -------------------------- //
 
+// TxErrorData is attached to the JSON-RPC error of a rejected transaction. The clients can
+// branch on the error code or name, which are stable, instead of the message.
+type TxErrorData struct {
+	ErrorCode int    `json:"error_code"`
+	ErrorName string `json:"error_name"`
+}
+
+// txError converts the error of a rejected transaction to a JSON-RPC error carrying the error code
+func txError(err error) error {
+	code := txresult.ErrorCodeOf(err)
+	if err == mempool.DuplicateTxError {
+		code = txresult.CodeDuplicateTx
+	}
+	rpcErr := jsonrpc2.NewError(rpcServerErrorCode, err.Error())
+	rpcErr.Data = TxErrorData{
+		ErrorCode: int(code),
+		ErrorName: code.String(),
+	}
+	return rpcErr
+}
+
 func decodeTxHexBytes(txBytes string) ([]byte, error) {
 	if hexutil.Has0xPrefix(txBytes) {
 		txBytes = txBytes[2:]
@@ -1,12 +1,16 @@
 package rpc
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/common/result"
 	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/mempool"
+	"github.com/thetatoken/theta/rpc/lib/rpc-codec/jsonrpc2"
 )
 
 func TestTxCallbackManager(t *testing.T) {
@@ -55,3 +59,20 @@ func TestTxCallbackManager(t *testing.T) {
 	assert.Equal(1, len(m.txHashToCallback))
 	assert.Equal(1, len(m.callbacks))
 }
+
+func TestTxError(t *testing.T) {
+	assert := assert.New(t)
+
+	res := result.Error("Insufficient fund").WithErrorCode(result.CodeInsufficientFund)
+	err, ok := txError(res.Err()).(*jsonrpc2.Error)
+	assert.True(ok)
+	assert.Equal(rpcServerErrorCode, err.Code)
+	assert.Equal("Insufficient fund", err.Message)
+	assert.Equal(TxErrorData{ErrorCode: 100003, ErrorName: "InsufficientFund"}, err.Data)
+
+	err = txError(mempool.DuplicateTxError).(*jsonrpc2.Error)
+	assert.Equal(TxErrorData{ErrorCode: 100014, ErrorName: "DuplicateTx"}, err.Data)
+
+	err = txError(errors.New("other error")).(*jsonrpc2.Error)
+	assert.Equal(TxErrorData{ErrorCode: 100000, ErrorName: "GenericError"}, err.Data)
+}
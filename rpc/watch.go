@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/websocket"
+
+	"github.com/thetatoken/theta/common"
+)
+
+var errWatchDisabled = errors.New("watched addresses are not available")
+
+// ------------------------------- AddWatchedAddresses -----------------------------------
+
+type AddWatchedAddressesArgs struct {
+	Addresses []string `json:"addresses"`
+}
+
+type AddWatchedAddressesResult struct {
+	NumAdded common.JSONUint64 `json:"num_added"`
+	NumTotal common.JSONUint64 `json:"num_total"`
+}
+
+// AddWatchedAddresses registers the addresses to watch, whose finalized transactions are sent to
+// the /ws/watch subscribers and the webhooks. The addresses are persisted across restarts.
+func (t *ThetaRPCService) AddWatchedAddresses(args *AddWatchedAddressesArgs, result *AddWatchedAddressesResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if t.watched == nil {
+		return errWatchDisabled
+	}
+	addresses, err := parseAddresses(args.Addresses)
+	if err != nil {
+		return err
+	}
+
+	added, err := t.watched.Add(addresses)
+	if err != nil {
+		return err
+	}
+	result.NumAdded = common.JSONUint64(added)
+	result.NumTotal = common.JSONUint64(len(t.watched.Addresses()))
+	return nil
+}
+
+// ------------------------------- RemoveWatchedAddresses -----------------------------------
+
+type RemoveWatchedAddressesArgs struct {
+	Addresses []string `json:"addresses"`
+}
+
+type RemoveWatchedAddressesResult struct {
+	NumRemoved common.JSONUint64 `json:"num_removed"`
+	NumTotal   common.JSONUint64 `json:"num_total"`
+}
+
+// RemoveWatchedAddresses unregisters the addresses added by AddWatchedAddresses. The addresses
+// set by the watch.addresses config cannot be removed.
+func (t *ThetaRPCService) RemoveWatchedAddresses(args *RemoveWatchedAddressesArgs, result *RemoveWatchedAddressesResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if t.watched == nil {
+		return errWatchDisabled
+	}
+	addresses, err := parseAddresses(args.Addresses)
+	if err != nil {
+		return err
+	}
+
+	removed, err := t.watched.Remove(addresses)
+	if err != nil {
+		return err
+	}
+	result.NumRemoved = common.JSONUint64(removed)
+	result.NumTotal = common.JSONUint64(len(t.watched.Addresses()))
+	return nil
+}
+
+// ------------------------------- GetWatchedAddresses -----------------------------------
+
+type GetWatchedAddressesArgs struct {
+}
+
+type GetWatchedAddressesResult struct {
+	Addresses []common.Address `json:"addresses"`
+}
+
+func (t *ThetaRPCService) GetWatchedAddresses(args *GetWatchedAddressesArgs, result *GetWatchedAddressesResult) (err error) {
+	if err = checkAdminEnabled(); err != nil {
+		return err
+	}
+	if t.watched == nil {
+		return errWatchDisabled
+	}
+	result.Addresses = t.watched.Addresses()
+	return nil
+}
+
+// ------------------------------- /ws/watch -----------------------------------
+
+// serveWatchedTxs streams the finalized transactions touching the watched addresses to the
+// WebSocket client as JSON messages, until the client disconnects or falls behind
+func (t *ThetaRPCService) serveWatchedTxs(ws *websocket.Conn) {
+	defer ws.Close()
+	if t.watched == nil {
+		return
+	}
+
+	sub := t.watched.Subscribe(viper.GetInt(common.CfgWatchSubscriptionBuffer))
+	defer sub.Unsubscribe()
+
+	// The client is not expected to send anything, reading only detects the disconnection
+	closed := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, ws)
+		close(closed)
+	}()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-closed:
+			return
+		case match, ok := <-sub.Matches():
+			if !ok {
+				logger.Warnf("Watched address subscriber %v fell behind, disconnecting", ws.Request().RemoteAddr)
+				return
+			}
+			if err := websocket.JSON.Send(ws, match); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func parseAddresses(addressStrs []string) ([]common.Address, error) {
+	if len(addressStrs) == 0 {
+		return nil, errors.New("addresses must be specified")
+	}
+	addresses := []common.Address{}
+	for _, addressStr := range addressStrs {
+		address, err := parseAddress(addressStr)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
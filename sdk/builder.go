@@ -0,0 +1,124 @@
+package sdk
+
+import (
+	"math/big"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// NewSendTx creates a transaction sending the coins from one address to another. The fee is
+// paid by the sender on top of the coins.
+func NewSendTx(from, to common.Address, coins types.Coins, fee types.Coins, sequence uint64) *types.SendTx {
+	coins = coins.NoNil()
+	return &types.SendTx{
+		Fee: fee.NoNil(),
+		Inputs: []types.TxInput{{
+			Address:  from,
+			Coins:    coins.Plus(fee),
+			Sequence: sequence,
+		}},
+		Outputs: []types.TxOutput{{
+			Address: to,
+			Coins:   coins,
+		}},
+	}
+}
+
+// copyOrZero returns a copy of the amount, treating nil as zero the same as Coins.NoNil
+func copyOrZero(amount *big.Int) *big.Int {
+	if amount == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(amount)
+}
+
+// NewDepositStakeTx creates a transaction depositing the stake to a validator or a guardian holder.
+// The holders that register their BLS keys, e.g. the guardians, are not supported by this function,
+// since their summaries are produced by the node.
+func NewDepositStakeTx(source, holder common.Address, thetaWei *big.Int, purpose uint8, fee types.Coins, sequence uint64) *types.DepositStakeTxV2 {
+	stake := types.Coins{
+		ThetaWei: copyOrZero(thetaWei),
+		TFuelWei: big.NewInt(0),
+	}
+	return &types.DepositStakeTxV2{
+		Fee: fee.NoNil(),
+		Source: types.TxInput{
+			Address:  source,
+			Coins:    stake,
+			Sequence: sequence,
+		},
+		Holder: types.TxOutput{
+			Address: holder,
+		},
+		Purpose: purpose,
+	}
+}
+
+// NewWithdrawStakeTx creates a transaction withdrawing all the stake deposited by the source to the holder
+func NewWithdrawStakeTx(source, holder common.Address, purpose uint8, fee types.Coins, sequence uint64) *types.WithdrawStakeTx {
+	return &types.WithdrawStakeTx{
+		Fee: fee.NoNil(),
+		Source: types.TxInput{
+			Address:  source,
+			Sequence: sequence,
+		},
+		Holder: types.TxOutput{
+			Address: holder,
+		},
+		Purpose: purpose,
+	}
+}
+
+// NewSmartContractTx creates a transaction calling the contract at the to address, or deploying
+// the contract in the data if the to address is empty
+func NewSmartContractTx(from, to common.Address, value types.Coins, gasLimit uint64, gasPrice *big.Int, data []byte, sequence uint64) *types.SmartContractTx {
+	return &types.SmartContractTx{
+		From: types.TxInput{
+			Address:  from,
+			Coins:    value.NoNil(),
+			Sequence: sequence,
+		},
+		To: types.TxOutput{
+			Address: to,
+		},
+		GasLimit: gasLimit,
+		GasPrice: copyOrZero(gasPrice),
+		Data:     data,
+	}
+}
+
+// NewVestingTx creates a transaction sending the coins to the beneficiary, where the coins are
+// locked by the vesting schedule
+func NewVestingTx(from, beneficiary common.Address, schedule types.VestingSchedule, fee types.Coins, sequence uint64) *types.VestingTx {
+	coins := schedule.Coins.NoNil()
+	return &types.VestingTx{
+		Fee: fee.NoNil(),
+		Source: types.TxInput{
+			Address:  from,
+			Coins:    coins,
+			Sequence: sequence,
+		},
+		Beneficiary: types.TxOutput{
+			Address: beneficiary,
+			Coins:   coins,
+		},
+		StartHeight: schedule.StartHeight,
+		CliffHeight: schedule.CliffHeight,
+		EndHeight:   schedule.EndHeight,
+	}
+}
+
+// NewRotateValidatorKeyTx creates a transaction switching the key the validator stake holder signs the
+// votes and proposals with. The new key has to sign the tx with SignSigningKey before the holder signs it.
+func NewRotateValidatorKeyTx(holder, signingAddress common.Address, activationHeight uint64, fee types.Coins, sequence uint64) *types.RotateValidatorKeyTx {
+	return &types.RotateValidatorKeyTx{
+		Fee: fee.NoNil(),
+		Holder: types.TxInput{
+			Address:  holder,
+			Sequence: sequence,
+		},
+		SigningAddress:   signingAddress,
+		ActivationHeight: activationHeight,
+	}
+}
@@ -0,0 +1,113 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rpc"
+
+	rpcc "github.com/ybbus/jsonrpc"
+)
+
+// Client submits the transactions to, and queries the states from a Theta node via its RPC endpoint
+type Client struct {
+	rpcClient rpcc.RPCClient
+}
+
+// NewClient creates a client of the RPC endpoint, e.g. http://localhost:16888/rpc
+func NewClient(endpoint string) *Client {
+	return &Client{
+		rpcClient: rpcc.NewRPCClient(endpoint),
+	}
+}
+
+func (c *Client) call(method string, args interface{}, result interface{}) error {
+	res, err := c.rpcClient.Call(method, args)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return fmt.Errorf("Server returned error: %v", res.Error)
+	}
+	if err := res.GetObject(result); err != nil {
+		return fmt.Errorf("Failed to parse server response: %v", err)
+	}
+	return nil
+}
+
+// GetStatus returns the status of the node, e.g. the chain ID and the latest finalized block height
+func (c *Client) GetStatus() (*rpc.GetStatusResult, error) {
+	result := &rpc.GetStatusResult{}
+	if err := c.call("theta.GetStatus", rpc.GetStatusArgs{}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAccount returns the account at the latest finalized block. If preview is true, the account
+// reflects the transactions accepted by the mempool of the node as well.
+func (c *Client) GetAccount(address common.Address, preview bool) (*types.Account, error) {
+	result := &rpc.GetAccountResult{}
+	err := c.call("theta.GetAccount", rpc.GetAccountArgs{
+		Address: address.Hex(),
+		Preview: preview,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Account == nil {
+		return nil, fmt.Errorf("Account with address %s is not found", address.Hex())
+	}
+	return result.Account, nil
+}
+
+// GetNextSequence returns the sequence for the next transaction sent by the address, taking
+// the transactions accepted by the mempool of the node into account
+func (c *Client) GetNextSequence(address common.Address) (uint64, error) {
+	account, err := c.GetAccount(address, true)
+	if err != nil {
+		return 0, err
+	}
+	return account.Sequence + 1, nil
+}
+
+// BroadcastTx submits the signed transaction. Unless async is true, it waits until the transaction
+// is included in a block. The returned hash identifies the transaction in the GetTransaction RPC.
+func (c *Client) BroadcastTx(tx types.Tx, async bool) (*rpc.BroadcastRawTransactionResult, error) {
+	signedTx, err := EncodeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rpc.BroadcastRawTransactionResult{}
+	if async {
+		asyncResult := &rpc.BroadcastRawTransactionAsyncResult{}
+		err = c.call("theta.BroadcastRawTransactionAsync", rpc.BroadcastRawTransactionAsyncArgs{TxBytes: signedTx}, asyncResult)
+		result.TxHash = asyncResult.TxHash
+	} else {
+		err = c.call("theta.BroadcastRawTransaction", rpc.BroadcastRawTransactionArgs{TxBytes: signedTx}, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TxStatus is the status of a transaction, i.e. "not_found", "pending", "finalized" or "abandoned",
+// and the block including the transaction if any
+type TxStatus struct {
+	TxHash      common.Hash       `json:"hash"`
+	Status      string            `json:"status"`
+	BlockHash   common.Hash       `json:"block_hash"`
+	BlockHeight common.JSONUint64 `json:"block_height"`
+}
+
+// GetTxStatus returns the status of the transaction with the given hash
+func (c *Client) GetTxStatus(txHash string) (*TxStatus, error) {
+	result := &TxStatus{}
+	if err := c.call("theta.GetTransaction", rpc.GetTransactionArgs{Hash: txHash}, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
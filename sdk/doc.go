@@ -0,0 +1,16 @@
+/*
+Package sdk builds, signs and submits the Theta transactions.
+
+The package is the supported way to integrate with a Theta node from Go. Its exported API is kept
+backward compatible across releases, unlike the internal packages used by the node and thetacli.
+
+A typical flow is to query the next sequence of the sender, build the transaction, sign it, and
+broadcast it:
+
+	client := sdk.NewClient("http://localhost:16888/rpc")
+	seq, err := client.GetNextSequence(from)
+	tx := sdk.NewSendTx(from, to, types.NewCoins(10, 0), sdk.DefaultFee(), seq)
+	err = sdk.SignTx(chainID, tx, sdk.NewPrivateKeySigner(privKey))
+	result, err := client.BroadcastTx(tx, false)
+*/
+package sdk
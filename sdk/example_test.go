@@ -0,0 +1,57 @@
+package sdk_test
+
+import (
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/sdk"
+)
+
+// Send 10 Theta to another address
+func Example() {
+	privKey, _, _ := crypto.GenerateKeyPair()
+	signer := sdk.NewPrivateKeySigner(privKey)
+	to := common.HexToAddress("0x9F1233798E905E173560071255140b4A8aBd3Ec6")
+	client := sdk.NewClient("http://localhost:16888/rpc")
+
+	seq, err := client.GetNextSequence(signer.Address())
+	if err != nil {
+		fmt.Printf("Failed to get the sequence: %v\n", err)
+		return
+	}
+	tx := sdk.NewSendTx(signer.Address(), to, types.NewCoins(10, 0), sdk.DefaultFee(), seq)
+	if err := sdk.SignTx("privatenet", tx, signer); err != nil {
+		fmt.Printf("Failed to sign the transaction: %v\n", err)
+		return
+	}
+	result, err := client.BroadcastTx(tx, false)
+	if err != nil {
+		fmt.Printf("Failed to broadcast the transaction: %v\n", err)
+		return
+	}
+	fmt.Printf("Transaction %v included in block %v\n", result.TxHash, result.Block.Height)
+}
+
+// Lock 1000 Theta in the account of the beneficiary, which unlock linearly over 100000 blocks after a cliff
+func ExampleNewVestingTx() {
+	privKey, _, _ := crypto.GenerateKeyPair()
+	signer := sdk.NewPrivateKeySigner(privKey)
+	beneficiary := common.HexToAddress("0x9F1233798E905E173560071255140b4A8aBd3Ec6")
+
+	schedule := types.VestingSchedule{
+		Coins:       types.NewCoins(1000, 0),
+		StartHeight: 1000,
+		CliffHeight: 10000,
+		EndHeight:   101000,
+	}
+	tx := sdk.NewVestingTx(signer.Address(), beneficiary, schedule, sdk.DefaultFee(), 1)
+	if err := sdk.SignTx("privatenet", tx, signer); err != nil {
+		fmt.Printf("Failed to sign the transaction: %v\n", err)
+		return
+	}
+	encoded, _ := sdk.EncodeTx(tx)
+	fmt.Println(len(encoded) > 0)
+	// Output: true
+}
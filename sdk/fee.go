@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"math/big"
+
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+// DefaultFee returns the minimum fee of a regular transaction under the current fee schedule
+func DefaultFee() types.Coins {
+	return types.Coins{
+		ThetaWei: big.NewInt(0),
+		TFuelWei: new(big.Int).SetUint64(types.MinimumTransactionFeeTFuelWeiJune2021),
+	}
+}
+
+// MinimumFee returns the minimum fee of a regular transaction at the given block height
+func MinimumFee(blockHeight uint64) types.Coins {
+	return types.Coins{
+		ThetaWei: big.NewInt(0),
+		TFuelWei: types.GetMinimumTransactionFeeTFuelWei(blockHeight),
+	}
+}
+
+// MinimumSendTxFee returns the minimum fee of a SendTx at the given block height, which grows with
+// the number of the inputs and outputs
+func MinimumSendTxFee(numInputs, numOutputs int, blockHeight uint64) types.Coins {
+	return types.Coins{
+		ThetaWei: big.NewInt(0),
+		TFuelWei: types.GetSendTxMinimumTransactionFeeTFuelWei(uint64(numInputs+numOutputs), blockHeight),
+	}
+}
+
+// MinimumGasPrice returns the minimum gas price of a smart contract transaction at the given block height
+func MinimumGasPrice(blockHeight uint64) *big.Int {
+	return types.GetMinimumGasPrice(blockHeight)
+}
+
+// MaxSmartContractFee returns the max fee a smart contract transaction may be charged, i.e. the
+// gas limit times the gas price
+func MaxSmartContractFee(tx *types.SmartContractTx) *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(tx.GasLimit), tx.GasPrice)
+}
@@ -0,0 +1,82 @@
+package sdk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+func TestSignSendTx(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	signer := NewPrivateKeySigner(privKey)
+	_, toPubKey, _ := crypto.GenerateKeyPair()
+
+	tx := NewSendTx(signer.Address(), toPubKey.Address(), types.NewCoins(10, 20), DefaultFee(), 3)
+	assert.Equal(uint64(3), tx.Inputs[0].Sequence)
+	assert.True(tx.Inputs[0].Coins.IsEqual(types.NewCoins(10, 20).Plus(DefaultFee())))
+	assert.True(tx.Outputs[0].Coins.IsEqual(types.NewCoins(10, 20)))
+
+	assert.Nil(SignTx("testchain", tx, signer))
+	assert.True(tx.Inputs[0].Signature.Verify(tx.SignBytes("testchain"), signer.Address()))
+
+	encoded, err := EncodeTx(tx)
+	assert.Nil(err)
+	assert.NotEmpty(encoded)
+
+	// Cannot sign for the inputs of other addresses
+	other := NewSendTx(toPubKey.Address(), signer.Address(), types.NewCoins(1, 0), DefaultFee(), 1)
+	assert.NotNil(SignTx("testchain", other, signer))
+}
+
+func TestSignSmartContractTx(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, _ := crypto.GenerateKeyPair()
+	signer := NewPrivateKeySigner(privKey)
+	_, contractPubKey, _ := crypto.GenerateKeyPair()
+
+	gasPrice := MinimumGasPrice(1)
+	tx := NewSmartContractTx(signer.Address(), contractPubKey.Address(), types.NewCoins(0, 0), 100000, gasPrice, []byte{0x01}, 1)
+	assert.Nil(SignTx("testchain", tx, signer))
+	assert.True(tx.From.Signature.Verify(tx.SignBytes("testchain"), signer.Address()))
+	assert.Equal(new(big.Int).Mul(big.NewInt(100000), gasPrice), MaxSmartContractFee(tx))
+
+	assert.Equal(ErrUnsupportedTx, SignTx("testchain", &types.CoinbaseTx{}, signer))
+
+	// A nil gas price or stake is treated as zero
+	tx = NewSmartContractTx(signer.Address(), contractPubKey.Address(), types.Coins{}, 100000, nil, nil, 2)
+	assert.Equal(int64(0), tx.GasPrice.Int64())
+	depositTx := NewDepositStakeTx(signer.Address(), contractPubKey.Address(), nil, 0, DefaultFee(), 3)
+	assert.Equal(int64(0), depositTx.Source.Coins.ThetaWei.Int64())
+}
+
+func TestSignRotateValidatorKeyTx(t *testing.T) {
+	assert := assert.New(t)
+
+	holderKey, _, _ := crypto.GenerateKeyPair()
+	holder := NewPrivateKeySigner(holderKey)
+	signingKey, _, _ := crypto.GenerateKeyPair()
+	signing := NewPrivateKeySigner(signingKey)
+
+	tx := NewRotateValidatorKeyTx(holder.Address(), signing.Address(), 1000, DefaultFee(), 2)
+	assert.Nil(SignSigningKey("testchain", tx, signing))
+	assert.Nil(SignTx("testchain", tx, holder))
+	assert.True(tx.SigningSig.Verify(tx.SigningKeySignBytes("testchain"), signing.Address()))
+	assert.True(tx.Holder.Signature.Verify(tx.SignBytes("testchain"), holder.Address()))
+
+	// The signature of the new key does not cover the fee and the holder sequence
+	tx.Fee = types.NewCoins(0, 1)
+	tx.Holder.Sequence = 3
+	assert.True(tx.SigningSig.Verify(tx.SigningKeySignBytes("testchain"), signing.Address()))
+
+	tx.ActivationHeight = 2000
+	assert.False(tx.SigningSig.Verify(tx.SigningKeySignBytes("testchain"), signing.Address()))
+
+	// The holder cannot sign for the new key
+	assert.NotNil(SignSigningKey("testchain", tx, holder))
+}
@@ -0,0 +1,121 @@
+package sdk
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+var ErrUnsupportedTx = errors.New("Unsupported transaction type")
+
+// Signer signs the sign bytes with the private key of the address. The wallets implement this interface.
+type Signer interface {
+	Sign(address common.Address, txrlp common.Bytes) (*crypto.Signature, error)
+}
+
+// PrivateKeySigner signs with a private key held in memory
+type PrivateKeySigner struct {
+	privKey *crypto.PrivateKey
+}
+
+var _ Signer = (*PrivateKeySigner)(nil)
+
+// NewPrivateKeySigner creates a signer of the private key
+func NewPrivateKeySigner(privKey *crypto.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{
+		privKey: privKey,
+	}
+}
+
+// Address returns the address of the private key
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.privKey.PublicKey().Address()
+}
+
+// Sign signs the sign bytes if the address matches the private key
+func (s *PrivateKeySigner) Sign(address common.Address, txrlp common.Bytes) (*crypto.Signature, error) {
+	if address != s.Address() {
+		return nil, fmt.Errorf("Cannot sign for address %v", address.Hex())
+	}
+	return s.privKey.Sign(txrlp)
+}
+
+type signableTx interface {
+	types.Tx
+	SetSignature(addr common.Address, sig *crypto.Signature) bool
+}
+
+// SignTx signs the transaction for all its signers. For a SendTx with multiple inputs, the signer
+// has to be able to sign for all the input addresses.
+func SignTx(chainID string, tx types.Tx, signer Signer) error {
+	stx, ok := tx.(signableTx)
+	if !ok {
+		return ErrUnsupportedTx
+	}
+	addresses, err := signerAddresses(tx)
+	if err != nil {
+		return err
+	}
+	signBytes := stx.SignBytes(chainID)
+	for _, address := range addresses {
+		sig, err := signer.Sign(address, signBytes)
+		if err != nil {
+			return err
+		}
+		stx.SetSignature(address, sig)
+	}
+	return nil
+}
+
+func signerAddresses(tx types.Tx) ([]common.Address, error) {
+	switch tx := tx.(type) {
+	case *types.SendTx:
+		addresses := []common.Address{}
+		for _, input := range tx.Inputs {
+			addresses = append(addresses, input.Address)
+		}
+		return addresses, nil
+	case *types.ReserveFundTx:
+		return []common.Address{tx.Source.Address}, nil
+	case *types.ReleaseFundTx:
+		return []common.Address{tx.Source.Address}, nil
+	case *types.SplitRuleTx:
+		return []common.Address{tx.Initiator.Address}, nil
+	case *types.SmartContractTx:
+		return []common.Address{tx.From.Address}, nil
+	case *types.DepositStakeTxV2:
+		return []common.Address{tx.Source.Address}, nil
+	case *types.WithdrawStakeTx:
+		return []common.Address{tx.Source.Address}, nil
+	case *types.StakeRewardDistributionTx:
+		return []common.Address{tx.Holder.Address}, nil
+	case *types.VestingTx:
+		return []common.Address{tx.Source.Address}, nil
+	case *types.RotateValidatorKeyTx:
+		return []common.Address{tx.Holder.Address}, nil
+	}
+	return nil, ErrUnsupportedTx
+}
+
+// SignSigningKey signs the key rotation with the new signing key, proving its possession
+func SignSigningKey(chainID string, tx *types.RotateValidatorKeyTx, signer Signer) error {
+	sig, err := signer.Sign(tx.SigningAddress, tx.SigningKeySignBytes(chainID))
+	if err != nil {
+		return err
+	}
+	tx.SigningSig = sig
+	return nil
+}
+
+// EncodeTx encodes the signed transaction to the hex string accepted by the BroadcastRawTransaction RPC
+func EncodeTx(tx types.Tx) (string, error) {
+	raw, err := types.TxToBytes(tx)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
@@ -0,0 +1,166 @@
+package signer
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/rlp"
+)
+
+var _ core.Signer = (*RemoteSigner)(nil)
+
+// RemoteSigner implements the core.Signer interface by forwarding the signing requests
+// to a remote signer process.
+type RemoteSigner struct {
+	dial    func() (net.Conn, error)
+	timeout time.Duration
+
+	mu     *sync.Mutex
+	client *rpc.Client
+	pubKey *crypto.PublicKey
+}
+
+// NewRemoteSigner creates an instance of RemoteSigner connected to the signer at the given address.
+func NewRemoteSigner(address string, tlsConfig *tls.Config, timeout time.Duration) (*RemoteSigner, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	return newRemoteSigner(func() (net.Conn, error) {
+		return tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	}, timeout)
+}
+
+func newRemoteSigner(dial func() (net.Conn, error), timeout time.Duration) (*RemoteSigner, error) {
+	s := &RemoteSigner{
+		dial:    dial,
+		timeout: timeout,
+		mu:      &sync.Mutex{},
+	}
+	reply := &PublicKeyReply{}
+	if err := s.call("PublicKey", &PublicKeyArgs{}, reply); err != nil {
+		return nil, err
+	}
+	pubKey, err := crypto.PublicKeyFromBytes(reply.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	s.pubKey = pubKey
+	return s, nil
+}
+
+// PublicKey implements the core.Signer interface.
+func (s *RemoteSigner) PublicKey() *crypto.PublicKey {
+	return s.pubKey
+}
+
+// SignVote implements the core.Signer interface.
+func (s *RemoteSigner) SignVote(vote core.Vote) (*crypto.Signature, error) {
+	vote.Signature = nil
+	raw, err := rlp.EncodeToBytes(vote)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign("SignVote", &SignVoteArgs{Vote: raw}, vote.SignBytes())
+}
+
+// SignBlock implements the core.Signer interface.
+func (s *RemoteSigner) SignBlock(header *core.BlockHeader) (*crypto.Signature, error) {
+	raw, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return s.sign("SignBlock", &SignBlockArgs{Header: raw}, header.SignBytes())
+}
+
+// SignTx implements the core.Signer interface.
+func (s *RemoteSigner) SignTx(signBytes common.Bytes) (*crypto.Signature, error) {
+	return s.sign("SignTx", &SignTxArgs{SignBytes: signBytes}, signBytes)
+}
+
+// Close closes the connection to the remote signer.
+func (s *RemoteSigner) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeClient()
+}
+
+// sign sends the request and verifies the returned signature against the expected sign bytes.
+func (s *RemoteSigner) sign(method string, args interface{}, signBytes common.Bytes) (*crypto.Signature, error) {
+	reply := &SignReply{}
+	if err := s.call(method, args, reply); err != nil {
+		return nil, err
+	}
+	sig, err := crypto.SignatureFromBytes(reply.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(signBytes, s.pubKey.Address()) {
+		return nil, fmt.Errorf("Remote signer returned an invalid signature for %v", method)
+	}
+	return sig, nil
+}
+
+// call sends the request to the remote signer, connecting first if needed. The connection
+// is dropped on failures, and re-established by the next request.
+func (s *RemoteSigner) call(method string, args interface{}, reply interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		conn, err := s.dial()
+		if err != nil {
+			return fmt.Errorf("Failed to connect to remote signer: %v", err)
+		}
+		s.client = rpc.NewClient(conn)
+		if s.pubKey != nil {
+			if err := s.checkPublicKey(); err != nil {
+				s.closeClient()
+				return err
+			}
+		}
+	}
+
+	if err := s.callWithTimeout(method, args, reply); err != nil {
+		s.closeClient()
+		return err
+	}
+	return nil
+}
+
+// checkPublicKey makes sure the remote signer still holds the same key after reconnecting.
+func (s *RemoteSigner) checkPublicKey() error {
+	reply := &PublicKeyReply{}
+	if err := s.callWithTimeout("PublicKey", &PublicKeyArgs{}, reply); err != nil {
+		return err
+	}
+	pubKey, err := crypto.PublicKeyFromBytes(reply.PublicKey)
+	if err != nil {
+		return err
+	}
+	if pubKey.Address() != s.pubKey.Address() {
+		return fmt.Errorf("Remote signer key changed from %v to %v", s.pubKey.Address().Hex(), pubKey.Address().Hex())
+	}
+	return nil
+}
+
+func (s *RemoteSigner) callWithTimeout(method string, args interface{}, reply interface{}) error {
+	call := s.client.Go(serviceName+"."+method, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-time.After(s.timeout):
+		return fmt.Errorf("Remote signer timed out on %v", method)
+	}
+}
+
+func (s *RemoteSigner) closeClient() {
+	if s.client != nil {
+		s.client.Close()
+		s.client = nil
+	}
+}
@@ -0,0 +1,49 @@
+package signer
+
+import (
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/wallet/kms"
+)
+
+var _ core.Signer = (*KeyProviderSigner)(nil)
+
+// KeyProviderSigner signs with a key held by an external key management service, e.g. HashiCorp
+// Vault or AWS KMS, so that the validator key is never stored on the node.
+type KeyProviderSigner struct {
+	provider kms.KeyProvider
+	pubKey   *crypto.PublicKey
+}
+
+// NewKeyProviderSigner creates an instance of KeyProviderSigner.
+func NewKeyProviderSigner(provider kms.KeyProvider) (*KeyProviderSigner, error) {
+	pubKey, err := provider.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &KeyProviderSigner{
+		provider: provider,
+		pubKey:   pubKey,
+	}, nil
+}
+
+// PublicKey implements the core.Signer interface.
+func (s *KeyProviderSigner) PublicKey() *crypto.PublicKey {
+	return s.pubKey
+}
+
+// SignVote implements the core.Signer interface.
+func (s *KeyProviderSigner) SignVote(vote core.Vote) (*crypto.Signature, error) {
+	return s.provider.Sign(vote.SignBytes())
+}
+
+// SignBlock implements the core.Signer interface.
+func (s *KeyProviderSigner) SignBlock(header *core.BlockHeader) (*crypto.Signature, error) {
+	return s.provider.Sign(header.SignBytes())
+}
+
+// SignTx implements the core.Signer interface.
+func (s *KeyProviderSigner) SignTx(signBytes common.Bytes) (*crypto.Signature, error) {
+	return s.provider.Sign(signBytes)
+}
@@ -0,0 +1,51 @@
+package signer
+
+import (
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+)
+
+var _ core.Signer = (*ProtectedSigner)(nil)
+
+// ProtectedSigner wraps a signer, and checks every vote and proposal against the persisted sign
+// state before signing, so that a validator restored from a backup does not sign a vote or a
+// proposal conflicting with the ones it signed before.
+type ProtectedSigner struct {
+	signer core.Signer
+	state  *SignState
+}
+
+// NewProtectedSigner creates an instance of ProtectedSigner.
+func NewProtectedSigner(signer core.Signer, state *SignState) *ProtectedSigner {
+	return &ProtectedSigner{
+		signer: signer,
+		state:  state,
+	}
+}
+
+// PublicKey implements the core.Signer interface.
+func (s *ProtectedSigner) PublicKey() *crypto.PublicKey {
+	return s.signer.PublicKey()
+}
+
+// SignVote implements the core.Signer interface.
+func (s *ProtectedSigner) SignVote(vote core.Vote) (*crypto.Signature, error) {
+	if err := s.state.CheckVote(vote); err != nil {
+		return nil, err
+	}
+	return s.signer.SignVote(vote)
+}
+
+// SignBlock implements the core.Signer interface.
+func (s *ProtectedSigner) SignBlock(header *core.BlockHeader) (*crypto.Signature, error) {
+	if err := s.state.CheckBlock(header); err != nil {
+		return nil, err
+	}
+	return s.signer.SignBlock(header)
+}
+
+// SignTx implements the core.Signer interface.
+func (s *ProtectedSigner) SignTx(signBytes common.Bytes) (*crypto.Signature, error) {
+	return s.signer.SignTx(signBytes)
+}
@@ -0,0 +1,252 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/rpc"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/rlp"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "signer"})
+
+// serviceName is the name the signing service is registered with
+const serviceName = "Signer"
+
+// PublicKeyArgs is the arguments of the PublicKey request
+type PublicKeyArgs struct{}
+
+// PublicKeyReply is the reply of the PublicKey request
+type PublicKeyReply struct {
+	PublicKey []byte
+}
+
+// SignVoteArgs is the arguments of the SignVote request, with the RLP encoded vote
+type SignVoteArgs struct {
+	Vote []byte
+}
+
+// SignBlockArgs is the arguments of the SignBlock request, with the RLP encoded block header
+type SignBlockArgs struct {
+	Header []byte
+}
+
+// SignTxArgs is the arguments of the SignTx request
+type SignTxArgs struct {
+	SignBytes []byte
+}
+
+// SignReply is the reply of the signing requests
+type SignReply struct {
+	Signature []byte
+}
+
+// Service signs the requests of the validator node with the key held by the signer process
+type Service struct {
+	chainID string
+	privKey *crypto.PrivateKey
+	state   *SignState
+}
+
+// NewService creates an instance of Service.
+func NewService(chainID string, privKey *crypto.PrivateKey, state *SignState) *Service {
+	return &Service{
+		chainID: chainID,
+		privKey: privKey,
+		state:   state,
+	}
+}
+
+// PublicKey returns the public key of the signing key
+func (s *Service) PublicKey(args *PublicKeyArgs, reply *PublicKeyReply) error {
+	reply.PublicKey = s.privKey.PublicKey().ToBytes()
+	return nil
+}
+
+// SignVote signs the vote unless it conflicts with the last signed vote
+func (s *Service) SignVote(args *SignVoteArgs, reply *SignReply) error {
+	vote := core.Vote{}
+	if err := rlp.DecodeBytes(args.Vote, &vote); err != nil {
+		return fmt.Errorf("Failed to decode vote: %v", err)
+	}
+	if vote.ID != s.privKey.PublicKey().Address() {
+		return fmt.Errorf("Vote ID %v does not match the signing key", vote.ID.Hex())
+	}
+	if err := s.state.CheckVote(vote); err != nil {
+		logger.WithFields(log.Fields{"vote": vote, "height": vote.Height}).Warn(err)
+		return err
+	}
+	sig, err := s.privKey.Sign(vote.SignBytes())
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig.ToBytes()
+	return nil
+}
+
+// SignBlock signs the proposed block header unless it conflicts with the last signed proposal
+func (s *Service) SignBlock(args *SignBlockArgs, reply *SignReply) error {
+	header := &core.BlockHeader{}
+	if err := rlp.DecodeBytes(args.Header, header); err != nil {
+		return fmt.Errorf("Failed to decode block header: %v", err)
+	}
+	if header.ChainID != s.chainID {
+		return fmt.Errorf("Chain ID %v does not match %v", header.ChainID, s.chainID)
+	}
+	if header.Proposer != s.privKey.PublicKey().Address() {
+		return fmt.Errorf("Proposer %v does not match the signing key", header.Proposer.Hex())
+	}
+	if err := s.state.CheckBlock(header); err != nil {
+		logger.WithFields(log.Fields{"height": header.Height, "epoch": header.Epoch}).Warn(err)
+		return err
+	}
+	sig, err := s.privKey.Sign(header.SignBytes())
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig.ToBytes()
+	return nil
+}
+
+// SignTx signs the special transactions added by the proposer, i.e. the coinbase and the slash
+// transactions. Other sign bytes are rejected, so the signing key cannot be used to move funds.
+func (s *Service) SignTx(args *SignTxArgs, reply *SignReply) error {
+	tx, err := s.decodeSignBytes(args.SignBytes)
+	if err != nil {
+		return err
+	}
+	var proposer common.Address
+	switch tx := tx.(type) {
+	case *types.CoinbaseTx:
+		proposer = tx.Proposer.Address
+	case *types.SlashTx:
+		proposer = tx.Proposer.Address
+	default:
+		return fmt.Errorf("Refuse to sign transaction of type %T", tx)
+	}
+	if proposer != s.privKey.PublicKey().Address() {
+		return fmt.Errorf("Proposer %v does not match the signing key", proposer.Hex())
+	}
+	sig, err := s.privKey.Sign(args.SignBytes)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig.ToBytes()
+	return nil
+}
+
+// decodeSignBytes recovers the transaction from its sign bytes, and checks that the sign bytes are
+// exactly the ones of the transaction on the chain of the signer.
+func (s *Service) decodeSignBytes(signBytes []byte) (types.Tx, error) {
+	wrapper := types.EthereumTxWrapper{}
+	if err := rlp.DecodeBytes(signBytes, &wrapper); err != nil {
+		return nil, fmt.Errorf("Failed to decode sign bytes: %v", err)
+	}
+	chainIDBytes, err := rlp.EncodeToBytes(s.chainID)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(wrapper.Payload, chainIDBytes) {
+		return nil, fmt.Errorf("Sign bytes are not for chain %v", s.chainID)
+	}
+	tx, err := types.TxFromBytes(wrapper.Payload[len(chainIDBytes):])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode transaction: %v", err)
+	}
+	if !bytes.Equal(tx.SignBytes(s.chainID), signBytes) {
+		return nil, fmt.Errorf("Sign bytes do not match the transaction")
+	}
+	return tx, nil
+}
+
+// Server serves the signing requests of a validator node over mutually authenticated TLS connections.
+type Server struct {
+	service   *Service
+	tlsConfig *tls.Config
+	listener  net.Listener
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// NewServer creates an instance of Server.
+func NewServer(service *Service, tlsConfig *tls.Config) *Server {
+	return &Server{
+		service:   service,
+		tlsConfig: tlsConfig,
+		wg:        &sync.WaitGroup{},
+	}
+}
+
+// Start starts listening on the given address.
+func (s *Server) Start(ctx context.Context, address string) error {
+	c, cancel := context.WithCancel(ctx)
+	s.ctx = c
+	s.cancel = cancel
+
+	listener, err := tls.Listen("tcp", address, s.tlsConfig)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	logger.Infof("Remote signer listening on %v", address)
+
+	s.wg.Add(1)
+	go s.mainLoop()
+
+	go func() {
+		<-s.ctx.Done()
+		s.listener.Close()
+	}()
+	return nil
+}
+
+// Stop notifies the server to stop.
+func (s *Server) Stop() {
+	s.cancel()
+}
+
+// Wait suspends the caller goroutine.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) mainLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.ctx.Done():
+				return
+			default:
+			}
+			logger.Warnf("Failed to accept connection: %v", err)
+			continue
+		}
+		logger.Infof("Accepted connection from %v", conn.RemoteAddr())
+		go s.ServeConn(conn)
+	}
+}
+
+// ServeConn serves the signing requests on the given connection until it is closed.
+func (s *Server) ServeConn(conn net.Conn) {
+	defer conn.Close()
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, s.service); err != nil {
+		logger.Panic(err)
+	}
+	server.ServeConn(conn)
+}
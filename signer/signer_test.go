@@ -0,0 +1,167 @@
+package signer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+)
+
+const testChainID = "testchain"
+
+func TestSignState(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "signer_state.json")
+
+	s, err := LoadSignState(path)
+	assert.Nil(err)
+
+	b1 := common.HexToHash("a1")
+	b2 := common.HexToHash("a2")
+	assert.Nil(s.CheckVote(core.Vote{Block: b1, Height: 10, Epoch: 11}))
+	assert.Nil(s.CheckVote(core.Vote{Block: b1, Height: 10, Epoch: 12}))
+	assert.Equal(ErrDoubleSignVote, s.CheckVote(core.Vote{Block: b2, Height: 10, Epoch: 12}))
+	assert.Equal(ErrDoubleSignVote, s.CheckVote(core.Vote{Block: b2, Height: 9, Epoch: 13}))
+	assert.Nil(s.CheckVote(core.Vote{Block: b2, Height: 11, Epoch: 13}))
+
+	h1 := &core.BlockHeader{ChainID: testChainID, Epoch: 20, Height: 12, Parent: b2}
+	h2 := &core.BlockHeader{ChainID: testChainID, Epoch: 20, Height: 12, Parent: b1}
+	assert.Nil(s.CheckBlock(h1))
+	assert.Nil(s.CheckBlock(h1))
+	assert.Equal(ErrDoubleSignProposal, s.CheckBlock(h2))
+
+	// The state survives restarts
+	s, err = LoadSignState(path)
+	assert.Nil(err)
+	assert.Equal(uint64(11), s.VoteHeight)
+	assert.Equal(b2, s.VoteBlock)
+	assert.Equal(ErrDoubleSignVote, s.CheckVote(core.Vote{Block: b1, Height: 11, Epoch: 14}))
+	assert.Equal(ErrDoubleSignProposal, s.CheckBlock(h2))
+	h2.Epoch = 21
+	assert.Nil(s.CheckBlock(h2))
+}
+
+func newTestRemoteSigner(t *testing.T, privKey *crypto.PrivateKey) *RemoteSigner {
+	state, err := LoadSignState("")
+	assert.Nil(t, err)
+	server := NewServer(NewService(testChainID, privKey, state), nil)
+
+	dial := func() (net.Conn, error) {
+		clientConn, serverConn := net.Pipe()
+		go server.ServeConn(serverConn)
+		return clientConn, nil
+	}
+	signer, err := newRemoteSigner(dial, 3*time.Second)
+	assert.Nil(t, err)
+	return signer
+}
+
+func TestRemoteSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	address := privKey.PublicKey().Address()
+
+	signer := newTestRemoteSigner(t, privKey)
+	defer signer.Close()
+	assert.Equal(address, signer.PublicKey().Address())
+
+	// Votes
+	vote := core.Vote{Block: common.HexToHash("a1"), Height: 10, Epoch: 11, ID: address}
+	sig, err := signer.SignVote(vote)
+	assert.Nil(err)
+	assert.True(sig.Verify(vote.SignBytes(), address))
+
+	_, err = signer.SignVote(core.Vote{Block: common.HexToHash("a2"), Height: 10, Epoch: 12, ID: address})
+	assert.NotNil(err)
+
+	_, err = signer.SignVote(core.Vote{Block: common.HexToHash("a3"), Height: 11, Epoch: 12, ID: common.HexToAddress("b1")})
+	assert.NotNil(err)
+
+	// Proposals
+	header := &core.BlockHeader{
+		ChainID:   testChainID,
+		Epoch:     12,
+		Height:    11,
+		Parent:    common.HexToHash("a1"),
+		Timestamp: big.NewInt(1),
+		Proposer:  address,
+	}
+	sig, err = signer.SignBlock(header)
+	assert.Nil(err)
+	assert.True(sig.Verify(header.SignBytes(), address))
+
+	header.Parent = common.HexToHash("a2")
+	_, err = signer.SignBlock(header)
+	assert.NotNil(err)
+
+	header.Epoch = 13
+	header.ChainID = "otherchain"
+	_, err = signer.SignBlock(header)
+	assert.NotNil(err)
+
+	// Transactions
+	coinbaseTx := &types.CoinbaseTx{
+		Proposer:    types.TxInput{Address: address},
+		Outputs:     []types.TxOutput{{Address: common.HexToAddress("b1")}},
+		BlockHeight: 11,
+	}
+	signBytes := coinbaseTx.SignBytes(testChainID)
+	sig, err = signer.SignTx(signBytes)
+	assert.Nil(err)
+	assert.True(sig.Verify(signBytes, address))
+
+	_, err = signer.SignTx(coinbaseTx.SignBytes("otherchain"))
+	assert.NotNil(err)
+
+	sendTx := &types.SendTx{
+		Fee:     types.NewCoins(0, 1),
+		Inputs:  []types.TxInput{{Address: address, Coins: types.NewCoins(0, 2), Sequence: 1}},
+		Outputs: []types.TxOutput{{Address: common.HexToAddress("b1"), Coins: types.NewCoins(0, 1)}},
+	}
+	_, err = signer.SignTx(sendTx.SignBytes(testChainID))
+	assert.NotNil(err)
+}
+
+func TestProtectedSigner(t *testing.T) {
+	assert := assert.New(t)
+
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(err)
+	address := privKey.PublicKey().Address()
+
+	state, err := LoadSignState("")
+	assert.Nil(err)
+	signer := NewProtectedSigner(core.NewLocalSigner(privKey), state)
+	assert.Equal(address, signer.PublicKey().Address())
+
+	vote := core.Vote{Block: common.HexToHash("a1"), Height: 10, Epoch: 11, ID: address}
+	sig, err := signer.SignVote(vote)
+	assert.Nil(err)
+	assert.True(sig.Verify(vote.SignBytes(), address))
+
+	_, err = signer.SignVote(core.Vote{Block: common.HexToHash("a2"), Height: 10, Epoch: 12, ID: address})
+	assert.Equal(ErrDoubleSignVote, err)
+
+	header := &core.BlockHeader{ChainID: testChainID, Epoch: 12, Height: 11, Proposer: address}
+	_, err = signer.SignBlock(header)
+	assert.Nil(err)
+	header.Parent = common.HexToHash("a2")
+	_, err = signer.SignBlock(header)
+	assert.Equal(ErrDoubleSignProposal, err)
+}
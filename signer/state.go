@@ -0,0 +1,131 @@
+package signer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+)
+
+var (
+	ErrDoubleSignVote     = errors.New("Refuse to sign a vote conflicting with the last signed vote")
+	ErrDoubleSignProposal = errors.New("Refuse to sign a proposal conflicting with the last signed proposal")
+)
+
+// SignState keeps the last signed vote and proposal. The signer never signs a vote for a lower height,
+// or for a different block at the same height, and never signs a proposal for a lower epoch, or a
+// different proposal in the same epoch. The state is persisted before the signature is released, so
+// the protection survives restarts.
+type SignState struct {
+	VoteHeight    uint64      `json:"vote_height"`
+	VoteBlock     common.Hash `json:"vote_block"`
+	ProposalEpoch uint64      `json:"proposal_epoch"`
+	ProposalHash  common.Hash `json:"proposal_hash"` // hash of the sign bytes of the proposed block header
+
+	path string
+	mu   *sync.Mutex
+}
+
+// LoadSignState loads the sign state from the given file. An empty state is created if the file does
+// not exist, and the state is kept in memory only if the path is empty.
+func LoadSignState(path string) (*SignState, error) {
+	s := &SignState{
+		path: path,
+		mu:   &sync.Mutex{},
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("Failed to parse sign state %v: %v", path, err)
+	}
+	return s, nil
+}
+
+// CheckVote checks whether the vote conflicts with the last signed vote, and records it otherwise.
+func (s *SignState) CheckVote(vote core.Vote) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if vote.Height < s.VoteHeight || (vote.Height == s.VoteHeight && vote.Block != s.VoteBlock) {
+		return ErrDoubleSignVote
+	}
+	if vote.Height == s.VoteHeight {
+		return nil // repeating the last vote, e.g. in a new epoch
+	}
+
+	prevHeight, prevBlock := s.VoteHeight, s.VoteBlock
+	s.VoteHeight, s.VoteBlock = vote.Height, vote.Block
+	if err := s.save(); err != nil {
+		s.VoteHeight, s.VoteBlock = prevHeight, prevBlock
+		return err
+	}
+	return nil
+}
+
+// CheckBlock checks whether the proposed block conflicts with the last signed proposal, and records it otherwise.
+func (s *SignState) CheckBlock(header *core.BlockHeader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash := crypto.Keccak256Hash(header.SignBytes())
+	if header.Epoch < s.ProposalEpoch || (header.Epoch == s.ProposalEpoch && hash != s.ProposalHash) {
+		return ErrDoubleSignProposal
+	}
+	if header.Epoch == s.ProposalEpoch {
+		return nil
+	}
+
+	prevEpoch, prevHash := s.ProposalEpoch, s.ProposalHash
+	s.ProposalEpoch, s.ProposalHash = header.Epoch, hash
+	if err := s.save(); err != nil {
+		s.ProposalEpoch, s.ProposalHash = prevEpoch, prevHash
+		return err
+	}
+	return nil
+}
+
+// save writes the state to a temporary file and renames it, so the file is never left half written.
+func (s *SignState) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
@@ -0,0 +1,158 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+)
+
+// Formats of the block dumps. The RLP format uses the same length prefixed records as the
+// chain backup files, the JSON format has one record per line.
+const (
+	BlockDumpFormatRLP  = "rlp"
+	BlockDumpFormatJSON = "json"
+)
+
+// blockDumpRecord is a record of the JSON block dump
+type blockDumpRecord struct {
+	Hash  common.Hash         `json:"hash"`
+	Block *core.ExtendedBlock `json:"block"`
+	Votes *core.VoteSet       `json:"votes"`
+}
+
+func checkBlockDumpFormat(format string) error {
+	if format != BlockDumpFormatRLP && format != BlockDumpFormatJSON {
+		return fmt.Errorf("Unsupported block dump format: %v, expected %v or %v", format, BlockDumpFormatRLP, BlockDumpFormatJSON)
+	}
+	return nil
+}
+
+// DumpBlocks writes the finalized blocks from height from to height to (inclusive) in the
+// ascending order of the heights, along with their votes. The heights without a finalized
+// block, e.g. pruned or before the snapshot, are skipped. Returns the number of blocks written.
+func DumpBlocks(chain *blockchain.Chain, from, to uint64, format string, w io.Writer) (uint64, error) {
+	if from > to {
+		return 0, fmt.Errorf("From height %v is greater than to height %v", from, to)
+	}
+	if err := checkBlockDumpFormat(format); err != nil {
+		return 0, err
+	}
+
+	writer := bufio.NewWriter(w)
+	defer writer.Flush()
+	encoder := json.NewEncoder(writer)
+
+	count := uint64(0)
+	for height := from; height <= to; height++ {
+		block := findFinalizedBlock(chain, height)
+		if block == nil {
+			continue
+		}
+		voteSet := chain.FindVotesByHash(block.Hash())
+
+		var err error
+		if format == BlockDumpFormatRLP {
+			err = writeBlock(writer, &core.BackupBlock{Block: block, Votes: voteSet})
+		} else {
+			err = encoder.Encode(blockDumpRecord{Hash: block.Hash(), Block: block, Votes: voteSet})
+		}
+		if err != nil {
+			return count, fmt.Errorf("Failed to write block at height %v: %v", height, err)
+		}
+		count++
+
+		if height == to { // avoid overflow when to is the max uint64
+			break
+		}
+	}
+	return count, writer.Flush()
+}
+
+// ImportBlocks reads the blocks written by DumpBlocks and adds them to the chain as finalized
+// blocks. The block headers and the chaining between the imported blocks are checked, but the
+// votes are not validated against the validator sets, so the dump must come from a trusted
+// source. Blocks already in the chain are skipped.
+func ImportBlocks(chain *blockchain.Chain, r io.Reader, format string) (imported, skipped uint64, err error) {
+	if err := checkBlockDumpFormat(format); err != nil {
+		return 0, 0, err
+	}
+
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+
+	var prevBlock *core.ExtendedBlock
+	for {
+		var block *core.ExtendedBlock
+		var voteSet *core.VoteSet
+		if format == BlockDumpFormatRLP {
+			backupBlock := &core.BackupBlock{}
+			if _, err := core.ReadRecord(reader, backupBlock); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return imported, skipped, fmt.Errorf("Failed to read block record: %v", err)
+			}
+			block, voteSet = backupBlock.Block, backupBlock.Votes
+		} else {
+			record := &blockDumpRecord{}
+			if err := decoder.Decode(record); err != nil {
+				if err == io.EOF {
+					break
+				}
+				return imported, skipped, fmt.Errorf("Failed to read block record: %v", err)
+			}
+			if record.Block == nil || record.Block.Block == nil {
+				return imported, skipped, fmt.Errorf("Block record %v has no block", record.Hash.Hex())
+			}
+			if record.Block.Hash() != record.Hash {
+				return imported, skipped, fmt.Errorf("Block record hash mismatch: %v vs %v", record.Block.Hash().Hex(), record.Hash.Hex())
+			}
+			block, voteSet = record.Block, record.Votes
+		}
+
+		if block.Height != core.GenesisBlockHeight {
+			if res := block.Validate(chain.ChainID); res.IsError() {
+				return imported, skipped, fmt.Errorf("Block %v at height %v is invalid: %v", block.Hash().Hex(), block.Height, res)
+			}
+		}
+		if prevBlock != nil && block.Height == prevBlock.Height+1 && block.Parent != prevBlock.Hash() {
+			return imported, skipped, fmt.Errorf("Block at height %v has parent %v, expected %v", block.Height, block.Parent.Hex(), prevBlock.Hash().Hex())
+		}
+		prevBlock = block
+
+		if _, err := chain.FindBlock(block.Hash()); err == nil {
+			skipped++
+			continue
+		}
+		if _, err := chain.AddBlock(block.Block); err != nil {
+			return imported, skipped, fmt.Errorf("Failed to add block %v: %v", block.Hash().Hex(), err)
+		}
+		if voteSet != nil {
+			for _, vote := range voteSet.Votes() {
+				chain.AddVoteToIndex(vote)
+			}
+		}
+		if block.HasValidatorUpdate {
+			chain.MarkBlockHasValidatorUpdate(block.Hash())
+		}
+		if err := chain.FinalizePreviousBlocks(block.Hash()); err != nil {
+			return imported, skipped, err
+		}
+		imported++
+	}
+	return imported, skipped, nil
+}
+
+func findFinalizedBlock(chain *blockchain.Chain, height uint64) *core.ExtendedBlock {
+	for _, block := range chain.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return block
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/store/database"
+)
+
+// WriteGenesisSnapshot writes the metadata and the state in the genesis snapshot format, which
+// is the format of the snapshots without the snapshot header. The account storages are
+// written along with the accounts.
+func WriteGenesisSnapshot(writer *bufio.Writer, metadata *core.SnapshotMetadata, sv *state.StoreView, db database.Database) error {
+	if err := core.WriteMetadata(writer, metadata); err != nil {
+		return err
+	}
+	writeStoreView(sv, true, writer, db)
+	return writer.Flush()
+}
+
+// LoadGenesisSnapshot loads the state of a genesis snapshot into the db, and checks the state
+// against the state hash of the genesis block. Unlike ImportSnapshot, the genesis block hash
+// is not checked, so that the snapshot can be inspected or migrated before the chain is set up.
+func LoadGenesisSnapshot(genesisFilePath string, db database.Database) (*core.SnapshotMetadata, *state.StoreView, error) {
+	file, err := os.Open(genesisFilePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	metadata := &core.SnapshotMetadata{}
+	if _, err := core.ReadRecord(file, metadata); err != nil {
+		return nil, nil, fmt.Errorf("Failed to load genesis snapshot metadata, %v", err)
+	}
+	if metadata.Version != 0 && metadata.Version != core.SnapshotVersionMin { // the genesis snapshots have no header
+		return nil, nil, fmt.Errorf("Unsupported genesis snapshot version %v", metadata.Version)
+	}
+	genesisBlockHeader := metadata.TailTrio.Second.Header
+	if genesisBlockHeader == nil || genesisBlockHeader.Height != core.GenesisBlockHeight {
+		return nil, nil, fmt.Errorf("%v is not a genesis snapshot", genesisFilePath)
+	}
+
+	sv, _, err := loadStateV2(file, db, 0, "Loading genesis snapshot")
+	if err != nil {
+		return nil, nil, err
+	}
+	if sv == nil || sv.Hash() != genesisBlockHeader.StateHash {
+		return nil, nil, fmt.Errorf("Genesis state hash mismatch, expected: %v", genesisBlockHeader.StateHash.Hex())
+	}
+	return metadata, sv, nil
+}
@@ -96,7 +96,7 @@ func ExportSnapshotV2(db database.Database, consensus *cns.ConsensusEngine, chai
 
 	// -------------- Export the Metadata Section -------------- //
 
-	metadata := &core.SnapshotMetadata{}
+	metadata := &core.SnapshotMetadata{Version: snapshotHeader.Version}
 	var genesisBlockHeader *core.BlockHeader
 	kvStore := kvstore.NewKVStore(db)
 	hl := sv.GetStakeTransactionHeightList().Heights
@@ -312,7 +312,7 @@ func ExportSnapshotV3(db database.Database, consensus *cns.ConsensusEngine, chai
 
 	// -------------- Export the Metadata Section -------------- //
 
-	metadata := &core.SnapshotMetadata{}
+	metadata := &core.SnapshotMetadata{Version: snapshotHeader.Version}
 	var genesisBlockHeader *core.BlockHeader
 	kvStore := kvstore.NewKVStore(db)
 	hl := sv.GetStakeTransactionHeightList().Heights
@@ -529,7 +529,7 @@ func ExportSnapshotV4(db database.Database, consensus *cns.ConsensusEngine, chai
 
 	// -------------- Export the Metadata Section -------------- //
 
-	metadata := &core.SnapshotMetadata{}
+	metadata := &core.SnapshotMetadata{Version: snapshotHeader.Version}
 
 	parentBlock, err := chain.FindBlock(lastFinalizedBlock.Parent)
 	if err != nil {
@@ -581,11 +581,21 @@ func ExportSnapshotV4(db database.Database, consensus *cns.ConsensusEngine, chai
 	return filename, nil
 }
 
+// proveVCP collects the Merkle proof of the validator candidate pool, along with the proofs of the
+// signing keys of the selected validators, from which the importer resolves their key rotations
 func proveVCP(block *core.ExtendedBlock, db database.Database) (*core.VCPProof, error) {
 	sv := state.NewStoreView(block.Height, block.StateHash, db)
 	vcpKey := state.ValidatorCandidatePoolKey()
 	vp := &core.VCPProof{}
 	err := sv.ProveVCP(vcpKey, vp)
+	if err != nil {
+		return vp, err
+	}
+	holders := []common.Address{}
+	for _, v := range cns.SelectTopStakeHoldersAsValidators(sv.GetValidatorCandidatePool()).Validators() {
+		holders = append(holders, v.ID())
+	}
+	err = sv.ProveValidatorSigningKeys(holders, vp)
 	return vp, err
 }
 
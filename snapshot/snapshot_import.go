@@ -149,16 +149,17 @@ func LoadSnapshotCheckpointHeader(snapshotFilePath string) *core.BlockHeader {
 	}
 	defer snapshotFile.Close()
 
-	snapshotHeader := &core.SnapshotHeader{}
-	_, err = core.ReadRecord(snapshotFile, snapshotHeader)
+	snapshotHeader, err := core.ReadSnapshotHeader(snapshotFile)
 	if err != nil {
 		return nil
 	}
 
-	lastCheckpoint := core.LastCheckpoint{}
-	_, err = core.ReadRecord(snapshotFile, &lastCheckpoint)
-	if err != nil {
-		return nil
+	if snapshotHeader.Version >= 2 {
+		lastCheckpoint := core.LastCheckpoint{}
+		_, err = core.ReadRecord(snapshotFile, &lastCheckpoint)
+		if err != nil {
+			return nil
+		}
 	}
 
 	metadata := core.SnapshotMetadata{}
@@ -166,6 +167,10 @@ func LoadSnapshotCheckpointHeader(snapshotFilePath string) *core.BlockHeader {
 	if err != nil {
 		return nil
 	}
+	if err := metadata.CheckVersion(snapshotHeader.Version); err != nil {
+		logger.Errorf("Failed to load snapshot checkpoint header: %v", err)
+		return nil
+	}
 
 	return metadata.TailTrio.Second.Header
 }
@@ -183,14 +188,11 @@ func loadSnapshot(snapshotFilePath string, db database.Database, logStr string)
 
 	// ------------------------------ Load State ------------------------------ //
 
-	snapshotVersion := uint(1)
-	snapshotHeader := &core.SnapshotHeader{}
-	_, err = core.ReadRecord(snapshotFile, snapshotHeader)
-	if err != nil || snapshotHeader.Magic != core.SnapshotHeaderMagic { // older version, reset snapshotFile
-		snapshotFile.Seek(0, 0)
-	} else {
-		snapshotVersion = snapshotHeader.Version
+	snapshotHeader, err := core.ReadSnapshotHeader(snapshotFile)
+	if err != nil {
+		return nil, nil, err
 	}
+	snapshotVersion := snapshotHeader.Version
 
 	logger.Infof("Reading snapshot header, version: %v, magic: %v", snapshotVersion, snapshotHeader.Magic)
 
@@ -241,6 +243,9 @@ func loadSnapshot(snapshotFilePath string, db database.Database, logStr string)
 	if err != nil {
 		return nil, nil, fmt.Errorf("Failed to load snapshot metadata, %v", err)
 	}
+	if err := metadata.CheckVersion(snapshotVersion); err != nil {
+		return nil, nil, err
+	}
 
 	fileInfo, err := os.Stat(snapshotFilePath)
 	var fileSize uint64
@@ -249,18 +254,21 @@ func loadSnapshot(snapshotFilePath string, db database.Database, logStr string)
 	}
 
 	var sv *state.StoreView
-	if snapshotHeader.Version >= 3 {
-		err = loadStateV3(snapshotFile, db, fileSize, logStr)
+	switch snapshotVersion {
+	case 1, 2:
+		sv, _, err = loadStateV2(snapshotFile, db, fileSize, logStr)
 		if err != nil {
 			return nil, nil, err
 		}
-		lfb := metadata.TailTrio.Second
-		sv = state.NewStoreView(lfb.Header.Height, lfb.Header.StateHash, db)
-	} else {
-		sv, _, err = loadStateV2(snapshotFile, db, fileSize, logStr)
+	case 3, 4:
+		err = loadStateV3(snapshotFile, db, fileSize, logStr)
 		if err != nil {
 			return nil, nil, err
 		}
+		lfb := metadata.TailTrio.Second
+		sv = state.NewStoreView(lfb.Header.Height, lfb.Header.StateHash, db)
+	default:
+		return nil, nil, fmt.Errorf("No state loader for snapshot version %v", snapshotVersion)
 	}
 
 	// ----------------------------- Validity Checks -------------------------- //
@@ -513,7 +521,7 @@ func loadChainSegment(filePath string, start, end uint64, prevBlock *core.Extend
 				if proofTrio.First.Header.Height == core.GenesisBlockHeight {
 					provenValSet, err = checkGenesisBlock(proofTrio.Second.Header, db)
 				} else {
-					provenValSet, err = getValidatorSetFromVCPProof(proofTrio.First.Header.StateHash, &proofTrio.First.Proof)
+					provenValSet, err = getValidatorSetFromVCPProof(proofTrio.First.Header.StateHash, block.Height, &proofTrio.First.Proof)
 				}
 				if err != nil {
 					return nil, fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
@@ -810,7 +818,7 @@ func checkSnapshotV4(sv *state.StoreView, metadata *core.SnapshotMetadata, db da
 	var err error
 
 	first := tailTrio.First
-	valSet, err = getValidatorSetFromVCPProof(first.Header.StateHash, &first.Proof)
+	valSet, err = getValidatorSetFromVCPProof(first.Header.StateHash, first.Header.Height+2, &first.Proof)
 	if err != nil {
 		return fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
 	}
@@ -828,7 +836,8 @@ func checkSnapshotV4(sv *state.StoreView, metadata *core.SnapshotMetadata, db da
 func checkProofTrios(proofTrios []core.SnapshotBlockTrio, db database.Database) (*core.ValidatorSet, error) {
 	logger.Debugf("Check validator set change proofs...")
 
-	var provenValSet *core.ValidatorSet // the proven validator set so far
+	var provenValSet *core.ValidatorSet   // the proven validator set so far
+	var provenBy *core.SnapshotFirstBlock // the block whose VCP proof proves the validator set, nil for the genesis
 	var err error
 	for idx, blockTrio := range proofTrios {
 		first := blockTrio.First
@@ -850,14 +859,23 @@ func checkProofTrios(proofTrios []core.SnapshotBlockTrio, db database.Database)
 					second.Header.Hash(), third.Header.HCC.BlockHash)
 			}
 
-			// third.Header.HCC.Votes contains the votes for the second block in the trio
-			if err := validateVotes(provenValSet, second.Header, third.Header.HCC.Votes); err != nil {
+			// third.Header.HCC.Votes contains the votes for the second block in the trio, signed by the
+			// keys of the validators at the height of the second block
+			voters := provenValSet
+			if provenBy != nil {
+				voters, err = getValidatorSetFromVCPProof(provenBy.Header.StateHash, second.Header.Height, &provenBy.Proof)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
+				}
+			}
+			if err := validateVotes(voters, second.Header, third.Header.HCC.Votes); err != nil {
 				return nil, fmt.Errorf("Failed to validate voteSet, %v", err)
 			}
-			provenValSet, err = getValidatorSetFromVCPProof(first.Header.StateHash, &first.Proof)
+			provenValSet, err = getValidatorSetFromVCPProof(first.Header.StateHash, first.Header.Height+2, &first.Proof)
 			if err != nil {
 				return nil, fmt.Errorf("Failed to retrieve validator set from VCP proof: %v", err)
 			}
+			provenBy = &first
 		}
 
 		logger.Debugf("Block height: %v, Currently proven validator set: %v", first.Header.Height, provenValSet)
@@ -877,7 +895,7 @@ func checkTailTrio(sv *state.StoreView, provenValSet *core.ValidatorSet, tailTri
 		}
 	} else {
 		validateVotes(provenValSet, third.Header, third.VoteSet)
-		retrievedValSet := getValidatorSetFromSV(sv)
+		retrievedValSet := getValidatorSetFromSV(sv, tailTrio.First.Header.Height+2)
 		if !provenValSet.Equals(retrievedValSet) {
 			return fmt.Errorf("The latest proven and retrieved validator set does not match")
 		}
@@ -911,12 +929,14 @@ func checkGenesisBlock(block *core.BlockHeader, db database.Database) (*core.Val
 	// genesis validator set from its state trie
 	gsv := state.NewStoreView(block.Height, block.StateHash, db)
 
-	genesisValidatorSet := getValidatorSetFromSV(gsv)
+	genesisValidatorSet := getValidatorSetFromSV(gsv, block.Height)
 
 	return genesisValidatorSet, nil
 }
 
-func getValidatorSetFromVCPProof(stateHash common.Hash, recoverredVp *core.VCPProof) (*core.ValidatorSet, error) {
+// getValidatorSetFromVCPProof retrieves the validator set from the proof of the validator candidate pool, with
+// the signing addresses at the given height resolved from the proofs of the validator signing keys
+func getValidatorSetFromVCPProof(stateHash common.Hash, height uint64, recoverredVp *core.VCPProof) (*core.ValidatorSet, error) {
 	serializedVCP, _, err := trie.VerifyProof(stateHash, state.ValidatorCandidatePoolKey(), recoverredVp)
 	if err != nil {
 		return nil, err
@@ -927,12 +947,22 @@ func getValidatorSetFromVCPProof(stateHash common.Hash, recoverredVp *core.VCPPr
 	if err != nil {
 		return nil, err
 	}
-	return consensus.SelectTopStakeHoldersAsValidators(vcp), nil
+	return consensus.SelectTopStakeHoldersAsValidatorsWithSigners(vcp, height, func(id common.Address) (common.Address, error) {
+		key, err := state.GetValidatorSigningKeyFromProof(stateHash, id, recoverredVp)
+		if err != nil || key == nil {
+			return id, err
+		}
+		return key.SigningAddress(height), nil
+	})
 }
 
-func getValidatorSetFromSV(sv *state.StoreView) *core.ValidatorSet {
+// getValidatorSetFromSV retrieves the validator set from the store view, with the signing addresses at the given height
+func getValidatorSetFromSV(sv *state.StoreView, height uint64) *core.ValidatorSet {
 	vcp := sv.GetValidatorCandidatePool()
-	return consensus.SelectTopStakeHoldersAsValidators(vcp)
+	valSet, _ := consensus.SelectTopStakeHoldersAsValidatorsWithSigners(vcp, height, func(id common.Address) (common.Address, error) {
+		return sv.GetValidatorSigningAddress(id, height), nil
+	})
+	return valSet
 }
 
 func validateVotes(validatorSet *core.ValidatorSet, block *core.BlockHeader, voteSet *core.VoteSet) error {
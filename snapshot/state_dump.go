@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/state"
+	"github.com/thetatoken/theta/store/database"
+)
+
+// ExportGenesisState writes the state of the finalized block at the height in the genesis
+// snapshot format written by generate_genesis, so that a new chain can be started from it. The
+// new genesis block inherits the timestamp of the finalized block, so the export is
+// deterministic. The heights recorded in the state, e.g. of the vesting schedules and the stake
+// withdrawals, are kept as is. Returns the header of the new genesis block.
+func ExportGenesisState(db database.Database, chain *blockchain.Chain, height uint64, chainID string, w io.Writer) (*core.BlockHeader, error) {
+	block := findFinalizedBlock(chain, height)
+	if block == nil {
+		return nil, fmt.Errorf("Can't find finalized block at height %v", height)
+	}
+	sv := state.NewStoreView(block.Height, block.StateHash, db)
+	if sv == nil {
+		return nil, fmt.Errorf("State of block %v at height %v is not retained", block.Hash().Hex(), height)
+	}
+	if chainID == "" {
+		chainID = block.ChainID
+	}
+
+	genesisBlock := core.NewBlock()
+	genesisBlock.ChainID = chainID
+	genesisBlock.Height = core.GenesisBlockHeight
+	genesisBlock.Epoch = genesisBlock.Height
+	genesisBlock.Parent = common.Hash{}
+	genesisBlock.StateHash = sv.Hash()
+	genesisBlock.Timestamp = new(big.Int).Set(block.Timestamp)
+
+	metadata := &core.SnapshotMetadata{
+		TailTrio: core.SnapshotBlockTrio{
+			First:  core.SnapshotFirstBlock{},
+			Second: core.SnapshotSecondBlock{Header: genesisBlock.BlockHeader},
+			Third:  core.SnapshotThirdBlock{},
+		},
+	}
+
+	if err := WriteGenesisSnapshot(bufio.NewWriter(w), metadata, sv, db); err != nil {
+		return nil, err
+	}
+	return genesisBlock.BlockHeader, nil
+}
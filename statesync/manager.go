@@ -0,0 +1,392 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/dispatcher"
+	"github.com/thetatoken/theta/p2p"
+	p2ptypes "github.com/thetatoken/theta/p2p/types"
+	"github.com/thetatoken/theta/p2pl"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "statesync"})
+
+const (
+	offerCollectionTime  = 10 * time.Second
+	chunkRequestTimeout  = 30 * time.Second
+	maxChunkAttempts     = 5
+	maxDownloadPeers     = 8
+	offerQueueSize       = 128
+	chunkResponseBufSize = 16
+	maxServingRequests   = 4 // max number of the snapshot queries and chunk requests served concurrently
+)
+
+var _ p2p.MessageHandler = (*StateSyncManager)(nil)
+var _ p2pl.MessageHandler = (*StateSyncManager)(nil)
+
+//
+// StateSyncManager serves the local snapshots to the peers, and downloads the latest
+// snapshot from the peers so that a new node does not need to sync from genesis
+//
+type StateSyncManager struct {
+	chainID    string
+	dispatcher *dispatcher.Dispatcher
+	provider   *SnapshotProvider
+
+	serveSlots chan struct{} // bounds the goroutines serving the peers
+
+	mutex         *sync.Mutex
+	syncing       bool
+	offers        chan peerOffer
+	pendingChunks map[uint64]chan ChunkResponse // chunk index |-> response channel
+}
+
+type peerOffer struct {
+	peerID string
+	offer  SnapshotOffer
+}
+
+// NewStateSyncManager creates an instance of the StateSyncManager
+func NewStateSyncManager(chainID string, networkOld p2p.Network, network p2pl.Network, disp *dispatcher.Dispatcher) *StateSyncManager {
+	ssm := &StateSyncManager{
+		chainID:       chainID,
+		dispatcher:    disp,
+		serveSlots:    make(chan struct{}, maxServingRequests),
+		mutex:         &sync.Mutex{},
+		pendingChunks: make(map[uint64]chan ChunkResponse),
+	}
+
+	if viper.GetBool(common.CfgSyncSnapshotServeEnabled) {
+		serveDir := viper.GetString(common.CfgSyncSnapshotServeDir)
+		if serveDir == "" {
+			serveDir = path.Join(filepath.Dir(viper.ConfigFileUsed()), "backup", "snapshot")
+		}
+		ssm.provider = NewSnapshotProvider(serveDir)
+	}
+
+	if !reflect.ValueOf(networkOld).IsNil() {
+		networkOld.RegisterMessageHandler(ssm)
+	}
+	if !reflect.ValueOf(network).IsNil() {
+		network.RegisterMessageHandler(ssm)
+	}
+
+	return ssm
+}
+
+// GetChannelIDs implements the p2p.MessageHandler interface
+func (ssm *StateSyncManager) GetChannelIDs() []common.ChannelIDEnum {
+	return []common.ChannelIDEnum{
+		common.ChannelIDSnapshot,
+	}
+}
+
+// ParseMessage implements the p2p.MessageHandler interface
+func (ssm *StateSyncManager) ParseMessage(peerID string, channelID common.ChannelIDEnum,
+	rawMessageBytes common.Bytes) (p2ptypes.Message, error) {
+	message := p2ptypes.Message{
+		PeerID:    peerID,
+		ChannelID: channelID,
+	}
+	data, err := decodeMessage(rawMessageBytes)
+	message.Content = data
+	return message, err
+}
+
+// EncodeMessage implements the p2p.MessageHandler interface
+func (ssm *StateSyncManager) EncodeMessage(message interface{}) (common.Bytes, error) {
+	return encodeMessage(message)
+}
+
+// HandleMessage implements the p2p.MessageHandler interface
+func (ssm *StateSyncManager) HandleMessage(message p2ptypes.Message) error {
+	switch content := message.Content.(type) {
+	case SnapshotQuery:
+		if ssm.provider != nil && content.ChainID == ssm.chainID {
+			ssm.serve(message.PeerID, func() { ssm.sendOffer(message.PeerID) })
+		}
+	case ChunkRequest:
+		if ssm.provider != nil {
+			ssm.serve(message.PeerID, func() { ssm.sendChunk(message.PeerID, content) })
+		}
+	case SnapshotOffer:
+		ssm.mutex.Lock()
+		if ssm.syncing {
+			select {
+			case ssm.offers <- peerOffer{peerID: message.PeerID, offer: content}:
+			default:
+			}
+		}
+		ssm.mutex.Unlock()
+	case ChunkResponse:
+		ssm.mutex.Lock()
+		if ch, ok := ssm.pendingChunks[content.Index]; ok {
+			select {
+			case ch <- content:
+			default:
+			}
+		}
+		ssm.mutex.Unlock()
+	default:
+		return fmt.Errorf("Unsupported message type: %v", reflect.TypeOf(message.Content))
+	}
+	return nil
+}
+
+// serve runs the handler in a new goroutine if a serving slot is available, otherwise the request
+// is dropped, and the peer retries with another node or on timeout
+func (ssm *StateSyncManager) serve(peerID string, handler func()) {
+	select {
+	case ssm.serveSlots <- struct{}{}:
+		go func() {
+			defer func() { <-ssm.serveSlots }()
+			handler()
+		}()
+	default:
+		logger.Debugf("Dropped snapshot request from peer %v, too many requests in progress", peerID)
+	}
+}
+
+func (ssm *StateSyncManager) sendOffer(peerID string) {
+	offer, err := ssm.provider.GetOffer()
+	if err != nil {
+		logger.Warnf("Failed to prepare the snapshot offer: %v", err)
+		return
+	}
+	if offer == nil {
+		return
+	}
+	ssm.dispatcher.Send([]string{peerID}, common.ChannelIDSnapshot, *offer)
+}
+
+func (ssm *StateSyncManager) sendChunk(peerID string, req ChunkRequest) {
+	data, err := ssm.provider.ReadChunk(req.SnapshotID, req.Index)
+	if err != nil {
+		logger.Debugf("Failed to read chunk %v of snapshot %v for peer %v: %v", req.Index, req.SnapshotID.Hex(), peerID, err)
+		return
+	}
+	ssm.dispatcher.Send([]string{peerID}, common.ChannelIDSnapshot, ChunkResponse{
+		SnapshotID: req.SnapshotID,
+		Index:      req.Index,
+		Data:       data,
+	})
+}
+
+// Sync discovers the latest snapshot above the given height from the peers, downloads
+// it into the given directory and returns the path of the snapshot file. The snapshot
+// itself still needs to be validated before being imported
+func (ssm *StateSyncManager) Sync(ctx context.Context, minHeight uint64, downloadDir string) (string, error) {
+	ssm.mutex.Lock()
+	if ssm.syncing {
+		ssm.mutex.Unlock()
+		return "", errors.New("State sync is already in progress")
+	}
+	ssm.syncing = true
+	ssm.offers = make(chan peerOffer, offerQueueSize)
+	ssm.mutex.Unlock()
+
+	defer func() {
+		ssm.mutex.Lock()
+		ssm.syncing = false
+		ssm.mutex.Unlock()
+	}()
+
+	offer, peerIDs, err := ssm.discoverSnapshot(ctx, minHeight)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(downloadDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	filePath := path.Join(downloadDir, snapshotFilePrefix+fmt.Sprintf("%v-%v", offer.Height, offer.ID().Hex()))
+	logger.Infof("Downloading snapshot at height %v, block: %v, size: %v bytes, from %v peers",
+		offer.Height, offer.BlockHash.Hex(), offer.FileSize, len(peerIDs))
+
+	if err := ssm.download(ctx, offer, peerIDs, filePath); err != nil {
+		return "", err
+	}
+
+	logger.Infof("Downloaded snapshot: %v", filePath)
+	return filePath, nil
+}
+
+// discoverSnapshot queries the peers until it finds the snapshot taken at the trusted block
+func (ssm *StateSyncManager) discoverSnapshot(ctx context.Context, minHeight uint64) (*SnapshotOffer, []string, error) {
+	trustedBlockHash := viper.GetString(common.CfgSyncStateSyncTrustedBlockHash)
+	if trustedBlockHash == "" {
+		return nil, nil, fmt.Errorf("State sync requires %v", common.CfgSyncStateSyncTrustedBlockHash)
+	}
+
+	for {
+		ssm.dispatcher.Send([]string{}, common.ChannelIDSnapshot, SnapshotQuery{ChainID: ssm.chainID})
+
+		offers := make(map[common.Hash]*SnapshotOffer)
+		offerPeers := make(map[common.Hash][]string)
+		timer := time.NewTimer(offerCollectionTime)
+	collect:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, nil, ctx.Err()
+			case <-timer.C:
+				break collect
+			case po := <-ssm.offers:
+				offer := po.offer
+				if offer.ChainID != ssm.chainID || offer.Height <= minHeight {
+					continue
+				}
+				if offer.BlockHash != common.HexToHash(trustedBlockHash) {
+					continue
+				}
+				if err := offer.Validate(); err != nil {
+					logger.Debugf("Ignored invalid snapshot offer from %v: %v", po.peerID, err)
+					continue
+				}
+				id := offer.ID()
+				offers[id] = &offer
+				offerPeers[id] = append(offerPeers[id], po.peerID)
+			}
+		}
+
+		var best *SnapshotOffer
+		var bestID common.Hash
+		for id, offer := range offers {
+			if best == nil || offer.Height > best.Height ||
+				(offer.Height == best.Height && len(offerPeers[id]) > len(offerPeers[bestID])) {
+				best = offer
+				bestID = id
+			}
+		}
+		if best != nil {
+			return best, offerPeers[bestID], nil
+		}
+		logger.Infof("No snapshot above height %v is offered by the peers yet, retrying...", minHeight)
+	}
+}
+
+// download fetches all the chunks of the snapshot in parallel, verifying each of them
+// against the chunk hashes in the offer. Chunks already downloaded are skipped
+func (ssm *StateSyncManager) download(ctx context.Context, offer *SnapshotOffer, peerIDs []string, filePath string) error {
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	indices := make(chan uint64, len(offer.ChunkHashes))
+	for index := range offer.ChunkHashes {
+		if hasValidChunk(file, offer, uint64(index)) {
+			continue
+		}
+		indices <- uint64(index)
+	}
+	close(indices)
+	logger.Infof("%v of %v chunks to download", len(indices), len(offer.ChunkHashes))
+
+	sort.Strings(peerIDs)
+	if len(peerIDs) > maxDownloadPeers {
+		peerIDs = peerIDs[:maxDownloadPeers]
+	}
+
+	wg := &sync.WaitGroup{}
+	errs := make(chan error, len(peerIDs))
+	for i := range peerIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for index := range indices {
+				if err := ssm.fetchChunk(ctx, offer, peerIDs, i, index, file); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err, ok := <-errs; ok {
+		return err
+	}
+	return file.Truncate(int64(offer.FileSize))
+}
+
+// fetchChunk downloads the chunk with the given index, starting from the peer at the given
+// position and moving on to the next peer on timeout or invalid data
+func (ssm *StateSyncManager) fetchChunk(ctx context.Context, offer *SnapshotOffer, peerIDs []string, peerIdx int, index uint64, file *os.File) error {
+	snapshotID := offer.ID()
+	ch := make(chan ChunkResponse, chunkResponseBufSize)
+
+	ssm.mutex.Lock()
+	ssm.pendingChunks[index] = ch
+	ssm.mutex.Unlock()
+	defer func() {
+		ssm.mutex.Lock()
+		delete(ssm.pendingChunks, index)
+		ssm.mutex.Unlock()
+	}()
+
+	for attempt := 0; attempt < maxChunkAttempts*len(peerIDs); attempt++ {
+		peerID := peerIDs[(peerIdx+attempt)%len(peerIDs)]
+		ssm.dispatcher.Send([]string{peerID}, common.ChannelIDSnapshot, ChunkRequest{SnapshotID: snapshotID, Index: index})
+
+		timer := time.NewTimer(chunkRequestTimeout)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+				logger.Debugf("Timed out waiting for chunk %v from peer %v", index, peerID)
+				break wait
+			case resp := <-ch:
+				if resp.SnapshotID != snapshotID {
+					continue
+				}
+				if uint64(len(resp.Data)) != offer.ChunkLength(index) || crypto.Keccak256Hash(resp.Data) != offer.ChunkHashes[index] {
+					logger.Warnf("Received invalid chunk %v from peer %v", index, peerID)
+					ssm.dispatcher.ReportMisbehavior(peerID, p2ptypes.MisbehaviorMalformedMessage)
+					timer.Stop()
+					break wait
+				}
+				timer.Stop()
+				offset, err := offer.ChunkOffset(index)
+				if err != nil {
+					return err
+				}
+				_, err = file.WriteAt(resp.Data, offset)
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("Failed to download chunk %v of snapshot %v", index, snapshotID.Hex())
+}
+
+func hasValidChunk(file *os.File, offer *SnapshotOffer, index uint64) bool {
+	offset, err := offer.ChunkOffset(index)
+	if err != nil {
+		return false
+	}
+	data := make([]byte, offer.ChunkLength(index))
+	n, err := file.ReadAt(data, offset)
+	if err != nil || uint64(n) != uint64(len(data)) {
+		return false
+	}
+	return crypto.Keccak256Hash(data) == offer.ChunkHashes[index]
+}
@@ -0,0 +1,175 @@
+package statesync
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/snapshot"
+)
+
+const (
+	snapshotFilePrefix = "theta_snapshot-"
+	defaultChunkSize   = 4 * 1024 * 1024 // 4 MB
+)
+
+//
+// SnapshotProvider serves the latest snapshot under the given directory to the peers
+//
+type SnapshotProvider struct {
+	mutex *sync.Mutex
+
+	dir       string
+	chunkSize uint64
+
+	// Cached offer of the latest snapshot
+	filePath   string
+	modTime    time.Time
+	offer      *SnapshotOffer
+	snapshotID common.Hash
+}
+
+// NewSnapshotProvider creates an instance of the SnapshotProvider
+func NewSnapshotProvider(dir string) *SnapshotProvider {
+	return &SnapshotProvider{
+		mutex:     &sync.Mutex{},
+		dir:       dir,
+		chunkSize: defaultChunkSize,
+	}
+}
+
+// GetOffer returns the offer of the latest snapshot, or nil if there is no snapshot to serve
+func (sp *SnapshotProvider) GetOffer() (*SnapshotOffer, error) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	filePath, err := sp.latestSnapshotFile()
+	if err != nil || filePath == "" {
+		return nil, err
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if filePath == sp.filePath && info.ModTime().Equal(sp.modTime) {
+		return sp.offer, nil
+	}
+
+	header := snapshot.LoadSnapshotCheckpointHeader(filePath)
+	if header == nil {
+		return nil, fmt.Errorf("Failed to load the header of snapshot %v", filePath)
+	}
+	chunkHashes, err := hashChunks(filePath, sp.chunkSize)
+	if err != nil {
+		return nil, err
+	}
+
+	offer := &SnapshotOffer{
+		ChainID:     header.ChainID,
+		Height:      header.Height,
+		BlockHash:   header.Hash(),
+		FileSize:    uint64(info.Size()),
+		ChunkSize:   sp.chunkSize,
+		ChunkHashes: chunkHashes,
+	}
+	sp.filePath = filePath
+	sp.modTime = info.ModTime()
+	sp.offer = offer
+	sp.snapshotID = offer.ID()
+
+	logger.Infof("Serving snapshot %v, height: %v, ID: %v", filePath, offer.Height, sp.snapshotID.Hex())
+
+	return offer, nil
+}
+
+// ReadChunk reads the chunk with the given index of the snapshot currently offered
+func (sp *SnapshotProvider) ReadChunk(snapshotID common.Hash, index uint64) (common.Bytes, error) {
+	sp.mutex.Lock()
+	defer sp.mutex.Unlock()
+
+	if sp.offer == nil || snapshotID != sp.snapshotID {
+		return nil, fmt.Errorf("Snapshot %v is not available", snapshotID.Hex())
+	}
+	offset, err := sp.offer.ChunkOffset(index)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(sp.filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data := make([]byte, sp.offer.ChunkLength(index))
+	_, err = file.ReadAt(data, offset)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// latestSnapshotFile returns the path of the snapshot with the highest height under the directory
+func (sp *SnapshotProvider) latestSnapshotFile() (string, error) {
+	files, err := ioutil.ReadDir(sp.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	latestFile := ""
+	latestHeight := uint64(0)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), snapshotFilePrefix) {
+			continue
+		}
+		// File name format: theta_snapshot-<height>-<state hash>-<date>
+		parts := strings.Split(file.Name(), "-")
+		if len(parts) < 2 {
+			continue
+		}
+		height, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if latestFile == "" || height > latestHeight {
+			latestFile = path.Join(sp.dir, file.Name())
+			latestHeight = height
+		}
+	}
+	return latestFile, nil
+}
+
+func hashChunks(filePath string, chunkSize uint64) ([]common.Hash, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	chunkHashes := []common.Hash{}
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(file, buf)
+		if n > 0 {
+			chunkHashes = append(chunkHashes, crypto.Keccak256Hash(buf[:n]))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return chunkHashes, nil
+}
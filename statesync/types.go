@@ -0,0 +1,169 @@
+package statesync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/rlp"
+)
+
+// The limits of the snapshot offers accepted from the peers, which bound the memory and the disk
+// space the download takes
+const (
+	minChunkSize        = 64 * 1024                // 64 KB
+	maxChunkSize        = 16 * 1024 * 1024         // 16 MB
+	maxSnapshotFileSize = 512 * 1024 * 1024 * 1024 // 512 GB
+	maxSnapshotChunks   = 1 << 18
+)
+
+// MessageIDEnum defines the message types exchanged on the snapshot channel
+type MessageIDEnum uint8
+
+const (
+	MessageIDSnapshotQuery MessageIDEnum = iota
+	MessageIDSnapshotOffer
+	MessageIDChunkRequest
+	MessageIDChunkResponse
+)
+
+// SnapshotQuery asks the peers for the latest snapshot they can serve
+type SnapshotQuery struct {
+	ChainID string
+}
+
+// SnapshotOffer advertises a snapshot a peer can serve. The snapshot file is split
+// into chunks of ChunkSize bytes, each chunk can be verified with its hash
+type SnapshotOffer struct {
+	ChainID     string
+	Height      uint64
+	BlockHash   common.Hash
+	FileSize    uint64
+	ChunkSize   uint64
+	ChunkHashes []common.Hash
+}
+
+// ID returns the identifier of the snapshot, which commits to all the chunk hashes
+func (so *SnapshotOffer) ID() common.Hash {
+	raw, _ := rlp.EncodeToBytes(so)
+	return crypto.Keccak256Hash(raw)
+}
+
+// Validate checks that the chunk layout is consistent with the file size, and within the limits
+func (so *SnapshotOffer) Validate() error {
+	if so.ChunkSize == 0 || so.FileSize == 0 {
+		return errors.New("empty snapshot")
+	}
+	if so.ChunkSize < minChunkSize || so.ChunkSize > maxChunkSize {
+		return fmt.Errorf("chunk size %v out of range [%v, %v]", so.ChunkSize, minChunkSize, maxChunkSize)
+	}
+	if so.FileSize > maxSnapshotFileSize {
+		return fmt.Errorf("file size %v exceeds the limit %v", so.FileSize, maxSnapshotFileSize)
+	}
+	numChunks := (so.FileSize-1)/so.ChunkSize + 1
+	if numChunks > maxSnapshotChunks {
+		return fmt.Errorf("%v chunks exceed the limit %v", numChunks, maxSnapshotChunks)
+	}
+	if uint64(len(so.ChunkHashes)) != numChunks {
+		return fmt.Errorf("expected %v chunk hashes, got %v", numChunks, len(so.ChunkHashes))
+	}
+	return nil
+}
+
+// ChunkOffset returns the offset of the chunk with the given index in the snapshot file
+func (so *SnapshotOffer) ChunkOffset(index uint64) (int64, error) {
+	if index >= uint64(len(so.ChunkHashes)) {
+		return 0, fmt.Errorf("chunk index %v out of range", index)
+	}
+	if so.ChunkSize != 0 && index > math.MaxInt64/so.ChunkSize {
+		return 0, fmt.Errorf("offset of chunk %v overflows", index)
+	}
+	offset := index * so.ChunkSize
+	if offset >= so.FileSize {
+		return 0, fmt.Errorf("offset of chunk %v exceeds the file size", index)
+	}
+	return int64(offset), nil
+}
+
+// ChunkLength returns the expected length of the chunk with the given index, which is
+// assumed to be in range, see ChunkOffset
+func (so *SnapshotOffer) ChunkLength(index uint64) uint64 {
+	offset := index * so.ChunkSize
+	if so.FileSize-offset < so.ChunkSize {
+		return so.FileSize - offset
+	}
+	return so.ChunkSize
+}
+
+// ChunkRequest requests a chunk of the given snapshot
+type ChunkRequest struct {
+	SnapshotID common.Hash
+	Index      uint64
+}
+
+// ChunkResponse carries a chunk of the given snapshot
+type ChunkResponse struct {
+	SnapshotID common.Hash
+	Index      uint64
+	Data       common.Bytes
+}
+
+func encodeMessage(message interface{}) (common.Bytes, error) {
+	var buf bytes.Buffer
+	var msgID MessageIDEnum
+	switch message.(type) {
+	case SnapshotQuery:
+		msgID = MessageIDSnapshotQuery
+	case SnapshotOffer:
+		msgID = MessageIDSnapshotOffer
+	case ChunkRequest:
+		msgID = MessageIDChunkRequest
+	case ChunkResponse:
+		msgID = MessageIDChunkResponse
+	default:
+		return nil, errors.New("Unsupported message type")
+	}
+	err := rlp.Encode(&buf, msgID)
+	if err != nil {
+		return nil, err
+	}
+	err = rlp.Encode(&buf, message)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeMessage(raw common.Bytes) (interface{}, error) {
+	if len(raw) <= 1 {
+		return nil, fmt.Errorf("Invalid message size")
+	}
+	var msgID MessageIDEnum
+	err := rlp.DecodeBytes(raw[:1], &msgID)
+	if err != nil {
+		return nil, err
+	}
+	switch msgID {
+	case MessageIDSnapshotQuery:
+		data := SnapshotQuery{}
+		err = rlp.DecodeBytes(raw[1:], &data)
+		return data, err
+	case MessageIDSnapshotOffer:
+		data := SnapshotOffer{}
+		err = rlp.DecodeBytes(raw[1:], &data)
+		return data, err
+	case MessageIDChunkRequest:
+		data := ChunkRequest{}
+		err = rlp.DecodeBytes(raw[1:], &data)
+		return data, err
+	case MessageIDChunkResponse:
+		data := ChunkResponse{}
+		err = rlp.DecodeBytes(raw[1:], &data)
+		return data, err
+	default:
+		return nil, fmt.Errorf("Unknown message ID: %v", msgID)
+	}
+}
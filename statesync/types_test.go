@@ -0,0 +1,129 @@
+package statesync
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+func TestSnapshotMessageEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	offer := SnapshotOffer{
+		ChainID:     "privatenet",
+		Height:      1000,
+		BlockHash:   common.HexToHash("0x01"),
+		FileSize:    10,
+		ChunkSize:   4,
+		ChunkHashes: []common.Hash{common.HexToHash("0x02"), common.HexToHash("0x03"), common.HexToHash("0x04")},
+	}
+	raw, err := encodeMessage(offer)
+	assert.Nil(err)
+	decoded, err := decodeMessage(raw)
+	assert.Nil(err)
+	decodedOffer, ok := decoded.(SnapshotOffer)
+	assert.True(ok)
+	assert.Equal(offer.ID(), decodedOffer.ID())
+
+	resp := ChunkResponse{SnapshotID: offer.ID(), Index: 2, Data: common.Bytes("ab")}
+	raw, err = encodeMessage(resp)
+	assert.Nil(err)
+	decoded, err = decodeMessage(raw)
+	assert.Nil(err)
+	assert.Equal(resp, decoded)
+
+	_, err = encodeMessage("unsupported")
+	assert.NotNil(err)
+}
+
+func TestSnapshotOfferChunks(t *testing.T) {
+	assert := assert.New(t)
+
+	offer := SnapshotOffer{
+		FileSize:    2*minChunkSize + 2,
+		ChunkSize:   minChunkSize,
+		ChunkHashes: make([]common.Hash, 3),
+	}
+	assert.Nil(offer.Validate())
+	assert.Equal(uint64(minChunkSize), offer.ChunkLength(0))
+	assert.Equal(uint64(2), offer.ChunkLength(2))
+
+	offset, err := offer.ChunkOffset(2)
+	assert.Nil(err)
+	assert.Equal(int64(2*minChunkSize), offset)
+	_, err = offer.ChunkOffset(3)
+	assert.NotNil(err)
+
+	offer.ChunkHashes = make([]common.Hash, 2)
+	assert.NotNil(offer.Validate())
+
+	offer.ChunkSize = 0
+	assert.NotNil(offer.Validate())
+}
+
+func TestSnapshotOfferLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	offer := SnapshotOffer{
+		FileSize:    maxChunkSize + 1,
+		ChunkSize:   maxChunkSize + 1,
+		ChunkHashes: make([]common.Hash, 1),
+	}
+	assert.NotNil(offer.Validate())
+
+	offer.FileSize = maxSnapshotFileSize + 1
+	offer.ChunkSize = maxChunkSize
+	offer.ChunkHashes = make([]common.Hash, maxSnapshotFileSize/maxChunkSize+1)
+	assert.NotNil(offer.Validate())
+
+	offer.FileSize = maxSnapshotChunks*minChunkSize + 1
+	offer.ChunkSize = minChunkSize
+	offer.ChunkHashes = make([]common.Hash, maxSnapshotChunks+1)
+	assert.NotNil(offer.Validate())
+
+	// The offsets of the forged layouts do not overflow
+	offer = SnapshotOffer{
+		FileSize:    ^uint64(0),
+		ChunkSize:   1 << 62,
+		ChunkHashes: make([]common.Hash, 4),
+	}
+	assert.NotNil(offer.Validate())
+	_, err := offer.ChunkOffset(3)
+	assert.NotNil(err)
+}
+
+func TestSnapshotProviderFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "statesync")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	content := []byte("0123456789")
+	assert.Nil(ioutil.WriteFile(path.Join(dir, "theta_snapshot-100-0xabc-2021-01-01"), content, 0600))
+	assert.Nil(ioutil.WriteFile(path.Join(dir, "theta_snapshot-200-0xdef-2021-01-02"), content, 0600))
+	assert.Nil(ioutil.WriteFile(path.Join(dir, "unrelated-300"), content, 0600))
+
+	sp := NewSnapshotProvider(dir)
+	latest, err := sp.latestSnapshotFile()
+	assert.Nil(err)
+	assert.Equal(path.Join(dir, "theta_snapshot-200-0xdef-2021-01-02"), latest)
+
+	chunkHashes, err := hashChunks(latest, 4)
+	assert.Nil(err)
+	assert.Equal([]common.Hash{
+		crypto.Keccak256Hash([]byte("0123")),
+		crypto.Keccak256Hash([]byte("4567")),
+		crypto.Keccak256Hash([]byte("89")),
+	}, chunkHashes)
+
+	// Not a valid snapshot, nothing to offer
+	offer, err := sp.GetOffer()
+	assert.NotNil(err)
+	assert.Nil(offer)
+}
@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"github.com/thetatoken/theta/store"
+	"github.com/thetatoken/theta/store/database"
+)
+
+//
+// OverlayDatabase layers a writable database on top of a read-only one. The reads fall back to
+// the lower database for the keys absent from the upper one, while all the writes, deletions and
+// reference counts go to the upper database, so that the lower database is never modified. It
+// allows replaying the blocks of a node against an imported state without touching the node's
+// own database. Note that a key deleted from the upper database may still be read from the lower.
+//
+type OverlayDatabase struct {
+	upper database.Database
+	lower database.Database
+}
+
+// NewOverlayDatabase creates an OverlayDatabase writing to upper and falling back to lower for the reads
+func NewOverlayDatabase(upper, lower database.Database) *OverlayDatabase {
+	return &OverlayDatabase{
+		upper: upper,
+		lower: lower,
+	}
+}
+
+func (db *OverlayDatabase) Put(key []byte, value []byte) error {
+	return db.upper.Put(key, value)
+}
+
+func (db *OverlayDatabase) Has(key []byte) (bool, error) {
+	has, err := db.upper.Has(key)
+	if err != nil || has {
+		return has, err
+	}
+	return db.lower.Has(key)
+}
+
+func (db *OverlayDatabase) Get(key []byte) ([]byte, error) {
+	value, err := db.upper.Get(key)
+	if err == store.ErrKeyNotFound {
+		return db.lower.Get(key)
+	}
+	return value, err
+}
+
+func (db *OverlayDatabase) Delete(key []byte) error {
+	return db.upper.Delete(key)
+}
+
+// Reference increments the reference count of the key in the upper database. The reference counts
+// of the keys only present in the lower database are not tracked.
+func (db *OverlayDatabase) Reference(key []byte) error {
+	err := db.upper.Reference(key)
+	if err == store.ErrKeyNotFound {
+		if has, _ := db.lower.Has(key); has {
+			return nil
+		}
+	}
+	return err
+}
+
+// Dereference decrements the reference count of the key in the upper database. The reference counts
+// of the keys only present in the lower database are not tracked.
+func (db *OverlayDatabase) Dereference(key []byte) error {
+	err := db.upper.Dereference(key)
+	if err == store.ErrKeyNotFound {
+		if has, _ := db.lower.Has(key); has {
+			return nil
+		}
+	}
+	return err
+}
+
+func (db *OverlayDatabase) CountReference(key []byte) (int, error) {
+	return db.upper.CountReference(key)
+}
+
+// Close closes the upper database, the lower database is left to its owner
+func (db *OverlayDatabase) Close() {
+	db.upper.Close()
+}
+
+func (db *OverlayDatabase) NewBatch() database.Batch {
+	return db.upper.NewBatch()
+}
@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thetatoken/theta/store"
+)
+
+func TestOverlayDatabase(t *testing.T) {
+	assert := assert.New(t)
+
+	lower := NewMemDatabase()
+	lower.Put([]byte("k1"), []byte("lower1"))
+	lower.Put([]byte("k2"), []byte("lower2"))
+	upper := NewMemDatabase()
+	db := NewOverlayDatabase(upper, lower)
+
+	// Reads fall back to the lower database
+	value, err := db.Get([]byte("k1"))
+	assert.Nil(err)
+	assert.Equal([]byte("lower1"), value)
+	has, _ := db.Has([]byte("k2"))
+	assert.True(has)
+	_, err = db.Get([]byte("k3"))
+	assert.Equal(store.ErrKeyNotFound, err)
+
+	// Writes go to the upper database only
+	assert.Nil(db.Put([]byte("k1"), []byte("upper1")))
+	batch := db.NewBatch()
+	batch.Put([]byte("k3"), []byte("upper3"))
+	batch.Reference([]byte("k3"))
+	assert.Nil(batch.Write())
+
+	value, _ = db.Get([]byte("k1"))
+	assert.Equal([]byte("upper1"), value)
+	value, _ = db.Get([]byte("k3"))
+	assert.Equal([]byte("upper3"), value)
+	ref, _ := db.CountReference([]byte("k3"))
+	assert.Equal(1, ref)
+	assert.Nil(db.Reference([]byte("k2")))
+
+	value, _ = lower.Get([]byte("k1"))
+	assert.Equal([]byte("lower1"), value)
+	has, _ = lower.Has([]byte("k3"))
+	assert.False(has)
+}
@@ -85,6 +85,11 @@ func (store *TreeStore) ProveVCP(vcpKey []byte, vp *core.VCPProof) error {
 	return store.Trie.Prove(vcpKey, 0, vp)
 }
 
+// Prove collects the Merkle proof of the given key into the proof.
+func (store *TreeStore) Prove(key []byte, proof *core.VCPProof) error {
+	return store.Trie.Prove(key, 0, proof)
+}
+
 // Set sets value of given key.
 func (store *TreeStore) Set(key, value common.Bytes) {
 	store.Trie.Update(key, value)
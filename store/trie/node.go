@@ -54,16 +54,63 @@ var nilValueNode = valueNode(nil)
 
 // EncodeRLP encodes a full node into the consensus RLP format.
 func (n *fullNode) EncodeRLP(w io.Writer) error {
-	var nodes [17]node
+	b, err := appendNodeRLP(nil, n)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
 
-	for i, child := range &n.Children {
-		if child != nil {
-			nodes[i] = child
-		} else {
-			nodes[i] = nilValueNode
+// EncodeRLP encodes a short node into the consensus RLP format.
+func (n *shortNode) EncodeRLP(w io.Writer) error {
+	b, err := appendNodeRLP(nil, n)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// appendNodeRLP appends the RLP encoding of the node to b. The nodes are encoded on every
+// hash and commit, so the common node types are appended directly rather than through
+// reflection, producing the same bytes.
+func appendNodeRLP(b []byte, n node) ([]byte, error) {
+	var err error
+	switch n := n.(type) {
+	case nil:
+		return append(b, rlp.EmptyList...), nil
+	case hashNode:
+		return rlp.AppendString(b, n), nil
+	case valueNode:
+		return rlp.AppendString(b, n), nil
+	case *fullNode:
+		if n == nil {
+			return append(b, rlp.EmptyList...), nil
+		}
+		content := make([]byte, 0, 17*33)
+		for _, child := range &n.Children {
+			if child == nil {
+				child = nilValueNode
+			}
+			if content, err = appendNodeRLP(content, child); err != nil {
+				return b, err
+			}
 		}
+		return rlp.AppendList(b, content), nil
+	case *shortNode:
+		if n == nil {
+			return append(b, rlp.EmptyList...), nil
+		}
+		content := make([]byte, 0, len(n.Key)+40)
+		content = rlp.AppendString(content, n.Key)
+		if content, err = appendNodeRLP(content, n.Val); err != nil {
+			return b, err
+		}
+		return rlp.AppendList(b, content), nil
+	default:
+		return rlp.AppendValue(b, n)
 	}
-	return rlp.Encode(w, nodes)
 }
 
 func (n *fullNode) copy() *fullNode   { copy := *n; return &copy }
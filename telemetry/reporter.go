@@ -0,0 +1,176 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/consensus"
+	dp "github.com/thetatoken/theta/dispatcher"
+	"github.com/thetatoken/theta/version"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "telemetry"})
+
+const (
+	requestTimeout   = 10 * time.Second
+	minInterval      = 10 * time.Second
+	maxResponseBytes = 4096
+)
+
+// Report is the JSON payload POSTed to the telemetry URL. It carries no IP address nor any
+// account information besides the node ID.
+type Report struct {
+	NodeID          string            `json:"node_id"`
+	Name            string            `json:"name"`
+	Region          string            `json:"region"`
+	ChainID         string            `json:"chain_id"`
+	Version         string            `json:"version"`
+	GitHash         string            `json:"git_hash"`
+	ProtocolVersion string            `json:"protocol_version"`
+	OS              string            `json:"os"`
+	Arch            string            `json:"arch"`
+	Height          common.JSONUint64 `json:"height"` // the last finalized height
+	Syncing         bool              `json:"syncing"`
+	NumPeers        int               `json:"num_peers"`
+	Timestamp       common.JSONUint64 `json:"timestamp"` // unix time in seconds
+}
+
+//
+// Reporter periodically POSTs the version, height, peer count and region of the node to the
+// telemetry collector, so that the community can observe the network health and the version
+// adoption. It is opt-in, and only runs if telemetry.enabled is set. A failed report is not
+// retried, the next one is sent on the next tick.
+//
+type Reporter struct {
+	consensus  *consensus.ConsensusEngine
+	dispatcher *dp.Dispatcher
+	chainID    string
+
+	url      string
+	name     string
+	region   string
+	interval time.Duration
+	client   *http.Client
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewReporter creates a Reporter with the configured URL and interval
+func NewReporter(chainID string, consensus *consensus.ConsensusEngine, dispatcher *dp.Dispatcher) *Reporter {
+	interval := time.Duration(viper.GetInt(common.CfgTelemetryIntervalSecs)) * time.Second
+	if interval < minInterval {
+		interval = minInterval
+	}
+	return &Reporter{
+		consensus:  consensus,
+		dispatcher: dispatcher,
+		chainID:    chainID,
+		url:        viper.GetString(common.CfgTelemetryURL),
+		name:       viper.GetString(common.CfgTelemetryName),
+		region:     viper.GetString(common.CfgTelemetryRegion),
+		interval:   interval,
+		client:     &http.Client{Timeout: requestTimeout},
+		wg:         &sync.WaitGroup{},
+	}
+}
+
+// Start starts the reporting
+func (r *Reporter) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	r.ctx = c
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go r.mainLoop()
+}
+
+// Stop stops the reporting
+func (r *Reporter) Stop() {
+	r.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (r *Reporter) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Reporter) mainLoop() {
+	defer r.wg.Done()
+
+	logger.Infof("Reporting telemetry to %v every %v", r.url, r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if err := r.send(r.BuildReport()); err != nil && r.ctx.Err() == nil {
+			logger.Warnf("Failed to report telemetry to %v: %v", r.url, err)
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// BuildReport returns the current report of the node
+func (r *Reporter) BuildReport() *Report {
+	report := &Report{
+		NodeID:          r.consensus.ID(),
+		Name:            r.name,
+		Region:          r.region,
+		ChainID:         r.chainID,
+		Version:         version.Version,
+		GitHash:         version.GitHash,
+		ProtocolVersion: viper.GetString(common.CfgP2PVersion),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		Syncing:         !r.consensus.HasSynced(),
+		NumPeers:        len(r.dispatcher.Peers(true)),
+		Timestamp:       common.JSONUint64(time.Now().Unix()),
+	}
+	if block := r.consensus.GetLastFinalizedBlock(); block != nil {
+		report.Height = common.JSONUint64(block.Height)
+	}
+	return report
+}
+
+func (r *Reporter) send(report *Report) error {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", r.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(r.ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Unexpected status %v", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	assert := assert.New(t)
+
+	received := make(chan *Report, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := &Report{}
+		if err := json.NewDecoder(r.Body).Decode(report); err != nil || r.Method != "POST" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- report
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	r := &Reporter{
+		url:    server.URL,
+		client: &http.Client{Timeout: time.Second},
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	defer r.cancel()
+
+	assert.Nil(r.send(&Report{NodeID: "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", Region: "eu-west", Height: 42, NumPeers: 7}))
+	report := <-received
+	assert.Equal("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab", report.NodeID)
+	assert.Equal("eu-west", report.Region)
+	assert.Equal(uint64(42), uint64(report.Height))
+	assert.Equal(7, report.NumPeers)
+
+	// Rejected reports are reported as errors
+	failing := httptest.NewServer(http.NotFoundHandler())
+	defer failing.Close()
+	r.url = failing.URL
+	assert.NotNil(r.send(&Report{NodeID: "0x2E833968E5bB786Ae419c4d13189fB081Cc43bab"}))
+}
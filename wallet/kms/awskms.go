@@ -0,0 +1,246 @@
+package kms
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+var _ KeyProvider = (*AWSKMSProvider)(nil)
+
+const (
+	awsKMSService     = "kms"
+	awsKMSContentType = "application/x-amz-json-1.1"
+	awsTimeFormat     = "20060102T150405Z"
+	awsDateFormat     = "20060102"
+)
+
+// AWSCredentials is the credentials the requests to AWS are signed with
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSCredentialsFromEnv reads the credentials from the standard AWS environment variables
+func AWSCredentialsFromEnv() AWSCredentials {
+	return AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// AWSKMSProvider signs with an asymmetric AWS KMS key with the ECC_SECG_P256K1 key spec.
+type AWSKMSProvider struct {
+	region      string
+	keyID       string
+	endpoint    string
+	credentials AWSCredentials
+	client      *http.Client
+	now         func() time.Time
+
+	mu     *sync.Mutex
+	pubKey *crypto.PublicKey
+}
+
+// NewAWSKMSProvider creates an instance of AWSKMSProvider. The endpoint defaults to the
+// regional KMS endpoint if empty.
+func NewAWSKMSProvider(region, keyID, endpoint string, credentials AWSCredentials) (*AWSKMSProvider, error) {
+	if region == "" || keyID == "" {
+		return nil, fmt.Errorf("AWS region and KMS key ID are required")
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS credentials are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com/", region)
+	}
+	return &AWSKMSProvider{
+		region:      region,
+		keyID:       keyID,
+		endpoint:    endpoint,
+		credentials: credentials,
+		client:      newHTTPClient(),
+		now:         time.Now,
+		mu:          &sync.Mutex{},
+	}, nil
+}
+
+// PublicKey implements the KeyProvider interface.
+func (p *AWSKMSProvider) PublicKey() (*crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pubKey != nil {
+		return p.pubKey, nil
+	}
+
+	req := map[string]string{"KeyId": p.keyID}
+	var resp struct {
+		PublicKey string
+		KeySpec   string
+	}
+	if err := p.request("GetPublicKey", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.KeySpec != "" && resp.KeySpec != "ECC_SECG_P256K1" {
+		return nil, fmt.Errorf("KMS key %v has key spec %v, ECC_SECG_P256K1 is required", p.keyID, resp.KeySpec)
+	}
+	der, err := base64.StdEncoding.DecodeString(resp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode KMS public key: %v", err)
+	}
+	pubKey, err := parsePublicKeyDER(der)
+	if err != nil {
+		return nil, err
+	}
+	p.pubKey = pubKey
+	return pubKey, nil
+}
+
+// Sign implements the KeyProvider interface.
+func (p *AWSKMSProvider) Sign(msg common.Bytes) (*crypto.Signature, error) {
+	pubKey, err := p.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]string{
+		"KeyId":            p.keyID,
+		"Message":          base64.StdEncoding.EncodeToString(crypto.Keccak256(msg)),
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": "ECDSA_SHA_256",
+	}
+	var resp struct {
+		Signature string
+	}
+	if err := p.request("Sign", req, &resp); err != nil {
+		return nil, err
+	}
+	der, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode KMS signature: %v", err)
+	}
+	return signatureFromDER(msg, der, pubKey)
+}
+
+func (p *AWSKMSProvider) request(action string, body interface{}, result interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", p.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", awsKMSContentType)
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	p.signRequest(req, reqBody, p.now().UTC())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("KMS request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("KMS request failed with status %v: %s", resp.StatusCode, respBody)
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+// signRequest signs the request with AWS Signature Version 4.
+func (p *AWSKMSProvider) signRequest(req *http.Request, body []byte, t time.Time) {
+	amzDate := t.Format(awsTimeFormat)
+	date := t.Format(awsDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.credentials.SessionToken)
+	}
+
+	headers := map[string]string{
+		"content-type": req.Header.Get("Content-Type"),
+		"host":         req.URL.Host,
+		"x-amz-date":   amzDate,
+		"x-amz-target": req.Header.Get("X-Amz-Target"),
+	}
+	signedHeaders := []string{"content-type", "host", "x-amz-date"}
+	if p.credentials.SessionToken != "" {
+		headers["x-amz-security-token"] = p.credentials.SessionToken
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	signedHeaders = append(signedHeaders, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQueryString(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hexSHA256(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, p.region, awsKMSService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := awsSigningKey(p.credentials.SecretAccessKey, date, p.region, awsKMSService)
+	signature := hex.EncodeToString(hmacSHA256(signingKey, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.credentials.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// awsSigningKey derives the Signature Version 4 signing key
+func awsSigningKey(secret, date, region, service string) []byte {
+	kDate := hmacSHA256([]byte("AWS4"+secret), []byte(date))
+	kRegion := hmacSHA256(kDate, []byte(region))
+	kService := hmacSHA256(kRegion, []byte(service))
+	return hmacSHA256(kService, []byte("aws4_request"))
+}
+
+func canonicalQueryString(query url.Values) string {
+	// url.Values.Encode sorts by key, which is what Signature Version 4 requires
+	return strings.Replace(query.Encode(), "+", "%20", -1)
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,218 @@
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+// testKey signs digests the way the key management services do, i.e. with DER encoded
+// signatures which may have high S values.
+type testKey struct {
+	privKey *crypto.PrivateKey
+	ecdsa   *ecdsa.PrivateKey
+}
+
+func newTestKey(t *testing.T) *testKey {
+	privKey, _, err := crypto.GenerateKeyPair()
+	assert.Nil(t, err)
+	pubBytes := privKey.PublicKey().ToBytes()
+	return &testKey{
+		privKey: privKey,
+		ecdsa: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: crypto.S256(),
+				X:     new(big.Int).SetBytes(pubBytes[1:33]),
+				Y:     new(big.Int).SetBytes(pubBytes[33:65]),
+			},
+			D: privKey.D(),
+		},
+	}
+}
+
+func (k *testKey) publicKeyDER(t *testing.T) []byte {
+	spki := subjectPublicKeyInfo{PublicKey: asn1.BitString{Bytes: k.privKey.PublicKey().ToBytes(), BitLength: 65 * 8}}
+	spki.Algorithm.Algorithm = oidPublicKeyECDSA
+	spki.Algorithm.Parameters = oidNamedCurveS256K
+	der, err := asn1.Marshal(spki)
+	assert.Nil(t, err)
+	return der
+}
+
+func (k *testKey) signDER(t *testing.T, digest []byte, highS bool) []byte {
+	r, s, err := ecdsa.Sign(rand.Reader, k.ecdsa, digest)
+	assert.Nil(t, err)
+	n := crypto.S256().Params().N
+	if (s.Cmp(new(big.Int).Rsh(n, 1)) > 0) != highS {
+		s = new(big.Int).Sub(n, s)
+	}
+	der, err := asn1.Marshal(ecdsaSignature{R: r, S: s})
+	assert.Nil(t, err)
+	return der
+}
+
+func TestParsePublicKeyDER(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	pubKey, err := parsePublicKeyDER(key.publicKeyDER(t))
+	assert.Nil(err)
+	assert.Equal(key.privKey.PublicKey().Address(), pubKey.Address())
+
+	// P-256 keys are rejected
+	spki := subjectPublicKeyInfo{PublicKey: asn1.BitString{Bytes: key.privKey.PublicKey().ToBytes(), BitLength: 65 * 8}}
+	spki.Algorithm.Algorithm = oidPublicKeyECDSA
+	spki.Algorithm.Parameters = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+	der, err := asn1.Marshal(spki)
+	assert.Nil(err)
+	_, err = parsePublicKeyDER(der)
+	assert.NotNil(err)
+}
+
+func TestSignatureFromDER(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	address := key.privKey.PublicKey().Address()
+	msg := common.Bytes("hello")
+	digest := crypto.Keccak256(msg)
+
+	for _, highS := range []bool{false, true} {
+		sig, err := signatureFromDER(msg, key.signDER(t, digest, highS), key.privKey.PublicKey())
+		assert.Nil(err)
+		assert.True(sig.Verify(msg, address))
+	}
+
+	other := newTestKey(t)
+	_, err := signatureFromDER(msg, key.signDER(t, digest, false), other.privKey.PublicKey())
+	assert.NotNil(err)
+}
+
+func TestVaultTransitProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: key.publicKeyDER(t)})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/secp256k1/keys/validator":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"latest_version": 2,
+					"keys": map[string]interface{}{
+						"2": map[string]string{"public_key": string(pemKey)},
+					},
+				},
+			})
+		case r.Method == "POST" && r.URL.Path == "/v1/secp256k1/sign/validator":
+			var req struct {
+				Input     string `json:"input"`
+				Prehashed bool   `json:"prehashed"`
+			}
+			assert.Nil(json.NewDecoder(r.Body).Decode(&req))
+			assert.True(req.Prehashed)
+			digest, err := base64.StdEncoding.DecodeString(req.Input)
+			assert.Nil(err)
+			sig := base64.StdEncoding.EncodeToString(key.signDER(t, digest, true))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]string{"signature": "vault:v2:" + sig},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewVaultTransitProvider(server.URL, "token", "secp256k1", "validator")
+	assert.Nil(err)
+	wallet, err := NewWallet(provider)
+	assert.Nil(err)
+
+	address := key.privKey.PublicKey().Address()
+	addresses, err := wallet.List()
+	assert.Nil(err)
+	assert.Equal([]common.Address{address}, addresses)
+	assert.Nil(wallet.Unlock(address, "", nil))
+	assert.NotNil(wallet.Unlock(common.HexToAddress("b1"), "", nil))
+
+	msg := common.Bytes("tx sign bytes")
+	sig, err := wallet.Sign(address, msg)
+	assert.Nil(err)
+	assert.True(sig.Verify(msg, address))
+
+	provider, err = NewVaultTransitProvider(server.URL, "wrong token", "secp256k1", "validator")
+	assert.Nil(err)
+	_, err = provider.Sign(msg)
+	assert.NotNil(err)
+}
+
+func TestAWSKMSProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	key := newTestKey(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-target") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var req map[string]string
+		assert.Nil(json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal("alias/validator", req["KeyId"])
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			json.NewEncoder(w).Encode(map[string]string{
+				"KeyId":     req["KeyId"],
+				"KeySpec":   "ECC_SECG_P256K1",
+				"PublicKey": base64.StdEncoding.EncodeToString(key.publicKeyDER(t)),
+			})
+		case "TrentService.Sign":
+			assert.Equal("DIGEST", req["MessageType"])
+			digest, err := base64.StdEncoding.DecodeString(req["Message"])
+			assert.Nil(err)
+			json.NewEncoder(w).Encode(map[string]string{
+				"KeyId":     req["KeyId"],
+				"Signature": base64.StdEncoding.EncodeToString(key.signDER(t, digest, false)),
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewAWSKMSProvider("us-east-1", "alias/validator", server.URL, AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	assert.Nil(err)
+	pubKey, err := provider.PublicKey()
+	assert.Nil(err)
+	address := key.privKey.PublicKey().Address()
+	assert.Equal(address, pubKey.Address())
+
+	msg := common.Bytes("vote sign bytes")
+	sig, err := provider.Sign(msg)
+	assert.Nil(err)
+	assert.True(sig.Verify(msg, address))
+
+	_, err = NewAWSKMSProvider("us-east-1", "alias/validator", server.URL, AWSCredentials{})
+	assert.NotNil(err)
+}
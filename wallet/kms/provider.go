@@ -0,0 +1,130 @@
+package kms
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "kms"})
+
+const (
+	ProviderVault  = "vault"
+	ProviderAWSKMS = "awskms"
+
+	requestTimeout = 10 * time.Second
+)
+
+// KeyProvider signs with a secp256k1 key held by an external key management service,
+// so that the private key is never materialized on the local disk.
+type KeyProvider interface {
+	// PublicKey returns the public key of the key held by the service
+	PublicKey() (*crypto.PublicKey, error)
+
+	// Sign signs the Keccak256 hash of the message, and returns the signature in the
+	// [R || S || V] format, same as crypto.PrivateKey.Sign
+	Sign(msg common.Bytes) (*crypto.Signature, error)
+}
+
+// NewKeyProviderFromConfig creates the key provider specified in the config file, or
+// returns nil if no key provider is configured.
+func NewKeyProviderFromConfig() (KeyProvider, error) {
+	switch provider := viper.GetString(common.CfgKeyProvider); provider {
+	case "":
+		return nil, nil
+	case ProviderVault:
+		return NewVaultTransitProvider(viper.GetString(common.CfgKeyVaultAddress), viper.GetString(common.CfgKeyVaultToken),
+			viper.GetString(common.CfgKeyVaultTransitPath), viper.GetString(common.CfgKeyVaultKeyName))
+	case ProviderAWSKMS:
+		return NewAWSKMSProvider(viper.GetString(common.CfgKeyAWSKMSRegion), viper.GetString(common.CfgKeyAWSKMSKeyID),
+			viper.GetString(common.CfgKeyAWSKMSEndpoint), AWSCredentialsFromEnv())
+	default:
+		return nil, fmt.Errorf("Unsupported key provider: %v", provider)
+	}
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: requestTimeout}
+}
+
+// secp256k1 public keys in the X.509 SubjectPublicKeyInfo structure, as returned by the services
+type subjectPublicKeyInfo struct {
+	Algorithm struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier
+	}
+	PublicKey asn1.BitString
+}
+
+var (
+	oidPublicKeyECDSA  = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidNamedCurveS256K = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// parsePublicKeyDER parses a DER encoded SubjectPublicKeyInfo of a secp256k1 key. The standard
+// library does not support the curve, so the structure is decoded manually.
+func parsePublicKeyDER(der []byte) (*crypto.PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse public key: %v", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("Trailing data after public key")
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) || !spki.Algorithm.Parameters.Equal(oidNamedCurveS256K) {
+		return nil, fmt.Errorf("Not a secp256k1 key: algorithm %v, curve %v", spki.Algorithm.Algorithm, spki.Algorithm.Parameters)
+	}
+	return crypto.PublicKeyFromBytes(spki.PublicKey.Bytes)
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// signatureFromDER converts a DER encoded ECDSA signature of the Keccak256 hash of the message to
+// the [R || S || V] format. The services do not return the recovery ID, so it is found by trying
+// to recover the public key.
+func signatureFromDER(msg common.Bytes, der []byte, pubKey *crypto.PublicKey) (*crypto.Signature, error) {
+	var sig ecdsaSignature
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("Failed to parse signature: %v", err)
+	}
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, fmt.Errorf("Invalid signature")
+	}
+
+	// Only the signatures with low S values are accepted (EIP-2)
+	n := crypto.S256().Params().N
+	halfN := new(big.Int).Rsh(n, 1)
+	if sig.S.Cmp(halfN) > 0 {
+		sig.S = new(big.Int).Sub(n, sig.S)
+	}
+
+	r, s := sig.R.Bytes(), sig.S.Bytes()
+	if len(r) > 32 || len(s) > 32 {
+		return nil, fmt.Errorf("Invalid signature")
+	}
+	sigBytes := make([]byte, crypto.SignatureLength)
+	copy(sigBytes[32-len(r):32], r)
+	copy(sigBytes[64-len(s):64], s)
+	for v := byte(0); v < 2; v++ {
+		sigBytes[64] = v
+		signature, err := crypto.SignatureFromBytes(sigBytes)
+		if err != nil {
+			return nil, err
+		}
+		if signature.Verify(msg, pubKey.Address()) {
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("Signature does not match the public key %v", pubKey.Address().Hex())
+}
@@ -0,0 +1,156 @@
+package kms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+)
+
+var _ KeyProvider = (*VaultTransitProvider)(nil)
+
+// VaultTransitProvider signs with a key of the HashiCorp Vault transit secrets engine. The key
+// has to be a secp256k1 key, which the built-in transit engine does not offer, so the engine
+// mounted at the given path needs to support it (e.g. through a plugin with the same API).
+type VaultTransitProvider struct {
+	address   string
+	token     string
+	mountPath string
+	keyName   string
+	client    *http.Client
+
+	mu     *sync.Mutex
+	pubKey *crypto.PublicKey
+}
+
+// NewVaultTransitProvider creates an instance of VaultTransitProvider. The token defaults to the
+// VAULT_TOKEN environment variable if empty.
+func NewVaultTransitProvider(address, token, mountPath, keyName string) (*VaultTransitProvider, error) {
+	if address == "" || keyName == "" {
+		return nil, fmt.Errorf("Vault address and key name are required")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+	return &VaultTransitProvider{
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		mountPath: strings.Trim(mountPath, "/"),
+		keyName:   keyName,
+		client:    newHTTPClient(),
+		mu:        &sync.Mutex{},
+	}, nil
+}
+
+// PublicKey implements the KeyProvider interface.
+func (p *VaultTransitProvider) PublicKey() (*crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pubKey != nil {
+		return p.pubKey, nil
+	}
+
+	var resp struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+			Keys          map[string]struct {
+				PublicKey string `json:"public_key"`
+			} `json:"keys"`
+		} `json:"data"`
+	}
+	if err := p.request("GET", "keys/"+p.keyName, nil, &resp); err != nil {
+		return nil, err
+	}
+	key, ok := resp.Data.Keys[strconv.Itoa(resp.Data.LatestVersion)]
+	if !ok {
+		return nil, fmt.Errorf("Vault key %v has no version %v", p.keyName, resp.Data.LatestVersion)
+	}
+	block, _ := pem.Decode([]byte(key.PublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("Vault key %v has no PEM encoded public key", p.keyName)
+	}
+	pubKey, err := parsePublicKeyDER(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	p.pubKey = pubKey
+	return pubKey, nil
+}
+
+// Sign implements the KeyProvider interface.
+func (p *VaultTransitProvider) Sign(msg common.Bytes) (*crypto.Signature, error) {
+	pubKey, err := p.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(crypto.Keccak256(msg)),
+		"prehashed":            true,
+		"marshaling_algorithm": "asn1",
+	}
+	var resp struct {
+		Data struct {
+			Signature string `json:"signature"`
+		} `json:"data"`
+	}
+	if err := p.request("POST", "sign/"+p.keyName, req, &resp); err != nil {
+		return nil, err
+	}
+
+	// The signature is in the format of vault:v<version>:<base64 encoded signature>
+	parts := strings.Split(resp.Data.Signature, ":")
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("Unexpected Vault signature format: %v", resp.Data.Signature)
+	}
+	der, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Failed to decode Vault signature: %v", err)
+	}
+	return signatureFromDER(msg, der, pubKey)
+}
+
+func (p *VaultTransitProvider) request(method, path string, body interface{}, result interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s", p.address, p.mountPath, path)
+	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Vault request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Vault request failed with status %v: %s", resp.StatusCode, respBody)
+	}
+	return json.Unmarshal(respBody, result)
+}
@@ -0,0 +1,103 @@
+package kms
+
+import (
+	"fmt"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/wallet/types"
+)
+
+var _ types.Wallet = (*Wallet)(nil)
+
+//
+// Wallet implements the Wallet interface with the single key held by a key provider
+//
+
+type Wallet struct {
+	provider KeyProvider
+	pubKey   *crypto.PublicKey
+}
+
+// NewWallet creates an instance of Wallet. It fetches the public key from the key
+// provider, so that the address of the key is known.
+func NewWallet(provider KeyProvider) (*Wallet, error) {
+	pubKey, err := provider.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		provider: provider,
+		pubKey:   pubKey,
+	}, nil
+}
+
+// ID returns the ID of the wallet
+func (w *Wallet) ID() string {
+	return "kms:" + w.pubKey.Address().Hex()
+}
+
+// Status returns the status of the wallet
+func (w *Wallet) Status() (string, error) {
+	return "", nil
+}
+
+// List returns the address of the key
+func (w *Wallet) List() ([]common.Address, error) {
+	return []common.Address{w.pubKey.Address()}, nil
+}
+
+// NewKey is not supported, the keys are created in the key management service
+func (w *Wallet) NewKey(password string) (common.Address, error) {
+	return common.Address{}, fmt.Errorf("Not supported for KMS wallet")
+}
+
+// Unlock checks the address is the address of the key, no password is needed since the
+// access is controlled by the key management service
+func (w *Wallet) Unlock(address common.Address, password string, derivationPath types.DerivationPath) error {
+	if address != w.pubKey.Address() {
+		return fmt.Errorf("Address %v does not match the KMS key address %v", address.Hex(), w.pubKey.Address().Hex())
+	}
+	return nil
+}
+
+// Lock is a no-op
+func (w *Wallet) Lock(address common.Address) error {
+	return nil
+}
+
+// IsUnlocked returns whether the address is the address of the key
+func (w *Wallet) IsUnlocked(address common.Address) bool {
+	return address == w.pubKey.Address()
+}
+
+// Delete is not supported
+func (w *Wallet) Delete(address common.Address, password string) error {
+	return fmt.Errorf("Not supported for KMS wallet")
+}
+
+// UpdatePassword is not supported
+func (w *Wallet) UpdatePassword(address common.Address, oldPassword, newPassword string) error {
+	return fmt.Errorf("Not supported for KMS wallet")
+}
+
+// Derive is not supported
+func (w *Wallet) Derive(path types.DerivationPath, pin bool) (common.Address, error) {
+	return common.Address{}, fmt.Errorf("Not supported for KMS wallet")
+}
+
+// GetPublicKey returns the public key of the key
+func (w *Wallet) GetPublicKey(address common.Address) (*crypto.PublicKey, error) {
+	if address != w.pubKey.Address() {
+		return nil, fmt.Errorf("Unknown address: %v", address.Hex())
+	}
+	return w.pubKey, nil
+}
+
+// Sign signs the transaction bytes with the key provider
+func (w *Wallet) Sign(address common.Address, txrlp common.Bytes) (*crypto.Signature, error) {
+	if address != w.pubKey.Address() {
+		return nil, fmt.Errorf("Unknown address: %v", address.Hex())
+	}
+	return w.provider.Sign(txrlp)
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/spf13/viper"
+
 	"github.com/thetatoken/theta/common"
 )
 
@@ -31,6 +33,18 @@ type Keystore interface {
 	DeleteKey(address common.Address, auth string) error
 }
 
+// KDFParamsFromConfig returns the KDF parameters specified in the config file
+func KDFParamsFromConfig() KDFParams {
+	return KDFParams{
+		KDF:            viper.GetString(common.CfgKeyKDF),
+		ScryptN:        viper.GetInt(common.CfgKeyScryptN),
+		ScryptP:        viper.GetInt(common.CfgKeyScryptP),
+		Argon2Time:     uint32(viper.GetInt(common.CfgKeyArgon2Time)),
+		Argon2MemoryKB: uint32(viper.GetInt(common.CfgKeyArgon2MemoryKB)),
+		Argon2Threads:  uint8(viper.GetInt(common.CfgKeyArgon2Threads)),
+	}
+}
+
 func writeKeyFile(file string, content common.Bytes) error {
 	// Create the keystore directory with appropriate permissions
 	// in case it is not present yet.
@@ -49,6 +49,7 @@ import (
 	"github.com/thetatoken/theta/common/math"
 	"github.com/thetatoken/theta/crypto"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
 )
@@ -56,7 +57,8 @@ import (
 const (
 	version = 3
 
-	keyHeaderKDF = "scrypt"
+	keyHeaderKDF         = "scrypt"
+	keyHeaderKDFArgon2id = "argon2id"
 
 	// StandardScryptN is the N parameter of Scrypt encryption algorithm, using 256MB
 	// memory and taking approximately 1s CPU time on a modern processor.
@@ -76,19 +78,81 @@ const (
 
 	scryptR     = 8
 	scryptDKLen = 32
+
+	// DefaultArgon2Time is the number of passes of the Argon2id key derivation
+	DefaultArgon2Time = 3
+
+	// DefaultArgon2MemoryKB is the memory (in KB) used by the Argon2id key derivation
+	DefaultArgon2MemoryKB = 64 * 1024
+
+	// DefaultArgon2Threads is the degree of parallelism of the Argon2id key derivation
+	DefaultArgon2Threads = 4
 )
 
+// KDFParams specifies the key derivation function used to encrypt the keys, and its parameters.
+// Keys encrypted with scrypt can be decrypted by the Ethereum tools (e.g. geth), while Argon2id
+// is only understood by Theta.
+type KDFParams struct {
+	KDF            string // "scrypt" or "argon2id"
+	ScryptN        int
+	ScryptP        int
+	Argon2Time     uint32
+	Argon2MemoryKB uint32
+	Argon2Threads  uint8
+}
+
+// StandardKDFParams returns the standard scrypt parameters
+func StandardKDFParams() KDFParams {
+	return KDFParams{
+		KDF:     keyHeaderKDF,
+		ScryptN: StandardScryptN,
+		ScryptP: StandardScryptP,
+	}
+}
+
+// Validate checks the KDF is supported and the parameters are sensible
+func (params KDFParams) Validate() error {
+	switch params.KDF {
+	case keyHeaderKDF:
+		if params.ScryptN <= 1 || params.ScryptN&(params.ScryptN-1) != 0 {
+			return fmt.Errorf("scrypt N must be a power of 2 greater than 1, got %v", params.ScryptN)
+		}
+		if params.ScryptP <= 0 {
+			return fmt.Errorf("scrypt P must be positive, got %v", params.ScryptP)
+		}
+	case keyHeaderKDFArgon2id:
+		if params.Argon2Time == 0 || params.Argon2MemoryKB == 0 || params.Argon2Threads == 0 {
+			return fmt.Errorf("Argon2 time, memory and threads must be positive")
+		}
+	default:
+		return fmt.Errorf("Unsupported KDF: %s", params.KDF)
+	}
+	return nil
+}
+
 var (
 	ErrDecrypt = fmt.Errorf("could not decrypt key with given password")
 )
 
 type KeystoreEncrypted struct {
 	keysDirPath string
-	scryptN     int
-	scryptP     int
+	kdfParams   KDFParams
 }
 
 func NewKeystoreEncrypted(keysDirRoot string, scryptN, scryptP int) (KeystoreEncrypted, error) {
+	return NewKeystoreEncryptedWithKDF(keysDirRoot, KDFParams{
+		KDF:     keyHeaderKDF,
+		ScryptN: scryptN,
+		ScryptP: scryptP,
+	})
+}
+
+// NewKeystoreEncryptedWithKDF creates an encrypted keystore which encrypts the keys with the given KDF
+func NewKeystoreEncryptedWithKDF(keysDirRoot string, kdfParams KDFParams) (KeystoreEncrypted, error) {
+	if err := kdfParams.Validate(); err != nil {
+		return KeystoreEncrypted{}, err
+	}
+
 	keysDirPath := path.Join(keysDirRoot, "encrypted")
 	err := os.MkdirAll(keysDirPath, 0700)
 	if err != nil {
@@ -106,8 +170,7 @@ func NewKeystoreEncrypted(keysDirRoot string, scryptN, scryptP int) (KeystoreEnc
 
 	ks := KeystoreEncrypted{
 		keysDirPath: keysDirPath,
-		scryptN:     scryptN,
-		scryptP:     scryptP,
+		kdfParams:   kdfParams,
 	}
 
 	return ks, nil
@@ -157,7 +220,7 @@ func (ks KeystoreEncrypted) GetKey(address common.Address, auth string) (*Key, e
 func (ks KeystoreEncrypted) StoreKey(key *Key, auth string) error {
 	address := key.Address
 	filePath := ks.getFilePath(address, mixedCase)
-	keyjson, err := encryptKey(key, auth, ks.scryptN, ks.scryptP)
+	keyjson, err := encryptKeyWithKDF(key, auth, ks.kdfParams)
 	if err != nil {
 		return err
 	}
@@ -194,16 +257,47 @@ func (ks KeystoreEncrypted) getFilePath(address common.Address, addrFormat Addre
 // encryptKey encrypts a key using the specified scrypt parameters into a json
 // blob that can be decrypted later on.
 func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
+	return encryptKeyWithKDF(key, auth, KDFParams{
+		KDF:     keyHeaderKDF,
+		ScryptN: scryptN,
+		ScryptP: scryptP,
+	})
+}
+
+// encryptKeyWithKDF encrypts a key using the specified KDF into a json blob that
+// can be decrypted later on.
+func encryptKeyWithKDF(key *Key, auth string, kdfParams KDFParams) ([]byte, error) {
 	authArray := []byte(auth)
 
 	salt := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
 		panic("reading from crypto/rand failed: " + err.Error())
 	}
-	derivedKey, err := scrypt.Key(authArray, salt, scryptN, scryptR, scryptP, scryptDKLen)
+
+	var derivedKey []byte
+	var err error
+	kdfParamsJSON := make(map[string]interface{}, 5)
+	switch kdfParams.KDF {
+	case keyHeaderKDF:
+		derivedKey, err = scrypt.Key(authArray, salt, kdfParams.ScryptN, scryptR, kdfParams.ScryptP, scryptDKLen)
+		kdfParamsJSON["n"] = kdfParams.ScryptN
+		kdfParamsJSON["r"] = scryptR
+		kdfParamsJSON["p"] = kdfParams.ScryptP
+	case keyHeaderKDFArgon2id:
+		derivedKey = argon2.IDKey(authArray, salt, kdfParams.Argon2Time, kdfParams.Argon2MemoryKB,
+			kdfParams.Argon2Threads, scryptDKLen)
+		kdfParamsJSON["t"] = kdfParams.Argon2Time
+		kdfParamsJSON["m"] = kdfParams.Argon2MemoryKB
+		kdfParamsJSON["p"] = kdfParams.Argon2Threads
+	default:
+		err = fmt.Errorf("Unsupported KDF: %s", kdfParams.KDF)
+	}
 	if err != nil {
 		return nil, err
 	}
+	kdfParamsJSON["dklen"] = scryptDKLen
+	kdfParamsJSON["salt"] = hex.EncodeToString(salt)
+
 	encryptKey := derivedKey[:16]
 	keyBytes := math.PaddedBigBytes(key.PrivateKey.D(), 32)
 
@@ -217,13 +311,6 @@ func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
 	}
 	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
 
-	scryptParamsJSON := make(map[string]interface{}, 5)
-	scryptParamsJSON["n"] = scryptN
-	scryptParamsJSON["r"] = scryptR
-	scryptParamsJSON["p"] = scryptP
-	scryptParamsJSON["dklen"] = scryptDKLen
-	scryptParamsJSON["salt"] = hex.EncodeToString(salt)
-
 	cipherParamsJSON := cipherparamsJSON{
 		IV: hex.EncodeToString(iv),
 	}
@@ -232,8 +319,8 @@ func encryptKey(key *Key, auth string, scryptN, scryptP int) ([]byte, error) {
 		Cipher:       "aes-128-ctr",
 		CipherText:   hex.EncodeToString(cipherText),
 		CipherParams: cipherParamsJSON,
-		KDF:          keyHeaderKDF,
-		KDFParams:    scryptParamsJSON,
+		KDF:          kdfParams.KDF,
+		KDFParams:    kdfParamsJSON,
 		MAC:          hex.EncodeToString(mac),
 	}
 
@@ -328,6 +415,12 @@ func getKDFKey(cryptoJSON cryptoJSON, auth string) ([]byte, error) {
 		}
 		key := pbkdf2.Key(authArray, salt, c, dkLen, sha256.New)
 		return key, nil
+
+	} else if cryptoJSON.KDF == keyHeaderKDFArgon2id {
+		t := ensureInt(cryptoJSON.KDFParams["t"])
+		m := ensureInt(cryptoJSON.KDFParams["m"])
+		p := ensureInt(cryptoJSON.KDFParams["p"])
+		return argon2.IDKey(authArray, salt, uint32(t), uint32(m), uint8(p), uint32(dkLen)), nil
 	}
 
 	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
@@ -19,9 +19,11 @@ package keystore
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/thetatoken/theta/common"
@@ -116,3 +118,87 @@ func TestKeyEncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyStoreEncryptedArgon2id(t *testing.T) {
+	dir, err := ioutil.TempDir("", "theta-keystore-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ks, err := NewKeystoreEncryptedWithKDF(dir, KDFParams{
+		KDF:            keyHeaderKDFArgon2id,
+		Argon2Time:     1,
+		Argon2MemoryKB: 64,
+		Argon2Threads:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pass := "foo"
+	k1, err := storeNewKeyTest(ks, rand.Reader, pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k2, err := ks.GetKey(k1.Address, pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(k1.PrivateKey, k2.PrivateKey) {
+		t.Fatal("decrypted key mismatch")
+	}
+	if _, err = ks.GetKey(k1.Address, "bar"); err != ErrDecrypt {
+		t.Fatalf("wrong error for invalid password\ngot %q\nwant %q", err, ErrDecrypt)
+	}
+}
+
+func TestKDFParamsValidate(t *testing.T) {
+	if err := StandardKDFParams().Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (KDFParams{KDF: keyHeaderKDF, ScryptN: 1000, ScryptP: 1}).Validate(); err == nil {
+		t.Fatal("scrypt N not a power of 2 should be rejected")
+	}
+	if err := (KDFParams{KDF: keyHeaderKDFArgon2id}).Validate(); err == nil {
+		t.Fatal("zero Argon2 parameters should be rejected")
+	}
+	if err := (KDFParams{KDF: "bcrypt"}).Validate(); err == nil {
+		t.Fatal("unsupported KDF should be rejected")
+	}
+}
+
+// Tests that a geth key file can be imported, and the exported key file can be imported again.
+func TestImportExportKey(t *testing.T) {
+	keyjson, err := ioutil.ReadFile("testdata/very-light-scrypt.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := common.HexToAddress("45dea0fb0bba44f4fcf290bba71fd57d7117cbb8")
+
+	key, err := ImportKey(keyjson, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address != address {
+		t.Fatalf("key address mismatch: have %x, want %x", key.Address, address)
+	}
+
+	exported, err := encryptKey(key, "foo", veryLightScryptN, veryLightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := ImportKey(exported, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key2.Address != address {
+		t.Fatalf("key address mismatch: have %x, want %x", key2.Address, address)
+	}
+
+	// The address in the key file must match the key
+	tampered := strings.Replace(string(exported), hex.EncodeToString(address[:]), "0000000000000000000000000000000000000001", 1)
+	if _, err := ImportKey([]byte(tampered), "foo"); err == nil {
+		t.Fatal("key file with mismatched address should be rejected")
+	}
+}
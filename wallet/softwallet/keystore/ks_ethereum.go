@@ -0,0 +1,59 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thetatoken/theta/common"
+)
+
+// ImportKey decrypts a key file in the Web3 Secret Storage (keystore V3) format, e.g. one
+// created by geth, so that the key can be stored into a Theta keystore.
+func ImportKey(keyjson []byte, auth string) (*Key, error) {
+	encryptedKeyJs := new(encryptedKeyJSON)
+	if err := json.Unmarshal(keyjson, encryptedKeyJs); err != nil {
+		return nil, fmt.Errorf("Invalid key file: %v", err)
+	}
+
+	key, err := decryptKey(keyjson, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	// The address field is optional, but should match the key if present
+	if addrStr := encryptedKeyJs.Address; addrStr != "" {
+		addrBytes, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(addrStr), "0x"))
+		if err != nil || common.BytesToAddress(addrBytes) != key.Address {
+			return nil, fmt.Errorf("key content mismatch: have account %x, key file claims %v", key.Address, addrStr)
+		}
+	}
+	return key, nil
+}
+
+// ExportKey encrypts the key into the Web3 Secret Storage (keystore V3) format with the standard
+// scrypt parameters, so that the key file can be imported by the Ethereum tools.
+func ExportKey(key *Key, auth string) ([]byte, error) {
+	return encryptKey(key, auth, StandardScryptN, StandardScryptP)
+}
+
+// ExportKeyFileName returns the name of the exported key file, following the Ethereum
+// convention, e.g. UTC--2019-10-01T12-34-56.789000000Z--2e833968e5bb786ae419c4d13189fb081cc43bab
+func ExportKeyFileName(address common.Address) string {
+	ts := time.Now().UTC()
+	return fmt.Sprintf("UTC--%s--%s", toISO8601(ts), hex.EncodeToString(address[:]))
+}
+
+func toISO8601(t time.Time) string {
+	var tz string
+	name, offset := t.Zone()
+	if name == "UTC" {
+		tz = "Z"
+	} else {
+		tz = fmt.Sprintf("%03d00", offset/3600)
+	}
+	return fmt.Sprintf("%04d-%02d-%02dT%02d-%02d-%02d.%09d%s",
+		t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), tz)
+}
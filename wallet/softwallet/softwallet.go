@@ -38,7 +38,7 @@ func NewSoftWallet(keysDirPath string, kstype KeystoreType) (*SoftWallet, error)
 	var keystore ks.Keystore
 	var err error
 	if kstype == KeystoreTypeEncrypted {
-		keystore, err = ks.NewKeystoreEncrypted(keysDirPath, ks.StandardScryptN, ks.StandardScryptP)
+		keystore, err = ks.NewKeystoreEncryptedWithKDF(keysDirPath, ks.KDFParamsFromConfig())
 	} else {
 		keystore, err = ks.NewKeystorePlain(keysDirPath)
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/thetatoken/theta/wallet/coldwallet"
 	cw "github.com/thetatoken/theta/wallet/coldwallet"
+	"github.com/thetatoken/theta/wallet/kms"
 	sw "github.com/thetatoken/theta/wallet/softwallet"
 	"github.com/thetatoken/theta/wallet/types"
 )
@@ -28,6 +29,18 @@ func OpenWallet(cfgPath string, walletType types.WalletType, encrypted bool) (ty
 		if err != nil {
 			return nil, err
 		}
+	} else if walletType == types.WalletTypeKMS {
+		provider, err := kms.NewKeyProviderFromConfig()
+		if err != nil {
+			return nil, err
+		}
+		if provider == nil {
+			return nil, fmt.Errorf("No key provider configured")
+		}
+		wallet, err = kms.NewWallet(provider)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		var hub *coldwallet.Hub
 		var err error
@@ -0,0 +1,55 @@
+package watch
+
+import (
+	"encoding/binary"
+
+	"github.com/thetatoken/theta/crypto"
+)
+
+const (
+	minBloomBits        = 2048
+	bloomBitsPerAddress = 16 // about 0.1% false positives with bloomHashes hashes
+	bloomHashes         = 6
+)
+
+// bloomFilter is a bloom filter of the watched addresses, sized for the number of the addresses
+// so that the false positive rate stays low as thousands of addresses are watched. Unlike the
+// 2048-bit log bloom, it is never exposed and can be resized by rebuilding it.
+type bloomFilter struct {
+	bits []byte
+}
+
+func newBloomFilter(numItems int) *bloomFilter {
+	numBits := numItems * bloomBitsPerAddress
+	if numBits < minBloomBits {
+		numBits = minBloomBits
+	}
+	return &bloomFilter{
+		bits: make([]byte, (numBits+7)/8),
+	}
+}
+
+func (b *bloomFilter) add(d []byte) {
+	h := crypto.Keccak256(d)
+	for i := 0; i < bloomHashes; i++ {
+		idx, mask := b.bit(h, i)
+		b.bits[idx] |= mask
+	}
+}
+
+func (b *bloomFilter) test(d []byte) bool {
+	h := crypto.Keccak256(d)
+	for i := 0; i < bloomHashes; i++ {
+		idx, mask := b.bit(h, i)
+		if b.bits[idx]&mask == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bit returns the byte index and the mask of the i-th bit of the hash
+func (b *bloomFilter) bit(h []byte, i int) (int, byte) {
+	pos := binary.BigEndian.Uint32(h[4*i:]) % uint32(len(b.bits)*8)
+	return int(pos / 8), byte(1) << (pos % 8)
+}
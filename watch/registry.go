@@ -0,0 +1,295 @@
+package watch
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "watch"})
+
+// registeredKey is the key of the addresses registered at runtime in the store
+var registeredKey = common.Bytes("watch/addresses")
+
+// TxMatch is a finalized transaction touching watched addresses
+type TxMatch struct {
+	Height    common.JSONUint64 `json:"height"`
+	BlockHash common.Hash       `json:"block_hash"`
+	TxHash    common.Hash       `json:"tx_hash"`
+	TxType    string            `json:"tx_type"`
+	Addresses []common.Address  `json:"addresses"` // The watched addresses touched by the tx
+	Tx        types.Tx          `json:"tx"`
+}
+
+//
+// Registry keeps the watched addresses, i.e. the addresses set by the watch.addresses config and
+// the ones registered at runtime, which are persisted across restarts. The transactions of the
+// finalized blocks are first checked against a bloom filter of the addresses, so that the
+// transactions touching none of them are skipped cheaply, and the matched ones are sent to the
+// subscribers.
+//
+type Registry struct {
+	mu         *sync.RWMutex
+	store      store.Store
+	configured map[common.Address]bool
+	registered map[common.Address]bool
+	bloom      *bloomFilter
+
+	subMu       *sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewRegistry creates a Registry with the configured addresses and the ones registered before
+func NewRegistry(store store.Store) (*Registry, error) {
+	r := &Registry{
+		mu:          &sync.RWMutex{},
+		store:       store,
+		configured:  make(map[common.Address]bool),
+		registered:  make(map[common.Address]bool),
+		subMu:       &sync.Mutex{},
+		subscribers: make(map[*Subscription]struct{}),
+	}
+	for _, addressStr := range viper.GetStringSlice(common.CfgWatchAddresses) {
+		address, err := common.ParseAddress(addressStr, false)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid watched address %v: %v", addressStr, err)
+		}
+		r.configured[address] = true
+	}
+	if store != nil {
+		registered := []common.Address{}
+		if err := store.Get(registeredKey, &registered); err == nil {
+			for _, address := range registered {
+				r.registered[address] = true
+			}
+		}
+	}
+	r.rebuildBloom()
+	return r, nil
+}
+
+// Add registers the addresses, and returns the number of the newly watched ones
+func (r *Registry) Add(addresses []common.Address) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	added := 0
+	for _, address := range addresses {
+		if r.configured[address] || r.registered[address] {
+			continue
+		}
+		r.registered[address] = true
+		added++
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	r.rebuildBloom()
+	return added, r.save()
+}
+
+// Remove unregisters the addresses, and returns the number of the removed ones. The configured
+// addresses cannot be removed.
+func (r *Registry) Remove(addresses []common.Address) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for _, address := range addresses {
+		if !r.registered[address] {
+			continue
+		}
+		delete(r.registered, address)
+		removed++
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	r.rebuildBloom()
+	return removed, r.save()
+}
+
+// Addresses returns the watched addresses in ascending order
+func (r *Registry) Addresses() []common.Address {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	addresses := []common.Address{}
+	for address := range r.configured {
+		addresses = append(addresses, address)
+	}
+	for address := range r.registered {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].Hex() < addresses[j].Hex() })
+	return addresses
+}
+
+// Match returns the watched addresses touched by the tx
+func (r *Registry) Match(tx types.Tx) []common.Address {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []common.Address{}
+	if len(r.configured)+len(r.registered) == 0 {
+		return matched
+	}
+	for _, address := range types.TxAccountAddresses(tx) {
+		if !r.bloom.test(address.Bytes()) {
+			continue
+		}
+		if !r.configured[address] && !r.registered[address] {
+			continue // false positive
+		}
+		if !containsAddress(matched, address) {
+			matched = append(matched, address)
+		}
+	}
+	return matched
+}
+
+// MatchBlock returns the transactions of the block touching watched addresses
+func (r *Registry) MatchBlock(block *core.Block) []*TxMatch {
+	matches := []*TxMatch{}
+	blockHash := block.Hash()
+	for _, rawTx := range block.Txs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		addresses := r.Match(tx)
+		if len(addresses) == 0 {
+			continue
+		}
+		txType, _ := types.GetTxType(tx)
+		matches = append(matches, &TxMatch{
+			Height:    common.JSONUint64(block.Height),
+			BlockHash: blockHash,
+			TxHash:    crypto.Keccak256Hash(rawTx),
+			TxType:    txType.String(),
+			Addresses: addresses,
+			Tx:        tx,
+		})
+	}
+	return matches
+}
+
+// ProcessBlock sends the transactions of the finalized block touching watched addresses to the
+// subscribers
+func (r *Registry) ProcessBlock(block *core.Block) {
+	r.subMu.Lock()
+	numSubscribers := len(r.subscribers)
+	r.subMu.Unlock()
+	if numSubscribers == 0 {
+		return
+	}
+
+	for _, match := range r.MatchBlock(block) {
+		r.publish(match)
+	}
+}
+
+// rebuildBloom should be called with the lock held
+func (r *Registry) rebuildBloom() {
+	bloom := newBloomFilter(len(r.configured) + len(r.registered))
+	for address := range r.configured {
+		bloom.add(address.Bytes())
+	}
+	for address := range r.registered {
+		bloom.add(address.Bytes())
+	}
+	r.bloom = bloom
+}
+
+// save should be called with the lock held
+func (r *Registry) save() error {
+	if r.store == nil {
+		return nil
+	}
+	registered := []common.Address{}
+	for address := range r.registered {
+		registered = append(registered, address)
+	}
+	if err := r.store.Put(registeredKey, registered); err != nil {
+		logger.Errorf("Failed to save the watched addresses: %v", err)
+		return err
+	}
+	return nil
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// ------------------------------- Subscription -----------------------------------
+
+// Subscription receives the matched transactions. The channel is closed if the subscriber falls
+// behind and the buffer overflows.
+type Subscription struct {
+	registry *Registry
+	ch       chan *TxMatch
+	closed   bool
+}
+
+// Subscribe subscribes to the matched transactions of the finalized blocks
+func (r *Registry) Subscribe(bufferSize int) *Subscription {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	sub := &Subscription{
+		registry: r,
+		ch:       make(chan *TxMatch, bufferSize),
+	}
+	r.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Matches returns the channel of the matched transactions
+func (s *Subscription) Matches() <-chan *TxMatch {
+	return s.ch
+}
+
+// Unsubscribe stops the delivery of the matched transactions and closes the channel
+func (s *Subscription) Unsubscribe() {
+	s.registry.subMu.Lock()
+	defer s.registry.subMu.Unlock()
+	s.registry.remove(s)
+}
+
+func (r *Registry) publish(match *TxMatch) {
+	r.subMu.Lock()
+	defer r.subMu.Unlock()
+
+	for sub := range r.subscribers {
+		select {
+		case sub.ch <- match:
+		default:
+			logger.Warnf("Watched address subscriber fell behind, dropping subscription")
+			r.remove(sub)
+		}
+	}
+}
+
+// remove should be called with subMu held
+func (r *Registry) remove(sub *Subscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(r.subscribers, sub)
+	close(sub.ch)
+}
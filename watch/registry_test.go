@@ -0,0 +1,104 @@
+package watch
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store/database/backend"
+	"github.com/thetatoken/theta/store/kvstore"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	store := kvstore.NewKVStore(backend.NewMemDatabase())
+	alice := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	bob := common.HexToAddress("0x70f587259738cB626A1720Af7038B8DcDb6a42a0")
+	carol := common.HexToAddress("0xcd56123D0c5D6C1Ba4D39367b88cba61D93F5405")
+
+	r, err := NewRegistry(store)
+	assert.Nil(err)
+	added, err := r.Add([]common.Address{alice, bob, alice})
+	assert.Nil(err)
+	assert.Equal(2, added)
+
+	tx := &types.SendTx{
+		Inputs:  []types.TxInput{{Address: alice}},
+		Outputs: []types.TxOutput{{Address: carol}},
+	}
+	assert.Equal([]common.Address{alice}, r.Match(tx))
+
+	removed, err := r.Remove([]common.Address{alice, carol})
+	assert.Nil(err)
+	assert.Equal(1, removed)
+	assert.Equal(0, len(r.Match(tx)))
+
+	// The registered addresses are restored
+	r, err = NewRegistry(store)
+	assert.Nil(err)
+	assert.Equal([]common.Address{bob}, r.Addresses())
+}
+
+func TestRegistryManyAddresses(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := NewRegistry(nil)
+	assert.Nil(err)
+	addresses := []common.Address{}
+	for i := 0; i < 5000; i++ {
+		addresses = append(addresses, common.BigToAddress(big.NewInt(int64(i+1))))
+	}
+	_, err = r.Add(addresses)
+	assert.Nil(err)
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if r.bloom.test(common.BigToAddress(big.NewInt(int64(i + 100000))).Bytes()) {
+			falsePositives++
+		}
+	}
+	assert.True(falsePositives < 100)
+	for _, address := range addresses {
+		assert.True(r.bloom.test(address.Bytes()))
+	}
+}
+
+func TestRegistrySubscription(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	bob := common.HexToAddress("0x70f587259738cB626A1720Af7038B8DcDb6a42a0")
+
+	r, err := NewRegistry(nil)
+	assert.Nil(err)
+	r.Add([]common.Address{bob})
+
+	rawTx, err := types.TxToBytes(&types.SendTx{
+		Inputs:  []types.TxInput{{Address: alice}},
+		Outputs: []types.TxOutput{{Address: bob}},
+	})
+	assert.Nil(err)
+	block := &core.Block{
+		BlockHeader: &core.BlockHeader{Height: 7},
+		Txs:         []common.Bytes{rawTx},
+	}
+
+	sub := r.Subscribe(1)
+	r.ProcessBlock(block)
+	match := <-sub.Matches()
+	assert.Equal(common.JSONUint64(7), match.Height)
+	assert.Equal("send", match.TxType)
+	assert.Equal([]common.Address{bob}, match.Addresses)
+
+	// Dropped once the buffer overflows
+	r.ProcessBlock(block)
+	r.ProcessBlock(block)
+	<-sub.Matches()
+	_, ok := <-sub.Matches()
+	assert.False(ok)
+}
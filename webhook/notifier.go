@@ -0,0 +1,351 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/thetatoken/theta/blockchain"
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/crypto"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/store"
+	"github.com/thetatoken/theta/watch"
+)
+
+var logger *log.Entry = log.WithFields(log.Fields{"prefix": "webhook"})
+
+const (
+	pollInterval     = 5 * time.Second
+	eventBufferSize  = 64
+	signatureHeader  = "X-Theta-Signature"
+	maxResponseBytes = 4096
+)
+
+// cursorKey is the key of the height of the last notified block in the store
+var cursorKey = common.Bytes("webhook/cursor")
+
+// The types of the webhook events
+const (
+	EventBlockFinalized = "block_finalized"
+	EventTx             = "tx"
+	EventStake          = "stake"
+)
+
+// The actions of the stake events
+const (
+	StakeActionDeposit  = "deposit"
+	StakeActionWithdraw = "withdraw"
+)
+
+// Event is the JSON payload POSTed to the webhook URLs
+type Event struct {
+	Type      string            `json:"type"`
+	Height    common.JSONUint64 `json:"height"`
+	BlockHash common.Hash       `json:"block_hash"`
+	Data      interface{}       `json:"data"`
+}
+
+// BlockData is the data of a block_finalized event
+type BlockData struct {
+	Timestamp *common.JSONBig   `json:"timestamp"`
+	NumTxs    common.JSONUint64 `json:"num_txs"`
+}
+
+// TxData is the data of a tx event, Addresses are the watched addresses touched by the tx
+type TxData struct {
+	TxHash    common.Hash      `json:"tx_hash"`
+	TxType    string           `json:"tx_type"`
+	Addresses []common.Address `json:"addresses"`
+	Tx        types.Tx         `json:"tx"`
+}
+
+// StakeData is the data of a stake event
+type StakeData struct {
+	TxHash  common.Hash    `json:"tx_hash"`
+	Action  string         `json:"action"`
+	Source  common.Address `json:"source"`
+	Holder  common.Address `json:"holder"`
+	Purpose uint8          `json:"purpose"`
+	Amount  types.Coins    `json:"amount"`
+}
+
+//
+// Notifier POSTs the events of the finalized blocks to the configured webhook URLs: one event per
+// block, one per transaction touching a watched address, and one per stake deposit or withdrawal.
+// Only the finalized blocks are notified, so the events are never reverted by a reorg. The height
+// of the last notified block is persisted, so that the notifications resume from where they
+// stopped after a restart. The events are delivered at least once, in the order of the heights.
+//
+type Notifier struct {
+	chain     *blockchain.Chain
+	consensus core.ConsensusEngine
+	store     store.Store
+
+	urls         []string
+	watched      *watch.Registry
+	secret       []byte
+	maxRetries   int
+	retryBackoff time.Duration
+	client       *http.Client
+
+	// Life cycle
+	wg     *sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewNotifier creates a Notifier with the configured URLs, which notifies the transactions
+// touching the addresses watched by the registry
+func NewNotifier(chain *blockchain.Chain, consensus core.ConsensusEngine, store store.Store, watched *watch.Registry) *Notifier {
+	return &Notifier{
+		chain:        chain,
+		consensus:    consensus,
+		store:        store,
+		urls:         viper.GetStringSlice(common.CfgWebhookURLs),
+		watched:      watched,
+		secret:       []byte(viper.GetString(common.CfgWebhookSecret)),
+		maxRetries:   viper.GetInt(common.CfgWebhookMaxRetries),
+		retryBackoff: time.Duration(viper.GetInt(common.CfgWebhookRetryBackoffMs)) * time.Millisecond,
+		client: &http.Client{
+			Timeout: time.Duration(viper.GetInt(common.CfgWebhookTimeoutSecs)) * time.Second,
+		},
+		wg: &sync.WaitGroup{},
+	}
+}
+
+// Start starts the notifications
+func (n *Notifier) Start(ctx context.Context) {
+	c, cancel := context.WithCancel(ctx)
+	n.ctx = c
+	n.cancel = cancel
+
+	n.wg.Add(1)
+	go n.mainLoop()
+}
+
+// Stop stops the notifications
+func (n *Notifier) Stop() {
+	n.cancel()
+}
+
+// Wait suspends the caller goroutine
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
+
+func (n *Notifier) mainLoop() {
+	defer n.wg.Done()
+
+	next := n.consensus.GetLastFinalizedBlock().Height + 1
+	var lastNotified uint64
+	if err := n.store.Get(cursorKey, &lastNotified); err == nil {
+		next = lastNotified + 1
+	}
+	logger.Infof("Webhook notifications start at height %v", next)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	sub := n.chain.SubscribeEvents(eventBufferSize)
+	defer func() { sub.Unsubscribe() }()
+	for {
+		lastFinalizedHeight := n.consensus.GetLastFinalizedBlock().Height
+		for ; next <= lastFinalizedHeight; next++ {
+			block := n.findFinalizedBlockByHeight(next)
+			if block == nil { // might have been pruned
+				continue
+			}
+			for _, event := range n.BuildEvents(block) {
+				if err := n.deliver(event); err != nil {
+					return // shutting down, the block is notified again after restart
+				}
+			}
+			if err := n.store.Put(cursorKey, next); err != nil {
+				logger.Errorf("Failed to save the webhook cursor at height %v: %v", next, err)
+			}
+		}
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case _, ok := <-sub.Events():
+			if !ok {
+				// Dropped for falling behind, the heights are re-checked anyway
+				sub = n.chain.SubscribeEvents(eventBufferSize)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *Notifier) findFinalizedBlockByHeight(height uint64) *core.ExtendedBlock {
+	for _, block := range n.chain.FindBlocksByHeight(height) {
+		if block.Status.IsFinalized() {
+			return block
+		}
+	}
+	return nil
+}
+
+// BuildEvents returns the events of the finalized block
+func (n *Notifier) BuildEvents(block *core.ExtendedBlock) []*Event {
+	height := common.JSONUint64(block.Height)
+	blockHash := block.Hash()
+	events := []*Event{
+		{
+			Type:      EventBlockFinalized,
+			Height:    height,
+			BlockHash: blockHash,
+			Data: &BlockData{
+				Timestamp: (*common.JSONBig)(block.Timestamp),
+				NumTxs:    common.JSONUint64(len(block.Txs)),
+			},
+		},
+	}
+
+	for _, rawTx := range block.Txs {
+		tx, err := types.TxFromBytes(rawTx)
+		if err != nil {
+			continue
+		}
+		txHash := crypto.Keccak256Hash(rawTx)
+
+		if addresses := n.watched.Match(tx); len(addresses) > 0 {
+			txType, _ := types.GetTxType(tx)
+			events = append(events, &Event{
+				Type:      EventTx,
+				Height:    height,
+				BlockHash: blockHash,
+				Data: &TxData{
+					TxHash:    txHash,
+					TxType:    txType.String(),
+					Addresses: addresses,
+					Tx:        tx,
+				},
+			})
+		}
+
+		if stake := stakeData(tx); stake != nil {
+			stake.TxHash = txHash
+			events = append(events, &Event{
+				Type:      EventStake,
+				Height:    height,
+				BlockHash: blockHash,
+				Data:      stake,
+			})
+		}
+	}
+	return events
+}
+
+// stakeData returns the data of the stake event of the tx, or nil if the tx is not a stake deposit
+// or withdrawal
+func stakeData(tx types.Tx) *StakeData {
+	switch tx := tx.(type) {
+	case *types.DepositStakeTx:
+		return &StakeData{
+			Action:  StakeActionDeposit,
+			Source:  tx.Source.Address,
+			Holder:  tx.Holder.Address,
+			Purpose: tx.Purpose,
+			Amount:  tx.Source.Coins,
+		}
+	case *types.DepositStakeTxV2:
+		return &StakeData{
+			Action:  StakeActionDeposit,
+			Source:  tx.Source.Address,
+			Holder:  tx.Holder.Address,
+			Purpose: tx.Purpose,
+			Amount:  tx.Source.Coins,
+		}
+	case *types.WithdrawStakeTx:
+		return &StakeData{
+			Action:  StakeActionWithdraw,
+			Source:  tx.Source.Address,
+			Holder:  tx.Holder.Address,
+			Purpose: tx.Purpose,
+			Amount:  tx.Source.Coins,
+		}
+	}
+	return nil
+}
+
+// deliver POSTs the event to all the URLs. A failed delivery is retried with exponential backoff,
+// and given up after maxRetries retries. It only returns an error if the notifier is stopped.
+func (n *Notifier) deliver(event *Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorf("Failed to encode the %v event at height %v: %v", event.Type, event.Height, err)
+		return nil
+	}
+
+	for _, url := range n.urls {
+		backoff := n.retryBackoff
+		for attempt := 0; ; attempt++ {
+			err := n.post(url, payload)
+			if err == nil {
+				break
+			}
+			if n.ctx.Err() != nil {
+				return n.ctx.Err()
+			}
+			if attempt >= n.maxRetries {
+				logger.Errorf("Gave up delivering the %v event at height %v to %v: %v", event.Type, event.Height, url, err)
+				break
+			}
+			logger.Warnf("Failed to deliver the %v event at height %v to %v, retry in %v: %v", event.Type, event.Height, url, backoff, err)
+			select {
+			case <-n.ctx.Done():
+				return n.ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+	return nil
+}
+
+func (n *Notifier) post(url string, payload []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(n.ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(signatureHeader, Sign(n.secret, payload))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Unexpected status %v", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the payload, which is sent in the X-Theta-Signature
+// header for the receivers to authenticate the events
+func Sign(secret []byte, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/thetatoken/theta/common"
+	"github.com/thetatoken/theta/core"
+	"github.com/thetatoken/theta/ledger/types"
+	"github.com/thetatoken/theta/watch"
+)
+
+func newTestNotifier(urls []string, watched ...common.Address) *Notifier {
+	registry, _ := watch.NewRegistry(nil)
+	registry.Add(watched)
+	n := &Notifier{
+		urls:         urls,
+		watched:      registry,
+		secret:       []byte("secret"),
+		maxRetries:   3,
+		retryBackoff: time.Millisecond,
+		client:       &http.Client{Timeout: time.Second},
+	}
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+	return n
+}
+
+func TestBuildEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	alice := common.HexToAddress("0x2E833968E5bB786Ae419c4d13189fB081Cc43bab")
+	bob := common.HexToAddress("0x70f587259738cB626A1720Af7038B8DcDb6a42a0")
+	carol := common.HexToAddress("0xcd56123D0c5D6C1Ba4D39367b88cba61D93F5405")
+
+	send := &types.SendTx{
+		Fee:     types.NewCoins(0, 1000000000000),
+		Inputs:  []types.TxInput{{Address: alice, Coins: types.NewCoins(0, 1001000000000)}},
+		Outputs: []types.TxOutput{{Address: bob, Coins: types.NewCoins(0, 1000000000)}},
+	}
+	deposit := &types.DepositStakeTx{
+		Fee:     types.NewCoins(0, 1000000000000),
+		Source:  types.TxInput{Address: carol, Coins: types.NewCoins(1000, 0)},
+		Holder:  types.TxOutput{Address: alice},
+		Purpose: core.StakeForValidator,
+	}
+	rawSend, err := types.TxToBytes(send)
+	assert.Nil(err)
+	rawDeposit, err := types.TxToBytes(deposit)
+	assert.Nil(err)
+
+	block := &core.ExtendedBlock{Block: &core.Block{
+		BlockHeader: &core.BlockHeader{Height: 10, Timestamp: big.NewInt(1000)},
+		Txs:         []common.Bytes{rawSend, rawDeposit},
+	}}
+
+	n := newTestNotifier(nil, bob)
+	events := n.BuildEvents(block)
+	assert.Equal(3, len(events))
+	assert.Equal(EventBlockFinalized, events[0].Type)
+	assert.Equal(common.JSONUint64(2), events[0].Data.(*BlockData).NumTxs)
+	assert.Equal(EventTx, events[1].Type)
+	assert.Equal([]common.Address{bob}, events[1].Data.(*TxData).Addresses)
+	assert.Equal("send", events[1].Data.(*TxData).TxType)
+	assert.Equal(EventStake, events[2].Type)
+	stake := events[2].Data.(*StakeData)
+	assert.Equal(StakeActionDeposit, stake.Action)
+	assert.Equal(carol, stake.Source)
+	assert.Equal(alice, stake.Holder)
+	for _, event := range events {
+		assert.Equal(common.JSONUint64(10), event.Height)
+		assert.Equal(block.Hash(), event.BlockHash)
+	}
+
+	// Only the stake event if no address is watched
+	n = newTestNotifier(nil)
+	events = n.BuildEvents(block)
+	assert.Equal(2, len(events))
+	assert.Equal(EventStake, events[1].Type)
+}
+
+func TestDeliverWithRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int32
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(Sign([]byte("secret"), body), r.Header.Get(signatureHeader))
+		received = body
+	}))
+	defer server.Close()
+
+	n := newTestNotifier([]string{server.URL})
+	event := &Event{Type: EventBlockFinalized, Height: 5, Data: &BlockData{NumTxs: 1}}
+	assert.Nil(n.deliver(event))
+	assert.Equal(int32(3), atomic.LoadInt32(&attempts))
+
+	var decoded map[string]interface{}
+	assert.Nil(json.Unmarshal(received, &decoded))
+	assert.Equal(EventBlockFinalized, decoded["type"])
+	assert.Equal("5", decoded["height"])
+
+	// Gives up after the max retries
+	atomic.StoreInt32(&attempts, -100)
+	assert.Nil(n.deliver(event))
+	assert.Equal(int32(-96), atomic.LoadInt32(&attempts))
+
+	// Stops retrying once stopped
+	n.Stop()
+	assert.NotNil(n.deliver(event))
+}